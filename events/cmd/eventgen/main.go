@@ -0,0 +1,42 @@
+// Command eventgen generates Go types of event schema:
+//
+//	go run ./cmd/eventgen -schema schema/fine_event.json -out fine_event.gen.go -package events
+package main
+
+import (
+	"events/internal/gen"
+	"events/internal/schema"
+	"flag"
+	"log"
+	"os"
+	"path/filepath"
+)
+
+func main() {
+	schemaPath := flag.String("schema", "", "path of JSON schema")
+	out := flag.String("out", "", "path of generated Go file")
+	pkg := flag.String("package", "events", "package of generated Go file")
+	flag.Parse()
+	if *schemaPath == "" || *out == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	data, err := os.ReadFile(*schemaPath)
+	if err != nil {
+		log.Fatal(err)
+	}
+	s, err := schema.Parse(data)
+	if err != nil {
+		log.Fatalf("parse %s: %v", *schemaPath, err)
+	}
+
+	code, err := gen.Generate(s, *pkg, filepath.ToSlash(*schemaPath))
+	if err != nil {
+		log.Fatalf("generate %s: %v", *schemaPath, err)
+	}
+	err = os.WriteFile(*out, code, 0o644)
+	if err != nil {
+		log.Fatal(err)
+	}
+}
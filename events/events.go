@@ -0,0 +1,171 @@
+// Package events is contract of messages, which service sends to fine_notification and to other consumers.
+// Messages are described
+// by JSON schemas in schema directory, Go types are generated from them:
+//
+//	go generate ./...
+//
+// Producer validates its messages by schema in tests, consumer decodes examples of every schema version
+package events
+
+import (
+	"embed"
+	"encoding/json"
+	"errors"
+	"events/internal/schema"
+	"path"
+)
+
+//go:generate go run ./cmd/eventgen -schema schema/fine_event.json -out fine_event.gen.go -package events
+//go:generate go run ./cmd/eventgen -schema schema/domain_event.json -out domain_event.gen.go -package events
+
+const (
+	// FineEventV1 contains image bytes. Events without schema version were sent by earlier versions
+	// of service and are version 1
+	FineEventV1 = 1
+	// FineEventV2 contains signed url of image instead of image bytes
+	FineEventV2 = 2
+)
+
+// FineEventVersions are schema versions of fine event, which producer may send and consumer must accept
+var FineEventVersions = []int{FineEventV1, FineEventV2}
+
+// FineEventSchema is path of fine event schema in Schemas
+const FineEventSchema = "schema/fine_event.json"
+
+// DomainEventV1 is schema version of domain event envelope
+const DomainEventV1 = 1
+
+// Types of domain events are routing keys of events in topic exchange
+const (
+	CaseCreatedEvent          = "case.created"
+	CaseAssignedEvent         = "case.assigned"
+	CaseDecisionRecordedEvent = "case.decision_recorded"
+	CaseSolvedEvent           = "case.solved"
+	CaseEscalatedEvent        = "case.escalated"
+	CaseReviewRequestedEvent  = "case.review_requested"
+	CaseOverriddenEvent       = "case.overridden"
+	ExpertConfirmedEvent      = "expert.confirmed"
+	ExpertSkillChangedEvent   = "expert.skill_changed"
+)
+
+// DomainEventTypes are types of domain events, which service publishes
+var DomainEventTypes = []string{
+	CaseCreatedEvent,
+	CaseAssignedEvent,
+	CaseDecisionRecordedEvent,
+	CaseSolvedEvent,
+	CaseEscalatedEvent,
+	CaseReviewRequestedEvent,
+	CaseOverriddenEvent,
+	ExpertConfirmedEvent,
+	ExpertSkillChangedEvent,
+}
+
+// DomainEventSchema is path of domain event schema in Schemas
+const DomainEventSchema = "schema/domain_event.json"
+
+// Schemas are JSON schemas of events
+//
+//go:embed schema/*.json
+var Schemas embed.FS
+
+//go:embed examples/*.json examples/domain/*.json
+var examples embed.FS
+
+// ValidateFineEvent returns all violations of fine event schema in message
+func ValidateFineEvent(msg []byte) error {
+	return validate(FineEventSchema, msg)
+}
+
+// FineEventExamples returns example messages of every schema version by file name. Consumer must
+// decode all of them
+func FineEventExamples() (map[string][]byte, error) {
+	return readExamples("examples")
+}
+
+// ValidateDomainEvent returns all violations of domain event schema in message, data is validated
+// by payload of event type
+func ValidateDomainEvent(msg []byte) error {
+	s, err := parseSchema(DomainEventSchema)
+	if err != nil {
+		return err
+	}
+	err = s.Validate(msg)
+
+	var event DomainEvent
+	if json.Unmarshal(msg, &event) != nil || event.Data == nil {
+		return err
+	}
+	if _, ok := s.Payloads[event.Type]; !ok {
+		return err
+	}
+	return errors.Join(err, s.ValidatePayload(event.Type, event.Data, "$.data"))
+}
+
+// DomainEventExamples returns example messages of every domain event type by file name
+func DomainEventExamples() (map[string][]byte, error) {
+	return readExamples("examples/domain")
+}
+
+// NewDomainEventData returns pointer to payload of event type, which data of event is decoded to.
+// Ok is false for unknown type, consumers should skip such events
+func NewDomainEventData(eventType string) (data any, ok bool) {
+	switch eventType {
+	case CaseCreatedEvent:
+		return &CaseCreated{}, true
+	case CaseAssignedEvent:
+		return &CaseAssigned{}, true
+	case CaseDecisionRecordedEvent:
+		return &CaseDecisionRecorded{}, true
+	case CaseSolvedEvent:
+		return &CaseSolved{}, true
+	case CaseEscalatedEvent:
+		return &CaseEscalated{}, true
+	case CaseReviewRequestedEvent:
+		return &CaseReviewRequested{}, true
+	case CaseOverriddenEvent:
+		return &CaseOverridden{}, true
+	case ExpertConfirmedEvent:
+		return &ExpertConfirmed{}, true
+	case ExpertSkillChangedEvent:
+		return &ExpertSkillChanged{}, true
+	default:
+		return nil, false
+	}
+}
+
+func readExamples(dir string) (map[string][]byte, error) {
+	entries, err := examples.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[string][]byte, len(entries))
+	for _, e := range entries {
+		if e.IsDir() {
+			continue
+		}
+		data, err := examples.ReadFile(path.Join(dir, e.Name()))
+		if err != nil {
+			return nil, err
+		}
+		result[e.Name()] = data
+	}
+	return result, nil
+}
+
+func validate(schemaPath string, msg []byte) error {
+	s, err := parseSchema(schemaPath)
+	if err != nil {
+		return err
+	}
+	return s.Validate(msg)
+}
+
+func parseSchema(schemaPath string) (*schema.Schema, error) {
+	data, err := Schemas.ReadFile(schemaPath)
+	if err != nil {
+		return nil, err
+	}
+	return schema.Parse(data)
+}
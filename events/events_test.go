@@ -0,0 +1,304 @@
+package events
+
+import (
+	"bytes"
+	"encoding/json"
+	"events/internal/gen"
+	"events/internal/schema"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func mustParseSchema(t *testing.T, schemaPath string) *schema.Schema {
+	s, err := parseSchema(schemaPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+// TestGeneratedTypes fails, when schema is changed without go generate
+func TestGeneratedTypes(t *testing.T) {
+	testCases := []struct {
+		schemaPath string
+		file       string
+	}{
+		{schemaPath: FineEventSchema, file: "fine_event.gen.go"},
+		{schemaPath: DomainEventSchema, file: "domain_event.gen.go"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.file, func(t *testing.T) {
+			expected, err := gen.Generate(mustParseSchema(t, tc.schemaPath), "events", tc.schemaPath)
+			if err != nil {
+				t.Fatal(err)
+			}
+			actual, err := os.ReadFile(tc.file)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !bytes.Equal(expected, actual) {
+				t.Errorf("%s is not generated from schema, run go generate ./...", tc.file)
+			}
+		})
+	}
+}
+
+func TestFineEventVersions(t *testing.T) {
+	var versions []int
+	for _, p := range mustParseSchema(t, FineEventSchema).Properties {
+		if p.Name != "schema_version" {
+			continue
+		}
+		for _, v := range p.Schema.Enum {
+			versions = append(versions, int(v.(float64)))
+		}
+	}
+
+	if !reflect.DeepEqual(FineEventVersions, versions) {
+		t.Errorf("expected versions of schema %v, got %v", versions, FineEventVersions)
+	}
+}
+
+// TestFineEventExamples checks, that examples follow schema and generated types keep all their fields
+func TestFineEventExamples(t *testing.T) {
+	examples, err := FineEventExamples()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(examples) != len(FineEventVersions) {
+		t.Errorf("expected example of every version, got %d examples", len(examples))
+	}
+
+	for name, data := range examples {
+		t.Run(name, func(t *testing.T) {
+			if err := ValidateFineEvent(data); err != nil {
+				t.Fatal(err)
+			}
+
+			var event FineEvent
+			if err := decodeStrict(data, &event); err != nil {
+				t.Fatal(err)
+			}
+			encoded, err := json.Marshal(event)
+			if err != nil {
+				t.Fatal(err)
+			}
+			assertSameJSON(t, data, encoded)
+		})
+	}
+}
+
+func TestValidateFineEvent(t *testing.T) {
+	examples, err := FineEventExamples()
+	if err != nil {
+		t.Fatal(err)
+	}
+	example := examples["fine_event_v2.json"]
+
+	testCases := []struct {
+		name        string
+		replace     [2]string
+		expectedErr string
+	}{
+		{name: "Valid event"},
+		{
+			name:        "Unknown version",
+			replace:     [2]string{`"schema_version": 2`, `"schema_version": 3`},
+			expectedErr: "$.schema_version: 3 is not one of",
+		},
+		{
+			name:        "Required property is missing",
+			replace:     [2]string{`"region": "77",`, ``},
+			expectedErr: "$.case.transport: required property region is missing",
+		},
+		{
+			name:        "Property is not defined",
+			replace:     [2]string{`"chars": "ABC",`, `"chars": "ABC", "color": "red",`},
+			expectedErr: "$.case.transport: property color is not defined by schema",
+		},
+		{
+			name:        "Invalid date",
+			replace:     [2]string{`"date": "2024-03-01T12:30:00Z"`, `"date": "01.03.2024"`},
+			expectedErr: "$.case.date: parsing time",
+		},
+		{
+			name:        "Invalid type",
+			replace:     [2]string{`"fine_amount": 500`, `"fine_amount": "500"`},
+			expectedErr: "$.case.violation.fine_amount: expected [integer], got string",
+		},
+		{
+			name:        "Invalid reference",
+			replace:     [2]string{`"reference": "18810000000000000001"`, `"reference": "1881"`},
+			expectedErr: "$.fine.reference:",
+		},
+		{
+			name:        "Unknown channel",
+			replace:     [2]string{`"tg_id": "123456789"`, `"notify_channels": ["fax"]`},
+			expectedErr: "$.case.transport.person.notify_channels[0]: fax is not one of",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			msg := []byte(strings.Replace(string(example), tc.replace[0], tc.replace[1], 1))
+
+			err := ValidateFineEvent(msg)
+
+			if tc.expectedErr == "" && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tc.expectedErr != "" && (err == nil || !strings.Contains(err.Error(), tc.expectedErr)) {
+				t.Errorf("expected error %q, got %v", tc.expectedErr, err)
+			}
+		})
+	}
+}
+
+func TestDomainEventTypes(t *testing.T) {
+	s := mustParseSchema(t, DomainEventSchema)
+	var types []string
+	for _, p := range s.Properties {
+		if p.Name != "type" {
+			continue
+		}
+		for _, v := range p.Schema.Enum {
+			types = append(types, v.(string))
+		}
+	}
+
+	if !reflect.DeepEqual(DomainEventTypes, types) {
+		t.Errorf("expected types of schema %v, got %v", types, DomainEventTypes)
+	}
+	if len(s.Payloads) != len(types) {
+		t.Errorf("expected payload of every type, got %d payloads", len(s.Payloads))
+	}
+	for _, typ := range types {
+		if _, ok := s.Payloads[typ]; !ok {
+			t.Errorf("payload of %s is not defined", typ)
+		}
+		if _, ok := NewDomainEventData(typ); !ok {
+			t.Errorf("data of %s is not defined", typ)
+		}
+	}
+}
+
+// TestDomainEventExamples checks, that examples follow schema and payloads of generated types keep all their fields
+func TestDomainEventExamples(t *testing.T) {
+	examples, err := DomainEventExamples()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(examples) != len(DomainEventTypes) {
+		t.Errorf("expected example of every type, got %d examples", len(examples))
+	}
+
+	for name, data := range examples {
+		t.Run(name, func(t *testing.T) {
+			if err := ValidateDomainEvent(data); err != nil {
+				t.Fatal(err)
+			}
+
+			var event DomainEvent
+			if err := decodeStrict(data, &event); err != nil {
+				t.Fatal(err)
+			}
+			payload, ok := NewDomainEventData(event.Type)
+			if !ok {
+				t.Fatalf("unknown type %s", event.Type)
+			}
+			if err := decodeStrict(event.Data, payload); err != nil {
+				t.Fatal(err)
+			}
+
+			event.Data, err = json.Marshal(payload)
+			if err != nil {
+				t.Fatal(err)
+			}
+			encoded, err := json.Marshal(event)
+			if err != nil {
+				t.Fatal(err)
+			}
+			assertSameJSON(t, data, encoded)
+		})
+	}
+}
+
+func TestValidateDomainEvent(t *testing.T) {
+	examples, err := DomainEventExamples()
+	if err != nil {
+		t.Fatal(err)
+	}
+	example := examples["case_solved.json"]
+
+	testCases := []struct {
+		name        string
+		replace     [2]string
+		expectedErr string
+	}{
+		{name: "Valid event"},
+		{
+			name:        "Unknown type",
+			replace:     [2]string{`"type": "case.solved"`, `"type": "case.deleted"`},
+			expectedErr: "$.type: case.deleted is not one of",
+		},
+		{
+			name:        "Data of other type",
+			replace:     [2]string{`"type": "case.solved"`, `"type": "case.escalated"`},
+			expectedErr: "$.data: required property from_skill is missing",
+		},
+		{
+			name:        "Required property of envelope is missing",
+			replace:     [2]string{`"occurred_at": "2024-03-01T13:05:00Z",`, ``},
+			expectedErr: "$: required property occurred_at is missing",
+		},
+		{
+			name:        "Invalid reference of fine",
+			replace:     [2]string{`"reference": "18810000000000000001"`, `"reference": "1881"`},
+			expectedErr: "$.data.fine.reference:",
+		},
+		{
+			name:        "Property of data is not defined",
+			replace:     [2]string{`"fine_decision": true,`, `"fine_decision": true, "expert_id": "expert",`},
+			expectedErr: "$.data: property expert_id is not defined by schema",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			msg := []byte(strings.Replace(string(example), tc.replace[0], tc.replace[1], 1))
+
+			err := ValidateDomainEvent(msg)
+
+			if tc.expectedErr == "" && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tc.expectedErr != "" && (err == nil || !strings.Contains(err.Error(), tc.expectedErr)) {
+				t.Errorf("expected error %q, got %v", tc.expectedErr, err)
+			}
+		})
+	}
+}
+
+func decodeStrict(data []byte, v any) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	return dec.Decode(v)
+}
+
+func assertSameJSON(t *testing.T, expected []byte, actual []byte) {
+	t.Helper()
+	var e, a any
+	if err := json.Unmarshal(expected, &e); err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(actual, &a); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(e, a) {
+		t.Errorf("expected %s, got %s", expected, actual)
+	}
+}
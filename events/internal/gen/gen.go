@@ -0,0 +1,182 @@
+// Package gen generates Go types of event schema
+package gen
+
+import (
+	"bytes"
+	"events/internal/schema"
+	"fmt"
+	"go/format"
+	"sort"
+	"strings"
+)
+
+// initialisms are parts of property names, which are written in upper case
+var initialisms = map[string]bool{"ID": true, "URL": true}
+
+// Generate returns formatted Go source with struct of root schema and structs of its definitions
+// in alphabetical order. Optional properties are omitted from JSON, when they are empty, optional and
+// nullable objects and formatted strings are pointers, properties without type are raw JSON.
+// Source is name of schema file in header of code
+func Generate(s *schema.Schema, pkg string, source string) ([]byte, error) {
+	g := &generator{root: s}
+	err := g.writeStruct(s.Title, s)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(s.Defs))
+	for name := range s.Defs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		err = g.writeStruct(name, s.Defs[name])
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var out bytes.Buffer
+	fmt.Fprintf(&out, "// Code generated by eventgen from %s. DO NOT EDIT.\n\n", source)
+	fmt.Fprintf(&out, "package %s\n\n", pkg)
+	var imports []string
+	if g.usesJSON {
+		imports = append(imports, "encoding/json")
+	}
+	if g.usesTime {
+		imports = append(imports, "time")
+	}
+	if len(imports) == 1 {
+		fmt.Fprintf(&out, "import %q\n\n", imports[0])
+	}
+	if len(imports) > 1 {
+		out.WriteString("import (\n")
+		for _, imp := range imports {
+			fmt.Fprintf(&out, "%q\n", imp)
+		}
+		out.WriteString(")\n\n")
+	}
+	out.Write(g.body.Bytes())
+
+	return format.Source(out.Bytes())
+}
+
+type generator struct {
+	root     *schema.Schema
+	body     bytes.Buffer
+	usesTime bool
+	usesJSON bool
+}
+
+func (g *generator) writeStruct(name string, s *schema.Schema) error {
+	if name == "" {
+		return fmt.Errorf("schema has no title")
+	}
+	if typ, _, _ := s.Type.NonNull(); typ != "object" {
+		return fmt.Errorf("%s: type %s is not object", name, typ)
+	}
+
+	writeComment(&g.body, s.Description)
+	fmt.Fprintf(&g.body, "type %s struct {\n", name)
+	for _, p := range s.Properties {
+		required := s.IsRequired(p.Name)
+		typ, err := g.goType(p.Schema, required)
+		if err != nil {
+			return fmt.Errorf("%s.%s: %w", name, p.Name, err)
+		}
+
+		tag := p.Name
+		if !required {
+			tag += ",omitempty"
+		}
+		writeComment(&g.body, p.Schema.Description)
+		fmt.Fprintf(&g.body, "%s %s `json:\"%s\"`\n", fieldName(p), typ, tag)
+	}
+	g.body.WriteString("}\n\n")
+
+	return nil
+}
+
+// goType returns type of property. Nested objects must be definitions
+func (g *generator) goType(s *schema.Schema, required bool) (string, error) {
+	if s.Ref != "" {
+		if !required {
+			return "*" + s.RefName(), nil
+		}
+		return s.RefName(), nil
+	}
+	if len(s.Type) == 0 {
+		g.usesJSON = true
+		return "json.RawMessage", nil
+	}
+
+	typ, nullable, err := s.Type.NonNull()
+	if err != nil {
+		return "", err
+	}
+	pointer := ""
+	if nullable {
+		pointer = "*"
+	}
+
+	switch typ {
+	case "string":
+		if s.ContentEncoding == "base64" {
+			return "[]byte", nil
+		}
+		if s.Format == "date-time" {
+			g.usesTime = true
+			if !required {
+				pointer = "*"
+			}
+			return pointer + "time.Time", nil
+		}
+		return pointer + "string", nil
+	case "integer":
+		return pointer + "int", nil
+	case "number":
+		return pointer + "float64", nil
+	case "boolean":
+		return pointer + "bool", nil
+	case "array":
+		if s.Items == nil {
+			return "", fmt.Errorf("array has no items")
+		}
+		item, err := g.goType(s.Items, true)
+		if err != nil {
+			return "", err
+		}
+		return "[]" + item, nil
+	default:
+		return "", fmt.Errorf("type %s is not supported, objects must be definitions", typ)
+	}
+}
+
+// fieldName converts snake case name of property to Go name, when schema does not set it
+func fieldName(p schema.Property) string {
+	if p.Schema.GoName != "" {
+		return p.Schema.GoName
+	}
+
+	var name strings.Builder
+	for _, part := range strings.Split(p.Name, "_") {
+		if part == "" {
+			continue
+		}
+		if upper := strings.ToUpper(part); initialisms[upper] {
+			name.WriteString(upper)
+			continue
+		}
+		name.WriteString(strings.ToUpper(part[:1]) + part[1:])
+	}
+	return name.String()
+}
+
+func writeComment(buf *bytes.Buffer, text string) {
+	if text == "" {
+		return
+	}
+	for _, line := range strings.Split(text, "\n") {
+		fmt.Fprintf(buf, "// %s\n", line)
+	}
+}
@@ -0,0 +1,186 @@
+// Package schema parses subset of JSON Schema (draft 2020-12), which is used by event schemas, and
+// validates JSON documents by it
+package schema
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// refPrefix is prefix of references to definitions of the same schema, other references are not supported
+const refPrefix = "#/$defs/"
+
+// Schema is JSON Schema. Properties keep order of schema document, so generated code follows it.
+// GoName overrides name of field in generated code. Payloads map values of discriminator property
+// to references of their payload definitions
+type Schema struct {
+	Title                string             `json:"title"`
+	Description          string             `json:"description"`
+	Type                 Types              `json:"type"`
+	Ref                  string             `json:"$ref"`
+	Format               string             `json:"format"`
+	ContentEncoding      string             `json:"contentEncoding"`
+	Pattern              string             `json:"pattern"`
+	MinLength            *int               `json:"minLength"`
+	Minimum              *float64           `json:"minimum"`
+	Enum                 []any              `json:"enum"`
+	Required             []string           `json:"required"`
+	Properties           Properties         `json:"properties"`
+	AdditionalProperties *bool              `json:"additionalProperties"`
+	Items                *Schema            `json:"items"`
+	Defs                 map[string]*Schema `json:"$defs"`
+	GoName               string             `json:"x-go-name"`
+	Payloads             map[string]string  `json:"x-payloads"`
+}
+
+// Types is type of schema, which is single type or list of types
+type Types []string
+
+func (t *Types) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*t = Types{single}
+		return nil
+	}
+
+	var list []string
+	if err := json.Unmarshal(data, &list); err != nil {
+		return fmt.Errorf("type must be string or array of strings: %w", err)
+	}
+	*t = list
+	return nil
+}
+
+// Has reports whether typ is one of types
+func (t Types) Has(typ string) bool {
+	for _, v := range t {
+		if v == typ {
+			return true
+		}
+	}
+	return false
+}
+
+// NonNull returns the only type except null, nullable is true, when null is allowed
+func (t Types) NonNull() (typ string, nullable bool, err error) {
+	for _, v := range t {
+		if v == "null" {
+			nullable = true
+			continue
+		}
+		if typ != "" {
+			return "", false, fmt.Errorf("multiple types %v are not supported", []string(t))
+		}
+		typ = v
+	}
+	return typ, nullable, nil
+}
+
+// Property is named schema of object property
+type Property struct {
+	Name   string
+	Schema *Schema
+}
+
+// Properties are properties of object in order of schema document
+type Properties []Property
+
+func (p *Properties) UnmarshalJSON(data []byte) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	t, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if t != json.Delim('{') {
+		return errors.New("properties must be object")
+	}
+
+	for dec.More() {
+		t, err = dec.Token()
+		if err != nil {
+			return err
+		}
+		var s Schema
+		err = dec.Decode(&s)
+		if err != nil {
+			return fmt.Errorf("property %s: %w", t, err)
+		}
+		*p = append(*p, Property{Name: t.(string), Schema: &s})
+	}
+
+	return nil
+}
+
+// Parse parses schema and checks, that all references point to its definitions
+func Parse(data []byte) (*Schema, error) {
+	var s Schema
+	err := json.Unmarshal(data, &s)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.checkRefs(&s)
+	if err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// IsRequired reports whether property is required by object schema
+func (s *Schema) IsRequired(name string) bool {
+	for _, r := range s.Required {
+		if r == name {
+			return true
+		}
+	}
+	return false
+}
+
+// RefName returns name of definition, which schema references
+func (s *Schema) RefName() string {
+	return strings.TrimPrefix(s.Ref, refPrefix)
+}
+
+func (s *Schema) resolve(root *Schema) *Schema {
+	if s.Ref == "" {
+		return s
+	}
+	return root.Defs[s.RefName()]
+}
+
+func (s *Schema) checkRefs(root *Schema) error {
+	if s.Ref != "" {
+		if !strings.HasPrefix(s.Ref, refPrefix) {
+			return fmt.Errorf("reference %s is not supported", s.Ref)
+		}
+		if _, ok := root.Defs[s.RefName()]; !ok {
+			return fmt.Errorf("reference %s is not defined", s.Ref)
+		}
+	}
+
+	for name, ref := range s.Payloads {
+		if err := (&Schema{Ref: ref}).checkRefs(root); err != nil {
+			return fmt.Errorf("payload %s: %w", name, err)
+		}
+	}
+	for _, p := range s.Properties {
+		if err := p.Schema.checkRefs(root); err != nil {
+			return err
+		}
+	}
+	if s.Items != nil {
+		if err := s.Items.checkRefs(root); err != nil {
+			return err
+		}
+	}
+	for _, def := range s.Defs {
+		if err := def.checkRefs(root); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,172 @@
+package schema
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"time"
+	"unicode/utf8"
+)
+
+// Validate returns all violations of schema in JSON document, every violation contains path of value
+func (s *Schema) Validate(data []byte) error {
+	return s.validateDocument(s, data, "$")
+}
+
+// ValidatePayload returns all violations of payload definition of name in JSON document at path
+func (s *Schema) ValidatePayload(name string, data []byte, path string) error {
+	ref, ok := s.Payloads[name]
+	if !ok {
+		return fmt.Errorf("%s: payload of %s is not defined", path, name)
+	}
+	return (&Schema{Ref: ref}).validateDocument(s, data, path)
+}
+
+func (s *Schema) validateDocument(root *Schema, data []byte, path string) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+
+	var v any
+	err := dec.Decode(&v)
+	if err != nil {
+		return err
+	}
+
+	return errors.Join(s.validate(root, v, path)...)
+}
+
+func (s *Schema) validate(root *Schema, v any, path string) []error {
+	s = s.resolve(root)
+
+	if len(s.Type) > 0 && !s.Type.Has(typeOf(v)) && !(s.Type.Has("number") && typeOf(v) == "integer") {
+		return []error{fmt.Errorf("%s: expected %v, got %s", path, []string(s.Type), typeOf(v))}
+	}
+	if len(s.Enum) > 0 && !inEnum(s.Enum, v) {
+		return []error{fmt.Errorf("%s: %v is not one of %v", path, v, s.Enum)}
+	}
+
+	switch value := v.(type) {
+	case map[string]any:
+		return s.validateObject(root, value, path)
+	case []any:
+		var errs []error
+		if s.Items != nil {
+			for i, item := range value {
+				errs = append(errs, s.Items.validate(root, item, fmt.Sprintf("%s[%d]", path, i))...)
+			}
+		}
+		return errs
+	case string:
+		return s.validateString(value, path)
+	case json.Number:
+		if s.Minimum != nil {
+			f, _ := value.Float64()
+			if f < *s.Minimum {
+				return []error{fmt.Errorf("%s: %s is less than %v", path, value, *s.Minimum)}
+			}
+		}
+	}
+
+	return nil
+}
+
+func (s *Schema) validateObject(root *Schema, obj map[string]any, path string) []error {
+	var errs []error
+	for _, name := range s.Required {
+		if _, ok := obj[name]; !ok {
+			errs = append(errs, fmt.Errorf("%s: required property %s is missing", path, name))
+		}
+	}
+
+	for name, value := range obj {
+		prop := s.property(name)
+		if prop == nil {
+			if s.AdditionalProperties != nil && !*s.AdditionalProperties {
+				errs = append(errs, fmt.Errorf("%s: property %s is not defined by schema", path, name))
+			}
+			continue
+		}
+		errs = append(errs, prop.validate(root, value, path+"."+name)...)
+	}
+
+	return errs
+}
+
+func (s *Schema) validateString(value string, path string) []error {
+	if s.MinLength != nil && utf8.RuneCountInString(value) < *s.MinLength {
+		return []error{fmt.Errorf("%s: length is less than %d", path, *s.MinLength)}
+	}
+	if s.Pattern != "" {
+		matched, err := regexp.MatchString(s.Pattern, value)
+		if err != nil {
+			return []error{fmt.Errorf("%s: invalid pattern %s: %w", path, s.Pattern, err)}
+		}
+		if !matched {
+			return []error{fmt.Errorf("%s: %q does not match %s", path, value, s.Pattern)}
+		}
+	}
+
+	var err error
+	switch s.Format {
+	case "date-time":
+		_, err = time.Parse(time.RFC3339Nano, value)
+	case "uri":
+		var u *url.URL
+		u, err = url.Parse(value)
+		if err == nil && !u.IsAbs() {
+			err = errors.New("uri is not absolute")
+		}
+	}
+	if err == nil && s.ContentEncoding == "base64" {
+		_, err = base64.StdEncoding.DecodeString(value)
+	}
+	if err != nil {
+		return []error{fmt.Errorf("%s: %w", path, err)}
+	}
+
+	return nil
+}
+
+func (s *Schema) property(name string) *Schema {
+	for _, p := range s.Properties {
+		if p.Name == name {
+			return p.Schema
+		}
+	}
+	return nil
+}
+
+func typeOf(v any) string {
+	switch value := v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case string:
+		return "string"
+	case json.Number:
+		if _, err := value.Int64(); err == nil {
+			return "integer"
+		}
+		return "number"
+	case []any:
+		return "array"
+	case map[string]any:
+		return "object"
+	default:
+		return fmt.Sprintf("%T", v)
+	}
+}
+
+func inEnum(enum []any, v any) bool {
+	for _, e := range enum {
+		if fmt.Sprint(e) == fmt.Sprint(v) {
+			return true
+		}
+	}
+	return false
+}
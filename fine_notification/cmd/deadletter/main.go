@@ -0,0 +1,10 @@
+package main
+
+import (
+	"fine_notification/internal/app"
+	"os"
+)
+
+func main() {
+	app.RunDeadLetter(os.Args[1:])
+}
@@ -1,73 +1,318 @@
 package app
 
 import (
+	"context"
+	"errors"
 	"fine_notification/internal/config"
+	"fine_notification/internal/images"
+	"fine_notification/internal/ledger"
 	"fine_notification/internal/mailer"
+	"fine_notification/internal/notice"
+	"fine_notification/internal/notifier"
+	"fine_notification/internal/templates"
 	"fine_notification/internal/transport/rabbitmq"
+	"fine_notification/internal/transport/rest"
+	"fmt"
+	"github.com/golang-migrate/migrate"
+	_ "github.com/golang-migrate/migrate/database/postgres"
+	_ "github.com/golang-migrate/migrate/source/file"
 	amqp "github.com/rabbitmq/amqp091-go"
 	"gopkg.in/gomail.v2"
 	"log"
+	"net/http"
+	"time"
 )
 
 const (
 	notificationConfigPath = "notification_config.yaml"
+
+	defaultRetryMaxAttempts = 5
+	defaultRetryDelay       = time.Minute
+
+	defaultNotifierTimeout = 10 * time.Second
+	defaultTelegramURL     = "https://api.telegram.org"
+	defaultVKURL           = "https://api.vk.com"
+	defaultVKVersion       = "5.199"
+
+	defaultTemplatesDir            = "templates"
+	defaultTemplatesLocale         = "ru"
+	defaultTemplatesReloadInterval = 5 * time.Second
+	defaultHTTPPort                = 8081
+
+	defaultImagesTimeout = 10 * time.Second
+	defaultImagesMaxSize = 10 << 20
 )
 
+// defaultNotifyOrder is order of channels for persons without preferences
+var defaultNotifyOrder = []string{
+	string(notifier.EmailChannel),
+	string(notifier.TelegramChannel),
+	string(notifier.VKChannel),
+	string(notifier.SMSChannel),
+}
+
 func Run() {
 	cfg, err := config.ParseConfig(notificationConfigPath)
 	if err != nil {
 		log.Fatal(err)
 	}
+	setupRetryDefaults(&cfg.Retry)
+	setupNotifiersDefaults(&cfg.Notifiers)
+	setupTemplatesDefaults(cfg)
+	setupImagesDefaults(&cfg.Images)
 
-	mqConn, err := rabbitmq.NewRabbitMQConn(cfg)
+	// Init database of delivery ledger
+	dbPool, err := ledger.NewPool(context.Background(), cfg.Postgres)
 	if err != nil {
 		log.Fatal(err)
 	}
+	defer dbPool.Close()
+
+	migrationsURL, err := ledger.MigrationsURL(ledger.ConnString(cfg.Postgres))
+	if err != nil {
+		log.Fatal(err)
+	}
+	runMigrations(migrationsURL)
+	deliveryLedger := ledger.NewLedgerPostgres(dbPool)
+	noticeStore := notice.NewStorePostgres(dbPool)
+
+	// Connection is dialed again in background every time it is lost
+	done := make(chan struct{})
+	mqConn := rabbitmq.NewConnection(cfg.RabbitMQ)
+	go mqConn.Run(done)
 
 	dialer := setupMailDialer(cfg)
 
+	renderer, err := templates.NewRenderer(cfg.Templates.Dir, cfg.Templates.Locale)
+	if err != nil {
+		log.Fatal(err)
+	}
+	go renderer.Run(done, cfg.Templates.ReloadInterval)
+	runServer(cfg.HTTP, renderer, deliveryLedger, noticeStore)
+
+	noticeGenerator, err := notice.NewGenerator(cfg.Notice)
+	if err != nil {
+		log.Fatal(err)
+	}
+	noticeIssuer := notice.NewIssuer(noticeGenerator, noticeStore)
+
 	fineMailer := mailer.NewMailer(dialer)
-	fineConsumer := setupFineConsumer(mqConn, fineMailer)
+	dispatcher := setupDispatcher(cfg, fineMailer, renderer, deliveryLedger, noticeIssuer)
+	imageFetcher := images.NewFetcher(&http.Client{Timeout: cfg.Images.Timeout}, cfg.Images.MaxSize)
+	fineConsumer := rabbitmq.NewFineConsumer(mqConn, fineTopology(), dispatcher, imageFetcher, cfg.Retry)
 
-	startConsume(fineConsumer, cfg)
+	startConsume(done, fineConsumer)
 }
 
-func setupFineConsumer(mqConn *amqp.Connection, mailer *mailer.Mailer) *rabbitmq.FineConsumer {
-	fineConsumer, err := rabbitmq.NewFineConsumer(mqConn, mailer)
-	if err != nil {
-		log.Fatal(err)
+func setupRetryDefaults(cfg *config.RetryConfig) {
+	if cfg.MaxAttempts <= 0 {
+		cfg.MaxAttempts = defaultRetryMaxAttempts
 	}
-	err = fineConsumer.SetupExchangeAndQueue(
-		rabbitmq.ExchangeParams{
-			Name:       rabbitmq.FineExchange,
-			Kind:       "fanout",
-			Durable:    true,
-			AutoDelete: false,
-			Internal:   false,
-			NoWait:     false,
-			Args:       nil,
-		}, rabbitmq.QueueParams{
-			Name:       "fine_queue",
-			Durable:    false,
-			AutoDelete: false,
-			Exclusive:  false,
-			NoWait:     false,
-			Args:       nil,
-		},
-		rabbitmq.BindingParams{
-			Queue:    "fine_queue",
-			Key:      "",
-			Exchange: rabbitmq.FineExchange,
-			NoWait:   false,
-			Args:     nil,
-		},
-	)
+	if cfg.Delay <= 0 {
+		cfg.Delay = defaultRetryDelay
+	}
+}
+
+func setupNotifiersDefaults(cfg *config.NotifiersConfig) {
+	if len(cfg.Order) == 0 {
+		cfg.Order = defaultNotifyOrder
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = defaultNotifierTimeout
+	}
+	if cfg.Telegram.URL == "" {
+		cfg.Telegram.URL = defaultTelegramURL
+	}
+	if cfg.VK.URL == "" {
+		cfg.VK.URL = defaultVKURL
+	}
+	if cfg.VK.Version == "" {
+		cfg.VK.Version = defaultVKVersion
+	}
+}
 
+func setupTemplatesDefaults(cfg *config.Config) {
+	if cfg.Templates.Dir == "" {
+		cfg.Templates.Dir = defaultTemplatesDir
+	}
+	if cfg.Templates.Locale == "" {
+		cfg.Templates.Locale = defaultTemplatesLocale
+	}
+	if cfg.Templates.ReloadInterval <= 0 {
+		cfg.Templates.ReloadInterval = defaultTemplatesReloadInterval
+	}
+	if cfg.HTTP.Port == 0 {
+		cfg.HTTP.Port = defaultHTTPPort
+	}
+}
+
+func setupImagesDefaults(cfg *config.ImagesConfig) {
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = defaultImagesTimeout
+	}
+	if cfg.MaxSize <= 0 {
+		cfg.MaxSize = defaultImagesMaxSize
+	}
+}
+
+func runMigrations(dbUrl string) {
+	m, err := migrate.New("file://migrations", dbUrl)
 	if err != nil {
 		log.Fatal(err)
 	}
+	err = m.Up()
+	if errors.Is(err, migrate.ErrNoChange) {
+		log.Println("Migrate no change")
+	} else if err != nil {
+		log.Fatal(err)
+	}
+	log.Println("Migrate ran successfully")
+}
+
+// runServer starts server of template preview, delivery status and notices for directors in background
+func runServer(
+	cfg config.HTTPConfig,
+	renderer *templates.Renderer,
+	deliveryLedger *ledger.LedgerPostgres,
+	noticeStore *notice.StorePostgres,
+) {
+	if cfg.SigningKey == "" {
+		log.Println("HTTP server is disabled: signing key is not set")
+		return
+	}
+
+	auth := rest.NewDirectorAuth(cfg.SigningKey)
+	templateHandler := rest.NewTemplateHandler(renderer)
+	deliveryHandler := rest.NewDeliveryHandler(deliveryLedger)
+	noticeHandler := rest.NewNoticeHandler(noticeStore)
+
+	mux := http.NewServeMux()
+	mux.Handle("POST /templates/preview", auth.Middleware(http.HandlerFunc(templateHandler.Preview)))
+	mux.Handle("GET /deliveries", auth.Middleware(http.HandlerFunc(deliveryHandler.GetDeliveries)))
+	mux.Handle("GET /notices", auth.Middleware(http.HandlerFunc(noticeHandler.GetNotice)))
+
+	addr := fmt.Sprintf(":%d", cfg.Port)
+	go func() {
+		log.Printf("Run server on %s\n", addr)
+		err := http.ListenAndServe(addr, mux)
+		if err != nil {
+			log.Println(err)
+		}
+	}()
+}
+
+// setupDispatcher creates notifiers of enabled channels in configured order
+func setupDispatcher(
+	cfg *config.Config,
+	fineMailer *mailer.Mailer,
+	renderer *templates.Renderer,
+	deliveryLedger *ledger.LedgerPostgres,
+	noticeIssuer *notice.Issuer,
+) *notifier.Dispatcher {
+	client := &http.Client{Timeout: cfg.Notifiers.Timeout}
+	notifiersCfg := cfg.Notifiers
+
+	var notifiers []notifier.Notifier
+	for _, channel := range notifiersCfg.Order {
+		switch notifier.Channel(channel) {
+		case notifier.EmailChannel:
+			notifiers = append(notifiers, notifier.NewEmailNotifier(
+				fineMailer, renderer, noticeIssuer, cfg.EmailSender.Username, cfg.EmailSender.Subject,
+			))
+		case notifier.TelegramChannel:
+			if notifiersCfg.Telegram.Token != "" {
+				notifiers = append(notifiers, notifier.NewTelegramNotifier(
+					client, renderer, notifiersCfg.Telegram.URL, notifiersCfg.Telegram.Token,
+				))
+			}
+		case notifier.VKChannel:
+			if notifiersCfg.VK.Token != "" {
+				notifiers = append(notifiers, notifier.NewVKNotifier(
+					client, renderer, notifiersCfg.VK.URL, notifiersCfg.VK.Token, notifiersCfg.VK.Version,
+				))
+			}
+		case notifier.SMSChannel:
+			if notifiersCfg.SMS.Token != "" {
+				notifiers = append(notifiers, notifier.NewSMSNotifier(
+					client, renderer, notifiersCfg.SMS.URL, notifiersCfg.SMS.Token, notifiersCfg.SMS.Sender,
+				))
+			}
+		default:
+			log.Fatalf("unknown notification channel in config: %s", channel)
+		}
+	}
+	if len(notifiers) == 0 {
+		log.Fatal("no notification channel is enabled")
+	}
+
+	return notifier.NewDispatcher(deliveryLedger, noticeIssuer, notifiers...)
+}
 
-	return fineConsumer
+// fineTopology declares fine queue with the same params as service. Retry queue has no consumers:
+// expired messages are dead-lettered to fine queue through default exchange
+func fineTopology() rabbitmq.FineTopology {
+	return rabbitmq.FineTopology{
+		Fine: rabbitmq.Topology{
+			Exchange: rabbitmq.ExchangeParams{
+				Name:       rabbitmq.FineExchange,
+				Kind:       amqp.ExchangeFanout,
+				Durable:    true,
+				AutoDelete: false,
+				Internal:   false,
+				NoWait:     false,
+				Args:       nil,
+			},
+			Queue: rabbitmq.QueueParams{
+				Name:       rabbitmq.FineQueue,
+				Durable:    true,
+				AutoDelete: false,
+				Exclusive:  false,
+				NoWait:     false,
+				Args:       nil,
+			},
+			Binding: rabbitmq.BindingParams{
+				Queue:    rabbitmq.FineQueue,
+				Key:      "",
+				Exchange: rabbitmq.FineExchange,
+				NoWait:   false,
+				Args:     nil,
+			},
+		},
+		Retry: rabbitmq.Topology{
+			Exchange: rabbitmq.ExchangeParams{
+				Name:    rabbitmq.FineRetryExchange,
+				Kind:    amqp.ExchangeFanout,
+				Durable: true,
+			},
+			Queue: rabbitmq.QueueParams{
+				Name:    rabbitmq.FineRetryQueue,
+				Durable: true,
+				Args: amqp.Table{
+					"x-dead-letter-exchange":    "",
+					"x-dead-letter-routing-key": rabbitmq.FineQueue,
+				},
+			},
+			Binding: rabbitmq.BindingParams{
+				Queue:    rabbitmq.FineRetryQueue,
+				Exchange: rabbitmq.FineRetryExchange,
+			},
+		},
+		DeadLetter: rabbitmq.Topology{
+			Exchange: rabbitmq.ExchangeParams{
+				Name:    rabbitmq.FineDeadLetterExchange,
+				Kind:    amqp.ExchangeFanout,
+				Durable: true,
+			},
+			Queue: rabbitmq.QueueParams{
+				Name:    rabbitmq.FineDeadLetterQueue,
+				Durable: true,
+			},
+			Binding: rabbitmq.BindingParams{
+				Queue:    rabbitmq.FineDeadLetterQueue,
+				Exchange: rabbitmq.FineDeadLetterExchange,
+			},
+		},
+	}
 }
 
 func setupMailDialer(cfg *config.Config) *gomail.Dialer {
@@ -79,18 +324,15 @@ func setupMailDialer(cfg *config.Config) *gomail.Dialer {
 	)
 }
 
-func startConsume(fineConsumer *rabbitmq.FineConsumer, cfg *config.Config) {
+func startConsume(done <-chan struct{}, fineConsumer *rabbitmq.FineConsumer) {
 	consumeParams := rabbitmq.ConsumeParams{
 		Queue:     rabbitmq.FineQueue,
 		Consumer:  "",
-		AutoAck:   true,
+		AutoAck:   false,
 		Exclusive: false,
 		NoLocal:   false,
 		NoWait:    false,
 		Args:      nil,
 	}
-	err := fineConsumer.StartConsume(consumeParams, cfg.EmailSender.Username, cfg.EmailSender.Subject)
-	if err != nil {
-		log.Fatal(err)
-	}
+	fineConsumer.StartConsume(done, consumeParams)
 }
@@ -0,0 +1,129 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"fine_notification/internal/config"
+	"fine_notification/internal/transport/rabbitmq"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+const (
+	deadLetterUsage = `Usage:
+  deadletter list [-config path] [-limit n] [-body]
+  deadletter requeue [-config path] (-id message_id ... | -all)`
+
+	defaultDeadLetterLimit = 100
+	// deadLetterTimeout limits connection to RabbitMQ and handling of dead letters
+	deadLetterTimeout = 30 * time.Second
+)
+
+// idsFlag collects values of repeated flag
+type idsFlag []string
+
+func (f *idsFlag) String() string {
+	return strings.Join(*f, ",")
+}
+
+func (f *idsFlag) Set(v string) error {
+	*f = append(*f, v)
+	return nil
+}
+
+// RunDeadLetter lists dead-lettered fine notifications or moves them back to fine queue
+func RunDeadLetter(args []string) {
+	if len(args) == 0 {
+		log.Fatal(deadLetterUsage)
+	}
+
+	fs := flag.NewFlagSet(args[0], flag.ExitOnError)
+	configPath := fs.String("config", notificationConfigPath, "path to config")
+	limit := fs.Int("limit", defaultDeadLetterLimit, "max number of listed messages")
+	withBody := fs.Bool("body", false, "print message body")
+	all := fs.Bool("all", false, "requeue all messages")
+	var ids idsFlag
+	fs.Var(&ids, "id", "id of requeued message, may be repeated")
+	if err := fs.Parse(args[1:]); err != nil {
+		log.Fatal(err)
+	}
+
+	cfg, err := config.ParseConfig(*configPath)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	done := make(chan struct{})
+	defer close(done)
+	mqConn := rabbitmq.NewConnection(cfg.RabbitMQ)
+	go mqConn.Run(done)
+
+	ctx, cancel := context.WithTimeout(context.Background(), deadLetterTimeout)
+	defer cancel()
+	queue := rabbitmq.NewDeadLetterQueue(mqConn, fineTopology())
+
+	switch args[0] {
+	case "list":
+		err = listDeadLetters(ctx, queue, *limit, *withBody)
+	case "requeue":
+		if len(ids) == 0 && !*all {
+			log.Fatal(deadLetterUsage)
+		}
+		var requeued int
+		requeued, err = queue.Requeue(ctx, ids)
+		fmt.Printf("Requeued %d messages\n", requeued)
+	default:
+		log.Fatal(deadLetterUsage)
+	}
+
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+func listDeadLetters(ctx context.Context, queue *rabbitmq.DeadLetterQueue, limit int, withBody bool) error {
+	letters, err := queue.List(ctx, limit)
+	if err != nil {
+		return err
+	}
+
+	if withBody {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		for _, l := range letters {
+			err = enc.Encode(struct {
+				MessageID string          `json:"message_id"`
+				Attempts  int             `json:"attempts"`
+				Error     string          `json:"error"`
+				Timestamp time.Time       `json:"timestamp"`
+				Body      json.RawMessage `json:"body"`
+			}{l.MessageID, l.Attempts, l.Error, l.Timestamp, rawBody(l.Body)})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "MESSAGE ID\tATTEMPTS\tTIMESTAMP\tERROR")
+	for _, l := range letters {
+		fmt.Fprintf(w, "%s\t%d\t%s\t%s\n", l.MessageID, l.Attempts, l.Timestamp.Format(time.RFC3339), l.Error)
+	}
+
+	return w.Flush()
+}
+
+// rawBody returns body as JSON, body which is not valid JSON is returned as string
+func rawBody(body []byte) json.RawMessage {
+	if json.Valid(body) {
+		return body
+	}
+	quoted, _ := json.Marshal(string(body))
+	return quoted
+}
@@ -3,18 +3,115 @@ package config
 import (
 	"gopkg.in/yaml.v3"
 	"os"
+	"time"
 )
 
 type Config struct {
 	EmailSender EmailSenderConfig `yaml:"emailSender"`
 	RabbitMQ    RabbitMQConfig    `yaml:"rabbitmq"`
+	Retry       RetryConfig       `yaml:"retry"`
+	Notifiers   NotifiersConfig   `yaml:"notifiers"`
+	Templates   TemplatesConfig   `yaml:"templates"`
+	HTTP        HTTPConfig        `yaml:"http"`
+	Postgres    PostgresConfig    `yaml:"postgres"`
+	Notice      NoticeConfig      `yaml:"notice"`
+	Images      ImagesConfig      `yaml:"images"`
 }
 
-type RabbitMQConfig struct {
+// ImagesConfig limits fetch of case images from service by signed urls of fine events. Timeout limits
+// one request, MaxSize is max size of image in bytes
+type ImagesConfig struct {
+	Timeout time.Duration `yaml:"timeout"`
+	MaxSize int64         `yaml:"maxSize"`
+}
+
+// NoticeConfig sets PDF notice about fine. ReferencePrefix is first digits of payment reference,
+// Payee is used in payment QR code, QR code is skipped without payee name and account
+type NoticeConfig struct {
+	Authority       string      `yaml:"authority"`
+	ReferencePrefix string      `yaml:"referencePrefix"`
+	Payee           PayeeConfig `yaml:"payee"`
+}
+
+// PayeeConfig is payment details of fine receiver
+type PayeeConfig struct {
+	Name        string `yaml:"name"`
+	PersonalAcc string `yaml:"personalAcc"`
+	BankName    string `yaml:"bankName"`
+	BIC         string `yaml:"bic"`
+	CorrespAcc  string `yaml:"correspAcc"`
+	INN         string `yaml:"inn"`
+	KPP         string `yaml:"kpp"`
+	KBK         string `yaml:"kbk"`
+	OKTMO       string `yaml:"oktmo"`
+}
+
+// PostgresConfig is database of delivery ledger
+type PostgresConfig struct {
 	User     string `yaml:"user"`
 	Password string `yaml:"password"`
 	Host     string `yaml:"host"`
 	Port     int    `yaml:"port"`
+	Database string `yaml:"database"`
+}
+
+// TemplatesConfig Locale is default locale of templates. Directory is checked for changes every ReloadInterval
+type TemplatesConfig struct {
+	Dir            string        `yaml:"dir"`
+	Locale         string        `yaml:"locale"`
+	ReloadInterval time.Duration `yaml:"reloadInterval"`
+}
+
+// HTTPConfig server of template preview and delivery status is started, when SigningKey is set.
+// SigningKey must be the same as notification signing key of service, which differs from signing key
+// of users, so fine_notification accepts tokens issued for it and can not issue tokens for service
+type HTTPConfig struct {
+	Port       int    `yaml:"port"`
+	SigningKey string `yaml:"signingKey"`
+}
+
+// NotifiersConfig Order is order of channels for persons without preferences. Telegram, VK and SMS
+// are enabled, when their token is set. Timeout limits one request to API of channel
+type NotifiersConfig struct {
+	Order    []string       `yaml:"order"`
+	Timeout  time.Duration  `yaml:"timeout"`
+	Telegram TelegramConfig `yaml:"telegram"`
+	VK       VKConfig       `yaml:"vk"`
+	SMS      SMSConfig      `yaml:"sms"`
+}
+
+type TelegramConfig struct {
+	URL   string `yaml:"url"`
+	Token string `yaml:"token"`
+}
+
+type VKConfig struct {
+	URL     string `yaml:"url"`
+	Token   string `yaml:"token"`
+	Version string `yaml:"version"`
+}
+
+type SMSConfig struct {
+	URL    string `yaml:"url"`
+	Token  string `yaml:"token"`
+	Sender string `yaml:"sender"`
+}
+
+// RetryConfig MaxAttempts is number of attempts to send notification, before message is moved to
+// dead-letter queue. Delay is time between attempts
+type RetryConfig struct {
+	MaxAttempts int           `yaml:"maxAttempts"`
+	Delay       time.Duration `yaml:"delay"`
+}
+
+// RabbitMQConfig ReconnectMinBackoff and ReconnectMaxBackoff limit delay between dials after connection is lost
+type RabbitMQConfig struct {
+	User                string        `yaml:"user"`
+	Password            string        `yaml:"password"`
+	Host                string        `yaml:"host"`
+	Port                int           `yaml:"port"`
+	ReconnectMinBackoff time.Duration `yaml:"reconnectMinBackoff"`
+	ReconnectMaxBackoff time.Duration `yaml:"reconnectMaxBackoff"`
 }
 
 type EmailSenderConfig struct {
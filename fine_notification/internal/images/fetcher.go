@@ -0,0 +1,63 @@
+package images
+
+import (
+	"context"
+	"errors"
+	"events"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+)
+
+// ErrUnavailable is returned, when service will not give image by url: url is expired, its signature
+// is not accepted, image is deleted or it is larger than max size. Next attempts with the same url fail too
+var ErrUnavailable = errors.New("image is unavailable")
+
+// Fetcher gets case images of fine events from service by signed urls
+type Fetcher struct {
+	client  *http.Client
+	maxSize int64
+}
+
+// NewFetcher creates fetcher, client timeout limits one request, maxSize limits image size in bytes
+func NewFetcher(client *http.Client, maxSize int64) *Fetcher {
+	return &Fetcher{client: client, maxSize: maxSize}
+}
+
+// Fetch returns ErrUnavailable, when service responds with 403, 404 or 410 or image is too large. Error of client is returned
+// without url, because url contains signature
+func (f *Fetcher) Fetch(ctx context.Context, ref events.ImageRef) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, ref.URL, nil)
+	if err != nil {
+		return nil, errors.New("invalid image url")
+	}
+
+	resp, err := f.client.Do(req)
+	if err != nil {
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return nil, fmt.Errorf("get image: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusForbidden, http.StatusNotFound, http.StatusGone:
+		return nil, fmt.Errorf("%w: status %d", ErrUnavailable, resp.StatusCode)
+	default:
+		return nil, fmt.Errorf("get image: unexpected status %d", resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, f.maxSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("read image: %w", err)
+	}
+	if int64(len(data)) > f.maxSize {
+		return nil, fmt.Errorf("%w: image is larger than %d bytes", ErrUnavailable, f.maxSize)
+	}
+
+	return data, nil
+}
@@ -0,0 +1,68 @@
+package images
+
+import (
+	"context"
+	"errors"
+	"events"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestFetcherFetch(t *testing.T) {
+	testCases := []struct {
+		name        string
+		status      int
+		body        string
+		maxSize     int64
+		expected    string
+		expectErr   bool
+		expectedErr error
+	}{
+		{name: "Image is fetched", status: http.StatusOK, body: "image", maxSize: 5, expected: "image"},
+		{name: "Url is expired", status: http.StatusGone, maxSize: 5, expectErr: true, expectedErr: ErrUnavailable},
+		{name: "Signature is not accepted", status: http.StatusForbidden, maxSize: 5, expectErr: true, expectedErr: ErrUnavailable},
+		{name: "Image not exists", status: http.StatusNotFound, maxSize: 5, expectErr: true, expectedErr: ErrUnavailable},
+		{name: "Service is unavailable", status: http.StatusBadGateway, maxSize: 5, expectErr: true},
+		{name: "Image is too large", status: http.StatusOK, body: "image!", maxSize: 5, expectErr: true, expectedErr: ErrUnavailable},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Query().Get("signature") != "sig" {
+					t.Errorf("unexpected query %s", r.URL.RawQuery)
+				}
+				w.WriteHeader(tc.status)
+				_, _ = w.Write([]byte(tc.body))
+			}))
+			defer server.Close()
+
+			f := NewFetcher(server.Client(), tc.maxSize)
+			data, err := f.Fetch(context.Background(), events.ImageRef{URL: server.URL + "/internal/case/1/img?signature=sig"})
+
+			if (err != nil) != tc.expectErr {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tc.expectedErr != nil && !errors.Is(err, tc.expectedErr) {
+				t.Errorf("expected error %v, got %v", tc.expectedErr, err)
+			}
+			if string(data) != tc.expected {
+				t.Errorf("expected %s, got %s", tc.expected, data)
+			}
+		})
+	}
+}
+
+func TestFetcherErrorHidesSignature(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	server.Close()
+
+	f := NewFetcher(http.DefaultClient, 5)
+	_, err := f.Fetch(context.Background(), events.ImageRef{URL: server.URL + "/img?signature=secret-signature"})
+
+	if err == nil || strings.Contains(err.Error(), "secret-signature") {
+		t.Errorf("expected error without signature, got %v", err)
+	}
+}
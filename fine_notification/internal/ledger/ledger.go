@@ -0,0 +1,127 @@
+package ledger
+
+import (
+	"context"
+	"errors"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"time"
+)
+
+type Status string
+
+const (
+	DeliveredStatus Status = "delivered"
+	FailedStatus    Status = "failed"
+)
+
+type Kind string
+
+const (
+	// FineKind is notification about issued fine, CancellationKind is notification about cancelled fine
+	FineKind         Kind = "fine"
+	CancellationKind Kind = "cancellation"
+)
+
+var ErrNoDeliveries = errors.New("no deliveries of case")
+
+// Delivery is state of notification about case by one channel. FineID is empty for notifications
+// without fine, which were sent by earlier versions of service
+type Delivery struct {
+	CaseID      string
+	Kind        Kind
+	FineID      string
+	Channel     string
+	Status      Status
+	Attempts    int
+	LastError   *string
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	DeliveredAt *time.Time
+}
+
+// LedgerPostgres records attempts of notification by case, kind of notification, fine and channel
+type LedgerPostgres struct {
+	db *pgxpool.Pool
+}
+
+func NewLedgerPostgres(pool *pgxpool.Pool) *LedgerPostgres {
+	return &LedgerPostgres{db: pool}
+}
+
+const isDeliveredQuery = `SELECT EXISTS(
+    SELECT 1 FROM notification_deliveries
+    WHERE case_id = $1 AND kind = $2 AND fine_id = $3 AND status = 'delivered'
+)`
+
+// IsDelivered checks, that notification of kind about fine of case is delivered by any channel
+func (l *LedgerPostgres) IsDelivered(ctx context.Context, caseID string, kind string, fineID string) (bool, error) {
+	var delivered bool
+	err := l.db.QueryRow(ctx, isDeliveredQuery, caseID, kind, fineID).Scan(&delivered)
+	return delivered, err
+}
+
+const recordAttemptQuery = `INSERT INTO notification_deliveries
+    (case_id, kind, fine_id, channel, status, attempts, last_error, created_at, updated_at, delivered_at)
+VALUES ($1, $2, $3, $4, $5, 1, $6, NOW(), NOW(), CASE WHEN $5 = 'delivered' THEN NOW() END)
+ON CONFLICT (case_id, kind, fine_id, channel) DO UPDATE
+SET status       = EXCLUDED.status,
+    attempts     = notification_deliveries.attempts + 1,
+    last_error   = COALESCE(EXCLUDED.last_error, notification_deliveries.last_error),
+    updated_at   = EXCLUDED.updated_at,
+    delivered_at = EXCLUDED.delivered_at`
+
+// RecordAttempt records attempt of notification by channel, nil sendErr means notification is delivered.
+// Error of last failed attempt is kept after delivery
+func (l *LedgerPostgres) RecordAttempt(
+	ctx context.Context,
+	caseID string,
+	kind string,
+	fineID string,
+	channel string,
+	sendErr error,
+) error {
+	status := DeliveredStatus
+	var lastError *string
+	if sendErr != nil {
+		status = FailedStatus
+		msg := sendErr.Error()
+		lastError = &msg
+	}
+
+	_, err := l.db.Exec(ctx, recordAttemptQuery, caseID, kind, fineID, channel, string(status), lastError)
+	return err
+}
+
+const getDeliveriesQuery = `SELECT case_id, kind, fine_id, channel, status, attempts, last_error,
+       created_at, updated_at, delivered_at
+FROM notification_deliveries
+WHERE case_id = $1
+ORDER BY created_at, kind, channel`
+
+// GetDeliveries returns deliveries of all notifications about case by all channels, which were tried
+func (l *LedgerPostgres) GetDeliveries(ctx context.Context, caseID string) ([]Delivery, error) {
+	rows, err := l.db.Query(ctx, getDeliveriesQuery, caseID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var deliveries []Delivery
+	for rows.Next() {
+		var d Delivery
+		err = rows.Scan(&d.CaseID, &d.Kind, &d.FineID, &d.Channel, &d.Status, &d.Attempts, &d.LastError,
+			&d.CreatedAt, &d.UpdatedAt, &d.DeliveredAt)
+		if err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, d)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(deliveries) == 0 {
+		return nil, ErrNoDeliveries
+	}
+	return deliveries, nil
+}
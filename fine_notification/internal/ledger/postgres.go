@@ -0,0 +1,52 @@
+package ledger
+
+import (
+	"context"
+	"fine_notification/internal/config"
+	"fmt"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"net/url"
+)
+
+// migrationsTable differs from table of service migrations, so both services may use the same database
+const migrationsTable = "notification_schema_migrations"
+
+func ConnString(cfg config.PostgresConfig) string {
+	return fmt.Sprintf(
+		"postgresql://%s:%s@%s:%d/%s?sslmode=disable",
+		cfg.User,
+		cfg.Password,
+		cfg.Host,
+		cfg.Port,
+		cfg.Database,
+	)
+}
+
+// MigrationsURL is connection string with table of fine_notification migrations
+func MigrationsURL(connString string) (string, error) {
+	u, err := url.Parse(connString)
+	if err != nil {
+		return "", err
+	}
+	q := u.Query()
+	q.Set("x-migrations-table", migrationsTable)
+	u.RawQuery = q.Encode()
+
+	return u.String(), nil
+}
+
+// NewPool creates pool of connections to database and checks connection
+func NewPool(ctx context.Context, cfg config.PostgresConfig) (*pgxpool.Pool, error) {
+	pool, err := pgxpool.New(ctx, ConnString(cfg))
+	if err != nil {
+		return nil, err
+	}
+
+	err = pool.Ping(ctx)
+	if err != nil {
+		pool.Close()
+		return nil, err
+	}
+
+	return pool, nil
+}
@@ -5,11 +5,11 @@ import (
 	"fmt"
 	"gopkg.in/gomail.v2"
 	"io"
-	"time"
 )
 
 const (
 	contextTypeHtml = "text/html"
+	contentTypeText = "text/plain"
 	fieldFrom       = "From"
 	fieldTo         = "To"
 	fieldSubject    = "subject"
@@ -25,36 +25,31 @@ func NewMailer(mailDialer *gomail.Dialer) *Mailer {
 }
 
 func (m *Mailer) SendFineNotification(email Email, caseInfo dto.CaseWithImage) error {
-	body := m.getBodyFromCase(caseInfo)
-
 	gm := gomail.NewMessage()
 	gm.SetHeader(fieldFrom, email.From)
 	gm.SetHeader(fieldTo, email.To)
 	gm.SetHeader(fieldSubject, email.Subject)
-	gm.SetBody(contextTypeHtml, body)
-	gm.Attach(
-		fmt.Sprintf("%s.%s", violationPrefix, caseInfo.ImageExtension),
-		gomail.SetCopyFunc(func(w io.Writer) error {
-			_, err := w.Write(caseInfo.Image)
-			return err
-		}),
-	)
+	gm.SetBody(contentTypeText, email.Text)
+	if email.HTML != "" {
+		gm.AddAlternative(contextTypeHtml, email.HTML)
+	}
+	// image is unavailable or is not sent, for example with notification about cancelled fine
+	if len(caseInfo.Image) > 0 {
+		gm.Attach(
+			fmt.Sprintf("%s.%s", violationPrefix, caseInfo.ImageExtension),
+			gomail.SetCopyFunc(copyData(caseInfo.Image)),
+		)
+	}
+	for _, a := range email.Attachments {
+		gm.Attach(a.Name, gomail.SetCopyFunc(copyData(a.Data)))
+	}
 
 	return m.mailDialer.DialAndSend(gm)
 }
 
-func (m *Mailer) getBodyFromCase(caseInfo dto.CaseWithImage) string {
-	coordinatesInfo := fmt.Sprintf("Координаты места происшествия: %f,%f",
-		caseInfo.Case.Camera.Latitude, caseInfo.Case.Camera.Longitude)
-	violationInfo := fmt.Sprintf("Правонарушение: %s, значение: %s",
-		caseInfo.Case.Violation.Name, caseInfo.Case.ViolationValue)
-	fineAmountInfo := fmt.Sprintf("Размер штрафа: %d", caseInfo.Case.Violation.FineAmount)
-	dateInfo := fmt.Sprintf("Дата: %s", caseInfo.Case.Date.Format(time.RFC850))
-
-	return fmt.Sprintf(`
-<p>%s</p>
-<p>%s</p>
-<p>%s</p>
-<p>%s</p>`,
-		coordinatesInfo, violationInfo, fineAmountInfo, dateInfo)
+func copyData(data []byte) func(w io.Writer) error {
+	return func(w io.Writer) error {
+		_, err := w.Write(data)
+		return err
+	}
 }
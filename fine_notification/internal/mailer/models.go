@@ -1,7 +1,17 @@
 package mailer
 
+// Email Text is plain text body, HTML is alternative part, it is skipped when empty.
+// Attachments are attached after photo of violation
 type Email struct {
-	From    string
-	To      string
-	Subject string
+	From        string
+	To          string
+	Subject     string
+	Text        string
+	HTML        string
+	Attachments []Attachment
+}
+
+type Attachment struct {
+	Name string
+	Data []byte
 }
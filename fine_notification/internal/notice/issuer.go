@@ -0,0 +1,51 @@
+package notice
+
+import (
+	"context"
+	"errors"
+	"fine_notification/internal/transport/dto"
+	"time"
+)
+
+type Store interface {
+	Save(ctx context.Context, n Notice) (bool, error)
+	GetFineNotice(ctx context.Context, caseID string, fineID string) (Notice, error)
+}
+
+// Issuer issues one notice for every fine of case: notice is generated on first issue, then stored notice
+// is returned, so retries of notification attach the same document
+type Issuer struct {
+	generator *Generator
+	store     Store
+	now       func() time.Time
+}
+
+func NewIssuer(generator *Generator, store Store) *Issuer {
+	return &Issuer{
+		generator: generator,
+		store:     store,
+		now:       time.Now,
+	}
+}
+
+func (i *Issuer) Issue(ctx context.Context, c dto.CaseWithImage) (Notice, error) {
+	n, err := i.store.GetFineNotice(ctx, c.Case.ID, c.FineID())
+	if !errors.Is(err, ErrNoNotice) {
+		return n, err
+	}
+
+	n, err = i.generator.Generate(c, i.now().UTC())
+	if err != nil {
+		return Notice{}, err
+	}
+	inserted, err := i.store.Save(ctx, n)
+	if err != nil {
+		return Notice{}, err
+	}
+	if !inserted {
+		// notice was issued in parallel, the first one is kept
+		return i.store.GetFineNotice(ctx, c.Case.ID, c.FineID())
+	}
+
+	return n, nil
+}
@@ -0,0 +1,275 @@
+package notice
+
+import (
+	"bytes"
+	_ "embed"
+	"events"
+	"fine_notification/internal/config"
+	"fine_notification/internal/transport/dto"
+	"fmt"
+	"github.com/jung-kurt/gofpdf"
+	"github.com/skip2/go-qrcode"
+	"log"
+	"strings"
+	"time"
+)
+
+const (
+	fontFamily = "DejaVuSans"
+	dateLayout = "02.01.2006"
+	timeLayout = "02.01.2006 15:04"
+
+	pageMargin  = 20.0
+	labelWidth  = 60.0
+	lineHeight  = 6.0
+	photoWidth  = 170.0
+	photoHeight = 90.0
+	qrSize      = 45.0
+	qrPixels    = 512
+
+	photoImage = "photo"
+	qrImage    = "qr"
+)
+
+//go:embed fonts/DejaVuSans.ttf
+var regularFont []byte
+
+//go:embed fonts/DejaVuSans-Bold.ttf
+var boldFont []byte
+
+// Notice is PDF notice about fine (postanovlenie) with payment reference. FineID is empty for case
+// without fine
+type Notice struct {
+	CaseID    string
+	FineID    string
+	Reference string
+	PDF       []byte
+	CreatedAt time.Time
+}
+
+// Generator builds PDF notices with photo of violation and payment QR code
+type Generator struct {
+	cfg config.NoticeConfig
+}
+
+func NewGenerator(cfg config.NoticeConfig) (*Generator, error) {
+	err := validateReferencePrefix(cfg.ReferencePrefix)
+	if err != nil {
+		return nil, err
+	}
+	if !hasPayee(cfg.Payee) {
+		log.Println("Payee of notice is not set: notices are generated without payment QR code")
+	}
+
+	return &Generator{cfg: cfg}, nil
+}
+
+// Generate builds notice about case, which is issued at issuedAt. Photo, which can not be embedded,
+// is skipped, so notice is issued even for broken image. Notice uses reference and terms of fine
+// issued by service, reference of case without fine is built from case id
+func (g *Generator) Generate(c dto.CaseWithImage, issuedAt time.Time) (Notice, error) {
+	reference := PaymentReference(g.cfg.ReferencePrefix, c.Case.ID)
+	if c.Fine != nil {
+		reference = c.Fine.Reference
+	}
+
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.SetCreationDate(issuedAt)
+	pdf.SetCatalogSort(true)
+	pdf.SetTitle("Постановление "+reference, true)
+	pdf.AddUTF8FontFromBytes(fontFamily, "", regularFont)
+	pdf.AddUTF8FontFromBytes(fontFamily, "B", boldFont)
+	pdf.SetMargins(pageMargin, pageMargin, pageMargin)
+	pdf.AddPage()
+
+	g.writeHeader(pdf, c.Case.ID, issuedAt)
+	writeDetails(pdf, c, reference)
+	writePhoto(pdf, c)
+	g.writePayment(pdf, c, reference, issuedAt)
+
+	var buf bytes.Buffer
+	err := pdf.Output(&buf)
+	if err != nil {
+		return Notice{}, fmt.Errorf("generate notice of case %s: %w", c.Case.ID, err)
+	}
+
+	return Notice{
+		CaseID:    c.Case.ID,
+		FineID:    c.FineID(),
+		Reference: reference,
+		PDF:       buf.Bytes(),
+		CreatedAt: issuedAt,
+	}, nil
+}
+
+func (g *Generator) writeHeader(pdf *gofpdf.Fpdf, caseID string, issuedAt time.Time) {
+	if g.cfg.Authority != "" {
+		pdf.SetFont(fontFamily, "", 10)
+		pdf.MultiCell(0, 5, g.cfg.Authority, "", "C", false)
+		pdf.Ln(4)
+	}
+
+	pdf.SetFont(fontFamily, "B", 16)
+	pdf.CellFormat(0, 8, "ПОСТАНОВЛЕНИЕ", "", 1, "C", false, 0, "")
+	pdf.SetFont(fontFamily, "", 11)
+	pdf.MultiCell(0, lineHeight, "по делу об административном правонарушении № "+caseID, "", "C", false)
+	pdf.CellFormat(0, lineHeight, "Дата вынесения: "+issuedAt.Format(dateLayout), "", 1, "R", false, 0, "")
+	pdf.Ln(4)
+}
+
+type detailRow struct {
+	label string
+	value string
+}
+
+func writeDetails(pdf *gofpdf.Fpdf, c dto.CaseWithImage, reference string) {
+	rows := []detailRow{
+		{label: "Дата и время", value: c.Case.Date.Format(timeLayout)},
+		{label: "Правонарушение", value: c.Case.Violation.Name},
+		{label: "Значение", value: c.Case.ViolationValue},
+		{label: "Транспортное средство", value: plate(c.Case.Transport)},
+		{label: "Место", value: c.Case.Camera.ShortDesc},
+		{label: "Координаты", value: fmt.Sprintf("%f, %f", c.Case.Camera.Latitude, c.Case.Camera.Longitude)},
+		{label: "Размер штрафа", value: fmt.Sprintf("%d руб.", fineAmount(c))},
+	}
+	if c.Fine != nil {
+		if c.Fine.DiscountUntil != nil {
+			rows = append(rows, detailRow{
+				label: "Со скидкой",
+				value: fmt.Sprintf("%d руб. до %s", c.Fine.DiscountAmount, c.Fine.DiscountUntil.Format(dateLayout)),
+			})
+		}
+		rows = append(rows, detailRow{label: "Оплатить до", value: c.Fine.DueAt.Format(dateLayout)})
+	}
+	rows = append(rows, detailRow{label: "УИН", value: reference})
+
+	pdf.SetFont(fontFamily, "", 11)
+	for _, row := range rows {
+		pdf.SetFont(fontFamily, "B", 11)
+		pdf.CellFormat(labelWidth, lineHeight, row.label, "", 0, "L", false, 0, "")
+		pdf.SetFont(fontFamily, "", 11)
+		pdf.MultiCell(0, lineHeight, row.value, "", "L", false)
+	}
+	pdf.Ln(4)
+}
+
+func writePhoto(pdf *gofpdf.Fpdf, c dto.CaseWithImage) {
+	pdf.SetFont(fontFamily, "", 11)
+	if len(c.Image) == 0 {
+		pdf.MultiCell(0, lineHeight, "Фото правонарушения отсутствует", "", "L", false)
+		pdf.Ln(4)
+		return
+	}
+
+	info := pdf.RegisterImageOptionsReader(
+		photoImage, gofpdf.ImageOptions{ImageType: c.ImageExtension}, bytes.NewReader(c.Image),
+	)
+	if pdf.Err() {
+		log.Printf("Photo of case %s is not embedded in notice: %v\n", c.Case.ID, pdf.Error())
+		pdf.ClearError()
+		pdf.MultiCell(0, lineHeight, "Фото правонарушения приложено к уведомлению", "", "L", false)
+		pdf.Ln(4)
+		return
+	}
+
+	// photo is scaled to fit into box, keeping aspect ratio
+	w, h := photoWidth, photoWidth*info.Height()/info.Width()
+	if h > photoHeight {
+		w, h = photoHeight*info.Width()/info.Height(), photoHeight
+	}
+	pdf.ImageOptions(photoImage, pageMargin, pdf.GetY(), w, h, true, gofpdf.ImageOptions{}, 0, "")
+	pdf.Ln(4)
+}
+
+// writePayment writes QR code with amount due at issue of notice, so discount is applied,
+// when notice is paid by QR code during discount period
+func (g *Generator) writePayment(pdf *gofpdf.Fpdf, c dto.CaseWithImage, reference string, issuedAt time.Time) {
+	purpose := "Штраф по постановлению, УИН " + reference
+
+	if hasPayee(g.cfg.Payee) {
+		png, err := qrcode.Encode(
+			paymentQR(g.cfg.Payee, amountDue(c, issuedAt), reference, purpose), qrcode.Medium, qrPixels,
+		)
+		if err != nil {
+			pdf.SetError(fmt.Errorf("encode payment qr code: %w", err))
+			return
+		}
+		pdf.RegisterImageOptionsReader(qrImage, gofpdf.ImageOptions{ImageType: "png"}, bytes.NewReader(png))
+
+		_, pageHeight := pdf.GetPageSize()
+		if pdf.GetY()+qrSize > pageHeight-pageMargin {
+			pdf.AddPage()
+		}
+		top := pdf.GetY()
+		pdf.ImageOptions(qrImage, pageMargin, top, qrSize, qrSize, false, gofpdf.ImageOptions{}, 0, "")
+
+		// payment details are written to the right of QR code
+		pdf.SetLeftMargin(pageMargin + qrSize + 5)
+		pdf.SetXY(pageMargin+qrSize+5, top)
+		pdf.SetFont(fontFamily, "", 9)
+		for _, line := range payeeLines(g.cfg.Payee, purpose) {
+			pdf.MultiCell(0, 4.5, line, "", "L", false)
+		}
+		pdf.SetLeftMargin(pageMargin)
+		pdf.SetXY(pageMargin, max(pdf.GetY(), top+qrSize))
+		pdf.Ln(4)
+	}
+
+	pdf.SetFont(fontFamily, "", 10)
+	if c.Fine == nil {
+		pdf.MultiCell(0, 5, "Штраф должен быть оплачен не позднее 60 дней со дня вступления постановления "+
+			"в законную силу. При оплате укажите УИН "+reference+".", "", "L", false)
+		return
+	}
+
+	terms := "Штраф должен быть оплачен до " + c.Fine.DueAt.Format(dateLayout) + "."
+	if c.Fine.DiscountUntil != nil {
+		terms += fmt.Sprintf(" При оплате до %s включительно оплачивается %d руб.",
+			c.Fine.DiscountUntil.Format(dateLayout), c.Fine.DiscountAmount)
+	}
+	pdf.MultiCell(0, 5, terms+" При оплате укажите УИН "+reference+".", "", "L", false)
+}
+
+// fineAmount is amount of issued fine, amount of violation is used for case without fine
+func fineAmount(c dto.CaseWithImage) int {
+	if c.Fine != nil {
+		return c.Fine.Amount
+	}
+	return c.Case.Violation.FineAmount
+}
+
+// amountDue is discount amount of fine during discount period, then full amount
+func amountDue(c dto.CaseWithImage, t time.Time) int {
+	if c.Fine != nil && c.Fine.DiscountUntil != nil && !t.After(*c.Fine.DiscountUntil) {
+		return c.Fine.DiscountAmount
+	}
+	return fineAmount(c)
+}
+
+func payeeLines(payee config.PayeeConfig, purpose string) []string {
+	lines := []string{"Получатель: " + payee.Name}
+	optional := []struct {
+		label string
+		value string
+	}{
+		{label: "ИНН", value: payee.INN},
+		{label: "КПП", value: payee.KPP},
+		{label: "Счет", value: payee.PersonalAcc},
+		{label: "Банк", value: payee.BankName},
+		{label: "БИК", value: payee.BIC},
+		{label: "Корр. счет", value: payee.CorrespAcc},
+		{label: "КБК", value: payee.KBK},
+		{label: "ОКТМО", value: payee.OKTMO},
+	}
+	for _, o := range optional {
+		if o.value != "" {
+			lines = append(lines, o.label+": "+o.value)
+		}
+	}
+
+	return append(lines, "Назначение платежа: "+purpose)
+}
+
+func plate(t events.Transport) string {
+	return strings.TrimSpace(fmt.Sprintf("%s %s %s", t.Chars, t.Num, t.Region))
+}
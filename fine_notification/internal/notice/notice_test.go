@@ -0,0 +1,256 @@
+package notice
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"events"
+	"fine_notification/internal/config"
+	"fine_notification/internal/transport/dto"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"testing"
+	"time"
+)
+
+var testPayee = config.PayeeConfig{
+	Name:        "УФК по г. Москве (ГУ МВД России по г. Москве)",
+	PersonalAcc: "03100643000000017300",
+	BankName:    "ГУ Банка России по ЦФО",
+	BIC:         "004525988",
+	CorrespAcc:  "40102810545370000003",
+	INN:         "7707089101",
+	KPP:         "770731005",
+	KBK:         "18811601123010001140",
+	OKTMO:       "45379000",
+}
+
+func testImage(t *testing.T, encode func(buf *bytes.Buffer, img image.Image) error) []byte {
+	img := image.NewRGBA(image.Rect(0, 0, 40, 20))
+	for x := 0; x < 40; x++ {
+		for y := 0; y < 20; y++ {
+			img.Set(x, y, color.RGBA{R: uint8(x * 6), G: uint8(y * 12), B: 100, A: 255})
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := encode(&buf, img); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func testCase(image []byte, extension string) dto.CaseWithImage {
+	return dto.CaseWithImage{
+		Case: events.Case{
+			ID:             "case 1",
+			Transport:      events.Transport{Chars: "ABC", Num: "123", Region: "77"},
+			Camera:         events.Camera{Latitude: 55.753722, Longitude: 37.621139, ShortDesc: "Камера на Тверской"},
+			Violation:      events.Violation{Name: "Превышение скорости", FineAmount: 500},
+			ViolationValue: "80 км/ч",
+			Date:           time.Date(2024, time.March, 1, 12, 30, 0, 0, time.UTC),
+		},
+		Image:          image,
+		ImageExtension: extension,
+	}
+}
+
+func testCaseWithFine(issuedAt time.Time) dto.CaseWithImage {
+	c := testCase(nil, "")
+	discountUntil := issuedAt.Add(20 * 24 * time.Hour)
+	c.Fine = &events.Fine{
+		Reference: "18810123456789012345", Amount: 500, DiscountAmount: 250, DiscountUntil: &discountUntil,
+		IssuedAt: issuedAt, DueAt: issuedAt.Add(60 * 24 * time.Hour), Status: "issued",
+	}
+	return c
+}
+
+func TestGenerate(t *testing.T) {
+	pngImage := testImage(t, func(buf *bytes.Buffer, img image.Image) error { return png.Encode(buf, img) })
+	jpegImage := testImage(t, func(buf *bytes.Buffer, img image.Image) error { return jpeg.Encode(buf, img, nil) })
+	issuedAt := time.Date(2024, time.March, 2, 9, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name  string
+		payee config.PayeeConfig
+		c     dto.CaseWithImage
+		// expectedImages are embedded photo and QR code
+		expectedImages int
+	}{
+		{name: "PNG photo and QR code", payee: testPayee, c: testCase(pngImage, "png"), expectedImages: 2},
+		{name: "JPEG photo and QR code", payee: testPayee, c: testCase(jpegImage, "jpeg"), expectedImages: 2},
+		{name: "Broken photo is skipped", payee: testPayee, c: testCase([]byte("not image"), "png"), expectedImages: 1},
+		{name: "No photo", payee: testPayee, c: testCase(nil, ""), expectedImages: 1},
+		{name: "No payee, QR code is skipped", c: testCase(pngImage, "png"), expectedImages: 1},
+		{name: "Fine issued by service", payee: testPayee, c: testCaseWithFine(issuedAt), expectedImages: 1},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			generator, err := NewGenerator(config.NoticeConfig{
+				Authority: "ГИБДД ГУ МВД России по г. Москве", ReferencePrefix: "18810", Payee: tc.payee,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			n, err := generator.Generate(tc.c, issuedAt)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			expectedReference := PaymentReference("18810", tc.c.Case.ID)
+			if tc.c.Fine != nil {
+				expectedReference = tc.c.Fine.Reference
+			}
+			if n.CaseID != tc.c.Case.ID || n.Reference != expectedReference || !n.CreatedAt.Equal(issuedAt) {
+				t.Errorf("unexpected notice %s %s %s", n.CaseID, n.Reference, n.CreatedAt)
+			}
+			if !bytes.HasPrefix(n.PDF, []byte("%PDF-")) {
+				t.Fatalf("notice is not PDF")
+			}
+			images := bytes.Count(n.PDF, []byte("/Subtype /Image"))
+			if images != tc.expectedImages {
+				t.Errorf("expected %d images, got %d", tc.expectedImages, images)
+			}
+		})
+	}
+}
+
+func TestPaymentQR(t *testing.T) {
+	payee := config.PayeeConfig{Name: "Получатель | УФК", PersonalAcc: "03100643000000017300", BIC: "004525988"}
+
+	actual := paymentQR(payee, 500, "18810000000000000001", "Штраф")
+	expected := "ST00012|Name=Получатель   УФК|PersonalAcc=03100643000000017300|BIC=004525988|Sum=50000" +
+		"|Purpose=Штраф|UIN=18810000000000000001"
+	if actual != expected {
+		t.Errorf("expected %s, got %s", expected, actual)
+	}
+}
+
+func TestAmountDue(t *testing.T) {
+	issuedAt := time.Date(2024, time.March, 2, 9, 0, 0, 0, time.UTC)
+	withFine := testCaseWithFine(issuedAt)
+	withoutDiscount := testCaseWithFine(issuedAt)
+	withoutDiscount.Fine.DiscountUntil = nil
+
+	testCases := []struct {
+		name     string
+		c        dto.CaseWithImage
+		t        time.Time
+		expected int
+	}{
+		{name: "Case without fine", c: testCase(nil, ""), t: issuedAt, expected: 500},
+		{name: "Discount period", c: withFine, t: issuedAt, expected: 250},
+		{name: "Last day of discount", c: withFine, t: *withFine.Fine.DiscountUntil, expected: 250},
+		{name: "After discount period", c: withFine, t: issuedAt.Add(21 * 24 * time.Hour), expected: 500},
+		{name: "Fine without discount", c: withoutDiscount, t: issuedAt, expected: 500},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if actual := amountDue(tc.c, tc.t); actual != tc.expected {
+				t.Errorf("expected %d, got %d", tc.expected, actual)
+			}
+		})
+	}
+}
+
+// memoryStore keeps notices in memory by case and fine, saved is set to false to emulate parallel issue
+type memoryStore struct {
+	notices  map[string]Notice
+	parallel *Notice
+	saveErr  error
+}
+
+func noticeKey(caseID string, fineID string) string {
+	return caseID + "/" + fineID
+}
+
+func (s *memoryStore) Save(_ context.Context, n Notice) (bool, error) {
+	if s.saveErr != nil {
+		return false, s.saveErr
+	}
+	if s.parallel != nil {
+		s.notices[noticeKey(n.CaseID, n.FineID)] = *s.parallel
+		return false, nil
+	}
+	s.notices[noticeKey(n.CaseID, n.FineID)] = n
+	return true, nil
+}
+
+func (s *memoryStore) GetFineNotice(_ context.Context, caseID string, fineID string) (Notice, error) {
+	n, ok := s.notices[noticeKey(caseID, fineID)]
+	if !ok {
+		return Notice{}, ErrNoNotice
+	}
+	return n, nil
+}
+
+func TestIssue(t *testing.T) {
+	generator, err := NewGenerator(config.NoticeConfig{ReferencePrefix: "18810", Payee: testPayee})
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := testCase(nil, "")
+	stored := Notice{CaseID: c.Case.ID, Reference: "stored", PDF: []byte("%PDF-1.3")}
+	errDB := errors.New("db error")
+	reissued := testCaseWithFine(time.Date(2024, time.March, 2, 9, 0, 0, 0, time.UTC))
+	reissued.Fine.ID = "reissued_fine"
+
+	testCases := []struct {
+		name              string
+		c                 *dto.CaseWithImage
+		store             *memoryStore
+		expectedReference string
+		expectedErr       error
+	}{
+		{
+			name:              "First issue generates notice",
+			store:             &memoryStore{notices: map[string]Notice{}},
+			expectedReference: PaymentReference("18810", c.Case.ID),
+		},
+		{
+			name:              "Stored notice is returned",
+			store:             &memoryStore{notices: map[string]Notice{noticeKey(c.Case.ID, ""): stored}},
+			expectedReference: "stored",
+		},
+		{
+			name:  "Fine, which is issued again after cancellation, has own notice",
+			c:     &reissued,
+			store: &memoryStore{notices: map[string]Notice{noticeKey(c.Case.ID, "cancelled_fine"): stored}},
+			// reference of fine is kept, when fine is issued again
+			expectedReference: reissued.Fine.Reference,
+		},
+		{
+			name:              "Notice is issued in parallel, the first one is returned",
+			store:             &memoryStore{notices: map[string]Notice{}, parallel: &stored},
+			expectedReference: "stored",
+		},
+		{
+			name:        "Notice is not saved",
+			store:       &memoryStore{notices: map[string]Notice{}, saveErr: errDB},
+			expectedErr: errDB,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			issuer := NewIssuer(generator, tc.store)
+			issued := c
+			if tc.c != nil {
+				issued = *tc.c
+			}
+
+			n, err := issuer.Issue(context.Background(), issued)
+			if !errors.Is(err, tc.expectedErr) {
+				t.Fatalf("expected error %v, got %v", tc.expectedErr, err)
+			}
+			if n.Reference != tc.expectedReference {
+				t.Errorf("expected reference %s, got %s", tc.expectedReference, n.Reference)
+			}
+		})
+	}
+}
@@ -0,0 +1,49 @@
+package notice
+
+import (
+	"fine_notification/internal/config"
+	"strconv"
+	"strings"
+)
+
+// paymentFormatHeader is header of payment QR code by GOST R 56042-2014, last digit 2 is UTF-8 encoding
+const paymentFormatHeader = "ST00012"
+
+// hasPayee checks required payment details of QR code
+func hasPayee(payee config.PayeeConfig) bool {
+	return payee.Name != "" && payee.PersonalAcc != ""
+}
+
+// paymentQR is content of payment QR code by GOST R 56042-2014, amount is in rubles.
+// Empty optional details are skipped
+func paymentQR(payee config.PayeeConfig, amount int, reference string, purpose string) string {
+	fields := []struct {
+		key   string
+		value string
+	}{
+		{key: "Name", value: payee.Name},
+		{key: "PersonalAcc", value: payee.PersonalAcc},
+		{key: "BankName", value: payee.BankName},
+		{key: "BIC", value: payee.BIC},
+		{key: "CorrespAcc", value: payee.CorrespAcc},
+		{key: "PayeeINN", value: payee.INN},
+		{key: "KPP", value: payee.KPP},
+		{key: "CBC", value: payee.KBK},
+		{key: "OKTMO", value: payee.OKTMO},
+		{key: "Sum", value: strconv.Itoa(amount * 100)},
+		{key: "Purpose", value: purpose},
+		{key: "UIN", value: reference},
+	}
+
+	var b strings.Builder
+	b.WriteString(paymentFormatHeader)
+	for _, f := range fields {
+		if f.value == "" {
+			continue
+		}
+		// separator can not be escaped in value
+		b.WriteString("|" + f.key + "=" + strings.ReplaceAll(f.value, "|", " "))
+	}
+
+	return b.String()
+}
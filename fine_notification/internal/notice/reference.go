@@ -0,0 +1,51 @@
+package notice
+
+import (
+	"fmt"
+	"hash/fnv"
+	"strings"
+)
+
+const (
+	referenceLen = 20
+	// maxReferencePrefixLen leaves at least 9 digits of case hash in reference
+	maxReferencePrefixLen = 10
+)
+
+// validateReferencePrefix checks, that prefix of payment reference contains only digits
+// and leaves enough digits for case
+func validateReferencePrefix(prefix string) error {
+	if len(prefix) > maxReferencePrefixLen {
+		return fmt.Errorf("reference prefix is longer than %d digits: %s", maxReferencePrefixLen, prefix)
+	}
+	if strings.Trim(prefix, "0123456789") != "" {
+		return fmt.Errorf("reference prefix must contain only digits: %s", prefix)
+	}
+	return nil
+}
+
+// PaymentReference is 20 digit unique identifier of accrual (UIN): prefix, digits of case id hash
+// and check digit. Reference is the same for every notice about case
+func PaymentReference(prefix string, caseID string) string {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(caseID))
+	digits := fmt.Sprintf("%020d", h.Sum64())
+
+	body := prefix + digits[len(digits)-(referenceLen-1-len(prefix)):]
+	return body + string(rune('0'+checkDigit(body)))
+}
+
+// checkDigit of UIN: digits are weighted by 1..10 in cycle, remainder of division by 11 is check digit.
+// When remainder is 10, weights are shifted by 2, and remainder 10 gives check digit 0
+func checkDigit(digits string) int {
+	for _, shift := range []int{0, 2} {
+		sum := 0
+		for i, d := range digits {
+			sum += int(d-'0') * ((i+shift)%10 + 1)
+		}
+		if sum%11 < 10 {
+			return sum % 11
+		}
+	}
+	return 0
+}
@@ -0,0 +1,71 @@
+package notice
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCheckDigit(t *testing.T) {
+	testCases := []struct {
+		name     string
+		digits   string
+		expected int
+	}{
+		{name: "Remainder with base weights", digits: "1881017719031234567", expected: 6},
+		{name: "Remainder 10, weights are shifted", digits: "1881010000000000004", expected: 5},
+		{name: "Remainder 10 with shifted weights", digits: "1881010000000000097", expected: 0},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			actual := checkDigit(tc.digits)
+			if actual != tc.expected {
+				t.Errorf("expected %d, got %d", tc.expected, actual)
+			}
+		})
+	}
+}
+
+func TestPaymentReference(t *testing.T) {
+	for _, prefix := range []string{"", "18810", "1881017719"} {
+		reference := PaymentReference(prefix, "case 1")
+		if len(reference) != referenceLen || !strings.HasPrefix(reference, prefix) {
+			t.Errorf("unexpected reference %s with prefix %s", reference, prefix)
+		}
+		if strings.Trim(reference, "0123456789") != "" {
+			t.Errorf("reference contains not only digits: %s", reference)
+		}
+		if int(reference[referenceLen-1]-'0') != checkDigit(reference[:referenceLen-1]) {
+			t.Errorf("invalid check digit of reference %s", reference)
+		}
+		if reference != PaymentReference(prefix, "case 1") {
+			t.Errorf("reference of the same case differs")
+		}
+	}
+
+	if PaymentReference("18810", "case 1") == PaymentReference("18810", "case 2") {
+		t.Errorf("references of different cases are equal")
+	}
+}
+
+func TestValidateReferencePrefix(t *testing.T) {
+	testCases := []struct {
+		name         string
+		prefix       string
+		expectingErr bool
+	}{
+		{name: "Empty prefix", prefix: ""},
+		{name: "Digits", prefix: "18810"},
+		{name: "Not digits", prefix: "188A0", expectingErr: true},
+		{name: "Too long", prefix: "18810177190", expectingErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := validateReferencePrefix(tc.prefix)
+			if (err != nil) != tc.expectingErr {
+				t.Errorf("expecting error %v, got %v", tc.expectingErr, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,63 @@
+package notice
+
+import (
+	"context"
+	"errors"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+var ErrNoNotice = errors.New("no notice of case")
+
+// StorePostgres keeps one notice for every fine of case, so fine, which is issued again after cancellation,
+// has own notice
+type StorePostgres struct {
+	db *pgxpool.Pool
+}
+
+func NewStorePostgres(pool *pgxpool.Pool) *StorePostgres {
+	return &StorePostgres{db: pool}
+}
+
+const saveNoticeQuery = `INSERT INTO notices (case_id, fine_id, reference, pdf, created_at)
+VALUES ($1, $2, $3, $4, $5)
+ON CONFLICT (case_id, fine_id) DO NOTHING`
+
+// Save saves notice, if fine of case has no notice yet. Inserted is false, when notice of fine already exists
+func (s *StorePostgres) Save(ctx context.Context, n Notice) (inserted bool, err error) {
+	tag, err := s.db.Exec(ctx, saveNoticeQuery, n.CaseID, n.FineID, n.Reference, n.PDF, n.CreatedAt)
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() == 1, nil
+}
+
+const getNoticeQuery = `SELECT case_id, fine_id, reference, pdf, created_at
+FROM notices
+WHERE case_id = $1
+ORDER BY created_at DESC
+LIMIT 1`
+
+// Get returns the last notice of case
+func (s *StorePostgres) Get(ctx context.Context, caseID string) (Notice, error) {
+	return s.get(ctx, getNoticeQuery, caseID)
+}
+
+const getFineNoticeQuery = `SELECT case_id, fine_id, reference, pdf, created_at
+FROM notices
+WHERE case_id = $1 AND fine_id = $2`
+
+// GetFineNotice returns notice of fine of case, empty fineID means case without fine
+func (s *StorePostgres) GetFineNotice(ctx context.Context, caseID string, fineID string) (Notice, error) {
+	return s.get(ctx, getFineNoticeQuery, caseID, fineID)
+}
+
+func (s *StorePostgres) get(ctx context.Context, query string, args ...any) (Notice, error) {
+	var n Notice
+	err := s.db.QueryRow(ctx, query, args...).Scan(&n.CaseID, &n.FineID, &n.Reference, &n.PDF, &n.CreatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return Notice{}, ErrNoNotice
+	}
+
+	return n, err
+}
@@ -0,0 +1,109 @@
+package notice
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fine_notification/internal/ledger"
+	"fmt"
+	"github.com/golang-migrate/migrate"
+	_ "github.com/golang-migrate/migrate/database/postgres"
+	_ "github.com/golang-migrate/migrate/source/file"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"os"
+	"testing"
+	"time"
+)
+
+// testPostgresURLEnv contains url of database for store tests, tests are skipped without it
+const testPostgresURLEnv = "TEST_POSTGRES_URL"
+
+func TestStorePostgres(t *testing.T) {
+	dbURL := os.Getenv(testPostgresURLEnv)
+	if dbURL == "" {
+		t.Skipf("%s is not set", testPostgresURLEnv)
+	}
+
+	migrationsURL, err := ledger.MigrationsURL(dbURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m, err := migrate.New("file://../../migrations", migrationsURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = m.Up(); err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	pool, err := pgxpool.New(ctx, dbURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(pool.Close)
+	store := NewStorePostgres(pool)
+
+	caseID := fmt.Sprintf("test_%d", time.Now().UnixNano())
+	t.Cleanup(func() {
+		_, _ = pool.Exec(ctx, `DELETE FROM notices WHERE case_id = $1`, caseID)
+	})
+
+	_, err = store.Get(ctx, caseID)
+	if !errors.Is(err, ErrNoNotice) {
+		t.Fatalf("expected %v, got %v", ErrNoNotice, err)
+	}
+
+	first := Notice{CaseID: caseID, FineID: "fine_1", Reference: "18810000000000000001", PDF: []byte("%PDF-1"),
+		CreatedAt: time.Now().UTC().Truncate(time.Second)}
+	inserted, err := store.Save(ctx, first)
+	if err != nil || !inserted {
+		t.Fatalf("expected inserted notice, got %v %v", inserted, err)
+	}
+
+	second := first
+	second.PDF = []byte("%PDF-2")
+	inserted, err = store.Save(ctx, second)
+	if err != nil || inserted {
+		t.Fatalf("expected notice to be kept, got %v %v", inserted, err)
+	}
+
+	actual, err := store.Get(ctx, caseID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if actual.Reference != first.Reference || actual.FineID != first.FineID || !bytes.Equal(actual.PDF, first.PDF) ||
+		!actual.CreatedAt.Equal(first.CreatedAt) {
+		t.Errorf("unexpected notice %+v", actual)
+	}
+
+	_, err = store.GetFineNotice(ctx, caseID, "fine_2")
+	if !errors.Is(err, ErrNoNotice) {
+		t.Fatalf("expected %v, got %v", ErrNoNotice, err)
+	}
+
+	// fine is issued again after cancellation
+	reissued := first
+	reissued.FineID = "fine_2"
+	reissued.PDF = []byte("%PDF-3")
+	reissued.CreatedAt = first.CreatedAt.Add(time.Minute)
+	inserted, err = store.Save(ctx, reissued)
+	if err != nil || !inserted {
+		t.Fatalf("expected inserted notice of reissued fine, got %v %v", inserted, err)
+	}
+
+	actual, err = store.Get(ctx, caseID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if actual.FineID != reissued.FineID || !bytes.Equal(actual.PDF, reissued.PDF) {
+		t.Errorf("expected the last notice, got %+v", actual)
+	}
+	actual, err = store.GetFineNotice(ctx, caseID, first.FineID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(actual.PDF, first.PDF) {
+		t.Errorf("unexpected notice of fine %+v", actual)
+	}
+}
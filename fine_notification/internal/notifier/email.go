@@ -0,0 +1,80 @@
+package notifier
+
+import (
+	"context"
+	"fine_notification/internal/mailer"
+	"fine_notification/internal/transport/dto"
+	"fmt"
+)
+
+const noticeFilePrefix = "notice"
+
+type EmailSender interface {
+	SendFineNotification(email mailer.Email, c dto.CaseWithImage) error
+}
+
+// EmailNotifier sends email with plain text and html parts and PDF notice. Subject is rendered
+// from template, configured subject is used without template. Email about cancelled fine has no notice
+type EmailNotifier struct {
+	sender   EmailSender
+	renderer Renderer
+	notices  NoticeIssuer
+	from     string
+	subject  string
+}
+
+func NewEmailNotifier(
+	sender EmailSender,
+	renderer Renderer,
+	notices NoticeIssuer,
+	from string,
+	subject string,
+) *EmailNotifier {
+	return &EmailNotifier{
+		sender:   sender,
+		renderer: renderer,
+		notices:  notices,
+		from:     from,
+		subject:  subject,
+	}
+}
+
+func (n *EmailNotifier) Channel() Channel {
+	return EmailChannel
+}
+
+func (n *EmailNotifier) Notify(ctx context.Context, c dto.CaseWithImage) error {
+	to := person(c).Email
+	if to == "" {
+		return ErrNoContact
+	}
+
+	msg, err := n.renderer.Render(string(EmailChannel), "", c)
+	if err != nil {
+		return err
+	}
+	if msg.Subject == "" {
+		msg.Subject = n.subject
+	}
+	email := mailer.Email{
+		From:    n.from,
+		To:      to,
+		Subject: msg.Subject,
+		Text:    msg.Text,
+		HTML:    msg.HTML,
+	}
+	if c.IsCancellation() {
+		return n.sender.SendFineNotification(email, c)
+	}
+
+	fineNotice, err := n.notices.Issue(ctx, c)
+	if err != nil {
+		return err
+	}
+	email.Attachments = []mailer.Attachment{{
+		Name: fmt.Sprintf("%s_%s.pdf", noticeFilePrefix, fineNotice.Reference),
+		Data: fineNotice.PDF,
+	}}
+
+	return n.sender.SendFineNotification(email, c)
+}
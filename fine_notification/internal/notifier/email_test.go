@@ -0,0 +1,72 @@
+package notifier
+
+import (
+	"context"
+	"events"
+	"fine_notification/internal/mailer"
+	"fine_notification/internal/transport/dto"
+	"strings"
+	"testing"
+)
+
+type emailSenderFunc func(email mailer.Email, c dto.CaseWithImage) error
+
+func (f emailSenderFunc) SendFineNotification(email mailer.Email, c dto.CaseWithImage) error {
+	return f(email, c)
+}
+
+func TestEmailNotifierNotify(t *testing.T) {
+	var sent mailer.Email
+	sender := emailSenderFunc(func(email mailer.Email, c dto.CaseWithImage) error {
+		sent = email
+		return nil
+	})
+	n := NewEmailNotifier(sender, testRenderer(t), &stubNoticeIssuer{}, "from@example.com", "Config subject")
+
+	err := n.Notify(context.Background(), testCase(events.Person{Email: "to@example.com"}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if sent.From != "from@example.com" || sent.To != "to@example.com" {
+		t.Errorf("unexpected addresses: %+v", sent)
+	}
+	if sent.Subject != "Информация о правонарушении" {
+		t.Errorf("expected subject from template, got %s", sent.Subject)
+	}
+	if !strings.Contains(sent.Text, "Правонарушение: speeding") || !strings.Contains(sent.HTML, "<p>Правонарушение: speeding") {
+		t.Errorf("unexpected body: %+v", sent)
+	}
+	if len(sent.Attachments) != 1 || sent.Attachments[0].Name != "notice_18810000000000000001.pdf" ||
+		string(sent.Attachments[0].Data) != "%PDF-1.3" {
+		t.Errorf("expected notice attachment, got %+v", sent.Attachments)
+	}
+}
+
+func TestEmailNotifierNotifyCancellation(t *testing.T) {
+	var sent mailer.Email
+	sender := emailSenderFunc(func(email mailer.Email, c dto.CaseWithImage) error {
+		sent = email
+		return nil
+	})
+	notices := &stubNoticeIssuer{}
+	n := NewEmailNotifier(sender, testRenderer(t), notices, "from@example.com", "Config subject")
+
+	c := testCase(events.Person{Email: "to@example.com"})
+	c.Fine = &events.Fine{ID: "fine_id", Reference: "18810000000000000001", Status: "cancelled"}
+	err := n.Notify(context.Background(), c)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if sent.Subject != "Штраф по правонарушению отменен" {
+		t.Errorf("expected subject of cancellation, got %s", sent.Subject)
+	}
+	if !strings.Contains(sent.Text, "Штраф отменен") || !strings.Contains(sent.Text, "18810000000000000001") ||
+		!strings.Contains(sent.HTML, "<p>Штраф отменен.</p>") {
+		t.Errorf("unexpected body: %+v", sent)
+	}
+	if len(sent.Attachments) != 0 || notices.issued != 0 {
+		t.Errorf("expected email without notice, got %+v", sent.Attachments)
+	}
+}
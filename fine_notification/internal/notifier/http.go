@@ -0,0 +1,42 @@
+package notifier
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+)
+
+// maxErrorBody limits body of failed response, which is added to error
+const maxErrorBody = 512
+
+// do sends request and decodes JSON response into out, when out is not nil. Error of client is returned
+// without url, because url may contain token
+func do(client *http.Client, req *http.Request, out any) error {
+	resp, err := client.Do(req)
+	if err != nil {
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return fmt.Errorf("%s request: %w", req.Method, err)
+	}
+	defer resp.Body.Close()
+
+	if out == nil {
+		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+			body, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBody))
+			return fmt.Errorf("unexpected status %d: %s", resp.StatusCode, body)
+		}
+		return nil
+	}
+
+	err = json.NewDecoder(resp.Body).Decode(out)
+	if err != nil {
+		return fmt.Errorf("decode response with status %d: %w", resp.StatusCode, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,157 @@
+package notifier
+
+import (
+	"context"
+	"errors"
+	"events"
+	"fine_notification/internal/ledger"
+	"fine_notification/internal/notice"
+	"fine_notification/internal/templates"
+	"fine_notification/internal/transport/dto"
+	"fmt"
+	"log"
+)
+
+type Channel string
+
+const (
+	EmailChannel    Channel = "email"
+	TelegramChannel Channel = "telegram"
+	VKChannel       Channel = "vk"
+	SMSChannel      Channel = "sms"
+)
+
+// ErrNoContact is returned, when person has no contact for channel. Notification with no contacts
+// for all channels can not be sent in next attempts
+var ErrNoContact = errors.New("person has no contact for notification channel")
+
+// Renderer renders notification of channel from templates. Empty locale means default locale
+type Renderer interface {
+	Render(channel string, locale string, c dto.CaseWithImage) (templates.Message, error)
+}
+
+// Notifier sends fine notification to person by one channel
+type Notifier interface {
+	Channel() Channel
+	Notify(ctx context.Context, c dto.CaseWithImage) error
+}
+
+// Ledger records attempts of notification, so notification of every kind about fine of case is delivered once
+type Ledger interface {
+	IsDelivered(ctx context.Context, caseID string, kind string, fineID string) (bool, error)
+	RecordAttempt(ctx context.Context, caseID string, kind string, fineID string, channel string, sendErr error) error
+}
+
+// NoticeIssuer issues PDF notice about fine of case, repeated issue returns the same notice
+type NoticeIssuer interface {
+	Issue(ctx context.Context, c dto.CaseWithImage) (notice.Notice, error)
+}
+
+// Dispatcher sends fine notification by first channel, which succeeds. Channels are tried in order of
+// person preference, then in default order
+type Dispatcher struct {
+	ledger       Ledger
+	notices      NoticeIssuer
+	notifiers    map[Channel]Notifier
+	defaultOrder []Channel
+}
+
+// NewDispatcher creates dispatcher, default order contains channels of notifiers in order of arguments
+func NewDispatcher(ledger Ledger, notices NoticeIssuer, notifiers ...Notifier) *Dispatcher {
+	d := &Dispatcher{ledger: ledger, notices: notices, notifiers: make(map[Channel]Notifier, len(notifiers))}
+	for _, n := range notifiers {
+		d.notifiers[n.Channel()] = n
+		d.defaultOrder = append(d.defaultOrder, n.Channel())
+	}
+
+	return d
+}
+
+// SendFineNotification returns ErrNoContact, when person has no contact for all channels, otherwise
+// it returns errors of all failed channels. Notification, which is already delivered, is not sent again.
+// Notice is issued before sending by any channel, so director can get it for every notified case.
+// Notification about cancelled fine is sent without notice
+func (d *Dispatcher) SendFineNotification(ctx context.Context, c dto.CaseWithImage) error {
+	kind := ledger.FineKind
+	if c.IsCancellation() {
+		kind = ledger.CancellationKind
+	}
+
+	delivered, err := d.ledger.IsDelivered(ctx, c.Case.ID, string(kind), c.FineID())
+	if err != nil {
+		return err
+	}
+	if delivered {
+		log.Printf("Notification %s about case %s is already delivered\n", kind, c.Case.ID)
+		return nil
+	}
+
+	if kind == ledger.FineKind {
+		_, err = d.notices.Issue(ctx, c)
+		if err != nil {
+			return fmt.Errorf("issue notice: %w", err)
+		}
+	}
+
+	var errs []error
+	for _, channel := range d.order(c.Case.Transport.Person) {
+		err = d.notifiers[channel].Notify(ctx, c)
+		if errors.Is(err, ErrNoContact) {
+			continue
+		}
+		d.recordAttempt(ctx, c, kind, channel, err)
+		if err == nil {
+			return nil
+		}
+
+		log.Printf("Notify case %s by %s: %v\n", c.Case.ID, channel, err)
+		errs = append(errs, fmt.Errorf("%s: %w", channel, err))
+	}
+
+	if len(errs) == 0 {
+		return ErrNoContact
+	}
+	return errors.Join(errs...)
+}
+
+// recordAttempt only logs error of ledger: when notification is delivered, it must not be sent again
+// because of ledger error
+func (d *Dispatcher) recordAttempt(
+	ctx context.Context,
+	c dto.CaseWithImage,
+	kind ledger.Kind,
+	channel Channel,
+	sendErr error,
+) {
+	err := d.ledger.RecordAttempt(ctx, c.Case.ID, string(kind), c.FineID(), string(channel), sendErr)
+	if err != nil {
+		log.Printf("Record notification %s attempt of case %s by %s: %v\n", kind, c.Case.ID, channel, err)
+	}
+}
+
+// order returns preferred channels of person and then other channels in default order.
+// Unknown and disabled channels are skipped
+func (d *Dispatcher) order(person *events.Person) []Channel {
+	order := make([]Channel, 0, len(d.defaultOrder))
+	added := make(map[Channel]bool, len(d.defaultOrder))
+
+	var preferred []string
+	if person != nil {
+		preferred = person.NotifyChannels
+	}
+	for _, p := range preferred {
+		channel := Channel(p)
+		if _, ok := d.notifiers[channel]; ok && !added[channel] {
+			order = append(order, channel)
+			added[channel] = true
+		}
+	}
+	for _, channel := range d.defaultOrder {
+		if !added[channel] {
+			order = append(order, channel)
+			added[channel] = true
+		}
+	}
+
+	return order
+}
@@ -0,0 +1,208 @@
+package notifier
+
+import (
+	"context"
+	"errors"
+	"events"
+	"fine_notification/internal/notice"
+	"fine_notification/internal/transport/dto"
+	"reflect"
+	"testing"
+)
+
+// stubNotifier records calls and returns err
+type stubNotifier struct {
+	channel Channel
+	err     error
+	calls   *[]Channel
+}
+
+func (n stubNotifier) Channel() Channel {
+	return n.channel
+}
+
+func (n stubNotifier) Notify(_ context.Context, _ dto.CaseWithImage) error {
+	*n.calls = append(*n.calls, n.channel)
+	return n.err
+}
+
+// stubLedger records attempts in memory, keys are kinds and fines of checked and recorded notifications
+type stubLedger struct {
+	delivered   bool
+	deliveryErr error
+	attempts    []string
+	keys        []string
+}
+
+func (l *stubLedger) IsDelivered(_ context.Context, _ string, kind string, fineID string) (bool, error) {
+	l.keys = append(l.keys, kind+" "+fineID)
+	return l.delivered, l.deliveryErr
+}
+
+func (l *stubLedger) RecordAttempt(
+	_ context.Context,
+	_ string,
+	kind string,
+	fineID string,
+	channel string,
+	sendErr error,
+) error {
+	status := "delivered"
+	if sendErr != nil {
+		status = "failed"
+	}
+	l.attempts = append(l.attempts, channel+" "+status)
+	l.keys = append(l.keys, kind+" "+fineID)
+	return nil
+}
+
+// stubNoticeIssuer counts issued notices and returns err
+type stubNoticeIssuer struct {
+	err    error
+	issued int
+}
+
+func (i *stubNoticeIssuer) Issue(_ context.Context, c dto.CaseWithImage) (notice.Notice, error) {
+	if i.err != nil {
+		return notice.Notice{}, i.err
+	}
+	i.issued++
+	return notice.Notice{CaseID: c.Case.ID, Reference: "18810000000000000001", PDF: []byte("%PDF-1.3")}, nil
+}
+
+func TestDispatcherSendFineNotification(t *testing.T) {
+	errSend := errors.New("send error")
+
+	testCases := []struct {
+		name          string
+		errs          map[Channel]error
+		preferred     []string
+		fine          *events.Fine
+		ledger        *stubLedger
+		noticeErr     error
+		expectedCalls []Channel
+		expectedErr   error
+		// expectedAttempts are attempts recorded in ledger
+		expectedAttempts []string
+		// expectedKey is kind and fine of notification in ledger
+		expectedKey string
+	}{
+		{
+			name:             "Default order. First channel succeeds",
+			expectedCalls:    []Channel{EmailChannel},
+			expectedAttempts: []string{"email delivered"},
+			expectedKey:      "fine ",
+		},
+		{
+			name:             "Notification about fine",
+			fine:             &events.Fine{ID: "fine_id", Status: "issued"},
+			expectedCalls:    []Channel{EmailChannel},
+			expectedAttempts: []string{"email delivered"},
+			expectedKey:      "fine fine_id",
+		},
+		{
+			name:             "Notification about cancelled fine is sent without notice",
+			fine:             &events.Fine{ID: "fine_id", Status: "cancelled"},
+			noticeErr:        errSend,
+			expectedCalls:    []Channel{EmailChannel},
+			expectedAttempts: []string{"email delivered"},
+			expectedKey:      "cancellation fine_id",
+		},
+		{
+			name:          "Notification is already delivered",
+			ledger:        &stubLedger{delivered: true},
+			expectedCalls: nil,
+		},
+		{
+			name:          "Ledger is unavailable",
+			ledger:        &stubLedger{deliveryErr: errSend},
+			expectedCalls: nil,
+			expectedErr:   errSend,
+		},
+		{
+			name:          "Notice is not issued",
+			noticeErr:     errSend,
+			expectedCalls: nil,
+			expectedErr:   errSend,
+		},
+		{
+			name:          "Default order. Person has no email, fallback to telegram",
+			errs:          map[Channel]error{EmailChannel: ErrNoContact},
+			expectedCalls: []Channel{EmailChannel, TelegramChannel},
+		},
+		{
+			name:          "Preferred channel is tried first",
+			preferred:     []string{string(SMSChannel)},
+			expectedCalls: []Channel{SMSChannel},
+		},
+		{
+			name:             "Preferred channel fails, fallback to default order without repeat",
+			errs:             map[Channel]error{TelegramChannel: errSend},
+			preferred:        []string{string(TelegramChannel), "fax"},
+			expectedCalls:    []Channel{TelegramChannel, EmailChannel},
+			expectedAttempts: []string{"telegram failed", "email delivered"},
+		},
+		{
+			name: "All channels fail",
+			errs: map[Channel]error{
+				EmailChannel: errSend, TelegramChannel: ErrNoContact, SMSChannel: ErrNoContact,
+			},
+			expectedCalls:    []Channel{EmailChannel, TelegramChannel, SMSChannel},
+			expectedErr:      errSend,
+			expectedAttempts: []string{"email failed"},
+		},
+		{
+			name: "Person has no contacts",
+			errs: map[Channel]error{
+				EmailChannel: ErrNoContact, TelegramChannel: ErrNoContact, SMSChannel: ErrNoContact,
+			},
+			expectedCalls: []Channel{EmailChannel, TelegramChannel, SMSChannel},
+			expectedErr:   ErrNoContact,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var calls []Channel
+			var notifiers []Notifier
+			for _, channel := range []Channel{EmailChannel, TelegramChannel, SMSChannel} {
+				notifiers = append(notifiers, stubNotifier{channel: channel, err: tc.errs[channel], calls: &calls})
+			}
+			ledger := tc.ledger
+			if ledger == nil {
+				ledger = &stubLedger{}
+			}
+			notices := &stubNoticeIssuer{err: tc.noticeErr}
+			dispatcher := NewDispatcher(ledger, notices, notifiers...)
+
+			c := dto.CaseWithImage{Case: events.Case{Transport: events.Transport{
+				Person: &events.Person{NotifyChannels: tc.preferred},
+			}}, Fine: tc.fine}
+			err := dispatcher.SendFineNotification(context.Background(), c)
+
+			if !errors.Is(err, tc.expectedErr) || (tc.expectedErr == nil && err != nil) {
+				t.Errorf("expected error %v, got %v", tc.expectedErr, err)
+			}
+			if !reflect.DeepEqual(tc.expectedCalls, calls) {
+				t.Errorf("expected calls %v, got %v", tc.expectedCalls, calls)
+			}
+			if tc.expectedAttempts != nil && !reflect.DeepEqual(tc.expectedAttempts, ledger.attempts) {
+				t.Errorf("expected attempts %v, got %v", tc.expectedAttempts, ledger.attempts)
+			}
+			if tc.expectedKey != "" {
+				for _, key := range ledger.keys {
+					if key != tc.expectedKey {
+						t.Errorf("expected notification %q in ledger, got %q", tc.expectedKey, key)
+					}
+				}
+			}
+			expectedNotices := 1
+			if c.IsCancellation() {
+				expectedNotices = 0
+			}
+			if len(tc.expectedCalls) > 0 && notices.issued != expectedNotices {
+				t.Errorf("expected %d notices to be issued before sending, got %d", expectedNotices, notices.issued)
+			}
+		})
+	}
+}
@@ -0,0 +1,13 @@
+package notifier
+
+import (
+	"events"
+	"fine_notification/internal/transport/dto"
+)
+
+func person(c dto.CaseWithImage) events.Person {
+	if c.Case.Transport.Person == nil {
+		return events.Person{}
+	}
+	return *c.Case.Transport.Person
+}
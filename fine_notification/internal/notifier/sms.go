@@ -0,0 +1,65 @@
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fine_notification/internal/transport/dto"
+	"net/http"
+)
+
+// SMSNotifier sends SMS to phone of person by HTTP gateway. Gateway accepts JSON with phone, sender and
+// text and responds with 2xx status, when SMS is accepted
+type SMSNotifier struct {
+	client   *http.Client
+	renderer Renderer
+	url      string
+	token    string
+	sender   string
+}
+
+func NewSMSNotifier(client *http.Client, renderer Renderer, url string, token string, sender string) *SMSNotifier {
+	return &SMSNotifier{
+		client:   client,
+		renderer: renderer,
+		url:      url,
+		token:    token,
+		sender:   sender,
+	}
+}
+
+type smsMessage struct {
+	To   string `json:"to"`
+	From string `json:"from"`
+	Text string `json:"text"`
+}
+
+func (n *SMSNotifier) Channel() Channel {
+	return SMSChannel
+}
+
+func (n *SMSNotifier) Notify(ctx context.Context, c dto.CaseWithImage) error {
+	phone := person(c).PhoneNum
+	if phone == "" {
+		return ErrNoContact
+	}
+
+	msg, err := n.renderer.Render(string(SMSChannel), "", c)
+	if err != nil {
+		return err
+	}
+
+	body, err := json.Marshal(smsMessage{To: phone, From: n.sender, Text: msg.Text})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+n.token)
+
+	return do(n.client, req, nil)
+}
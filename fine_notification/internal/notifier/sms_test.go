@@ -0,0 +1,72 @@
+package notifier
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"events"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestSMSNotifierNotify(t *testing.T) {
+	token := "sms-token"
+	sender := "GIBDD"
+
+	testCases := []struct {
+		name        string
+		phone       string
+		status      int
+		expectErr   bool
+		expectedErr error
+	}{
+		{
+			name:   "SMS is accepted",
+			phone:  "+79990000000",
+			status: http.StatusAccepted,
+		},
+		{
+			name:      "Gateway is unavailable",
+			phone:     "+79990000000",
+			status:    http.StatusServiceUnavailable,
+			expectErr: true,
+		},
+		{
+			name:        "Person has no phone",
+			expectErr:   true,
+			expectedErr: ErrNoContact,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Header.Get("Authorization") != "Bearer "+token {
+					t.Errorf("unexpected authorization %s", r.Header.Get("Authorization"))
+				}
+				var msg smsMessage
+				if err := json.NewDecoder(r.Body).Decode(&msg); err != nil {
+					t.Error(err)
+				}
+				if msg.To != tc.phone || msg.From != sender || !strings.HasPrefix(msg.Text, "Штраф 500 ₽: speeding") {
+					t.Errorf("unexpected message %+v", msg)
+				}
+
+				w.WriteHeader(tc.status)
+			}))
+			defer server.Close()
+
+			n := NewSMSNotifier(server.Client(), testRenderer(t), server.URL, token, sender)
+			err := n.Notify(context.Background(), testCase(events.Person{PhoneNum: tc.phone}))
+
+			if (err != nil) != tc.expectErr {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tc.expectedErr != nil && !errors.Is(err, tc.expectedErr) {
+				t.Errorf("expected error %v, got %v", tc.expectedErr, err)
+			}
+		})
+	}
+}
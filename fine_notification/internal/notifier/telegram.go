@@ -0,0 +1,78 @@
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fine_notification/internal/transport/dto"
+	"fmt"
+	"net/http"
+)
+
+// TelegramNotifier sends message to chat of person by Telegram Bot API
+type TelegramNotifier struct {
+	client   *http.Client
+	renderer Renderer
+	url      string
+	token    string
+}
+
+func NewTelegramNotifier(client *http.Client, renderer Renderer, url string, token string) *TelegramNotifier {
+	return &TelegramNotifier{
+		client:   client,
+		renderer: renderer,
+		url:      url,
+		token:    token,
+	}
+}
+
+type telegramMessage struct {
+	ChatID string `json:"chat_id"`
+	Text   string `json:"text"`
+}
+
+type telegramResponse struct {
+	OK          bool   `json:"ok"`
+	ErrorCode   int    `json:"error_code"`
+	Description string `json:"description"`
+}
+
+func (n *TelegramNotifier) Channel() Channel {
+	return TelegramChannel
+}
+
+func (n *TelegramNotifier) Notify(ctx context.Context, c dto.CaseWithImage) error {
+	chatID := person(c).TgID
+	if chatID == "" {
+		return ErrNoContact
+	}
+
+	msg, err := n.renderer.Render(string(TelegramChannel), "", c)
+	if err != nil {
+		return err
+	}
+
+	body, err := json.Marshal(telegramMessage{ChatID: chatID, Text: msg.Text})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(
+		ctx, http.MethodPost, fmt.Sprintf("%s/bot%s/sendMessage", n.url, n.token), bytes.NewReader(body),
+	)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	var resp telegramResponse
+	err = do(n.client, req, &resp)
+	if err != nil {
+		return err
+	}
+	if !resp.OK {
+		return fmt.Errorf("telegram error %d: %s", resp.ErrorCode, resp.Description)
+	}
+
+	return nil
+}
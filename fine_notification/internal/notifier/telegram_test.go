@@ -0,0 +1,104 @@
+package notifier
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"events"
+	"fine_notification/internal/templates"
+	"fine_notification/internal/transport/dto"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestTelegramNotifierNotify(t *testing.T) {
+	token := "bot-token"
+
+	testCases := []struct {
+		name        string
+		tgID        string
+		response    string
+		status      int
+		expectErr   bool
+		expectedErr error
+	}{
+		{
+			name:     "Message is sent",
+			tgID:     "12345",
+			response: `{"ok":true,"result":{}}`,
+			status:   http.StatusOK,
+		},
+		{
+			name:      "Telegram returns error",
+			tgID:      "12345",
+			response:  `{"ok":false,"error_code":403,"description":"Forbidden: bot was blocked by the user"}`,
+			status:    http.StatusForbidden,
+			expectErr: true,
+		},
+		{
+			name:        "Person has no telegram",
+			expectErr:   true,
+			expectedErr: ErrNoContact,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/bot"+token+"/sendMessage" {
+					t.Errorf("unexpected path %s", r.URL.Path)
+				}
+				var msg telegramMessage
+				if err := json.NewDecoder(r.Body).Decode(&msg); err != nil {
+					t.Error(err)
+				}
+				if msg.ChatID != tc.tgID || !strings.Contains(msg.Text, "Правонарушение: speeding") {
+					t.Errorf("unexpected message %+v", msg)
+				}
+
+				w.WriteHeader(tc.status)
+				_, _ = w.Write([]byte(tc.response))
+			}))
+			defer server.Close()
+
+			n := NewTelegramNotifier(server.Client(), testRenderer(t), server.URL, token)
+			err := n.Notify(context.Background(), testCase(events.Person{TgID: tc.tgID}))
+
+			if (err != nil) != tc.expectErr {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tc.expectedErr != nil && !errors.Is(err, tc.expectedErr) {
+				t.Errorf("expected error %v, got %v", tc.expectedErr, err)
+			}
+		})
+	}
+}
+
+func TestTelegramNotifierErrorHidesToken(t *testing.T) {
+	token := "secret-token"
+	n := NewTelegramNotifier(http.DefaultClient, testRenderer(t), "http://127.0.0.1:0", token)
+
+	err := n.Notify(context.Background(), testCase(events.Person{TgID: "12345"}))
+	if err == nil || strings.Contains(err.Error(), token) {
+		t.Errorf("expected error without token, got %v", err)
+	}
+}
+
+// testRenderer renders templates, which are deployed with service
+func testRenderer(t *testing.T) *templates.Renderer {
+	renderer, err := templates.NewRenderer("../../templates", "ru")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return renderer
+}
+
+func testCase(p events.Person) dto.CaseWithImage {
+	return dto.CaseWithImage{Case: events.Case{
+		ID:        "case-id",
+		Transport: events.Transport{Person: &p},
+		Violation: events.Violation{Name: "speeding", FineAmount: 500},
+	}}
+}
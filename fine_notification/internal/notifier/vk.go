@@ -0,0 +1,101 @@
+package notifier
+
+import (
+	"context"
+	"fine_notification/internal/transport/dto"
+	"fmt"
+	"hash/fnv"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// VKNotifier sends message to person by VK API messages.send on behalf of community
+type VKNotifier struct {
+	client   *http.Client
+	renderer Renderer
+	url      string
+	token    string
+	version  string
+}
+
+func NewVKNotifier(client *http.Client, renderer Renderer, url string, token string, version string) *VKNotifier {
+	return &VKNotifier{
+		client:   client,
+		renderer: renderer,
+		url:      url,
+		token:    token,
+		version:  version,
+	}
+}
+
+type vkResponse struct {
+	Error *struct {
+		Code    int    `json:"error_code"`
+		Message string `json:"error_msg"`
+	} `json:"error"`
+}
+
+func (n *VKNotifier) Channel() Channel {
+	return VKChannel
+}
+
+func (n *VKNotifier) Notify(ctx context.Context, c dto.CaseWithImage) error {
+	userID := person(c).VkID
+	if userID == "" {
+		return ErrNoContact
+	}
+
+	msg, err := n.renderer.Render(string(VKChannel), "", c)
+	if err != nil {
+		return err
+	}
+
+	form := url.Values{
+		"user_id":      {userID},
+		"random_id":    {strconv.FormatUint(uint64(randomID(notificationKey(c))), 10)},
+		"message":      {msg.Text},
+		"access_token": {n.token},
+		"v":            {n.version},
+	}
+	req, err := http.NewRequestWithContext(
+		ctx, http.MethodPost, n.url+"/method/messages.send", strings.NewReader(form.Encode()),
+	)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	var resp vkResponse
+	err = do(n.client, req, &resp)
+	if err != nil {
+		return err
+	}
+	if resp.Error != nil {
+		return fmt.Errorf("vk error %d: %s", resp.Error.Code, resp.Error.Message)
+	}
+
+	return nil
+}
+
+// randomID is the same for every attempt of case notification, so VK does not send message again,
+// when response of previous attempt was lost
+func randomID(key string) int32 {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(key))
+	return int32(h.Sum32() >> 1)
+}
+
+// notificationKey differs for notifications about fine, its cancellation and fine, which is issued again,
+// so they are not deduplicated by VK as attempts of one notification
+func notificationKey(c dto.CaseWithImage) string {
+	if c.Fine == nil {
+		return c.Case.ID
+	}
+	key := c.Case.ID + "/" + c.Fine.ID
+	if c.IsCancellation() {
+		key += "/cancellation"
+	}
+	return key
+}
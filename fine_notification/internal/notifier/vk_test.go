@@ -0,0 +1,99 @@
+package notifier
+
+import (
+	"context"
+	"errors"
+	"events"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+)
+
+func TestVKNotifierNotify(t *testing.T) {
+	token := "vk-token"
+	version := "5.199"
+
+	testCases := []struct {
+		name        string
+		vkID        string
+		response    string
+		expectErr   bool
+		expectedErr error
+	}{
+		{
+			name:     "Message is sent",
+			vkID:     "100",
+			response: `{"response":1}`,
+		},
+		{
+			name:      "VK returns error",
+			vkID:      "100",
+			response:  `{"error":{"error_code":901,"error_msg":"Can't send messages for users without permission"}}`,
+			expectErr: true,
+		},
+		{
+			name:        "Person has no vk",
+			expectErr:   true,
+			expectedErr: ErrNoContact,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/method/messages.send" {
+					t.Errorf("unexpected path %s", r.URL.Path)
+				}
+				if err := r.ParseForm(); err != nil {
+					t.Error(err)
+				}
+				expectedRandomID := strconv.FormatUint(uint64(randomID("case-id")), 10)
+				if r.PostForm.Get("user_id") != tc.vkID || r.PostForm.Get("access_token") != token ||
+					r.PostForm.Get("v") != version || r.PostForm.Get("random_id") != expectedRandomID ||
+					r.PostForm.Get("message") == "" {
+					t.Errorf("unexpected form %v", r.PostForm)
+				}
+
+				_, _ = w.Write([]byte(tc.response))
+			}))
+			defer server.Close()
+
+			n := NewVKNotifier(server.Client(), testRenderer(t), server.URL, token, version)
+			err := n.Notify(context.Background(), testCase(events.Person{VkID: tc.vkID}))
+
+			if (err != nil) != tc.expectErr {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tc.expectedErr != nil && !errors.Is(err, tc.expectedErr) {
+				t.Errorf("expected error %v, got %v", tc.expectedErr, err)
+			}
+		})
+	}
+}
+
+func TestRandomID(t *testing.T) {
+	if randomID("case-id") != randomID("case-id") {
+		t.Error("random id of the same case differs")
+	}
+	if randomID("case-id") < 0 {
+		t.Error("random id is negative")
+	}
+}
+
+func TestNotificationKey(t *testing.T) {
+	c := testCase(events.Person{VkID: "1"})
+	if notificationKey(c) != c.Case.ID {
+		t.Errorf("expected case id as key of case without fine, got %s", notificationKey(c))
+	}
+
+	c.Fine = &events.Fine{ID: "fine_id", Status: "issued"}
+	issued := notificationKey(c)
+	c.Fine.Status = "cancelled"
+	cancelled := notificationKey(c)
+	c.Fine = &events.Fine{ID: "reissued_fine_id", Status: "issued"}
+	reissued := notificationKey(c)
+	if issued == cancelled || issued == reissued || cancelled == reissued {
+		t.Errorf("expected different keys, got %s, %s, %s", issued, cancelled, reissued)
+	}
+}
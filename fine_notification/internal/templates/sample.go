@@ -0,0 +1,65 @@
+package templates
+
+import (
+	"events"
+	"fine_notification/internal/transport/dto"
+	"time"
+)
+
+// SampleCase is used to preview templates
+func SampleCase() dto.CaseWithImage {
+	issuedAt := time.Date(2024, time.March, 2, 9, 0, 0, 0, time.UTC)
+	discountUntil := issuedAt.AddDate(0, 0, 20)
+
+	return dto.CaseWithImage{
+		Case: events.Case{
+			ID: "00000000-0000-0000-0000-000000000000",
+			Transport: events.Transport{
+				ID:     "00000000-0000-0000-0000-000000000001",
+				Chars:  "ABC",
+				Num:    "123",
+				Region: "77",
+				Person: &events.Person{
+					ID:       "00000000-0000-0000-0000-000000000002",
+					PhoneNum: "+79990000000",
+					Email:    "owner@example.com",
+				},
+			},
+			Camera: events.Camera{
+				ID:        "00000000-0000-0000-0000-000000000003",
+				Latitude:  55.753722,
+				Longitude: 37.621139,
+				ShortDesc: "Camera on Red Square",
+			},
+			Violation: events.Violation{
+				ID:         "00000000-0000-0000-0000-000000000004",
+				Name:       "Превышение скорости",
+				FineAmount: 500,
+			},
+			ViolationValue: "80 км/ч",
+			RequiredSkill:  1,
+			Date:           time.Date(2024, time.March, 1, 12, 30, 0, 0, time.UTC),
+			IsSolved:       true,
+			FineDecision:   true,
+		},
+		ImageExtension: "jpg",
+		Fine: &events.Fine{
+			ID:             "00000000-0000-0000-0000-000000000005",
+			CaseID:         "00000000-0000-0000-0000-000000000000",
+			Reference:      "18810000000000000001",
+			Amount:         500,
+			DiscountAmount: 250,
+			DiscountUntil:  &discountUntil,
+			IssuedAt:       issuedAt,
+			DueAt:          issuedAt.AddDate(0, 0, 60),
+			Status:         "issued",
+		},
+	}
+}
+
+// SampleCancellation is used to preview templates of notification about cancelled fine
+func SampleCancellation() dto.CaseWithImage {
+	c := SampleCase()
+	c.Fine.Status = "cancelled"
+	return c
+}
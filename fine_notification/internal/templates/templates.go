@@ -0,0 +1,328 @@
+package templates
+
+import (
+	"bytes"
+	"errors"
+	"events"
+	"fine_notification/internal/transport/dto"
+	"fmt"
+	htmltemplate "html/template"
+	"io"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	texttemplate "text/template"
+	"time"
+)
+
+type Kind string
+
+const (
+	SubjectKind Kind = "subject"
+	TextKind    Kind = "text"
+	HTMLKind    Kind = "html"
+
+	// defaultName is name of template, which is used for channels without own template
+	defaultName = "default"
+	// cancellationName is name of template of notification about cancelled fine, which is used for channels
+	// without own template cancellation_<channel>
+	cancellationName = "cancellation"
+
+	subjectSuffix = ".subject.txt"
+	textSuffix    = ".txt"
+	htmlSuffix    = ".html"
+)
+
+var ErrNoTemplate = errors.New("no template")
+
+// errChanged is returned, when files of directory were changed during reload, for example template
+// was still being written, so parsed templates may be partial
+var errChanged = errors.New("templates were changed during reload")
+
+// Data is passed to templates. Fine is nil, when service did not issue fine
+type Data struct {
+	Case           events.Case
+	Fine           *events.Fine
+	ImageExtension string
+	Channel        string
+	Locale         string
+}
+
+type Message struct {
+	Subject string `json:"subject"`
+	Text    string `json:"text"`
+	HTML    string `json:"html,omitempty"`
+}
+
+// executor is parsed html or text template
+type executor interface {
+	Execute(w io.Writer, data any) error
+}
+
+var funcs = map[string]any{
+	"date": func(t time.Time, layout string) string {
+		return t.Format(layout)
+	},
+}
+
+// Renderer renders templates of directory. Templates are stored as <locale>/<channel><suffix>, where suffix
+// is .subject.txt, .txt or .html. Template with name default is used for channels without own template,
+// templates of default locale are used for locales without own template. Notification about cancelled fine
+// is rendered from templates cancellation_<channel> and cancellation
+type Renderer struct {
+	dir           string
+	defaultLocale string
+
+	mu        sync.RWMutex
+	templates map[string]executor
+	// signature contains paths, sizes and modification times of files of loaded templates
+	signature string
+}
+
+func NewRenderer(dir string, defaultLocale string) (*Renderer, error) {
+	r := &Renderer{
+		dir:           dir,
+		defaultLocale: defaultLocale,
+	}
+
+	return r, r.Reload()
+}
+
+// Reload parses all templates of directory. Templates are replaced only when all of them are parsed
+// and files were not changed during parsing. Empty template is an error, it is usually a file,
+// which is not written yet
+func (r *Renderer) Reload() error {
+	signature, err := r.dirSignature()
+	if err != nil {
+		return err
+	}
+
+	parsed := make(map[string]executor)
+	err = filepath.WalkDir(r.dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		locale, name, kind, ok := r.parsePath(path)
+		if !ok {
+			return nil
+		}
+
+		tmpl, err := parseFile(kind, path)
+		if err != nil {
+			return err
+		}
+		parsed[key(locale, name, kind)] = tmpl
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	parsedSignature, err := r.dirSignature()
+	if err != nil {
+		return err
+	}
+	if parsedSignature != signature {
+		return errChanged
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.templates = parsed
+	r.signature = signature
+
+	return nil
+}
+
+// Run reloads templates every interval, when files of directory are changed, until done is closed.
+// Templates are reloaded, when files are not changed for one interval, so files, which are being written,
+// are not loaded. Templates with errors are not loaded, previous templates are used
+func (r *Renderer) Run(done <-chan struct{}, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	// pending is signature of changed files, which were seen on previous tick
+	var pending string
+	for {
+		select {
+		case <-ticker.C:
+			signature, err := r.dirSignature()
+			if err != nil {
+				log.Printf("Templates: %v\n", err)
+				continue
+			}
+
+			r.mu.RLock()
+			changed := signature != r.signature
+			r.mu.RUnlock()
+			if !changed {
+				continue
+			}
+			if signature != pending {
+				pending = signature
+				continue
+			}
+
+			err = r.Reload()
+			if err != nil {
+				log.Printf("Reload templates: %v\n", err)
+				continue
+			}
+			log.Printf("Templates are reloaded from %s\n", r.dir)
+		case <-done:
+			return
+		}
+	}
+}
+
+// Render renders subject, text and html of channel notification. Text template is required,
+// subject and html are empty without templates. Empty locale means default locale
+func (r *Renderer) Render(channel string, locale string, c dto.CaseWithImage) (Message, error) {
+	if locale == "" {
+		locale = r.defaultLocale
+	}
+	data := Data{Case: c.Case, Fine: c.Fine, ImageExtension: c.ImageExtension, Channel: channel, Locale: locale}
+
+	var msg Message
+	for _, part := range []struct {
+		kind     Kind
+		dst      *string
+		required bool
+	}{
+		{kind: SubjectKind, dst: &msg.Subject},
+		{kind: TextKind, dst: &msg.Text, required: true},
+		{kind: HTMLKind, dst: &msg.HTML},
+	} {
+		tmpl, ok := r.lookup(templateNames(channel, c), locale, part.kind)
+		if !ok {
+			if part.required {
+				return Message{}, fmt.Errorf("%w: %s %s for %s", ErrNoTemplate, locale, part.kind, channel)
+			}
+			continue
+		}
+
+		rendered, err := execute(tmpl, data)
+		if err != nil {
+			return Message{}, err
+		}
+		*part.dst = rendered
+	}
+	msg.Subject = strings.TrimSpace(msg.Subject)
+
+	return msg, nil
+}
+
+// RenderSource parses and renders template source, so template may be checked before it is deployed
+func RenderSource(kind Kind, source string, data Data) (string, error) {
+	var tmpl executor
+	var err error
+	switch kind {
+	case HTMLKind:
+		tmpl, err = htmltemplate.New(string(kind)).Funcs(funcs).Parse(source)
+	case TextKind, SubjectKind:
+		tmpl, err = texttemplate.New(string(kind)).Funcs(funcs).Parse(source)
+	default:
+		return "", fmt.Errorf("unknown template kind: %s", kind)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	return execute(tmpl, data)
+}
+
+// templateNames returns names of templates of channel notification in order of lookup
+func templateNames(channel string, c dto.CaseWithImage) []string {
+	if c.IsCancellation() {
+		return []string{cancellationName + "_" + channel, cancellationName}
+	}
+	return []string{channel, defaultName}
+}
+
+func (r *Renderer) lookup(names []string, locale string, kind Kind) (executor, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, l := range []string{locale, r.defaultLocale} {
+		for _, name := range names {
+			if tmpl, ok := r.templates[key(l, name, kind)]; ok {
+				return tmpl, true
+			}
+		}
+	}
+
+	return nil, false
+}
+
+// parsePath returns locale, name and kind of template from path <dir>/<locale>/<name><suffix>
+func (r *Renderer) parsePath(path string) (string, string, Kind, bool) {
+	rel, err := filepath.Rel(r.dir, path)
+	if err != nil {
+		return "", "", "", false
+	}
+	locale, file, ok := strings.Cut(filepath.ToSlash(rel), "/")
+	if !ok || strings.Contains(file, "/") {
+		return "", "", "", false
+	}
+
+	switch {
+	case strings.HasSuffix(file, subjectSuffix):
+		return locale, strings.TrimSuffix(file, subjectSuffix), SubjectKind, true
+	case strings.HasSuffix(file, textSuffix):
+		return locale, strings.TrimSuffix(file, textSuffix), TextKind, true
+	case strings.HasSuffix(file, htmlSuffix):
+		return locale, strings.TrimSuffix(file, htmlSuffix), HTMLKind, true
+	default:
+		return "", "", "", false
+	}
+}
+
+func (r *Renderer) dirSignature() (string, error) {
+	var b strings.Builder
+	err := filepath.WalkDir(r.dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(&b, "%s %d %d\n", path, info.Size(), info.ModTime().UnixNano())
+		return nil
+	})
+
+	return b.String(), err
+}
+
+func parseFile(kind Kind, path string) (executor, error) {
+	source, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if len(bytes.TrimSpace(source)) == 0 {
+		return nil, fmt.Errorf("template %s is empty", path)
+	}
+
+	name := filepath.Base(path)
+	if kind == HTMLKind {
+		return htmltemplate.New(name).Funcs(funcs).Parse(string(source))
+	}
+	return texttemplate.New(name).Funcs(funcs).Parse(string(source))
+}
+
+func execute(tmpl executor, data Data) (string, error) {
+	var buf bytes.Buffer
+	err := tmpl.Execute(&buf, data)
+	if err != nil {
+		return "", err
+	}
+
+	return buf.String(), nil
+}
+
+func key(locale string, name string, kind Kind) string {
+	return locale + "/" + name + "." + string(kind)
+}
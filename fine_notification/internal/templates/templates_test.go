@@ -0,0 +1,246 @@
+package templates
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func writeTemplate(t *testing.T, dir string, path string, content string) {
+	t.Helper()
+	path = filepath.Join(dir, path)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRendererRender(t *testing.T) {
+	dir := t.TempDir()
+	writeTemplate(t, dir, "ru/default.txt", "ru default {{ .Case.Violation.Name }}")
+	writeTemplate(t, dir, "ru/sms.txt", "ru sms {{ .Case.Violation.FineAmount }}")
+	writeTemplate(t, dir, "ru/email.subject.txt", "ru subject {{ .Case.ID }}\n")
+	writeTemplate(t, dir, "ru/email.html", "<p>{{ .Case.Violation.Name }}</p>")
+	writeTemplate(t, dir, "en/default.txt", "en default {{ date .Case.Date \"2006-01-02\" }}")
+	writeTemplate(t, dir, "en/readme.md", "not a template")
+
+	renderer, err := NewRenderer(dir, "ru")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c := SampleCase()
+	c.Case.Violation.Name = "<speeding>"
+
+	testCases := []struct {
+		name     string
+		channel  string
+		locale   string
+		expected Message
+	}{
+		{
+			name:    "Channel template of default locale",
+			channel: "sms",
+			expected: Message{
+				Text: "ru sms 500",
+			},
+		},
+		{
+			name:    "Email with subject and escaped html",
+			channel: "email",
+			locale:  "ru",
+			expected: Message{
+				Subject: "ru subject " + c.Case.ID,
+				Text:    "ru default <speeding>",
+				HTML:    "<p>&lt;speeding&gt;</p>",
+			},
+		},
+		{
+			name:    "Default template of locale, other parts from default locale",
+			channel: "email",
+			locale:  "en",
+			expected: Message{
+				Subject: "ru subject " + c.Case.ID,
+				Text:    "en default 2024-03-01",
+				HTML:    "<p>&lt;speeding&gt;</p>",
+			},
+		},
+		{
+			name:    "Unknown locale falls back to default locale",
+			channel: "telegram",
+			locale:  "de",
+			expected: Message{
+				Text: "ru default <speeding>",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			msg, err := renderer.Render(tc.channel, tc.locale, c)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if msg != tc.expected {
+				t.Errorf("expected %+v, got %+v", tc.expected, msg)
+			}
+		})
+	}
+}
+
+func TestRendererRenderCancellation(t *testing.T) {
+	dir := t.TempDir()
+	writeTemplate(t, dir, "ru/default.txt", "ru default")
+	writeTemplate(t, dir, "ru/email.subject.txt", "ru subject")
+	writeTemplate(t, dir, "ru/cancellation.txt", "ru cancellation {{ .Fine.Reference }}")
+	writeTemplate(t, dir, "ru/cancellation_sms.txt", "ru sms cancellation")
+	writeTemplate(t, dir, "ru/cancellation_email.subject.txt", "ru cancellation subject")
+
+	renderer, err := NewRenderer(dir, "ru")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c := SampleCancellation()
+
+	testCases := []struct {
+		name     string
+		channel  string
+		expected Message
+	}{
+		{
+			name:    "Cancellation template of channel",
+			channel: "sms",
+			expected: Message{
+				Text: "ru sms cancellation",
+			},
+		},
+		{
+			name:    "Cancellation template without channel template, fine templates are not used",
+			channel: "email",
+			expected: Message{
+				Subject: "ru cancellation subject",
+				Text:    "ru cancellation " + c.Fine.Reference,
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			msg, err := renderer.Render(tc.channel, "", c)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if msg != tc.expected {
+				t.Errorf("expected %+v, got %+v", tc.expected, msg)
+			}
+		})
+	}
+}
+
+func TestRendererRenderNoTemplate(t *testing.T) {
+	dir := t.TempDir()
+	writeTemplate(t, dir, "ru/email.html", "<p>{{ .Case.ID }}</p>")
+
+	renderer, err := NewRenderer(dir, "ru")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = renderer.Render("email", "", SampleCase())
+	if !errors.Is(err, ErrNoTemplate) {
+		t.Errorf("expected %v, got %v", ErrNoTemplate, err)
+	}
+}
+
+func TestRendererReload(t *testing.T) {
+	dir := t.TempDir()
+	writeTemplate(t, dir, "ru/default.txt", "first")
+
+	renderer, err := NewRenderer(dir, "ru")
+	if err != nil {
+		t.Fatal(err)
+	}
+	done := make(chan struct{})
+	defer close(done)
+	go renderer.Run(done, 10*time.Millisecond)
+
+	render := func() string {
+		msg, err := renderer.Render("sms", "", SampleCase())
+		if err != nil {
+			t.Fatal(err)
+		}
+		return msg.Text
+	}
+	waitText := func(expected string) {
+		t.Helper()
+		deadline := time.Now().Add(5 * time.Second)
+		for render() != expected {
+			if time.Now().After(deadline) {
+				t.Fatalf("expected %q, got %q", expected, render())
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	}
+
+	writeTemplate(t, dir, "ru/default.txt", "second, changed")
+	waitText("second, changed")
+
+	// Empty template is a file, which is not written yet, previous templates are used
+	writeTemplate(t, dir, "ru/default.txt", "")
+	time.Sleep(100 * time.Millisecond)
+	waitText("second, changed")
+	writeTemplate(t, dir, "ru/default.txt", "second, changed")
+
+	// Template with error is not loaded, previous templates are used
+	writeTemplate(t, dir, "ru/sms.txt", "{{ .Case.ID ")
+	time.Sleep(100 * time.Millisecond)
+	waitText("second, changed")
+
+	writeTemplate(t, dir, "ru/sms.txt", "sms {{ .Case.Violation.FineAmount }}")
+	waitText("sms 500")
+}
+
+func TestReloadEmptyTemplate(t *testing.T) {
+	dir := t.TempDir()
+	writeTemplate(t, dir, "ru/default.txt", " \n")
+
+	_, err := NewRenderer(dir, "ru")
+	if err == nil {
+		t.Error("expected error of empty template")
+	}
+}
+
+func TestRenderSource(t *testing.T) {
+	data := Data{Case: SampleCase().Case, Channel: "email", Locale: "ru"}
+
+	testCases := []struct {
+		name      string
+		kind      Kind
+		source    string
+		expected  string
+		expectErr bool
+	}{
+		{name: "Text template", kind: TextKind, source: "{{ .Channel }} {{ .Case.Violation.FineAmount }}", expected: "email 500"},
+		{name: "Html template is escaped", kind: HTMLKind, source: "<b>{{ .Case.Transport.Num }}</b>{{ \"<br>\" }}", expected: "<b>123</b>&lt;br&gt;"},
+		{name: "Syntax error", kind: TextKind, source: "{{ .Case.ID ", expectErr: true},
+		{name: "Unknown field", kind: TextKind, source: "{{ .Case.Unknown }}", expectErr: true},
+		{name: "Unknown kind", kind: "pdf", source: "text", expectErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			actual, err := RenderSource(tc.kind, tc.source, data)
+			if (err != nil) != tc.expectErr {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if actual != tc.expected {
+				t.Errorf("expected %q, got %q", tc.expected, actual)
+			}
+		})
+	}
+}
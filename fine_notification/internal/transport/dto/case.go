@@ -1,21 +1,28 @@
 package dto
 
-import "time"
+import "events"
 
-type Case struct {
-	ID             string    `json:"id,omitempty"`
-	Transport      Transport `json:"transport,omitempty"`
-	Camera         Camera    `json:"camera,omitempty"`
-	Violation      Violation `json:"violation,omitempty"`
-	ViolationValue string    `json:"violation_value,omitempty"`
-	RequiredSkill  int       `json:"required_skill,omitempty"`
-	Date           time.Time `json:"date,omitempty"`
-	IsSolved       bool      `json:"is_solved,omitempty"`
-	FineDecision   bool      `json:"fine_decision,omitempty"`
-}
+// cancelledFineStatus is status of fine, which was cancelled by service after notification about it
+const cancelledFineStatus = "cancelled"
 
+// CaseWithImage is case of fine event with resolved image, which is sent by notifiers. Fine is nil,
+// when case was solved before service issued fines. Image is nil, when it is unavailable
 type CaseWithImage struct {
-	Case           Case   `json:"case"`
-	Image          []byte `json:"image"`
-	ImageExtension string `json:"image_extension"`
+	Case           events.Case  `json:"case"`
+	Image          []byte       `json:"image"`
+	ImageExtension string       `json:"image_extension"`
+	Fine           *events.Fine `json:"fine,omitempty"`
+}
+
+// IsCancellation checks, that event is notification about cancelled fine instead of issued fine
+func (c CaseWithImage) IsCancellation() bool {
+	return c.Fine != nil && c.Fine.Status == cancelledFineStatus
+}
+
+// FineID returns id of fine or empty string for case without fine
+func (c CaseWithImage) FineID() string {
+	if c.Fine == nil {
+		return ""
+	}
+	return c.Fine.ID
 }
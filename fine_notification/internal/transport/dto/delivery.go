@@ -0,0 +1,16 @@
+package dto
+
+import "time"
+
+type Delivery struct {
+	CaseID      string     `json:"case_id"`
+	Kind        string     `json:"kind"`
+	FineID      string     `json:"fine_id,omitempty"`
+	Channel     string     `json:"channel"`
+	Status      string     `json:"status"`
+	Attempts    int        `json:"attempts"`
+	LastError   *string    `json:"last_error,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+	DeliveredAt *time.Time `json:"delivered_at,omitempty"`
+}
@@ -0,0 +1,14 @@
+package dto
+
+import "events"
+
+// TemplatePreview Template is source of template, which is rendered instead of deployed templates of channel.
+// Case is rendered instead of sample case, when it is set. Cancellation renders notification about cancelled fine
+type TemplatePreview struct {
+	Channel      string       `json:"channel"`
+	Locale       string       `json:"locale"`
+	Kind         string       `json:"kind"`
+	Template     string       `json:"template"`
+	Case         *events.Case `json:"case"`
+	Cancellation bool         `json:"cancellation"`
+}
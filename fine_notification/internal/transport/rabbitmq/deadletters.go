@@ -0,0 +1,131 @@
+package rabbitmq
+
+import (
+	"context"
+	amqp "github.com/rabbitmq/amqp091-go"
+	"time"
+)
+
+type DeadLetter struct {
+	MessageID string
+	Attempts  int
+	Error     string
+	Timestamp time.Time
+	Body      []byte
+}
+
+// DeadLetterQueue inspects dead-letter queue and moves its messages back to fine queue
+type DeadLetterQueue struct {
+	conn     *Connection
+	topology FineTopology
+}
+
+func NewDeadLetterQueue(conn *Connection, topology FineTopology) *DeadLetterQueue {
+	return &DeadLetterQueue{
+		conn:     conn,
+		topology: topology,
+	}
+}
+
+// List returns up to limit dead letters. Messages are not acknowledged, so they stay in queue
+func (q *DeadLetterQueue) List(ctx context.Context, limit int) ([]DeadLetter, error) {
+	ch, err := q.channel(ctx)
+	if err != nil {
+		return nil, err
+	}
+	// Unacknowledged messages return to queue, when channel is closed
+	defer closeChannel(ch)
+
+	var letters []DeadLetter
+	for len(letters) < limit {
+		d, ok, err := ch.Get(q.topology.DeadLetter.Queue.Name, false)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			break
+		}
+		letters = append(letters, toDeadLetter(d))
+	}
+
+	return letters, nil
+}
+
+// Requeue moves dead letters with ids or all dead letters, when ids are empty, to fine queue.
+// Attempts of requeued message are counted from zero. It returns number of requeued messages
+func (q *DeadLetterQueue) Requeue(ctx context.Context, ids []string) (int, error) {
+	ch, err := q.channel(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer closeChannel(ch)
+
+	err = ch.Confirm(false)
+	if err != nil {
+		return 0, err
+	}
+
+	requeue := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		requeue[id] = true
+	}
+
+	requeued := 0
+	for {
+		d, ok, err := ch.Get(q.topology.DeadLetter.Queue.Name, false)
+		if err != nil {
+			return requeued, err
+		}
+		if !ok {
+			return requeued, nil
+		}
+		if len(ids) > 0 && !requeue[d.MessageId] {
+			continue
+		}
+
+		// Message is published to fine queue directly, not to fanout exchange, so other queues bound
+		// to exchange do not receive it again
+		err = publishConfirmed(ctx, ch, "", q.topology.Fine.Queue.Name, amqp.Publishing{
+			ContentType:  d.ContentType,
+			DeliveryMode: amqp.Persistent,
+			MessageId:    d.MessageId,
+			Timestamp:    d.Timestamp,
+			Body:         d.Body,
+		})
+		if err != nil {
+			return requeued, err
+		}
+		err = d.Ack(false)
+		if err != nil {
+			return requeued, err
+		}
+		requeued++
+	}
+}
+
+func (q *DeadLetterQueue) channel(ctx context.Context) (*amqp.Channel, error) {
+	ch, err := q.conn.Channel(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	err = q.topology.Declare(ch)
+	if err != nil {
+		closeChannel(ch)
+		return nil, err
+	}
+
+	return ch, nil
+}
+
+func toDeadLetter(d amqp.Delivery) DeadLetter {
+	lastError, _ := d.Headers[errorHeader].(string)
+
+	return DeadLetter{
+		MessageID: d.MessageId,
+		Attempts:  attempts(d.Headers),
+		Error:     lastError,
+		Timestamp: d.Timestamp,
+		Body:      d.Body,
+	}
+}
@@ -1,87 +1,117 @@
 package rabbitmq
 
 import (
+	"context"
 	"encoding/json"
-	"fine_notification/internal/mailer"
+	"errors"
+	"events"
+	"fine_notification/internal/config"
+	"fine_notification/internal/images"
+	"fine_notification/internal/notifier"
 	"fine_notification/internal/transport/dto"
+	"fmt"
 	amqp "github.com/rabbitmq/amqp091-go"
 	"log"
+	"slices"
+	"strconv"
+	"time"
 )
 
-type FineConsumer struct {
-	amqpChan *amqp.Channel
-	mailer   *mailer.Mailer
+const (
+	// resumeDelay is delay before consuming is resumed after channel is closed
+	resumeDelay = time.Second
+	// prefetchCount limits unacknowledged messages, which broker delivers to consumer
+	prefetchCount = 10
+)
+
+// errUnknownVersion is returned, when fine event has version, which is not supported by consumer
+var errUnknownVersion = errors.New("unknown version of fine event")
+
+type FineSender interface {
+	SendFineNotification(ctx context.Context, c dto.CaseWithImage) error
 }
 
-func NewFineConsumer(
-	mqConn *amqp.Connection,
-	mailer *mailer.Mailer,
-) (*FineConsumer, error) {
+// ImageFetcher gets image of fine event by reference. It returns images.ErrUnavailable, when
+// image will not be given by reference in next attempts
+type ImageFetcher interface {
+	Fetch(ctx context.Context, ref events.ImageRef) ([]byte, error)
+}
 
-	amqpChan, err := mqConn.Channel()
-	if err != nil {
-		return nil, err
-	}
+// FineConsumer consumes fine notifications with manual acknowledgement. Message is acknowledged after
+// notification is sent. Failed message is moved to retry queue, it returns to fine queue after retry delay.
+// After max attempts, when message can not be decoded or person has no contacts, it is moved to dead-letter queue.
+// When channel or connection is closed, consuming is resumed on new channel with declared topology
+type FineConsumer struct {
+	conn     *Connection
+	topology FineTopology
+	sender   FineSender
+	images   ImageFetcher
+	retry    config.RetryConfig
+}
 
+func NewFineConsumer(
+	conn *Connection,
+	topology FineTopology,
+	sender FineSender,
+	images ImageFetcher,
+	retry config.RetryConfig,
+) *FineConsumer {
 	return &FineConsumer{
-		amqpChan: amqpChan,
-		mailer:   mailer,
-	}, nil
+		conn:     conn,
+		topology: topology,
+		sender:   sender,
+		images:   images,
+		retry:    retry,
+	}
 }
 
-func (p *FineConsumer) SetupExchangeAndQueue(
-	exchangeParams ExchangeParams,
-	queueParams QueueParams,
-	bindingsParams BindingParams,
-) error {
-	err := p.amqpChan.ExchangeDeclare(
-		exchangeParams.Name,
-		exchangeParams.Kind,
-		exchangeParams.Durable,
-		exchangeParams.AutoDelete,
-		exchangeParams.Internal,
-		exchangeParams.NoWait,
-		exchangeParams.Args,
-	)
+// StartConsume consumes messages until done is closed
+func (p *FineConsumer) StartConsume(done <-chan struct{}, params ConsumeParams) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		<-done
+		cancel()
+	}()
+
+	for {
+		err := p.consume(ctx, params)
+		if ctx.Err() != nil {
+			return
+		}
+		log.Printf("FineConsumer: %v. Resume in %s\n", err, resumeDelay)
+
+		select {
+		case <-time.After(resumeDelay):
+		case <-done:
+			return
+		}
+	}
+}
 
+// consume opens channel and handles messages, until channel is closed or ctx is done.
+// Unacknowledged messages are delivered again, when channel is closed
+func (p *FineConsumer) consume(ctx context.Context, params ConsumeParams) error {
+	ch, err := p.conn.Channel(ctx)
 	if err != nil {
 		return err
 	}
+	defer closeChannel(ch)
 
-	_, err = p.amqpChan.QueueDeclare(
-		queueParams.Name,
-		queueParams.Durable,
-		queueParams.AutoDelete,
-		queueParams.Exclusive,
-		queueParams.NoWait,
-		queueParams.Args,
-	)
+	err = p.topology.Declare(ch)
 	if err != nil {
 		return err
 	}
-
-	err = p.amqpChan.QueueBind(
-		bindingsParams.Queue,
-		bindingsParams.Key,
-		bindingsParams.Exchange,
-		bindingsParams.NoWait,
-		bindingsParams.Args,
-	)
+	err = ch.Qos(prefetchCount, 0, false)
 	if err != nil {
 		return err
 	}
-
-	return nil
-}
-
-func (p *FineConsumer) CloseChan() {
-	if err := p.amqpChan.Close(); err != nil {
-		log.Printf("EmailsPublisher CloseChan: %v\n", err)
+	err = ch.Confirm(false)
+	if err != nil {
+		return err
 	}
-}
 
-func (p *FineConsumer) StartConsume(params ConsumeParams, from string, subject string) error {
-	msgs, err := p.amqpChan.Consume(
+	msgs, err := ch.Consume(
 		params.Queue,     // queue
 		params.Consumer,  // consumer
 		params.AutoAck,   // auto-ack
@@ -90,36 +120,186 @@ func (p *FineConsumer) StartConsume(params ConsumeParams, from string, subject s
 		params.NoWait,    // no-wait
 		params.Args,      // args
 	)
-
 	if err != nil {
 		return err
 	}
+	log.Printf("FineConsumer consumes %s\n", params.Queue)
 
-	var forever chan struct{}
-
-	go func() {
-		for d := range msgs {
-			var cDto dto.CaseWithImage
-			err = json.Unmarshal(d.Body, &cDto)
-			if err != nil {
-				log.Println(err)
-				continue
-			}
-			log.Println(cDto.Case)
-			log.Println(cDto.ImageExtension)
-			email := mailer.Email{
-				From:    from,
-				To:      cDto.Case.Transport.Person.Email,
-				Subject: subject,
+	for {
+		select {
+		case d, ok := <-msgs:
+			if !ok {
+				return amqp.ErrClosed
 			}
-			log.Println(cDto.ImageExtension)
-			err = p.mailer.SendFineNotification(email, cDto)
+			err = p.handle(ctx, ch, d)
 			if err != nil {
-				log.Println(err)
+				return err
 			}
+		case <-ctx.Done():
+			return ctx.Err()
 		}
-	}()
+	}
+}
+
+// handle sends notification and acknowledges message. Error is returned only when message
+// could not be acknowledged or forwarded, then message is delivered again on new channel
+func (p *FineConsumer) handle(ctx context.Context, ch *amqp.Channel, d amqp.Delivery) error {
+	event, err := decodeEvent(d.Body)
+	if err != nil {
+		log.Printf("FineConsumer decode message %s: %v\n", d.MessageId, err)
+		// Message can not be sent in next attempts, so it is not retried
+		return p.forward(ctx, ch, d, p.topology.DeadLetter.Exchange.Name, attempts(d.Headers)+1, err)
+	}
+
+	cDto, err := p.resolveImage(ctx, event)
+	if err == nil {
+		err = p.sender.SendFineNotification(ctx, cDto)
+	}
+	if err == nil {
+		return d.Ack(false)
+	}
+
+	failed := attempts(d.Headers) + 1
+	exchange := p.failureExchange(failed)
+	if errors.Is(err, notifier.ErrNoContact) {
+		exchange = p.topology.DeadLetter.Exchange.Name
+	}
+	log.Printf("FineConsumer send message %s, attempt %d: %v. Move to %s\n", d.MessageId, failed, err, exchange)
+
+	return p.forward(ctx, ch, d, exchange, failed, err)
+}
 
-	<-forever
-	return err
+// failureExchange returns exchange for message, which has failed attempts
+func (p *FineConsumer) failureExchange(failed int) string {
+	if failed >= p.retry.MaxAttempts {
+		return p.topology.DeadLetter.Exchange.Name
+	}
+	return p.topology.Retry.Exchange.Name
+}
+
+// forward publishes copy of message with number of failed attempts and last error, then acknowledges
+// message. Copy in retry exchange expires after retry delay
+func (p *FineConsumer) forward(
+	ctx context.Context,
+	ch *amqp.Channel,
+	d amqp.Delivery,
+	exchange string,
+	failed int,
+	cause error,
+) error {
+	msg := amqp.Publishing{
+		Headers:      amqp.Table{attemptsHeader: int32(failed), errorHeader: cause.Error()},
+		ContentType:  d.ContentType,
+		DeliveryMode: amqp.Persistent,
+		MessageId:    d.MessageId,
+		Timestamp:    d.Timestamp,
+		Body:         d.Body,
+	}
+	if exchange == p.topology.Retry.Exchange.Name {
+		msg.Expiration = strconv.FormatInt(p.retry.Delay.Milliseconds(), 10)
+	}
+
+	err := publishConfirmed(ctx, ch, exchange, "", msg)
+	if err != nil {
+		return err
+	}
+
+	return d.Ack(false)
+}
+
+// publishConfirmed publishes message on channel in confirm mode and waits for acknowledgement of broker
+func publishConfirmed(ctx context.Context, ch *amqp.Channel, exchange string, key string, msg amqp.Publishing) error {
+	confirmation, err := ch.PublishWithDeferredConfirmWithContext(ctx, exchange, key, false, false, msg)
+	if err != nil {
+		return err
+	}
+
+	acked, err := confirmation.WaitContext(ctx)
+	if err != nil {
+		return err
+	}
+	if !acked {
+		return fmt.Errorf("message %s is not confirmed by broker", msg.MessageId)
+	}
+
+	return nil
+}
+
+// resolveImage returns case of event with image. Image of version 2 is fetched from service, notification
+// is sent without image, when it is unavailable, so expired url does not block notification
+func (p *FineConsumer) resolveImage(ctx context.Context, event events.FineEvent) (dto.CaseWithImage, error) {
+	cDto := dto.CaseWithImage{Case: event.Case, Fine: event.Fine}
+	if event.SchemaVersion == events.FineEventV1 {
+		cDto.Image = event.Image
+		cDto.ImageExtension = event.ImageExtension
+		return cDto, nil
+	}
+	if event.ImageRef == nil {
+		return cDto, nil
+	}
+
+	img, err := p.images.Fetch(ctx, *event.ImageRef)
+	if errors.Is(err, images.ErrUnavailable) {
+		log.Printf("FineConsumer image of case %s: %v. Send without image\n", event.Case.ID, err)
+		return cDto, nil
+	}
+	if err != nil {
+		return dto.CaseWithImage{}, err
+	}
+
+	cDto.Image = img
+	cDto.ImageExtension = event.ImageRef.Extension
+	return cDto, nil
+}
+
+// decodeEvent decodes event without version as version 1, which was sent by earlier versions of service.
+// Version of events, which were sent before shared schema, is read from field version
+func decodeEvent(body []byte) (events.FineEvent, error) {
+	var event events.FineEvent
+	err := json.Unmarshal(body, &event)
+	if err != nil {
+		return events.FineEvent{}, err
+	}
+
+	if event.SchemaVersion == 0 {
+		var legacy struct {
+			Version int `json:"version"`
+		}
+		err = json.Unmarshal(body, &legacy)
+		if err != nil {
+			return events.FineEvent{}, err
+		}
+		event.SchemaVersion = legacy.Version
+	}
+	if event.SchemaVersion == 0 {
+		event.SchemaVersion = events.FineEventV1
+	}
+	if !slices.Contains(events.FineEventVersions, event.SchemaVersion) {
+		return events.FineEvent{}, fmt.Errorf("%w: %d", errUnknownVersion, event.SchemaVersion)
+	}
+
+	return event, nil
+}
+
+// attempts returns number of failed attempts from message headers
+func attempts(headers amqp.Table) int {
+	switch v := headers[attemptsHeader].(type) {
+	case int32:
+		return int(v)
+	case int64:
+		return int(v)
+	case int:
+		return v
+	default:
+		return 0
+	}
+}
+
+func closeChannel(ch *amqp.Channel) {
+	if ch.IsClosed() {
+		return
+	}
+	if err := ch.Close(); err != nil {
+		log.Printf("Close channel: %v\n", err)
+	}
 }
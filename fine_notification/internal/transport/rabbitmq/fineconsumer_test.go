@@ -0,0 +1,471 @@
+package rabbitmq
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"events"
+	"fine_notification/internal/config"
+	"fine_notification/internal/images"
+	"fine_notification/internal/transport/dto"
+	"fmt"
+	amqp "github.com/rabbitmq/amqp091-go"
+	"os"
+	"os/exec"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// testRabbitMQURLEnv contains url of local broker, testRabbitMQContainerEnv contains name of its docker
+// container, which is restarted by tests. Tests are skipped without them
+const (
+	testRabbitMQURLEnv       = "TEST_RABBITMQ_URL"
+	testRabbitMQContainerEnv = "TEST_RABBITMQ_CONTAINER"
+
+	reconnectTimeout = time.Minute
+)
+
+type senderFunc func(ctx context.Context, c dto.CaseWithImage) error
+
+func (f senderFunc) SendFineNotification(ctx context.Context, c dto.CaseWithImage) error {
+	return f(ctx, c)
+}
+
+type imageFetcherFunc func(ctx context.Context, ref events.ImageRef) ([]byte, error)
+
+func (f imageFetcherFunc) Fetch(ctx context.Context, ref events.ImageRef) ([]byte, error) {
+	return f(ctx, ref)
+}
+
+func testBrokerConfig(t *testing.T) (config.RabbitMQConfig, string) {
+	brokerURL, container := os.Getenv(testRabbitMQURLEnv), os.Getenv(testRabbitMQContainerEnv)
+	if brokerURL == "" || container == "" {
+		t.Skipf("%s or %s is not set", testRabbitMQURLEnv, testRabbitMQContainerEnv)
+	}
+
+	uri, err := amqp.ParseURI(brokerURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return config.RabbitMQConfig{
+		User:                uri.Username,
+		Password:            uri.Password,
+		Host:                uri.Host,
+		Port:                uri.Port,
+		ReconnectMinBackoff: 100 * time.Millisecond,
+		ReconnectMaxBackoff: time.Second,
+	}, container
+}
+
+func restartBroker(t *testing.T, container string) {
+	out, err := exec.Command("docker", "restart", container).CombinedOutput()
+	if err != nil {
+		t.Fatalf("%v: %s", err, out)
+	}
+}
+
+// publishCase publishes case with email to exchange, retrying until broker accepts it
+func publishCase(t *testing.T, cfg config.RabbitMQConfig, exchange string, email string) {
+	body, err := json.Marshal(events.FineEvent{
+		SchemaVersion: events.FineEventV2,
+		Case:          events.Case{Transport: events.Transport{Person: &events.Person{Email: email}}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	deadline := time.Now().Add(reconnectTimeout)
+	for {
+		err = publish(cfg, exchange, body)
+		if err == nil {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatal(err)
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+}
+
+func publish(cfg config.RabbitMQConfig, exchange string, body []byte) error {
+	conn, err := amqp.Dial(URL(cfg))
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	ch, err := conn.Channel()
+	if err != nil {
+		return err
+	}
+	err = ch.Confirm(false)
+	if err != nil {
+		return err
+	}
+
+	confirmation, err := ch.PublishWithDeferredConfirmWithContext(
+		context.Background(), exchange, "", true, false,
+		amqp.Publishing{ContentType: "application/json", DeliveryMode: amqp.Persistent, Body: body},
+	)
+	if err != nil {
+		return err
+	}
+	if !confirmation.Wait() {
+		return fmt.Errorf("message to %s is not confirmed", exchange)
+	}
+	return nil
+}
+
+// testFineTopology declares durable queues with unique names, so messages survive restart of broker
+func testFineTopology(t *testing.T, cfg config.RabbitMQConfig) FineTopology {
+	name := fmt.Sprintf("test_%d", time.Now().UnixNano())
+	t.Cleanup(func() {
+		deleteTopology(t, cfg, name, name+"_retry", name+"_dead_letter")
+	})
+
+	return FineTopology{
+		Fine: Topology{
+			Exchange: ExchangeParams{Name: name, Kind: amqp.ExchangeFanout, Durable: true},
+			Queue:    QueueParams{Name: name, Durable: true},
+			Binding:  BindingParams{Queue: name, Exchange: name},
+		},
+		Retry: Topology{
+			Exchange: ExchangeParams{Name: name + "_retry", Kind: amqp.ExchangeFanout, Durable: true},
+			Queue: QueueParams{Name: name + "_retry", Durable: true, Args: amqp.Table{
+				"x-dead-letter-exchange":    "",
+				"x-dead-letter-routing-key": name,
+			}},
+			Binding: BindingParams{Queue: name + "_retry", Exchange: name + "_retry"},
+		},
+		DeadLetter: Topology{
+			Exchange: ExchangeParams{Name: name + "_dead_letter", Kind: amqp.ExchangeFanout, Durable: true},
+			Queue:    QueueParams{Name: name + "_dead_letter", Durable: true},
+			Binding:  BindingParams{Queue: name + "_dead_letter", Exchange: name + "_dead_letter"},
+		},
+	}
+}
+
+// startConsumer consumes fine queue of topology until test is finished
+func startConsumer(t *testing.T, cfg config.RabbitMQConfig, topology FineTopology, sender FineSender, retry config.RetryConfig) *Connection {
+	done := make(chan struct{})
+	conn := NewConnection(cfg)
+	go conn.Run(done)
+
+	consumer := NewFineConsumer(conn, topology, sender, imageFetcherFunc(nil), retry)
+	stopped := make(chan struct{})
+	go func() {
+		consumer.StartConsume(done, ConsumeParams{Queue: topology.Fine.Queue.Name})
+		close(stopped)
+	}()
+	t.Cleanup(func() {
+		close(done)
+		<-stopped
+	})
+
+	return conn
+}
+
+func deleteTopology(t *testing.T, cfg config.RabbitMQConfig, names ...string) {
+	conn, err := amqp.Dial(URL(cfg))
+	if err != nil {
+		t.Log(err)
+		return
+	}
+	defer conn.Close()
+
+	ch, err := conn.Channel()
+	if err != nil {
+		t.Log(err)
+		return
+	}
+	for _, name := range names {
+		_, _ = ch.QueueDelete(name, false, false, false)
+		_ = ch.ExchangeDelete(name, false, false)
+	}
+}
+
+func TestFineConsumerReconnect(t *testing.T) {
+	cfg, container := testBrokerConfig(t)
+	topology := testFineTopology(t, cfg)
+
+	emails := make(chan string, 2)
+	sender := senderFunc(func(ctx context.Context, c dto.CaseWithImage) error {
+		emails <- c.Case.Transport.Person.Email
+		return nil
+	})
+	startConsumer(t, cfg, topology, sender, config.RetryConfig{MaxAttempts: 1, Delay: time.Second})
+
+	// Exchange is declared by consumer, so first message is published, when consumer has started
+	publishCase(t, cfg, topology.Fine.Exchange.Name, "before@example.com")
+	expectEmail(t, emails, "before@example.com")
+
+	restartBroker(t, container)
+
+	publishCase(t, cfg, topology.Fine.Exchange.Name, "after@example.com")
+	expectEmail(t, emails, "after@example.com")
+}
+
+func TestFineConsumerDeadLetter(t *testing.T) {
+	cfg, _ := testBrokerConfig(t)
+	topology := testFineTopology(t, cfg)
+	retry := config.RetryConfig{MaxAttempts: 3, Delay: 100 * time.Millisecond}
+
+	var failing atomic.Bool
+	failing.Store(true)
+	var sendAttempts atomic.Int32
+	emails := make(chan string, 1)
+	sender := senderFunc(func(ctx context.Context, c dto.CaseWithImage) error {
+		sendAttempts.Add(1)
+		if failing.Load() {
+			return errors.New("smtp is unavailable")
+		}
+		emails <- c.Case.Transport.Person.Email
+		return nil
+	})
+	conn := startConsumer(t, cfg, topology, sender, retry)
+
+	publishCase(t, cfg, topology.Fine.Exchange.Name, "dead@example.com")
+
+	// Message is moved to dead-letter queue after all attempts
+	deadLetters := NewDeadLetterQueue(conn, topology)
+	var letters []DeadLetter
+	deadline := time.Now().Add(reconnectTimeout)
+	for len(letters) == 0 && time.Now().Before(deadline) {
+		time.Sleep(100 * time.Millisecond)
+		var err error
+		letters, err = deadLetters.List(context.Background(), 10)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(letters) != 1 {
+		t.Fatalf("expected 1 dead letter, got %d", len(letters))
+	}
+	if letters[0].Attempts != retry.MaxAttempts || letters[0].Error != "smtp is unavailable" {
+		t.Fatalf("unexpected dead letter: %+v", letters[0])
+	}
+	if actual := sendAttempts.Load(); actual != int32(retry.MaxAttempts) {
+		t.Fatalf("expected %d attempts, got %d", retry.MaxAttempts, actual)
+	}
+
+	failing.Store(false)
+	requeued, err := deadLetters.Requeue(context.Background(), []string{letters[0].MessageID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if requeued != 1 {
+		t.Fatalf("expected 1 requeued message, got %d", requeued)
+	}
+	expectEmail(t, emails, "dead@example.com")
+
+	letters, err = deadLetters.List(context.Background(), 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(letters) != 0 {
+		t.Fatalf("expected empty dead-letter queue, got %d", len(letters))
+	}
+}
+
+func expectEmail(t *testing.T, emails <-chan string, expected string) {
+	t.Helper()
+	select {
+	case actual := <-emails:
+		if actual != expected {
+			t.Fatalf("expected email to %s, got %s", expected, actual)
+		}
+	case <-time.After(reconnectTimeout):
+		t.Fatalf("email to %s is not sent", expected)
+	}
+}
+
+func TestFailureExchange(t *testing.T) {
+	consumer := &FineConsumer{
+		topology: FineTopology{
+			Retry:      Topology{Exchange: ExchangeParams{Name: FineRetryExchange}},
+			DeadLetter: Topology{Exchange: ExchangeParams{Name: FineDeadLetterExchange}},
+		},
+		retry: config.RetryConfig{MaxAttempts: 3},
+	}
+
+	testCases := []struct {
+		name     string
+		failed   int
+		expected string
+	}{
+		{name: "First attempt failed", failed: 1, expected: FineRetryExchange},
+		{name: "Attempt before last failed", failed: 2, expected: FineRetryExchange},
+		{name: "Last attempt failed", failed: 3, expected: FineDeadLetterExchange},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if actual := consumer.failureExchange(tc.failed); actual != tc.expected {
+				t.Errorf("expected %s, got %s", tc.expected, actual)
+			}
+		})
+	}
+}
+
+func TestAttempts(t *testing.T) {
+	testCases := []struct {
+		name     string
+		headers  amqp.Table
+		expected int
+	}{
+		{name: "No headers", headers: nil, expected: 0},
+		{name: "Attempts header of int32", headers: amqp.Table{attemptsHeader: int32(2)}, expected: 2},
+		{name: "Attempts header of int64", headers: amqp.Table{attemptsHeader: int64(3)}, expected: 3},
+		{name: "Attempts header of unexpected type", headers: amqp.Table{attemptsHeader: "2"}, expected: 0},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if actual := attempts(tc.headers); actual != tc.expected {
+				t.Errorf("expected %d, got %d", tc.expected, actual)
+			}
+		})
+	}
+}
+
+func TestDecodeEvent(t *testing.T) {
+	testCases := []struct {
+		name            string
+		body            string
+		expectedVersion int
+		expectErr       bool
+	}{
+		{
+			name:            "Event without version",
+			body:            `{"case":{"transport":{"person":{"email":"a@example.com"}}},"image":"aW1hZ2U="}`,
+			expectedVersion: events.FineEventV1,
+		},
+		{
+			name:            "Event of version 2",
+			body:            `{"version":2,"case":{},"image_ref":{"url":"http://service/img","extension":"jpeg"}}`,
+			expectedVersion: events.FineEventV2,
+		},
+		{name: "Unknown version", body: `{"schema_version":3,"case":{}}`, expectErr: true},
+		{name: "Invalid JSON", body: `{"case":`, expectErr: true},
+		{name: "Body is not object", body: `"case"`, expectErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			event, err := decodeEvent([]byte(tc.body))
+			if (err != nil) != tc.expectErr {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if event.SchemaVersion != tc.expectedVersion {
+				t.Errorf("expected version %d, got %d", tc.expectedVersion, event.SchemaVersion)
+			}
+		})
+	}
+}
+
+func TestResolveImage(t *testing.T) {
+	errService := errors.New("service is unavailable")
+	ref := &events.ImageRef{URL: "http://service/img", Extension: "jpeg"}
+
+	testCases := []struct {
+		name              string
+		event             events.FineEvent
+		fetchErr          error
+		expectedImage     string
+		expectedExtension string
+		expectedErr       error
+	}{
+		{
+			name:              "Version 1. Image is embedded",
+			event:             events.FineEvent{SchemaVersion: events.FineEventV1, Image: []byte("embedded"), ImageExtension: "png"},
+			expectedImage:     "embedded",
+			expectedExtension: "png",
+		},
+		{
+			name:              "Version 2. Image is fetched",
+			event:             events.FineEvent{SchemaVersion: events.FineEventV2, ImageRef: ref},
+			expectedImage:     "fetched",
+			expectedExtension: "jpeg",
+		},
+		{
+			name:  "Version 2. Event without image",
+			event: events.FineEvent{SchemaVersion: events.FineEventV2},
+		},
+		{
+			name:     "Version 2. Image is unavailable, case is sent without image",
+			event:    events.FineEvent{SchemaVersion: events.FineEventV2, ImageRef: ref},
+			fetchErr: fmt.Errorf("%w: status 410", images.ErrUnavailable),
+		},
+		{
+			name:        "Version 2. Service is unavailable",
+			event:       events.FineEvent{SchemaVersion: events.FineEventV2, ImageRef: ref},
+			fetchErr:    errService,
+			expectedErr: errService,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			consumer := &FineConsumer{images: imageFetcherFunc(func(_ context.Context, r events.ImageRef) ([]byte, error) {
+				if r.URL != ref.URL {
+					t.Errorf("unexpected url %s", r.URL)
+				}
+				if tc.fetchErr != nil {
+					return nil, tc.fetchErr
+				}
+				return []byte("fetched"), nil
+			})}
+
+			c, err := consumer.resolveImage(context.Background(), tc.event)
+
+			if !errors.Is(err, tc.expectedErr) || (tc.expectedErr == nil && err != nil) {
+				t.Fatalf("expected error %v, got %v", tc.expectedErr, err)
+			}
+			if string(c.Image) != tc.expectedImage || c.ImageExtension != tc.expectedExtension {
+				t.Errorf("unexpected image %s %s", c.Image, c.ImageExtension)
+			}
+		})
+	}
+}
+
+// TestFineEventContract fails, when consumer does not accept examples of every schema version of events
+func TestFineEventContract(t *testing.T) {
+	examples, err := events.FineEventExamples()
+	if err != nil {
+		t.Fatal(err)
+	}
+	consumer := &FineConsumer{images: imageFetcherFunc(func(_ context.Context, _ events.ImageRef) ([]byte, error) {
+		return []byte("fetched"), nil
+	})}
+
+	versions := make(map[int]bool)
+	for name, body := range examples {
+		t.Run(name, func(t *testing.T) {
+			event, err := decodeEvent(body)
+			if err != nil {
+				t.Fatal(err)
+			}
+			versions[event.SchemaVersion] = true
+
+			c, err := consumer.resolveImage(context.Background(), event)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if c.Case.ID == "" || c.Case.Transport.Region == "" || c.Case.Transport.Person == nil ||
+				c.Case.Violation.Name == "" || c.Case.Date.IsZero() {
+				t.Errorf("case is not decoded: %+v", c.Case)
+			}
+			if len(c.Image) == 0 || c.ImageExtension == "" {
+				t.Errorf("image is not resolved")
+			}
+		})
+	}
+
+	for _, version := range events.FineEventVersions {
+		if !versions[version] {
+			t.Errorf("version %d has no example", version)
+		}
+	}
+}
@@ -2,9 +2,23 @@ package rabbitmq
 
 import "github.com/rabbitmq/amqp091-go"
 
+// FineQueue is declared durable by service and fine_notification. Params of declarations must be
+// the same, otherwise broker closes channel of the second declaration
 const (
 	FineExchange = "fine"
 	FineQueue    = "fine_queue"
+
+	// FineRetryQueue keeps failed messages until their expiration, then they are dead-lettered back to FineQueue
+	FineRetryExchange = "fine_retry"
+	FineRetryQueue    = "fine_retry_queue"
+
+	// FineDeadLetterQueue keeps messages, which are not sent after all attempts or can not be decoded
+	FineDeadLetterExchange = "fine_dead_letter"
+	FineDeadLetterQueue    = "fine_dead_letter_queue"
+
+	// attemptsHeader contains number of failed attempts, errorHeader contains error of last attempt
+	attemptsHeader = "attempts"
+	errorHeader    = "error"
 )
 
 type QueueParams struct {
@@ -0,0 +1,59 @@
+package rest
+
+import (
+	"fmt"
+	"github.com/golang-jwt/jwt/v5"
+	"net/http"
+	"strings"
+)
+
+const (
+	authorizationHeader = "Authorization"
+	bearerPrefix        = "Bearer "
+	// directorRole is role of director in tokens of service
+	directorRole = "director"
+)
+
+// DirectorAuth checks JWT, which service has issued to director for fine_notification. Service signs
+// these tokens by separate key, which is shared with fine_notification, instead of signing key of users
+type DirectorAuth struct {
+	signingKey []byte
+}
+
+func NewDirectorAuth(signingKey string) *DirectorAuth {
+	return &DirectorAuth{signingKey: []byte(signingKey)}
+}
+
+func (a *DirectorAuth) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		role, err := a.parseRole(r.Header.Get(authorizationHeader))
+		if err != nil || role != directorRole {
+			writeMessage(w, http.StatusUnauthorized, "Unauthorized")
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (a *DirectorAuth) parseRole(header string) (string, error) {
+	accessToken, ok := strings.CutPrefix(header, bearerPrefix)
+	if !ok || accessToken == "" {
+		return "", fmt.Errorf("invalid authorization header")
+	}
+
+	token, err := jwt.Parse(accessToken, func(token *jwt.Token) (any, error) {
+		return a.signingKey, nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}))
+	if err != nil {
+		return "", err
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok {
+		return "", fmt.Errorf("error get user claims from token")
+	}
+	role, _ := claims["role"].(string)
+
+	return role, nil
+}
@@ -0,0 +1,62 @@
+package rest
+
+import (
+	"context"
+	"errors"
+	"fine_notification/internal/ledger"
+	"fine_notification/internal/transport/dto"
+	"log"
+	"net/http"
+)
+
+const caseIDKey = "case_id"
+
+type DeliveryLedger interface {
+	GetDeliveries(ctx context.Context, caseID string) ([]ledger.Delivery, error)
+}
+
+type DeliveryHandler struct {
+	ledger DeliveryLedger
+}
+
+func NewDeliveryHandler(ledger DeliveryLedger) *DeliveryHandler {
+	return &DeliveryHandler{ledger: ledger}
+}
+
+// GetDeliveries returns deliveries of notifications about fine and its cancellation by every channel, which was tried
+func (h *DeliveryHandler) GetDeliveries(w http.ResponseWriter, r *http.Request) {
+	caseID := r.URL.Query().Get(caseIDKey)
+	if caseID == "" {
+		writeMessage(w, http.StatusBadRequest, "case_id is empty")
+		return
+	}
+
+	deliveries, err := h.ledger.GetDeliveries(r.Context(), caseID)
+	if errors.Is(err, ledger.ErrNoDeliveries) {
+		writeMessage(w, http.StatusNotFound, "No deliveries of case")
+		return
+	}
+	if err != nil {
+		log.Println(err)
+		writeMessage(w, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+
+	dtos := make([]dto.Delivery, 0, len(deliveries))
+	for _, d := range deliveries {
+		dtos = append(dtos, dto.Delivery{
+			CaseID:      d.CaseID,
+			Kind:        string(d.Kind),
+			FineID:      d.FineID,
+			Channel:     d.Channel,
+			Status:      string(d.Status),
+			Attempts:    d.Attempts,
+			LastError:   d.LastError,
+			CreatedAt:   d.CreatedAt,
+			UpdatedAt:   d.UpdatedAt,
+			DeliveredAt: d.DeliveredAt,
+		})
+	}
+
+	writeJSON(w, http.StatusOK, dtos)
+}
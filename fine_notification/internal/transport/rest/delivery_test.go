@@ -0,0 +1,84 @@
+package rest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fine_notification/internal/ledger"
+	"fine_notification/internal/transport/dto"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+type deliveryLedgerFunc func(ctx context.Context, caseID string) ([]ledger.Delivery, error)
+
+func (f deliveryLedgerFunc) GetDeliveries(ctx context.Context, caseID string) ([]ledger.Delivery, error) {
+	return f(ctx, caseID)
+}
+
+func TestGetDeliveries(t *testing.T) {
+	caseID := "case_id"
+	now := time.Now()
+	lastError := "smtp is unavailable"
+	deliveries := []ledger.Delivery{
+		{CaseID: caseID, Kind: ledger.FineKind, FineID: "fine_id", Channel: "email", Status: ledger.FailedStatus,
+			Attempts: 2, LastError: &lastError, CreatedAt: now, UpdatedAt: now},
+		{CaseID: caseID, Kind: ledger.CancellationKind, FineID: "fine_id", Channel: "telegram",
+			Status: ledger.DeliveredStatus, Attempts: 1, CreatedAt: now, UpdatedAt: now, DeliveredAt: &now},
+	}
+
+	testCases := []struct {
+		name         string
+		query        string
+		ledgerErr    error
+		expectedCode int
+	}{
+		{name: "Deliveries of case. 200 OK", query: "?case_id=" + caseID, expectedCode: http.StatusOK},
+		{name: "Empty case id. 400 Bad request", query: "", expectedCode: http.StatusBadRequest},
+		{
+			name: "No deliveries. 404 Not found", query: "?case_id=" + caseID,
+			ledgerErr: ledger.ErrNoDeliveries, expectedCode: http.StatusNotFound,
+		},
+		{
+			name: "Database error. 500 Internal server error", query: "?case_id=" + caseID,
+			ledgerErr: errors.New("db error"), expectedCode: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			handler := NewDeliveryHandler(deliveryLedgerFunc(func(ctx context.Context, id string) ([]ledger.Delivery, error) {
+				if id != caseID {
+					t.Errorf("unexpected case id %s", id)
+				}
+				if tc.ledgerErr != nil {
+					return nil, tc.ledgerErr
+				}
+				return deliveries, nil
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, "/deliveries"+tc.query, nil)
+			rec := httptest.NewRecorder()
+			handler.GetDeliveries(rec, req)
+
+			if rec.Code != tc.expectedCode {
+				t.Fatalf("expected code %d, got %d", tc.expectedCode, rec.Code)
+			}
+			if tc.expectedCode != http.StatusOK {
+				return
+			}
+
+			var actual []dto.Delivery
+			if err := json.Unmarshal(rec.Body.Bytes(), &actual); err != nil {
+				t.Fatal(err)
+			}
+			if len(actual) != 2 || actual[0].Status != "failed" || *actual[0].LastError != lastError ||
+				actual[0].Kind != "fine" || actual[1].Kind != "cancellation" || actual[1].FineID != "fine_id" ||
+				actual[1].DeliveredAt == nil {
+				t.Errorf("unexpected deliveries %+v", actual)
+			}
+		})
+	}
+}
@@ -0,0 +1,51 @@
+package rest
+
+import (
+	"context"
+	"errors"
+	"fine_notification/internal/notice"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+)
+
+type NoticeStore interface {
+	Get(ctx context.Context, caseID string) (notice.Notice, error)
+}
+
+type NoticeHandler struct {
+	store NoticeStore
+}
+
+func NewNoticeHandler(store NoticeStore) *NoticeHandler {
+	return &NoticeHandler{store: store}
+}
+
+// GetNotice returns PDF notice about fine, which was issued for case
+func (h *NoticeHandler) GetNotice(w http.ResponseWriter, r *http.Request) {
+	caseID := r.URL.Query().Get(caseIDKey)
+	if caseID == "" {
+		writeMessage(w, http.StatusBadRequest, "case_id is empty")
+		return
+	}
+
+	n, err := h.store.Get(r.Context(), caseID)
+	if errors.Is(err, notice.ErrNoNotice) {
+		writeMessage(w, http.StatusNotFound, "No notice of case")
+		return
+	}
+	if err != nil {
+		log.Println(err)
+		writeMessage(w, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/pdf")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="notice_%s.pdf"`, n.Reference))
+	w.Header().Set("Content-Length", strconv.Itoa(len(n.PDF)))
+	w.WriteHeader(http.StatusOK)
+	if _, err = w.Write(n.PDF); err != nil {
+		log.Printf("Write notice: %v\n", err)
+	}
+}
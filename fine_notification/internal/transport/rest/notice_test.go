@@ -0,0 +1,73 @@
+package rest
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fine_notification/internal/notice"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type noticeStoreFunc func(ctx context.Context, caseID string) (notice.Notice, error)
+
+func (f noticeStoreFunc) Get(ctx context.Context, caseID string) (notice.Notice, error) {
+	return f(ctx, caseID)
+}
+
+func TestGetNotice(t *testing.T) {
+	caseID := "case_id"
+	stored := notice.Notice{CaseID: caseID, Reference: "18810000000000000001", PDF: []byte("%PDF-1.3")}
+
+	testCases := []struct {
+		name         string
+		query        string
+		storeErr     error
+		expectedCode int
+	}{
+		{name: "Notice of case. 200 OK", query: "?case_id=" + caseID, expectedCode: http.StatusOK},
+		{name: "Empty case id. 400 Bad request", query: "", expectedCode: http.StatusBadRequest},
+		{
+			name: "No notice. 404 Not found", query: "?case_id=" + caseID,
+			storeErr: notice.ErrNoNotice, expectedCode: http.StatusNotFound,
+		},
+		{
+			name: "Database error. 500 Internal server error", query: "?case_id=" + caseID,
+			storeErr: errors.New("db error"), expectedCode: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			handler := NewNoticeHandler(noticeStoreFunc(func(ctx context.Context, id string) (notice.Notice, error) {
+				if id != caseID {
+					t.Errorf("unexpected case id %s", id)
+				}
+				if tc.storeErr != nil {
+					return notice.Notice{}, tc.storeErr
+				}
+				return stored, nil
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, "/notices"+tc.query, nil)
+			rec := httptest.NewRecorder()
+			handler.GetNotice(rec, req)
+
+			if rec.Code != tc.expectedCode {
+				t.Fatalf("expected code %d, got %d", tc.expectedCode, rec.Code)
+			}
+			if tc.expectedCode != http.StatusOK {
+				return
+			}
+
+			if rec.Header().Get("Content-Type") != "application/pdf" || !bytes.Equal(rec.Body.Bytes(), stored.PDF) {
+				t.Errorf("unexpected notice response %v: %s", rec.Header(), rec.Body.String())
+			}
+			expectedDisposition := `attachment; filename="notice_18810000000000000001.pdf"`
+			if rec.Header().Get("Content-Disposition") != expectedDisposition {
+				t.Errorf("unexpected content disposition %s", rec.Header().Get("Content-Disposition"))
+			}
+		})
+	}
+}
@@ -0,0 +1,30 @@
+package rest
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+)
+
+type messageBody struct {
+	Message string `json:"message"`
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	body, err := json.Marshal(v)
+	if err != nil {
+		log.Printf("Marshal response body: %v\n", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if _, err = w.Write(body); err != nil {
+		log.Printf("Write response body: %v\n", err)
+	}
+}
+
+func writeMessage(w http.ResponseWriter, status int, msg string) {
+	writeJSON(w, status, messageBody{Message: msg})
+}
@@ -0,0 +1,96 @@
+package rest
+
+import (
+	"encoding/json"
+	"errors"
+	"fine_notification/internal/templates"
+	"fine_notification/internal/transport/dto"
+	"io"
+	"log"
+	"net/http"
+)
+
+const defaultPreviewChannel = "email"
+
+type Renderer interface {
+	Render(channel string, locale string, c dto.CaseWithImage) (templates.Message, error)
+}
+
+type TemplateHandler struct {
+	renderer Renderer
+}
+
+func NewTemplateHandler(renderer Renderer) *TemplateHandler {
+	return &TemplateHandler{renderer: renderer}
+}
+
+// Preview renders deployed templates of channel or template from request against sample case.
+// Response contains subject, text and html of notification
+func (h *TemplateHandler) Preview(w http.ResponseWriter, r *http.Request) {
+	var req dto.TemplatePreview
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil && !errors.Is(err, io.EOF) {
+		writeMessage(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if req.Channel == "" {
+		req.Channel = defaultPreviewChannel
+	}
+
+	c := templates.SampleCase()
+	if req.Cancellation {
+		c = templates.SampleCancellation()
+	}
+	if req.Case != nil {
+		c.Case = *req.Case
+	}
+
+	if req.Template != "" {
+		h.previewSource(w, req, c)
+		return
+	}
+
+	msg, err := h.renderer.Render(req.Channel, req.Locale, c)
+	if errors.Is(err, templates.ErrNoTemplate) {
+		writeMessage(w, http.StatusNotFound, err.Error())
+		return
+	}
+	if err != nil {
+		log.Println(err)
+		writeMessage(w, http.StatusUnprocessableEntity, err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusOK, msg)
+}
+
+func (h *TemplateHandler) previewSource(w http.ResponseWriter, req dto.TemplatePreview, c dto.CaseWithImage) {
+	kind := templates.Kind(req.Kind)
+	if kind == "" {
+		kind = templates.TextKind
+	}
+
+	rendered, err := templates.RenderSource(kind, req.Template, templates.Data{
+		Case:           c.Case,
+		Fine:           c.Fine,
+		ImageExtension: c.ImageExtension,
+		Channel:        req.Channel,
+		Locale:         req.Locale,
+	})
+	if err != nil {
+		writeMessage(w, http.StatusUnprocessableEntity, err.Error())
+		return
+	}
+
+	var msg templates.Message
+	switch kind {
+	case templates.SubjectKind:
+		msg.Subject = rendered
+	case templates.HTMLKind:
+		msg.HTML = rendered
+	default:
+		msg.Text = rendered
+	}
+
+	writeJSON(w, http.StatusOK, msg)
+}
@@ -0,0 +1,135 @@
+package rest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fine_notification/internal/templates"
+	"github.com/golang-jwt/jwt/v5"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+const testSigningKey = "signing_key"
+
+func testToken(t *testing.T, role string, key string) string {
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"exp":  time.Now().Add(time.Hour).Unix(),
+		"sub":  "user_id",
+		"role": role,
+	}).SignedString([]byte(key))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return token
+}
+
+func TestPreview(t *testing.T) {
+	renderer, err := templates.NewRenderer("../../../templates", "ru")
+	if err != nil {
+		t.Fatal(err)
+	}
+	handler := NewDirectorAuth(testSigningKey).Middleware(http.HandlerFunc(NewTemplateHandler(renderer).Preview))
+	directorToken := testToken(t, directorRole, testSigningKey)
+
+	testCases := []struct {
+		name         string
+		token        string
+		body         string
+		expectedCode int
+		check        func(t *testing.T, msg templates.Message)
+	}{
+		{
+			name:         "Deployed email templates with sample case. 200 OK",
+			token:        directorToken,
+			expectedCode: http.StatusOK,
+			check: func(t *testing.T, msg templates.Message) {
+				sample := templates.SampleCase().Case
+				if msg.Subject == "" || !strings.Contains(msg.Text, sample.Violation.Name) ||
+					!strings.Contains(msg.HTML, "<p>") {
+					t.Errorf("unexpected message %+v", msg)
+				}
+			},
+		},
+		{
+			name:         "Deployed sms template of locale with case from request. 200 OK",
+			token:        directorToken,
+			body:         `{"channel":"sms","locale":"en","case":{"violation":{"name":"Parking","fine_amount":300}}}`,
+			expectedCode: http.StatusOK,
+			check: func(t *testing.T, msg templates.Message) {
+				if !strings.Contains(msg.Text, "Parking") || !strings.Contains(msg.Text, "300") {
+					t.Errorf("unexpected message %+v", msg)
+				}
+			},
+		},
+		{
+			name:         "Deployed cancellation templates of email. 200 OK",
+			token:        directorToken,
+			body:         `{"cancellation":true}`,
+			expectedCode: http.StatusOK,
+			check: func(t *testing.T, msg templates.Message) {
+				sample := templates.SampleCancellation()
+				if !strings.Contains(msg.Subject, "отменен") || !strings.Contains(msg.Text, sample.Fine.Reference) ||
+					strings.Contains(msg.HTML, "PDF") {
+					t.Errorf("unexpected message %+v", msg)
+				}
+			},
+		},
+		{
+			name:         "Template from request. 200 OK",
+			token:        directorToken,
+			body:         `{"kind":"html","template":"<b>{{ .Case.Violation.FineAmount }}</b>"}`,
+			expectedCode: http.StatusOK,
+			check: func(t *testing.T, msg templates.Message) {
+				if msg.HTML != "<b>500</b>" {
+					t.Errorf("unexpected message %+v", msg)
+				}
+			},
+		},
+		{
+			name:         "Template from request with error. 422 Unprocessable entity",
+			token:        directorToken,
+			body:         `{"template":"{{ .Case.Unknown }}"}`,
+			expectedCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name:         "Invalid body. 400 Bad request",
+			token:        directorToken,
+			body:         `{"channel":`,
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "Expert token. 401 Unauthorized",
+			token:        testToken(t, "expert", testSigningKey),
+			expectedCode: http.StatusUnauthorized,
+		},
+		{
+			name:         "Token signed by other key. 401 Unauthorized",
+			token:        testToken(t, directorRole, "other_key"),
+			expectedCode: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/templates/preview", bytes.NewBufferString(tc.body))
+			req.Header.Set(authorizationHeader, bearerPrefix+tc.token)
+			rec := httptest.NewRecorder()
+
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tc.expectedCode {
+				t.Fatalf("expected code %d, got %d: %s", tc.expectedCode, rec.Code, rec.Body.String())
+			}
+			if tc.check != nil {
+				var msg templates.Message
+				if err := json.Unmarshal(rec.Body.Bytes(), &msg); err != nil {
+					t.Fatal(err)
+				}
+				tc.check(t, msg)
+			}
+		})
+	}
+}
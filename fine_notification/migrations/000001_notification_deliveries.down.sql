@@ -0,0 +1 @@
+DROP TABLE "notification_deliveries";
\ No newline at end of file
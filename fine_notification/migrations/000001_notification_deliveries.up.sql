@@ -0,0 +1,14 @@
+CREATE TABLE "notification_deliveries"
+(
+    "case_id"      VARCHAR(255) NOT NULL,
+    "channel"      VARCHAR(32)  NOT NULL,
+    "status"       VARCHAR(32)  NOT NULL,
+    "attempts"     INTEGER      NOT NULL DEFAULT 0,
+    "last_error"   TEXT         NULL,
+    "created_at"   TIMESTAMP    NOT NULL DEFAULT NOW(),
+    "updated_at"   TIMESTAMP    NOT NULL DEFAULT NOW(),
+    "delivered_at" TIMESTAMP    NULL
+);
+ALTER TABLE
+    "notification_deliveries"
+    ADD PRIMARY KEY ("case_id", "channel");
\ No newline at end of file
@@ -0,0 +1 @@
+DROP TABLE "notices";
\ No newline at end of file
@@ -0,0 +1,8 @@
+CREATE TABLE "notices"
+(
+    "case_id"    VARCHAR(255) NOT NULL PRIMARY KEY,
+    "reference"  VARCHAR(20)  NOT NULL,
+    "pdf"        BYTEA        NOT NULL,
+    "created_at" TIMESTAMP    NOT NULL DEFAULT NOW()
+);
+CREATE INDEX "notices_reference_index" ON "notices" ("reference");
\ No newline at end of file
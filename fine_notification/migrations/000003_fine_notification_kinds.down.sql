@@ -0,0 +1,21 @@
+DELETE FROM "notification_deliveries" WHERE "kind" <> 'fine';
+DELETE FROM "notification_deliveries" d USING "notification_deliveries" newer
+WHERE d.case_id = newer.case_id AND d.channel = newer.channel AND d.created_at < newer.created_at;
+ALTER TABLE "notification_deliveries"
+    DROP CONSTRAINT "notification_deliveries_pkey";
+ALTER TABLE "notification_deliveries"
+    DROP COLUMN "kind",
+    DROP COLUMN "fine_id";
+ALTER TABLE
+    "notification_deliveries"
+    ADD PRIMARY KEY ("case_id", "channel");
+
+DELETE FROM "notices" n USING "notices" newer
+WHERE n.case_id = newer.case_id AND n.created_at < newer.created_at;
+ALTER TABLE "notices"
+    DROP CONSTRAINT "notices_pkey";
+ALTER TABLE "notices"
+    DROP COLUMN "fine_id";
+ALTER TABLE
+    "notices"
+    ADD PRIMARY KEY ("case_id");
\ No newline at end of file
@@ -0,0 +1,16 @@
+ALTER TABLE "notification_deliveries"
+    ADD COLUMN "kind"    VARCHAR(32)  NOT NULL DEFAULT 'fine',
+    ADD COLUMN "fine_id" VARCHAR(255) NOT NULL DEFAULT '';
+ALTER TABLE "notification_deliveries"
+    DROP CONSTRAINT "notification_deliveries_pkey";
+ALTER TABLE
+    "notification_deliveries"
+    ADD PRIMARY KEY ("case_id", "kind", "fine_id", "channel");
+
+ALTER TABLE "notices"
+    ADD COLUMN "fine_id" VARCHAR(255) NOT NULL DEFAULT '';
+ALTER TABLE "notices"
+    DROP CONSTRAINT "notices_pkey";
+ALTER TABLE
+    "notices"
+    ADD PRIMARY KEY ("case_id", "fine_id");
\ No newline at end of file
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"strings"
+)
+
+const (
+	signInPath    = "/auth/sign_in"
+	casePath      = "/case"
+	caseImgPath   = "/case/%s/img"
+	caseImageKey  = "image"
+	bearerPrefix  = "Bearer "
+	jsonMediaType = "application/json"
+	binMediaType  = "application/octet-stream"
+)
+
+// client sends requests to service on behalf of camera
+type client struct {
+	addr        string
+	httpClient  *http.Client
+	accessToken string
+}
+
+func newClient(addr string) *client {
+	return &client{
+		addr:       strings.TrimSuffix(addr, "/"),
+		httpClient: &http.Client{},
+	}
+}
+
+func (c *client) signIn(ctx context.Context, username, password string) error {
+	body, err := json.Marshal(map[string]string{
+		"username": username,
+		"password": password,
+	})
+	if err != nil {
+		return err
+	}
+
+	var out struct {
+		AccessToken string `json:"accessToken"`
+	}
+	err = c.do(ctx, signInPath, jsonMediaType, bytes.NewReader(body), &out)
+	if err != nil {
+		return fmt.Errorf("sign in: %w", err)
+	}
+
+	c.accessToken = out.AccessToken
+	return nil
+}
+
+// addCase sends binary payload and returns id of created case
+func (c *client) addCase(ctx context.Context, payload []byte) (string, error) {
+	var out struct {
+		ID string `json:"id"`
+	}
+	err := c.do(ctx, casePath, binMediaType, bytes.NewReader(payload), &out)
+	if err != nil {
+		return "", fmt.Errorf("add case: %w", err)
+	}
+
+	return out.ID, nil
+}
+
+func (c *client) uploadCaseImg(ctx context.Context, caseID string, img []byte, contentType string) error {
+	var body bytes.Buffer
+	w := multipart.NewWriter(&body)
+
+	header := make(textproto.MIMEHeader)
+	header.Set("Content-Disposition", fmt.Sprintf(`form-data; name="%s"; filename="%s"`, caseImageKey, caseID))
+	header.Set("Content-Type", contentType)
+	part, err := w.CreatePart(header)
+	if err != nil {
+		return err
+	}
+	if _, err = part.Write(img); err != nil {
+		return err
+	}
+	if err = w.Close(); err != nil {
+		return err
+	}
+
+	err = c.do(ctx, fmt.Sprintf(caseImgPath, caseID), w.FormDataContentType(), &body, nil)
+	if err != nil {
+		return fmt.Errorf("upload image of case %s: %w", caseID, err)
+	}
+	return nil
+}
+
+func (c *client) do(ctx context.Context, path, contentType string, body io.Reader, out any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.addr+path, body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", contentType)
+	if c.accessToken != "" {
+		req.Header.Set("Authorization", bearerPrefix+c.accessToken)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s: %s", resp.Status, strings.TrimSpace(string(respBody)))
+	}
+
+	if out == nil {
+		return nil
+	}
+	return json.Unmarshal(respBody, out)
+}
@@ -0,0 +1,133 @@
+package main
+
+import (
+	"TrafficPolice/pkg/camerapayload"
+	"fmt"
+	"math/rand/v2"
+	"strings"
+	"time"
+)
+
+const (
+	typeCamerus1 = "camerus1"
+	typeCamerus2 = "camerus2"
+	typeCamerus3 = "camerus3"
+
+	minSpeed = 60
+	maxSpeed = 160
+)
+
+type transport struct {
+	chars  string
+	num    string
+	region string
+}
+
+// simCase is generated case, which is encoded in format of camera type
+type simCase struct {
+	transport      transport
+	cameraID       string
+	violationID    string
+	violationValue string
+	skill          int64
+	date           time.Time
+}
+
+type encodeFunc func(c simCase) ([]byte, error)
+
+var encoders = map[string]encodeFunc{
+	typeCamerus1: encodeCamerus1,
+	typeCamerus2: encodeCamerus2,
+	typeCamerus3: encodeCamerus3,
+}
+
+type generator struct {
+	cameraID   string
+	violations []string
+	transports []transport
+	maxSkill   int64
+}
+
+func (g *generator) next() simCase {
+	return simCase{
+		transport:      g.transports[rand.IntN(len(g.transports))],
+		cameraID:       g.cameraID,
+		violationID:    g.violations[rand.IntN(len(g.violations))],
+		violationValue: fmt.Sprintf("%dkm/h", minSpeed+rand.IntN(maxSpeed-minSpeed+1)),
+		skill:          1 + rand.Int64N(g.maxSkill),
+		date:           time.Now().Truncate(time.Second),
+	}
+}
+
+// parseTransports parses comma separated list of transports in format chars:num:region
+func parseTransports(s string) ([]transport, error) {
+	transports := make([]transport, 0)
+	for _, item := range strings.Split(s, ",") {
+		parts := strings.Split(strings.TrimSpace(item), ":")
+		if len(parts) != 3 || parts[0] == "" || parts[1] == "" || parts[2] == "" {
+			return nil, fmt.Errorf("transport %q is not in format chars:num:region", item)
+		}
+		transports = append(transports, transport{chars: parts[0], num: parts[1], region: parts[2]})
+	}
+
+	return transports, nil
+}
+
+func encodeCamerus1(c simCase) ([]byte, error) {
+	return camerapayload.Encode(
+		camerapayload.String("transport_chars", c.transport.chars),
+		camerapayload.String("transport_numbers", c.transport.num),
+		camerapayload.String("transport_region", c.transport.region),
+		camerapayload.String("camera_id", c.cameraID),
+		camerapayload.String("violation_id", c.violationID),
+		camerapayload.String("violation_value", c.violationValue),
+		camerapayload.Int("skill_value", c.skill),
+		camerapayload.String("datetime", c.date.Format(time.RFC3339)),
+	)
+}
+
+func encodeCamerus2(c simCase) ([]byte, error) {
+	return camerapayload.Encode(
+		camerapayload.Dict("transport",
+			camerapayload.String("chars", c.transport.chars),
+			camerapayload.String("numbers", c.transport.num),
+			camerapayload.String("region", c.transport.region),
+		),
+		camerapayload.Dict("camera", camerapayload.String("id", c.cameraID)),
+		camerapayload.Dict("violation",
+			camerapayload.String("id", c.violationID),
+			camerapayload.String("value", c.violationValue),
+		),
+		camerapayload.Dict("skill", camerapayload.Int("value", c.skill)),
+		camerapayload.Dict("datetime",
+			camerapayload.Int("year", int64(c.date.Year())),
+			camerapayload.Int("month", int64(c.date.Month())),
+			camerapayload.Int("day", int64(c.date.Day())),
+			camerapayload.Int("hour", int64(c.date.Hour())),
+			camerapayload.Int("minute", int64(c.date.Minute())),
+			camerapayload.Int("seconds", int64(c.date.Second())),
+			camerapayload.String("utc_offset", c.date.Format("-07:00")),
+		),
+	)
+}
+
+// encodeCamerus3 writes transport as one string: first number digit, chars, other number digits, region
+func encodeCamerus3(c simCase) ([]byte, error) {
+	num := []rune(c.transport.num)
+	if len(num) != 3 || len([]rune(c.transport.chars)) != 3 {
+		return nil, fmt.Errorf("camerus3 supports only transports with 3 chars and 3 digits, got %s%s",
+			c.transport.chars, c.transport.num)
+	}
+	transportStr := string(num[0]) + c.transport.chars + string(num[1:]) + c.transport.region
+
+	return camerapayload.Encode(
+		camerapayload.String("transport", transportStr),
+		camerapayload.Dict("camera", camerapayload.String("id", c.cameraID)),
+		camerapayload.Dict("violation",
+			camerapayload.String("id", c.violationID),
+			camerapayload.String("value", c.violationValue),
+		),
+		camerapayload.Int("skill", c.skill),
+		camerapayload.Int("datetime", c.date.Unix()),
+	)
+}
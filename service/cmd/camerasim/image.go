@@ -0,0 +1,100 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"math/rand/v2"
+	"net/http"
+	"os"
+	"path/filepath"
+)
+
+const (
+	imgWidth  = 640
+	imgHeight = 480
+
+	plateWidth  = 160
+	plateHeight = 36
+)
+
+type caseImage struct {
+	data        []byte
+	contentType string
+}
+
+// imageSource returns image for every generated case
+type imageSource interface {
+	next() (caseImage, error)
+}
+
+// dirImages picks random png or jpeg image from directory
+type dirImages struct {
+	images []caseImage
+}
+
+func newDirImages(dir string) (*dirImages, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	images := make([]caseImage, 0)
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+
+		data, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		contentType := http.DetectContentType(data)
+		if contentType != "image/png" && contentType != "image/jpeg" {
+			continue
+		}
+		images = append(images, caseImage{data: data, contentType: contentType})
+	}
+
+	if len(images) == 0 {
+		return nil, fmt.Errorf("no png or jpeg images in %s", dir)
+	}
+	return &dirImages{images: images}, nil
+}
+
+func (d *dirImages) next() (caseImage, error) {
+	return d.images[rand.IntN(len(d.images))], nil
+}
+
+// generatedImages draws road scene with car and license plate in random colors
+type generatedImages struct{}
+
+func (generatedImages) next() (caseImage, error) {
+	img := image.NewRGBA(image.Rect(0, 0, imgWidth, imgHeight))
+
+	road := color.RGBA{R: 70, G: 70, B: 75, A: 255}
+	draw.Draw(img, img.Bounds(), &image.Uniform{C: road}, image.Point{}, draw.Src)
+
+	sky := color.RGBA{R: 140, G: 180, B: uint8(200 + rand.IntN(56)), A: 255}
+	draw.Draw(img, image.Rect(0, 0, imgWidth, imgHeight/3), &image.Uniform{C: sky}, image.Point{}, draw.Src)
+
+	carColor := color.RGBA{R: uint8(rand.IntN(256)), G: uint8(rand.IntN(256)), B: uint8(rand.IntN(256)), A: 255}
+	carX := 120 + rand.IntN(200)
+	car := image.Rect(carX, 200, carX+260, 400)
+	draw.Draw(img, car, &image.Uniform{C: carColor}, image.Point{}, draw.Src)
+
+	plateX := car.Min.X + (car.Dx()-plateWidth)/2
+	plate := image.Rect(plateX, car.Max.Y-plateHeight-12, plateX+plateWidth, car.Max.Y-12)
+	draw.Draw(img, plate, &image.Uniform{C: color.White}, image.Point{}, draw.Src)
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return caseImage{}, err
+	}
+
+	return caseImage{data: buf.Bytes(), contentType: "image/png"}, nil
+}
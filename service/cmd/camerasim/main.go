@@ -0,0 +1,135 @@
+// Command camerasim imitates registered camera: it signs in, generates cases
+// in camerus1-3 format at configured rate and uploads them with images.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+)
+
+type config struct {
+	addr       string
+	username   string
+	password   string
+	cameraID   string
+	cameraType string
+	violations string
+	transports string
+	rate       float64
+	count      int
+	maxSkill   int64
+	imagesDir  string
+}
+
+func main() {
+	var cfg config
+	flag.StringVar(&cfg.addr, "addr", "http://localhost:8080", "address of service")
+	flag.StringVar(&cfg.username, "username", "", "username of registered camera")
+	flag.StringVar(&cfg.password, "password", "", "password of registered camera")
+	flag.StringVar(&cfg.cameraID, "camera-id", "", "id of registered camera")
+	flag.StringVar(&cfg.cameraType, "camera-type", typeCamerus1, "format of payload: camerus1, camerus2 or camerus3")
+	flag.StringVar(&cfg.violations, "violations", "", "comma separated ids of imported violations")
+	flag.StringVar(&cfg.transports, "transports", "", "comma separated imported transports in format chars:num:region")
+	flag.Float64Var(&cfg.rate, "rate", 1, "cases per second")
+	flag.IntVar(&cfg.count, "count", 0, "number of cases to send, 0 means until interrupted")
+	flag.Int64Var(&cfg.maxSkill, "max-skill", 3, "max required skill of generated cases")
+	flag.StringVar(&cfg.imagesDir, "images", "", "directory with png or jpeg images, images are generated if empty")
+	flag.Parse()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if err := run(ctx, cfg); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func run(ctx context.Context, cfg config) error {
+	encode, ok := encoders[cfg.cameraType]
+	if !ok {
+		return fmt.Errorf("unknown camera type %q", cfg.cameraType)
+	}
+	if cfg.username == "" || cfg.password == "" || cfg.cameraID == "" || cfg.violations == "" || cfg.transports == "" {
+		return fmt.Errorf("username, password, camera-id, violations and transports are required")
+	}
+	if cfg.rate <= 0 || cfg.maxSkill <= 0 {
+		return fmt.Errorf("rate and max-skill must be positive")
+	}
+
+	transports, err := parseTransports(cfg.transports)
+	if err != nil {
+		return err
+	}
+	gen := &generator{
+		cameraID:   cfg.cameraID,
+		violations: strings.Split(cfg.violations, ","),
+		transports: transports,
+		maxSkill:   cfg.maxSkill,
+	}
+
+	var images imageSource = generatedImages{}
+	if cfg.imagesDir != "" {
+		images, err = newDirImages(cfg.imagesDir)
+		if err != nil {
+			return err
+		}
+	}
+
+	c := newClient(cfg.addr)
+	if err = c.signIn(ctx, cfg.username, cfg.password); err != nil {
+		return err
+	}
+
+	ticker := time.NewTicker(time.Duration(float64(time.Second) / cfg.rate))
+	defer ticker.Stop()
+
+	for sent := 0; cfg.count == 0 || sent < cfg.count; sent++ {
+		simCase := gen.next()
+		payload, err := encode(simCase)
+		if err != nil {
+			return err
+		}
+		img, err := images.next()
+		if err != nil {
+			return err
+		}
+
+		caseID, err := sendCase(ctx, c, payload, img)
+		if err != nil {
+			log.Printf("Error while sending case: %v\n", err)
+		} else {
+			log.Printf("Sent case %s: %s%s%s, violation %s, %s\n", caseID,
+				simCase.transport.chars, simCase.transport.num, simCase.transport.region,
+				simCase.violationID, simCase.violationValue)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+
+	return nil
+}
+
+func sendCase(ctx context.Context, c *client, payload []byte, img caseImage) (string, error) {
+	caseID, err := c.addCase(ctx, payload)
+	if err != nil {
+		return "", err
+	}
+
+	err = c.uploadCaseImg(ctx, caseID, img.data, img.contentType)
+	if err != nil {
+		return "", err
+	}
+
+	return caseID, nil
+}
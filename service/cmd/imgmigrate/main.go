@@ -0,0 +1,172 @@
+// Command imgmigrate moves images from old layout (cases/<id>.<ext>, experts/<id>.<ext>)
+// to content-addressed storage configured in service config.
+// Migrations of database must be applied before, so it is run after service started once.
+// Only originals are migrated, redacted variants of case images are generated by service on first request.
+package main
+
+import (
+	"TrafficPolice/internal/config"
+	"TrafficPolice/internal/domain"
+	"TrafficPolice/internal/errs"
+	"TrafficPolice/internal/repository"
+	postgres "TrafficPolice/internal/repository/postresql"
+	"TrafficPolice/internal/service"
+	"TrafficPolice/internal/storage"
+	"TrafficPolice/pkg/imagereader"
+	"context"
+	"errors"
+	"flag"
+	"github.com/google/uuid"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+type migrator struct {
+	imgService service.ImgService
+	caseRepo   repository.CaseRepo
+	reader     imagereader.ImageReader
+	remove     bool
+}
+
+func main() {
+	configPath := flag.String("config", "service_config.yaml", "path to service config")
+	casesDir := flag.String("cases", "cases", "directory with images of cases")
+	expertsDir := flag.String("experts", "experts", "directory with images of experts")
+	remove := flag.Bool("remove", false, "remove migrated files")
+	flag.Parse()
+
+	cfg, err := config.ParseConfig(*configPath)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	dbPool, err := postgres.NewPool(context.Background(), cfg.Postgres)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer dbPool.Close()
+
+	imgStorage, err := storage.New(cfg.Storage)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	imgService := service.NewImgService(
+		imgStorage, postgres.NewImageRepoPostgres(dbPool), postgres.NewUnitOfWorkPostgres(dbPool),
+	)
+	m := &migrator{
+		imgService: imgService,
+		caseRepo:   postgres.NewCaseRepoPostgres(dbPool),
+		reader:     imagereader.NewImageReader(),
+		remove:     *remove,
+	}
+
+	for ownerType, dir := range map[domain.ImageOwnerType]string{
+		domain.CaseImageOwner:   *casesDir,
+		domain.ExpertImageOwner: *expertsDir,
+	} {
+		migrated, err := m.migrateDir(ownerType, dir)
+		if err != nil {
+			log.Fatal(err)
+		}
+		log.Printf("Migrated %d images of %s from %s\n", migrated, ownerType, dir)
+	}
+}
+
+// migrateDir migrates files named <id>.<ext>. If there are several files with same id,
+// the latest modified one is migrated, because old layout could keep both after reupload
+func (m *migrator) migrateDir(ownerType domain.ImageOwnerType, dir string) (int, error) {
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		log.Printf("Directory %s does not exist, skip it\n", dir)
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	latest := make(map[string]os.FileInfo)
+	stale := make(map[string][]string)
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		name := entry.Name()
+		id := strings.TrimSuffix(name, filepath.Ext(name))
+		if uuid.Validate(id) != nil {
+			log.Printf("Skip %s: file name is not id\n", filepath.Join(dir, name))
+			continue
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			return 0, err
+		}
+		prev, ok := latest[id]
+		if !ok || info.ModTime().After(prev.ModTime()) {
+			latest[id] = info
+			if ok {
+				stale[id] = append(stale[id], prev.Name())
+			}
+		} else {
+			stale[id] = append(stale[id], name)
+		}
+	}
+
+	migrated := 0
+	for id, info := range latest {
+		filePath := filepath.Join(dir, info.Name())
+		err = m.migrateFile(domain.ImageOwner{Type: ownerType, ID: id}, filePath)
+		if err != nil {
+			log.Printf("Skip %s: %v\n", filePath, err)
+			continue
+		}
+		migrated++
+
+		for _, name := range stale[id] {
+			log.Printf("Skip %s: there is newer image with same id\n", filepath.Join(dir, name))
+			m.removeFile(filepath.Join(dir, name))
+		}
+	}
+
+	return migrated, nil
+}
+
+func (m *migrator) migrateFile(owner domain.ImageOwner, filePath string) error {
+	img, err := m.reader.Read(filePath)
+	if err != nil {
+		return err
+	}
+	if _, ok := domain.ImageExtensions[http.DetectContentType(img)]; !ok {
+		return errs.ErrInvalidImage
+	}
+
+	err = m.imgService.SaveImg(context.Background(), owner, domain.OriginalImage, img)
+	if err != nil {
+		return err
+	}
+
+	// Cases with images uploaded before has_image column must become available for experts
+	if owner.Type == domain.CaseImageOwner {
+		err = m.caseRepo.SetCaseHasImage(context.Background(), owner.ID)
+		if err != nil {
+			return err
+		}
+	}
+
+	m.removeFile(filePath)
+	return nil
+}
+
+func (m *migrator) removeFile(filePath string) {
+	if !m.remove {
+		return
+	}
+	err := os.Remove(filePath)
+	if err != nil {
+		log.Printf("Error while removing %s: %v\n", filePath, err)
+	}
+}
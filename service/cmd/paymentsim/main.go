@@ -0,0 +1,98 @@
+// Command paymentsim imitates payment provider: it sends signed notification about paid fine
+// to payment webhook of service.
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"github.com/google/uuid"
+	"io"
+	"log"
+	"net/http"
+	"time"
+)
+
+const signatureHeader = "X-Signature"
+
+type config struct {
+	addr      string
+	secret    string
+	reference string
+	amount    int
+	paymentID string
+	paidAt    string
+}
+
+type payment struct {
+	PaymentID string    `json:"payment_id"`
+	Reference string    `json:"reference"`
+	Amount    int       `json:"amount"`
+	PaidAt    time.Time `json:"paid_at"`
+}
+
+func main() {
+	var cfg config
+	flag.StringVar(&cfg.addr, "addr", "http://localhost:8080", "address of service")
+	flag.StringVar(&cfg.secret, "secret", "", "webhook secret of service (fines.webhookSecret)")
+	flag.StringVar(&cfg.reference, "reference", "", "payment reference (UIN) of fine")
+	flag.IntVar(&cfg.amount, "amount", 0, "paid amount in rubles")
+	flag.StringVar(&cfg.paymentID, "payment-id", "", "id of payment, random if empty. Repeat id to resend payment")
+	flag.StringVar(&cfg.paidAt, "paid-at", "", "time of payment in RFC 3339, now if empty")
+	flag.Parse()
+
+	if err := run(context.Background(), cfg); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func run(ctx context.Context, cfg config) error {
+	if cfg.secret == "" || cfg.reference == "" || cfg.amount <= 0 {
+		return fmt.Errorf("secret, reference and positive amount are required")
+	}
+
+	p := payment{PaymentID: cfg.paymentID, Reference: cfg.reference, Amount: cfg.amount, PaidAt: time.Now()}
+	if p.PaymentID == "" {
+		p.PaymentID = uuid.New().String()
+	}
+	if cfg.paidAt != "" {
+		paidAt, err := time.Parse(time.RFC3339, cfg.paidAt)
+		if err != nil {
+			return fmt.Errorf("parse paid-at: %w", err)
+		}
+		p.PaidAt = paidAt
+	}
+
+	body, err := json.Marshal(p)
+	if err != nil {
+		return err
+	}
+	mac := hmac.New(sha256.New, []byte(cfg.secret))
+	mac.Write(body)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, cfg.addr+"/fines/payments", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(signatureHeader, hex.EncodeToString(mac.Sum(nil)))
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	log.Printf("Payment %s of %s: %s %s\n", p.PaymentID, p.Reference, resp.Status, respBody)
+
+	return nil
+}
@@ -343,30 +343,48 @@ const docTemplate = `{
                 }
             }
         },
-        "/case": {
-            "post": {
+        "/camera/type/{id}/mapping": {
+            "put": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Принимает бинарную строку в описанном формате. Добавить проишествие может только камера",
+                "description": "Задает, из каких ключей бинарной строки берутся поля случая. Вложенные ключи указываются через точку, например transport.chars. Для поля date можно указать format: rfc3339, unix, parts или layout Go. Только директор может задать соответствие",
                 "consumes": [
-                    "application/octet-stream"
+                    "application/json"
                 ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "case"
+                    "camera"
+                ],
+                "summary": "Установка соответствия полей для вида камеры",
+                "operationId": "set-camera-type-mapping",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id вида камеры",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Соответствие полей",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.CameraTypeMapping"
+                        }
+                    }
                 ],
-                "summary": "Добавление информации о проишествии",
-                "operationId": "case-add",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/response.IDResponse"
+                            "$ref": "#/definitions/response.Body"
                         }
                     },
                     "400": {
@@ -381,6 +399,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/response.Body"
                         }
                     },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Body"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -396,39 +420,48 @@ const docTemplate = `{
                 }
             }
         },
-        "/case/{id}/img": {
-            "get": {
+        "/camera/{id}/redact_regions": {
+            "put": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Получение фотографии проишествия по id прошествия. Воспользоваться могут эксперт или директор",
+                "description": "Задает прямоугольники в пикселях (например, тротуар с прохожими), которые размываются на фотографиях камеры перед показом экспертам и отправкой в уведомлении. Области применяются к фотографиям, загруженным после изменения. Пустой список убирает размытие. Только директор может задать области",
                 "consumes": [
-                    "multipart/form-data"
+                    "application/json"
                 ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "case"
+                    "camera"
                 ],
-                "summary": "Получение фотографии проишествия",
-                "operationId": "case-image-get",
+                "summary": "Установка областей размытия камеры",
+                "operationId": "set-camera-redact-regions",
                 "parameters": [
                     {
                         "type": "string",
                         "description": "id камеры",
                         "name": "id",
-                        "in": "query",
+                        "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Области размытия",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.CameraRedactRegions"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "file"
+                            "$ref": "#/definitions/response.Body"
                         }
                     },
                     "400": {
@@ -462,15 +495,18 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
+            }
+        },
+        "/case": {
             "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Принимает фотографию и сохраняет ее по переданному id. Добавить фотографию может только камера",
+                "description": "Принимает бинарную строку в описанном формате. Добавить проишествие может только камера,\nкоторой принадлежит camera_id из бинарной строки.\nЕсли запрос в формате multipart/form-data, то бинарная строка передается в поле payload,\nа фотография проишествия в поле image. Тогда случай сохраняется только вместе с фотографией",
                 "consumes": [
+                    "application/octet-stream",
                     "multipart/form-data"
                 ],
                 "produces": [
@@ -479,29 +515,27 @@ const docTemplate = `{
                 "tags": [
                     "case"
                 ],
-                "summary": "Добавление фотографии к проишествию",
-                "operationId": "case-image-upload",
+                "summary": "Добавление информации о проишествии",
+                "operationId": "case-add",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "id проишествия",
-                        "name": "id",
-                        "in": "query",
-                        "required": true
+                        "type": "file",
+                        "description": "Бинарная строка",
+                        "name": "payload",
+                        "in": "formData"
                     },
                     {
                         "type": "file",
                         "description": "Фотография проишествия",
-                        "name": "file",
-                        "in": "formData",
-                        "required": true
+                        "name": "image",
+                        "in": "formData"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/response.Body"
+                            "$ref": "#/definitions/response.IDResponse"
                         }
                     },
                     "400": {
@@ -516,6 +550,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/response.Body"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/response.Body"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -531,39 +571,46 @@ const docTemplate = `{
                 }
             }
         },
-        "/contact_info": {
-            "post": {
+        "/case/{id}/img": {
+            "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Принимает excel файл в формате по столбикам: Буквы авто:Номера авто:Регион:Номер телефона:email:VK ID: Tg ID. Только директор может загрузить файл",
-                "consumes": [
-                    "multipart/form-data"
-                ],
+                "description": "Получение фотографии проишествия по id прошествия. Воспользоваться могут эксперт или директор.\nВозвращается обработанная фотография: без метаданных EXIF/GPS и с размытыми областями камеры.\nПараметр size: web - уменьшенная до 1280 пикселей, thumb - превью до 320 пикселей, без параметра - полный размер",
                 "produces": [
-                    "application/json"
+                    "image/jpeg"
                 ],
                 "tags": [
-                    "contact_info"
+                    "case"
                 ],
-                "summary": "Ввод информации о транспорте и его владельце",
-                "operationId": "insert-contact-info",
+                "summary": "Получение фотографии проишествия",
+                "operationId": "case-image-get",
                 "parameters": [
                     {
-                        "type": "file",
-                        "description": "Excel файл с контактной информацией",
-                        "name": "file",
-                        "in": "formData",
+                        "type": "string",
+                        "description": "id проишествия",
+                        "name": "id",
+                        "in": "path",
                         "required": true
+                    },
+                    {
+                        "enum": [
+                            "web",
+                            "thumb"
+                        ],
+                        "type": "string",
+                        "description": "Размер фотографии",
+                        "name": "size",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/response.Body"
+                            "type": "file"
                         }
                     },
                     "400": {
@@ -578,6 +625,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/response.Body"
                         }
                     },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Body"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -591,44 +644,38 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/director/analytics/expert": {
-            "get": {
+            },
+            "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Получить количество всех случаев, правильно решенных случаев, неправильно решенных случаев, неизвестных случаев и максимальное количество подряд решенных задач. Воспользоваться может только директор",
+                "description": "Принимает фотографию и сохраняет ее по переданному id. Добавить фотографию может только камера,\nкоторая создала проишествие. Тип фотографии определяется по содержимому (png или jpeg).\nПовторно загрузить фотографию можно, только если это разрешил директор",
+                "consumes": [
+                    "multipart/form-data"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "director"
+                    "case"
                 ],
-                "summary": "Получение аналитики проверяющих специалистов по промежуткам времени",
-                "operationId": "director-analytics-expert",
+                "summary": "Добавление фотографии к проишествию",
+                "operationId": "case-image-upload",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "id эксперта",
+                        "description": "id проишествия",
                         "name": "id",
                         "in": "query",
                         "required": true
                     },
                     {
-                        "type": "string",
-                        "description": "Начало промежутка времени в формате yyyy-mm-dd",
-                        "name": "start_time",
-                        "in": "query",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "Конец промежутка времени в формате yyyy-mm-dd",
-                        "name": "end_time",
-                        "in": "query",
+                        "type": "file",
+                        "description": "Фотография проишествия",
+                        "name": "file",
+                        "in": "formData",
                         "required": true
                     }
                 ],
@@ -636,15 +683,9 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/dto.AnalyticsInterval"
-                            }
+                            "$ref": "#/definitions/response.Body"
                         }
                     },
-                    "204": {
-                        "description": ""
-                    },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
@@ -657,12 +698,30 @@ const docTemplate = `{
                             "$ref": "#/definitions/response.Body"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/response.Body"
+                        }
+                    },
                     "404": {
                         "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/response.Body"
                         }
                     },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/response.Body"
+                        }
+                    },
+                    "413": {
+                        "description": "Request Entity Too Large",
+                        "schema": {
+                            "$ref": "#/definitions/response.Body"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -678,26 +737,26 @@ const docTemplate = `{
                 }
             }
         },
-        "/director/case": {
-            "get": {
+        "/case/{id}/img/allow_overwrite": {
+            "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Получение состояния для конкретного случая по его id. Воспользоваться может только директор",
+                "description": "Разрешает камере один раз заново загрузить фотографию проишествия. Воспользоваться может только директор",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "director"
+                    "case"
                 ],
-                "summary": "Получение состояния для случая",
-                "operationId": "director-case-get",
+                "summary": "Разрешение повторной загрузки фотографии проишествия",
+                "operationId": "case-image-allow-overwrite",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "id случая",
+                        "description": "id проишествия",
                         "name": "id",
                         "in": "query",
                         "required": true
@@ -707,7 +766,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/dto.CaseStatus"
+                            "$ref": "#/definitions/response.Body"
                         }
                     },
                     "400": {
@@ -743,38 +802,37 @@ const docTemplate = `{
                 }
             }
         },
-        "/director/expert_skill": {
-            "patch": {
+        "/case/{id}/img/original": {
+            "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Обновление уровня компетенций у эксперта по его id. Воспользоваться может только директор",
+                "description": "Получение загруженной камерой фотографии без обработки. Воспользоваться может только директор",
                 "produces": [
-                    "application/json"
+                    "image/png",
+                    "image/jpeg"
                 ],
                 "tags": [
-                    "director"
+                    "case"
                 ],
-                "summary": "Обновление уровня компетенций у эксперта",
-                "operationId": "director-expert-skill",
+                "summary": "Получение оригинала фотографии проишествия",
+                "operationId": "case-original-image-get",
                 "parameters": [
                     {
-                        "description": "id эксперта и его новый уровень компетенций",
-                        "name": "input",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/dto.UpdateExpertSkill"
-                        }
+                        "type": "string",
+                        "description": "id проишествия",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/response.Body"
+                            "type": "file"
                         }
                     },
                     "400": {
@@ -789,6 +847,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/response.Body"
                         }
                     },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Body"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -804,40 +868,49 @@ const docTemplate = `{
                 }
             }
         },
-        "/expert/case": {
-            "get": {
+        "/contact_info": {
+            "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Получение случая для оценки экспертом. Воспользоваться могут эксперт или директор",
+                "description": "Принимает excel файл в формате по столбикам: Буквы авто:Номера авто:Регион:Номер телефона:email:VK ID: Tg ID: Каналы уведомлений. Каналы уведомлений (email, telegram, vk, sms) перечисляются через запятую в порядке предпочтения, столбец необязателен. Только директор может загрузить файл",
+                "consumes": [
+                    "multipart/form-data"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "expert"
+                    "contact_info"
+                ],
+                "summary": "Ввод информации о транспорте и его владельце",
+                "operationId": "insert-contact-info",
+                "parameters": [
+                    {
+                        "type": "file",
+                        "description": "Excel файл с контактной информацией",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    }
                 ],
-                "summary": "Получение случая для оценки экспертом",
-                "operationId": "expert-get-case",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/dto.Case"
+                            "$ref": "#/definitions/response.Body"
                         }
                     },
-                    "204": {
-                        "description": ""
-                    },
-                    "401": {
-                        "description": "Unauthorized",
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
                             "$ref": "#/definitions/response.Body"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
                             "$ref": "#/definitions/response.Body"
                         }
@@ -857,42 +930,122 @@ const docTemplate = `{
                 }
             }
         },
-        "/expert/decision": {
-            "post": {
+        "/director/analytics/expert": {
+            "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Установка оценки случая экспертом. Воспользоваться может только эксперт",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Получить количество всех случаев, правильно решенных случаев, неправильно решенных случаев, неизвестных случаев и максимальное количество подряд решенных задач. Воспользоваться может только директор",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "expert"
+                    "director"
                 ],
-                "summary": "Оценка случая экспертом",
-                "operationId": "expert-set-decision",
+                "summary": "Получение аналитики проверяющих специалистов по промежуткам времени",
+                "operationId": "director-analytics-expert",
                 "parameters": [
                     {
-                        "description": "id случая и решение эксперта",
-                        "name": "input",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/dto.Decision"
-                        }
-                    }
-                ],
+                        "type": "string",
+                        "description": "id эксперта",
+                        "name": "id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Начало промежутка времени в формате yyyy-mm-dd",
+                        "name": "start_time",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Конец промежутка времени в формате yyyy-mm-dd",
+                        "name": "end_time",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
                 "responses": {
                     "200": {
                         "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/dto.AnalyticsInterval"
+                            }
+                        }
+                    },
+                    "204": {
+                        "description": ""
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Body"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/response.Body"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Body"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Body"
+                        }
+                    },
+                    "default": {
+                        "description": "",
                         "schema": {
                             "$ref": "#/definitions/response.Body"
                         }
+                    }
+                }
+            }
+        },
+        "/director/case": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Получение состояния для конкретного случая по его id. Воспользоваться может только директор",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "director"
+                ],
+                "summary": "Получение состояния для случая",
+                "operationId": "director-case-get",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id случая",
+                        "name": "id",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.CaseStatus"
+                        }
                     },
                     "400": {
                         "description": "Bad Request",
@@ -927,14 +1080,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/expert/training": {
+        "/director/cases/{id}/override": {
             "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Получение прошествий для тренировки. Может воспользоваться только эксперт",
+                "description": "Изменяет решение о штрафе по решенному случаю с обязательным указанием причины, изменение сохраняется\nв журнале изменений. Рейтинг экспертов пересчитывается по новому решению. При отмене штрафа неоплаченный\nштраф аннулируется и владельцу транспорта отправляется уведомление об аннулировании, при решении о штрафе\nвыставляется штраф и отправляется уведомление. Воспользоваться может только директор",
                 "consumes": [
                     "application/json"
                 ],
@@ -942,40 +1095,33 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "expert"
+                    "director"
                 ],
-                "summary": "Получение проишествий для тренировки",
-                "operationId": "expert-training",
+                "summary": "Изменение решения о штрафе по решенному случаю",
+                "operationId": "director-case-override",
                 "parameters": [
                     {
-                        "description": "Информация для фильтров по проишествиям",
+                        "type": "string",
+                        "description": "id случая",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Новое решение о штрафе и причина изменения",
                         "name": "input",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/dto.SolvedCasesParams"
+                            "$ref": "#/definitions/dto.OverrideCaseDecision"
                         }
-                    },
-                    {
-                        "type": "integer",
-                        "description": "номер страницы",
-                        "name": "page",
-                        "in": "query",
-                        "required": true
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Лимит кейсов на странице",
-                        "name": "limit",
-                        "in": "query",
-                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/dto.TrainingInfo"
+                            "$ref": "#/definitions/dto.CaseOverride"
                         }
                     },
                     "400": {
@@ -984,12 +1130,24 @@ const docTemplate = `{
                             "$ref": "#/definitions/response.Body"
                         }
                     },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/response.Body"
+                        }
+                    },
                     "404": {
                         "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/response.Body"
                         }
                     },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/response.Body"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -1005,39 +1163,48 @@ const docTemplate = `{
                 }
             }
         },
-        "/expert/{id}/img": {
-            "get": {
+        "/director/cases/{id}/reopen": {
+            "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Получение фотографии эксперта по его id. Воспользоваться могут эксперт или директор",
+                "description": "Возвращает решенный случай экспертам для повторной оценки с обязательным указанием причины, изменение\nсохраняется в журнале изменений. Оценки экспертов аннулируются, а рейтинг, полученный за них, отменяется.\nНеоплаченный штраф аннулируется, владельцу транспорта отправляется уведомление об аннулировании.\nВоспользоваться может только директор",
                 "consumes": [
-                    "multipart/form-data"
+                    "application/json"
                 ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "expert"
+                    "director"
                 ],
-                "summary": "Получение фотографии эксперта",
-                "operationId": "expert-image-get",
+                "summary": "Повторное открытие решенного случая",
+                "operationId": "director-case-reopen",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "id эксперта",
+                        "description": "id случая",
                         "name": "id",
-                        "in": "query",
+                        "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Причина повторного открытия",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.CaseCorrection"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "file"
+                            "$ref": "#/definitions/dto.CaseOverride"
                         }
                     },
                     "400": {
@@ -1058,6 +1225,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/response.Body"
                         }
                     },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/response.Body"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -1071,38 +1244,1106 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
+            }
+        },
+        "/director/cases/{id}/void_fine": {
             "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Добавление фотографии к профилю эксперта. Может воспользоваться директор или эксперт",
+                "description": "Аннулирует неоплаченный штраф, выставленный по ошибке, с обязательным указанием причины, изменение\nсохраняется в журнале изменений. Владельцу транспорта отправляется уведомление об аннулировании.\nРешение о штрафе и рейтинг экспертов не изменяются. Воспользоваться может только директор",
                 "consumes": [
-                    "multipart/form-data"
+                    "application/json"
                 ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "expert"
+                    "director"
                 ],
-                "summary": "Добавление фотографии к профилю эксперта",
-                "operationId": "expert-image-upload",
+                "summary": "Аннулирование штрафа по случаю",
+                "operationId": "director-case-void-fine",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "id эксперта",
+                        "description": "id случая",
                         "name": "id",
-                        "in": "query",
+                        "in": "path",
                         "required": true
                     },
                     {
-                        "type": "file",
-                        "description": "Фотография эксперта",
-                        "name": "file",
-                        "in": "formData",
+                        "description": "Причина аннулирования",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.CaseCorrection"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.CaseOverride"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Body"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/response.Body"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Body"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/response.Body"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Body"
+                        }
+                    },
+                    "default": {
+                        "description": "",
+                        "schema": {
+                            "$ref": "#/definitions/response.Body"
+                        }
+                    }
+                }
+            }
+        },
+        "/director/expert_skill": {
+            "patch": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Обновление уровня компетенций у эксперта по его id. Воспользоваться может только директор",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "director"
+                ],
+                "summary": "Обновление уровня компетенций у эксперта",
+                "operationId": "director-expert-skill",
+                "parameters": [
+                    {
+                        "description": "id эксперта и его новый уровень компетенций",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.UpdateExpertSkill"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/response.Body"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Body"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/response.Body"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Body"
+                        }
+                    },
+                    "default": {
+                        "description": "",
+                        "schema": {
+                            "$ref": "#/definitions/response.Body"
+                        }
+                    }
+                }
+            }
+        },
+        "/director/notification_token": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Выдает токен директора для HTTP сервера fine_notification (предпросмотр шаблонов, статус доставки,\nпостановления). Токен подписан отдельным ключом и не принимается service.\nВоспользоваться может только директор",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "director"
+                ],
+                "summary": "Получение токена для fine_notification",
+                "operationId": "director-notification-token",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.NotificationToken"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/response.Body"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Body"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Body"
+                        }
+                    },
+                    "default": {
+                        "description": "",
+                        "schema": {
+                            "$ref": "#/definitions/response.Body"
+                        }
+                    }
+                }
+            }
+        },
+        "/director/reviews": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Получение нерешенных случаев, по которым эксперты максимального уровня компетенций не достигли консенсуса,\nс количеством оценок экспертов. Случаи отсортированы по времени передачи директору.\nВоспользоваться может только директор",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "director"
+                ],
+                "summary": "Получение случаев на рассмотрении директора",
+                "operationId": "director-reviews-get",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Максимальное количество случаев. По умолчанию 100",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Количество пропущенных случаев. По умолчанию 0",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/dto.ReviewCase"
+                            }
+                        }
+                    },
+                    "204": {
+                        "description": ""
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Body"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/response.Body"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Body"
+                        }
+                    },
+                    "default": {
+                        "description": "",
+                        "schema": {
+                            "$ref": "#/definitions/response.Body"
+                        }
+                    }
+                }
+            }
+        },
+        "/director/reviews/{id}/resolve": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Решает случай на рассмотрении так же, как консенсус экспертов: обновляется рейтинг экспертов,\nа при решении о штрафе выставляется штраф и отправляется уведомление. Воспользоваться может только директор",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "director"
+                ],
+                "summary": "Решение директора по случаю на рассмотрении",
+                "operationId": "director-reviews-resolve",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id случая",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Решение о штрафе",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResolveReviewCase"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/response.Body"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Body"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/response.Body"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Body"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/response.Body"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Body"
+                        }
+                    },
+                    "default": {
+                        "description": "",
+                        "schema": {
+                            "$ref": "#/definitions/response.Body"
+                        }
+                    }
+                }
+            }
+        },
+        "/expert/case": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Получение случая для оценки экспертом. Воспользоваться могут эксперт или директор.\nСлучай закрепляется за экспертом до lease_expires_at, после этого передается другим экспертам",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "expert"
+                ],
+                "summary": "Получение случая для оценки экспертом",
+                "operationId": "expert-get-case",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Case"
+                        }
+                    },
+                    "204": {
+                        "description": ""
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/response.Body"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Body"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Body"
+                        }
+                    },
+                    "default": {
+                        "description": "",
+                        "schema": {
+                            "$ref": "#/definitions/response.Body"
+                        }
+                    }
+                }
+            }
+        },
+        "/expert/decision": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Установка оценки случая экспертом. Воспользоваться может только эксперт",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "expert"
+                ],
+                "summary": "Оценка случая экспертом",
+                "operationId": "expert-set-decision",
+                "parameters": [
+                    {
+                        "description": "id случая и решение эксперта",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.Decision"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/response.Body"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Body"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/response.Body"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Body"
+                        }
+                    },
+                    "409": {
+                        "description": "Случай не закреплен за экспертом или время на оценку истекло",
+                        "schema": {
+                            "$ref": "#/definitions/response.Body"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Body"
+                        }
+                    },
+                    "default": {
+                        "description": "",
+                        "schema": {
+                            "$ref": "#/definitions/response.Body"
+                        }
+                    }
+                }
+            }
+        },
+        "/expert/training": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Получение прошествий для тренировки. Может воспользоваться только эксперт",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "expert"
+                ],
+                "summary": "Получение проишествий для тренировки",
+                "operationId": "expert-training",
+                "parameters": [
+                    {
+                        "description": "Информация для фильтров по проишествиям",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.SolvedCasesParams"
+                        }
+                    },
+                    {
+                        "type": "integer",
+                        "description": "номер страницы",
+                        "name": "page",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Лимит кейсов на странице",
+                        "name": "limit",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.TrainingInfo"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Body"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Body"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Body"
+                        }
+                    },
+                    "default": {
+                        "description": "",
+                        "schema": {
+                            "$ref": "#/definitions/response.Body"
+                        }
+                    }
+                }
+            }
+        },
+        "/expert/{id}/img": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Получение фотографии эксперта по его id. Воспользоваться могут эксперт или директор",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "expert"
+                ],
+                "summary": "Получение фотографии эксперта",
+                "operationId": "expert-image-get",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id эксперта",
+                        "name": "id",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Body"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/response.Body"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Body"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Body"
+                        }
+                    },
+                    "default": {
+                        "description": "",
+                        "schema": {
+                            "$ref": "#/definitions/response.Body"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Добавление фотографии к профилю эксперта. Может воспользоваться директор или эксперт",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "expert"
+                ],
+                "summary": "Добавление фотографии к профилю эксперта",
+                "operationId": "expert-image-upload",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id эксперта",
+                        "name": "id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "file",
+                        "description": "Фотография эксперта",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/response.Body"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Body"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/response.Body"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Body"
+                        }
+                    },
+                    "default": {
+                        "description": "",
+                        "schema": {
+                            "$ref": "#/definitions/response.Body"
+                        }
+                    }
+                }
+            }
+        },
+        "/fines": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Получение штрафов по статусам, отсортированных по сроку оплаты. По умолчанию возвращаются\nнеоплаченные штрафы (issued и overdue). Воспользоваться может только директор",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "fines"
+                ],
+                "summary": "Получение штрафов",
+                "operationId": "fines-get",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Статусы через запятую: issued, paid, overdue, cancelled или unpaid (issued и overdue)",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Максимальное количество штрафов. По умолчанию 100",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Количество пропущенных штрафов. По умолчанию 0",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/dto.Fine"
+                            }
+                        }
+                    },
+                    "204": {
+                        "description": ""
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Body"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/response.Body"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Body"
+                        }
+                    },
+                    "default": {
+                        "description": "",
+                        "schema": {
+                            "$ref": "#/definitions/response.Body"
+                        }
+                    }
+                }
+            }
+        },
+        "/fines/payments": {
+            "post": {
+                "description": "Вызывается платежным провайдером после оплаты штрафа. Тело запроса подписывается HMAC-SHA256\nс секретом fines.webhookSecret, подпись передается в заголовке X-Signature в hex. Повторное\nуведомление о том же платеже возвращает оплаченный штраф. До окончания скидки штраф оплачивается\nсуммой со скидкой, затем полной суммой",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "fines"
+                ],
+                "summary": "Уведомление об оплате штрафа",
+                "operationId": "fines-payment",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "HMAC-SHA256 тела запроса в hex",
+                        "name": "X-Signature",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Платеж",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.FinePayment"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Fine"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Body"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/response.Body"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Body"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/response.Body"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/response.Body"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Body"
+                        }
+                    },
+                    "default": {
+                        "description": "",
+                        "schema": {
+                            "$ref": "#/definitions/response.Body"
+                        }
+                    }
+                }
+            }
+        },
+        "/fines/{id}/cancel": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Отменяет неоплаченный штраф, оплата отмененного штрафа отклоняется. Оплаченный штраф отменить нельзя.\nПричина обязательна, отмена записывается в журнал корректировок дела, нарушитель получает уведомление.\nВоспользоваться может только директор",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "fines"
+                ],
+                "summary": "Отмена штрафа",
+                "operationId": "fines-cancel",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id штрафа",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "причина отмены",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.CaseCorrection"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Fine"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Body"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/response.Body"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Body"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/response.Body"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Body"
+                        }
+                    },
+                    "default": {
+                        "description": "",
+                        "schema": {
+                            "$ref": "#/definitions/response.Body"
+                        }
+                    }
+                }
+            }
+        },
+        "/internal/case/{id}/img": {
+            "get": {
+                "description": "Получение фотографии проишествия размера web по ссылке из события о штрафе. Ссылка подписывается\nservice и действует до времени expires, авторизация не требуется. Используется fine_notification",
+                "produces": [
+                    "image/jpeg"
+                ],
+                "tags": [
+                    "case"
+                ],
+                "summary": "Получение фотографии проишествия по подписанной ссылке",
+                "operationId": "case-image-signed-get",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id проишествия",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Время окончания действия ссылки (unix)",
+                        "name": "expires",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Подпись ссылки",
+                        "name": "signature",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Body"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/response.Body"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Body"
+                        }
+                    },
+                    "410": {
+                        "description": "Gone",
+                        "schema": {
+                            "$ref": "#/definitions/response.Body"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Body"
+                        }
+                    },
+                    "default": {
+                        "description": "",
+                        "schema": {
+                            "$ref": "#/definitions/response.Body"
+                        }
+                    }
+                }
+            }
+        },
+        "/outbox": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Получение сообщений outbox (уведомлений о штрафах), которые не удалось отправить за все попытки (failed),\nили которые не отправлены дольше заданного в конфиге времени (stuck). Воспользоваться может только директор",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "outbox"
+                ],
+                "summary": "Получение неотправленных сообщений outbox",
+                "operationId": "outbox-get",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Максимальное количество сообщений. По умолчанию 100",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/dto.OutboxMessage"
+                            }
+                        }
+                    },
+                    "204": {
+                        "description": ""
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Body"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/response.Body"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Body"
+                        }
+                    },
+                    "default": {
+                        "description": "",
+                        "schema": {
+                            "$ref": "#/definitions/response.Body"
+                        }
+                    }
+                }
+            }
+        },
+        "/outbox/{id}/replay": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Сбрасывает попытки неотправленного сообщения outbox, и оно отправляется при следующем запуске relay.\nВоспользоваться может только директор",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "outbox"
+                ],
+                "summary": "Повторная отправка сообщения outbox",
+                "operationId": "outbox-replay",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id сообщения",
+                        "name": "id",
+                        "in": "path",
                         "required": true
                     }
                 ],
@@ -1125,6 +2366,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/response.Body"
                         }
                     },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Body"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -1260,6 +2507,9 @@ const docTemplate = `{
                 "date": {
                     "type": "string"
                 },
+                "expired_cnt": {
+                    "type": "integer"
+                },
                 "incorrect_cnt": {
                     "type": "integer"
                 },
@@ -1315,11 +2565,29 @@ const docTemplate = `{
                 "longitude": {
                     "type": "number"
                 },
+                "redact_regions": {
+                    "description": "RedactRegions are blurred on images of camera before they are shown to experts",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.RedactRegion"
+                    }
+                },
                 "short_desc": {
                     "type": "string"
                 }
             }
         },
+        "dto.CameraRedactRegions": {
+            "type": "object",
+            "properties": {
+                "regions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.RedactRegion"
+                    }
+                }
+            }
+        },
         "dto.CameraTypeIn": {
             "type": "object",
             "required": [
@@ -1331,6 +2599,21 @@ const docTemplate = `{
                 }
             }
         },
+        "dto.CameraTypeMapping": {
+            "type": "object",
+            "required": [
+                "fields"
+            ],
+            "properties": {
+                "fields": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "$ref": "#/definitions/dto.FieldMapping"
+                    }
+                }
+            }
+        },
         "dto.Case": {
             "type": "object",
             "properties": {
@@ -1349,6 +2632,10 @@ const docTemplate = `{
                 "is_solved": {
                     "type": "boolean"
                 },
+                "lease_expires_at": {
+                    "description": "LeaseExpiresAt is time, until which expert must solve assigned case",
+                    "type": "string"
+                },
                 "required_skill": {
                     "type": "integer"
                 },
@@ -1374,6 +2661,67 @@ const docTemplate = `{
                 },
                 "is_expert_solve": {
                     "type": "boolean"
+                },
+                "is_lease_expired": {
+                    "type": "boolean"
+                },
+                "is_voided": {
+                    "description": "IsVoided is true for decision, which was made before case was reopened by director",
+                    "type": "boolean"
+                }
+            }
+        },
+        "dto.CaseCorrection": {
+            "type": "object",
+            "properties": {
+                "reason": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.CaseEscalation": {
+            "type": "object",
+            "properties": {
+                "escalated_at": {
+                    "type": "string"
+                },
+                "from_skill": {
+                    "type": "integer"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "to_skill": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.CaseOverride": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "fine_id": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "new_fine_decision": {
+                    "type": "boolean"
+                },
+                "old_fine_decision": {
+                    "type": "boolean"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
                 }
             }
         },
@@ -1392,15 +2740,42 @@ const docTemplate = `{
                 "case_id": {
                     "type": "string"
                 },
+                "escalations": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.CaseEscalation"
+                    }
+                },
                 "fine_decision": {
                     "type": "boolean"
                 },
                 "is_solved": {
                     "type": "boolean"
                 },
+                "notifications": {
+                    "description": "Notifications are deliveries of fine notification, notifications_unavailable is true,\nwhen fine_notification did not return them",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.NotificationDelivery"
+                    }
+                },
+                "notifications_unavailable": {
+                    "type": "boolean"
+                },
+                "overrides": {
+                    "description": "Overrides are corrections of case by director",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.CaseOverride"
+                    }
+                },
                 "required_skill": {
                     "type": "integer"
                 },
+                "review_requested_at": {
+                    "description": "ReviewRequestedAt is set, when case waits for decision of director",
+                    "type": "string"
+                },
                 "violation_value": {
                     "type": "string"
                 }
@@ -1431,6 +2806,180 @@ const docTemplate = `{
                 }
             }
         },
+        "dto.FieldMapping": {
+            "type": "object",
+            "required": [
+                "case_field",
+                "payload_key"
+            ],
+            "properties": {
+                "case_field": {
+                    "type": "string",
+                    "enum": [
+                        "transport.chars",
+                        "transport.num",
+                        "transport.region",
+                        "camera.id",
+                        "violation.id",
+                        "violation_value",
+                        "required_skill",
+                        "date"
+                    ]
+                },
+                "format": {
+                    "type": "string"
+                },
+                "payload_key": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.Fine": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "integer"
+                },
+                "cancelled_at": {
+                    "type": "string"
+                },
+                "case_id": {
+                    "type": "string"
+                },
+                "discount_amount": {
+                    "type": "integer"
+                },
+                "discount_until": {
+                    "type": "string"
+                },
+                "due_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "issued_at": {
+                    "type": "string"
+                },
+                "paid_amount": {
+                    "type": "integer"
+                },
+                "paid_at": {
+                    "type": "string"
+                },
+                "payment_id": {
+                    "type": "string"
+                },
+                "reference": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.FinePayment": {
+            "type": "object",
+            "required": [
+                "paid_at",
+                "payment_id",
+                "reference"
+            ],
+            "properties": {
+                "amount": {
+                    "type": "integer"
+                },
+                "paid_at": {
+                    "type": "string"
+                },
+                "payment_id": {
+                    "type": "string"
+                },
+                "reference": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.NotificationDelivery": {
+            "type": "object",
+            "properties": {
+                "attempts": {
+                    "type": "integer"
+                },
+                "channel": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "delivered_at": {
+                    "type": "string"
+                },
+                "kind": {
+                    "type": "string"
+                },
+                "last_error": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.NotificationToken": {
+            "type": "object",
+            "properties": {
+                "token": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.OutboxMessage": {
+            "type": "object",
+            "properties": {
+                "attempts": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "failed_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "kind": {
+                    "type": "string"
+                },
+                "last_error": {
+                    "type": "string"
+                },
+                "next_attempt_at": {
+                    "type": "string"
+                },
+                "payload": {
+                    "type": "object"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.OverrideCaseDecision": {
+            "type": "object",
+            "properties": {
+                "fine_decision": {
+                    "type": "boolean"
+                },
+                "reason": {
+                    "type": "string"
+                }
+            }
+        },
         "dto.Pagination": {
             "type": "object",
             "properties": {
@@ -1460,6 +3009,13 @@ const docTemplate = `{
                 "email": {
                     "type": "string"
                 },
+                "notify_channels": {
+                    "description": "NotifyChannels are channels of fine notification in order of preference: email, telegram, vk, sms",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
                 "phone_num": {
                     "type": "string"
                 },
@@ -1491,6 +3047,31 @@ const docTemplate = `{
                 }
             }
         },
+        "dto.RedactRegion": {
+            "type": "object",
+            "required": [
+                "height",
+                "width"
+            ],
+            "properties": {
+                "height": {
+                    "type": "integer",
+                    "minimum": 1
+                },
+                "width": {
+                    "type": "integer",
+                    "minimum": 1
+                },
+                "x": {
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "y": {
+                    "type": "integer",
+                    "minimum": 0
+                }
+            }
+        },
         "dto.RegisterCamera": {
             "type": "object",
             "required": [
@@ -1506,6 +3087,40 @@ const docTemplate = `{
                 }
             }
         },
+        "dto.ResolveReviewCase": {
+            "type": "object",
+            "properties": {
+                "fine_decision": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "dto.ReviewCase": {
+            "type": "object",
+            "properties": {
+                "case_date": {
+                    "type": "string"
+                },
+                "case_id": {
+                    "type": "string"
+                },
+                "negative_decisions": {
+                    "type": "integer"
+                },
+                "positive_decisions": {
+                    "type": "integer"
+                },
+                "required_skill": {
+                    "type": "integer"
+                },
+                "review_requested_at": {
+                    "type": "string"
+                },
+                "violation_value": {
+                    "type": "string"
+                }
+            }
+        },
         "dto.SignInInput": {
             "type": "object",
             "required": [
@@ -4,29 +4,52 @@ import (
 	_ "TrafficPolice/docs"
 	"TrafficPolice/internal/config"
 	"TrafficPolice/internal/domain"
+	postgres "TrafficPolice/internal/repository/postresql"
 	"TrafficPolice/internal/service"
+	"TrafficPolice/internal/storage"
 	"TrafficPolice/internal/tokens"
 	"TrafficPolice/internal/transport/rabbitmq"
 	"TrafficPolice/internal/transport/rest/middlewares"
 	"TrafficPolice/internal/validation"
-	"TrafficPolice/pkg/imagereader"
+	"TrafficPolice/pkg/uin"
 	"context"
 	"errors"
-	"fmt"
+	"events"
 	"github.com/go-playground/validator/v10"
 	"github.com/golang-migrate/migrate"
 	_ "github.com/golang-migrate/migrate/database/postgres"
 	_ "github.com/golang-migrate/migrate/source/file"
-	"github.com/jackc/pgx/v5"
-	amqp "github.com/rabbitmq/amqp091-go"
 	"log"
 	"net/http"
+	"time"
 )
 
 const (
 	serviceConfigPath = "service_config.yaml"
 	defaultMinExperts = 3
 	serverPort        = ":8080"
+
+	defaultLeaseDuration     = 24 * time.Hour
+	defaultLeaseReapInterval = time.Minute
+
+	defaultMaxSkill = 5
+
+	defaultOutboxRelayInterval = time.Second
+	defaultOutboxBatchSize     = 50
+	defaultOutboxClaimTimeout  = time.Minute
+	defaultOutboxMaxAttempts   = 10
+	defaultOutboxMinBackoff    = time.Second
+	defaultOutboxMaxBackoff    = 10 * time.Minute
+	defaultOutboxStuckAfter    = 15 * time.Minute
+
+	defaultNotificationTimeout = 5 * time.Second
+
+	defaultFineReferencePrefix = "18810"
+	defaultFineDueIn           = 60 * 24 * time.Hour
+	defaultFineDiscountPeriod  = 20 * 24 * time.Hour
+	defaultFineOverdueInterval = time.Hour
+
+	defaultImageURLTTL = 24 * time.Hour
 )
 
 func Run() {
@@ -39,37 +62,60 @@ func Run() {
 			defaultMinExperts, cfg.Rating.MinExperts,
 		)
 	}
+	if cfg.Lease.Duration <= 0 {
+		cfg.Lease.Duration = defaultLeaseDuration
+	}
+	if cfg.Lease.ReapInterval <= 0 {
+		cfg.Lease.ReapInterval = defaultLeaseReapInterval
+	}
+	if cfg.Escalation.MaxSkill <= 0 {
+		cfg.Escalation.MaxSkill = defaultMaxSkill
+	}
+	setupOutboxDefaults(&cfg.Outbox)
+	if cfg.Notification.Timeout <= 0 {
+		cfg.Notification.Timeout = defaultNotificationTimeout
+	}
+	setupFinesDefaults(&cfg.Fines)
+	setupEventsDefaults(&cfg.Events, cfg.SigningKey)
 
 	// Init database
-	dbConnString := setupDBConnString(cfg)
-	dbConn, err := pgx.Connect(context.Background(), dbConnString)
+	dbPool, err := postgres.NewPool(context.Background(), cfg.Postgres)
 	if err != nil {
 		log.Fatal(err)
 	}
-	defer dbConn.Close(context.Background())
+	defer dbPool.Close()
 
-	runMigrations(dbConnString)
+	runMigrations(postgres.ConnString(cfg.Postgres))
 
-	// Init RabbitMQ
-	mQConn, err := rabbitmq.NewRabbitMQConn(cfg)
-	if err != nil {
-		log.Fatal(err)
-	}
-	defer mQConn.Close()
+	// Init RabbitMQ. Connection is dialed again in background every time it is lost
+	done := make(chan struct{})
+	mQConn := rabbitmq.NewConnection(cfg.RabbitMQ)
+	go mQConn.Run(done)
 
 	// Init handlers, service, repos (Clean architecture)
 	tokenManager, err := tokens.NewTokenManager(cfg.SigningKey)
 	if err != nil {
 		log.Fatal(err)
 	}
-	finePublisher := setupFinePublisher(mQConn)
+	notificationTokens := newNotificationTokens(cfg)
+	publisher := rabbitmq.NewPublisher(mQConn, fineTopology(), eventsTopology())
+	defer publisher.Close()
+	finePublisher := rabbitmq.NewFinePublisher(publisher)
+	eventPublisher := rabbitmq.NewEventPublisher(publisher)
 	validate := newValidate()
-	imageReader := imagereader.NewImageReader()
+	imgStorage, err := storage.New(cfg.Storage)
+	if err != nil {
+		log.Fatal(err)
+	}
 
 	converters := newConverters()
-	repos := newRepos(dbConn)
-	services := newServices(repos, tokenManager, cfg)
-	handlers := newHandlers(services, converters, validate, finePublisher, imageReader)
+	repos := newRepos(dbPool, cfg.Notification, notificationTokens)
+	services := newServices(repos, tokenManager, notificationTokens, imgStorage, cfg)
+	handlers := newHandlers(services, converters, validate, cfg)
+	outboxRelay := rabbitmq.NewOutboxRelay(
+		services.outbox, services.expert, services.caseService, services.fine, finePublisher, eventPublisher,
+		converters.event, handlers.signedImage, cfg.Events, cfg.Outbox.RelayInterval,
+	)
 
 	authMiddleware := middlewares.NewAuthMiddleware(tokenManager, services.expert)
 	serveMuxInit := newServeMuxInit(handlers, authMiddleware)
@@ -78,8 +124,10 @@ func Run() {
 	// Run logic
 	registerDirectors(cfg, services.auth)
 
-	done := make(chan struct{})
 	go services.rating.RunReportPeriod(done)
+	go services.expert.RunLeaseReaper(done)
+	go services.fine.RunOverdueChecker(done)
+	go outboxRelay.Run(done)
 
 	// Run Server
 	server := http.Server{
@@ -93,10 +141,100 @@ func Run() {
 		log.Println(err)
 	}
 
-	done <- struct{}{}
+	close(done)
 
 }
 
+func setupOutboxDefaults(cfg *config.OutboxConfig) {
+	if cfg.RelayInterval <= 0 {
+		cfg.RelayInterval = defaultOutboxRelayInterval
+	}
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = defaultOutboxBatchSize
+	}
+	if cfg.ClaimTimeout <= 0 {
+		cfg.ClaimTimeout = defaultOutboxClaimTimeout
+	}
+	if cfg.MaxAttempts <= 0 {
+		cfg.MaxAttempts = defaultOutboxMaxAttempts
+	}
+	if cfg.MinBackoff <= 0 {
+		cfg.MinBackoff = defaultOutboxMinBackoff
+	}
+	if cfg.MaxBackoff < cfg.MinBackoff {
+		cfg.MaxBackoff = max(defaultOutboxMaxBackoff, cfg.MinBackoff)
+	}
+	if cfg.StuckAfter <= 0 {
+		cfg.StuckAfter = defaultOutboxStuckAfter
+	}
+}
+
+func setupFinesDefaults(cfg *config.FinesConfig) {
+	if cfg.ReferencePrefix == "" {
+		cfg.ReferencePrefix = defaultFineReferencePrefix
+	}
+	err := uin.ValidatePrefix(cfg.ReferencePrefix)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if cfg.DiscountPercent < 0 || cfg.DiscountPercent >= 100 {
+		log.Fatalf("config fines discount percent must be in [0, 100), but got: %d", cfg.DiscountPercent)
+	}
+	if cfg.DueIn <= 0 {
+		cfg.DueIn = defaultFineDueIn
+	}
+	if cfg.DiscountPeriod <= 0 {
+		cfg.DiscountPeriod = defaultFineDiscountPeriod
+	}
+	if cfg.OverdueInterval <= 0 {
+		cfg.OverdueInterval = defaultFineOverdueInterval
+	}
+	if cfg.WebhookSecret == "" {
+		log.Println("Fines webhook secret is not set, payments are rejected")
+	}
+}
+
+// newNotificationTokens returns nil, when signing key of fine_notification is not set. Key of users is not
+// accepted, because fine_notification could issue tokens of director for service with it
+func newNotificationTokens(cfg *config.Config) tokens.TokenManager {
+	if cfg.Notification.SigningKey == "" {
+		return nil
+	}
+	if cfg.Notification.SigningKey == cfg.SigningKey {
+		log.Fatal("config notification signing key must differ from signing key")
+	}
+
+	notificationTokens, err := tokens.NewTokenManager(cfg.Notification.SigningKey)
+	if err != nil {
+		log.Fatal(err)
+	}
+	return notificationTokens
+}
+
+// setupEventsDefaults sets version 2 of fine events. Image url is signed by own secret, key of users is not
+// accepted, because anyone with image url secret could sign tokens of any user with it
+func setupEventsDefaults(cfg *config.EventsConfig, signingKey string) {
+	if cfg.Version == 0 {
+		cfg.Version = events.FineEventV2
+	}
+	if cfg.Version != events.FineEventV1 && cfg.Version != events.FineEventV2 {
+		log.Fatalf("config events version must be %d or %d, but got: %d", events.FineEventV1, events.FineEventV2,
+			cfg.Version)
+	}
+	if cfg.Version == events.FineEventV2 && cfg.ImageBaseURL == "" {
+		log.Fatal("config events imageBaseURL must be set for version 2 of fine events")
+	}
+	if cfg.ImageURLTTL <= 0 {
+		cfg.ImageURLTTL = defaultImageURLTTL
+	}
+	if cfg.ImageURLSecret == "" {
+		log.Fatal("config events imageURLSecret must be set")
+	}
+	if cfg.ImageURLSecret == signingKey {
+		log.Fatal("config events image url secret must differ from signing key")
+	}
+}
+
 func runMigrations(dbUrl string) {
 	log.Printf("Run migrations on %s\n", dbUrl)
 	m, err := migrate.New("file://migrations", dbUrl)
@@ -119,7 +257,7 @@ func registerDirectors(cfg *config.Config, authService service.AuthService) {
 		users[i] = domain.UserInfo{Username: d.Username, Password: d.Password}
 	}
 
-	err := authService.RegisterDirectors(users)
+	err := authService.RegisterDirectors(context.Background(), users)
 	if err != nil {
 		log.Fatal(err)
 	}
@@ -135,24 +273,9 @@ func newValidate() *validator.Validate {
 	return validate
 }
 
-func setupDBConnString(cfg *config.Config) string {
-	return fmt.Sprintf(
-		"postgresql://%s:%s@%s:%d/%s?sslmode=disable",
-		cfg.Postgres.User,
-		cfg.Postgres.Password,
-		cfg.Postgres.Host,
-		cfg.Postgres.Port,
-		cfg.Postgres.Database,
-	)
-}
-
-func setupFinePublisher(mqConn *amqp.Connection) *rabbitmq.FinePublisherRabbitMQ {
-	finePublisher, err := rabbitmq.NewFinePublisher(mqConn)
-	if err != nil {
-		log.Fatal(err)
-	}
-	err = finePublisher.SetupExchangeAndQueue(
-		rabbitmq.ExchangeParams{
+func fineTopology() rabbitmq.Topology {
+	return rabbitmq.Topology{
+		Exchange: rabbitmq.ExchangeParams{
 			Name:       rabbitmq.FineExchange,
 			Kind:       rabbitmq.Fanout,
 			Durable:    true,
@@ -160,26 +283,36 @@ func setupFinePublisher(mqConn *amqp.Connection) *rabbitmq.FinePublisherRabbitMQ
 			Internal:   false,
 			NoWait:     false,
 			Args:       nil,
-		}, rabbitmq.QueueParams{
+		},
+		Queue: rabbitmq.QueueParams{
 			Name:       rabbitmq.FineQueue,
-			Durable:    false,
+			Durable:    true,
 			AutoDelete: false,
 			Exclusive:  false,
 			NoWait:     false,
 			Args:       nil,
 		},
-		rabbitmq.BindingParams{
+		Binding: rabbitmq.BindingParams{
 			Queue:    rabbitmq.FineQueue,
 			Key:      "",
 			Exchange: rabbitmq.FineExchange,
 			NoWait:   false,
 			Args:     nil,
 		},
-	)
-
-	if err != nil {
-		log.Fatal(err)
 	}
+}
 
-	return finePublisher
+// eventsTopology declares only exchange of domain events, queues are declared by consumers
+func eventsTopology() rabbitmq.Topology {
+	return rabbitmq.Topology{
+		Exchange: rabbitmq.ExchangeParams{
+			Name:       rabbitmq.EventsExchange,
+			Kind:       rabbitmq.Topic,
+			Durable:    true,
+			AutoDelete: false,
+			Internal:   false,
+			NoWait:     false,
+			Args:       nil,
+		},
+	}
 }
@@ -12,6 +12,9 @@ type converters struct {
 	userInfo      *converter.UserInfoConverter
 	analytics     *converter.AnalyticsConverter
 	rating        *converter.RatingConverter
+	outbox        *converter.OutboxConverter
+	fine          *converter.FineConverter
+	event         *converter.EventConverter
 }
 
 func newConverters() *converters {
@@ -25,5 +28,8 @@ func newConverters() *converters {
 		userInfo:      converter.NewUserInfoConverter(),
 		analytics:     converter.NewAnalyticsConverter(),
 		rating:        converter.NewRatingConverter(),
+		outbox:        converter.NewOutboxConverter(),
+		fine:          converter.NewFineConverter(),
+		event:         converter.NewEventConverter(),
 	}
 }
@@ -2,9 +2,9 @@ package app
 
 import (
 	"TrafficPolice/internal/camera"
-	"TrafficPolice/internal/transport/rabbitmq"
+	"TrafficPolice/internal/config"
 	"TrafficPolice/internal/transport/rest"
-	"TrafficPolice/pkg/imagereader"
+	"TrafficPolice/pkg/signedurl"
 	"github.com/go-playground/validator/v10"
 )
 
@@ -18,29 +18,34 @@ type handlers struct {
 	expert      *rest.ExpertHandler
 	training    *rest.TrainingHandler
 	director    *rest.DirectorHandler
+	outbox      *rest.OutboxHandler
+	fine        *rest.FineHandler
+	signedImage *rest.SignedImageHandler
 }
 
 func newHandlers(
 	s *services,
 	c *converters,
 	validate *validator.Validate,
-	finePublisher rabbitmq.FinePublisher,
-	imageReader imagereader.ImageReader,
+	cfg *config.Config,
 ) *handlers {
-	cameraParser := camera.NewParser(s.camera)
+	cameraParser := camera.NewParser(s.camera, camera.NewDefaultRegistry())
 	return &handlers{
 		rating:      rest.NewRatingHandler(s.rating, c.rating),
 		auth:        rest.NewAuthHandler(s.auth, validate, c.userInfo, c.auth),
 		camera:      rest.NewCameraHandler(s.camera, s.auth, validate, c.camera),
-		caseHandler: rest.NewCaseHandler(s.caseService, s.img, s.camera, c.caseConverter, cameraParser),
+		caseHandler: rest.NewCaseHandler(s.caseService, s.camera, c.caseConverter, cameraParser),
 		contactInfo: rest.NewContactInfoHandler(s.contactInfo),
 		violation:   rest.NewViolationHandler(s.violation),
-		expert: rest.NewExpertHandler(
-			s.img, s.expert, s.rating, finePublisher, imageReader, c.caseConverter, c.caseDecision,
-		),
+		expert:      rest.NewExpertHandler(s.img, s.expert, c.caseConverter, c.caseDecision),
 		training: rest.NewTrainingHandler(
 			s.training, s.pagination, validate, c.caseConverter, c.pagination, c.solvedCases,
 		),
 		director: rest.NewDirectorHandler(s.director, c.caseConverter, c.analytics),
+		outbox:   rest.NewOutboxHandler(s.outbox, c.outbox),
+		fine:     rest.NewFineHandler(s.fine, validate, c.fine, cfg.Fines.WebhookSecret),
+		signedImage: rest.NewSignedImageHandler(
+			s.caseService, signedurl.New(cfg.Events.ImageURLSecret), cfg.Events.ImageBaseURL,
+		),
 	}
 }
@@ -1,9 +1,12 @@
 package app
 
 import (
+	"TrafficPolice/internal/config"
 	"TrafficPolice/internal/repository"
+	"TrafficPolice/internal/repository/notification"
 	postgres "TrafficPolice/internal/repository/postresql"
-	"github.com/jackc/pgx/v5"
+	"TrafficPolice/internal/tokens"
+	"github.com/jackc/pgx/v5/pgxpool"
 )
 
 type repos struct {
@@ -19,21 +22,36 @@ type repos struct {
 	checker     repository.CheckerRepo
 	expert      repository.ExpertRepo
 	director    repository.DirectorRepo
+	image       repository.ImageRepo
+	outbox      repository.OutboxRepo
+	fine        repository.FineRepo
+	uow         repository.UnitOfWork
+	delivery    repository.DeliveryRepo
 }
 
-func newRepos(dbConn *pgx.Conn) *repos {
-	return &repos{
-		rating:      postgres.NewRatingRepoPostgres(dbConn),
-		auth:        postgres.NewAuthRepoPostgres(dbConn),
-		pagination:  postgres.NewPaginationRepoPostgres(dbConn),
-		camera:      postgres.NewCameraRepoPostgres(dbConn),
-		transport:   postgres.NewTransportRepoPostgres(dbConn),
-		caseRepo:    postgres.NewCaseRepoPostgres(dbConn),
-		contactInfo: postgres.NewContactInfoRepoPostgres(dbConn),
-		violation:   postgres.NewViolationDBPostgres(dbConn),
-		training:    postgres.NewTrainingRepoPostgres(dbConn),
-		checker:     postgres.NewCheckerRepoPostgres(dbConn),
-		expert:      postgres.NewExpertRepoPostgres(dbConn),
-		director:    postgres.NewDirectorRepoPostgres(dbConn),
+func newRepos(dbPool *pgxpool.Pool, notificationCfg config.NotificationConfig, notificationTokens tokens.TokenManager) *repos {
+	r := &repos{
+		rating:      postgres.NewRatingRepoPostgres(dbPool),
+		auth:        postgres.NewAuthRepoPostgres(dbPool),
+		pagination:  postgres.NewPaginationRepoPostgres(dbPool),
+		camera:      postgres.NewCameraRepoPostgres(dbPool),
+		transport:   postgres.NewTransportRepoPostgres(dbPool),
+		caseRepo:    postgres.NewCaseRepoPostgres(dbPool),
+		contactInfo: postgres.NewContactInfoRepoPostgres(dbPool),
+		violation:   postgres.NewViolationDBPostgres(dbPool),
+		training:    postgres.NewTrainingRepoPostgres(dbPool),
+		checker:     postgres.NewCheckerRepoPostgres(dbPool),
+		expert:      postgres.NewExpertRepoPostgres(dbPool),
+		director:    postgres.NewDirectorRepoPostgres(dbPool),
+		image:       postgres.NewImageRepoPostgres(dbPool),
+		outbox:      postgres.NewOutboxRepoPostgres(dbPool),
+		fine:        postgres.NewFineRepoPostgres(dbPool),
+		uow:         postgres.NewUnitOfWorkPostgres(dbPool),
 	}
+	// Delivery status of notifications is disabled without url or signing key of fine_notification
+	if notificationCfg.URL != "" && notificationTokens != nil {
+		r.delivery = notification.NewDeliveryRepoHTTP(notificationCfg, notificationTokens)
+	}
+
+	return r
 }
@@ -2,6 +2,7 @@ package app
 
 import (
 	"TrafficPolice/internal/domain"
+	"TrafficPolice/internal/transport/rest"
 	"TrafficPolice/internal/transport/rest/middlewares"
 	httpSwagger "github.com/swaggo/http-swagger"
 	"net/http"
@@ -33,6 +34,9 @@ func (s *ServeMuxInit) Init() *http.ServeMux {
 	s.initExpertHandlers()
 	s.initRatingHandlers()
 	s.initDirectorHandlers()
+	s.initOutboxHandlers()
+	s.initFineHandlers()
+	s.initSignedImageHandlers()
 
 	return s.mux
 }
@@ -42,9 +46,17 @@ func (s *ServeMuxInit) initCameraHandlers() {
 		s.authMiddleware.IdentifyRole(http.HandlerFunc(s.h.camera.AddCameraType), domain.DirectorRole),
 	)
 
+	s.mux.Handle("PUT /camera/type/{id}/mapping",
+		s.authMiddleware.IdentifyRole(http.HandlerFunc(s.h.camera.SetCameraTypeMapping), domain.DirectorRole),
+	)
+
 	s.mux.Handle("POST /camera",
 		s.authMiddleware.IdentifyRole(http.HandlerFunc(s.h.camera.RegisterCamera), domain.DirectorRole),
 	)
+
+	s.mux.Handle("PUT /camera/{id}/redact_regions",
+		s.authMiddleware.IdentifyRole(http.HandlerFunc(s.h.camera.SetCameraRedactRegions), domain.DirectorRole),
+	)
 }
 func (s *ServeMuxInit) initCaseHandlers() {
 	s.mux.Handle("POST /case",
@@ -55,12 +67,20 @@ func (s *ServeMuxInit) initCaseHandlers() {
 		s.authMiddleware.IdentifyRole(http.HandlerFunc(s.h.caseHandler.UploadCaseImg), domain.CameraRole),
 	)
 
+	s.mux.Handle("POST /case/{id}/img/allow_overwrite",
+		s.authMiddleware.IdentifyRole(http.HandlerFunc(s.h.caseHandler.AllowCaseImgOverwrite), domain.DirectorRole),
+	)
+
 	s.mux.Handle("GET /case/{id}/img",
 		s.authMiddleware.IdentifyRole(
 			s.authMiddleware.IsExpertConfirmed(http.HandlerFunc(s.h.caseHandler.GetCaseImg)),
 			domain.DirectorRole, domain.ExpertRole,
 		),
 	)
+
+	s.mux.Handle("GET /case/{id}/img/original",
+		s.authMiddleware.IdentifyRole(http.HandlerFunc(s.h.caseHandler.GetCaseOriginalImg), domain.DirectorRole),
+	)
 }
 
 func (s *ServeMuxInit) initContactInfoHandlers() {
@@ -158,4 +178,86 @@ func (s *ServeMuxInit) initDirectorHandlers() {
 			domain.DirectorRole,
 		),
 	)
+
+	s.mux.Handle("GET /director/reviews",
+		s.authMiddleware.IdentifyRole(
+			http.HandlerFunc(s.h.director.GetReviewCases),
+			domain.DirectorRole,
+		),
+	)
+
+	s.mux.Handle("POST /director/reviews/{id}/resolve",
+		s.authMiddleware.IdentifyRole(
+			http.HandlerFunc(s.h.director.ResolveReviewCase),
+			domain.DirectorRole,
+		),
+	)
+
+	s.mux.Handle("POST /director/cases/{id}/override",
+		s.authMiddleware.IdentifyRole(
+			http.HandlerFunc(s.h.director.OverrideCaseDecision),
+			domain.DirectorRole,
+		),
+	)
+
+	s.mux.Handle("POST /director/cases/{id}/void_fine",
+		s.authMiddleware.IdentifyRole(
+			http.HandlerFunc(s.h.director.VoidCaseFine),
+			domain.DirectorRole,
+		),
+	)
+
+	s.mux.Handle("POST /director/cases/{id}/reopen",
+		s.authMiddleware.IdentifyRole(
+			http.HandlerFunc(s.h.director.ReopenCase),
+			domain.DirectorRole,
+		),
+	)
+
+	s.mux.Handle("GET /director/notification_token",
+		s.authMiddleware.IdentifyRole(
+			http.HandlerFunc(s.h.director.NewNotificationToken),
+			domain.DirectorRole,
+		),
+	)
+}
+
+func (s *ServeMuxInit) initOutboxHandlers() {
+	s.mux.Handle("GET /outbox",
+		s.authMiddleware.IdentifyRole(
+			http.HandlerFunc(s.h.outbox.GetProblemMessages),
+			domain.DirectorRole,
+		),
+	)
+
+	s.mux.Handle("POST /outbox/{id}/replay",
+		s.authMiddleware.IdentifyRole(
+			http.HandlerFunc(s.h.outbox.ReplayMessage),
+			domain.DirectorRole,
+		),
+	)
+}
+
+func (s *ServeMuxInit) initFineHandlers() {
+	// Payment provider is authenticated by signature of webhook
+	s.mux.HandleFunc("POST /fines/payments", s.h.fine.PaymentWebhook)
+
+	s.mux.Handle("GET /fines",
+		s.authMiddleware.IdentifyRole(
+			http.HandlerFunc(s.h.fine.GetFines),
+			domain.DirectorRole,
+		),
+	)
+
+	s.mux.Handle("POST /fines/{id}/cancel",
+		s.authMiddleware.IdentifyRole(
+			http.HandlerFunc(s.h.fine.CancelFine),
+			domain.DirectorRole,
+		),
+	)
+}
+
+func (s *ServeMuxInit) initSignedImageHandlers() {
+	// Image is authenticated by signature of url
+	s.mux.HandleFunc("GET "+rest.SignedCaseImgPath, s.h.signedImage.GetCaseImg)
 }
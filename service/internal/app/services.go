@@ -3,6 +3,7 @@ package app
 import (
 	"TrafficPolice/internal/config"
 	"TrafficPolice/internal/service"
+	"TrafficPolice/internal/storage"
 	"TrafficPolice/internal/tokens"
 	"TrafficPolice/pkg/hash"
 )
@@ -19,22 +20,38 @@ type services struct {
 	expert      service.ExpertService
 	training    service.TrainingService
 	director    service.DirectorService
+	outbox      service.OutboxService
+	fine        service.FineService
 }
 
-func newServices(r *repos, manager tokens.TokenManager, cfg *config.Config) *services {
+func newServices(
+	r *repos,
+	manager tokens.TokenManager,
+	notificationTokens tokens.TokenManager,
+	imgStorage storage.BlobStorage,
+	cfg *config.Config,
+) *services {
 	hasher := hash.NewSHA1Hasher(cfg.PassSalt)
+	img := service.NewImgService(imgStorage, r.image, r.uow)
+	events := service.NewEventPublisher()
 
 	return &services{
-		img:         service.NewImgService(),
-		rating:      service.NewRatingService(r.rating, cfg.Rating),
-		auth:        service.NewAuthService(r.auth, r.rating, hasher, manager),
+		img:         img,
+		rating:      service.NewRatingService(r.rating, r.uow, events, cfg.Rating),
+		auth:        service.NewAuthService(r.auth, r.uow, events, hasher, manager),
 		pagination:  service.NewPaginationService(r.pagination),
 		camera:      service.NewCameraService(r.camera),
-		caseService: service.NewCaseService(r.caseRepo, r.transport),
+		caseService: service.NewCaseService(r.caseRepo, r.transport, r.camera, img, r.uow, events),
 		contactInfo: service.NewContactInfoService(r.contactInfo),
 		violation:   service.NewViolationService(r.violation),
-		expert:      service.NewExpertService(r.expert, r.caseRepo, cfg.Consensus),
-		training:    service.NewTrainingService(r.training),
-		director:    service.NewDirectorService(r.director, r.checker),
+		expert: service.NewExpertService(
+			r.expert, r.caseRepo, r.uow, events, cfg.Consensus, cfg.Lease, cfg.Escalation, cfg.Fines,
+		),
+		training: service.NewTrainingService(r.training),
+		director: service.NewDirectorService(
+			r.director, r.checker, r.delivery, notificationTokens, r.uow, events, cfg.Fines,
+		),
+		outbox: service.NewOutboxService(r.outbox, cfg.Outbox),
+		fine:   service.NewFineService(r.fine, r.uow, events, cfg.Fines),
 	}
 }
@@ -0,0 +1,122 @@
+package camera
+
+import (
+	"TrafficPolice/internal/transport/rest/dto"
+	"strconv"
+	"time"
+)
+
+const (
+	typeCamerus1 = "camerus1"
+	typeCamerus2 = "camerus2"
+	typeCamerus3 = "camerus3"
+
+	// camerus3 transport is one string: number digit, 3 chars, 2 number digits, region
+	camerus3TransportMinLen = 7
+)
+
+func decodeCamerus1(info map[string]any) (dto.Case, error) {
+	var c dto.Case
+	var err error
+
+	if c.Date, err = getDateLayout(info, "datetime", time.RFC3339); err != nil {
+		return dto.Case{}, err
+	}
+	if c.Transport.Chars, err = getString(info, "transport_chars"); err != nil {
+		return dto.Case{}, err
+	}
+	if c.Transport.Num, err = getString(info, "transport_numbers"); err != nil {
+		return dto.Case{}, err
+	}
+	if c.Transport.Region, err = getString(info, "transport_region"); err != nil {
+		return dto.Case{}, err
+	}
+	if c.Camera.ID, err = getString(info, "camera_id"); err != nil {
+		return dto.Case{}, err
+	}
+	if c.Violation.ID, err = getString(info, "violation_id"); err != nil {
+		return dto.Case{}, err
+	}
+	if c.ViolationValue, err = getString(info, "violation_value"); err != nil {
+		return dto.Case{}, err
+	}
+	if c.RequiredSkill, err = getInt(info, "skill_value"); err != nil {
+		return dto.Case{}, err
+	}
+
+	return c, nil
+}
+
+func decodeCamerus2(info map[string]any) (dto.Case, error) {
+	var c dto.Case
+	var err error
+
+	if c.Transport.Chars, err = getString(info, "transport.chars"); err != nil {
+		return dto.Case{}, err
+	}
+	if c.Transport.Num, err = getString(info, "transport.numbers"); err != nil {
+		return dto.Case{}, err
+	}
+	if c.Transport.Region, err = getString(info, "transport.region"); err != nil {
+		return dto.Case{}, err
+	}
+	if c.Camera.ID, err = getString(info, "camera.id"); err != nil {
+		return dto.Case{}, err
+	}
+	if c.Violation.ID, err = getString(info, "violation.id"); err != nil {
+		return dto.Case{}, err
+	}
+	if c.ViolationValue, err = getString(info, "violation.value"); err != nil {
+		return dto.Case{}, err
+	}
+	if c.RequiredSkill, err = getInt(info, "skill.value"); err != nil {
+		return dto.Case{}, err
+	}
+	if c.Date, err = getDateParts(info, "datetime"); err != nil {
+		return dto.Case{}, err
+	}
+
+	return c, nil
+}
+
+func decodeCamerus3(info map[string]any) (dto.Case, error) {
+	var c dto.Case
+
+	transportStr, err := getString(info, "transport")
+	if err != nil {
+		return dto.Case{}, err
+	}
+	transport := []rune(transportStr)
+	if len(transport) < camerus3TransportMinLen {
+		return dto.Case{}, &FieldError{
+			Key:      "transport",
+			Expected: "string of at least " + strconv.Itoa(camerus3TransportMinLen) + " symbols",
+			Got:      strconv.Quote(transportStr),
+		}
+	}
+
+	c.Transport.Chars = string(transport[1:4])
+	c.Transport.Num = string(transport[0]) + string(transport[4:6])
+	c.Transport.Region = string(transport[6:])
+
+	if c.Camera.ID, err = getString(info, "camera.id"); err != nil {
+		return dto.Case{}, err
+	}
+	if c.Violation.ID, err = getString(info, "violation.id"); err != nil {
+		return dto.Case{}, err
+	}
+	if c.ViolationValue, err = getString(info, "violation.value"); err != nil {
+		return dto.Case{}, err
+	}
+	if c.RequiredSkill, err = getInt(info, "skill"); err != nil {
+		return dto.Case{}, err
+	}
+
+	seconds, err := getInt(info, "datetime")
+	if err != nil {
+		return dto.Case{}, err
+	}
+	c.Date = time.Unix(seconds, 0)
+
+	return c, nil
+}
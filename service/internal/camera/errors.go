@@ -0,0 +1,39 @@
+package camera
+
+import (
+	"TrafficPolice/internal/errs"
+	"fmt"
+)
+
+// DecodeError describes malformed binary payload
+type DecodeError struct {
+	Offset   int
+	Key      string
+	Expected string
+}
+
+func (e *DecodeError) Error() string {
+	if e.Key == "" {
+		return fmt.Sprintf("invalid payload at offset %d: expected %s", e.Offset, e.Expected)
+	}
+	return fmt.Sprintf("invalid payload at offset %d (key %q): expected %s", e.Offset, e.Key, e.Expected)
+}
+
+func (e *DecodeError) Unwrap() error {
+	return errs.ErrInvalidPayload
+}
+
+// FieldError describes decoded payload that misses a key or has a value of unexpected type
+type FieldError struct {
+	Key      string
+	Expected string
+	Got      string
+}
+
+func (e *FieldError) Error() string {
+	return fmt.Sprintf("invalid payload key %q: expected %s, got %s", e.Key, e.Expected, e.Got)
+}
+
+func (e *FieldError) Unwrap() error {
+	return errs.ErrInvalidPayload
+}
@@ -0,0 +1,190 @@
+package camera
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	typeNameString = "string"
+	typeNameInt    = "int"
+	typeNameDict   = "dict"
+	typeNameNone   = "nothing"
+)
+
+// lookupKey searches key as is, then as path of nested dicts separated by dot
+func lookupKey(info map[string]any, key string) (any, bool) {
+	if value, ok := info[key]; ok {
+		return value, true
+	}
+
+	parts := strings.Split(key, ".")
+	current := info
+	for i, part := range parts {
+		value, ok := current[part]
+		if !ok {
+			return nil, false
+		}
+		if i == len(parts)-1 {
+			return value, true
+		}
+
+		current, ok = value.(map[string]any)
+		if !ok {
+			return nil, false
+		}
+	}
+
+	return nil, false
+}
+
+func getValue(info map[string]any, key string, expected string) (any, error) {
+	value, ok := lookupKey(info, key)
+	if !ok {
+		return nil, &FieldError{Key: key, Expected: expected, Got: typeNameNone}
+	}
+	return value, nil
+}
+
+func getString(info map[string]any, key string) (string, error) {
+	value, err := getValue(info, key, typeNameString)
+	if err != nil {
+		return "", err
+	}
+
+	s, ok := value.(string)
+	if !ok {
+		return "", &FieldError{Key: key, Expected: typeNameString, Got: typeName(value)}
+	}
+	return s, nil
+}
+
+func getInt(info map[string]any, key string) (int64, error) {
+	value, err := getValue(info, key, typeNameInt)
+	if err != nil {
+		return 0, err
+	}
+
+	n, ok := value.(int64)
+	if !ok {
+		return 0, &FieldError{Key: key, Expected: typeNameInt, Got: typeName(value)}
+	}
+	return n, nil
+}
+
+func getDict(info map[string]any, key string) (map[string]any, error) {
+	value, err := getValue(info, key, typeNameDict)
+	if err != nil {
+		return nil, err
+	}
+
+	dict, ok := value.(map[string]any)
+	if !ok {
+		return nil, &FieldError{Key: key, Expected: typeNameDict, Got: typeName(value)}
+	}
+	return dict, nil
+}
+
+// getStringLike accepts string and int values
+func getStringLike(info map[string]any, key string) (string, error) {
+	value, err := getValue(info, key, typeNameString)
+	if err != nil {
+		return "", err
+	}
+
+	switch v := value.(type) {
+	case string:
+		return v, nil
+	case int64:
+		return strconv.FormatInt(v, 10), nil
+	default:
+		return "", &FieldError{Key: key, Expected: typeNameString, Got: typeName(value)}
+	}
+}
+
+// getIntLike accepts int values and strings with integer
+func getIntLike(info map[string]any, key string) (int64, error) {
+	value, err := getValue(info, key, typeNameInt)
+	if err != nil {
+		return 0, err
+	}
+
+	switch v := value.(type) {
+	case int64:
+		return v, nil
+	case string:
+		n, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return 0, &FieldError{Key: key, Expected: typeNameInt, Got: strconv.Quote(v)}
+		}
+		return n, nil
+	default:
+		return 0, &FieldError{Key: key, Expected: typeNameInt, Got: typeName(value)}
+	}
+}
+
+func getDateLayout(info map[string]any, key string, layout string) (time.Time, error) {
+	s, err := getString(info, key)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	date, err := time.Parse(layout, s)
+	if err != nil {
+		return time.Time{}, &FieldError{Key: key, Expected: fmt.Sprintf("date in layout %s", layout), Got: strconv.Quote(s)}
+	}
+	return date, nil
+}
+
+// getDateParts reads dict with year, month, day, hour, minute, seconds and utc_offset keys
+func getDateParts(info map[string]any, key string) (time.Time, error) {
+	parts, err := getDict(info, key)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	partKeys := []string{"year", "month", "day", "hour", "minute", "seconds"}
+	values := make([]int64, len(partKeys))
+	for i, partKey := range partKeys {
+		values[i], err = getIntLike(parts, partKey)
+		if err != nil {
+			return time.Time{}, prefixFieldError(err, key)
+		}
+	}
+
+	utcOffset, err := getString(parts, "utc_offset")
+	if err != nil {
+		return time.Time{}, prefixFieldError(err, key)
+	}
+
+	dateString := fmt.Sprintf("%04d-%02d-%02dT%02d:%02d:%02d%s",
+		values[0], values[1], values[2], values[3], values[4], values[5], utcOffset)
+	date, err := time.Parse(time.RFC3339, dateString)
+	if err != nil {
+		return time.Time{}, &FieldError{Key: key, Expected: "valid date", Got: strconv.Quote(dateString)}
+	}
+
+	return date, nil
+}
+
+func prefixFieldError(err error, prefix string) error {
+	if fieldErr, ok := err.(*FieldError); ok {
+		return &FieldError{Key: prefix + "." + fieldErr.Key, Expected: fieldErr.Expected, Got: fieldErr.Got}
+	}
+	return err
+}
+
+func typeName(value any) string {
+	switch value.(type) {
+	case string:
+		return typeNameString
+	case int64:
+		return typeNameInt
+	case map[string]any:
+		return typeNameDict
+	default:
+		return fmt.Sprintf("%T", value)
+	}
+}
@@ -0,0 +1,77 @@
+package camera
+
+import (
+	"TrafficPolice/internal/domain"
+	"TrafficPolice/internal/errs"
+	"TrafficPolice/internal/transport/rest/dto"
+	"fmt"
+	"time"
+)
+
+const (
+	DateFormatRFC3339 = "rfc3339"
+	DateFormatUnix    = "unix"
+	// DateFormatParts expects dict with year, month, day, hour, minute, seconds and utc_offset keys
+	DateFormatParts = "parts"
+)
+
+// MappingDecoder decodes payload by declarative field mapping of camera type
+type MappingDecoder struct {
+	fields []domain.FieldMapping
+}
+
+func NewMappingDecoder(mapping domain.CameraTypeMapping) *MappingDecoder {
+	return &MappingDecoder{fields: mapping.Fields}
+}
+
+func (d *MappingDecoder) Decode(info map[string]any) (dto.Case, error) {
+	var c dto.Case
+
+	for _, f := range d.fields {
+		var err error
+		switch f.CaseField {
+		case domain.CaseFieldTransportChars:
+			c.Transport.Chars, err = getStringLike(info, f.PayloadKey)
+		case domain.CaseFieldTransportNum:
+			c.Transport.Num, err = getStringLike(info, f.PayloadKey)
+		case domain.CaseFieldTransportRegion:
+			c.Transport.Region, err = getStringLike(info, f.PayloadKey)
+		case domain.CaseFieldCameraID:
+			c.Camera.ID, err = getString(info, f.PayloadKey)
+		case domain.CaseFieldViolationID:
+			c.Violation.ID, err = getString(info, f.PayloadKey)
+		case domain.CaseFieldViolationValue:
+			c.ViolationValue, err = getStringLike(info, f.PayloadKey)
+		case domain.CaseFieldRequiredSkill:
+			c.RequiredSkill, err = getIntLike(info, f.PayloadKey)
+		case domain.CaseFieldDate:
+			c.Date, err = getDate(info, f.PayloadKey, f.Format)
+		default:
+			err = fmt.Errorf("%w: unknown case field %s", errs.ErrInvalidMapping, f.CaseField)
+		}
+
+		if err != nil {
+			return dto.Case{}, err
+		}
+	}
+
+	return c, nil
+}
+
+func getDate(info map[string]any, key string, format string) (time.Time, error) {
+	switch format {
+	case "", DateFormatRFC3339:
+		return getDateLayout(info, key, time.RFC3339)
+	case DateFormatUnix:
+		seconds, err := getIntLike(info, key)
+		if err != nil {
+			return time.Time{}, err
+		}
+		return time.Unix(seconds, 0), nil
+	case DateFormatParts:
+		return getDateParts(info, key)
+	default:
+		// Any other format is treated as Go time layout
+		return getDateLayout(info, key, format)
+	}
+}
@@ -0,0 +1,79 @@
+package camera
+
+import (
+	"TrafficPolice/internal/domain"
+	"TrafficPolice/internal/errs"
+	"TrafficPolice/internal/transport/rest/dto"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestMappingDecoder(t *testing.T) {
+	mapping := domain.CameraTypeMapping{
+		Fields: []domain.FieldMapping{
+			{CaseField: domain.CaseFieldTransportChars, PayloadKey: "transport_chars"},
+			{CaseField: domain.CaseFieldTransportNum, PayloadKey: "transport.numbers"},
+			{CaseField: domain.CaseFieldTransportRegion, PayloadKey: "transport.region"},
+			{CaseField: domain.CaseFieldViolationID, PayloadKey: "violation.id"},
+			{CaseField: domain.CaseFieldViolationValue, PayloadKey: "violation.value"},
+			{CaseField: domain.CaseFieldRequiredSkill, PayloadKey: "skill"},
+			{CaseField: domain.CaseFieldDate, PayloadKey: "datetime", Format: DateFormatUnix},
+		},
+	}
+
+	testCases := []struct {
+		name         string
+		info         map[string]any
+		expectedCase dto.Case
+		expectedErr  error
+	}{
+		{
+			name: "Flat and nested keys",
+			info: map[string]any{
+				"transport_chars": "abc",
+				"transport":       map[string]any{"numbers": "123", "region": "77"},
+				"violation":       map[string]any{"id": "violation_id", "value": int64(90)},
+				"skill":           int64(2),
+				"datetime":        int64(1700000000),
+			},
+			expectedCase: dto.Case{
+				Transport:      dto.Transport{Chars: "abc", Num: "123", Region: "77"},
+				Violation:      dto.Violation{ID: "violation_id"},
+				ViolationValue: "90",
+				RequiredSkill:  2,
+				Date:           time.Unix(1700000000, 0),
+			},
+			expectedErr: nil,
+		},
+		{
+			name: "Missing key",
+			info: map[string]any{
+				"transport_chars": "abc",
+			},
+			expectedCase: dto.Case{},
+			expectedErr:  errs.ErrInvalidPayload,
+		},
+		{
+			name: "Wrong value type",
+			info: map[string]any{
+				"transport_chars": map[string]any{},
+				"transport":       map[string]any{"numbers": "123", "region": "77"},
+				"violation":       map[string]any{"id": "violation_id", "value": "90"},
+				"skill":           int64(2),
+				"datetime":        int64(1700000000),
+			},
+			expectedCase: dto.Case{},
+			expectedErr:  errs.ErrInvalidPayload,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			c, err := NewMappingDecoder(mapping).Decode(tc.info)
+
+			assert.ErrorIs(t, err, tc.expectedErr)
+			assert.Equal(t, tc.expectedCase, c)
+		})
+	}
+}
@@ -5,77 +5,77 @@ import (
 	"TrafficPolice/internal/service"
 	"TrafficPolice/internal/transport/rest/dto"
 	"bytes"
+	"context"
 	"encoding/binary"
+	"errors"
 	"fmt"
 	"github.com/google/uuid"
 	"github.com/jcalabro/leb128"
-	"time"
 )
 
 const (
-	typeCamerus1 = "camerus1"
-	typeCamerus2 = "camerus2"
-	typeCamerus3 = "camerus3"
-
 	cameraIDKey = "camera_id"
 	cameraKey   = "camera"
+
+	headerSize      = 2
+	entryHeaderSize = 5
+
+	valueTypeString = 0
+	valueTypeInt    = 1
+	valueTypeDict   = 2
+)
+
+const (
+	// MaxPayloadSize is the max size of binary payload in bytes
+	MaxPayloadSize = 64 << 10
+	// MaxDepth is the max nesting level of dicts in payload
+	MaxDepth = 8
 )
 
 type Parser struct {
 	cameraService service.CameraService
+	registry      *Registry
 }
 
-func NewParser(cameraService service.CameraService) *Parser {
+func NewParser(cameraService service.CameraService, registry *Registry) *Parser {
 	return &Parser{
 		cameraService: cameraService,
+		registry:      registry,
 	}
 }
 
-func (p *Parser) ParseCameraInfo(payload []byte) (dto.Case, error) {
+func (p *Parser) ParseCameraInfo(ctx context.Context, payload []byte) (dto.Case, error) {
 	if len(payload) == 0 {
 		return dto.Case{}, errs.ErrEmptyPayload
 	}
 
-	payload = payload[2:]
-	info := p.parsePayload(payload)
+	info, err := ParsePayload(payload)
+	if err != nil {
+		return dto.Case{}, err
+	}
 
-	var cameraType string
-	var err error
-	if cameraID, ok := info[cameraIDKey]; ok {
-		_, err = uuid.Parse(cameraID.(string))
-		if err != nil {
-			return dto.Case{}, errs.ErrInvalidCameraID
-		}
-		cameraType, err = p.cameraService.GetCameraTypeByCameraID(cameraID.(string))
-	} else if camera, ok := info[cameraKey].(map[string]any); ok {
-		cameraID := camera["id"].(string)
-		_, err = uuid.Parse(cameraID)
-		if err != nil {
-			return dto.Case{}, errs.ErrInvalidCameraID
-		}
-		cameraType, err = p.cameraService.GetCameraTypeByCameraID(cameraID)
-	} else {
-		err = errs.ErrUnknownCameraID
+	cameraID, err := p.getCameraID(info)
+	if err != nil {
+		return dto.Case{}, err
 	}
 
+	cameraType, err := p.cameraService.GetCameraTypeByCameraID(ctx, cameraID)
 	if err != nil {
 		return dto.Case{}, err
 	}
 
-	var caseInfo dto.Case
-	switch cameraType {
-	case typeCamerus1:
-		caseInfo, err = p.parseCamerus1(info)
-	case typeCamerus2:
-		caseInfo, err = p.parseCamerus2(info)
-	case typeCamerus3:
-		caseInfo, err = p.parseCamerus3(info)
-	default:
-		return dto.Case{}, errs.ErrUnknownCameraType
+	decoder, err := p.getDecoder(ctx, cameraType)
+	if err != nil {
+		return dto.Case{}, err
 	}
+
+	caseInfo, err := decoder.Decode(info)
 	if err != nil {
 		return dto.Case{}, err
 	}
+	if caseInfo.Camera.ID == "" {
+		caseInfo.Camera.ID = cameraID
+	}
 
 	err = p.validateCase(caseInfo)
 	if err != nil {
@@ -84,130 +84,130 @@ func (p *Parser) ParseCameraInfo(payload []byte) (dto.Case, error) {
 	return caseInfo, nil
 }
 
-func (p *Parser) parsePayload(payload []byte) map[string]any {
-	info := make(map[string]any)
-
-	for len(payload) > 0 {
-		keySize := binary.BigEndian.Uint16(payload[:2])
-		payload = payload[2:]
+// ParsePayload decodes binary payload: 2 bytes of header and sequence of entries.
+// Entry consists of key size (2 bytes), value size (2 bytes), value type (1 byte), key and value
+func ParsePayload(payload []byte) (map[string]any, error) {
+	if len(payload) > MaxPayloadSize {
+		return nil, &DecodeError{Offset: MaxPayloadSize, Expected: fmt.Sprintf("payload of at most %d bytes", MaxPayloadSize)}
+	}
+	if len(payload) < headerSize {
+		return nil, &DecodeError{Offset: len(payload), Expected: "payload header"}
+	}
 
-		valueSize := binary.BigEndian.Uint16(payload[:2])
-		payload = payload[2:]
+	return parseEntries(payload[headerSize:], headerSize, 1)
+}
 
-		valueType := payload[0]
-		payload = payload[1:]
+func parseEntries(payload []byte, offset int, depth int) (map[string]any, error) {
+	if depth > MaxDepth {
+		return nil, &DecodeError{Offset: offset, Expected: fmt.Sprintf("nesting depth of at most %d", MaxDepth)}
+	}
 
-		keyValue := payload[:keySize]
-		payload = payload[keySize:]
+	info := make(map[string]any)
+	pos := 0
+	for pos < len(payload) {
+		if len(payload)-pos < entryHeaderSize {
+			return nil, &DecodeError{Offset: offset + pos, Expected: "entry header"}
+		}
+		keySize := int(binary.BigEndian.Uint16(payload[pos : pos+2]))
+		valueSize := int(binary.BigEndian.Uint16(payload[pos+2 : pos+4]))
+		valueType := payload[pos+4]
+		pos += entryHeaderSize
 
-		value := payload[:valueSize]
-		payload = payload[valueSize:]
+		if len(payload)-pos < keySize {
+			return nil, &DecodeError{Offset: offset + pos, Expected: fmt.Sprintf("key of %d bytes", keySize)}
+		}
+		key := string(payload[pos : pos+keySize])
+		pos += keySize
 
-		if valueType == 0 {
-			info[string(keyValue)] = string(value)
-		} else if valueType == 1 {
-			n, _ := leb128.DecodeS64(bytes.NewBuffer(value))
-			info[string(keyValue)] = n
-		} else if valueType == 2 {
-			dict := p.parsePayload(value)
-			info[string(keyValue)] = dict
+		if len(payload)-pos < valueSize {
+			return nil, &DecodeError{Offset: offset + pos, Key: key, Expected: fmt.Sprintf("value of %d bytes", valueSize)}
+		}
+		value := payload[pos : pos+valueSize]
+		valueOffset := offset + pos
+		pos += valueSize
+
+		switch valueType {
+		case valueTypeString:
+			info[key] = string(value)
+		case valueTypeInt:
+			n, err := decodeInt(value)
+			if err != nil {
+				return nil, &DecodeError{Offset: valueOffset, Key: key, Expected: "leb128 int"}
+			}
+			info[key] = n
+		case valueTypeDict:
+			dict, err := parseEntries(value, valueOffset, depth+1)
+			if err != nil {
+				return nil, err
+			}
+			info[key] = dict
+		default:
+			return nil, &DecodeError{Offset: valueOffset - keySize - 1, Key: key, Expected: "value type 0, 1 or 2"}
 		}
 	}
 
-	return info
+	return info, nil
 }
 
-func (p *Parser) parseCamerus1(info map[string]any) (dto.Case, error) {
-	date, err := time.Parse(time.RFC3339, info["datetime"].(string))
+// decodeInt decodes signed leb128 value, which must take all passed bytes
+func decodeInt(value []byte) (int64, error) {
+	// leb128 stops silently on EOF, so unterminated value is checked here
+	if len(value) == 0 || value[len(value)-1]&0x80 != 0 {
+		return 0, errors.New("unterminated leb128 value")
+	}
+
+	buf := bytes.NewReader(value)
+	n, err := leb128.DecodeS64(buf)
 	if err != nil {
-		return dto.Case{}, err
+		return 0, err
+	}
+	if buf.Len() != 0 {
+		return 0, errors.New("trailing bytes after leb128 value")
 	}
-	return dto.Case{
-		Transport: dto.Transport{
-			Chars:  info["transport_chars"].(string),
-			Num:    info["transport_numbers"].(string),
-			Region: info["transport_region"].(string),
-		},
-		Camera: dto.Camera{
-			ID: info["camera_id"].(string),
-		},
-		Violation: dto.Violation{
-			ID: info["violation_id"].(string),
-		},
-		ViolationValue: info["violation_value"].(string),
-		RequiredSkill:  info["skill_value"].(int64),
-		Date:           date,
-	}, nil
+	return n, nil
 }
 
-func (p *Parser) parseCamerus2(info map[string]any) (dto.Case, error) {
-	transport := info["transport"].(map[string]any)
-	camera := info["camera"].(map[string]any)
-	violation := info["violation"].(map[string]any)
-	skill := info["skill"].(map[string]any)
-
-	datetime := info["datetime"].(map[string]any)
-
-	year := datetime["year"].(int64)
-	month := datetime["month"].(int64)
-	day := datetime["day"].(int64)
-	hour := datetime["hour"].(int64)
-	minute := datetime["minute"].(int64)
-	seconds := datetime["seconds"].(int64)
-	utcOffset := datetime["utc_offset"].(string)
+// getCameraID finds camera id by camera_id key or by id key of camera dict
+func (p *Parser) getCameraID(info map[string]any) (string, error) {
+	var cameraID string
+	if id, ok := info[cameraIDKey]; ok {
+		cameraID, ok = id.(string)
+		if !ok {
+			return "", errs.ErrInvalidCameraID
+		}
+	} else if camera, ok := info[cameraKey].(map[string]any); ok {
+		cameraID, ok = camera["id"].(string)
+		if !ok {
+			return "", errs.ErrInvalidCameraID
+		}
+	} else {
+		return "", errs.ErrUnknownCameraID
+	}
 
-	dateString := fmt.Sprintf("%04d-%02d-%02dT%02d:%02d:%02d%s", year, month, day, hour, minute, seconds, utcOffset)
-	date, err := time.Parse(time.RFC3339, dateString)
+	_, err := uuid.Parse(cameraID)
 	if err != nil {
-		return dto.Case{}, err
+		return "", errs.ErrInvalidCameraID
 	}
 
-	return dto.Case{
-		Transport: dto.Transport{
-			Chars:  transport["chars"].(string),
-			Num:    transport["numbers"].(string),
-			Region: transport["region"].(string),
-		},
-		Camera: dto.Camera{
-			ID: camera["id"].(string),
-		},
-		Violation: dto.Violation{
-			ID: violation["id"].(string),
-		},
-		ViolationValue: violation["value"].(string),
-		RequiredSkill:  skill["value"].(int64),
-		Date:           date,
-	}, nil
-
+	return cameraID, nil
 }
 
-func (p *Parser) parseCamerus3(info map[string]any) (dto.Case, error) {
-	transportStr := info["transport"].(string)
-	transport := []rune(transportStr)
-
-	chars := string(transport[1:4])
-	num := string(transport[0]) + string(transport[4:6])
-	region := string(transport[6:])
-
-	camera := info["camera"].(map[string]any)
-	violation := info["violation"].(map[string]any)
-
-	return dto.Case{
-		Transport: dto.Transport{
-			Chars:  chars,
-			Num:    num,
-			Region: region,
-		},
-		Camera: dto.Camera{
-			ID: camera["id"].(string),
-		},
-		Violation: dto.Violation{
-			ID: violation["id"].(string),
-		},
-		ViolationValue: violation["value"].(string),
-		RequiredSkill:  info["skill"].(int64),
-		Date:           time.Unix(info["datetime"].(int64), 0),
-	}, nil
+// getDecoder returns registered decoder of camera type.
+// If there is no registered decoder, then field mapping of camera type is used
+func (p *Parser) getDecoder(ctx context.Context, cameraType string) (Decoder, error) {
+	if decoder, ok := p.registry.Get(cameraType); ok {
+		return decoder, nil
+	}
+
+	mapping, err := p.cameraService.GetCameraTypeMapping(ctx, cameraType)
+	if err != nil {
+		if errors.Is(err, errs.ErrNoCameraTypeMapping) {
+			return nil, errs.ErrUnknownCameraType
+		}
+		return nil, err
+	}
+
+	return NewMappingDecoder(mapping), nil
 }
 
 func (p *Parser) validateCase(c dto.Case) error {
@@ -0,0 +1,264 @@
+package camera
+
+import (
+	"TrafficPolice/internal/errs"
+	"TrafficPolice/internal/service/mocks"
+	"TrafficPolice/internal/transport/rest/dto"
+	"TrafficPolice/pkg/camerapayload"
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"testing"
+	"time"
+)
+
+const (
+	testCameraID    = "3e6fa3f3-5b1c-4f4b-a7f3-3c0b5e0e1a11"
+	testViolationID = "9b2d6b0e-8f5a-4a44-9f0c-7c1d2e3f4a55"
+)
+
+func encodeTestPayload(entries ...camerapayload.Entry) []byte {
+	payload, err := camerapayload.Encode(entries...)
+	if err != nil {
+		panic(err)
+	}
+	return payload
+}
+
+func camerus1Sample() []byte {
+	return encodeTestPayload(
+		camerapayload.String("transport_chars", "abc"),
+		camerapayload.String("transport_numbers", "123"),
+		camerapayload.String("transport_region", "77"),
+		camerapayload.String("camera_id", testCameraID),
+		camerapayload.String("violation_id", testViolationID),
+		camerapayload.String("violation_value", "90km/h"),
+		camerapayload.Int("skill_value", 1),
+		camerapayload.String("datetime", "2024-03-10T12:30:00+03:00"),
+	)
+}
+
+func camerus2Sample() []byte {
+	return encodeTestPayload(
+		camerapayload.Dict("transport",
+			camerapayload.String("chars", "abc"),
+			camerapayload.String("numbers", "123"),
+			camerapayload.String("region", "77"),
+		),
+		camerapayload.Dict("camera", camerapayload.String("id", testCameraID)),
+		camerapayload.Dict("violation",
+			camerapayload.String("id", testViolationID),
+			camerapayload.String("value", "90km/h"),
+		),
+		camerapayload.Dict("skill", camerapayload.Int("value", 1)),
+		camerapayload.Dict("datetime",
+			camerapayload.Int("year", 2024),
+			camerapayload.Int("month", 3),
+			camerapayload.Int("day", 10),
+			camerapayload.Int("hour", 12),
+			camerapayload.Int("minute", 30),
+			camerapayload.Int("seconds", 0),
+			camerapayload.String("utc_offset", "+03:00"),
+		),
+	)
+}
+
+func camerus3Sample() []byte {
+	return encodeTestPayload(
+		camerapayload.String("transport", "1abc2377"),
+		camerapayload.Dict("camera", camerapayload.String("id", testCameraID)),
+		camerapayload.Dict("violation",
+			camerapayload.String("id", testViolationID),
+			camerapayload.String("value", "90km/h"),
+		),
+		camerapayload.Int("skill", 1),
+		camerapayload.Int("datetime", 1710063000),
+	)
+}
+
+func TestParseCameraInfo(t *testing.T) {
+	date, err := time.Parse(time.RFC3339, "2024-03-10T12:30:00+03:00")
+	assert.NoError(t, err)
+
+	expectedCase := dto.Case{
+		Transport:      dto.Transport{Chars: "abc", Num: "123", Region: "77"},
+		Camera:         dto.Camera{ID: testCameraID},
+		Violation:      dto.Violation{ID: testViolationID},
+		ViolationValue: "90km/h",
+		RequiredSkill:  1,
+	}
+
+	testCases := []struct {
+		name         string
+		cameraType   string
+		payload      []byte
+		expectedCase dto.Case
+		expectedErr  error
+	}{
+		{
+			name:         "camerus1",
+			cameraType:   typeCamerus1,
+			payload:      camerus1Sample(),
+			expectedCase: expectedCase,
+			expectedErr:  nil,
+		},
+		{
+			name:         "camerus2",
+			cameraType:   typeCamerus2,
+			payload:      camerus2Sample(),
+			expectedCase: expectedCase,
+			expectedErr:  nil,
+		},
+		{
+			name:       "camerus3",
+			cameraType: typeCamerus3,
+			payload:    camerus3Sample(),
+			expectedCase: dto.Case{
+				Transport:      dto.Transport{Chars: "abc", Num: "123", Region: "77"},
+				Camera:         dto.Camera{ID: testCameraID},
+				Violation:      dto.Violation{ID: testViolationID},
+				ViolationValue: "90km/h",
+				RequiredSkill:  1,
+			},
+			expectedErr: nil,
+		},
+		{
+			name:       "camerus1 with wrong value type",
+			cameraType: typeCamerus1,
+			payload: encodeTestPayload(
+				camerapayload.String("camera_id", testCameraID),
+				camerapayload.Int("datetime", 1710063000),
+			),
+			expectedCase: dto.Case{},
+			expectedErr:  errs.ErrInvalidPayload,
+		},
+		{
+			name:       "camerus3 with short transport",
+			cameraType: typeCamerus3,
+			payload: encodeTestPayload(
+				camerapayload.String("transport", "1ab"),
+				camerapayload.Dict("camera", camerapayload.String("id", testCameraID)),
+			),
+			expectedCase: dto.Case{},
+			expectedErr:  errs.ErrInvalidPayload,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cameraService := mocks.NewCameraService(t)
+			cameraService.On("GetCameraTypeByCameraID", mock.Anything, testCameraID).
+				Return(tc.cameraType, nil)
+
+			parser := NewParser(cameraService, NewDefaultRegistry())
+			c, err := parser.ParseCameraInfo(context.Background(), tc.payload)
+
+			assert.ErrorIs(t, err, tc.expectedErr)
+			if tc.expectedErr == nil {
+				assert.True(t, c.Date.Equal(date))
+				c.Date = time.Time{}
+			}
+			assert.Equal(t, tc.expectedCase, c)
+		})
+	}
+}
+
+func TestParsePayloadErrors(t *testing.T) {
+	nested := camerapayload.String("id", testCameraID)
+	for i := 0; i < MaxDepth; i++ {
+		nested = camerapayload.Dict("dict", nested)
+	}
+
+	testCases := []struct {
+		name           string
+		payload        []byte
+		expectedOffset int
+		expectedKey    string
+	}{
+		{
+			name:           "Only one byte of header",
+			payload:        []byte{0},
+			expectedOffset: 1,
+		},
+		{
+			name:           "Truncated entry header",
+			payload:        []byte{0, 0, 0, 3, 0},
+			expectedOffset: 2,
+		},
+		{
+			name:           "Key is longer than payload",
+			payload:        []byte{0, 0, 0, 9, 0, 1, 0, 'k', 'e', 'y'},
+			expectedOffset: 7,
+		},
+		{
+			name:           "Value is longer than payload",
+			payload:        []byte{0, 0, 0, 1, 0, 9, 0, 'k', 'v'},
+			expectedOffset: 8,
+			expectedKey:    "k",
+		},
+		{
+			name:           "Broken leb128 value",
+			payload:        []byte{0, 0, 0, 1, 0, 1, 1, 'k', 0x80},
+			expectedOffset: 8,
+			expectedKey:    "k",
+		},
+		{
+			name:           "Unknown value type",
+			payload:        []byte{0, 0, 0, 1, 0, 1, 7, 'k', 'v'},
+			expectedOffset: 6,
+			expectedKey:    "k",
+		},
+		{
+			name:           "Too deep nesting",
+			payload:        encodeTestPayload(nested),
+			expectedOffset: 2 + MaxDepth*(entryHeaderSize+len("dict")),
+		},
+		{
+			name:           "Too large payload",
+			payload:        make([]byte, MaxPayloadSize+1),
+			expectedOffset: MaxPayloadSize,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := ParsePayload(tc.payload)
+
+			assert.ErrorIs(t, err, errs.ErrInvalidPayload)
+
+			var decodeErr *DecodeError
+			if assert.ErrorAs(t, err, &decodeErr) {
+				assert.Equal(t, tc.expectedOffset, decodeErr.Offset)
+				assert.Equal(t, tc.expectedKey, decodeErr.Key)
+			}
+		})
+	}
+}
+
+// FuzzParsePayload checks that any payload is either decoded or rejected with ErrInvalidPayload.
+// Corpus in testdata contains real camerus1-3 samples
+func FuzzParsePayload(f *testing.F) {
+	f.Add([]byte{})
+	f.Add([]byte{0, 0, 0, 1, 0, 1, 1, 'k', 0x80})
+
+	registry := NewDefaultRegistry()
+	cameraTypes := []string{typeCamerus1, typeCamerus2, typeCamerus3}
+
+	f.Fuzz(func(t *testing.T, payload []byte) {
+		info, err := ParsePayload(payload)
+		if err != nil {
+			assert.ErrorIs(t, err, errs.ErrInvalidPayload)
+			return
+		}
+
+		for _, cameraType := range cameraTypes {
+			decoder, ok := registry.Get(cameraType)
+			assert.True(t, ok)
+
+			_, err = decoder.Decode(info)
+			if err != nil {
+				assert.ErrorIs(t, err, errs.ErrInvalidPayload)
+			}
+		}
+	})
+}
@@ -0,0 +1,68 @@
+package camera
+
+import (
+	"TrafficPolice/internal/errs"
+	"TrafficPolice/internal/transport/rest/dto"
+	"fmt"
+	"sync"
+)
+
+// Decoder converts parsed payload of concrete camera type to case
+type Decoder interface {
+	Decode(info map[string]any) (dto.Case, error)
+}
+
+// DecoderFunc allows to use ordinary functions as Decoder
+type DecoderFunc func(info map[string]any) (dto.Case, error)
+
+func (f DecoderFunc) Decode(info map[string]any) (dto.Case, error) {
+	return f(info)
+}
+
+// Registry stores decoders by camera type name
+type Registry struct {
+	mu       sync.RWMutex
+	decoders map[string]Decoder
+}
+
+func NewRegistry() *Registry {
+	return &Registry{
+		decoders: make(map[string]Decoder),
+	}
+}
+
+// NewDefaultRegistry returns registry with built-in camerus decoders
+func NewDefaultRegistry() *Registry {
+	r := NewRegistry()
+	r.MustRegister(typeCamerus1, DecoderFunc(decodeCamerus1))
+	r.MustRegister(typeCamerus2, DecoderFunc(decodeCamerus2))
+	r.MustRegister(typeCamerus3, DecoderFunc(decodeCamerus3))
+
+	return r
+}
+
+func (r *Registry) Register(cameraType string, decoder Decoder) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.decoders[cameraType]; ok {
+		return fmt.Errorf("decoder for camera type %s: %w", cameraType, errs.ErrAlreadyExists)
+	}
+	r.decoders[cameraType] = decoder
+
+	return nil
+}
+
+func (r *Registry) MustRegister(cameraType string, decoder Decoder) {
+	if err := r.Register(cameraType, decoder); err != nil {
+		panic(err)
+	}
+}
+
+func (r *Registry) Get(cameraType string) (Decoder, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	decoder, ok := r.decoders[cameraType]
+	return decoder, ok
+}
@@ -7,13 +7,42 @@ import (
 )
 
 type Config struct {
-	Consensus  int            `yaml:"consensus"`
-	PassSalt   string         `yaml:"passSalt"`
-	SigningKey string         `yaml:"signingKey"`
-	Rating     RatingConfig   `yaml:"rating"`
-	Postgres   PostgresConfig `yaml:"postgres"`
-	RabbitMQ   RabbitMQConfig `yaml:"rabbitmq"`
-	Directors  []DirectorInfo `yaml:"directors"`
+	Consensus    int                `yaml:"consensus"`
+	PassSalt     string             `yaml:"passSalt"`
+	SigningKey   string             `yaml:"signingKey"`
+	Rating       RatingConfig       `yaml:"rating"`
+	Lease        LeaseConfig        `yaml:"lease"`
+	Escalation   EscalationConfig   `yaml:"escalation"`
+	Outbox       OutboxConfig       `yaml:"outbox"`
+	Postgres     PostgresConfig     `yaml:"postgres"`
+	RabbitMQ     RabbitMQConfig     `yaml:"rabbitmq"`
+	Storage      StorageConfig      `yaml:"storage"`
+	Notification NotificationConfig `yaml:"notification"`
+	Fines        FinesConfig        `yaml:"fines"`
+	Events       EventsConfig       `yaml:"events"`
+	Directors    []DirectorInfo     `yaml:"directors"`
+}
+
+// FinesConfig sets fines, which are issued for cases with fine decision. Fine is paid with discount
+// of DiscountPercent during DiscountPeriod after issue and should be paid during DueIn.
+// Payments of provider are accepted only with signature by WebhookSecret
+type FinesConfig struct {
+	ReferencePrefix string        `yaml:"referencePrefix"`
+	DueIn           time.Duration `yaml:"dueIn"`
+	DiscountPercent int           `yaml:"discountPercent"`
+	DiscountPeriod  time.Duration `yaml:"discountPeriod"`
+	OverdueInterval time.Duration `yaml:"overdueInterval"`
+	WebhookSecret   string        `yaml:"webhookSecret"`
+}
+
+// EventsConfig sets fine events. Event of Version 1 contains image bytes, event of version 2 contains
+// url of image, which is signed by ImageURLSecret and expires after ImageURLTTL. ImageBaseURL is address
+// of service, which is reachable by fine_notification
+type EventsConfig struct {
+	Version        int           `yaml:"version"`
+	ImageBaseURL   string        `yaml:"imageBaseURL"`
+	ImageURLTTL    time.Duration `yaml:"imageURLTTL"`
+	ImageURLSecret string        `yaml:"imageURLSecret"`
 }
 
 type RatingConfig struct {
@@ -22,19 +51,82 @@ type RatingConfig struct {
 	MinExperts     int           `yaml:"minExperts"`
 }
 
+// LeaseConfig sets how long case is assigned to expert and how often expired leases are released
+type LeaseConfig struct {
+	Duration     time.Duration `yaml:"duration"`
+	ReapInterval time.Duration `yaml:"reapInterval"`
+}
+
+// EscalationConfig MaxSkill is maximum required skill of case. Case, which experts of MaxSkill can not solve,
+// is sent to review of director
+type EscalationConfig struct {
+	MaxSkill int `yaml:"maxSkill"`
+}
+
+// OutboxConfig sets how relay publishes outbox messages: how often and how many messages are claimed,
+// how long claimed message is hidden from other relays and how retries of failed publish are delayed.
+// Message, which is not published during StuckAfter, is shown to director as stuck
+type OutboxConfig struct {
+	RelayInterval time.Duration `yaml:"relayInterval"`
+	BatchSize     int           `yaml:"batchSize"`
+	ClaimTimeout  time.Duration `yaml:"claimTimeout"`
+	MaxAttempts   int           `yaml:"maxAttempts"`
+	MinBackoff    time.Duration `yaml:"minBackoff"`
+	MaxBackoff    time.Duration `yaml:"maxBackoff"`
+	StuckAfter    time.Duration `yaml:"stuckAfter"`
+}
+
+// PostgresConfig sets connection to database and pool of connections. Zero values of pool settings
+// mean defaults of pgxpool, zero StatementTimeout means queries without timeout
 type PostgresConfig struct {
-	User     string `yaml:"user"`
-	Password string `yaml:"password"`
-	Host     string `yaml:"host"`
-	Port     int    `yaml:"port"`
-	Database string `yaml:"database"`
+	User             string        `yaml:"user"`
+	Password         string        `yaml:"password"`
+	Host             string        `yaml:"host"`
+	Port             int           `yaml:"port"`
+	Database         string        `yaml:"database"`
+	MaxConns         int32         `yaml:"maxConns"`
+	MinConns         int32         `yaml:"minConns"`
+	MaxConnLifetime  time.Duration `yaml:"maxConnLifetime"`
+	MaxConnIdleTime  time.Duration `yaml:"maxConnIdleTime"`
+	StatementTimeout time.Duration `yaml:"statementTimeout"`
 }
 
+// RabbitMQConfig ReconnectMinBackoff and ReconnectMaxBackoff limit delay between dials after connection is lost
 type RabbitMQConfig struct {
-	User     string `yaml:"user"`
-	Password string `yaml:"password"`
-	Host     string `yaml:"host"`
-	Port     int    `yaml:"port"`
+	User                string        `yaml:"user"`
+	Password            string        `yaml:"password"`
+	Host                string        `yaml:"host"`
+	Port                int           `yaml:"port"`
+	ReconnectMinBackoff time.Duration `yaml:"reconnectMinBackoff"`
+	ReconnectMaxBackoff time.Duration `yaml:"reconnectMaxBackoff"`
+}
+
+// NotificationConfig is HTTP server of fine_notification, which returns delivery status of notifications.
+// Tokens for fine_notification are signed by SigningKey. It must differ from signing key of users,
+// so fine_notification can not issue tokens accepted by service. Empty URL or SigningKey disables delivery status
+type NotificationConfig struct {
+	URL        string        `yaml:"url"`
+	Timeout    time.Duration `yaml:"timeout"`
+	SigningKey string        `yaml:"signingKey"`
+}
+
+type StorageConfig struct {
+	Type  string             `yaml:"type"`
+	Local LocalStorageConfig `yaml:"local"`
+	S3    S3Config           `yaml:"s3"`
+}
+
+type LocalStorageConfig struct {
+	Dir string `yaml:"dir"`
+}
+
+type S3Config struct {
+	Endpoint  string        `yaml:"endpoint"`
+	Region    string        `yaml:"region"`
+	Bucket    string        `yaml:"bucket"`
+	AccessKey string        `yaml:"accessKey"`
+	SecretKey string        `yaml:"secretKey"`
+	Timeout   time.Duration `yaml:"timeout"`
 }
 
 type DirectorInfo struct {
@@ -20,6 +20,7 @@ func (c *AnalyticsConverter) MapDomainToDto(d domain.AnalyticsInterval) dto.Anal
 		CorrectCnt:           d.CorrectCnt,
 		IncorrectCnt:         d.IncorrectCnt,
 		UnknownCnt:           d.UnknownCnt,
+		ExpiredCnt:           d.ExpiredCnt,
 		MaxConsecutiveSolved: d.MaxConsecutiveSolved,
 	}
 }
@@ -17,13 +17,46 @@ func (c *CameraConverter) MapRegisterCameraDtoToDomain(camera dto.CameraIn,
 ) domain.RegisterCamera {
 	return domain.RegisterCamera{
 		Camera: domain.Camera{
-			ID:         "",
-			CameraType: domain.CameraType{ID: camera.CameraTypeID},
-			Latitude:   camera.Latitude,
-			Longitude:  camera.Longitude,
-			ShortDesc:  camera.ShortDesc,
+			ID:            "",
+			CameraType:    domain.CameraType{ID: camera.CameraTypeID},
+			Latitude:      camera.Latitude,
+			Longitude:     camera.Longitude,
+			ShortDesc:     camera.ShortDesc,
+			RedactRegions: c.MapRedactRegionsDtoToDomain(camera.RedactRegions),
 		},
 		Username: signUp.Username,
 		Password: signUp.Password,
 	}
 }
+
+func (c *CameraConverter) MapCameraTypeMappingDtoToDomain(cameraTypeID string,
+	mapping dto.CameraTypeMapping,
+) domain.CameraTypeMapping {
+	fields := make([]domain.FieldMapping, len(mapping.Fields))
+	for i, f := range mapping.Fields {
+		fields[i] = domain.FieldMapping{
+			CaseField:  f.CaseField,
+			PayloadKey: f.PayloadKey,
+			Format:     f.Format,
+		}
+	}
+
+	return domain.CameraTypeMapping{
+		CameraTypeID: cameraTypeID,
+		Fields:       fields,
+	}
+}
+
+func (c *CameraConverter) MapRedactRegionsDtoToDomain(regions []dto.RedactRegion) []domain.RedactRegion {
+	result := make([]domain.RedactRegion, len(regions))
+	for i, r := range regions {
+		result[i] = domain.RedactRegion{
+			X:      r.X,
+			Y:      r.Y,
+			Width:  r.Width,
+			Height: r.Height,
+		}
+	}
+
+	return result
+}
@@ -97,56 +97,83 @@ func (c *CaseConverter) MapDomainToDto(d domain.Case) dto.Case {
 	}
 }
 
-func (c *CaseConverter) MapCaseWithPersonToDTO(d domain.Case) dto.Case {
-	return dto.Case{
-		ID: d.ID,
-		Transport: dto.Transport{
-			ID:    d.Transport.ID,
-			Chars: d.Transport.Chars,
-			Num:   d.Transport.Num,
-			Person: &dto.Person{
-				ID:       d.Transport.Person.ID,
-				PhoneNum: d.Transport.Person.PhoneNum,
-				Email:    d.Transport.Person.Email,
-				VkID:     d.Transport.Person.VkID,
-				TgID:     d.Transport.Person.TgID,
-			},
-		},
-		Camera: dto.Camera{
-			ID:           d.Camera.ID,
-			CameraTypeID: d.Camera.CameraType.ID,
-			Latitude:     d.Camera.Latitude,
-			Longitude:    d.Camera.Longitude,
-			ShortDesc:    d.Camera.ShortDesc,
-		},
-		Violation: dto.Violation{
-			ID:         d.Violation.ID,
-			Name:       d.Violation.Name,
-			FineAmount: d.Violation.FineAmount,
-		},
-		ViolationValue: d.ViolationValue,
-		RequiredSkill:  d.RequiredSkill,
-		Date:           d.Date,
-		IsSolved:       d.IsSolved,
-		FineDecision:   d.FineDecision,
-	}
+func (c *CaseConverter) MapAssignedCaseToDto(d domain.AssignedCase) dto.Case {
+	cDto := c.MapDomainToDto(d.Case)
+	cDto.LeaseExpiresAt = &d.LeaseExpiresAt
+	return cDto
 }
 
 func (c *CaseConverter) MapCaseStatusToDto(d domain.CaseStatus) dto.CaseStatus {
 	assessments := make([]dto.CaseAssessment, 0)
 	for _, assessment := range d.CaseAssessments {
 		assessments = append(assessments, dto.CaseAssessment{
-			ExpertID: assessment.ExpertID, IsExpertSolve: assessment.IsExpertSolve, FineDecision: assessment.FineDecision},
-		)
+			ExpertID: assessment.ExpertID, IsExpertSolve: assessment.IsExpertSolve, FineDecision: assessment.FineDecision,
+			IsLeaseExpired: assessment.IsLeaseExpired,
+		})
+	}
+
+	notifications := make([]dto.NotificationDelivery, 0, len(d.Notifications))
+	for _, n := range d.Notifications {
+		notifications = append(notifications, dto.NotificationDelivery{
+			Channel: n.Channel, Status: n.Status, Attempts: n.Attempts, LastError: n.LastError,
+			CreatedAt: n.CreatedAt, UpdatedAt: n.UpdatedAt, DeliveredAt: n.DeliveredAt,
+		})
+	}
+
+	escalations := make([]dto.CaseEscalation, 0, len(d.Escalations))
+	for _, e := range d.Escalations {
+		escalations = append(escalations, dto.CaseEscalation{
+			FromSkill: e.FromSkill, ToSkill: e.ToSkill, Reason: string(e.Reason), EscalatedAt: e.EscalatedAt,
+		})
+	}
+
+	overrides := make([]dto.CaseOverride, 0, len(d.Overrides))
+	for _, o := range d.Overrides {
+		overrides = append(overrides, c.MapCaseOverrideToDto(o))
 	}
 
 	return dto.CaseStatus{
-		CaseID:          d.CaseID,
-		ViolationValue:  d.ViolationValue,
-		RequiredSkill:   d.RequiredSkill,
-		CaseDate:        d.CaseDate,
-		IsSolved:        d.IsSolved,
-		FineDecision:    d.FineDecision,
-		CaseAssessments: assessments,
+		CaseID:                   d.CaseID,
+		ViolationValue:           d.ViolationValue,
+		RequiredSkill:            d.RequiredSkill,
+		CaseDate:                 d.CaseDate,
+		IsSolved:                 d.IsSolved,
+		FineDecision:             d.FineDecision,
+		CaseAssessments:          assessments,
+		ReviewRequestedAt:        d.ReviewRequestedAt,
+		Escalations:              escalations,
+		Overrides:                overrides,
+		Notifications:            notifications,
+		NotificationsUnavailable: d.NotificationsUnavailable,
+	}
+}
+
+func (c *CaseConverter) MapReviewCasesToDto(reviewCases []domain.ReviewCase) []dto.ReviewCase {
+	dtos := make([]dto.ReviewCase, 0, len(reviewCases))
+	for _, rc := range reviewCases {
+		dtos = append(dtos, dto.ReviewCase{
+			CaseID:            rc.CaseID,
+			ViolationValue:    rc.ViolationValue,
+			RequiredSkill:     rc.RequiredSkill,
+			CaseDate:          rc.CaseDate,
+			ReviewRequestedAt: rc.ReviewRequestedAt,
+			PositiveDecisions: rc.PositiveDecisions,
+			NegativeDecisions: rc.NegativeDecisions,
+		})
+	}
+
+	return dtos
+}
+
+func (c *CaseConverter) MapCaseOverrideToDto(d domain.CaseOverride) dto.CaseOverride {
+	return dto.CaseOverride{
+		ID:              d.ID,
+		UserID:          d.UserID,
+		Action:          string(d.Action),
+		OldFineDecision: d.OldFineDecision,
+		NewFineDecision: d.NewFineDecision,
+		FineID:          d.FineID,
+		Reason:          d.Reason,
+		CreatedAt:       d.CreatedAt,
 	}
 }
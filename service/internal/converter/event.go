@@ -0,0 +1,70 @@
+package converter
+
+import (
+	"TrafficPolice/internal/domain"
+	"events"
+)
+
+// EventConverter maps domain to types of events, which are published for fine_notification
+type EventConverter struct {
+}
+
+func NewEventConverter() *EventConverter {
+	return &EventConverter{}
+}
+
+func (c *EventConverter) MapCaseToEvent(d domain.Case) events.Case {
+	var person *events.Person
+	if d.Transport.Person != nil {
+		person = &events.Person{
+			ID:             d.Transport.Person.ID,
+			PhoneNum:       d.Transport.Person.PhoneNum,
+			Email:          d.Transport.Person.Email,
+			VkID:           d.Transport.Person.VkID,
+			TgID:           d.Transport.Person.TgID,
+			NotifyChannels: d.Transport.Person.NotifyChannels,
+		}
+	}
+
+	return events.Case{
+		ID: d.ID,
+		Transport: events.Transport{
+			ID:     d.Transport.ID,
+			Chars:  d.Transport.Chars,
+			Num:    d.Transport.Num,
+			Region: d.Transport.Region,
+			Person: person,
+		},
+		Camera: events.Camera{
+			ID:           d.Camera.ID,
+			CameraTypeID: d.Camera.CameraType.ID,
+			Latitude:     d.Camera.Latitude,
+			Longitude:    d.Camera.Longitude,
+			ShortDesc:    d.Camera.ShortDesc,
+		},
+		Violation: events.Violation{
+			ID:         d.Violation.ID,
+			Name:       d.Violation.Name,
+			FineAmount: d.Violation.FineAmount,
+		},
+		ViolationValue: d.ViolationValue,
+		RequiredSkill:  int(d.RequiredSkill),
+		Date:           d.Date,
+		IsSolved:       d.IsSolved,
+		FineDecision:   d.FineDecision,
+	}
+}
+
+func (c *EventConverter) MapFineToEvent(d domain.Fine) events.Fine {
+	return events.Fine{
+		ID:             d.ID,
+		CaseID:         d.CaseID,
+		Reference:      d.Reference,
+		Amount:         d.Amount,
+		DiscountAmount: d.DiscountAmount,
+		DiscountUntil:  d.DiscountUntil,
+		IssuedAt:       d.IssuedAt,
+		DueAt:          d.DueAt,
+		Status:         string(d.Status),
+	}
+}
@@ -0,0 +1,48 @@
+package converter
+
+import (
+	"TrafficPolice/internal/domain"
+	"TrafficPolice/internal/transport/rest/dto"
+)
+
+type FineConverter struct {
+}
+
+func NewFineConverter() *FineConverter {
+	return &FineConverter{}
+}
+
+func (c *FineConverter) MapDomainToDto(d domain.Fine) dto.Fine {
+	return dto.Fine{
+		ID:             d.ID,
+		CaseID:         d.CaseID,
+		Reference:      d.Reference,
+		Amount:         d.Amount,
+		DiscountAmount: d.DiscountAmount,
+		DiscountUntil:  d.DiscountUntil,
+		IssuedAt:       d.IssuedAt,
+		DueAt:          d.DueAt,
+		Status:         string(d.Status),
+		PaymentID:      d.PaymentID,
+		PaidAmount:     d.PaidAmount,
+		PaidAt:         d.PaidAt,
+		CancelledAt:    d.CancelledAt,
+	}
+}
+
+func (c *FineConverter) MapSliceDomainToDto(domains []domain.Fine) []dto.Fine {
+	dtos := make([]dto.Fine, len(domains))
+	for i := range domains {
+		dtos[i] = c.MapDomainToDto(domains[i])
+	}
+	return dtos
+}
+
+func (c *FineConverter) MapPaymentDtoToDomain(d dto.FinePayment) domain.FinePayment {
+	return domain.FinePayment{
+		PaymentID: d.PaymentID,
+		Reference: d.Reference,
+		Amount:    d.Amount,
+		PaidAt:    d.PaidAt,
+	}
+}
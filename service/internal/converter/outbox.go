@@ -0,0 +1,47 @@
+package converter
+
+import (
+	"TrafficPolice/internal/domain"
+	"TrafficPolice/internal/transport/rest/dto"
+)
+
+const (
+	outboxFailedStatus = "failed"
+	outboxStuckStatus  = "stuck"
+)
+
+type OutboxConverter struct {
+}
+
+func NewOutboxConverter() *OutboxConverter {
+	return &OutboxConverter{}
+}
+
+// MapDomainToDto sets status failed for messages, which used all attempts, other not published
+// messages are stuck
+func (c *OutboxConverter) MapDomainToDto(d domain.OutboxMessage) dto.OutboxMessage {
+	status := outboxStuckStatus
+	if d.FailedAt != nil {
+		status = outboxFailedStatus
+	}
+
+	return dto.OutboxMessage{
+		ID:            d.ID,
+		Kind:          string(d.Kind),
+		Payload:       d.Payload,
+		Status:        status,
+		CreatedAt:     d.CreatedAt,
+		Attempts:      d.Attempts,
+		NextAttemptAt: d.NextAttemptAt,
+		LastError:     d.LastError,
+		FailedAt:      d.FailedAt,
+	}
+}
+
+func (c *OutboxConverter) MapSliceDomainToDto(domains []domain.OutboxMessage) []dto.OutboxMessage {
+	dtos := make([]dto.OutboxMessage, len(domains))
+	for i := range domains {
+		dtos[i] = c.MapDomainToDto(domains[i])
+	}
+	return dtos
+}
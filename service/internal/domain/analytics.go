@@ -5,6 +5,7 @@ import "time"
 type IntervalCase struct {
 	GotAt              time.Time
 	IsExpertSolve      bool
+	IsLeaseExpired     bool
 	ExpertFineDecision bool
 	CaseFineDecision   bool
 }
@@ -15,5 +16,6 @@ type AnalyticsInterval struct {
 	CorrectCnt           int
 	IncorrectCnt         int
 	UnknownCnt           int
+	ExpiredCnt           int
 	MaxConsecutiveSolved int
 }
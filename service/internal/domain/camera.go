@@ -1,11 +1,21 @@
 package domain
 
 type Camera struct {
-	ID         string
-	CameraType CameraType
-	Latitude   float64
-	Longitude  float64
-	ShortDesc  string
+	ID            string
+	CameraType    CameraType
+	Latitude      float64
+	Longitude     float64
+	ShortDesc     string
+	RedactRegions []RedactRegion
+}
+
+// RedactRegion is rectangle of camera image in pixels, which is blurred before image is shown
+// to experts or sent in fine notification. For example, sidewalk with bystanders
+type RedactRegion struct {
+	X      int
+	Y      int
+	Width  int
+	Height int
 }
 
 type CameraType struct {
@@ -18,3 +28,39 @@ type RegisterCamera struct {
 	Username string
 	Password string
 }
+
+const (
+	CaseFieldTransportChars  = "transport.chars"
+	CaseFieldTransportNum    = "transport.num"
+	CaseFieldTransportRegion = "transport.region"
+	CaseFieldCameraID        = "camera.id"
+	CaseFieldViolationID     = "violation.id"
+	CaseFieldViolationValue  = "violation_value"
+	CaseFieldRequiredSkill   = "required_skill"
+	CaseFieldDate            = "date"
+)
+
+// RequiredCaseFields must be mapped by every camera type mapping.
+// Camera id is resolved by parser before decoding, so it is optional
+var RequiredCaseFields = []string{
+	CaseFieldTransportChars,
+	CaseFieldTransportNum,
+	CaseFieldTransportRegion,
+	CaseFieldViolationID,
+	CaseFieldViolationValue,
+	CaseFieldRequiredSkill,
+	CaseFieldDate,
+}
+
+type CameraTypeMapping struct {
+	CameraTypeID string
+	Fields       []FieldMapping
+}
+
+// FieldMapping maps payload key to case field. Nested keys are separated by dot,
+// for example "transport.chars"
+type FieldMapping struct {
+	CaseField  string
+	PayloadKey string
+	Format     string
+}
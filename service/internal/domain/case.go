@@ -18,12 +18,26 @@ type Case struct {
 	Date           time.Time
 	IsSolved       bool
 	FineDecision   bool
+	HasImage       bool
+	// InReview is set, when case waits for decision of director
+	InReview bool
+}
+
+// CaseImageInfo is used to check, whether image of case can be uploaded
+type CaseImageInfo struct {
+	CameraID         string
+	CameraUserID     string
+	HasImage         bool
+	OverwriteAllowed bool
 }
 
 type CaseAssessment struct {
-	ExpertID      string
-	IsExpertSolve bool
-	FineDecision  bool
+	ExpertID       string
+	IsExpertSolve  bool
+	FineDecision   bool
+	IsLeaseExpired bool
+	// IsVoided is set, when decision was made before case was reopened by director
+	IsVoided bool
 }
 
 type CaseStatus struct {
@@ -35,4 +49,26 @@ type CaseStatus struct {
 	FineDecision    bool
 	SolvedAt        *time.Time
 	CaseAssessments []CaseAssessment
+	// ReviewRequestedAt is set, when case is sent to review of director. Escalations are ordered by time
+	ReviewRequestedAt *time.Time
+	Escalations       []CaseEscalation
+	// Overrides are corrections of case by director ordered by time
+	Overrides []CaseOverride
+	// Notifications are deliveries of fine notification by channels. NotificationsUnavailable is set,
+	// when fine_notification did not return deliveries
+	Notifications            []NotificationDelivery
+	NotificationsUnavailable bool
+}
+
+// NotificationDelivery is state of fine notification by one channel in fine_notification. Kind is fine
+// for notification about issued fine and cancellation for notification about cancelled fine
+type NotificationDelivery struct {
+	Kind        string
+	Channel     string
+	Status      string
+	Attempts    int
+	LastError   *string
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	DeliveredAt *time.Time
 }
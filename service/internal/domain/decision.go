@@ -12,12 +12,18 @@ type Decision struct {
 type FineDecisions struct {
 	PositiveDecisions int
 	NegativeDecisions int
+	// ExpiredLeases is count of experts, who did not decide until lease expired
+	ExpiredLeases int
 }
 
 type CaseDecisionInfo struct {
 	CaseID         string
 	ShouldSendFine bool
 	IsSolved       bool
+	// NotificationID is id of outbox message with fine notification, which is recorded with decision
+	NotificationID string
+	// FineID is id of fine, which is issued with decision
+	FineID string
 }
 type ExpertCaseDecision struct {
 	ExpertID string
@@ -0,0 +1,34 @@
+package domain
+
+import "time"
+
+type EscalationReason string
+
+const (
+	// NoConsensusEscalation is escalation after decision of expert, when experts left can not reach consensus
+	NoConsensusEscalation EscalationReason = "no_consensus"
+	// LeaseExpiredEscalation is escalation after expired lease, when experts left can not reach consensus
+	LeaseExpiredEscalation EscalationReason = "lease_expired"
+)
+
+// CaseEscalation is record of escalation log. ToSkill is nil, when required skill of case has reached
+// maximum skill and case is sent to review of director
+type CaseEscalation struct {
+	ID          string
+	CaseID      string
+	FromSkill   int
+	ToSkill     *int
+	Reason      EscalationReason
+	EscalatedAt time.Time
+}
+
+// ReviewCase is not solved case, which waits for decision of director, with decisions of experts
+type ReviewCase struct {
+	CaseID            string
+	ViolationValue    string
+	RequiredSkill     int
+	CaseDate          time.Time
+	ReviewRequestedAt time.Time
+	PositiveDecisions int
+	NegativeDecisions int
+}
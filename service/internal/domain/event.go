@@ -0,0 +1,140 @@
+package domain
+
+import (
+	"slices"
+	"time"
+)
+
+type EventType string
+
+// Types of domain events. Event is recorded in outbox as message of kind of its type
+const (
+	CaseCreatedEvent          EventType = "case.created"
+	CaseAssignedEvent         EventType = "case.assigned"
+	CaseDecisionRecordedEvent EventType = "case.decision_recorded"
+	CaseSolvedEvent           EventType = "case.solved"
+	CaseEscalatedEvent        EventType = "case.escalated"
+	CaseReviewRequestedEvent  EventType = "case.review_requested"
+	CaseOverriddenEvent       EventType = "case.overridden"
+	ExpertConfirmedEvent      EventType = "expert.confirmed"
+	ExpertSkillChangedEvent   EventType = "expert.skill_changed"
+)
+
+var EventTypes = []EventType{
+	CaseCreatedEvent,
+	CaseAssignedEvent,
+	CaseDecisionRecordedEvent,
+	CaseSolvedEvent,
+	CaseEscalatedEvent,
+	CaseReviewRequestedEvent,
+	CaseOverriddenEvent,
+	ExpertConfirmedEvent,
+	ExpertSkillChangedEvent,
+}
+
+// IsEvent reports whether message of kind is domain event
+func (k OutboxKind) IsEvent() bool {
+	return slices.Contains(EventTypes, EventType(k))
+}
+
+// Event is payload of domain event, which is marshalled to data of published event
+type Event interface {
+	EventType() EventType
+}
+
+type CaseCreated struct {
+	CaseID         string    `json:"case_id"`
+	CameraID       string    `json:"camera_id"`
+	ViolationID    string    `json:"violation_id"`
+	ViolationValue string    `json:"violation_value"`
+	RequiredSkill  int       `json:"required_skill"`
+	Date           time.Time `json:"date"`
+	HasImage       bool      `json:"has_image"`
+}
+
+func (CaseCreated) EventType() EventType { return CaseCreatedEvent }
+
+type CaseAssigned struct {
+	CaseID         string    `json:"case_id"`
+	ExpertID       string    `json:"expert_id"`
+	LeaseExpiresAt time.Time `json:"lease_expires_at"`
+}
+
+func (CaseAssigned) EventType() EventType { return CaseAssignedEvent }
+
+type CaseDecisionRecorded struct {
+	CaseID       string `json:"case_id"`
+	ExpertID     string `json:"expert_id"`
+	ExpertSkill  int    `json:"expert_skill"`
+	FineDecision bool   `json:"fine_decision"`
+}
+
+func (CaseDecisionRecorded) EventType() EventType { return CaseDecisionRecordedEvent }
+
+// CaseSolved contains fine, when fine is issued for case
+type CaseSolved struct {
+	CaseID       string      `json:"case_id"`
+	FineDecision bool        `json:"fine_decision"`
+	Fine         *IssuedFine `json:"fine,omitempty"`
+}
+
+func (CaseSolved) EventType() EventType { return CaseSolvedEvent }
+
+type IssuedFine struct {
+	FineID    string    `json:"fine_id"`
+	Reference string    `json:"reference"`
+	Amount    int       `json:"amount"`
+	DueAt     time.Time `json:"due_at"`
+}
+
+type CaseEscalated struct {
+	CaseID    string           `json:"case_id"`
+	FromSkill int              `json:"from_skill"`
+	ToSkill   int              `json:"to_skill"`
+	Reason    EscalationReason `json:"reason"`
+}
+
+func (CaseEscalated) EventType() EventType { return CaseEscalatedEvent }
+
+// CaseReviewRequested is sent instead of CaseEscalated, when required skill of case has reached maximum skill
+type CaseReviewRequested struct {
+	CaseID string           `json:"case_id"`
+	Skill  int              `json:"skill"`
+	Reason EscalationReason `json:"reason"`
+}
+
+func (CaseReviewRequested) EventType() EventType { return CaseReviewRequestedEvent }
+
+// CaseOverridden is sent, when director corrects solved case. FineDecision is new fine decision
+// of case, it is nil, when fine decision is not changed or case is reopened
+type CaseOverridden struct {
+	CaseID       string         `json:"case_id"`
+	Action       OverrideAction `json:"action"`
+	FineDecision *bool          `json:"fine_decision,omitempty"`
+	Reason       string         `json:"reason"`
+}
+
+func (CaseOverridden) EventType() EventType { return CaseOverriddenEvent }
+
+type ExpertConfirmed struct {
+	ExpertID string `json:"expert_id"`
+}
+
+func (ExpertConfirmed) EventType() EventType { return ExpertConfirmedEvent }
+
+type SkillChangeReason string
+
+const (
+	// ReportPeriodSkillChange is change of skill by rating of experts at the end of report period
+	ReportPeriodSkillChange SkillChangeReason = "report_period"
+	DirectorSkillChange     SkillChangeReason = "director"
+)
+
+type ExpertSkillChanged struct {
+	ExpertID string            `json:"expert_id"`
+	OldSkill int               `json:"old_skill"`
+	NewSkill int               `json:"new_skill"`
+	Reason   SkillChangeReason `json:"reason"`
+}
+
+func (ExpertSkillChanged) EventType() EventType { return ExpertSkillChangedEvent }
@@ -2,12 +2,22 @@ package domain
 
 import "time"
 
+// ExpertCase is assignment of case to expert. Expert must decide until lease expires,
+// otherwise assignment is marked expired and stays in history of expert
 type ExpertCase struct {
-	ExpertCaseID  string
-	ExpertID      string
-	CaseID        string
-	IsExpertSolve bool
-	FineDecision  bool
-	GotAt         time.Time
-	SolvedAt      time.Time
+	ExpertCaseID   string
+	ExpertID       string
+	CaseID         string
+	IsExpertSolve  bool
+	FineDecision   bool
+	GotAt          time.Time
+	SolvedAt       time.Time
+	LeaseExpiresAt time.Time
+	ExpiredAt      time.Time
+}
+
+// AssignedCase is case given to expert with expiry of lease
+type AssignedCase struct {
+	Case           Case
+	LeaseExpiresAt time.Time
 }
@@ -0,0 +1,46 @@
+package domain
+
+import "time"
+
+type FineStatus string
+
+const (
+	FineIssued    FineStatus = "issued"
+	FinePaid      FineStatus = "paid"
+	FineOverdue   FineStatus = "overdue"
+	FineCancelled FineStatus = "cancelled"
+)
+
+// Fine is issued for case with fine decision. Fine is paid with discount amount until DiscountUntil,
+// then with full amount. Issued fine becomes overdue after DueAt
+type Fine struct {
+	ID             string
+	CaseID         string
+	Reference      string
+	Amount         int
+	DiscountAmount int
+	DiscountUntil  *time.Time
+	IssuedAt       time.Time
+	DueAt          time.Time
+	Status         FineStatus
+	PaymentID      *string
+	PaidAmount     *int
+	PaidAt         *time.Time
+	CancelledAt    *time.Time
+}
+
+// AmountDue is amount, which should be paid at time t
+func (f Fine) AmountDue(t time.Time) int {
+	if f.DiscountUntil != nil && !t.After(*f.DiscountUntil) {
+		return f.DiscountAmount
+	}
+	return f.Amount
+}
+
+// FinePayment is notification of payment provider about paid fine
+type FinePayment struct {
+	PaymentID string
+	Reference string
+	Amount    int
+	PaidAt    time.Time
+}
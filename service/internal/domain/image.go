@@ -0,0 +1,49 @@
+package domain
+
+import "time"
+
+type ImageOwnerType string
+
+const (
+	CaseImageOwner   ImageOwnerType = "case"
+	ExpertImageOwner ImageOwnerType = "expert"
+)
+
+// ImageVariant is version of image. Original is uploaded image, other variants are derived from it
+type ImageVariant string
+
+const (
+	OriginalImage ImageVariant = "original"
+	// RedactedImage is full size image without metadata and with blurred redact regions of camera
+	RedactedImage ImageVariant = "redacted"
+	// WebImage is redacted image downscaled for web
+	WebImage ImageVariant = "web"
+	// ThumbImage is redacted image downscaled for previews
+	ThumbImage ImageVariant = "thumb"
+)
+
+// ImageExtensions maps content type of supported images to file extension
+var ImageExtensions = map[string]string{
+	"image/png":  "png",
+	"image/jpeg": "jpeg",
+}
+
+type ImageOwner struct {
+	Type ImageOwnerType
+	ID   string
+}
+
+// ImageMeta links variant of owner image with blob in storage. Blob key is SHA-256 of image content
+type ImageMeta struct {
+	Owner       ImageOwner
+	Variant     ImageVariant
+	BlobKey     string
+	ContentType string
+	Size        int64
+	CreatedAt   time.Time
+}
+
+type Image struct {
+	Data        []byte
+	ContentType string
+}
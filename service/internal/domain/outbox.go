@@ -0,0 +1,34 @@
+package domain
+
+import "time"
+
+type OutboxKind string
+
+const (
+	// FineNotificationKind is message about fine, which is sent to violator
+	FineNotificationKind OutboxKind = "fine_notification"
+	// FineCancellationKind is message about cancelled fine, which is sent to violator
+	FineCancellationKind OutboxKind = "fine_cancellation"
+)
+
+// OutboxMessage is recorded in transaction of state change, which message is about,
+// and is marked published after it is delivered to broker
+type OutboxMessage struct {
+	ID            string
+	Kind          OutboxKind
+	Payload       []byte
+	CreatedAt     time.Time
+	PublishedAt   *time.Time
+	Attempts      int
+	NextAttemptAt time.Time
+	LastError     *string
+	// FailedAt is set, when relay used all attempts. Failed message is published only after replay
+	FailedAt *time.Time
+}
+
+// FineNotificationPayload is payload of FineNotificationKind and FineCancellationKind messages.
+// FineID is set in cancellation, it is id of cancelled fine
+type FineNotificationPayload struct {
+	CaseID string `json:"case_id"`
+	FineID string `json:"fine_id,omitempty"`
+}
@@ -0,0 +1,28 @@
+package domain
+
+import "time"
+
+type OverrideAction string
+
+const (
+	// OverrideDecisionAction changes fine decision of solved case
+	OverrideDecisionAction OverrideAction = "override_decision"
+	// VoidFineAction cancels fine of case, which was issued in error, fine decision of case is kept
+	VoidFineAction OverrideAction = "void_fine"
+	// ReopenCaseAction returns solved case to experts, decisions of experts are voided
+	ReopenCaseAction OverrideAction = "reopen_case"
+)
+
+// CaseOverride is record of audit log of corrections by director. NewFineDecision is nil, when fine
+// decision is not changed or case is reopened. FineID is fine, which was cancelled or issued by override
+type CaseOverride struct {
+	ID              string
+	CaseID          string
+	UserID          string
+	Action          OverrideAction
+	OldFineDecision bool
+	NewFineDecision *bool
+	FineID          *string
+	Reason          string
+	CreatedAt       time.Time
+}
@@ -6,4 +6,6 @@ type Person struct {
 	Email    string
 	VkID     string
 	TgID     string
+	// NotifyChannels are channels of fine notification in order of preference, empty means default order
+	NotifyChannels []string
 }
@@ -18,3 +18,10 @@ type UpdateCompetenceSkill struct {
 	ExpertID       string
 	ShouldIncrease bool
 }
+
+// SkillChange is competence skill of expert before and after update
+type SkillChange struct {
+	ExpertID string
+	OldSkill int
+	NewSkill int
+}
@@ -2,4 +2,7 @@ package errs
 
 import "github.com/lib/pq"
 
-const ForeignKeyViolationErrorCode = pq.ErrorCode("23503")
+const (
+	ForeignKeyViolationErrorCode = pq.ErrorCode("23503")
+	UniqueViolationErrorCode     = pq.ErrorCode("23505")
+)
@@ -3,10 +3,11 @@ package errs
 import "errors"
 
 var (
-	ErrNoRows        = errors.New("no rows")
-	ErrAlreadyExists = errors.New("already exists")
-	ErrInvalidPass   = errors.New("invalid password")
-	ErrEmptyPayload  = errors.New("empty payload")
+	ErrNoRows         = errors.New("no rows")
+	ErrAlreadyExists  = errors.New("already exists")
+	ErrInvalidPass    = errors.New("invalid password")
+	ErrEmptyPayload   = errors.New("empty payload")
+	ErrInvalidPayload = errors.New("invalid payload")
 
 	ErrUnknownCameraID       = errors.New("unknown camera id")
 	ErrUnknownCameraType     = errors.New("unknown camera type")
@@ -15,14 +16,39 @@ var (
 	ErrInvalidViolationID    = errors.New("invalid violation id")
 	ErrInvalidRelevantParams = errors.New("invalid relevant params")
 
+	ErrCameraTypeNotExists = errors.New("camera type not exists")
+	ErrNoCameraTypeMapping = errors.New("no camera type mapping")
+	ErrInvalidMapping      = errors.New("invalid camera type mapping")
+
 	ErrUserNotExists = errors.New("user not exists")
 
 	ErrNoLastNotSolvedCase = errors.New("no last not solved case")
 	ErrNoNotSolvedCase     = errors.New("no not solved case")
+	ErrCaseNotAssigned     = errors.New("case is not assigned to expert or lease expired")
+	ErrCaseAlreadySolved   = errors.New("case is already solved")
+	ErrCaseNotInReview     = errors.New("case is not in review of director")
+	ErrCaseNotSolved       = errors.New("case is not solved")
+	ErrDecisionNotChanged  = errors.New("case already has this fine decision")
 
 	ErrNoCase      = errors.New("no case")
 	ErrNoTransport = errors.New("no transport")
 	ErrNoImage     = errors.New("no image")
 
+	ErrInvalidImage         = errors.New("invalid image")
+	ErrImageTooLarge        = errors.New("image is too large")
+	ErrNotCaseOwner         = errors.New("case is created by another camera")
+	ErrImageAlreadyUploaded = errors.New("image is already uploaded")
+
 	ErrExpertNotExists = errors.New("expert not exists")
+
+	ErrNoOutboxMessage = errors.New("no not published outbox message")
+
+	ErrNoFine                = errors.New("no fine")
+	ErrFineCancelled         = errors.New("fine is cancelled")
+	ErrFineAlreadyPaid       = errors.New("fine is already paid by another payment")
+	ErrInsufficientPayment   = errors.New("payment amount is less than amount due")
+	ErrFineCannotBeCancelled = errors.New("paid fine can not be cancelled")
+	ErrFineAlreadyIssued     = errors.New("case already has not cancelled fine")
+
+	ErrNotificationDisabled = errors.New("tokens for fine_notification are disabled")
 )
@@ -4,6 +4,7 @@ package mocks
 
 import (
 	domain "TrafficPolice/internal/domain"
+	context "context"
 
 	mock "github.com/stretchr/testify/mock"
 
@@ -15,17 +16,17 @@ type AuthRepo struct {
 	mock.Mock
 }
 
-// CheckUserExists provides a mock function with given fields: username
-func (_m *AuthRepo) CheckUserExists(username string) bool {
-	ret := _m.Called(username)
+// CheckUserExists provides a mock function with given fields: ctx, username
+func (_m *AuthRepo) CheckUserExists(ctx context.Context, username string) bool {
+	ret := _m.Called(ctx, username)
 
 	if len(ret) == 0 {
 		panic("no return value specified for CheckUserExists")
 	}
 
 	var r0 bool
-	if rf, ok := ret.Get(0).(func(string) bool); ok {
-		r0 = rf(username)
+	if rf, ok := ret.Get(0).(func(context.Context, string) bool); ok {
+		r0 = rf(ctx, username)
 	} else {
 		r0 = ret.Get(0).(bool)
 	}
@@ -33,17 +34,17 @@ func (_m *AuthRepo) CheckUserExists(username string) bool {
 	return r0
 }
 
-// ConfirmExpert provides a mock function with given fields: data
-func (_m *AuthRepo) ConfirmExpert(data domain.ConfirmExpert) error {
-	ret := _m.Called(data)
+// ConfirmExpert provides a mock function with given fields: ctx, data
+func (_m *AuthRepo) ConfirmExpert(ctx context.Context, data domain.ConfirmExpert) error {
+	ret := _m.Called(ctx, data)
 
 	if len(ret) == 0 {
 		panic("no return value specified for ConfirmExpert")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(domain.ConfirmExpert) error); ok {
-		r0 = rf(data)
+	if rf, ok := ret.Get(0).(func(context.Context, domain.ConfirmExpert) error); ok {
+		r0 = rf(ctx, data)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -51,9 +52,9 @@ func (_m *AuthRepo) ConfirmExpert(data domain.ConfirmExpert) error {
 	return r0
 }
 
-// InsertCamera provides a mock function with given fields: camera, userID
-func (_m *AuthRepo) InsertCamera(camera domain.Camera, userID uuid.UUID) (string, error) {
-	ret := _m.Called(camera, userID)
+// InsertCamera provides a mock function with given fields: ctx, camera, userID
+func (_m *AuthRepo) InsertCamera(ctx context.Context, camera domain.Camera, userID uuid.UUID) (string, error) {
+	ret := _m.Called(ctx, camera, userID)
 
 	if len(ret) == 0 {
 		panic("no return value specified for InsertCamera")
@@ -61,17 +62,17 @@ func (_m *AuthRepo) InsertCamera(camera domain.Camera, userID uuid.UUID) (string
 
 	var r0 string
 	var r1 error
-	if rf, ok := ret.Get(0).(func(domain.Camera, uuid.UUID) (string, error)); ok {
-		return rf(camera, userID)
+	if rf, ok := ret.Get(0).(func(context.Context, domain.Camera, uuid.UUID) (string, error)); ok {
+		return rf(ctx, camera, userID)
 	}
-	if rf, ok := ret.Get(0).(func(domain.Camera, uuid.UUID) string); ok {
-		r0 = rf(camera, userID)
+	if rf, ok := ret.Get(0).(func(context.Context, domain.Camera, uuid.UUID) string); ok {
+		r0 = rf(ctx, camera, userID)
 	} else {
 		r0 = ret.Get(0).(string)
 	}
 
-	if rf, ok := ret.Get(1).(func(domain.Camera, uuid.UUID) error); ok {
-		r1 = rf(camera, userID)
+	if rf, ok := ret.Get(1).(func(context.Context, domain.Camera, uuid.UUID) error); ok {
+		r1 = rf(ctx, camera, userID)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -79,17 +80,17 @@ func (_m *AuthRepo) InsertCamera(camera domain.Camera, userID uuid.UUID) (string
 	return r0, r1
 }
 
-// InsertDirector provides a mock function with given fields: director
-func (_m *AuthRepo) InsertDirector(director domain.Director) error {
-	ret := _m.Called(director)
+// InsertDirector provides a mock function with given fields: ctx, director
+func (_m *AuthRepo) InsertDirector(ctx context.Context, director domain.Director) error {
+	ret := _m.Called(ctx, director)
 
 	if len(ret) == 0 {
 		panic("no return value specified for InsertDirector")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(domain.Director) error); ok {
-		r0 = rf(director)
+	if rf, ok := ret.Get(0).(func(context.Context, domain.Director) error); ok {
+		r0 = rf(ctx, director)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -97,17 +98,17 @@ func (_m *AuthRepo) InsertDirector(director domain.Director) error {
 	return r0
 }
 
-// InsertExpert provides a mock function with given fields: expert
-func (_m *AuthRepo) InsertExpert(expert domain.Expert) error {
-	ret := _m.Called(expert)
+// InsertExpert provides a mock function with given fields: ctx, expert
+func (_m *AuthRepo) InsertExpert(ctx context.Context, expert domain.Expert) error {
+	ret := _m.Called(ctx, expert)
 
 	if len(ret) == 0 {
 		panic("no return value specified for InsertExpert")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(domain.Expert) error); ok {
-		r0 = rf(expert)
+	if rf, ok := ret.Get(0).(func(context.Context, domain.Expert) error); ok {
+		r0 = rf(ctx, expert)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -115,17 +116,17 @@ func (_m *AuthRepo) InsertExpert(expert domain.Expert) error {
 	return r0
 }
 
-// InsertUser provides a mock function with given fields: user
-func (_m *AuthRepo) InsertUser(user domain.UserInfo) error {
-	ret := _m.Called(user)
+// InsertUser provides a mock function with given fields: ctx, user
+func (_m *AuthRepo) InsertUser(ctx context.Context, user domain.UserInfo) error {
+	ret := _m.Called(ctx, user)
 
 	if len(ret) == 0 {
 		panic("no return value specified for InsertUser")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(domain.UserInfo) error); ok {
-		r0 = rf(user)
+	if rf, ok := ret.Get(0).(func(context.Context, domain.UserInfo) error); ok {
+		r0 = rf(ctx, user)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -133,9 +134,9 @@ func (_m *AuthRepo) InsertUser(user domain.UserInfo) error {
 	return r0
 }
 
-// SignIn provides a mock function with given fields: username
-func (_m *AuthRepo) SignIn(username string) (domain.UserInfo, error) {
-	ret := _m.Called(username)
+// SignIn provides a mock function with given fields: ctx, username
+func (_m *AuthRepo) SignIn(ctx context.Context, username string) (domain.UserInfo, error) {
+	ret := _m.Called(ctx, username)
 
 	if len(ret) == 0 {
 		panic("no return value specified for SignIn")
@@ -143,17 +144,17 @@ func (_m *AuthRepo) SignIn(username string) (domain.UserInfo, error) {
 
 	var r0 domain.UserInfo
 	var r1 error
-	if rf, ok := ret.Get(0).(func(string) (domain.UserInfo, error)); ok {
-		return rf(username)
+	if rf, ok := ret.Get(0).(func(context.Context, string) (domain.UserInfo, error)); ok {
+		return rf(ctx, username)
 	}
-	if rf, ok := ret.Get(0).(func(string) domain.UserInfo); ok {
-		r0 = rf(username)
+	if rf, ok := ret.Get(0).(func(context.Context, string) domain.UserInfo); ok {
+		r0 = rf(ctx, username)
 	} else {
 		r0 = ret.Get(0).(domain.UserInfo)
 	}
 
-	if rf, ok := ret.Get(1).(func(string) error); ok {
-		r1 = rf(username)
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, username)
 	} else {
 		r1 = ret.Error(1)
 	}
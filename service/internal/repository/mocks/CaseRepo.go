@@ -4,6 +4,7 @@ package mocks
 
 import (
 	domain "TrafficPolice/internal/domain"
+	context "context"
 
 	mock "github.com/stretchr/testify/mock"
 
@@ -15,9 +16,9 @@ type CaseRepo struct {
 	mock.Mock
 }
 
-// GetCaseByID provides a mock function with given fields: caseID
-func (_m *CaseRepo) GetCaseByID(caseID string) (domain.Case, error) {
-	ret := _m.Called(caseID)
+// GetCaseByID provides a mock function with given fields: ctx, caseID
+func (_m *CaseRepo) GetCaseByID(ctx context.Context, caseID string) (domain.Case, error) {
+	ret := _m.Called(ctx, caseID)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetCaseByID")
@@ -25,17 +26,17 @@ func (_m *CaseRepo) GetCaseByID(caseID string) (domain.Case, error) {
 
 	var r0 domain.Case
 	var r1 error
-	if rf, ok := ret.Get(0).(func(string) (domain.Case, error)); ok {
-		return rf(caseID)
+	if rf, ok := ret.Get(0).(func(context.Context, string) (domain.Case, error)); ok {
+		return rf(ctx, caseID)
 	}
-	if rf, ok := ret.Get(0).(func(string) domain.Case); ok {
-		r0 = rf(caseID)
+	if rf, ok := ret.Get(0).(func(context.Context, string) domain.Case); ok {
+		r0 = rf(ctx, caseID)
 	} else {
 		r0 = ret.Get(0).(domain.Case)
 	}
 
-	if rf, ok := ret.Get(1).(func(string) error); ok {
-		r1 = rf(caseID)
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, caseID)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -43,9 +44,37 @@ func (_m *CaseRepo) GetCaseByID(caseID string) (domain.Case, error) {
 	return r0, r1
 }
 
-// GetCaseWithPersonInfo provides a mock function with given fields: caseID
-func (_m *CaseRepo) GetCaseWithPersonInfo(caseID string) (domain.Case, error) {
-	ret := _m.Called(caseID)
+// GetCaseImageInfo provides a mock function with given fields: ctx, caseID
+func (_m *CaseRepo) GetCaseImageInfo(ctx context.Context, caseID string) (domain.CaseImageInfo, error) {
+	ret := _m.Called(ctx, caseID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetCaseImageInfo")
+	}
+
+	var r0 domain.CaseImageInfo
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (domain.CaseImageInfo, error)); ok {
+		return rf(ctx, caseID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) domain.CaseImageInfo); ok {
+		r0 = rf(ctx, caseID)
+	} else {
+		r0 = ret.Get(0).(domain.CaseImageInfo)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, caseID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetCaseWithPersonInfo provides a mock function with given fields: ctx, caseID
+func (_m *CaseRepo) GetCaseWithPersonInfo(ctx context.Context, caseID string) (domain.Case, error) {
+	ret := _m.Called(ctx, caseID)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetCaseWithPersonInfo")
@@ -53,17 +82,17 @@ func (_m *CaseRepo) GetCaseWithPersonInfo(caseID string) (domain.Case, error) {
 
 	var r0 domain.Case
 	var r1 error
-	if rf, ok := ret.Get(0).(func(string) (domain.Case, error)); ok {
-		return rf(caseID)
+	if rf, ok := ret.Get(0).(func(context.Context, string) (domain.Case, error)); ok {
+		return rf(ctx, caseID)
 	}
-	if rf, ok := ret.Get(0).(func(string) domain.Case); ok {
-		r0 = rf(caseID)
+	if rf, ok := ret.Get(0).(func(context.Context, string) domain.Case); ok {
+		r0 = rf(ctx, caseID)
 	} else {
 		r0 = ret.Get(0).(domain.Case)
 	}
 
-	if rf, ok := ret.Get(1).(func(string) error); ok {
-		r1 = rf(caseID)
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, caseID)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -71,9 +100,9 @@ func (_m *CaseRepo) GetCaseWithPersonInfo(caseID string) (domain.Case, error) {
 	return r0, r1
 }
 
-// InsertCase provides a mock function with given fields: c
-func (_m *CaseRepo) InsertCase(c domain.Case) (string, error) {
-	ret := _m.Called(c)
+// InsertCase provides a mock function with given fields: ctx, c
+func (_m *CaseRepo) InsertCase(ctx context.Context, c domain.Case) (string, error) {
+	ret := _m.Called(ctx, c)
 
 	if len(ret) == 0 {
 		panic("no return value specified for InsertCase")
@@ -81,17 +110,109 @@ func (_m *CaseRepo) InsertCase(c domain.Case) (string, error) {
 
 	var r0 string
 	var r1 error
-	if rf, ok := ret.Get(0).(func(domain.Case) (string, error)); ok {
-		return rf(c)
+	if rf, ok := ret.Get(0).(func(context.Context, domain.Case) (string, error)); ok {
+		return rf(ctx, c)
 	}
-	if rf, ok := ret.Get(0).(func(domain.Case) string); ok {
-		r0 = rf(c)
+	if rf, ok := ret.Get(0).(func(context.Context, domain.Case) string); ok {
+		r0 = rf(ctx, c)
 	} else {
 		r0 = ret.Get(0).(string)
 	}
 
-	if rf, ok := ret.Get(1).(func(domain.Case) error); ok {
-		r1 = rf(c)
+	if rf, ok := ret.Get(1).(func(context.Context, domain.Case) error); ok {
+		r1 = rf(ctx, c)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// InsertEscalation provides a mock function with given fields: ctx, escalation
+func (_m *CaseRepo) InsertEscalation(ctx context.Context, escalation domain.CaseEscalation) error {
+	ret := _m.Called(ctx, escalation)
+
+	if len(ret) == 0 {
+		panic("no return value specified for InsertEscalation")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, domain.CaseEscalation) error); ok {
+		r0 = rf(ctx, escalation)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// InsertOverride provides a mock function with given fields: ctx, override
+func (_m *CaseRepo) InsertOverride(ctx context.Context, override domain.CaseOverride) error {
+	ret := _m.Called(ctx, override)
+
+	if len(ret) == 0 {
+		panic("no return value specified for InsertOverride")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, domain.CaseOverride) error); ok {
+		r0 = rf(ctx, override)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// LockCase provides a mock function with given fields: ctx, caseID
+func (_m *CaseRepo) LockCase(ctx context.Context, caseID string) (domain.Case, error) {
+	ret := _m.Called(ctx, caseID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for LockCase")
+	}
+
+	var r0 domain.Case
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (domain.Case, error)); ok {
+		return rf(ctx, caseID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) domain.Case); ok {
+		r0 = rf(ctx, caseID)
+	} else {
+		r0 = ret.Get(0).(domain.Case)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, caseID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// LockCaseImageInfo provides a mock function with given fields: ctx, caseID
+func (_m *CaseRepo) LockCaseImageInfo(ctx context.Context, caseID string) (domain.CaseImageInfo, error) {
+	ret := _m.Called(ctx, caseID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for LockCaseImageInfo")
+	}
+
+	var r0 domain.CaseImageInfo
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (domain.CaseImageInfo, error)); ok {
+		return rf(ctx, caseID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) domain.CaseImageInfo); ok {
+		r0 = rf(ctx, caseID)
+	} else {
+		r0 = ret.Get(0).(domain.CaseImageInfo)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, caseID)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -99,17 +220,89 @@ func (_m *CaseRepo) InsertCase(c domain.Case) (string, error) {
 	return r0, r1
 }
 
-// SetCaseFineDecision provides a mock function with given fields: caseID, fineDecision, solvedAt
-func (_m *CaseRepo) SetCaseFineDecision(caseID string, fineDecision bool, solvedAt time.Time) error {
-	ret := _m.Called(caseID, fineDecision, solvedAt)
+// ReopenCase provides a mock function with given fields: ctx, caseID
+func (_m *CaseRepo) ReopenCase(ctx context.Context, caseID string) error {
+	ret := _m.Called(ctx, caseID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReopenCase")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, caseID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RequestCaseReview provides a mock function with given fields: ctx, caseID, requestedAt
+func (_m *CaseRepo) RequestCaseReview(ctx context.Context, caseID string, requestedAt time.Time) error {
+	ret := _m.Called(ctx, caseID, requestedAt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RequestCaseReview")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time) error); ok {
+		r0 = rf(ctx, caseID, requestedAt)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SetCaseFineDecision provides a mock function with given fields: ctx, caseID, fineDecision, solvedAt
+func (_m *CaseRepo) SetCaseFineDecision(ctx context.Context, caseID string, fineDecision bool, solvedAt time.Time) error {
+	ret := _m.Called(ctx, caseID, fineDecision, solvedAt)
 
 	if len(ret) == 0 {
 		panic("no return value specified for SetCaseFineDecision")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(string, bool, time.Time) error); ok {
-		r0 = rf(caseID, fineDecision, solvedAt)
+	if rf, ok := ret.Get(0).(func(context.Context, string, bool, time.Time) error); ok {
+		r0 = rf(ctx, caseID, fineDecision, solvedAt)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SetCaseHasImage provides a mock function with given fields: ctx, caseID
+func (_m *CaseRepo) SetCaseHasImage(ctx context.Context, caseID string) error {
+	ret := _m.Called(ctx, caseID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetCaseHasImage")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, caseID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SetCaseImageOverwriteAllowed provides a mock function with given fields: ctx, caseID, allowed
+func (_m *CaseRepo) SetCaseImageOverwriteAllowed(ctx context.Context, caseID string, allowed bool) error {
+	ret := _m.Called(ctx, caseID, allowed)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetCaseImageOverwriteAllowed")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, bool) error); ok {
+		r0 = rf(ctx, caseID, allowed)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -117,17 +310,17 @@ func (_m *CaseRepo) SetCaseFineDecision(caseID string, fineDecision bool, solved
 	return r0
 }
 
-// UpdateCaseRequiredSkill provides a mock function with given fields: caseID, requiredSkill
-func (_m *CaseRepo) UpdateCaseRequiredSkill(caseID string, requiredSkill int) error {
-	ret := _m.Called(caseID, requiredSkill)
+// UpdateCaseRequiredSkill provides a mock function with given fields: ctx, caseID, requiredSkill
+func (_m *CaseRepo) UpdateCaseRequiredSkill(ctx context.Context, caseID string, requiredSkill int) error {
+	ret := _m.Called(ctx, caseID, requiredSkill)
 
 	if len(ret) == 0 {
 		panic("no return value specified for UpdateCaseRequiredSkill")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(string, int) error); ok {
-		r0 = rf(caseID, requiredSkill)
+	if rf, ok := ret.Get(0).(func(context.Context, string, int) error); ok {
+		r0 = rf(ctx, caseID, requiredSkill)
 	} else {
 		r0 = ret.Error(0)
 	}
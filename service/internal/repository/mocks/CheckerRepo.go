@@ -2,16 +2,20 @@
 
 package mocks
 
-import mock "github.com/stretchr/testify/mock"
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+)
 
 // CheckerRepo is an autogenerated mock type for the CheckerRepo type
 type CheckerRepo struct {
 	mock.Mock
 }
 
-// CheckExpertExists provides a mock function with given fields: expertID
-func (_m *CheckerRepo) CheckExpertExists(expertID string) (bool, error) {
-	ret := _m.Called(expertID)
+// CheckExpertExists provides a mock function with given fields: ctx, expertID
+func (_m *CheckerRepo) CheckExpertExists(ctx context.Context, expertID string) (bool, error) {
+	ret := _m.Called(ctx, expertID)
 
 	if len(ret) == 0 {
 		panic("no return value specified for CheckExpertExists")
@@ -19,17 +23,17 @@ func (_m *CheckerRepo) CheckExpertExists(expertID string) (bool, error) {
 
 	var r0 bool
 	var r1 error
-	if rf, ok := ret.Get(0).(func(string) (bool, error)); ok {
-		return rf(expertID)
+	if rf, ok := ret.Get(0).(func(context.Context, string) (bool, error)); ok {
+		return rf(ctx, expertID)
 	}
-	if rf, ok := ret.Get(0).(func(string) bool); ok {
-		r0 = rf(expertID)
+	if rf, ok := ret.Get(0).(func(context.Context, string) bool); ok {
+		r0 = rf(ctx, expertID)
 	} else {
 		r0 = ret.Get(0).(bool)
 	}
 
-	if rf, ok := ret.Get(1).(func(string) error); ok {
-		r1 = rf(expertID)
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, expertID)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -4,6 +4,7 @@ package mocks
 
 import (
 	domain "TrafficPolice/internal/domain"
+	context "context"
 
 	mock "github.com/stretchr/testify/mock"
 
@@ -15,9 +16,9 @@ type DirectorRepo struct {
 	mock.Mock
 }
 
-// GetCase provides a mock function with given fields: caseID
-func (_m *DirectorRepo) GetCase(caseID string) (domain.CaseStatus, error) {
-	ret := _m.Called(caseID)
+// GetCase provides a mock function with given fields: ctx, caseID
+func (_m *DirectorRepo) GetCase(ctx context.Context, caseID string) (domain.CaseStatus, error) {
+	ret := _m.Called(ctx, caseID)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetCase")
@@ -25,17 +26,17 @@ func (_m *DirectorRepo) GetCase(caseID string) (domain.CaseStatus, error) {
 
 	var r0 domain.CaseStatus
 	var r1 error
-	if rf, ok := ret.Get(0).(func(string) (domain.CaseStatus, error)); ok {
-		return rf(caseID)
+	if rf, ok := ret.Get(0).(func(context.Context, string) (domain.CaseStatus, error)); ok {
+		return rf(ctx, caseID)
 	}
-	if rf, ok := ret.Get(0).(func(string) domain.CaseStatus); ok {
-		r0 = rf(caseID)
+	if rf, ok := ret.Get(0).(func(context.Context, string) domain.CaseStatus); ok {
+		r0 = rf(ctx, caseID)
 	} else {
 		r0 = ret.Get(0).(domain.CaseStatus)
 	}
 
-	if rf, ok := ret.Get(1).(func(string) error); ok {
-		r1 = rf(caseID)
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, caseID)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -43,9 +44,9 @@ func (_m *DirectorRepo) GetCase(caseID string) (domain.CaseStatus, error) {
 	return r0, r1
 }
 
-// GetExpertIntervalCases provides a mock function with given fields: expertID, startDate, endDate
-func (_m *DirectorRepo) GetExpertIntervalCases(expertID string, startDate time.Time, endDate time.Time) (map[domain.Date][]domain.IntervalCase, error) {
-	ret := _m.Called(expertID, startDate, endDate)
+// GetExpertIntervalCases provides a mock function with given fields: ctx, expertID, startDate, endDate
+func (_m *DirectorRepo) GetExpertIntervalCases(ctx context.Context, expertID string, startDate time.Time, endDate time.Time) (map[domain.Date][]domain.IntervalCase, error) {
+	ret := _m.Called(ctx, expertID, startDate, endDate)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetExpertIntervalCases")
@@ -53,19 +54,19 @@ func (_m *DirectorRepo) GetExpertIntervalCases(expertID string, startDate time.T
 
 	var r0 map[domain.Date][]domain.IntervalCase
 	var r1 error
-	if rf, ok := ret.Get(0).(func(string, time.Time, time.Time) (map[domain.Date][]domain.IntervalCase, error)); ok {
-		return rf(expertID, startDate, endDate)
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time, time.Time) (map[domain.Date][]domain.IntervalCase, error)); ok {
+		return rf(ctx, expertID, startDate, endDate)
 	}
-	if rf, ok := ret.Get(0).(func(string, time.Time, time.Time) map[domain.Date][]domain.IntervalCase); ok {
-		r0 = rf(expertID, startDate, endDate)
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time, time.Time) map[domain.Date][]domain.IntervalCase); ok {
+		r0 = rf(ctx, expertID, startDate, endDate)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(map[domain.Date][]domain.IntervalCase)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(string, time.Time, time.Time) error); ok {
-		r1 = rf(expertID, startDate, endDate)
+	if rf, ok := ret.Get(1).(func(context.Context, string, time.Time, time.Time) error); ok {
+		r1 = rf(ctx, expertID, startDate, endDate)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -73,22 +74,62 @@ func (_m *DirectorRepo) GetExpertIntervalCases(expertID string, startDate time.T
 	return r0, r1
 }
 
-// UpdateExpertSkill provides a mock function with given fields: expertID, skill
-func (_m *DirectorRepo) UpdateExpertSkill(expertID string, skill int) error {
-	ret := _m.Called(expertID, skill)
+// GetReviewCases provides a mock function with given fields: ctx, limit, offset
+func (_m *DirectorRepo) GetReviewCases(ctx context.Context, limit int, offset int) ([]domain.ReviewCase, error) {
+	ret := _m.Called(ctx, limit, offset)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetReviewCases")
+	}
+
+	var r0 []domain.ReviewCase
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) ([]domain.ReviewCase, error)); ok {
+		return rf(ctx, limit, offset)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) []domain.ReviewCase); ok {
+		r0 = rf(ctx, limit, offset)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.ReviewCase)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, int) error); ok {
+		r1 = rf(ctx, limit, offset)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateExpertSkill provides a mock function with given fields: ctx, expertID, skill
+func (_m *DirectorRepo) UpdateExpertSkill(ctx context.Context, expertID string, skill int) (domain.SkillChange, error) {
+	ret := _m.Called(ctx, expertID, skill)
 
 	if len(ret) == 0 {
 		panic("no return value specified for UpdateExpertSkill")
 	}
 
-	var r0 error
-	if rf, ok := ret.Get(0).(func(string, int) error); ok {
-		r0 = rf(expertID, skill)
+	var r0 domain.SkillChange
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int) (domain.SkillChange, error)); ok {
+		return rf(ctx, expertID, skill)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, int) domain.SkillChange); ok {
+		r0 = rf(ctx, expertID, skill)
+	} else {
+		r0 = ret.Get(0).(domain.SkillChange)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, int) error); ok {
+		r1 = rf(ctx, expertID, skill)
 	} else {
-		r0 = ret.Error(0)
+		r1 = ret.Error(1)
 	}
 
-	return r0
+	return r0, r1
 }
 
 // NewDirectorRepo creates a new instance of DirectorRepo. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
@@ -4,8 +4,11 @@ package mocks
 
 import (
 	domain "TrafficPolice/internal/domain"
+	context "context"
 
 	mock "github.com/stretchr/testify/mock"
+
+	time "time"
 )
 
 // ExpertRepo is an autogenerated mock type for the ExpertRepo type
@@ -13,9 +16,65 @@ type ExpertRepo struct {
 	mock.Mock
 }
 
-// GetCaseFineDecisions provides a mock function with given fields: caseID, competenceSkill
-func (_m *ExpertRepo) GetCaseFineDecisions(caseID string, competenceSkill int) (domain.FineDecisions, error) {
-	ret := _m.Called(caseID, competenceSkill)
+// ClaimNotSolvedCase provides a mock function with given fields: ctx, expert, lease, consensus
+func (_m *ExpertRepo) ClaimNotSolvedCase(ctx context.Context, expert domain.Expert, lease domain.ExpertCase, consensus int) (domain.Case, error) {
+	ret := _m.Called(ctx, expert, lease, consensus)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ClaimNotSolvedCase")
+	}
+
+	var r0 domain.Case
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, domain.Expert, domain.ExpertCase, int) (domain.Case, error)); ok {
+		return rf(ctx, expert, lease, consensus)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, domain.Expert, domain.ExpertCase, int) domain.Case); ok {
+		r0 = rf(ctx, expert, lease, consensus)
+	} else {
+		r0 = ret.Get(0).(domain.Case)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, domain.Expert, domain.ExpertCase, int) error); ok {
+		r1 = rf(ctx, expert, lease, consensus)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ExpireCaseLeases provides a mock function with given fields: ctx, caseID, now
+func (_m *ExpertRepo) ExpireCaseLeases(ctx context.Context, caseID string, now time.Time) (int, error) {
+	ret := _m.Called(ctx, caseID, now)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ExpireCaseLeases")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time) (int, error)); ok {
+		return rf(ctx, caseID, now)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time) int); ok {
+		r0 = rf(ctx, caseID, now)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, time.Time) error); ok {
+		r1 = rf(ctx, caseID, now)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetCaseFineDecisions provides a mock function with given fields: ctx, caseID, competenceSkill
+func (_m *ExpertRepo) GetCaseFineDecisions(ctx context.Context, caseID string, competenceSkill int) (domain.FineDecisions, error) {
+	ret := _m.Called(ctx, caseID, competenceSkill)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetCaseFineDecisions")
@@ -23,17 +82,17 @@ func (_m *ExpertRepo) GetCaseFineDecisions(caseID string, competenceSkill int) (
 
 	var r0 domain.FineDecisions
 	var r1 error
-	if rf, ok := ret.Get(0).(func(string, int) (domain.FineDecisions, error)); ok {
-		return rf(caseID, competenceSkill)
+	if rf, ok := ret.Get(0).(func(context.Context, string, int) (domain.FineDecisions, error)); ok {
+		return rf(ctx, caseID, competenceSkill)
 	}
-	if rf, ok := ret.Get(0).(func(string, int) domain.FineDecisions); ok {
-		r0 = rf(caseID, competenceSkill)
+	if rf, ok := ret.Get(0).(func(context.Context, string, int) domain.FineDecisions); ok {
+		r0 = rf(ctx, caseID, competenceSkill)
 	} else {
 		r0 = ret.Get(0).(domain.FineDecisions)
 	}
 
-	if rf, ok := ret.Get(1).(func(string, int) error); ok {
-		r1 = rf(caseID, competenceSkill)
+	if rf, ok := ret.Get(1).(func(context.Context, string, int) error); ok {
+		r1 = rf(ctx, caseID, competenceSkill)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -41,9 +100,9 @@ func (_m *ExpertRepo) GetCaseFineDecisions(caseID string, competenceSkill int) (
 	return r0, r1
 }
 
-// GetExpertByUserID provides a mock function with given fields: userID
-func (_m *ExpertRepo) GetExpertByUserID(userID string) (domain.Expert, error) {
-	ret := _m.Called(userID)
+// GetExpertByUserID provides a mock function with given fields: ctx, userID
+func (_m *ExpertRepo) GetExpertByUserID(ctx context.Context, userID string) (domain.Expert, error) {
+	ret := _m.Called(ctx, userID)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetExpertByUserID")
@@ -51,17 +110,17 @@ func (_m *ExpertRepo) GetExpertByUserID(userID string) (domain.Expert, error) {
 
 	var r0 domain.Expert
 	var r1 error
-	if rf, ok := ret.Get(0).(func(string) (domain.Expert, error)); ok {
-		return rf(userID)
+	if rf, ok := ret.Get(0).(func(context.Context, string) (domain.Expert, error)); ok {
+		return rf(ctx, userID)
 	}
-	if rf, ok := ret.Get(0).(func(string) domain.Expert); ok {
-		r0 = rf(userID)
+	if rf, ok := ret.Get(0).(func(context.Context, string) domain.Expert); ok {
+		r0 = rf(ctx, userID)
 	} else {
 		r0 = ret.Get(0).(domain.Expert)
 	}
 
-	if rf, ok := ret.Get(1).(func(string) error); ok {
-		r1 = rf(userID)
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, userID)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -69,9 +128,9 @@ func (_m *ExpertRepo) GetExpertByUserID(userID string) (domain.Expert, error) {
 	return r0, r1
 }
 
-// GetExpertsCountBySkill provides a mock function with given fields: competenceSkill
-func (_m *ExpertRepo) GetExpertsCountBySkill(competenceSkill int) (int, error) {
-	ret := _m.Called(competenceSkill)
+// GetExpertsCountBySkill provides a mock function with given fields: ctx, competenceSkill
+func (_m *ExpertRepo) GetExpertsCountBySkill(ctx context.Context, competenceSkill int) (int, error) {
+	ret := _m.Called(ctx, competenceSkill)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetExpertsCountBySkill")
@@ -79,17 +138,17 @@ func (_m *ExpertRepo) GetExpertsCountBySkill(competenceSkill int) (int, error) {
 
 	var r0 int
 	var r1 error
-	if rf, ok := ret.Get(0).(func(int) (int, error)); ok {
-		return rf(competenceSkill)
+	if rf, ok := ret.Get(0).(func(context.Context, int) (int, error)); ok {
+		return rf(ctx, competenceSkill)
 	}
-	if rf, ok := ret.Get(0).(func(int) int); ok {
-		r0 = rf(competenceSkill)
+	if rf, ok := ret.Get(0).(func(context.Context, int) int); ok {
+		r0 = rf(ctx, competenceSkill)
 	} else {
 		r0 = ret.Get(0).(int)
 	}
 
-	if rf, ok := ret.Get(1).(func(int) error); ok {
-		r1 = rf(competenceSkill)
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, competenceSkill)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -97,27 +156,29 @@ func (_m *ExpertRepo) GetExpertsCountBySkill(competenceSkill int) (int, error) {
 	return r0, r1
 }
 
-// GetLastNotSolvedCaseID provides a mock function with given fields: expertID
-func (_m *ExpertRepo) GetLastNotSolvedCaseID(expertID string) (string, error) {
-	ret := _m.Called(expertID)
+// GetExpiredLeaseCases provides a mock function with given fields: ctx, now
+func (_m *ExpertRepo) GetExpiredLeaseCases(ctx context.Context, now time.Time) ([]string, error) {
+	ret := _m.Called(ctx, now)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetLastNotSolvedCaseID")
+		panic("no return value specified for GetExpiredLeaseCases")
 	}
 
-	var r0 string
+	var r0 []string
 	var r1 error
-	if rf, ok := ret.Get(0).(func(string) (string, error)); ok {
-		return rf(expertID)
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) ([]string, error)); ok {
+		return rf(ctx, now)
 	}
-	if rf, ok := ret.Get(0).(func(string) string); ok {
-		r0 = rf(expertID)
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) []string); ok {
+		r0 = rf(ctx, now)
 	} else {
-		r0 = ret.Get(0).(string)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
 	}
 
-	if rf, ok := ret.Get(1).(func(string) error); ok {
-		r1 = rf(expertID)
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time) error); ok {
+		r1 = rf(ctx, now)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -125,27 +186,27 @@ func (_m *ExpertRepo) GetLastNotSolvedCaseID(expertID string) (string, error) {
 	return r0, r1
 }
 
-// GetNotSolvedCase provides a mock function with given fields: expert
-func (_m *ExpertRepo) GetNotSolvedCase(expert domain.Expert) (domain.Case, error) {
-	ret := _m.Called(expert)
+// GetLastNotSolvedCase provides a mock function with given fields: ctx, expertID, now
+func (_m *ExpertRepo) GetLastNotSolvedCase(ctx context.Context, expertID string, now time.Time) (domain.ExpertCase, error) {
+	ret := _m.Called(ctx, expertID, now)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetNotSolvedCase")
+		panic("no return value specified for GetLastNotSolvedCase")
 	}
 
-	var r0 domain.Case
+	var r0 domain.ExpertCase
 	var r1 error
-	if rf, ok := ret.Get(0).(func(domain.Expert) (domain.Case, error)); ok {
-		return rf(expert)
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time) (domain.ExpertCase, error)); ok {
+		return rf(ctx, expertID, now)
 	}
-	if rf, ok := ret.Get(0).(func(domain.Expert) domain.Case); ok {
-		r0 = rf(expert)
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time) domain.ExpertCase); ok {
+		r0 = rf(ctx, expertID, now)
 	} else {
-		r0 = ret.Get(0).(domain.Case)
+		r0 = ret.Get(0).(domain.ExpertCase)
 	}
 
-	if rf, ok := ret.Get(1).(func(domain.Expert) error); ok {
-		r1 = rf(expert)
+	if rf, ok := ret.Get(1).(func(context.Context, string, time.Time) error); ok {
+		r1 = rf(ctx, expertID, now)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -153,17 +214,17 @@ func (_m *ExpertRepo) GetNotSolvedCase(expert domain.Expert) (domain.Case, error
 	return r0, r1
 }
 
-// InsertNotSolvedCase provides a mock function with given fields: solvedCase
-func (_m *ExpertRepo) InsertNotSolvedCase(solvedCase domain.ExpertCase) error {
-	ret := _m.Called(solvedCase)
+// SetCaseDecision provides a mock function with given fields: ctx, decision
+func (_m *ExpertRepo) SetCaseDecision(ctx context.Context, decision domain.Decision) error {
+	ret := _m.Called(ctx, decision)
 
 	if len(ret) == 0 {
-		panic("no return value specified for InsertNotSolvedCase")
+		panic("no return value specified for SetCaseDecision")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(domain.ExpertCase) error); ok {
-		r0 = rf(solvedCase)
+	if rf, ok := ret.Get(0).(func(context.Context, domain.Decision) error); ok {
+		r0 = rf(ctx, decision)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -171,17 +232,17 @@ func (_m *ExpertRepo) InsertNotSolvedCase(solvedCase domain.ExpertCase) error {
 	return r0
 }
 
-// SetCaseDecision provides a mock function with given fields: decision
-func (_m *ExpertRepo) SetCaseDecision(decision domain.Decision) error {
-	ret := _m.Called(decision)
+// VoidCaseDecisions provides a mock function with given fields: ctx, caseID, voidedAt
+func (_m *ExpertRepo) VoidCaseDecisions(ctx context.Context, caseID string, voidedAt time.Time) error {
+	ret := _m.Called(ctx, caseID, voidedAt)
 
 	if len(ret) == 0 {
-		panic("no return value specified for SetCaseDecision")
+		panic("no return value specified for VoidCaseDecisions")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(domain.Decision) error); ok {
-		r0 = rf(decision)
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time) error); ok {
+		r0 = rf(ctx, caseID, voidedAt)
 	} else {
 		r0 = ret.Error(0)
 	}
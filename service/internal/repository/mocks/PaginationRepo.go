@@ -2,16 +2,20 @@
 
 package mocks
 
-import mock "github.com/stretchr/testify/mock"
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+)
 
 // PaginationRepo is an autogenerated mock type for the PaginationRepo type
 type PaginationRepo struct {
 	mock.Mock
 }
 
-// GetRecordsCount provides a mock function with given fields: table
-func (_m *PaginationRepo) GetRecordsCount(table string) (int, error) {
-	ret := _m.Called(table)
+// GetRecordsCount provides a mock function with given fields: ctx, table
+func (_m *PaginationRepo) GetRecordsCount(ctx context.Context, table string) (int, error) {
+	ret := _m.Called(ctx, table)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetRecordsCount")
@@ -19,17 +23,17 @@ func (_m *PaginationRepo) GetRecordsCount(table string) (int, error) {
 
 	var r0 int
 	var r1 error
-	if rf, ok := ret.Get(0).(func(string) (int, error)); ok {
-		return rf(table)
+	if rf, ok := ret.Get(0).(func(context.Context, string) (int, error)); ok {
+		return rf(ctx, table)
 	}
-	if rf, ok := ret.Get(0).(func(string) int); ok {
-		r0 = rf(table)
+	if rf, ok := ret.Get(0).(func(context.Context, string) int); ok {
+		r0 = rf(ctx, table)
 	} else {
 		r0 = ret.Get(0).(int)
 	}
 
-	if rf, ok := ret.Get(1).(func(string) error); ok {
-		r1 = rf(table)
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, table)
 	} else {
 		r1 = ret.Error(1)
 	}
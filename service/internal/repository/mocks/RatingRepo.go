@@ -4,6 +4,7 @@ package mocks
 
 import (
 	domain "TrafficPolice/internal/domain"
+	context "context"
 
 	mock "github.com/stretchr/testify/mock"
 )
@@ -13,17 +14,17 @@ type RatingRepo struct {
 	mock.Mock
 }
 
-// ClearRating provides a mock function with given fields:
-func (_m *RatingRepo) ClearRating() error {
-	ret := _m.Called()
+// ClearRating provides a mock function with given fields: ctx
+func (_m *RatingRepo) ClearRating(ctx context.Context) error {
+	ret := _m.Called(ctx)
 
 	if len(ret) == 0 {
 		panic("no return value specified for ClearRating")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func() error); ok {
-		r0 = rf()
+	if rf, ok := ret.Get(0).(func(context.Context) error); ok {
+		r0 = rf(ctx)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -31,9 +32,9 @@ func (_m *RatingRepo) ClearRating() error {
 	return r0
 }
 
-// GetExpertsRating provides a mock function with given fields: minSolvedCases
-func (_m *RatingRepo) GetExpertsRating(minSolvedCases int) ([]domain.ExpertRating, error) {
-	ret := _m.Called(minSolvedCases)
+// GetExpertsRating provides a mock function with given fields: ctx, minSolvedCases
+func (_m *RatingRepo) GetExpertsRating(ctx context.Context, minSolvedCases int) ([]domain.ExpertRating, error) {
+	ret := _m.Called(ctx, minSolvedCases)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetExpertsRating")
@@ -41,19 +42,19 @@ func (_m *RatingRepo) GetExpertsRating(minSolvedCases int) ([]domain.ExpertRatin
 
 	var r0 []domain.ExpertRating
 	var r1 error
-	if rf, ok := ret.Get(0).(func(int) ([]domain.ExpertRating, error)); ok {
-		return rf(minSolvedCases)
+	if rf, ok := ret.Get(0).(func(context.Context, int) ([]domain.ExpertRating, error)); ok {
+		return rf(ctx, minSolvedCases)
 	}
-	if rf, ok := ret.Get(0).(func(int) []domain.ExpertRating); ok {
-		r0 = rf(minSolvedCases)
+	if rf, ok := ret.Get(0).(func(context.Context, int) []domain.ExpertRating); ok {
+		r0 = rf(ctx, minSolvedCases)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]domain.ExpertRating)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(int) error); ok {
-		r1 = rf(minSolvedCases)
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, minSolvedCases)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -61,9 +62,9 @@ func (_m *RatingRepo) GetExpertsRating(minSolvedCases int) ([]domain.ExpertRatin
 	return r0, r1
 }
 
-// GetRating provides a mock function with given fields:
-func (_m *RatingRepo) GetRating() ([]domain.RatingInfo, error) {
-	ret := _m.Called()
+// GetRating provides a mock function with given fields: ctx
+func (_m *RatingRepo) GetRating(ctx context.Context) ([]domain.RatingInfo, error) {
+	ret := _m.Called(ctx)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetRating")
@@ -71,19 +72,19 @@ func (_m *RatingRepo) GetRating() ([]domain.RatingInfo, error) {
 
 	var r0 []domain.RatingInfo
 	var r1 error
-	if rf, ok := ret.Get(0).(func() ([]domain.RatingInfo, error)); ok {
-		return rf()
+	if rf, ok := ret.Get(0).(func(context.Context) ([]domain.RatingInfo, error)); ok {
+		return rf(ctx)
 	}
-	if rf, ok := ret.Get(0).(func() []domain.RatingInfo); ok {
-		r0 = rf()
+	if rf, ok := ret.Get(0).(func(context.Context) []domain.RatingInfo); ok {
+		r0 = rf(ctx)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]domain.RatingInfo)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func() error); ok {
-		r1 = rf()
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -91,9 +92,9 @@ func (_m *RatingRepo) GetRating() ([]domain.RatingInfo, error) {
 	return r0, r1
 }
 
-// GetSolvedCaseDecisions provides a mock function with given fields: caseDecision
-func (_m *RatingRepo) GetSolvedCaseDecisions(caseDecision domain.CaseDecisionInfo) ([]domain.ExpertCaseDecision, error) {
-	ret := _m.Called(caseDecision)
+// GetSolvedCaseDecisions provides a mock function with given fields: ctx, caseDecision
+func (_m *RatingRepo) GetSolvedCaseDecisions(ctx context.Context, caseDecision domain.CaseDecisionInfo) ([]domain.ExpertCaseDecision, error) {
+	ret := _m.Called(ctx, caseDecision)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetSolvedCaseDecisions")
@@ -101,19 +102,19 @@ func (_m *RatingRepo) GetSolvedCaseDecisions(caseDecision domain.CaseDecisionInf
 
 	var r0 []domain.ExpertCaseDecision
 	var r1 error
-	if rf, ok := ret.Get(0).(func(domain.CaseDecisionInfo) ([]domain.ExpertCaseDecision, error)); ok {
-		return rf(caseDecision)
+	if rf, ok := ret.Get(0).(func(context.Context, domain.CaseDecisionInfo) ([]domain.ExpertCaseDecision, error)); ok {
+		return rf(ctx, caseDecision)
 	}
-	if rf, ok := ret.Get(0).(func(domain.CaseDecisionInfo) []domain.ExpertCaseDecision); ok {
-		r0 = rf(caseDecision)
+	if rf, ok := ret.Get(0).(func(context.Context, domain.CaseDecisionInfo) []domain.ExpertCaseDecision); ok {
+		r0 = rf(ctx, caseDecision)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]domain.ExpertCaseDecision)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(domain.CaseDecisionInfo) error); ok {
-		r1 = rf(caseDecision)
+	if rf, ok := ret.Get(1).(func(context.Context, domain.CaseDecisionInfo) error); ok {
+		r1 = rf(ctx, caseDecision)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -121,17 +122,17 @@ func (_m *RatingRepo) GetSolvedCaseDecisions(caseDecision domain.CaseDecisionInf
 	return r0, r1
 }
 
-// InsertExpertId provides a mock function with given fields: expertID
-func (_m *RatingRepo) InsertExpertId(expertID string) error {
-	ret := _m.Called(expertID)
+// InsertExpertId provides a mock function with given fields: ctx, expertID
+func (_m *RatingRepo) InsertExpertId(ctx context.Context, expertID string) error {
+	ret := _m.Called(ctx, expertID)
 
 	if len(ret) == 0 {
 		panic("no return value specified for InsertExpertId")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(string) error); ok {
-		r0 = rf(expertID)
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, expertID)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -139,17 +140,17 @@ func (_m *RatingRepo) InsertExpertId(expertID string) error {
 	return r0
 }
 
-// SetRating provides a mock function with given fields: decisions
-func (_m *RatingRepo) SetRating(decisions []domain.ExpertCaseDecision) error {
-	ret := _m.Called(decisions)
+// RevertRating provides a mock function with given fields: ctx, decisions
+func (_m *RatingRepo) RevertRating(ctx context.Context, decisions []domain.ExpertCaseDecision) error {
+	ret := _m.Called(ctx, decisions)
 
 	if len(ret) == 0 {
-		panic("no return value specified for SetRating")
+		panic("no return value specified for RevertRating")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func([]domain.ExpertCaseDecision) error); ok {
-		r0 = rf(decisions)
+	if rf, ok := ret.Get(0).(func(context.Context, []domain.ExpertCaseDecision) error); ok {
+		r0 = rf(ctx, decisions)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -157,17 +158,17 @@ func (_m *RatingRepo) SetRating(decisions []domain.ExpertCaseDecision) error {
 	return r0
 }
 
-// UpdateCompetenceSkills provides a mock function with given fields: infos
-func (_m *RatingRepo) UpdateCompetenceSkills(infos []domain.UpdateCompetenceSkill) error {
-	ret := _m.Called(infos)
+// SetRating provides a mock function with given fields: ctx, decisions
+func (_m *RatingRepo) SetRating(ctx context.Context, decisions []domain.ExpertCaseDecision) error {
+	ret := _m.Called(ctx, decisions)
 
 	if len(ret) == 0 {
-		panic("no return value specified for UpdateCompetenceSkills")
+		panic("no return value specified for SetRating")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func([]domain.UpdateCompetenceSkill) error); ok {
-		r0 = rf(infos)
+	if rf, ok := ret.Get(0).(func(context.Context, []domain.ExpertCaseDecision) error); ok {
+		r0 = rf(ctx, decisions)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -175,6 +176,36 @@ func (_m *RatingRepo) UpdateCompetenceSkills(infos []domain.UpdateCompetenceSkil
 	return r0
 }
 
+// UpdateCompetenceSkills provides a mock function with given fields: ctx, infos
+func (_m *RatingRepo) UpdateCompetenceSkills(ctx context.Context, infos []domain.UpdateCompetenceSkill) ([]domain.SkillChange, error) {
+	ret := _m.Called(ctx, infos)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateCompetenceSkills")
+	}
+
+	var r0 []domain.SkillChange
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []domain.UpdateCompetenceSkill) ([]domain.SkillChange, error)); ok {
+		return rf(ctx, infos)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []domain.UpdateCompetenceSkill) []domain.SkillChange); ok {
+		r0 = rf(ctx, infos)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.SkillChange)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []domain.UpdateCompetenceSkill) error); ok {
+		r1 = rf(ctx, infos)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // NewRatingRepo creates a new instance of RatingRepo. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewRatingRepo(t interface {
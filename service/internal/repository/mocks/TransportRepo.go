@@ -2,16 +2,20 @@
 
 package mocks
 
-import mock "github.com/stretchr/testify/mock"
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+)
 
 // TransportRepo is an autogenerated mock type for the TransportRepo type
 type TransportRepo struct {
 	mock.Mock
 }
 
-// GetTransportID provides a mock function with given fields: chars, num, region
-func (_m *TransportRepo) GetTransportID(chars string, num string, region string) (string, error) {
-	ret := _m.Called(chars, num, region)
+// GetTransportID provides a mock function with given fields: ctx, chars, num, region
+func (_m *TransportRepo) GetTransportID(ctx context.Context, chars string, num string, region string) (string, error) {
+	ret := _m.Called(ctx, chars, num, region)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetTransportID")
@@ -19,17 +23,17 @@ func (_m *TransportRepo) GetTransportID(chars string, num string, region string)
 
 	var r0 string
 	var r1 error
-	if rf, ok := ret.Get(0).(func(string, string, string) (string, error)); ok {
-		return rf(chars, num, region)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) (string, error)); ok {
+		return rf(ctx, chars, num, region)
 	}
-	if rf, ok := ret.Get(0).(func(string, string, string) string); ok {
-		r0 = rf(chars, num, region)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) string); ok {
+		r0 = rf(ctx, chars, num, region)
 	} else {
 		r0 = ret.Get(0).(string)
 	}
 
-	if rf, ok := ret.Get(1).(func(string, string, string) error); ok {
-		r1 = rf(chars, num, region)
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, string) error); ok {
+		r1 = rf(ctx, chars, num, region)
 	} else {
 		r1 = ret.Error(1)
 	}
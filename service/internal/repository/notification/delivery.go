@@ -0,0 +1,103 @@
+package notification
+
+import (
+	"TrafficPolice/internal/config"
+	"TrafficPolice/internal/domain"
+	"TrafficPolice/internal/repository"
+	"TrafficPolice/internal/tokens"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	deliveriesPath = "/deliveries"
+	// serviceUserID is subject of tokens, which service issues to itself for requests to fine_notification
+	serviceUserID = "service"
+	// tokenTTL is lifetime of token of one request, it covers request timeout
+	tokenTTL = time.Minute
+)
+
+type delivery struct {
+	Kind        string     `json:"kind"`
+	Channel     string     `json:"channel"`
+	Status      string     `json:"status"`
+	Attempts    int        `json:"attempts"`
+	LastError   *string    `json:"last_error"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+	DeliveredAt *time.Time `json:"delivered_at"`
+}
+
+// deliveryRepoHTTP gets deliveries from ledger of fine_notification. Requests are authorized
+// by director token signed by key of fine_notification, which differs from signing key of users
+type deliveryRepoHTTP struct {
+	url          string
+	client       *http.Client
+	tokenManager tokens.TokenManager
+}
+
+func NewDeliveryRepoHTTP(cfg config.NotificationConfig, tokenManager tokens.TokenManager) repository.DeliveryRepo {
+	return &deliveryRepoHTTP{
+		url:          strings.TrimSuffix(cfg.URL, "/"),
+		client:       &http.Client{Timeout: cfg.Timeout},
+		tokenManager: tokenManager,
+	}
+}
+
+func (r *deliveryRepoHTTP) GetCaseDeliveries(ctx context.Context, caseID string) ([]domain.NotificationDelivery, error) {
+	token, err := r.tokenManager.NewJWT(
+		tokens.TokenInfo{UserID: serviceUserID, UserRole: domain.DirectorRole}, tokenTTL,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	query := url.Values{"case_id": {caseID}}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, r.url+deliveriesPath+"?"+query.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return []domain.NotificationDelivery{}, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return nil, fmt.Errorf("get deliveries of case %s: status %d: %s", caseID, resp.StatusCode, body)
+	}
+
+	var deliveries []delivery
+	err = json.NewDecoder(resp.Body).Decode(&deliveries)
+	if err != nil {
+		return nil, fmt.Errorf("decode deliveries of case %s: %w", caseID, err)
+	}
+
+	result := make([]domain.NotificationDelivery, 0, len(deliveries))
+	for _, d := range deliveries {
+		result = append(result, domain.NotificationDelivery{
+			Kind:        d.Kind,
+			Channel:     d.Channel,
+			Status:      d.Status,
+			Attempts:    d.Attempts,
+			LastError:   d.LastError,
+			CreatedAt:   d.CreatedAt,
+			UpdatedAt:   d.UpdatedAt,
+			DeliveredAt: d.DeliveredAt,
+		})
+	}
+
+	return result, nil
+}
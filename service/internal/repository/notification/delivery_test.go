@@ -0,0 +1,98 @@
+package notification
+
+import (
+	"TrafficPolice/internal/config"
+	"TrafficPolice/internal/domain"
+	"TrafficPolice/internal/tokens"
+	"context"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGetCaseDeliveries(t *testing.T) {
+	tokenManager, err := tokens.NewTokenManager("sign")
+	assert.NoError(t, err)
+
+	caseID := "case 1"
+	lastError := "smtp is unavailable"
+	createdAt := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	deliveredAt := createdAt.Add(time.Minute)
+
+	testCases := []struct {
+		name         string
+		status       int
+		body         string
+		expected     []domain.NotificationDelivery
+		expectingErr bool
+	}{
+		{
+			name:   "Deliveries of case",
+			status: http.StatusOK,
+			body: `[{"case_id":"case 1","kind":"fine","channel":"email","status":"delivered","attempts":2,` +
+				`"last_error":"smtp is unavailable","created_at":"2024-05-01T10:00:00Z",` +
+				`"updated_at":"2024-05-01T10:01:00Z","delivered_at":"2024-05-01T10:01:00Z"},` +
+				`{"case_id":"case 1","kind":"cancellation","channel":"sms","status":"failed","attempts":1,` +
+				`"created_at":"2024-05-01T10:00:00Z","updated_at":"2024-05-01T10:00:00Z"}]`,
+			expected: []domain.NotificationDelivery{
+				{
+					Kind: "fine", Channel: "email", Status: "delivered", Attempts: 2, LastError: &lastError,
+					CreatedAt: createdAt, UpdatedAt: deliveredAt, DeliveredAt: &deliveredAt,
+				},
+				{
+					Kind: "cancellation", Channel: "sms", Status: "failed", Attempts: 1,
+					CreatedAt: createdAt, UpdatedAt: createdAt,
+				},
+			},
+		},
+		{
+			name:     "Notification was not sent",
+			status:   http.StatusNotFound,
+			body:     `{"message":"No deliveries of case"}`,
+			expected: []domain.NotificationDelivery{},
+		},
+		{
+			name:         "Internal error of fine_notification",
+			status:       http.StatusInternalServerError,
+			body:         `{"message":"Internal server error"}`,
+			expectingErr: true,
+		},
+		{
+			name:         "Invalid body",
+			status:       http.StatusOK,
+			body:         `{`,
+			expectingErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, deliveriesPath, r.URL.Path)
+				assert.Equal(t, caseID, r.URL.Query().Get("case_id"))
+
+				token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+				assert.True(t, ok)
+				info, err := tokenManager.Parse(token)
+				assert.NoError(t, err)
+				assert.Equal(t, domain.DirectorRole, info.UserRole)
+
+				w.WriteHeader(tc.status)
+				_, _ = w.Write([]byte(tc.body))
+			}))
+			defer server.Close()
+
+			repo := NewDeliveryRepoHTTP(config.NotificationConfig{URL: server.URL + "/", Timeout: time.Second}, tokenManager)
+			actual, err := repo.GetCaseDeliveries(context.Background(), caseID)
+			if tc.expectingErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, actual)
+		})
+	}
+}
@@ -8,20 +8,21 @@ import (
 	"errors"
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
 )
 
 type authRepoPostgres struct {
-	conn *pgx.Conn
+	db querier
 }
 
-func NewAuthRepoPostgres(conn *pgx.Conn) repository.AuthRepo {
-	return &authRepoPostgres{conn: conn}
+func NewAuthRepoPostgres(pool *pgxpool.Pool) repository.AuthRepo {
+	return &authRepoPostgres{db: pool}
 }
 
 const checkUserExistsQuery = "SELECT username FROM users WHERE username = $1"
 
-func (r *authRepoPostgres) CheckUserExists(username string) bool {
-	row := r.conn.QueryRow(context.Background(), checkUserExistsQuery, username)
+func (r *authRepoPostgres) CheckUserExists(ctx context.Context, username string) bool {
+	row := r.db.QueryRow(ctx, checkUserExistsQuery, username)
 
 	var userName string
 	err := row.Scan(&userName)
@@ -32,8 +33,8 @@ func (r *authRepoPostgres) CheckUserExists(username string) bool {
 const insertUserQuery = `INSERT INTO users (user_id, username, hash_pass, role) 
 	VALUES ($1, $2, $3, $4)`
 
-func (r *authRepoPostgres) InsertUser(user domain.UserInfo) error {
-	_, err := r.conn.Exec(context.Background(), insertUserQuery,
+func (r *authRepoPostgres) InsertUser(ctx context.Context, user domain.UserInfo) error {
+	_, err := r.db.Exec(ctx, insertUserQuery,
 		user.ID.String(),
 		user.Username,
 		user.Password,
@@ -46,22 +47,22 @@ func (r *authRepoPostgres) InsertUser(user domain.UserInfo) error {
 const insertExpertQuery = `INSERT INTO experts (expert_id, is_confirmed, user_id, competence_skill) 
 	VALUES ($1, false, $2, 1)`
 
-func (r *authRepoPostgres) InsertExpert(expert domain.Expert) error {
-	_, err := r.conn.Exec(context.Background(), insertExpertQuery, expert.ID, expert.UserInfo.ID.String())
+func (r *authRepoPostgres) InsertExpert(ctx context.Context, expert domain.Expert) error {
+	_, err := r.db.Exec(ctx, insertExpertQuery, expert.ID, expert.UserInfo.ID.String())
 	return err
 }
 
 const insertDirectorQuery = "INSERT INTO directors (director_id, user_id) VALUES ($1, $2)"
 
-func (r *authRepoPostgres) InsertDirector(director domain.Director) error {
-	_, err := r.conn.Exec(context.Background(), insertDirectorQuery, director.ID, director.User.ID)
+func (r *authRepoPostgres) InsertDirector(ctx context.Context, director domain.Director) error {
+	_, err := r.db.Exec(ctx, insertDirectorQuery, director.ID, director.User.ID)
 	return err
 }
 
 const signInQuery = `SELECT user_id, hash_pass, role FROM users WHERE username = $1`
 
-func (r *authRepoPostgres) SignIn(username string) (domain.UserInfo, error) {
-	row := r.conn.QueryRow(context.Background(), signInQuery, username)
+func (r *authRepoPostgres) SignIn(ctx context.Context, username string) (domain.UserInfo, error) {
+	row := r.db.QueryRow(ctx, signInQuery, username)
 
 	var user domain.UserInfo
 	var userID string
@@ -79,8 +80,8 @@ func (r *authRepoPostgres) SignIn(username string) (domain.UserInfo, error) {
 
 const confirmExpertQuery = "UPDATE experts SET is_confirmed = $1 WHERE expert_id = $2"
 
-func (r *authRepoPostgres) ConfirmExpert(data domain.ConfirmExpert) error {
-	n, err := r.conn.Exec(context.Background(), confirmExpertQuery, data.IsConfirmed, data.ExpertID)
+func (r *authRepoPostgres) ConfirmExpert(ctx context.Context, data domain.ConfirmExpert) error {
+	n, err := r.db.Exec(ctx, confirmExpertQuery, data.IsConfirmed, data.ExpertID)
 
 	if n.RowsAffected() == 0 {
 		return errs.ErrNoRows
@@ -92,12 +93,25 @@ const insertCameraQuery = `INSERT INTO cameras (
                      camera_id, camera_type_id, camera_latitude, camera_longitude, short_desc, user_id) 
 		VALUES ($1, $2, $3, $4, $5, $6) RETURNING camera_id`
 
-func (r *authRepoPostgres) InsertCamera(camera domain.Camera, userID uuid.UUID) (string, error) {
+func (r *authRepoPostgres) InsertCamera(ctx context.Context, camera domain.Camera, userID uuid.UUID) (string, error) {
+	batch := &pgx.Batch{}
+
+	batch.Queue(insertCameraQuery,
+		camera.ID, camera.CameraType.ID, camera.Latitude, camera.Longitude, camera.ShortDesc, userID)
+	for _, region := range camera.RedactRegions {
+		batch.Queue(insertCameraRedactRegionQuery, camera.ID, region.X, region.Y, region.Width, region.Height)
+	}
+
+	results := r.db.SendBatch(ctx, batch)
+	defer results.Close()
+
 	var cameraID string
-	err := r.conn.QueryRow(context.Background(), insertCameraQuery,
-		camera.ID, camera.CameraType.ID, camera.Latitude, camera.Longitude, camera.ShortDesc, userID).
-		Scan(&cameraID)
+	err := results.QueryRow().Scan(&cameraID)
+	if err != nil {
+		return "", err
+	}
 
+	err = results.Close()
 	if err != nil {
 		return "", err
 	}
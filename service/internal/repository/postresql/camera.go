@@ -7,23 +7,25 @@ import (
 	"context"
 	"errors"
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
 )
 
 type cameraRepoPostgres struct {
-	conn *pgx.Conn
+	db querier
 }
 
-func NewCameraRepoPostgres(conn *pgx.Conn) repository.CameraRepo {
-	return &cameraRepoPostgres{conn: conn}
+func NewCameraRepoPostgres(pool *pgxpool.Pool) repository.CameraRepo {
+	return &cameraRepoPostgres{db: pool}
 }
 
 const addCameraTypeQuery = `INSERT INTO camera_types (camera_type_id, camera_type_name) 
 VALUES ($1, $2) RETURNING camera_type_id`
 
-func (r *cameraRepoPostgres) AddCameraType(cameraType domain.CameraType) (string, error) {
+func (r *cameraRepoPostgres) AddCameraType(ctx context.Context, cameraType domain.CameraType) (string, error) {
 	var cameraTypeID string
 
-	err := r.conn.QueryRow(context.Background(), addCameraTypeQuery, cameraType.ID, cameraType.Name).
+	err := r.db.QueryRow(ctx, addCameraTypeQuery, cameraType.ID, cameraType.Name).
 		Scan(&cameraTypeID)
 	if err != nil {
 		return "", errs.ErrAlreadyExists
@@ -37,8 +39,8 @@ FROM camera_types as type
 JOIN cameras as c ON type.camera_type_id = c.camera_type_id
 WHERE c.camera_id = $1`
 
-func (r *cameraRepoPostgres) GetCameraTypeByCameraID(cameraID string) (string, error) {
-	row := r.conn.QueryRow(context.Background(), getCameraTypeByCameraIDQuery, cameraID)
+func (r *cameraRepoPostgres) GetCameraTypeByCameraID(ctx context.Context, cameraID string) (string, error) {
+	row := r.db.QueryRow(ctx, getCameraTypeByCameraIDQuery, cameraID)
 
 	var cameraType string
 	err := row.Scan(&cameraType)
@@ -51,3 +53,139 @@ func (r *cameraRepoPostgres) GetCameraTypeByCameraID(cameraID string) (string, e
 
 	return cameraType, nil
 }
+
+const getCameraUserIDQuery = `SELECT user_id FROM cameras WHERE camera_id = $1`
+
+func (r *cameraRepoPostgres) GetCameraUserID(ctx context.Context, cameraID string) (string, error) {
+	var userID string
+	err := r.db.QueryRow(ctx, getCameraUserIDQuery, cameraID).Scan(&userID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return "", errs.ErrCameraNotExists
+	}
+	if err != nil {
+		return "", err
+	}
+
+	return userID, nil
+}
+
+const deleteCameraTypeFieldsQuery = `DELETE FROM camera_type_fields WHERE camera_type_id = $1`
+
+const insertCameraTypeFieldQuery = `INSERT INTO camera_type_fields 
+    (camera_type_id, case_field, payload_key, value_format) 
+	VALUES ($1, $2, $3, $4)`
+
+func (r *cameraRepoPostgres) SetCameraTypeMapping(ctx context.Context, mapping domain.CameraTypeMapping) error {
+	batch := &pgx.Batch{}
+
+	batch.Queue(deleteCameraTypeFieldsQuery, mapping.CameraTypeID)
+	for _, f := range mapping.Fields {
+		batch.Queue(insertCameraTypeFieldQuery, mapping.CameraTypeID, f.CaseField, f.PayloadKey, f.Format)
+	}
+
+	err := r.db.SendBatch(ctx, batch).Close()
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == string(errs.ForeignKeyViolationErrorCode) {
+		return errs.ErrCameraTypeNotExists
+	}
+
+	return err
+}
+
+const getCameraTypeMappingQuery = `SELECT f.camera_type_id, f.case_field, f.payload_key, f.value_format
+FROM camera_type_fields AS f
+JOIN camera_types AS type ON f.camera_type_id = type.camera_type_id
+WHERE type.camera_type_name = $1`
+
+func (r *cameraRepoPostgres) GetCameraTypeMapping(
+	ctx context.Context,
+	cameraTypeName string,
+) (domain.CameraTypeMapping, error) {
+	rows, err := r.db.Query(ctx, getCameraTypeMappingQuery, cameraTypeName)
+	if err != nil {
+		return domain.CameraTypeMapping{}, err
+	}
+	defer rows.Close()
+
+	mapping := domain.CameraTypeMapping{Fields: make([]domain.FieldMapping, 0)}
+	for rows.Next() {
+		var f domain.FieldMapping
+		err = rows.Scan(&mapping.CameraTypeID, &f.CaseField, &f.PayloadKey, &f.Format)
+		if err != nil {
+			return domain.CameraTypeMapping{}, err
+		}
+
+		mapping.Fields = append(mapping.Fields, f)
+	}
+	if err = rows.Err(); err != nil {
+		return domain.CameraTypeMapping{}, err
+	}
+
+	if len(mapping.Fields) == 0 {
+		return domain.CameraTypeMapping{}, errs.ErrNoCameraTypeMapping
+	}
+
+	return mapping, nil
+}
+
+const checkCameraExistsQuery = `SELECT EXISTS(SELECT 1 FROM cameras WHERE camera_id = $1)`
+
+const deleteCameraRedactRegionsQuery = `DELETE FROM camera_redact_regions WHERE camera_id = $1`
+
+const insertCameraRedactRegionQuery = `INSERT INTO camera_redact_regions (camera_id, x, y, width, height)
+VALUES ($1, $2, $3, $4, $5)`
+
+func (r *cameraRepoPostgres) SetCameraRedactRegions(
+	ctx context.Context,
+	cameraID string,
+	regions []domain.RedactRegion,
+) error {
+	batch := &pgx.Batch{}
+
+	batch.Queue(checkCameraExistsQuery, cameraID)
+	batch.Queue(deleteCameraRedactRegionsQuery, cameraID)
+	for _, region := range regions {
+		batch.Queue(insertCameraRedactRegionQuery, cameraID, region.X, region.Y, region.Width, region.Height)
+	}
+
+	results := r.db.SendBatch(ctx, batch)
+	defer results.Close()
+
+	var exists bool
+	err := results.QueryRow().Scan(&exists)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return errs.ErrCameraNotExists
+	}
+
+	return results.Close()
+}
+
+const getCameraRedactRegionsQuery = `SELECT x, y, width, height
+FROM camera_redact_regions
+WHERE camera_id = $1`
+
+func (r *cameraRepoPostgres) GetCameraRedactRegions(
+	ctx context.Context,
+	cameraID string,
+) ([]domain.RedactRegion, error) {
+	rows, err := r.db.Query(ctx, getCameraRedactRegionsQuery, cameraID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	regions := make([]domain.RedactRegion, 0)
+	for rows.Next() {
+		var region domain.RedactRegion
+		err = rows.Scan(&region.X, &region.Y, &region.Width, &region.Height)
+		if err != nil {
+			return nil, err
+		}
+		regions = append(regions, region)
+	}
+
+	return regions, rows.Err()
+}
@@ -7,26 +7,27 @@ import (
 	"context"
 	"errors"
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
 	"time"
 )
 
 type caseRepoPostgres struct {
-	conn *pgx.Conn
+	db querier
 }
 
-func NewCaseRepoPostgres(conn *pgx.Conn) repository.CaseRepo {
-	return &caseRepoPostgres{conn: conn}
+func NewCaseRepoPostgres(pool *pgxpool.Pool) repository.CaseRepo {
+	return &caseRepoPostgres{db: pool}
 }
 
 const insertCaseQuery = `INSERT INTO cases (case_id, transport_id, camera_id, 
-                   violation_id, violation_value, required_skill, case_date, is_solved, fine_decision, solved_at) 
-		VALUES ($1, $2, $3, $4, $5, $6, $7, false, false, NULL) RETURNING case_id`
+                   violation_id, violation_value, required_skill, case_date, is_solved, fine_decision, solved_at, has_image) 
+		VALUES ($1, $2, $3, $4, $5, $6, $7, false, false, NULL, $8) RETURNING case_id`
 
-func (r *caseRepoPostgres) InsertCase(c domain.Case) (string, error) {
+func (r *caseRepoPostgres) InsertCase(ctx context.Context, c domain.Case) (string, error) {
 	var caseID string
 
-	err := r.conn.QueryRow(context.Background(), insertCaseQuery,
-		c.ID, c.Transport.ID, c.Camera.ID, c.Violation.ID, c.ViolationValue, c.RequiredSkill, c.Date,
+	err := r.db.QueryRow(ctx, insertCaseQuery,
+		c.ID, c.Transport.ID, c.Camera.ID, c.Violation.ID, c.ViolationValue, c.RequiredSkill, c.Date, c.HasImage,
 	).Scan(&caseID)
 
 	if err != nil {
@@ -48,10 +49,10 @@ JOIN cameras AS cam ON c.camera_id = cam.camera_id
 WHERE c.case_id = $1
 LIMIT 1`
 
-func (r *caseRepoPostgres) GetCaseByID(caseID string) (domain.Case, error) {
+func (r *caseRepoPostgres) GetCaseByID(ctx context.Context, caseID string) (domain.Case, error) {
 	c := domain.Case{Transport: domain.Transport{Person: &domain.Person{}}, Camera: domain.Camera{}, Violation: domain.Violation{}}
 
-	row := r.conn.QueryRow(context.Background(), getCaseByIDQuery, caseID)
+	row := r.db.QueryRow(ctx, getCaseByIDQuery, caseID)
 
 	err := row.Scan(&c.ID, &c.Transport.ID, &c.Transport.Chars, &c.Transport.Num, &c.Transport.Region,
 		&c.Transport.Person.ID, &c.Camera.CameraType.ID, &c.Camera.Latitude, &c.Camera.Longitude,
@@ -68,26 +69,162 @@ func (r *caseRepoPostgres) GetCaseByID(caseID string) (domain.Case, error) {
 	return c, nil
 }
 
+// lockCaseQuery locks case until end of transaction, so decisions of case are evaluated one by one
+const lockCaseQuery = `SELECT case_id, violation_value, required_skill, case_date, is_solved, fine_decision,
+       review_requested_at IS NOT NULL
+FROM cases
+WHERE case_id = $1
+FOR UPDATE`
+
+func (r *caseRepoPostgres) LockCase(ctx context.Context, caseID string) (domain.Case, error) {
+	var c domain.Case
+
+	row := r.db.QueryRow(ctx, lockCaseQuery, caseID)
+	err := row.Scan(&c.ID, &c.ViolationValue, &c.RequiredSkill, &c.Date, &c.IsSolved, &c.FineDecision, &c.InReview)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return domain.Case{}, errs.ErrNoCase
+	}
+	if err != nil {
+		return domain.Case{}, err
+	}
+
+	return c, nil
+}
+
 const setCaseFineDecisionQuery = `UPDATE cases
 SET fine_decision = $1, is_solved = true, solved_at = $2
 WHERE case_id = $3`
 
-func (r *caseRepoPostgres) SetCaseFineDecision(caseID string, fineDecision bool, solvedAt time.Time) error {
-	_, err := r.conn.Exec(context.Background(), setCaseFineDecisionQuery, fineDecision, solvedAt, caseID)
+func (r *caseRepoPostgres) SetCaseFineDecision(
+	ctx context.Context,
+	caseID string,
+	fineDecision bool,
+	solvedAt time.Time,
+) error {
+	_, err := r.db.Exec(ctx, setCaseFineDecisionQuery, fineDecision, solvedAt, caseID)
 	return err
 }
 
+const setCaseHasImageQuery = `UPDATE cases
+SET has_image = true, image_overwrite_allowed = false
+WHERE case_id = $1`
+
+func (r *caseRepoPostgres) SetCaseHasImage(ctx context.Context, caseID string) error {
+	tag, err := r.db.Exec(ctx, setCaseHasImageQuery, caseID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return errs.ErrNoCase
+	}
+	return nil
+}
+
+const getCaseImageInfoQuery = `SELECT c.camera_id, cam.user_id, c.has_image, c.image_overwrite_allowed
+FROM cases AS c
+JOIN cameras AS cam ON c.camera_id = cam.camera_id
+WHERE c.case_id = $1`
+
+func (r *caseRepoPostgres) GetCaseImageInfo(ctx context.Context, caseID string) (domain.CaseImageInfo, error) {
+	return r.getCaseImageInfo(ctx, getCaseImageInfoQuery, caseID)
+}
+
+const lockCaseImageInfoQuery = getCaseImageInfoQuery + `
+FOR UPDATE OF c`
+
+func (r *caseRepoPostgres) LockCaseImageInfo(ctx context.Context, caseID string) (domain.CaseImageInfo, error) {
+	return r.getCaseImageInfo(ctx, lockCaseImageInfoQuery, caseID)
+}
+
+func (r *caseRepoPostgres) getCaseImageInfo(
+	ctx context.Context,
+	query string,
+	caseID string,
+) (domain.CaseImageInfo, error) {
+	var info domain.CaseImageInfo
+
+	err := r.db.QueryRow(ctx, query, caseID).
+		Scan(&info.CameraID, &info.CameraUserID, &info.HasImage, &info.OverwriteAllowed)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return domain.CaseImageInfo{}, errs.ErrNoCase
+	}
+	if err != nil {
+		return domain.CaseImageInfo{}, err
+	}
+
+	return info, nil
+}
+
+const setCaseImageOverwriteAllowedQuery = `UPDATE cases
+SET image_overwrite_allowed = $1
+WHERE case_id = $2`
+
+func (r *caseRepoPostgres) SetCaseImageOverwriteAllowed(ctx context.Context, caseID string, allowed bool) error {
+	tag, err := r.db.Exec(ctx, setCaseImageOverwriteAllowedQuery, allowed, caseID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return errs.ErrNoCase
+	}
+	return nil
+}
+
 const updateCaseRequiredSkillQuery = `UPDATE cases
 SET required_skill = $1
 WHERE case_id = $2`
 
-func (r *caseRepoPostgres) UpdateCaseRequiredSkill(caseID string, requiredSkill int) error {
-	_, err := r.conn.Exec(context.Background(), updateCaseRequiredSkillQuery, requiredSkill, caseID)
+func (r *caseRepoPostgres) UpdateCaseRequiredSkill(ctx context.Context, caseID string, requiredSkill int) error {
+	_, err := r.db.Exec(ctx, updateCaseRequiredSkillQuery, requiredSkill, caseID)
+	return err
+}
+
+const requestCaseReviewQuery = `UPDATE cases
+SET review_requested_at = $1
+WHERE case_id = $2`
+
+func (r *caseRepoPostgres) RequestCaseReview(ctx context.Context, caseID string, requestedAt time.Time) error {
+	_, err := r.db.Exec(ctx, requestCaseReviewQuery, requestedAt, caseID)
+	return err
+}
+
+const insertEscalationQuery = `INSERT INTO case_escalations 
+    (escalation_id, case_id, from_skill, to_skill, reason, escalated_at) 
+VALUES ($1, $2, $3, $4, $5, $6)`
+
+func (r *caseRepoPostgres) InsertEscalation(ctx context.Context, escalation domain.CaseEscalation) error {
+	_, err := r.db.Exec(ctx, insertEscalationQuery, escalation.ID, escalation.CaseID, escalation.FromSkill,
+		escalation.ToSkill, escalation.Reason, escalation.EscalatedAt)
+	return err
+}
+
+const reopenCaseQuery = `UPDATE cases
+SET is_solved = false, fine_decision = false, solved_at = NULL, review_requested_at = NULL
+WHERE case_id = $1`
+
+func (r *caseRepoPostgres) ReopenCase(ctx context.Context, caseID string) error {
+	tag, err := r.db.Exec(ctx, reopenCaseQuery, caseID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return errs.ErrNoCase
+	}
+	return nil
+}
+
+const insertOverrideQuery = `INSERT INTO case_overrides 
+    (override_id, case_id, user_id, action, old_fine_decision, new_fine_decision, fine_id, reason, created_at) 
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`
+
+func (r *caseRepoPostgres) InsertOverride(ctx context.Context, override domain.CaseOverride) error {
+	_, err := r.db.Exec(ctx, insertOverrideQuery, override.ID, override.CaseID, override.UserID, override.Action,
+		override.OldFineDecision, override.NewFineDecision, override.FineID, override.Reason, override.CreatedAt)
 	return err
 }
 
 const getCaseWithPersonInfoQuery = `SELECT c.case_id, t.transport_id, t.transport_chars, 
-       t.transport_nums, t.region, p.id, p.phone_num, p.email, p.vk_id, p.tg_id, 
+       t.transport_nums, t.region, p.id, p.phone_num, p.email, p.vk_id, p.tg_id, p.notify_channels,
        cam.camera_id ,cam.camera_type_id, cam.camera_latitude, cam.camera_longitude, cam.short_desc, 
        v.violation_id, v.violation_name, v.fine_amount, 
        c.violation_value, c.required_skill, c.case_date, c.is_solved, c.fine_decision
@@ -99,15 +236,15 @@ JOIN cameras AS cam ON c.camera_id = cam.camera_id
 WHERE c.case_id = $1
 LIMIT 1`
 
-func (r *caseRepoPostgres) GetCaseWithPersonInfo(caseID string) (domain.Case, error) {
+func (r *caseRepoPostgres) GetCaseWithPersonInfo(ctx context.Context, caseID string) (domain.Case, error) {
 	c := domain.Case{Transport: domain.Transport{Person: &domain.Person{}}, Camera: domain.Camera{}, Violation: domain.Violation{}}
 
-	row := r.conn.QueryRow(context.Background(), getCaseWithPersonInfoQuery, caseID)
+	row := r.db.QueryRow(ctx, getCaseWithPersonInfoQuery, caseID)
 
 	err := row.Scan(&c.ID, &c.Transport.ID, &c.Transport.Chars, &c.Transport.Num,
 		&c.Transport.Region, &c.Transport.Person.ID, &c.Transport.Person.PhoneNum,
 		&c.Transport.Person.Email, &c.Transport.Person.VkID, &c.Transport.Person.TgID,
-		&c.Camera.ID, &c.Camera.CameraType.ID, &c.Camera.Latitude, &c.Camera.Longitude,
+		&c.Transport.Person.NotifyChannels, &c.Camera.ID, &c.Camera.CameraType.ID, &c.Camera.Latitude, &c.Camera.Longitude,
 		&c.Camera.ShortDesc, &c.Violation.ID, &c.Violation.Name, &c.Violation.FineAmount,
 		&c.ViolationValue, &c.RequiredSkill, &c.Date, &c.IsSolved, &c.FineDecision)
 
@@ -3,24 +3,24 @@ package repository
 import (
 	"TrafficPolice/internal/repository"
 	"context"
-	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
 	"log"
 )
 
 type checkerRepoPostgres struct {
-	conn *pgx.Conn
+	db querier
 }
 
-func NewCheckerRepoPostgres(conn *pgx.Conn) repository.CheckerRepo {
-	return &checkerRepoPostgres{conn: conn}
+func NewCheckerRepoPostgres(pool *pgxpool.Pool) repository.CheckerRepo {
+	return &checkerRepoPostgres{db: pool}
 }
 
 const checkExpertExistsQuery = `SELECT user_id FROM experts WHERE expert_id = $1`
 
-func (r *checkerRepoPostgres) CheckExpertExists(expertID string) (bool, error) {
+func (r *checkerRepoPostgres) CheckExpertExists(ctx context.Context, expertID string) (bool, error) {
 	var userID string
 
-	row := r.conn.QueryRow(context.Background(), checkExpertExistsQuery, expertID)
+	row := r.db.QueryRow(ctx, checkExpertExistsQuery, expertID)
 	err := row.Scan(&userID)
 
 	if err != nil {
@@ -5,31 +5,36 @@ import (
 	"TrafficPolice/internal/repository"
 	"context"
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
 )
 
 type contactInfoRepoPostgres struct {
-	conn *pgx.Conn
+	db querier
 }
 
-func NewContactInfoRepoPostgres(conn *pgx.Conn) repository.ContactInfoRepo {
-	return &contactInfoRepoPostgres{conn: conn}
+func NewContactInfoRepoPostgres(pool *pgxpool.Pool) repository.ContactInfoRepo {
+	return &contactInfoRepoPostgres{db: pool}
 }
 
-func (r *contactInfoRepoPostgres) InsertContactInfo(m map[string][]*domain.Transport) error {
+func (r *contactInfoRepoPostgres) InsertContactInfo(ctx context.Context, m map[string][]*domain.Transport) error {
 	batch := &pgx.Batch{}
 
-	personQuery := `INSERT INTO persons (id, phone_num, email, vk_id, tg_id) VALUES ($1, $2, $3, $4, $5)`
+	personQuery := `INSERT INTO persons (id, phone_num, email, vk_id, tg_id, notify_channels) VALUES ($1, $2, $3, $4, $5, $6)`
 	transportQuery := `INSERT INTO transports(transport_id, transport_chars, transport_nums, region, person_id) VALUES ($1, $2, $3, $4, $5)`
 
 	for _, transports := range m {
 		if len(transports) > 0 {
 			person := transports[0].Person
-			batch.Queue(personQuery, person.ID, person.PhoneNum, person.Email, person.VkID, person.TgID)
+			notifyChannels := person.NotifyChannels
+			if notifyChannels == nil {
+				notifyChannels = []string{}
+			}
+			batch.Queue(personQuery, person.ID, person.PhoneNum, person.Email, person.VkID, person.TgID, notifyChannels)
 		}
 		for _, t := range transports {
 			batch.Queue(transportQuery, t.ID, t.Chars, t.Num, t.Region, t.Person.ID)
 		}
 	}
 
-	return r.conn.SendBatch(context.Background(), batch).Close()
+	return r.db.SendBatch(ctx, batch).Close()
 }
@@ -7,31 +7,37 @@ import (
 	"context"
 	"errors"
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
 	"log"
 	"time"
 )
 
 type directorRepoPostgres struct {
-	conn *pgx.Conn
+	db querier
 }
 
-func NewDirectorRepoPostgres(conn *pgx.Conn) repository.DirectorRepo {
-	return &directorRepoPostgres{conn: conn}
+func NewDirectorRepoPostgres(pool *pgxpool.Pool) repository.DirectorRepo {
+	return &directorRepoPostgres{db: pool}
 }
 
 const getCaseQuery = `SELECT case_id, violation_value, required_skill, case_date, is_solved, 
-       fine_decision, solved_at
+       fine_decision, solved_at, review_requested_at
 FROM cases WHERE case_id = $1`
 
-const getCaseAssessments = `SELECT expert_id, is_expert_solve, fine_decision 
+const getCaseAssessments = `SELECT expert_id, is_expert_solve, fine_decision, expired_at IS NOT NULL, 
+       voided_at IS NOT NULL
 FROM expert_cases WHERE case_id = $1`
 
-func (r *directorRepoPostgres) GetCase(caseID string) (domain.CaseStatus, error) {
-	row := r.conn.QueryRow(context.Background(), getCaseQuery, caseID)
+func (r *directorRepoPostgres) GetCase(ctx context.Context, caseID string) (domain.CaseStatus, error) {
+	row := r.db.QueryRow(ctx, getCaseQuery, caseID)
 
-	status := domain.CaseStatus{CaseAssessments: make([]domain.CaseAssessment, 0)}
+	status := domain.CaseStatus{
+		CaseAssessments: make([]domain.CaseAssessment, 0),
+		Escalations:     make([]domain.CaseEscalation, 0),
+		Overrides:       make([]domain.CaseOverride, 0),
+	}
 	err := row.Scan(&status.CaseID, &status.ViolationValue, &status.RequiredSkill, &status.CaseDate,
-		&status.IsSolved, &status.FineDecision, &status.SolvedAt)
+		&status.IsSolved, &status.FineDecision, &status.SolvedAt, &status.ReviewRequestedAt)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return domain.CaseStatus{}, errs.ErrNoCase
@@ -39,7 +45,7 @@ func (r *directorRepoPostgres) GetCase(caseID string) (domain.CaseStatus, error)
 		return domain.CaseStatus{}, err
 	}
 
-	assessmentsRows, err := r.conn.Query(context.Background(), getCaseAssessments, caseID)
+	assessmentsRows, err := r.db.Query(ctx, getCaseAssessments, caseID)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return status, nil
@@ -51,7 +57,8 @@ func (r *directorRepoPostgres) GetCase(caseID string) (domain.CaseStatus, error)
 	for assessmentsRows.Next() {
 		assessment := domain.CaseAssessment{}
 		err = assessmentsRows.Scan(
-			&assessment.ExpertID, &assessment.IsExpertSolve, &assessment.FineDecision,
+			&assessment.ExpertID, &assessment.IsExpertSolve, &assessment.FineDecision, &assessment.IsLeaseExpired,
+			&assessment.IsVoided,
 		)
 		if err != nil {
 			log.Println(err)
@@ -63,23 +70,117 @@ func (r *directorRepoPostgres) GetCase(caseID string) (domain.CaseStatus, error)
 
 	status.CaseAssessments = assessments
 
+	status.Escalations, err = r.getCaseEscalations(ctx, caseID)
+	if err != nil {
+		return domain.CaseStatus{}, err
+	}
+
+	status.Overrides, err = r.getCaseOverrides(ctx, caseID)
+	if err != nil {
+		return domain.CaseStatus{}, err
+	}
+
 	return status, nil
 }
 
+const getCaseEscalationsQuery = `SELECT escalation_id, case_id, from_skill, to_skill, reason, escalated_at
+FROM case_escalations WHERE case_id = $1
+ORDER BY escalated_at`
+
+func (r *directorRepoPostgres) getCaseEscalations(ctx context.Context, caseID string) ([]domain.CaseEscalation, error) {
+	rows, err := r.db.Query(ctx, getCaseEscalationsQuery, caseID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	escalations := make([]domain.CaseEscalation, 0)
+	for rows.Next() {
+		var escalation domain.CaseEscalation
+		err = rows.Scan(&escalation.ID, &escalation.CaseID, &escalation.FromSkill, &escalation.ToSkill,
+			&escalation.Reason, &escalation.EscalatedAt)
+		if err != nil {
+			return nil, err
+		}
+		escalations = append(escalations, escalation)
+	}
+
+	return escalations, rows.Err()
+}
+
+const getCaseOverridesQuery = `SELECT override_id, case_id, user_id, action, old_fine_decision, new_fine_decision,
+       fine_id, reason, created_at
+FROM case_overrides WHERE case_id = $1
+ORDER BY created_at`
+
+func (r *directorRepoPostgres) getCaseOverrides(ctx context.Context, caseID string) ([]domain.CaseOverride, error) {
+	rows, err := r.db.Query(ctx, getCaseOverridesQuery, caseID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	overrides := make([]domain.CaseOverride, 0)
+	for rows.Next() {
+		var override domain.CaseOverride
+		err = rows.Scan(&override.ID, &override.CaseID, &override.UserID, &override.Action,
+			&override.OldFineDecision, &override.NewFineDecision, &override.FineID, &override.Reason,
+			&override.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		overrides = append(overrides, override)
+	}
+
+	return overrides, rows.Err()
+}
+
+const getReviewCasesQuery = `SELECT c.case_id, c.violation_value, c.required_skill, c.case_date, c.review_requested_at,
+       COUNT(*) FILTER (WHERE ec.is_expert_solve AND ec.fine_decision),
+       COUNT(*) FILTER (WHERE ec.is_expert_solve AND NOT ec.fine_decision)
+FROM cases AS c
+LEFT JOIN expert_cases AS ec ON ec.case_id = c.case_id AND ec.voided_at IS NULL
+WHERE c.is_solved = false AND c.review_requested_at IS NOT NULL
+GROUP BY c.case_id
+ORDER BY c.review_requested_at, c.case_id
+LIMIT $1 OFFSET $2`
+
+func (r *directorRepoPostgres) GetReviewCases(ctx context.Context, limit int, offset int) ([]domain.ReviewCase, error) {
+	rows, err := r.db.Query(ctx, getReviewCasesQuery, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	reviewCases := make([]domain.ReviewCase, 0)
+	for rows.Next() {
+		var c domain.ReviewCase
+		err = rows.Scan(&c.CaseID, &c.ViolationValue, &c.RequiredSkill, &c.CaseDate, &c.ReviewRequestedAt,
+			&c.PositiveDecisions, &c.NegativeDecisions)
+		if err != nil {
+			return nil, err
+		}
+		reviewCases = append(reviewCases, c)
+	}
+
+	return reviewCases, rows.Err()
+}
+
 const getExpertIntervalCasesQuery = `SELECT ec.is_expert_solve , ec.fine_decision AS expert_fine_decision,
-c.fine_decision AS case_fine_decision, ec.got_at
+c.fine_decision AS case_fine_decision, ec.got_at, ec.expired_at IS NOT NULL AS is_lease_expired
 FROM expert_cases AS ec
 JOIN cases AS c ON ec.case_id = c.case_id
-WHERE expert_id = $1
+WHERE expert_id = $1 AND ec.voided_at IS NULL
 AND (ec.got_at BETWEEN $2 AND $3)
 ORDER BY ec.got_at`
 
 func (r *directorRepoPostgres) GetExpertIntervalCases(
+	ctx context.Context,
 	expertID string,
 	startDate time.Time,
 	endDate time.Time,
 ) (map[domain.Date][]domain.IntervalCase, error) {
-	rows, err := r.conn.Query(context.Background(), getExpertIntervalCasesQuery, expertID, startDate, endDate)
+	rows, err := r.db.Query(ctx, getExpertIntervalCasesQuery, expertID, startDate, endDate)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, errs.ErrNoRows
@@ -90,7 +191,8 @@ func (r *directorRepoPostgres) GetExpertIntervalCases(
 	for rows.Next() {
 		interval := domain.IntervalCase{}
 
-		err = rows.Scan(&interval.IsExpertSolve, &interval.ExpertFineDecision, &interval.CaseFineDecision, &interval.GotAt)
+		err = rows.Scan(&interval.IsExpertSolve, &interval.ExpertFineDecision, &interval.CaseFineDecision, &interval.GotAt,
+			&interval.IsLeaseExpired)
 		if err != nil {
 			log.Println(err)
 			continue
@@ -107,11 +209,26 @@ func (r *directorRepoPostgres) GetExpertIntervalCases(
 	return intervals, nil
 }
 
-const updateExpertSkillQuery = `UPDATE experts
+const updateExpertSkillQuery = `UPDATE experts AS e
 SET competence_skill = $1
-WHERE expert_id = $2`
+FROM experts AS old
+WHERE e.expert_id = old.expert_id AND e.expert_id = $2
+RETURNING old.competence_skill, e.competence_skill`
+
+// UpdateExpertSkill returns change of skill, change is empty when expert does not exist
+func (r *directorRepoPostgres) UpdateExpertSkill(
+	ctx context.Context,
+	expertID string,
+	skill int,
+) (domain.SkillChange, error) {
+	change := domain.SkillChange{ExpertID: expertID}
+	err := r.db.QueryRow(ctx, updateExpertSkillQuery, skill, expertID).Scan(&change.OldSkill, &change.NewSkill)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return domain.SkillChange{}, nil
+	}
+	if err != nil {
+		return domain.SkillChange{}, err
+	}
 
-func (r *directorRepoPostgres) UpdateExpertSkill(expertID string, skill int) error {
-	_, err := r.conn.Exec(context.Background(), updateExpertSkillQuery, skill, expertID)
-	return err
+	return change, nil
 }
@@ -7,32 +7,45 @@ import (
 	"context"
 	"errors"
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"time"
 )
 
 type expertRepoPostgres struct {
-	conn *pgx.Conn
+	db querier
 }
 
-func NewExpertRepoPostgres(conn *pgx.Conn) repository.ExpertRepo {
-	return &expertRepoPostgres{conn: conn}
+func NewExpertRepoPostgres(pool *pgxpool.Pool) repository.ExpertRepo {
+	return &expertRepoPostgres{db: pool}
 }
 
-const getLastNotSolvedCaseQuery = `SELECT case_id FROM expert_cases 
-	WHERE expert_id = $1 and is_expert_solve = false`
+// getLastNotSolvedCaseQuery skips lease, which is expired but is not reaped yet,
+// because decision of expert is rejected after end of lease
+const getLastNotSolvedCaseQuery = `SELECT expert_case_id, expert_id, case_id, got_at, lease_expires_at
+FROM expert_cases 
+WHERE expert_id = $1 and is_expert_solve = false and expired_at IS NULL and voided_at IS NULL
+AND lease_expires_at > $2
+ORDER BY got_at DESC
+LIMIT 1`
 
-func (r *expertRepoPostgres) GetLastNotSolvedCaseID(expertID string) (string, error) {
-	var caseID string
+func (r *expertRepoPostgres) GetLastNotSolvedCase(
+	ctx context.Context,
+	expertID string,
+	now time.Time,
+) (domain.ExpertCase, error) {
+	var expertCase domain.ExpertCase
 
-	row := r.conn.QueryRow(context.Background(), getLastNotSolvedCaseQuery, expertID)
-	err := row.Scan(&caseID)
+	row := r.db.QueryRow(ctx, getLastNotSolvedCaseQuery, expertID, now)
+	err := row.Scan(&expertCase.ExpertCaseID, &expertCase.ExpertID, &expertCase.CaseID,
+		&expertCase.GotAt, &expertCase.LeaseExpiresAt)
 	if errors.Is(err, pgx.ErrNoRows) {
-		return "", errs.ErrNoLastNotSolvedCase
+		return domain.ExpertCase{}, errs.ErrNoLastNotSolvedCase
 	}
 	if err != nil {
-		return "", err
+		return domain.ExpertCase{}, err
 	}
 
-	return caseID, err
+	return expertCase, err
 }
 
 const getExpertByUserIDQuery = `SELECT e.expert_id, e.is_confirmed, e.competence_skill,
@@ -41,10 +54,10 @@ const getExpertByUserIDQuery = `SELECT e.expert_id, e.is_confirmed, e.competence
 	JOIN users AS u on e.user_id = u.user_id
 	WHERE u.user_id = $1`
 
-func (r *expertRepoPostgres) GetExpertByUserID(userID string) (domain.Expert, error) {
+func (r *expertRepoPostgres) GetExpertByUserID(ctx context.Context, userID string) (domain.Expert, error) {
 	expert := domain.Expert{UserInfo: domain.UserInfo{}}
 
-	row := r.conn.QueryRow(context.Background(), getExpertByUserIDQuery, userID)
+	row := r.db.QueryRow(ctx, getExpertByUserIDQuery, userID)
 
 	err := row.Scan(&expert.ID, &expert.IsConfirmed, &expert.CompetenceSkill,
 		&expert.UserInfo.ID, &expert.UserInfo.Username, &expert.UserInfo.Password,
@@ -59,7 +72,15 @@ func (r *expertRepoPostgres) GetExpertByUserID(userID string) (domain.Expert, er
 	return expert, nil
 }
 
-const getNotSolvedCaseQuery = `SELECT c.case_id, t.transport_id, t.transport_chars, 
+// claimAttempts limits retries of claim, when locked case is filled up by concurrent claim
+const claimAttempts = 3
+
+var errCaseFilledUp = errors.New("case is filled up by concurrent claim")
+
+// lockNotSolvedCaseQuery locks the oldest case, which expert has not got yet and which needs more experts
+// for consensus: active leases are fewer than decisions left to reach consensus.
+// Cases locked by concurrent claims are skipped
+const lockNotSolvedCaseQuery = `SELECT c.case_id, t.transport_id, t.transport_chars, 
        t.transport_nums, t.region, t.person_id, cam.camera_type_id,
        cam.camera_latitude, cam.camera_longitude, cam.short_desc, v.violation_name, v.fine_amount,
        c.violation_value, c.required_skill, c.case_date,
@@ -68,25 +89,78 @@ FROM cases as c
 JOIN transports AS t ON c.transport_id = t.transport_id
 JOIN violations AS v ON c.violation_id = v.violation_id
 JOIN cameras AS cam ON c.camera_id = cam.camera_id
-WHERE c.is_solved = false and c.required_skill = $1 and c.case_id 
-NOT IN (
-	SELECT ec.case_id FROM expert_cases as ec
-	WHERE ec.expert_id = $2 and ec.is_expert_solve = true
+WHERE c.is_solved = false and c.has_image = true and c.required_skill = $1 and c.review_requested_at IS NULL
+AND NOT EXISTS (
+	SELECT 1 FROM expert_cases AS ec
+	WHERE ec.case_id = c.case_id and ec.expert_id = $2 and ec.voided_at IS NULL
 )
-LIMIT 1`
+AND ` + caseHasFreeSlotCond + `
+ORDER BY c.case_date, c.case_id
+LIMIT 1
+FOR UPDATE OF c SKIP LOCKED`
+
+// caseHasFreeSlotCond compares active leases of case c with decisions, which are left to reach
+// consensus $3 among experts of skill $1
+const caseHasFreeSlotCond = `(
+	SELECT COUNT(*) FROM expert_cases AS ec
+	WHERE ec.case_id = c.case_id and ec.is_expert_solve = false and ec.expired_at IS NULL and ec.voided_at IS NULL
+) < $3 - (
+	SELECT GREATEST(
+		COUNT(*) FILTER (WHERE ec.fine_decision = true),
+		COUNT(*) FILTER (WHERE ec.fine_decision = false)
+	)
+	FROM expert_cases AS ec
+	JOIN experts AS e ON ec.expert_id = e.expert_id
+	WHERE ec.case_id = c.case_id and ec.is_expert_solve = true and ec.voided_at IS NULL
+	and e.competence_skill = $1
+)`
+
+// insertClaimQuery checks free slot again, because statement sees leases committed by claims,
+// which held the lock of case after lockNotSolvedCaseQuery had started
+const insertClaimQuery = `INSERT INTO expert_cases 
+    (expert_case_id, expert_id, case_id, is_expert_solve, fine_decision, got_at, solved_at, lease_expires_at) 
+SELECT $4::uuid, $2::uuid, c.case_id, false, false, $5::timestamp, NULL, $6::timestamp
+FROM cases AS c
+WHERE c.case_id = $7 AND ` + caseHasFreeSlotCond
+
+func (r *expertRepoPostgres) ClaimNotSolvedCase(
+	ctx context.Context,
+	expert domain.Expert,
+	lease domain.ExpertCase,
+	consensus int,
+) (domain.Case, error) {
+	for i := 0; i < claimAttempts; i++ {
+		c, err := r.claimNotSolvedCase(ctx, expert, lease, consensus)
+		if errors.Is(err, errCaseFilledUp) {
+			continue
+		}
+		return c, err
+	}
+
+	return domain.Case{}, errs.ErrNoNotSolvedCase
+}
+
+func (r *expertRepoPostgres) claimNotSolvedCase(
+	ctx context.Context,
+	expert domain.Expert,
+	lease domain.ExpertCase,
+	consensus int,
+) (domain.Case, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return domain.Case{}, err
+	}
+	defer tx.Rollback(ctx)
 
-func (r *expertRepoPostgres) GetNotSolvedCase(expert domain.Expert) (domain.Case, error) {
 	c := domain.Case{Transport: domain.Transport{Person: &domain.Person{}},
 		Camera: domain.Camera{}, Violation: domain.Violation{},
 	}
 
-	row := r.conn.QueryRow(context.Background(), getNotSolvedCaseQuery, expert.CompetenceSkill, expert.ID)
-
-	err := row.Scan(&c.ID, &c.Transport.ID, &c.Transport.Chars, &c.Transport.Num, &c.Transport.Region,
+	row := tx.QueryRow(ctx, lockNotSolvedCaseQuery, expert.CompetenceSkill, expert.ID, consensus)
+	err = row.Scan(&c.ID, &c.Transport.ID, &c.Transport.Chars, &c.Transport.Num, &c.Transport.Region,
 		&c.Transport.Person.ID, &c.Camera.CameraType.ID, &c.Camera.Latitude, &c.Camera.Longitude,
 		&c.Camera.ShortDesc, &c.Violation.Name, &c.Violation.FineAmount, &c.ViolationValue,
 		&c.RequiredSkill, &c.Date, &c.IsSolved, &c.FineDecision)
-
 	if errors.Is(err, pgx.ErrNoRows) {
 		return domain.Case{}, errs.ErrNoNotSolvedCase
 	}
@@ -94,53 +168,67 @@ func (r *expertRepoPostgres) GetNotSolvedCase(expert domain.Expert) (domain.Case
 		return domain.Case{}, err
 	}
 
-	return c, nil
-}
-
-const insertNotSolvedCaseQuery = `INSERT INTO expert_cases 
-    (expert_case_id, expert_id, case_id, is_expert_solve, fine_decision, got_at, solved_at) 
-    VALUES ($1, $2, $3, $4, $5, $6, NULL)`
-
-func (r *expertRepoPostgres) InsertNotSolvedCase(solvedCase domain.ExpertCase) error {
-	_, err := r.conn.Exec(context.Background(), insertNotSolvedCaseQuery,
-		solvedCase.ExpertCaseID,
-		solvedCase.ExpertID,
-		solvedCase.CaseID,
-		solvedCase.IsExpertSolve,
-		solvedCase.FineDecision,
-		solvedCase.GotAt,
+	tag, err := tx.Exec(ctx, insertClaimQuery,
+		expert.CompetenceSkill,
+		expert.ID,
+		consensus,
+		lease.ExpertCaseID,
+		lease.GotAt,
+		lease.LeaseExpiresAt,
+		c.ID,
 	)
+	if err != nil {
+		return domain.Case{}, err
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.Case{}, errCaseFilledUp
+	}
 
-	return err
+	return c, tx.Commit(ctx)
 }
 
 const setCaseDecisionQuery = `UPDATE expert_cases 
 SET is_expert_solve = true, fine_decision = $1, solved_at = $2
-WHERE expert_id = $3 and case_id = $4`
+WHERE expert_id = $3 and case_id = $4
+AND is_expert_solve = false AND expired_at IS NULL AND voided_at IS NULL AND lease_expires_at > $2`
 
-func (r *expertRepoPostgres) SetCaseDecision(decision domain.Decision) error {
-	_, err := r.conn.Exec(context.Background(), setCaseDecisionQuery,
+func (r *expertRepoPostgres) SetCaseDecision(ctx context.Context, decision domain.Decision) error {
+	tag, err := r.db.Exec(ctx, setCaseDecisionQuery,
 		decision.FineDecision,
 		decision.SolvedAt,
 		decision.Expert.ID,
 		decision.CaseID,
 	)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return errs.ErrCaseNotAssigned
+	}
 
-	return err
+	return nil
 }
 
 const gGetCaseFineDecisions = `SELECT 
-    SUM(CASE WHEN ec.fine_decision = true THEN 1 ELSE 0 END) AS positive_decisions,
-    SUM(CASE WHEN ec.fine_decision = false THEN 1 ELSE 0 END) AS negative_decisions
+    COALESCE(SUM(CASE WHEN ec.is_expert_solve = true AND ec.fine_decision = true THEN 1 ELSE 0 END), 0) 
+        AS positive_decisions,
+    COALESCE(SUM(CASE WHEN ec.is_expert_solve = true AND ec.fine_decision = false THEN 1 ELSE 0 END), 0) 
+        AS negative_decisions,
+    COALESCE(SUM(CASE WHEN ec.is_expert_solve = false AND ec.expired_at IS NOT NULL THEN 1 ELSE 0 END), 0) 
+        AS expired_leases
 FROM expert_cases AS ec
 JOIN experts AS e ON ec.expert_id = e.expert_id 
-WHERE ec.case_id = $1 and ec.is_expert_solve = true and e.competence_skill = $2`
+WHERE ec.case_id = $1 and e.competence_skill = $2 and ec.voided_at IS NULL`
 
-func (r *expertRepoPostgres) GetCaseFineDecisions(caseID string, competenceSkill int) (domain.FineDecisions, error) {
-	row := r.conn.QueryRow(context.Background(), gGetCaseFineDecisions, caseID, competenceSkill)
+func (r *expertRepoPostgres) GetCaseFineDecisions(
+	ctx context.Context,
+	caseID string,
+	competenceSkill int,
+) (domain.FineDecisions, error) {
+	row := r.db.QueryRow(ctx, gGetCaseFineDecisions, caseID, competenceSkill)
 
 	var fineDecisions domain.FineDecisions
-	err := row.Scan(&fineDecisions.PositiveDecisions, &fineDecisions.NegativeDecisions)
+	err := row.Scan(&fineDecisions.PositiveDecisions, &fineDecisions.NegativeDecisions, &fineDecisions.ExpiredLeases)
 	return fineDecisions, err
 }
 
@@ -148,10 +236,56 @@ const getExpertsCountByRequiredSkillQuery = `SELECT COUNT(*)
 FROM experts
 WHERE competence_skill = $1 and is_confirmed = true`
 
-func (r *expertRepoPostgres) GetExpertsCountBySkill(competenceSkill int) (int, error) {
-	row := r.conn.QueryRow(context.Background(), getExpertsCountByRequiredSkillQuery, competenceSkill)
+func (r *expertRepoPostgres) GetExpertsCountBySkill(ctx context.Context, competenceSkill int) (int, error) {
+	row := r.db.QueryRow(ctx, getExpertsCountByRequiredSkillQuery, competenceSkill)
 
 	var cnt int
 	err := row.Scan(&cnt)
 	return cnt, err
 }
+
+const getExpiredLeaseCasesQuery = `SELECT DISTINCT case_id
+FROM expert_cases
+WHERE is_expert_solve = false AND expired_at IS NULL AND voided_at IS NULL AND lease_expires_at <= $1`
+
+func (r *expertRepoPostgres) GetExpiredLeaseCases(ctx context.Context, now time.Time) ([]string, error) {
+	rows, err := r.db.Query(ctx, getExpiredLeaseCasesQuery, now)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	caseIDs := make([]string, 0)
+	for rows.Next() {
+		var caseID string
+		err = rows.Scan(&caseID)
+		if err != nil {
+			return nil, err
+		}
+		caseIDs = append(caseIDs, caseID)
+	}
+
+	return caseIDs, rows.Err()
+}
+
+const expireCaseLeasesQuery = `UPDATE expert_cases
+SET expired_at = $2
+WHERE case_id = $1 AND is_expert_solve = false AND expired_at IS NULL AND voided_at IS NULL
+  AND lease_expires_at <= $2`
+
+func (r *expertRepoPostgres) ExpireCaseLeases(ctx context.Context, caseID string, now time.Time) (int, error) {
+	tag, err := r.db.Exec(ctx, expireCaseLeasesQuery, caseID, now)
+	if err != nil {
+		return 0, err
+	}
+	return int(tag.RowsAffected()), nil
+}
+
+const voidCaseDecisionsQuery = `UPDATE expert_cases
+SET voided_at = $2
+WHERE case_id = $1 AND voided_at IS NULL`
+
+func (r *expertRepoPostgres) VoidCaseDecisions(ctx context.Context, caseID string, voidedAt time.Time) error {
+	_, err := r.db.Exec(ctx, voidCaseDecisionsQuery, caseID, voidedAt)
+	return err
+}
@@ -0,0 +1,366 @@
+package repository
+
+import (
+	"TrafficPolice/internal/domain"
+	"TrafficPolice/internal/errs"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/golang-migrate/migrate"
+	_ "github.com/golang-migrate/migrate/database/postgres"
+	_ "github.com/golang-migrate/migrate/source/file"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"sync"
+	"testing"
+	"time"
+)
+
+// testPostgresURLEnv contains url of database for repository tests, tests are skipped without it.
+// Tests create their own rows with unique required skill, so database may contain other data
+const testPostgresURLEnv = "TEST_POSTGRES_URL"
+
+// connectTestDB applies migrations and returns pool, which has connection for every of maxConns parallel queries
+func connectTestDB(t *testing.T, maxConns int32) *pgxpool.Pool {
+	dbURL := os.Getenv(testPostgresURLEnv)
+	if dbURL == "" {
+		t.Skipf("%s is not set", testPostgresURLEnv)
+	}
+
+	m, err := migrate.New("file://../../../migrations", dbURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = m.Up()
+	if err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		t.Fatal(err)
+	}
+
+	poolCfg, err := pgxpool.ParseConfig(dbURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	poolCfg.MaxConns = maxConns
+	pool, err := pgxpool.NewWithConfig(context.Background(), poolCfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(pool.Close)
+
+	return pool
+}
+
+// claimFixture contains cases and experts of one required skill, users are users of experts
+type claimFixture struct {
+	skill   int
+	cases   []string
+	experts []domain.Expert
+	users   []string
+}
+
+func newClaimFixture(t *testing.T, pool *pgxpool.Pool, casesCnt, expertsCnt int) claimFixture {
+	ctx := context.Background()
+	f := claimFixture{skill: 1000 + int(time.Now().UnixNano()%1_000_000)}
+
+	personID, transportID, violationID := uuid.New(), uuid.New(), uuid.New()
+	cameraTypeID, cameraID := uuid.New(), uuid.New()
+	userIDs := make([]uuid.UUID, 0, expertsCnt)
+
+	batch := &pgx.Batch{}
+	batch.Queue(`INSERT INTO persons (id, phone_num) VALUES ($1, '+70000000000')`, personID)
+	batch.Queue(`INSERT INTO transports (transport_id, transport_chars, transport_nums, region, person_id)
+		VALUES ($1, 'aa', '001', '77', $2)`, transportID, personID)
+	batch.Queue(`INSERT INTO violations (violation_id, violation_name, fine_amount) VALUES ($1, 'speed', 500)`,
+		violationID)
+	batch.Queue(`INSERT INTO camera_types (camera_type_id, camera_type_name) VALUES ($1, $2)`,
+		cameraTypeID, "claim_test_"+cameraTypeID.String())
+	batch.Queue(`INSERT INTO cameras (camera_id, camera_type_id, camera_latitude, camera_longitude, short_desc)
+		VALUES ($1, $2, 0, 0, 'claim test')`, cameraID, cameraTypeID)
+
+	caseDate := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < casesCnt; i++ {
+		caseID := uuid.New()
+		f.cases = append(f.cases, caseID.String())
+		batch.Queue(`INSERT INTO cases (case_id, transport_id, camera_id, violation_id, violation_value,
+			required_skill, case_date, is_solved, fine_decision, has_image)
+			VALUES ($1, $2, $3, $4, '100km/h', $5, $6, false, false, true)`,
+			caseID, transportID, cameraID, violationID, f.skill, caseDate.AddDate(0, 0, i))
+	}
+
+	for i := 0; i < expertsCnt; i++ {
+		userID, expertID := uuid.New(), uuid.New()
+		userIDs = append(userIDs, userID)
+		f.users = append(f.users, userID.String())
+		f.experts = append(f.experts, domain.Expert{
+			ID: expertID.String(), IsConfirmed: true, CompetenceSkill: f.skill,
+		})
+		batch.Queue(`INSERT INTO users (user_id, username, hash_pass, role) VALUES ($1, $2, 'hash', 'expert')`,
+			userID, "claim_test_"+userID.String())
+		batch.Queue(`INSERT INTO experts (expert_id, is_confirmed, user_id, competence_skill)
+			VALUES ($1, true, $2, $3)`, expertID, userID, f.skill)
+	}
+
+	err := pool.SendBatch(ctx, batch).Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() {
+		cleanup := &pgx.Batch{}
+		cleanup.Queue(`DELETE FROM expert_cases WHERE case_id IN (SELECT case_id FROM cases WHERE camera_id = $1)`,
+			cameraID)
+		cleanup.Queue(`DELETE FROM case_escalations WHERE case_id IN (SELECT case_id FROM cases WHERE camera_id = $1)`,
+			cameraID)
+		cleanup.Queue(`DELETE FROM case_overrides WHERE case_id IN (SELECT case_id FROM cases WHERE camera_id = $1)`,
+			cameraID)
+		cleanup.Queue(`DELETE FROM cases WHERE camera_id = $1`, cameraID)
+		cleanup.Queue(`DELETE FROM cameras WHERE camera_id = $1`, cameraID)
+		cleanup.Queue(`DELETE FROM camera_types WHERE camera_type_id = $1`, cameraTypeID)
+		cleanup.Queue(`DELETE FROM violations WHERE violation_id = $1`, violationID)
+		cleanup.Queue(`DELETE FROM transports WHERE transport_id = $1`, transportID)
+		cleanup.Queue(`DELETE FROM persons WHERE id = $1`, personID)
+		cleanup.Queue(`DELETE FROM experts WHERE user_id = ANY($1)`, userIDs)
+		cleanup.Queue(`DELETE FROM users WHERE user_id = ANY($1)`, userIDs)
+		err := pool.SendBatch(ctx, cleanup).Close()
+		if err != nil {
+			t.Log(err)
+		}
+	})
+
+	return f
+}
+
+func newLease(expert domain.Expert) domain.ExpertCase {
+	gotAt := time.Now()
+	return domain.ExpertCase{
+		ExpertCaseID:   uuid.New().String(),
+		ExpertID:       expert.ID,
+		GotAt:          gotAt,
+		LeaseExpiresAt: gotAt.Add(time.Hour),
+	}
+}
+
+func activeLeases(t *testing.T, pool *pgxpool.Pool, caseID string) int {
+	var cnt int
+	err := pool.QueryRow(context.Background(), `SELECT COUNT(*) FROM expert_cases
+		WHERE case_id = $1 AND is_expert_solve = false AND expired_at IS NULL`, caseID).Scan(&cnt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cnt
+}
+
+func TestClaimNotSolvedCaseConcurrent(t *testing.T) {
+	expertsCnt := 30
+	pool := connectTestDB(t, int32(expertsCnt))
+	consensus := 2
+	casesCnt := 3
+	f := newClaimFixture(t, pool, casesCnt, expertsCnt)
+	repo := NewExpertRepoPostgres(pool)
+
+	// all experts claim cases at the same time, every claim runs on its own connection of pool
+	var wg sync.WaitGroup
+	claimed := make([]string, len(f.experts))
+	claimErrs := make([]error, len(f.experts))
+	for i, expert := range f.experts {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			c, err := repo.ClaimNotSolvedCase(context.Background(), expert, newLease(expert), consensus)
+			claimed[i], claimErrs[i] = c.ID, err
+		}()
+	}
+	wg.Wait()
+
+	perCase := make(map[string]int)
+	var notClaimed []domain.Expert
+	for i := range f.experts {
+		if errors.Is(claimErrs[i], errs.ErrNoNotSolvedCase) {
+			notClaimed = append(notClaimed, f.experts[i])
+			continue
+		}
+		assert.NoError(t, claimErrs[i])
+		perCase[claimed[i]]++
+	}
+	for caseID, cnt := range perCase {
+		assert.LessOrEqual(t, cnt, consensus, "case %s", caseID)
+		assert.Equal(t, cnt, activeLeases(t, pool, caseID), "case %s", caseID)
+	}
+
+	// cases skipped because of locks are claimed by experts, who have not got case yet
+	for _, expert := range notClaimed {
+		_, err := repo.ClaimNotSolvedCase(context.Background(), expert, newLease(expert), consensus)
+		if errors.Is(err, errs.ErrNoNotSolvedCase) {
+			continue
+		}
+		assert.NoError(t, err)
+	}
+	for _, caseID := range f.cases {
+		assert.Equal(t, consensus, activeLeases(t, pool, caseID), "case %s", caseID)
+	}
+}
+
+func TestClaimNotSolvedCaseOrder(t *testing.T) {
+	pool := connectTestDB(t, 1)
+	consensus := 2
+	f := newClaimFixture(t, pool, 2, 3)
+	repo := NewExpertRepoPostgres(pool)
+
+	// the oldest case needs one decision more after positive decision of first expert
+	_, err := pool.Exec(context.Background(), `INSERT INTO expert_cases
+		(expert_case_id, expert_id, case_id, is_expert_solve, fine_decision, got_at, solved_at, lease_expires_at)
+		VALUES ($1, $2, $3, true, true, NOW(), NOW(), NOW())`, uuid.New(), f.experts[0].ID, f.cases[0])
+	assert.NoError(t, err)
+
+	expectedCases := []string{f.cases[0], f.cases[1]}
+	for i, expected := range expectedCases {
+		expert := f.experts[i+1]
+		c, err := repo.ClaimNotSolvedCase(context.Background(), expert, newLease(expert), consensus)
+		assert.NoError(t, err, fmt.Sprintf("expert %d", i+1))
+		assert.Equal(t, expected, c.ID, fmt.Sprintf("expert %d", i+1))
+	}
+
+	// case, which expert has already got, is not claimed again
+	c, err := repo.ClaimNotSolvedCase(context.Background(), f.experts[1], newLease(f.experts[1]), consensus)
+	assert.NoError(t, err)
+	assert.Equal(t, f.cases[1], c.ID)
+	assert.Equal(t, 1, activeLeases(t, pool, f.cases[0]))
+}
+
+func TestClaimNotSolvedCaseInReview(t *testing.T) {
+	pool := connectTestDB(t, 1)
+	f := newClaimFixture(t, pool, 2, 1)
+	expertRepo := NewExpertRepoPostgres(pool)
+	caseRepo := NewCaseRepoPostgres(pool)
+	ctx := context.Background()
+
+	// the oldest case waits for decision of director, so it is not given to experts
+	err := caseRepo.RequestCaseReview(ctx, f.cases[0], time.Now())
+	assert.NoError(t, err)
+	err = caseRepo.InsertEscalation(ctx, domain.CaseEscalation{
+		ID: uuid.New().String(), CaseID: f.cases[0], FromSkill: f.skill, Reason: domain.NoConsensusEscalation,
+		EscalatedAt: time.Now(),
+	})
+	assert.NoError(t, err)
+
+	c, err := caseRepo.LockCase(ctx, f.cases[0])
+	assert.NoError(t, err)
+	assert.True(t, c.InReview)
+
+	expert := f.experts[0]
+	c, err = expertRepo.ClaimNotSolvedCase(ctx, expert, newLease(expert), 2)
+	assert.NoError(t, err)
+	assert.Equal(t, f.cases[1], c.ID)
+
+	reviewCases, err := NewDirectorRepoPostgres(pool).GetReviewCases(ctx, 1000, 0)
+	assert.NoError(t, err)
+	assert.Contains(t, reviewCaseIDs(reviewCases), f.cases[0])
+	assert.NotContains(t, reviewCaseIDs(reviewCases), f.cases[1])
+}
+
+func TestExpireCaseLeases(t *testing.T) {
+	pool := connectTestDB(t, 1)
+	consensus := 2
+	f := newClaimFixture(t, pool, 1, 2)
+	repo := NewExpertRepoPostgres(pool)
+	ctx := context.Background()
+
+	for _, expert := range f.experts {
+		_, err := repo.ClaimNotSolvedCase(ctx, expert, newLease(expert), consensus)
+		assert.NoError(t, err)
+	}
+
+	caseIDs, err := repo.GetExpiredLeaseCases(ctx, time.Now())
+	assert.NoError(t, err)
+	assert.NotContains(t, caseIDs, f.cases[0])
+
+	now := time.Now().Add(2 * time.Hour)
+	caseIDs, err = repo.GetExpiredLeaseCases(ctx, now)
+	assert.NoError(t, err)
+	assert.Contains(t, caseIDs, f.cases[0])
+
+	// expired lease is not returned to expert before it is reaped
+	lastCase, err := repo.GetLastNotSolvedCase(ctx, f.experts[0].ID, time.Now())
+	assert.NoError(t, err)
+	assert.Equal(t, f.cases[0], lastCase.CaseID)
+	_, err = repo.GetLastNotSolvedCase(ctx, f.experts[0].ID, now)
+	assert.Equal(t, errs.ErrNoLastNotSolvedCase, err)
+
+	expired, err := repo.ExpireCaseLeases(ctx, f.cases[0], now)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, expired)
+	assert.Equal(t, 0, activeLeases(t, pool, f.cases[0]))
+
+	// leases are expired once
+	expired, err = repo.ExpireCaseLeases(ctx, f.cases[0], now)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, expired)
+
+	caseIDs, err = repo.GetExpiredLeaseCases(ctx, now)
+	assert.NoError(t, err)
+	assert.NotContains(t, caseIDs, f.cases[0])
+}
+
+func TestReopenCase(t *testing.T) {
+	pool := connectTestDB(t, 1)
+	f := newClaimFixture(t, pool, 1, 1)
+	expertRepo := NewExpertRepoPostgres(pool)
+	caseRepo := NewCaseRepoPostgres(pool)
+	ctx := context.Background()
+
+	expert := f.experts[0]
+	c, err := expertRepo.ClaimNotSolvedCase(ctx, expert, newLease(expert), 1)
+	assert.NoError(t, err)
+	assert.Equal(t, f.cases[0], c.ID)
+	err = expertRepo.SetCaseDecision(ctx, domain.Decision{
+		CaseID: c.ID, Expert: expert, FineDecision: true, SolvedAt: time.Now(),
+	})
+	assert.NoError(t, err)
+	err = caseRepo.SetCaseFineDecision(ctx, c.ID, true, time.Now())
+	assert.NoError(t, err)
+
+	assert.Equal(t, errs.ErrNoCase, caseRepo.ReopenCase(ctx, uuid.New().String()))
+
+	// decision of expert is voided, so expert decides reopened case again
+	err = expertRepo.VoidCaseDecisions(ctx, c.ID, time.Now())
+	assert.NoError(t, err)
+	err = caseRepo.ReopenCase(ctx, c.ID)
+	assert.NoError(t, err)
+	err = caseRepo.InsertOverride(ctx, domain.CaseOverride{
+		ID: uuid.New().String(), CaseID: c.ID, UserID: f.users[0], Action: domain.ReopenCaseAction,
+		OldFineDecision: true, Reason: "photo is blurred", CreatedAt: time.Now(),
+	})
+	assert.NoError(t, err)
+
+	locked, err := caseRepo.LockCase(ctx, c.ID)
+	assert.NoError(t, err)
+	assert.False(t, locked.IsSolved)
+	assert.False(t, locked.FineDecision)
+
+	decisions, err := expertRepo.GetCaseFineDecisions(ctx, c.ID, f.skill)
+	assert.NoError(t, err)
+	assert.Equal(t, domain.FineDecisions{}, decisions)
+
+	c, err = expertRepo.ClaimNotSolvedCase(ctx, expert, newLease(expert), 1)
+	assert.NoError(t, err)
+	assert.Equal(t, f.cases[0], c.ID)
+
+	status, err := NewDirectorRepoPostgres(pool).GetCase(ctx, c.ID)
+	assert.NoError(t, err)
+	assert.Len(t, status.CaseAssessments, 2)
+	assert.Len(t, status.Overrides, 1)
+	assert.Equal(t, domain.ReopenCaseAction, status.Overrides[0].Action)
+	assert.Nil(t, status.Overrides[0].NewFineDecision)
+}
+
+func reviewCaseIDs(reviewCases []domain.ReviewCase) []string {
+	ids := make([]string, 0, len(reviewCases))
+	for _, c := range reviewCases {
+		ids = append(ids, c.CaseID)
+	}
+	return ids
+}
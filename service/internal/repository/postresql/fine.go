@@ -0,0 +1,184 @@
+package repository
+
+import (
+	"TrafficPolice/internal/domain"
+	"TrafficPolice/internal/errs"
+	"TrafficPolice/internal/repository"
+	"context"
+	"errors"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"time"
+)
+
+type fineRepoPostgres struct {
+	db querier
+}
+
+func NewFineRepoPostgres(pool *pgxpool.Pool) repository.FineRepo {
+	return &fineRepoPostgres{db: pool}
+}
+
+const fineColumns = `fine_id, case_id, reference, amount, discount_amount, discount_until, issued_at, due_at,
+	status, payment_id, paid_amount, paid_at, cancelled_at`
+
+const getCaseFineAmountQuery = `SELECT v.fine_amount
+FROM cases AS c
+JOIN violations AS v ON c.violation_id = v.violation_id
+WHERE c.case_id = $1`
+
+func (r *fineRepoPostgres) GetCaseFineAmount(ctx context.Context, caseID string) (int, error) {
+	var amount int
+	err := r.db.QueryRow(ctx, getCaseFineAmountQuery, caseID).Scan(&amount)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, errs.ErrNoCase
+	}
+
+	return amount, err
+}
+
+// activeFineIndex allows only one not cancelled fine of case, cancelled fines are kept as history
+const activeFineIndex = "fines_case_id_active_unique"
+
+const insertFineQuery = `INSERT INTO fines (` + fineColumns + `)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)`
+
+func (r *fineRepoPostgres) InsertFine(ctx context.Context, fine domain.Fine) error {
+	_, err := r.db.Exec(ctx, insertFineQuery,
+		fine.ID,
+		fine.CaseID,
+		fine.Reference,
+		fine.Amount,
+		fine.DiscountAmount,
+		fine.DiscountUntil,
+		fine.IssuedAt,
+		fine.DueAt,
+		fine.Status,
+		fine.PaymentID,
+		fine.PaidAmount,
+		fine.PaidAt,
+		fine.CancelledAt,
+	)
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == string(errs.UniqueViolationErrorCode) &&
+		pgErr.ConstraintName == activeFineIndex {
+		return errs.ErrFineAlreadyIssued
+	}
+
+	return err
+}
+
+// lockFineByReferenceQuery locks fine until end of transaction, so payments of fine are processed one by one
+const lockFineByReferenceQuery = `SELECT ` + fineColumns + `
+FROM fines
+WHERE reference = $1
+FOR UPDATE`
+
+func (r *fineRepoPostgres) LockFineByReference(ctx context.Context, reference string) (domain.Fine, error) {
+	return scanFine(r.db.QueryRow(ctx, lockFineByReferenceQuery, reference))
+}
+
+const lockFineQuery = `SELECT ` + fineColumns + `
+FROM fines
+WHERE fine_id = $1
+FOR UPDATE`
+
+func (r *fineRepoPostgres) LockFine(ctx context.Context, fineID string) (domain.Fine, error) {
+	return scanFine(r.db.QueryRow(ctx, lockFineQuery, fineID))
+}
+
+const getFineQuery = `SELECT ` + fineColumns + `
+FROM fines
+WHERE fine_id = $1`
+
+func (r *fineRepoPostgres) GetFine(ctx context.Context, fineID string) (domain.Fine, error) {
+	return scanFine(r.db.QueryRow(ctx, getFineQuery, fineID))
+}
+
+// getCaseFineQuery returns the last issued fine of case, previous fines of case are cancelled
+const getCaseFineQuery = `SELECT ` + fineColumns + `
+FROM fines
+WHERE case_id = $1
+ORDER BY issued_at DESC
+LIMIT 1`
+
+func (r *fineRepoPostgres) GetCaseFine(ctx context.Context, caseID string) (domain.Fine, error) {
+	return scanFine(r.db.QueryRow(ctx, getCaseFineQuery, caseID))
+}
+
+const updateFineStatusQuery = `UPDATE fines
+SET status = $2, payment_id = $3, paid_amount = $4, paid_at = $5, cancelled_at = $6
+WHERE fine_id = $1`
+
+func (r *fineRepoPostgres) UpdateFineStatus(ctx context.Context, fine domain.Fine) error {
+	_, err := r.db.Exec(ctx, updateFineStatusQuery,
+		fine.ID,
+		fine.Status,
+		fine.PaymentID,
+		fine.PaidAmount,
+		fine.PaidAt,
+		fine.CancelledAt,
+	)
+	return err
+}
+
+const getFinesQuery = `SELECT ` + fineColumns + `
+FROM fines
+WHERE status = ANY($1)
+ORDER BY due_at, fine_id
+LIMIT $2 OFFSET $3`
+
+func (r *fineRepoPostgres) GetFines(
+	ctx context.Context,
+	statuses []domain.FineStatus,
+	limit int,
+	offset int,
+) ([]domain.Fine, error) {
+	statusValues := make([]string, len(statuses))
+	for i, status := range statuses {
+		statusValues[i] = string(status)
+	}
+
+	rows, err := r.db.Query(ctx, getFinesQuery, statusValues, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	fines := make([]domain.Fine, 0)
+	for rows.Next() {
+		fine, err := scanFine(rows)
+		if err != nil {
+			return nil, err
+		}
+		fines = append(fines, fine)
+	}
+
+	return fines, rows.Err()
+}
+
+const markFinesOverdueQuery = `UPDATE fines
+SET status = 'overdue'
+WHERE status = 'issued' AND due_at < $1`
+
+func (r *fineRepoPostgres) MarkOverdue(ctx context.Context, now time.Time) (int64, error) {
+	tag, err := r.db.Exec(ctx, markFinesOverdueQuery, now)
+	if err != nil {
+		return 0, err
+	}
+
+	return tag.RowsAffected(), nil
+}
+
+func scanFine(row pgx.Row) (domain.Fine, error) {
+	var fine domain.Fine
+	err := row.Scan(&fine.ID, &fine.CaseID, &fine.Reference, &fine.Amount, &fine.DiscountAmount,
+		&fine.DiscountUntil, &fine.IssuedAt, &fine.DueAt, &fine.Status, &fine.PaymentID, &fine.PaidAmount,
+		&fine.PaidAt, &fine.CancelledAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return domain.Fine{}, errs.ErrNoFine
+	}
+
+	return fine, err
+}
@@ -0,0 +1,144 @@
+package repository
+
+import (
+	"TrafficPolice/internal/domain"
+	"TrafficPolice/internal/errs"
+	"TrafficPolice/internal/repository"
+	"context"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestFineRepo(t *testing.T) {
+	paymentsCnt := 10
+	pool := connectTestDB(t, int32(paymentsCnt))
+	f := newClaimFixture(t, pool, 1, 0)
+	ctx := context.Background()
+	repo := NewFineRepoPostgres(pool)
+	uow := NewUnitOfWorkPostgres(pool)
+
+	// fine is deleted before cleanup of fixture deletes case
+	t.Cleanup(func() {
+		_, err := pool.Exec(ctx, `DELETE FROM fines WHERE case_id = $1`, f.cases[0])
+		if err != nil {
+			t.Log(err)
+		}
+	})
+
+	amount, err := repo.GetCaseFineAmount(ctx, f.cases[0])
+	assert.NoError(t, err)
+	assert.Equal(t, 500, amount)
+	_, err = repo.GetCaseFineAmount(ctx, uuid.New().String())
+	assert.Equal(t, errs.ErrNoCase, err)
+
+	issuedAt := time.Now().Add(-2 * time.Hour).UTC().Truncate(time.Microsecond)
+	fine := domain.Fine{
+		ID: uuid.New().String(), CaseID: f.cases[0], Reference: uuid.New().String()[:20], Amount: amount,
+		DiscountAmount: amount, IssuedAt: issuedAt, DueAt: issuedAt.Add(time.Hour), Status: domain.FineIssued,
+	}
+	assert.NoError(t, repo.InsertFine(ctx, fine))
+
+	marked, err := repo.MarkOverdue(ctx, time.Now())
+	assert.NoError(t, err)
+	assert.GreaterOrEqual(t, marked, int64(1))
+	actual, err := repo.GetCaseFine(ctx, f.cases[0])
+	assert.NoError(t, err)
+	assert.Equal(t, domain.FineOverdue, actual.Status)
+	actual, err = repo.GetFine(ctx, fine.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, f.cases[0], actual.CaseID)
+	_, err = repo.GetFine(ctx, uuid.New().String())
+	assert.Equal(t, errs.ErrNoFine, err)
+
+	// payments of the same fine are processed one by one, so only first payment marks fine as paid
+	var wg sync.WaitGroup
+	paid := make([]bool, paymentsCnt)
+	for i := 0; i < paymentsCnt; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			err := uow.Do(ctx, func(repos repository.TxRepos) error {
+				locked, err := repos.Fine.LockFineByReference(ctx, fine.Reference)
+				if err != nil || locked.Status == domain.FinePaid {
+					return err
+				}
+
+				paymentID, paidAt := uuid.New().String(), time.Now()
+				locked.Status = domain.FinePaid
+				locked.PaymentID, locked.PaidAmount, locked.PaidAt = &paymentID, &amount, &paidAt
+				paid[i] = true
+				return repos.Fine.UpdateFineStatus(ctx, locked)
+			})
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+
+	paidCnt := 0
+	for _, p := range paid {
+		if p {
+			paidCnt++
+		}
+	}
+	assert.Equal(t, 1, paidCnt)
+
+	fines, err := repo.GetFines(ctx, []domain.FineStatus{domain.FinePaid}, 1000, 0)
+	assert.NoError(t, err)
+	assert.Contains(t, fineIDs(fines), fine.ID)
+}
+
+func TestInsertFineAfterCancel(t *testing.T) {
+	pool := connectTestDB(t, 1)
+	f := newClaimFixture(t, pool, 1, 0)
+	ctx := context.Background()
+	repo := NewFineRepoPostgres(pool)
+
+	t.Cleanup(func() {
+		_, err := pool.Exec(ctx, `DELETE FROM fines WHERE case_id = $1`, f.cases[0])
+		if err != nil {
+			t.Log(err)
+		}
+	})
+
+	issuedAt := time.Now().UTC().Truncate(time.Microsecond)
+	fine := domain.Fine{
+		ID: uuid.New().String(), CaseID: f.cases[0], Reference: uuid.New().String()[:20], Amount: 500,
+		DiscountAmount: 250, IssuedAt: issuedAt, DueAt: issuedAt.Add(time.Hour), Status: domain.FineIssued,
+	}
+	assert.NoError(t, repo.InsertFine(ctx, fine))
+
+	reissued := fine
+	reissued.ID = uuid.New().String()
+	reissued.Reference = uuid.New().String()[:20]
+	assert.Equal(t, errs.ErrFineAlreadyIssued, repo.InsertFine(ctx, reissued))
+
+	// fine, which is cancelled by director, is kept as history, when fine is issued again
+	fine.Status = domain.FineCancelled
+	fine.CancelledAt = &issuedAt
+	assert.NoError(t, repo.UpdateFineStatus(ctx, fine))
+	reissued.IssuedAt = issuedAt.Add(time.Minute)
+	assert.NoError(t, repo.InsertFine(ctx, reissued))
+
+	actual, err := repo.GetCaseFine(ctx, f.cases[0])
+	assert.NoError(t, err)
+	assert.Equal(t, reissued.ID, actual.ID)
+	assert.Equal(t, domain.FineIssued, actual.Status)
+	assert.Nil(t, actual.CancelledAt)
+
+	cancelled, err := repo.LockFineByReference(ctx, fine.Reference)
+	assert.NoError(t, err)
+	assert.Equal(t, fine.ID, cancelled.ID)
+	assert.Equal(t, domain.FineCancelled, cancelled.Status)
+}
+
+func fineIDs(fines []domain.Fine) []string {
+	ids := make([]string, len(fines))
+	for i := range fines {
+		ids[i] = fines[i].ID
+	}
+	return ids
+}
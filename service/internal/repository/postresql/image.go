@@ -0,0 +1,102 @@
+package repository
+
+import (
+	"TrafficPolice/internal/domain"
+	"TrafficPolice/internal/errs"
+	"TrafficPolice/internal/repository"
+	"context"
+	"errors"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type imageRepoPostgres struct {
+	db querier
+}
+
+func NewImageRepoPostgres(pool *pgxpool.Pool) repository.ImageRepo {
+	return &imageRepoPostgres{db: pool}
+}
+
+const getImageMetaQuery = `SELECT owner_type, owner_id, variant, blob_key, content_type, size, created_at
+FROM images
+WHERE owner_type = $1 AND owner_id = $2 AND variant = $3`
+
+func (r *imageRepoPostgres) GetImageMeta(
+	ctx context.Context,
+	owner domain.ImageOwner,
+	variant domain.ImageVariant,
+) (domain.ImageMeta, error) {
+	var meta domain.ImageMeta
+
+	err := r.db.QueryRow(ctx, getImageMetaQuery, owner.Type, owner.ID, variant).
+		Scan(&meta.Owner.Type, &meta.Owner.ID, &meta.Variant, &meta.BlobKey, &meta.ContentType, &meta.Size, &meta.CreatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return domain.ImageMeta{}, errs.ErrNoImage
+	}
+	if err != nil {
+		return domain.ImageMeta{}, err
+	}
+
+	return meta, nil
+}
+
+const getImageMetasQuery = `SELECT owner_type, owner_id, variant, blob_key, content_type, size, created_at
+FROM images
+WHERE owner_type = $1 AND owner_id = $2`
+
+func (r *imageRepoPostgres) GetImageMetas(ctx context.Context, owner domain.ImageOwner) ([]domain.ImageMeta, error) {
+	rows, err := r.db.Query(ctx, getImageMetasQuery, owner.Type, owner.ID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	metas := make([]domain.ImageMeta, 0)
+	for rows.Next() {
+		var meta domain.ImageMeta
+		err = rows.Scan(&meta.Owner.Type, &meta.Owner.ID, &meta.Variant, &meta.BlobKey,
+			&meta.ContentType, &meta.Size, &meta.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		metas = append(metas, meta)
+	}
+
+	return metas, rows.Err()
+}
+
+const setImageMetaQuery = `INSERT INTO images (owner_type, owner_id, variant, blob_key, content_type, size, created_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+ON CONFLICT (owner_type, owner_id, variant) DO UPDATE
+SET blob_key = EXCLUDED.blob_key, content_type = EXCLUDED.content_type,
+    size = EXCLUDED.size, created_at = EXCLUDED.created_at`
+
+func (r *imageRepoPostgres) SetImageMeta(ctx context.Context, meta domain.ImageMeta) error {
+	_, err := r.db.Exec(ctx, setImageMetaQuery,
+		meta.Owner.Type, meta.Owner.ID, meta.Variant, meta.BlobKey, meta.ContentType, meta.Size, meta.CreatedAt,
+	)
+	return err
+}
+
+const deleteImageMetaQuery = `DELETE FROM images WHERE owner_type = $1 AND owner_id = $2`
+
+func (r *imageRepoPostgres) DeleteImageMeta(ctx context.Context, owner domain.ImageOwner) error {
+	_, err := r.db.Exec(ctx, deleteImageMetaQuery, owner.Type, owner.ID)
+	return err
+}
+
+const countBlobRefsQuery = `SELECT COUNT(*) FROM images WHERE blob_key = $1`
+
+func (r *imageRepoPostgres) CountBlobRefs(ctx context.Context, blobKey string) (int, error) {
+	var cnt int
+	err := r.db.QueryRow(ctx, countBlobRefsQuery, blobKey).Scan(&cnt)
+	return cnt, err
+}
+
+const lockBlobQuery = `SELECT pg_advisory_xact_lock(hashtext($1))`
+
+func (r *imageRepoPostgres) LockBlob(ctx context.Context, blobKey string) error {
+	_, err := r.db.Exec(ctx, lockBlobQuery, blobKey)
+	return err
+}
@@ -0,0 +1,142 @@
+package repository
+
+import (
+	"TrafficPolice/internal/domain"
+	"TrafficPolice/internal/errs"
+	"TrafficPolice/internal/repository"
+	"context"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"time"
+)
+
+type outboxRepoPostgres struct {
+	db querier
+}
+
+func NewOutboxRepoPostgres(pool *pgxpool.Pool) repository.OutboxRepo {
+	return &outboxRepoPostgres{db: pool}
+}
+
+const insertOutboxMessageQuery = `INSERT INTO outbox 
+    (outbox_id, kind, payload, created_at, published_at, attempts, next_attempt_at, last_error, failed_at)
+VALUES ($1, $2, $3, $4, NULL, 0, $4, NULL, NULL)`
+
+func (r *outboxRepoPostgres) InsertMessage(ctx context.Context, msg domain.OutboxMessage) error {
+	_, err := r.db.Exec(ctx, insertOutboxMessageQuery, msg.ID, msg.Kind, msg.Payload, msg.CreatedAt)
+	return err
+}
+
+const markOutboxMessagePublishedQuery = `UPDATE outbox
+SET published_at = $1
+WHERE outbox_id = $2 AND published_at IS NULL`
+
+func (r *outboxRepoPostgres) MarkPublished(ctx context.Context, messageID string, publishedAt time.Time) error {
+	_, err := r.db.Exec(ctx, markOutboxMessagePublishedQuery, publishedAt, messageID)
+	return err
+}
+
+// claimPendingMessagesQuery postpones next attempt of pending messages until claimUntil $2,
+// so concurrent relays do not publish the same messages. Messages locked by concurrent claims are skipped.
+// Messages are claimed and returned in order of creation, so events of case are published in order
+const claimPendingMessagesQuery = `WITH claimed AS (
+	SELECT outbox_id AS claimed_id FROM outbox
+	WHERE published_at IS NULL AND failed_at IS NULL AND next_attempt_at <= $1
+	ORDER BY created_at, outbox_id
+	LIMIT $3
+	FOR UPDATE SKIP LOCKED
+), updated AS (
+	UPDATE outbox
+	SET next_attempt_at = $2
+	FROM claimed
+	WHERE outbox_id = claimed.claimed_id
+	RETURNING ` + outboxMessageColumns + `
+)
+SELECT ` + outboxMessageColumns + `
+FROM updated
+ORDER BY created_at, outbox_id`
+
+const outboxMessageColumns = `outbox_id, kind, payload, created_at, published_at,
+	attempts, next_attempt_at, last_error, failed_at`
+
+func (r *outboxRepoPostgres) ClaimPendingMessages(
+	ctx context.Context,
+	now time.Time,
+	claimUntil time.Time,
+	limit int,
+) ([]domain.OutboxMessage, error) {
+	rows, err := r.db.Query(ctx, claimPendingMessagesQuery, now, claimUntil, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	return scanOutboxMessages(rows)
+}
+
+const markOutboxAttemptFailedQuery = `UPDATE outbox
+SET attempts = $2, last_error = $3, next_attempt_at = $4, failed_at = $5
+WHERE outbox_id = $1 AND published_at IS NULL`
+
+func (r *outboxRepoPostgres) MarkAttemptFailed(ctx context.Context, msg domain.OutboxMessage) error {
+	_, err := r.db.Exec(ctx, markOutboxAttemptFailedQuery,
+		msg.ID,
+		msg.Attempts,
+		msg.LastError,
+		msg.NextAttemptAt,
+		msg.FailedAt,
+	)
+	return err
+}
+
+// getProblemMessagesQuery selects failed messages and messages, which are not published since stuckBefore $1
+const getProblemMessagesQuery = `SELECT ` + outboxMessageColumns + `
+FROM outbox
+WHERE published_at IS NULL AND (failed_at IS NOT NULL OR created_at <= $1)
+ORDER BY created_at
+LIMIT $2`
+
+func (r *outboxRepoPostgres) GetProblemMessages(
+	ctx context.Context,
+	stuckBefore time.Time,
+	limit int,
+) ([]domain.OutboxMessage, error) {
+	rows, err := r.db.Query(ctx, getProblemMessagesQuery, stuckBefore, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	return scanOutboxMessages(rows)
+}
+
+const replayMessageQuery = `UPDATE outbox
+SET attempts = 0, last_error = NULL, failed_at = NULL, next_attempt_at = $2
+WHERE outbox_id = $1 AND published_at IS NULL`
+
+func (r *outboxRepoPostgres) ReplayMessage(ctx context.Context, messageID string, now time.Time) error {
+	tag, err := r.db.Exec(ctx, replayMessageQuery, messageID, now)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return errs.ErrNoOutboxMessage
+	}
+
+	return nil
+}
+
+func scanOutboxMessages(rows pgx.Rows) ([]domain.OutboxMessage, error) {
+	defer rows.Close()
+
+	messages := make([]domain.OutboxMessage, 0)
+	for rows.Next() {
+		var msg domain.OutboxMessage
+		err := rows.Scan(&msg.ID, &msg.Kind, &msg.Payload, &msg.CreatedAt, &msg.PublishedAt,
+			&msg.Attempts, &msg.NextAttemptAt, &msg.LastError, &msg.FailedAt)
+		if err != nil {
+			return nil, err
+		}
+		messages = append(messages, msg)
+	}
+
+	return messages, rows.Err()
+}
@@ -0,0 +1,52 @@
+package repository
+
+import (
+	"TrafficPolice/internal/domain"
+	"context"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestClaimPendingMessagesOrder(t *testing.T) {
+	pool := connectTestDB(t, 1)
+	repo := NewOutboxRepoPostgres(pool)
+	ctx := context.Background()
+
+	// messages are created long ago, so they are claimed before messages of other tests
+	createdAt := time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+	ids := make([]string, 3)
+	for i := len(ids) - 1; i >= 0; i-- {
+		ids[i] = uuid.New().String()
+		err := repo.InsertMessage(ctx, domain.OutboxMessage{
+			ID:        ids[i],
+			Kind:      domain.FineNotificationKind,
+			Payload:   []byte(`{}`),
+			CreatedAt: createdAt.Add(time.Duration(i) * time.Second),
+		})
+		assert.NoError(t, err)
+	}
+	t.Cleanup(func() {
+		_, err := pool.Exec(ctx, `DELETE FROM outbox WHERE outbox_id = ANY($1)`, ids)
+		if err != nil {
+			t.Log(err)
+		}
+	})
+
+	now := time.Now()
+	messages, err := repo.ClaimPendingMessages(ctx, now, now.Add(time.Minute), len(ids))
+	assert.NoError(t, err)
+	claimedIDs := make([]string, 0, len(messages))
+	for _, msg := range messages {
+		claimedIDs = append(claimedIDs, msg.ID)
+	}
+	assert.Equal(t, ids, claimedIDs)
+
+	// claimed messages are hidden until claim ends
+	messages, err = repo.ClaimPendingMessages(ctx, now, now.Add(time.Minute), len(ids))
+	assert.NoError(t, err)
+	for _, msg := range messages {
+		assert.NotContains(t, ids, msg.ID)
+	}
+}
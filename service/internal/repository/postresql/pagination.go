@@ -4,22 +4,22 @@ import (
 	"TrafficPolice/internal/repository"
 	"context"
 	"fmt"
-	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
 )
 
 type paginationRepoPostgres struct {
-	conn *pgx.Conn
+	db querier
 }
 
-func NewPaginationRepoPostgres(conn *pgx.Conn) repository.PaginationRepo {
+func NewPaginationRepoPostgres(pool *pgxpool.Pool) repository.PaginationRepo {
 	return &paginationRepoPostgres{
-		conn: conn,
+		db: pool,
 	}
 }
 
-func (r *paginationRepoPostgres) GetRecordsCount(table string) (int, error) {
+func (r *paginationRepoPostgres) GetRecordsCount(ctx context.Context, table string) (int, error) {
 	sqlTableQuery := fmt.Sprintf("SELECT count(*) FROM %s", table)
-	row := r.conn.QueryRow(context.Background(), sqlTableQuery)
+	row := r.db.QueryRow(ctx, sqlTableQuery)
 
 	var recordsCount int
 	err := row.Scan(&recordsCount)
@@ -4,32 +4,36 @@ import (
 	"TrafficPolice/internal/domain"
 	"TrafficPolice/internal/repository"
 	"context"
+	"errors"
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
 	"log"
 )
 
 type ratingRepoPostgres struct {
-	conn *pgx.Conn
+	db querier
 }
 
-func NewRatingRepoPostgres(conn *pgx.Conn) repository.RatingRepo {
+func NewRatingRepoPostgres(pool *pgxpool.Pool) repository.RatingRepo {
 	return &ratingRepoPostgres{
-		conn: conn,
+		db: pool,
 	}
 }
 
 const getSolvedCaseDecisionsQuery = `SELECT ec.expert_id, ec.fine_decision = c.fine_decision AS is_right
 FROM cases AS c
 JOIN expert_cases AS ec ON c.case_id = ec.case_id
-WHERE ec.case_id = $1`
+WHERE ec.case_id = $1 AND ec.is_expert_solve = true AND ec.voided_at IS NULL`
 
 func (r *ratingRepoPostgres) GetSolvedCaseDecisions(
+	ctx context.Context,
 	caseDecision domain.CaseDecisionInfo,
 ) ([]domain.ExpertCaseDecision, error) {
-	rows, err := r.conn.Query(context.Background(), getSolvedCaseDecisionsQuery, caseDecision.CaseID)
+	rows, err := r.db.Query(ctx, getSolvedCaseDecisionsQuery, caseDecision.CaseID)
 	if err != nil {
 		return nil, err
 	}
+	defer rows.Close()
 
 	solvedDecisions := make([]domain.ExpertCaseDecision, 0)
 	for rows.Next() {
@@ -52,7 +56,7 @@ const updateInCorrectCntQuery = `UPDATE rating
 SET incorrect_cnt = incorrect_cnt+1
 WHERE expert_id = $1`
 
-func (r *ratingRepoPostgres) SetRating(decisions []domain.ExpertCaseDecision) error {
+func (r *ratingRepoPostgres) SetRating(ctx context.Context, decisions []domain.ExpertCaseDecision) error {
 	batch := &pgx.Batch{}
 
 	for _, d := range decisions {
@@ -63,14 +67,37 @@ func (r *ratingRepoPostgres) SetRating(decisions []domain.ExpertCaseDecision) er
 		}
 	}
 
-	return r.conn.SendBatch(context.Background(), batch).Close()
+	return r.db.SendBatch(ctx, batch).Close()
+}
+
+// counters are not decreased below zero, because they are cleared at the end of report period,
+// and decision may be reverted in the next period
+const revertCorrectCntQuery = `UPDATE rating
+SET correct_cnt = GREATEST(correct_cnt-1, 0)
+WHERE expert_id = $1`
+const revertInCorrectCntQuery = `UPDATE rating
+SET incorrect_cnt = GREATEST(incorrect_cnt-1, 0)
+WHERE expert_id = $1`
+
+func (r *ratingRepoPostgres) RevertRating(ctx context.Context, decisions []domain.ExpertCaseDecision) error {
+	batch := &pgx.Batch{}
+
+	for _, d := range decisions {
+		if d.IsRight {
+			batch.Queue(revertCorrectCntQuery, d.ExpertID)
+		} else {
+			batch.Queue(revertInCorrectCntQuery, d.ExpertID)
+		}
+	}
+
+	return r.db.SendBatch(ctx, batch).Close()
 }
 
 const insertExpertIdQuery = `INSERT INTO rating (expert_id, correct_cnt, incorrect_cnt)
 VALUES ($1, 0, 0) ON CONFLICT DO NOTHING`
 
-func (r *ratingRepoPostgres) InsertExpertId(expertID string) error {
-	_, err := r.conn.Exec(context.Background(), insertExpertIdQuery, expertID)
+func (r *ratingRepoPostgres) InsertExpertId(ctx context.Context, expertID string) error {
+	_, err := r.db.Exec(ctx, insertExpertIdQuery, expertID)
 	return err
 }
 
@@ -80,8 +107,8 @@ FROM rating AS r
 JOIN experts as e ON r.expert_id = e.expert_id
 JOIN users AS u ON e.user_id = u.user_id`
 
-func (r *ratingRepoPostgres) GetRating() ([]domain.RatingInfo, error) {
-	rows, err := r.conn.Query(context.Background(), getRatingQuery)
+func (r *ratingRepoPostgres) GetRating(ctx context.Context) ([]domain.RatingInfo, error) {
+	rows, err := r.db.Query(ctx, getRatingQuery)
 	if err != nil {
 		return nil, err
 	}
@@ -105,8 +132,8 @@ const getExpertsRatingQuery = `SELECT expert_id, correct_cnt, incorrect_cnt
 FROM rating
 WHERE correct_cnt + incorrect_cnt >= $1`
 
-func (r *ratingRepoPostgres) GetExpertsRating(minSolvedCases int) ([]domain.ExpertRating, error) {
-	rows, err := r.conn.Query(context.Background(), getExpertsRatingQuery, minSolvedCases)
+func (r *ratingRepoPostgres) GetExpertsRating(ctx context.Context, minSolvedCases int) ([]domain.ExpertRating, error) {
+	rows, err := r.db.Query(ctx, getExpertsRatingQuery, minSolvedCases)
 	if err != nil {
 		return nil, err
 	}
@@ -126,18 +153,27 @@ func (r *ratingRepoPostgres) GetExpertsRating(minSolvedCases int) ([]domain.Expe
 	return ratings, nil
 }
 
-const increaseCompetenceSkill = `UPDATE experts
-SET competence_skill = competence_skill+1 
-WHERE expert_id = $1`
+// competence skill of expert before update is returned from self join, which reads row before update
+const increaseCompetenceSkill = `UPDATE experts AS e
+SET competence_skill = e.competence_skill+1
+FROM experts AS old
+WHERE e.expert_id = old.expert_id AND e.expert_id = $1
+RETURNING old.competence_skill, e.competence_skill`
 
-const decreaseCompetenceSkill = `UPDATE experts
+const decreaseCompetenceSkill = `UPDATE experts AS e
 SET competence_skill = CASE
-	WHEN competence_skill > 1 THEN competence_skill - 1
+	WHEN e.competence_skill > 1 THEN e.competence_skill - 1
 	ELSE 1
 END
-WHERE expert_id = $1`
-
-func (r *ratingRepoPostgres) UpdateCompetenceSkills(infos []domain.UpdateCompetenceSkill) error {
+FROM experts AS old
+WHERE e.expert_id = old.expert_id AND e.expert_id = $1
+RETURNING old.competence_skill, e.competence_skill`
+
+// UpdateCompetenceSkills returns changes of skills of updated experts
+func (r *ratingRepoPostgres) UpdateCompetenceSkills(
+	ctx context.Context,
+	infos []domain.UpdateCompetenceSkill,
+) ([]domain.SkillChange, error) {
 	batch := &pgx.Batch{}
 
 	for _, info := range infos {
@@ -148,13 +184,34 @@ func (r *ratingRepoPostgres) UpdateCompetenceSkills(infos []domain.UpdateCompete
 		}
 	}
 
-	return r.conn.SendBatch(context.Background(), batch).Close()
+	results := r.db.SendBatch(ctx, batch)
+	defer results.Close()
+
+	changes := make([]domain.SkillChange, 0, len(infos))
+	for _, info := range infos {
+		change := domain.SkillChange{ExpertID: info.ExpertID}
+		err := results.QueryRow().Scan(&change.OldSkill, &change.NewSkill)
+		if errors.Is(err, pgx.ErrNoRows) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		changes = append(changes, change)
+	}
+
+	err := results.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	return changes, nil
 }
 
 const clearRatingQuery = `UPDATE rating
 SET correct_cnt = 0, incorrect_cnt = 0`
 
-func (r *ratingRepoPostgres) ClearRating() error {
-	_, err := r.conn.Exec(context.Background(), clearRatingQuery)
+func (r *ratingRepoPostgres) ClearRating(ctx context.Context) error {
+	_, err := r.db.Exec(ctx, clearRatingQuery)
 	return err
 }
@@ -5,17 +5,17 @@ import (
 	"TrafficPolice/internal/errs"
 	"TrafficPolice/internal/repository"
 	"context"
-	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
 	"log"
 )
 
 type trainingRepoPostgres struct {
-	conn *pgx.Conn
+	db querier
 }
 
-func NewTrainingRepoPostgres(conn *pgx.Conn) repository.TrainingRepo {
+func NewTrainingRepoPostgres(pool *pgxpool.Pool) repository.TrainingRepo {
 	return &trainingRepoPostgres{
-		conn: conn,
+		db: pool,
 	}
 }
 
@@ -36,12 +36,13 @@ LIMIT $6
 OFFSET $7`
 
 func (r *trainingRepoPostgres) GetSolvedCasesByParams(
+	ctx context.Context,
 	params domain.SolvedCasesParams,
 	paginationParams domain.PaginationParams,
 ) ([]domain.Case, error) {
 	offset := paginationParams.Limit * (paginationParams.Page - 1)
 
-	rows, err := r.conn.Query(context.Background(), getSolvedCasesByParamsQuery,
+	rows, err := r.db.Query(ctx, getSolvedCasesByParamsQuery,
 		params.CameraID, params.RequiredSkill, params.ViolationID, params.StartTime, params.EndTime,
 		paginationParams.Limit, offset,
 	)
@@ -6,23 +6,29 @@ import (
 	"context"
 	"errors"
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
 )
 
 type transportRepoPostgres struct {
-	conn *pgx.Conn
+	db querier
 }
 
-func NewTransportRepoPostgres(conn *pgx.Conn) repository.TransportRepo {
+func NewTransportRepoPostgres(pool *pgxpool.Pool) repository.TransportRepo {
 	return &transportRepoPostgres{
-		conn: conn,
+		db: pool,
 	}
 }
 
 const getTransportIDQuery = `SELECT transport_id FROM transports
 WHERE transport_chars = $1 and transport_nums = $2 and region = $3`
 
-func (r *transportRepoPostgres) GetTransportID(chars string, num string, region string) (string, error) {
-	row := r.conn.QueryRow(context.Background(), getTransportIDQuery, chars, num, region)
+func (r *transportRepoPostgres) GetTransportID(
+	ctx context.Context,
+	chars string,
+	num string,
+	region string,
+) (string, error) {
+	row := r.db.QueryRow(ctx, getTransportIDQuery, chars, num, region)
 
 	var transportID string
 	err := row.Scan(&transportID)
@@ -0,0 +1,52 @@
+package repository
+
+import (
+	"TrafficPolice/internal/repository"
+	"context"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// querier is implemented by pool and by transaction, so repositories run the same queries
+// in autocommit mode and inside unit of work
+type querier interface {
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+	SendBatch(ctx context.Context, b *pgx.Batch) pgx.BatchResults
+	Begin(ctx context.Context) (pgx.Tx, error)
+}
+
+type unitOfWorkPostgres struct {
+	pool *pgxpool.Pool
+}
+
+func NewUnitOfWorkPostgres(pool *pgxpool.Pool) repository.UnitOfWork {
+	return &unitOfWorkPostgres{pool: pool}
+}
+
+// Do commits transaction, when fn returns nil, otherwise transaction is rolled back
+func (u *unitOfWorkPostgres) Do(ctx context.Context, fn func(repos repository.TxRepos) error) error {
+	tx, err := u.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	err = fn(repository.TxRepos{
+		Expert:   &expertRepoPostgres{db: tx},
+		Case:     &caseRepoPostgres{db: tx},
+		Rating:   &ratingRepoPostgres{db: tx},
+		Outbox:   &outboxRepoPostgres{db: tx},
+		Fine:     &fineRepoPostgres{db: tx},
+		Auth:     &authRepoPostgres{db: tx},
+		Director: &directorRepoPostgres{db: tx},
+		Image:    &imageRepoPostgres{db: tx},
+	})
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
@@ -5,17 +5,18 @@ import (
 	"TrafficPolice/internal/repository"
 	"context"
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
 )
 
 type violationDBPostgres struct {
-	conn *pgx.Conn
+	db querier
 }
 
-func NewViolationDBPostgres(conn *pgx.Conn) repository.ViolationRepo {
-	return &violationDBPostgres{conn: conn}
+func NewViolationDBPostgres(pool *pgxpool.Pool) repository.ViolationRepo {
+	return &violationDBPostgres{db: pool}
 }
 
-func (db *violationDBPostgres) InsertViolations(violations []*domain.Violation) error {
+func (db *violationDBPostgres) InsertViolations(ctx context.Context, violations []*domain.Violation) error {
 	batch := &pgx.Batch{}
 
 	query := `INSERT INTO violations (violation_id, violation_name, fine_amount) VALUES ($1, $2, $3)`
@@ -23,5 +24,5 @@ func (db *violationDBPostgres) InsertViolations(violations []*domain.Violation)
 		batch.Queue(query, v.ID, v.Name, v.FineAmount)
 	}
 
-	return db.conn.SendBatch(context.Background(), batch).Close()
+	return db.db.SendBatch(ctx, batch).Close()
 }
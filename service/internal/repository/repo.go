@@ -2,90 +2,202 @@ package repository
 
 import (
 	"TrafficPolice/internal/domain"
+	"context"
 	"github.com/google/uuid"
 	"time"
 )
 
+//go:generate go run github.com/vektra/mockery/v2@v2.42.1 --name CameraRepo
 type CameraRepo interface {
-	AddCameraType(cameraType domain.CameraType) (string, error)
-	GetCameraTypeByCameraID(cameraID string) (string, error)
+	AddCameraType(ctx context.Context, cameraType domain.CameraType) (string, error)
+	GetCameraTypeByCameraID(ctx context.Context, cameraID string) (string, error)
+	GetCameraUserID(ctx context.Context, cameraID string) (string, error)
+	SetCameraTypeMapping(ctx context.Context, mapping domain.CameraTypeMapping) error
+	GetCameraTypeMapping(ctx context.Context, cameraTypeName string) (domain.CameraTypeMapping, error)
+	SetCameraRedactRegions(ctx context.Context, cameraID string, regions []domain.RedactRegion) error
+	GetCameraRedactRegions(ctx context.Context, cameraID string) ([]domain.RedactRegion, error)
 }
 
 //go:generate go run github.com/vektra/mockery/v2@v2.42.1 --name CaseRepo
 type CaseRepo interface {
-	InsertCase(c domain.Case) (string, error)
-	GetCaseByID(caseID string) (domain.Case, error)
-	GetCaseWithPersonInfo(caseID string) (domain.Case, error)
-	SetCaseFineDecision(caseID string, fineDecision bool, solvedAt time.Time) error
-	UpdateCaseRequiredSkill(caseID string, requiredSkill int) error
+	InsertCase(ctx context.Context, c domain.Case) (string, error)
+	GetCaseByID(ctx context.Context, caseID string) (domain.Case, error)
+	LockCase(ctx context.Context, caseID string) (domain.Case, error)
+	GetCaseWithPersonInfo(ctx context.Context, caseID string) (domain.Case, error)
+	SetCaseFineDecision(ctx context.Context, caseID string, fineDecision bool, solvedAt time.Time) error
+	UpdateCaseRequiredSkill(ctx context.Context, caseID string, requiredSkill int) error
+	// RequestCaseReview hides case from experts until director decides it
+	RequestCaseReview(ctx context.Context, caseID string, requestedAt time.Time) error
+	InsertEscalation(ctx context.Context, escalation domain.CaseEscalation) error
+	// ReopenCase makes solved case not solved and returns it to experts of its required skill
+	ReopenCase(ctx context.Context, caseID string) error
+	InsertOverride(ctx context.Context, override domain.CaseOverride) error
+	SetCaseHasImage(ctx context.Context, caseID string) error
+	GetCaseImageInfo(ctx context.Context, caseID string) (domain.CaseImageInfo, error)
+	// LockCaseImageInfo locks case until end of transaction, so images of case are uploaded one by one
+	LockCaseImageInfo(ctx context.Context, caseID string) (domain.CaseImageInfo, error)
+	SetCaseImageOverwriteAllowed(ctx context.Context, caseID string, allowed bool) error
 }
 
 type ContactInfoRepo interface {
-	InsertContactInfo(m map[string][]*domain.Transport) error
+	InsertContactInfo(ctx context.Context, m map[string][]*domain.Transport) error
 }
 
 type ViolationRepo interface {
-	InsertViolations(violations []*domain.Violation) error
+	InsertViolations(ctx context.Context, violations []*domain.Violation) error
+}
+
+//go:generate go run github.com/vektra/mockery/v2@v2.42.1 --name ImageRepo
+type ImageRepo interface {
+	GetImageMeta(ctx context.Context, owner domain.ImageOwner, variant domain.ImageVariant) (domain.ImageMeta, error)
+	GetImageMetas(ctx context.Context, owner domain.ImageOwner) ([]domain.ImageMeta, error)
+	SetImageMeta(ctx context.Context, meta domain.ImageMeta) error
+	DeleteImageMeta(ctx context.Context, owner domain.ImageOwner) error
+	CountBlobRefs(ctx context.Context, blobKey string) (int, error)
+	// LockBlob locks blob key until end of transaction, so blob is not deleted, while it gets new refs
+	LockBlob(ctx context.Context, blobKey string) error
 }
 
 //go:generate go run github.com/vektra/mockery/v2@v2.42.1 --name AuthRepo
 type AuthRepo interface {
-	CheckUserExists(username string) bool
-	InsertUser(user domain.UserInfo) error
-	InsertExpert(expert domain.Expert) error
-	InsertCamera(camera domain.Camera, userID uuid.UUID) (string, error)
-	InsertDirector(director domain.Director) error
-	SignIn(username string) (domain.UserInfo, error)
-	ConfirmExpert(data domain.ConfirmExpert) error
+	CheckUserExists(ctx context.Context, username string) bool
+	InsertUser(ctx context.Context, user domain.UserInfo) error
+	InsertExpert(ctx context.Context, expert domain.Expert) error
+	InsertCamera(ctx context.Context, camera domain.Camera, userID uuid.UUID) (string, error)
+	InsertDirector(ctx context.Context, director domain.Director) error
+	SignIn(ctx context.Context, username string) (domain.UserInfo, error)
+	ConfirmExpert(ctx context.Context, data domain.ConfirmExpert) error
 }
 
 //go:generate go run github.com/vektra/mockery/v2@v2.42.1 --name ExpertRepo
 type ExpertRepo interface {
-	GetLastNotSolvedCaseID(expertID string) (string, error)
-	GetExpertByUserID(userID string) (domain.Expert, error)
-	GetNotSolvedCase(expert domain.Expert) (domain.Case, error)
-	InsertNotSolvedCase(solvedCase domain.ExpertCase) error
-	SetCaseDecision(decision domain.Decision) error
-	GetCaseFineDecisions(caseID string, competenceSkill int) (domain.FineDecisions, error)
-	GetExpertsCountBySkill(competenceSkill int) (int, error)
+	// GetLastNotSolvedCase returns case of expert, which lease is not expired at now
+	GetLastNotSolvedCase(ctx context.Context, expertID string, now time.Time) (domain.ExpertCase, error)
+	GetExpertByUserID(ctx context.Context, userID string) (domain.Expert, error)
+	ClaimNotSolvedCase(
+		ctx context.Context,
+		expert domain.Expert,
+		lease domain.ExpertCase,
+		consensus int,
+	) (domain.Case, error)
+	SetCaseDecision(ctx context.Context, decision domain.Decision) error
+	GetCaseFineDecisions(ctx context.Context, caseID string, competenceSkill int) (domain.FineDecisions, error)
+	GetExpertsCountBySkill(ctx context.Context, competenceSkill int) (int, error)
+	// GetExpiredLeaseCases returns ids of cases, which have leases expired until now and not marked yet
+	GetExpiredLeaseCases(ctx context.Context, now time.Time) ([]string, error)
+	// ExpireCaseLeases marks leases of case expired until now and returns count of them
+	ExpireCaseLeases(ctx context.Context, caseID string, now time.Time) (int, error)
+	// VoidCaseDecisions voids decisions and leases of case, so they are not counted and experts may get case again
+	VoidCaseDecisions(ctx context.Context, caseID string, voidedAt time.Time) error
 }
 
 type TrainingRepo interface {
-	GetSolvedCasesByParams(params domain.SolvedCasesParams, paginationParams domain.PaginationParams) ([]domain.Case, error)
+	GetSolvedCasesByParams(
+		ctx context.Context,
+		params domain.SolvedCasesParams,
+		paginationParams domain.PaginationParams,
+	) ([]domain.Case, error)
 }
 
 //go:generate go run github.com/vektra/mockery/v2@v2.42.1 --name PaginationRepo
 type PaginationRepo interface {
-	GetRecordsCount(table string) (int, error)
+	GetRecordsCount(ctx context.Context, table string) (int, error)
 }
 
 //go:generate go run github.com/vektra/mockery/v2@v2.42.1 --name TransportRepo
 type TransportRepo interface {
-	GetTransportID(chars string, num string, region string) (string, error)
+	GetTransportID(ctx context.Context, chars string, num string, region string) (string, error)
 }
 
 //go:generate go run github.com/vektra/mockery/v2@v2.42.1 --name RatingRepo
 type RatingRepo interface {
-	GetSolvedCaseDecisions(caseDecision domain.CaseDecisionInfo) ([]domain.ExpertCaseDecision, error)
-	SetRating(decisions []domain.ExpertCaseDecision) error
-	InsertExpertId(expertID string) error
-	GetRating() ([]domain.RatingInfo, error)
-	GetExpertsRating(minSolvedCases int) ([]domain.ExpertRating, error)
-	UpdateCompetenceSkills(infos []domain.UpdateCompetenceSkill) error
-	ClearRating() error
+	GetSolvedCaseDecisions(
+		ctx context.Context,
+		caseDecision domain.CaseDecisionInfo,
+	) ([]domain.ExpertCaseDecision, error)
+	SetRating(ctx context.Context, decisions []domain.ExpertCaseDecision) error
+	// RevertRating reverts counters, which were increased by SetRating with the same decisions
+	RevertRating(ctx context.Context, decisions []domain.ExpertCaseDecision) error
+	InsertExpertId(ctx context.Context, expertID string) error
+	GetRating(ctx context.Context) ([]domain.RatingInfo, error)
+	GetExpertsRating(ctx context.Context, minSolvedCases int) ([]domain.ExpertRating, error)
+	UpdateCompetenceSkills(ctx context.Context, infos []domain.UpdateCompetenceSkill) ([]domain.SkillChange, error)
+	ClearRating(ctx context.Context) error
+}
+
+//go:generate go run github.com/vektra/mockery/v2@v2.42.1 --name OutboxRepo
+type OutboxRepo interface {
+	InsertMessage(ctx context.Context, msg domain.OutboxMessage) error
+	MarkPublished(ctx context.Context, messageID string, publishedAt time.Time) error
+	ClaimPendingMessages(
+		ctx context.Context,
+		now time.Time,
+		claimUntil time.Time,
+		limit int,
+	) ([]domain.OutboxMessage, error)
+	MarkAttemptFailed(ctx context.Context, msg domain.OutboxMessage) error
+	GetProblemMessages(ctx context.Context, stuckBefore time.Time, limit int) ([]domain.OutboxMessage, error)
+	ReplayMessage(ctx context.Context, messageID string, now time.Time) error
+}
+
+//go:generate go run github.com/vektra/mockery/v2@v2.42.1 --name FineRepo
+type FineRepo interface {
+	// GetCaseFineAmount returns fine amount of violation of case
+	GetCaseFineAmount(ctx context.Context, caseID string) (int, error)
+	// InsertFine keeps cancelled fines of case, so fine may be issued again after cancellation.
+	// It returns errs.ErrFineAlreadyIssued, when case has not cancelled fine
+	InsertFine(ctx context.Context, fine domain.Fine) error
+	// LockFineByReference locks fine until end of transaction, so payments of fine are processed one by one
+	LockFineByReference(ctx context.Context, reference string) (domain.Fine, error)
+	LockFine(ctx context.Context, fineID string) (domain.Fine, error)
+	GetFine(ctx context.Context, fineID string) (domain.Fine, error)
+	// GetCaseFine returns the last issued fine of case
+	GetCaseFine(ctx context.Context, caseID string) (domain.Fine, error)
+	// UpdateFineStatus saves status, payment and cancel time of fine
+	UpdateFineStatus(ctx context.Context, fine domain.Fine) error
+	GetFines(ctx context.Context, statuses []domain.FineStatus, limit int, offset int) ([]domain.Fine, error)
+	// MarkOverdue marks issued fines, which are not paid until due time, as overdue
+	MarkOverdue(ctx context.Context, now time.Time) (int64, error)
+}
+
+// TxRepos are repositories, which run queries in transaction of unit of work
+type TxRepos struct {
+	Expert   ExpertRepo
+	Case     CaseRepo
+	Rating   RatingRepo
+	Outbox   OutboxRepo
+	Fine     FineRepo
+	Auth     AuthRepo
+	Director DirectorRepo
+	Image    ImageRepo
+}
+
+//go:generate go run github.com/vektra/mockery/v2@v2.42.1 --name UnitOfWork
+type UnitOfWork interface {
+	// Do runs fn in transaction, which is committed only when fn returns nil
+	Do(ctx context.Context, fn func(repos TxRepos) error) error
 }
 
 //go:generate go run github.com/vektra/mockery/v2@v2.42.1 --name DirectorRepo
 type DirectorRepo interface {
-	GetCase(caseID string) (domain.CaseStatus, error)
+	GetCase(ctx context.Context, caseID string) (domain.CaseStatus, error)
 	GetExpertIntervalCases(
+		ctx context.Context,
 		expertID string,
 		startDate time.Time,
 		endDate time.Time) (map[domain.Date][]domain.IntervalCase, error)
-	UpdateExpertSkill(expertID string, skill int) error
+	UpdateExpertSkill(ctx context.Context, expertID string, skill int) (domain.SkillChange, error)
+	// GetReviewCases returns not solved cases, which wait for decision of director, from the oldest request
+	GetReviewCases(ctx context.Context, limit int, offset int) ([]domain.ReviewCase, error)
+}
+
+//go:generate go run github.com/vektra/mockery/v2@v2.42.1 --name DeliveryRepo
+type DeliveryRepo interface {
+	// GetCaseDeliveries returns deliveries of fine notification about case, empty when notification was not sent
+	GetCaseDeliveries(ctx context.Context, caseID string) ([]domain.NotificationDelivery, error)
 }
 
 //go:generate go run github.com/vektra/mockery/v2@v2.42.1 --name CheckerRepo
 type CheckerRepo interface {
-	CheckExpertExists(expertID string) (bool, error)
+	CheckExpertExists(ctx context.Context, expertID string) (bool, error)
 }
@@ -6,23 +6,25 @@ import (
 	"TrafficPolice/internal/repository"
 	"TrafficPolice/internal/tokens"
 	"TrafficPolice/pkg/hash"
+	"context"
 	"github.com/google/uuid"
 	"time"
 )
 
 //go:generate go run github.com/vektra/mockery/v2@v2.42.1 --name AuthService
 type AuthService interface {
-	RegisterExpert(input domain.UserInfo) error
-	RegisterCamera(info domain.RegisterCamera) (string, error)
-	RegisterDirectors(users []domain.UserInfo) error
-	SignIn(input domain.UserInfo) (domain.Tokens, error)
-	ConfirmExpert(data domain.ConfirmExpert) error
+	RegisterExpert(ctx context.Context, input domain.UserInfo) error
+	RegisterCamera(ctx context.Context, info domain.RegisterCamera) (string, error)
+	RegisterDirectors(ctx context.Context, users []domain.UserInfo) error
+	SignIn(ctx context.Context, input domain.UserInfo) (domain.Tokens, error)
+	ConfirmExpert(ctx context.Context, data domain.ConfirmExpert) error
 	ParseAccessToken(accessToken string) (tokens.TokenInfo, error)
 }
 
 type authService struct {
 	authRepo       repository.AuthRepo
-	ratingRepo     repository.RatingRepo
+	uow            repository.UnitOfWork
+	events         EventPublisher
 	hasher         hash.PasswordHasher
 	tokenManager   tokens.TokenManager
 	accessTokenTTL time.Duration
@@ -30,21 +32,23 @@ type authService struct {
 
 func NewAuthService(
 	repo repository.AuthRepo,
-	ratingRepo repository.RatingRepo,
+	uow repository.UnitOfWork,
+	events EventPublisher,
 	hasher hash.PasswordHasher,
 	tokenManager tokens.TokenManager,
 ) AuthService {
 	return &authService{
 		authRepo:       repo,
-		ratingRepo:     ratingRepo,
+		uow:            uow,
+		events:         events,
 		hasher:         hasher,
 		tokenManager:   tokenManager,
 		accessTokenTTL: 30 * 24 * time.Hour,
 	}
 }
 
-func (s *authService) RegisterExpert(user domain.UserInfo) error {
-	alreadyExists := s.authRepo.CheckUserExists(user.Username)
+func (s *authService) RegisterExpert(ctx context.Context, user domain.UserInfo) error {
+	alreadyExists := s.authRepo.CheckUserExists(ctx, user.Username)
 	if alreadyExists {
 		return errs.ErrAlreadyExists
 	}
@@ -57,12 +61,12 @@ func (s *authService) RegisterExpert(user domain.UserInfo) error {
 	user.ID = uuid.New()
 	user.Password = hashedPass
 	user.UserRole = "expert"
-	err = s.authRepo.InsertUser(user)
+	err = s.authRepo.InsertUser(ctx, user)
 	if err != nil {
 		return err
 	}
 
-	err = s.authRepo.InsertExpert(domain.Expert{
+	err = s.authRepo.InsertExpert(ctx, domain.Expert{
 		ID:       uuid.New().String(),
 		UserInfo: user,
 	})
@@ -70,8 +74,8 @@ func (s *authService) RegisterExpert(user domain.UserInfo) error {
 	return err
 }
 
-func (s *authService) RegisterCamera(info domain.RegisterCamera) (string, error) {
-	alreadyExists := s.authRepo.CheckUserExists(info.Username)
+func (s *authService) RegisterCamera(ctx context.Context, info domain.RegisterCamera) (string, error) {
+	alreadyExists := s.authRepo.CheckUserExists(ctx, info.Username)
 	if alreadyExists {
 		return "", errs.ErrAlreadyExists
 	}
@@ -90,17 +94,17 @@ func (s *authService) RegisterCamera(info domain.RegisterCamera) (string, error)
 		Password: hashedPass,
 		UserRole: string(domain.CameraRole),
 	}
-	err = s.authRepo.InsertUser(userInfo)
+	err = s.authRepo.InsertUser(ctx, userInfo)
 	if err != nil {
 		return "", err
 	}
 
-	return s.authRepo.InsertCamera(info.Camera, userID)
+	return s.authRepo.InsertCamera(ctx, info.Camera, userID)
 }
 
-func (s *authService) RegisterDirectors(users []domain.UserInfo) error {
+func (s *authService) RegisterDirectors(ctx context.Context, users []domain.UserInfo) error {
 	for i := range users {
-		alreadyExists := s.authRepo.CheckUserExists(users[i].Username)
+		alreadyExists := s.authRepo.CheckUserExists(ctx, users[i].Username)
 		if alreadyExists {
 			continue
 		}
@@ -114,12 +118,12 @@ func (s *authService) RegisterDirectors(users []domain.UserInfo) error {
 		users[i].Password = hashedPass
 		users[i].UserRole = "director"
 
-		err = s.authRepo.InsertUser(users[i])
+		err = s.authRepo.InsertUser(ctx, users[i])
 		if err != nil {
 			return err
 		}
 
-		err = s.authRepo.InsertDirector(domain.Director{
+		err = s.authRepo.InsertDirector(ctx, domain.Director{
 			ID:   uuid.New(),
 			User: users[i],
 		})
@@ -132,8 +136,8 @@ func (s *authService) RegisterDirectors(users []domain.UserInfo) error {
 	return nil
 }
 
-func (s *authService) SignIn(input domain.UserInfo) (domain.Tokens, error) {
-	user, err := s.authRepo.SignIn(input.Username)
+func (s *authService) SignIn(ctx context.Context, input domain.UserInfo) (domain.Tokens, error) {
+	user, err := s.authRepo.SignIn(ctx, input.Username)
 	if err != nil {
 		return domain.Tokens{}, err
 	}
@@ -163,12 +167,23 @@ func (s *authService) SignIn(input domain.UserInfo) (domain.Tokens, error) {
 	return domain.Tokens{AccessToken: accessToken, RefreshToken: refreshToken}, nil
 }
 
-func (s *authService) ConfirmExpert(data domain.ConfirmExpert) error {
-	err := s.authRepo.ConfirmExpert(data)
-	if err != nil {
-		return err
-	}
-	return s.ratingRepo.InsertExpertId(data.ExpertID)
+// ConfirmExpert saves confirmation of expert with event about confirmed expert in one transaction
+func (s *authService) ConfirmExpert(ctx context.Context, data domain.ConfirmExpert) error {
+	return s.uow.Do(ctx, func(repos repository.TxRepos) error {
+		err := repos.Auth.ConfirmExpert(ctx, data)
+		if err != nil {
+			return err
+		}
+		err = repos.Rating.InsertExpertId(ctx, data.ExpertID)
+		if err != nil {
+			return err
+		}
+		if !data.IsConfirmed {
+			return nil
+		}
+
+		return s.events.Publish(ctx, repos.Outbox, domain.ExpertConfirmed{ExpertID: data.ExpertID})
+	})
 }
 
 func (s *authService) ParseAccessToken(accessToken string) (tokens.TokenInfo, error) {
@@ -5,8 +5,10 @@ import (
 	"TrafficPolice/internal/errs"
 	"TrafficPolice/internal/repository"
 	"TrafficPolice/internal/repository/mocks"
+	mocksservice "TrafficPolice/internal/service/mocks"
 	"TrafficPolice/internal/tokens"
 	"TrafficPolice/pkg/hash"
+	"context"
 	"errors"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
@@ -22,29 +24,25 @@ func TestRegisterExpert(t *testing.T) {
 	hasher := newHasher("salt")
 
 	testCases := []struct {
-		name            string
-		buildAuthRepo   func() repository.AuthRepo
-		buildRatingRepo func() repository.RatingRepo
-		buildUserInfo   func() domain.UserInfo
-		expectedErr     error
+		name          string
+		buildAuthRepo func() repository.AuthRepo
+		buildUserInfo func() domain.UserInfo
+		expectedErr   error
 	}{
 		{
 			name: "Should be registered expert, no error",
 			buildAuthRepo: func() repository.AuthRepo {
 				mockRepo := mocks.NewAuthRepo(t)
 
-				mockRepo.On("CheckUserExists", mock.Anything).
+				mockRepo.On("CheckUserExists", mock.Anything, mock.Anything).
 					Return(false)
-				mockRepo.On("InsertUser", mock.Anything).
+				mockRepo.On("InsertUser", mock.Anything, mock.Anything).
 					Return(nil)
-				mockRepo.On("InsertExpert", mock.Anything).
+				mockRepo.On("InsertExpert", mock.Anything, mock.Anything).
 					Return(nil)
 
 				return mockRepo
 			},
-			buildRatingRepo: func() repository.RatingRepo {
-				return mocks.NewRatingRepo(t)
-			},
 			buildUserInfo: func() domain.UserInfo {
 				return domain.UserInfo{}
 			},
@@ -54,14 +52,11 @@ func TestRegisterExpert(t *testing.T) {
 			name: "User already exists, expected ErrAlreadyExists error",
 			buildAuthRepo: func() repository.AuthRepo {
 				mockRepo := mocks.NewAuthRepo(t)
-				mockRepo.On("CheckUserExists", mock.Anything).
+				mockRepo.On("CheckUserExists", mock.Anything, mock.Anything).
 					Return(true)
 
 				return mockRepo
 			},
-			buildRatingRepo: func() repository.RatingRepo {
-				return mocks.NewRatingRepo(t)
-			},
 			buildUserInfo: func() domain.UserInfo {
 				return domain.UserInfo{}
 			},
@@ -72,14 +67,14 @@ func TestRegisterExpert(t *testing.T) {
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
 			authRepo := tc.buildAuthRepo()
-			ratingRepo := tc.buildRatingRepo()
 
 			tokenManager, err := tokens.NewTokenManager("sign")
 			assert.NoError(t, err)
 
-			authService := NewAuthService(authRepo, ratingRepo, hasher, tokenManager)
+			authService := NewAuthService(authRepo, mocks.NewUnitOfWork(t), mocksservice.NewEventPublisher(t), hasher,
+				tokenManager)
 
-			err = authService.RegisterExpert(tc.buildUserInfo())
+			err = authService.RegisterExpert(context.Background(), tc.buildUserInfo())
 			assert.Equal(t, tc.expectedErr, err)
 		})
 	}
@@ -91,7 +86,6 @@ func TestRegisterCamera(t *testing.T) {
 	testCases := []struct {
 		name                string
 		buildAuthRepo       func() repository.AuthRepo
-		buildRatingRepo     func() repository.RatingRepo
 		buildRegisterCamera func() domain.RegisterCamera
 		expectedErr         error
 	}{
@@ -100,18 +94,15 @@ func TestRegisterCamera(t *testing.T) {
 			buildAuthRepo: func() repository.AuthRepo {
 				mockRepo := mocks.NewAuthRepo(t)
 
-				mockRepo.On("CheckUserExists", mock.Anything).
+				mockRepo.On("CheckUserExists", mock.Anything, mock.Anything).
 					Return(false)
-				mockRepo.On("InsertUser", mock.Anything).
+				mockRepo.On("InsertUser", mock.Anything, mock.Anything).
 					Return(nil)
-				mockRepo.On("InsertCamera", mock.Anything, mock.Anything).
+				mockRepo.On("InsertCamera", mock.Anything, mock.Anything, mock.Anything).
 					Return("some id", nil)
 
 				return mockRepo
 			},
-			buildRatingRepo: func() repository.RatingRepo {
-				return mocks.NewRatingRepo(t)
-			},
 			buildRegisterCamera: func() domain.RegisterCamera {
 				return domain.RegisterCamera{}
 			},
@@ -122,14 +113,11 @@ func TestRegisterCamera(t *testing.T) {
 			buildAuthRepo: func() repository.AuthRepo {
 				mockRepo := mocks.NewAuthRepo(t)
 
-				mockRepo.On("CheckUserExists", mock.Anything).
+				mockRepo.On("CheckUserExists", mock.Anything, mock.Anything).
 					Return(true)
 
 				return mockRepo
 			},
-			buildRatingRepo: func() repository.RatingRepo {
-				return mocks.NewRatingRepo(t)
-			},
 			buildRegisterCamera: func() domain.RegisterCamera {
 				return domain.RegisterCamera{}
 			},
@@ -140,14 +128,14 @@ func TestRegisterCamera(t *testing.T) {
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
 			authRepo := tc.buildAuthRepo()
-			ratingRepo := tc.buildRatingRepo()
 
 			tokenManager, err := tokens.NewTokenManager("sign")
 			assert.NoError(t, err)
 
-			authService := NewAuthService(authRepo, ratingRepo, hasher, tokenManager)
+			authService := NewAuthService(authRepo, mocks.NewUnitOfWork(t), mocksservice.NewEventPublisher(t), hasher,
+				tokenManager)
 
-			_, err = authService.RegisterCamera(tc.buildRegisterCamera())
+			_, err = authService.RegisterCamera(context.Background(), tc.buildRegisterCamera())
 			assert.Equal(t, tc.expectedErr, err)
 		})
 	}
@@ -157,29 +145,25 @@ func TestRegisterDirectors(t *testing.T) {
 	hasher := newHasher("salt")
 
 	testCases := []struct {
-		name            string
-		buildAuthRepo   func() repository.AuthRepo
-		buildRatingRepo func() repository.RatingRepo
-		buildUsers      func() []domain.UserInfo
-		expectedErr     error
+		name          string
+		buildAuthRepo func() repository.AuthRepo
+		buildUsers    func() []domain.UserInfo
+		expectedErr   error
 	}{
 		{
 			name: "Should be registered all directors",
 			buildAuthRepo: func() repository.AuthRepo {
 				mockRepo := mocks.NewAuthRepo(t)
 
-				mockRepo.On("CheckUserExists", mock.Anything).
+				mockRepo.On("CheckUserExists", mock.Anything, mock.Anything).
 					Return(false)
-				mockRepo.On("InsertUser", mock.Anything).
+				mockRepo.On("InsertUser", mock.Anything, mock.Anything).
 					Return(nil)
-				mockRepo.On("InsertDirector", mock.Anything, mock.Anything).
+				mockRepo.On("InsertDirector", mock.Anything, mock.Anything, mock.Anything).
 					Return(nil)
 
 				return mockRepo
 			},
-			buildRatingRepo: func() repository.RatingRepo {
-				return mocks.NewRatingRepo(t)
-			},
 			buildUsers: func() []domain.UserInfo {
 				return []domain.UserInfo{
 					{Username: "director1", Password: "director1"},
@@ -193,14 +177,14 @@ func TestRegisterDirectors(t *testing.T) {
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
 			authRepo := tc.buildAuthRepo()
-			ratingRepo := tc.buildRatingRepo()
 
 			tokenManager, err := tokens.NewTokenManager("sign")
 			assert.NoError(t, err)
 
-			authService := NewAuthService(authRepo, ratingRepo, hasher, tokenManager)
+			authService := NewAuthService(authRepo, mocks.NewUnitOfWork(t), mocksservice.NewEventPublisher(t), hasher,
+				tokenManager)
 
-			err = authService.RegisterDirectors(tc.buildUsers())
+			err = authService.RegisterDirectors(context.Background(), tc.buildUsers())
 			assert.Equal(t, tc.expectedErr, err)
 		})
 	}
@@ -234,25 +218,21 @@ func TestSignIn(t *testing.T) {
 	}
 
 	testCases := []struct {
-		name            string
-		buildAuthRepo   func() repository.AuthRepo
-		buildRatingRepo func() repository.RatingRepo
-		buildUser       func() domain.UserInfo
-		expectedErr     error
+		name          string
+		buildAuthRepo func() repository.AuthRepo
+		buildUser     func() domain.UserInfo
+		expectedErr   error
 	}{
 		{
 			name: "User should be signed up. No error",
 			buildAuthRepo: func() repository.AuthRepo {
 				mockRepo := mocks.NewAuthRepo(t)
 
-				mockRepo.On("SignIn", mock.Anything).
+				mockRepo.On("SignIn", mock.Anything, mock.Anything).
 					Return(buildOutputUser("user_password"), nil)
 
 				return mockRepo
 			},
-			buildRatingRepo: func() repository.RatingRepo {
-				return mocks.NewRatingRepo(t)
-			},
 			buildUser: func() domain.UserInfo {
 				return buildInputUser("user_password")
 			},
@@ -263,14 +243,11 @@ func TestSignIn(t *testing.T) {
 			buildAuthRepo: func() repository.AuthRepo {
 				mockRepo := mocks.NewAuthRepo(t)
 
-				mockRepo.On("SignIn", mock.Anything).
+				mockRepo.On("SignIn", mock.Anything, mock.Anything).
 					Return(domain.UserInfo{}, errs.ErrNoRows)
 
 				return mockRepo
 			},
-			buildRatingRepo: func() repository.RatingRepo {
-				return mocks.NewRatingRepo(t)
-			},
 			buildUser: func() domain.UserInfo {
 				return buildInputUser("user_password")
 			},
@@ -281,14 +258,11 @@ func TestSignIn(t *testing.T) {
 			buildAuthRepo: func() repository.AuthRepo {
 				mockRepo := mocks.NewAuthRepo(t)
 
-				mockRepo.On("SignIn", mock.Anything).
+				mockRepo.On("SignIn", mock.Anything, mock.Anything).
 					Return(buildOutputUser("right_password"), nil)
 
 				return mockRepo
 			},
-			buildRatingRepo: func() repository.RatingRepo {
-				return mocks.NewRatingRepo(t)
-			},
 			buildUser: func() domain.UserInfo {
 				return buildInputUser("wrong_password")
 			},
@@ -299,11 +273,11 @@ func TestSignIn(t *testing.T) {
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
 			authRepo := tc.buildAuthRepo()
-			ratingRepo := tc.buildRatingRepo()
 
-			authService := NewAuthService(authRepo, ratingRepo, hasher, tokenManager)
+			authService := NewAuthService(authRepo, mocks.NewUnitOfWork(t), mocksservice.NewEventPublisher(t), hasher,
+				tokenManager)
 
-			_, err = authService.SignIn(tc.buildUser())
+			_, err = authService.SignIn(context.Background(), tc.buildUser())
 			assert.Equal(t, tc.expectedErr, err)
 		})
 	}
@@ -312,58 +286,82 @@ func TestSignIn(t *testing.T) {
 func TestConfirmExpert(t *testing.T) {
 	hasher := newHasher("salt")
 	errConfirmExpert := errors.New("error while confirming expert")
+	expertID := uuid.New().String()
 
 	testCases := []struct {
-		name               string
-		buildAuthRepo      func() repository.AuthRepo
-		buildRatingRepo    func() repository.RatingRepo
-		buildConfirmExpert func() domain.ConfirmExpert
-		expectedErr        error
+		name                string
+		confirm             domain.ConfirmExpert
+		buildAuthRepo       func() repository.AuthRepo
+		buildRatingRepo     func() repository.RatingRepo
+		buildEventPublisher func() EventPublisher
+		expectedErr         error
 	}{
 		{
-			name: "Expert should be confirmed. Expect no error",
+			name:    "Expert should be confirmed. Expect no error",
+			confirm: domain.ConfirmExpert{ExpertID: expertID, IsConfirmed: true},
 			buildAuthRepo: func() repository.AuthRepo {
 				mockRepo := mocks.NewAuthRepo(t)
 
-				mockRepo.On("ConfirmExpert", mock.Anything).
+				mockRepo.On("ConfirmExpert", mock.Anything, mock.Anything).
 					Return(nil)
 
 				return mockRepo
 			},
 			buildRatingRepo: func() repository.RatingRepo {
 				mockRepo := mocks.NewRatingRepo(t)
-				mockRepo.On("InsertExpertId", mock.Anything).
+				mockRepo.On("InsertExpertId", mock.Anything, expertID).
 					Return(nil)
 
 				return mockRepo
 			},
-			buildConfirmExpert: func() domain.ConfirmExpert {
-				return domain.ConfirmExpert{
-					ExpertID:    uuid.New().String(),
-					IsConfirmed: true,
-				}
+			buildEventPublisher: func() EventPublisher {
+				mockPublisher := mocksservice.NewEventPublisher(t)
+				mockPublisher.On("Publish", mock.Anything, mock.Anything, domain.ExpertConfirmed{ExpertID: expertID}).
+					Return(nil)
+
+				return mockPublisher
 			},
 			expectedErr: nil,
 		},
 		{
-			name: "Problems while confirm expert. Expect error",
+			name:    "Confirmation is revoked. Expect no event",
+			confirm: domain.ConfirmExpert{ExpertID: expertID, IsConfirmed: false},
 			buildAuthRepo: func() repository.AuthRepo {
 				mockRepo := mocks.NewAuthRepo(t)
 
-				mockRepo.On("ConfirmExpert", mock.Anything).
-					Return(errConfirmExpert)
+				mockRepo.On("ConfirmExpert", mock.Anything, mock.Anything).
+					Return(nil)
 
 				return mockRepo
 			},
 			buildRatingRepo: func() repository.RatingRepo {
 				mockRepo := mocks.NewRatingRepo(t)
+				mockRepo.On("InsertExpertId", mock.Anything, expertID).
+					Return(nil)
+
 				return mockRepo
 			},
-			buildConfirmExpert: func() domain.ConfirmExpert {
-				return domain.ConfirmExpert{
-					ExpertID:    uuid.New().String(),
-					IsConfirmed: true,
-				}
+			buildEventPublisher: func() EventPublisher {
+				return mocksservice.NewEventPublisher(t)
+			},
+			expectedErr: nil,
+		},
+		{
+			name:    "Problems while confirm expert. Expect error",
+			confirm: domain.ConfirmExpert{ExpertID: expertID, IsConfirmed: true},
+			buildAuthRepo: func() repository.AuthRepo {
+				mockRepo := mocks.NewAuthRepo(t)
+
+				mockRepo.On("ConfirmExpert", mock.Anything, mock.Anything).
+					Return(errConfirmExpert)
+
+				return mockRepo
+			},
+			buildRatingRepo: func() repository.RatingRepo {
+				return mocks.NewRatingRepo(t)
+			},
+			buildEventPublisher: func() EventPublisher {
+				return mocksservice.NewEventPublisher(t)
 			},
 			expectedErr: errConfirmExpert,
 		},
@@ -371,15 +369,14 @@ func TestConfirmExpert(t *testing.T) {
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			authRepo := tc.buildAuthRepo()
-			ratingRepo := tc.buildRatingRepo()
+			uow := newUnitOfWork(t, repository.TxRepos{Auth: tc.buildAuthRepo(), Rating: tc.buildRatingRepo()})
 
 			tokenManager, err := tokens.NewTokenManager("sign")
 			assert.NoError(t, err)
 
-			authService := NewAuthService(authRepo, ratingRepo, hasher, tokenManager)
+			authService := NewAuthService(mocks.NewAuthRepo(t), uow, tc.buildEventPublisher(), hasher, tokenManager)
 
-			err = authService.ConfirmExpert(tc.buildConfirmExpert())
+			err = authService.ConfirmExpert(context.Background(), tc.confirm)
 			assert.Equal(t, tc.expectedErr, err)
 		})
 	}
@@ -2,14 +2,20 @@ package service
 
 import (
 	"TrafficPolice/internal/domain"
+	"TrafficPolice/internal/errs"
 	"TrafficPolice/internal/repository"
+	"context"
+	"fmt"
 	"github.com/google/uuid"
 )
 
 //go:generate go run github.com/vektra/mockery/v2@v2.42.1 --name CameraService
 type CameraService interface {
-	AddCameraType(cameraType domain.CameraType) (string, error)
-	GetCameraTypeByCameraID(cameraID string) (string, error)
+	AddCameraType(ctx context.Context, cameraType domain.CameraType) (string, error)
+	GetCameraTypeByCameraID(ctx context.Context, cameraID string) (string, error)
+	SetCameraTypeMapping(ctx context.Context, mapping domain.CameraTypeMapping) error
+	GetCameraTypeMapping(ctx context.Context, cameraTypeName string) (domain.CameraTypeMapping, error)
+	SetCameraRedactRegions(ctx context.Context, cameraID string, regions []domain.RedactRegion) error
 }
 
 type cameraService struct {
@@ -20,12 +26,46 @@ func NewCameraService(db repository.CameraRepo) CameraService {
 	return &cameraService{cameraRepo: db}
 }
 
-func (s *cameraService) AddCameraType(cameraType domain.CameraType) (string, error) {
+func (s *cameraService) AddCameraType(ctx context.Context, cameraType domain.CameraType) (string, error) {
 	id := uuid.New()
 	cameraType.ID = id.String()
-	return s.cameraRepo.AddCameraType(cameraType)
+	return s.cameraRepo.AddCameraType(ctx, cameraType)
 }
 
-func (s *cameraService) GetCameraTypeByCameraID(cameraID string) (string, error) {
-	return s.cameraRepo.GetCameraTypeByCameraID(cameraID)
+func (s *cameraService) GetCameraTypeByCameraID(ctx context.Context, cameraID string) (string, error) {
+	return s.cameraRepo.GetCameraTypeByCameraID(ctx, cameraID)
+}
+
+func (s *cameraService) SetCameraTypeMapping(ctx context.Context, mapping domain.CameraTypeMapping) error {
+	mapped := make(map[string]struct{}, len(mapping.Fields))
+	for _, f := range mapping.Fields {
+		if _, ok := mapped[f.CaseField]; ok {
+			return fmt.Errorf("%w: field %s mapped twice", errs.ErrInvalidMapping, f.CaseField)
+		}
+		mapped[f.CaseField] = struct{}{}
+	}
+
+	for _, field := range domain.RequiredCaseFields {
+		if _, ok := mapped[field]; !ok {
+			return fmt.Errorf("%w: field %s is not mapped", errs.ErrInvalidMapping, field)
+		}
+	}
+
+	return s.cameraRepo.SetCameraTypeMapping(ctx, mapping)
+}
+
+func (s *cameraService) GetCameraTypeMapping(
+	ctx context.Context,
+	cameraTypeName string,
+) (domain.CameraTypeMapping, error) {
+	return s.cameraRepo.GetCameraTypeMapping(ctx, cameraTypeName)
+}
+
+// SetCameraRedactRegions replaces redact regions of camera. Regions are applied to images uploaded after change
+func (s *cameraService) SetCameraRedactRegions(
+	ctx context.Context,
+	cameraID string,
+	regions []domain.RedactRegion,
+) error {
+	return s.cameraRepo.SetCameraRedactRegions(ctx, cameraID, regions)
 }
@@ -2,38 +2,327 @@ package service
 
 import (
 	"TrafficPolice/internal/domain"
+	"TrafficPolice/internal/errs"
 	"TrafficPolice/internal/repository"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
 	"github.com/google/uuid"
+	"image"
+	_ "image/jpeg"
+	_ "image/png"
+	"log"
+	"net/http"
 )
 
+const (
+	// MaxCaseImgSize is max size of case image in bytes
+	MaxCaseImgSize = 10 << 20
+	// MaxCaseImgSide is max width and height of case image in pixels
+	MaxCaseImgSide = 8192
+)
+
+//go:generate go run github.com/vektra/mockery/v2@v2.42.1 --name CaseService
 type CaseService interface {
-	AddCase(c domain.Case) (string, error)
+	AddCase(ctx context.Context, cameraUserID string, c domain.Case) (string, error)
+	AddCaseWithImage(ctx context.Context, cameraUserID string, c domain.Case, img []byte) (string, error)
+	UploadCaseImg(ctx context.Context, cameraUserID string, caseID string, img []byte) error
+	AllowCaseImgOverwrite(ctx context.Context, caseID string) error
+	GetCaseImg(ctx context.Context, caseID string, variant domain.ImageVariant) (domain.Image, error)
 }
 
 type caseService struct {
 	caseRepo      repository.CaseRepo
 	transportRepo repository.TransportRepo
+	cameraRepo    repository.CameraRepo
+	imgService    ImgService
+	uow           repository.UnitOfWork
+	events        EventPublisher
 }
 
 func NewCaseService(
 	caseRepo repository.CaseRepo,
 	transportRepo repository.TransportRepo,
+	cameraRepo repository.CameraRepo,
+	imgService ImgService,
+	uow repository.UnitOfWork,
+	events EventPublisher,
 ) CaseService {
 	return &caseService{
 		caseRepo:      caseRepo,
 		transportRepo: transportRepo,
+		cameraRepo:    cameraRepo,
+		imgService:    imgService,
+		uow:           uow,
+		events:        events,
 	}
 }
 
-func (s *caseService) AddCase(c domain.Case) (string, error) {
+// AddCase inserts case of camera of passed user
+func (s *caseService) AddCase(ctx context.Context, cameraUserID string, c domain.Case) (string, error) {
+	err := s.checkCameraOwner(ctx, c.Camera.ID, cameraUserID)
+	if err != nil {
+		return "", err
+	}
+
 	id := uuid.New()
 	c.ID = id.String()
-	transportID, err := s.transportRepo.GetTransportID(c.Transport.Chars, c.Transport.Num, c.Transport.Region)
+	transportID, err := s.transportRepo.GetTransportID(ctx, c.Transport.Chars, c.Transport.Num, c.Transport.Region)
+
+	if err != nil {
+		return "", err
+	}
+	c.Transport.ID = transportID
+
+	return s.insertCase(ctx, c)
+}
+
+// AddCaseWithImage saves image and inserts case of camera of passed user marked with image.
+// If case is not inserted, then saved image is deleted
+func (s *caseService) AddCaseWithImage(
+	ctx context.Context,
+	cameraUserID string,
+	c domain.Case,
+	img []byte,
+) (string, error) {
+	err := checkCaseImg(img)
+	if err != nil {
+		return "", err
+	}
+	err = s.checkCameraOwner(ctx, c.Camera.ID, cameraUserID)
+	if err != nil {
+		return "", err
+	}
 
+	c.ID = uuid.New().String()
+	transportID, err := s.transportRepo.GetTransportID(ctx, c.Transport.Chars, c.Transport.Num, c.Transport.Region)
 	if err != nil {
 		return "", err
 	}
 	c.Transport.ID = transportID
+	c.HasImage = true
+
+	err = s.saveCaseImg(ctx, c.ID, c.Camera.ID, img)
+	if err != nil {
+		s.deleteImg(ctx, caseImgOwner(c.ID))
+		return "", err
+	}
+
+	caseID, err := s.insertCase(ctx, c)
+	if err != nil {
+		s.deleteImg(ctx, caseImgOwner(c.ID))
+		return "", err
+	}
+
+	return caseID, nil
+}
+
+// checkCameraOwner checks, that camera from payload belongs to user, who sends case
+func (s *caseService) checkCameraOwner(ctx context.Context, cameraID string, cameraUserID string) error {
+	userID, err := s.cameraRepo.GetCameraUserID(ctx, cameraID)
+	if err != nil {
+		return err
+	}
+	if userID != cameraUserID {
+		return errs.ErrNotCaseOwner
+	}
+
+	return nil
+}
+
+// insertCase inserts case and event about created case in one transaction
+func (s *caseService) insertCase(ctx context.Context, c domain.Case) (string, error) {
+	var caseID string
+	err := s.uow.Do(ctx, func(repos repository.TxRepos) error {
+		var err error
+		caseID, err = repos.Case.InsertCase(ctx, c)
+		if err != nil {
+			return err
+		}
+
+		return s.events.Publish(ctx, repos.Outbox, domain.CaseCreated{
+			CaseID:         caseID,
+			CameraID:       c.Camera.ID,
+			ViolationID:    c.Violation.ID,
+			ViolationValue: c.ViolationValue,
+			RequiredSkill:  int(c.RequiredSkill),
+			Date:           c.Date,
+			HasImage:       c.HasImage,
+		})
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return caseID, nil
+}
+
+// UploadCaseImg saves image of case created by camera of passed user.
+// Image of case can be uploaded again only if director allowed overwrite
+func (s *caseService) UploadCaseImg(ctx context.Context, cameraUserID string, caseID string, img []byte) error {
+	err := checkCaseImg(img)
+	if err != nil {
+		return err
+	}
+
+	// case is checked before processing, so image is not processed for case, which can not get it
+	info, err := s.caseRepo.GetCaseImageInfo(ctx, caseID)
+	if err != nil {
+		return err
+	}
+	err = checkCaseImgUpload(info, cameraUserID)
+	if err != nil {
+		return err
+	}
+
+	imgs, err := s.caseImgs(ctx, info.CameraID, img)
+	if err != nil {
+		return err
+	}
+
+	// case is locked and checked again in transaction with metadata of images,
+	// so concurrent uploads can not both pass the check
+	return s.imgService.SaveImgs(ctx, caseImgOwner(caseID), imgs, func(repos repository.TxRepos) error {
+		info, err := repos.Case.LockCaseImageInfo(ctx, caseID)
+		if err != nil {
+			return err
+		}
+		err = checkCaseImgUpload(info, cameraUserID)
+		if err != nil {
+			return err
+		}
+
+		return repos.Case.SetCaseHasImage(ctx, caseID)
+	})
+}
+
+// checkCaseImgUpload checks, that case is created by camera of user and image of case can be uploaded
+func checkCaseImgUpload(info domain.CaseImageInfo, cameraUserID string) error {
+	if info.CameraUserID != cameraUserID {
+		return errs.ErrNotCaseOwner
+	}
+	if info.HasImage && !info.OverwriteAllowed {
+		return errs.ErrImageAlreadyUploaded
+	}
+
+	return nil
+}
+
+// AllowCaseImgOverwrite allows camera to upload image of case once more
+func (s *caseService) AllowCaseImgOverwrite(ctx context.Context, caseID string) error {
+	return s.caseRepo.SetCaseImageOverwriteAllowed(ctx, caseID, true)
+}
+
+// GetCaseImg returns variant of case image. Derived variants are generated from original,
+// if they are missing, for example for images uploaded before redaction was introduced
+func (s *caseService) GetCaseImg(
+	ctx context.Context,
+	caseID string,
+	variant domain.ImageVariant,
+) (domain.Image, error) {
+	img, err := s.imgService.GetImg(ctx, caseImgOwner(caseID), variant)
+	if !errors.Is(err, errs.ErrNoImage) || variant == domain.OriginalImage {
+		return img, err
+	}
+
+	original, err := s.imgService.GetImg(ctx, caseImgOwner(caseID), domain.OriginalImage)
+	if err != nil {
+		return domain.Image{}, err
+	}
+	info, err := s.caseRepo.GetCaseImageInfo(ctx, caseID)
+	if err != nil {
+		return domain.Image{}, err
+	}
+	err = s.saveDerivedCaseImgs(ctx, caseID, info.CameraID, original.Data)
+	if err != nil {
+		return domain.Image{}, err
+	}
+
+	return s.imgService.GetImg(ctx, caseImgOwner(caseID), variant)
+}
+
+// saveCaseImg saves original image of case and variants derived from it
+func (s *caseService) saveCaseImg(ctx context.Context, caseID string, cameraID string, img []byte) error {
+	imgs, err := s.caseImgs(ctx, cameraID, img)
+	if err != nil {
+		return err
+	}
+
+	return s.imgService.SaveImgs(ctx, caseImgOwner(caseID), imgs, nil)
+}
+
+// saveDerivedCaseImgs generates variants of original image and saves them
+func (s *caseService) saveDerivedCaseImgs(ctx context.Context, caseID string, cameraID string, original []byte) error {
+	variants, err := s.derivedCaseImgs(ctx, cameraID, original)
+	if err != nil {
+		return err
+	}
+
+	return s.imgService.SaveImgs(ctx, caseImgOwner(caseID), variants, nil)
+}
+
+// caseImgs returns original image of case with variants derived from it
+func (s *caseService) caseImgs(
+	ctx context.Context,
+	cameraID string,
+	original []byte,
+) (map[domain.ImageVariant][]byte, error) {
+	imgs, err := s.derivedCaseImgs(ctx, cameraID, original)
+	if err != nil {
+		return nil, err
+	}
+	imgs[domain.OriginalImage] = original
+
+	return imgs, nil
+}
+
+// derivedCaseImgs generates variants of image with redact regions of camera
+func (s *caseService) derivedCaseImgs(
+	ctx context.Context,
+	cameraID string,
+	original []byte,
+) (map[domain.ImageVariant][]byte, error) {
+	regions, err := s.cameraRepo.GetCameraRedactRegions(ctx, cameraID)
+	if err != nil {
+		return nil, err
+	}
+
+	return deriveCaseImgs(original, regions)
+}
+
+// deleteImg removes images of case, which is not saved. Cleanup is not canceled with request,
+// otherwise images without case are left
+func (s *caseService) deleteImg(ctx context.Context, owner domain.ImageOwner) {
+	err := s.imgService.DeleteImg(context.WithoutCancel(ctx), owner)
+	if err != nil {
+		log.Printf("Error while deleting image of case %s: %v\n", owner.ID, err)
+	}
+}
+
+func caseImgOwner(caseID string) domain.ImageOwner {
+	return domain.ImageOwner{Type: domain.CaseImageOwner, ID: caseID}
+}
+
+// checkCaseImg checks sniffed content type, size and dimensions of image
+func checkCaseImg(img []byte) error {
+	if len(img) > MaxCaseImgSize {
+		return fmt.Errorf("%w: size is more than %d bytes", errs.ErrImageTooLarge, MaxCaseImgSize)
+	}
+
+	contentType := http.DetectContentType(img)
+	if _, ok := domain.ImageExtensions[contentType]; !ok {
+		return fmt.Errorf("%w: content type %s is not allowed", errs.ErrInvalidImage, contentType)
+	}
+
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(img))
+	if err != nil {
+		return fmt.Errorf("%w: %v", errs.ErrInvalidImage, err)
+	}
+	if cfg.Width > MaxCaseImgSide || cfg.Height > MaxCaseImgSide {
+		return fmt.Errorf("%w: %dx%d is more than %dx%d pixels",
+			errs.ErrImageTooLarge, cfg.Width, cfg.Height, MaxCaseImgSide, MaxCaseImgSide)
+	}
 
-	return s.caseRepo.InsertCase(c)
+	return nil
 }
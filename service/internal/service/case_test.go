@@ -5,20 +5,29 @@ import (
 	"TrafficPolice/internal/errs"
 	"TrafficPolice/internal/repository"
 	"TrafficPolice/internal/repository/mocks"
+	mocksservice "TrafficPolice/internal/service/mocks"
+	"bytes"
+	"context"
+	"errors"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"image"
+	"image/png"
 	"testing"
 )
 
 func TestAddCase(t *testing.T) {
 	caseID := uuid.New().String()
 	transportID := uuid.New().String()
+	cameraID := uuid.New().String()
+	cameraUserID := uuid.New().String()
 
 	testCases := []struct {
 		name               string
 		buildCaseRepo      func() repository.CaseRepo
 		buildTransportRepo func() repository.TransportRepo
+		cameraOwnerID      string
 		inputCase          domain.Case
 		expectedErr        error
 		expectedCaseID     string
@@ -28,7 +37,7 @@ func TestAddCase(t *testing.T) {
 			buildCaseRepo: func() repository.CaseRepo {
 				mockRepo := mocks.NewCaseRepo(t)
 
-				mockRepo.On("InsertCase", mock.Anything).
+				mockRepo.On("InsertCase", mock.Anything, mock.Anything).
 					Return(caseID, nil)
 
 				return mockRepo
@@ -36,11 +45,12 @@ func TestAddCase(t *testing.T) {
 			buildTransportRepo: func() repository.TransportRepo {
 				mockRepo := mocks.NewTransportRepo(t)
 
-				mockRepo.On("GetTransportID", mock.Anything, mock.Anything, mock.Anything).
+				mockRepo.On("GetTransportID", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
 					Return(transportID, nil)
 				return mockRepo
 			},
-			inputCase:      domain.Case{ID: caseID},
+			cameraOwnerID:  cameraUserID,
+			inputCase:      domain.Case{ID: caseID, Camera: domain.Camera{ID: cameraID}},
 			expectedErr:    nil,
 			expectedCaseID: caseID,
 		},
@@ -53,27 +63,641 @@ func TestAddCase(t *testing.T) {
 			buildTransportRepo: func() repository.TransportRepo {
 				mockRepo := mocks.NewTransportRepo(t)
 
-				mockRepo.On("GetTransportID", mock.Anything, mock.Anything, mock.Anything).
+				mockRepo.On("GetTransportID", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
 					Return("", errs.ErrNoTransport)
 				return mockRepo
 			},
-			inputCase:      domain.Case{ID: caseID},
+			cameraOwnerID:  cameraUserID,
+			inputCase:      domain.Case{ID: caseID, Camera: domain.Camera{ID: cameraID}},
 			expectedErr:    errs.ErrNoTransport,
 			expectedCaseID: "",
 		},
+		{
+			name: "Camera belongs to another user. Expect ErrNotCaseOwner",
+			buildCaseRepo: func() repository.CaseRepo {
+				return mocks.NewCaseRepo(t)
+			},
+			buildTransportRepo: func() repository.TransportRepo {
+				return mocks.NewTransportRepo(t)
+			},
+			cameraOwnerID:  uuid.New().String(),
+			inputCase:      domain.Case{ID: caseID, Camera: domain.Camera{ID: cameraID}},
+			expectedErr:    errs.ErrNotCaseOwner,
+			expectedCaseID: "",
+		},
 	}
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
 			caseRepo := tc.buildCaseRepo()
 			transportRepo := tc.buildTransportRepo()
+			uow := newUnitOfWork(t, repository.TxRepos{Case: caseRepo})
 
-			caseService := NewCaseService(caseRepo, transportRepo)
+			caseService := NewCaseService(caseRepo, transportRepo, cameraOfUser(t, cameraID, tc.cameraOwnerID),
+				mocksservice.NewImgService(t), uow, newCaseCreatedPublisher(t, tc.expectedCaseID))
 
-			actualID, err := caseService.AddCase(tc.inputCase)
+			actualID, err := caseService.AddCase(context.Background(), cameraUserID, tc.inputCase)
 			assert.Equal(t, tc.expectedErr, err)
 			assert.Equal(t, tc.expectedCaseID, actualID)
 
 		})
 	}
 }
+
+// newCaseCreatedPublisher expects event about created case, when case is inserted
+func newCaseCreatedPublisher(t *testing.T, caseID string) EventPublisher {
+	mockPublisher := mocksservice.NewEventPublisher(t)
+	if caseID != "" {
+		mockPublisher.On("Publish", mock.Anything, mock.Anything, mock.MatchedBy(func(e domain.CaseCreated) bool {
+			return e.CaseID == caseID
+		})).Return(nil)
+	}
+	return mockPublisher
+}
+
+func testImg(t *testing.T, width, height int) []byte {
+	var buf bytes.Buffer
+	err := png.Encode(&buf, image.NewGray(image.Rect(0, 0, width, height)))
+	assert.NoError(t, err)
+	return buf.Bytes()
+}
+
+// expectSaveCaseImg expects saving of original image and 3 derived variants. Transaction function
+// of caller is run with passed repos
+func expectSaveCaseImg(mockService *mocksservice.ImgService, owner any, img []byte, repos repository.TxRepos) {
+	mockService.On("SaveImgs", mock.Anything, owner, isCaseImgs(img), mock.Anything).
+		Return(func(
+			_ context.Context,
+			_ domain.ImageOwner,
+			_ map[domain.ImageVariant][]byte,
+			inTx func(repos repository.TxRepos) error,
+		) error {
+			if inTx == nil {
+				return nil
+			}
+			return inTx(repos)
+		}).
+		Once()
+}
+
+// isCaseImgs matches original image with 3 derived variants
+func isCaseImgs(img []byte) any {
+	return mock.MatchedBy(func(imgs map[domain.ImageVariant][]byte) bool {
+		return len(imgs) == 4 && bytes.Equal(imgs[domain.OriginalImage], img)
+	})
+}
+
+func TestAddCaseWithImage(t *testing.T) {
+	caseID := uuid.New().String()
+	cameraID := uuid.New().String()
+	cameraUserID := uuid.New().String()
+	transportID := uuid.New().String()
+	img := testImg(t, 4, 3)
+	errDiskFull := errors.New("disk is full")
+	isCaseOwner := mock.MatchedBy(func(owner domain.ImageOwner) bool {
+		return owner.Type == domain.CaseImageOwner
+	})
+
+	testCases := []struct {
+		name               string
+		buildCaseRepo      func() repository.CaseRepo
+		buildTransportRepo func() repository.TransportRepo
+		buildCameraRepo    func() repository.CameraRepo
+		buildImgService    func() ImgService
+		expectedErr        error
+		expectedCaseID     string
+	}{
+		{
+			name: "Successful add case with image",
+			buildCaseRepo: func() repository.CaseRepo {
+				mockRepo := mocks.NewCaseRepo(t)
+
+				mockRepo.On("InsertCase", mock.Anything, mock.MatchedBy(func(c domain.Case) bool {
+					return c.HasImage && c.Transport.ID == transportID
+				})).Return(caseID, nil)
+
+				return mockRepo
+			},
+			buildTransportRepo: func() repository.TransportRepo {
+				mockRepo := mocks.NewTransportRepo(t)
+
+				mockRepo.On("GetTransportID", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+					Return(transportID, nil)
+				return mockRepo
+			},
+			buildCameraRepo: func() repository.CameraRepo {
+				mockRepo := cameraOfUser(t, cameraID, cameraUserID)
+
+				mockRepo.On("GetCameraRedactRegions", mock.Anything, cameraID).
+					Return([]domain.RedactRegion{{X: 1, Y: 1, Width: 2, Height: 2}}, nil)
+				return mockRepo
+			},
+			buildImgService: func() ImgService {
+				mockService := mocksservice.NewImgService(t)
+
+				expectSaveCaseImg(mockService, isCaseOwner, img, repository.TxRepos{})
+				return mockService
+			},
+			expectedErr:    nil,
+			expectedCaseID: caseID,
+		},
+		{
+			name: "Transport not exists. Image is not saved",
+			buildCaseRepo: func() repository.CaseRepo {
+				mockRepo := mocks.NewCaseRepo(t)
+				return mockRepo
+			},
+			buildTransportRepo: func() repository.TransportRepo {
+				mockRepo := mocks.NewTransportRepo(t)
+
+				mockRepo.On("GetTransportID", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+					Return("", errs.ErrNoTransport)
+				return mockRepo
+			},
+			buildCameraRepo: func() repository.CameraRepo {
+				return cameraOfUser(t, cameraID, cameraUserID)
+			},
+			buildImgService: func() ImgService {
+				mockService := mocksservice.NewImgService(t)
+				return mockService
+			},
+			expectedErr:    errs.ErrNoTransport,
+			expectedCaseID: "",
+		},
+		{
+			name: "Image is not saved. Case is not inserted",
+			buildCaseRepo: func() repository.CaseRepo {
+				mockRepo := mocks.NewCaseRepo(t)
+				return mockRepo
+			},
+			buildTransportRepo: func() repository.TransportRepo {
+				mockRepo := mocks.NewTransportRepo(t)
+
+				mockRepo.On("GetTransportID", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+					Return(transportID, nil)
+				return mockRepo
+			},
+			buildCameraRepo: func() repository.CameraRepo {
+				mockRepo := cameraOfUser(t, cameraID, cameraUserID)
+
+				mockRepo.On("GetCameraRedactRegions", mock.Anything, cameraID).
+					Return([]domain.RedactRegion{}, nil)
+				return mockRepo
+			},
+			buildImgService: func() ImgService {
+				mockService := mocksservice.NewImgService(t)
+
+				mockService.On("SaveImgs", mock.Anything, isCaseOwner, isCaseImgs(img), mock.Anything).
+					Return(errDiskFull)
+				mockService.On("DeleteImg", mock.Anything, mock.Anything).
+					Return(nil)
+				return mockService
+			},
+			expectedErr:    errDiskFull,
+			expectedCaseID: "",
+		},
+		{
+			name: "Case is not inserted. Saved image is deleted",
+			buildCaseRepo: func() repository.CaseRepo {
+				mockRepo := mocks.NewCaseRepo(t)
+
+				mockRepo.On("InsertCase", mock.Anything, mock.Anything).
+					Return("", errs.ErrInvalidRelevantParams)
+				return mockRepo
+			},
+			buildTransportRepo: func() repository.TransportRepo {
+				mockRepo := mocks.NewTransportRepo(t)
+
+				mockRepo.On("GetTransportID", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+					Return(transportID, nil)
+				return mockRepo
+			},
+			buildCameraRepo: func() repository.CameraRepo {
+				mockRepo := cameraOfUser(t, cameraID, cameraUserID)
+
+				mockRepo.On("GetCameraRedactRegions", mock.Anything, cameraID).
+					Return([]domain.RedactRegion{}, nil)
+				return mockRepo
+			},
+			buildImgService: func() ImgService {
+				mockService := mocksservice.NewImgService(t)
+
+				expectSaveCaseImg(mockService, isCaseOwner, img, repository.TxRepos{})
+				mockService.On("DeleteImg", mock.Anything, mock.Anything).
+					Return(nil)
+				return mockService
+			},
+			expectedErr:    errs.ErrInvalidRelevantParams,
+			expectedCaseID: "",
+		},
+		{
+			name: "Camera belongs to another user. Image is not saved",
+			buildCaseRepo: func() repository.CaseRepo {
+				return mocks.NewCaseRepo(t)
+			},
+			buildTransportRepo: func() repository.TransportRepo {
+				return mocks.NewTransportRepo(t)
+			},
+			buildCameraRepo: func() repository.CameraRepo {
+				return cameraOfUser(t, cameraID, uuid.New().String())
+			},
+			buildImgService: func() ImgService {
+				return mocksservice.NewImgService(t)
+			},
+			expectedErr:    errs.ErrNotCaseOwner,
+			expectedCaseID: "",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			caseRepo := tc.buildCaseRepo()
+			caseService := NewCaseService(
+				caseRepo, tc.buildTransportRepo(), tc.buildCameraRepo(), tc.buildImgService(),
+				newUnitOfWork(t, repository.TxRepos{Case: caseRepo}), newCaseCreatedPublisher(t, tc.expectedCaseID),
+			)
+
+			actualID, err := caseService.AddCaseWithImage(context.Background(), cameraUserID,
+				domain.Case{Camera: domain.Camera{ID: cameraID}}, img)
+			assert.ErrorIs(t, err, tc.expectedErr)
+			assert.Equal(t, tc.expectedCaseID, actualID)
+		})
+	}
+}
+
+// cameraOfUser returns camera repo, in which camera belongs to user
+func cameraOfUser(t *testing.T, cameraID string, userID string) *mocks.CameraRepo {
+	mockRepo := mocks.NewCameraRepo(t)
+	mockRepo.On("GetCameraUserID", mock.Anything, cameraID).
+		Return(userID, nil)
+	return mockRepo
+}
+
+func TestUploadCaseImg(t *testing.T) {
+	caseID := uuid.New().String()
+	cameraID := uuid.New().String()
+	cameraUserID := uuid.New().String()
+	img := testImg(t, 4, 3)
+	owner := domain.ImageOwner{Type: domain.CaseImageOwner, ID: caseID}
+	errDiskFull := errors.New("disk is full")
+	firstUpload := domain.CaseImageInfo{CameraID: cameraID, CameraUserID: cameraUserID}
+	overwrite := domain.CaseImageInfo{CameraID: cameraID, CameraUserID: cameraUserID, HasImage: true, OverwriteAllowed: true}
+
+	testCases := []struct {
+		name            string
+		img             []byte
+		buildCaseRepo   func() repository.CaseRepo
+		buildCameraRepo func() repository.CameraRepo
+		buildImgService func(repos repository.TxRepos) ImgService
+		expectedErr     error
+	}{
+		{
+			name: "Successful upload of first image",
+			img:  img,
+			buildCaseRepo: func() repository.CaseRepo {
+				mockRepo := mocks.NewCaseRepo(t)
+
+				mockRepo.On("GetCaseImageInfo", mock.Anything, caseID).
+					Return(firstUpload, nil)
+				mockRepo.On("LockCaseImageInfo", mock.Anything, caseID).
+					Return(firstUpload, nil)
+				mockRepo.On("SetCaseHasImage", mock.Anything, caseID).
+					Return(nil)
+				return mockRepo
+			},
+			buildCameraRepo: func() repository.CameraRepo {
+				mockRepo := mocks.NewCameraRepo(t)
+
+				mockRepo.On("GetCameraRedactRegions", mock.Anything, cameraID).
+					Return([]domain.RedactRegion{}, nil)
+				return mockRepo
+			},
+			buildImgService: func(repos repository.TxRepos) ImgService {
+				mockService := mocksservice.NewImgService(t)
+
+				expectSaveCaseImg(mockService, owner, img, repos)
+				return mockService
+			},
+			expectedErr: nil,
+		},
+		{
+			name: "Successful overwrite of image",
+			img:  img,
+			buildCaseRepo: func() repository.CaseRepo {
+				mockRepo := mocks.NewCaseRepo(t)
+
+				mockRepo.On("GetCaseImageInfo", mock.Anything, caseID).
+					Return(overwrite, nil)
+				mockRepo.On("LockCaseImageInfo", mock.Anything, caseID).
+					Return(overwrite, nil)
+				mockRepo.On("SetCaseHasImage", mock.Anything, caseID).
+					Return(nil)
+				return mockRepo
+			},
+			buildCameraRepo: func() repository.CameraRepo {
+				mockRepo := mocks.NewCameraRepo(t)
+
+				mockRepo.On("GetCameraRedactRegions", mock.Anything, cameraID).
+					Return([]domain.RedactRegion{}, nil)
+				return mockRepo
+			},
+			buildImgService: func(repos repository.TxRepos) ImgService {
+				mockService := mocksservice.NewImgService(t)
+
+				expectSaveCaseImg(mockService, owner, img, repos)
+				return mockService
+			},
+			expectedErr: nil,
+		},
+		{
+			name: "Image is not saved. Case is not marked with image",
+			img:  img,
+			buildCaseRepo: func() repository.CaseRepo {
+				mockRepo := mocks.NewCaseRepo(t)
+
+				mockRepo.On("GetCaseImageInfo", mock.Anything, caseID).
+					Return(firstUpload, nil)
+				return mockRepo
+			},
+			buildCameraRepo: func() repository.CameraRepo {
+				mockRepo := mocks.NewCameraRepo(t)
+
+				mockRepo.On("GetCameraRedactRegions", mock.Anything, cameraID).
+					Return([]domain.RedactRegion{}, nil)
+				return mockRepo
+			},
+			buildImgService: func(repos repository.TxRepos) ImgService {
+				mockService := mocksservice.NewImgService(t)
+
+				mockService.On("SaveImgs", mock.Anything, owner, isCaseImgs(img), mock.Anything).
+					Return(errDiskFull)
+				return mockService
+			},
+			expectedErr: errDiskFull,
+		},
+		{
+			name: "Image is uploaded by concurrent request. Expect ErrImageAlreadyUploaded",
+			img:  img,
+			buildCaseRepo: func() repository.CaseRepo {
+				mockRepo := mocks.NewCaseRepo(t)
+
+				mockRepo.On("GetCaseImageInfo", mock.Anything, caseID).
+					Return(firstUpload, nil)
+				mockRepo.On("LockCaseImageInfo", mock.Anything, caseID).
+					Return(domain.CaseImageInfo{CameraID: cameraID, CameraUserID: cameraUserID, HasImage: true}, nil)
+				return mockRepo
+			},
+			buildCameraRepo: func() repository.CameraRepo {
+				mockRepo := mocks.NewCameraRepo(t)
+
+				mockRepo.On("GetCameraRedactRegions", mock.Anything, cameraID).
+					Return([]domain.RedactRegion{}, nil)
+				return mockRepo
+			},
+			buildImgService: func(repos repository.TxRepos) ImgService {
+				mockService := mocksservice.NewImgService(t)
+
+				expectSaveCaseImg(mockService, owner, img, repos)
+				return mockService
+			},
+			expectedErr: errs.ErrImageAlreadyUploaded,
+		},
+		{
+			name: "Case is created by another camera. Expect ErrNotCaseOwner",
+			img:  img,
+			buildCaseRepo: func() repository.CaseRepo {
+				mockRepo := mocks.NewCaseRepo(t)
+
+				mockRepo.On("GetCaseImageInfo", mock.Anything, caseID).
+					Return(domain.CaseImageInfo{CameraUserID: uuid.New().String()}, nil)
+				return mockRepo
+			},
+			buildCameraRepo: func() repository.CameraRepo {
+				return mocks.NewCameraRepo(t)
+			},
+			buildImgService: func(repos repository.TxRepos) ImgService {
+				return mocksservice.NewImgService(t)
+			},
+			expectedErr: errs.ErrNotCaseOwner,
+		},
+		{
+			name: "Image is uploaded and overwrite is not allowed. Expect ErrImageAlreadyUploaded",
+			img:  img,
+			buildCaseRepo: func() repository.CaseRepo {
+				mockRepo := mocks.NewCaseRepo(t)
+
+				mockRepo.On("GetCaseImageInfo", mock.Anything, caseID).
+					Return(domain.CaseImageInfo{CameraUserID: cameraUserID, HasImage: true}, nil)
+				return mockRepo
+			},
+			buildCameraRepo: func() repository.CameraRepo {
+				return mocks.NewCameraRepo(t)
+			},
+			buildImgService: func(repos repository.TxRepos) ImgService {
+				return mocksservice.NewImgService(t)
+			},
+			expectedErr: errs.ErrImageAlreadyUploaded,
+		},
+		{
+			name: "Case not exists. Expect ErrNoCase",
+			img:  img,
+			buildCaseRepo: func() repository.CaseRepo {
+				mockRepo := mocks.NewCaseRepo(t)
+
+				mockRepo.On("GetCaseImageInfo", mock.Anything, caseID).
+					Return(domain.CaseImageInfo{}, errs.ErrNoCase)
+				return mockRepo
+			},
+			buildCameraRepo: func() repository.CameraRepo {
+				return mocks.NewCameraRepo(t)
+			},
+			buildImgService: func(repos repository.TxRepos) ImgService {
+				return mocksservice.NewImgService(t)
+			},
+			expectedErr: errs.ErrNoCase,
+		},
+		{
+			name: "Content is not image. Expect ErrInvalidImage",
+			img:  []byte("<html>not image</html>"),
+			buildCaseRepo: func() repository.CaseRepo {
+				return mocks.NewCaseRepo(t)
+			},
+			buildCameraRepo: func() repository.CameraRepo {
+				return mocks.NewCameraRepo(t)
+			},
+			buildImgService: func(repos repository.TxRepos) ImgService {
+				return mocksservice.NewImgService(t)
+			},
+			expectedErr: errs.ErrInvalidImage,
+		},
+		{
+			name: "Truncated png. Expect ErrInvalidImage",
+			img:  img[:20],
+			buildCaseRepo: func() repository.CaseRepo {
+				return mocks.NewCaseRepo(t)
+			},
+			buildCameraRepo: func() repository.CameraRepo {
+				return mocks.NewCameraRepo(t)
+			},
+			buildImgService: func(repos repository.TxRepos) ImgService {
+				return mocksservice.NewImgService(t)
+			},
+			expectedErr: errs.ErrInvalidImage,
+		},
+		{
+			name: "Image is too wide. Expect ErrImageTooLarge",
+			img:  testImg(t, MaxCaseImgSide+1, 1),
+			buildCaseRepo: func() repository.CaseRepo {
+				return mocks.NewCaseRepo(t)
+			},
+			buildCameraRepo: func() repository.CameraRepo {
+				return mocks.NewCameraRepo(t)
+			},
+			buildImgService: func(repos repository.TxRepos) ImgService {
+				return mocksservice.NewImgService(t)
+			},
+			expectedErr: errs.ErrImageTooLarge,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			caseRepo := tc.buildCaseRepo()
+			caseService := NewCaseService(
+				caseRepo, mocks.NewTransportRepo(t), tc.buildCameraRepo(),
+				tc.buildImgService(repository.TxRepos{Case: caseRepo}),
+				mocks.NewUnitOfWork(t), mocksservice.NewEventPublisher(t),
+			)
+
+			err := caseService.UploadCaseImg(context.Background(), cameraUserID, caseID, tc.img)
+			assert.ErrorIs(t, err, tc.expectedErr)
+		})
+	}
+}
+
+func TestGetCaseImg(t *testing.T) {
+	caseID := uuid.New().String()
+	cameraID := uuid.New().String()
+	img := testImg(t, 4, 3)
+	owner := domain.ImageOwner{Type: domain.CaseImageOwner, ID: caseID}
+	redacted := domain.Image{Data: []byte("redacted"), ContentType: "image/jpeg"}
+
+	testCases := []struct {
+		name            string
+		variant         domain.ImageVariant
+		buildCaseRepo   func() repository.CaseRepo
+		buildCameraRepo func() repository.CameraRepo
+		buildImgService func() ImgService
+		expectedImg     domain.Image
+		expectedErr     error
+	}{
+		{
+			name:    "Derived variant exists",
+			variant: domain.RedactedImage,
+			buildCaseRepo: func() repository.CaseRepo {
+				return mocks.NewCaseRepo(t)
+			},
+			buildCameraRepo: func() repository.CameraRepo {
+				return mocks.NewCameraRepo(t)
+			},
+			buildImgService: func() ImgService {
+				mockService := mocksservice.NewImgService(t)
+
+				mockService.On("GetImg", mock.Anything, owner, domain.RedactedImage).
+					Return(redacted, nil)
+				return mockService
+			},
+			expectedImg: redacted,
+			expectedErr: nil,
+		},
+		{
+			name:    "Derived variant is generated from original",
+			variant: domain.RedactedImage,
+			buildCaseRepo: func() repository.CaseRepo {
+				mockRepo := mocks.NewCaseRepo(t)
+
+				mockRepo.On("GetCaseImageInfo", mock.Anything, caseID).
+					Return(domain.CaseImageInfo{CameraID: cameraID}, nil)
+				return mockRepo
+			},
+			buildCameraRepo: func() repository.CameraRepo {
+				mockRepo := mocks.NewCameraRepo(t)
+
+				mockRepo.On("GetCameraRedactRegions", mock.Anything, cameraID).
+					Return([]domain.RedactRegion{}, nil)
+				return mockRepo
+			},
+			buildImgService: func() ImgService {
+				mockService := mocksservice.NewImgService(t)
+
+				mockService.On("GetImg", mock.Anything, owner, domain.RedactedImage).
+					Return(domain.Image{}, errs.ErrNoImage).Once()
+				mockService.On("GetImg", mock.Anything, owner, domain.OriginalImage).
+					Return(domain.Image{Data: img, ContentType: "image/png"}, nil)
+				mockService.On("SaveImgs", mock.Anything, owner, mock.MatchedBy(func(imgs map[domain.ImageVariant][]byte) bool {
+					_, ok := imgs[domain.OriginalImage]
+					return len(imgs) == 3 && !ok
+				}), mock.Anything).
+					Return(nil).Once()
+				mockService.On("GetImg", mock.Anything, owner, domain.RedactedImage).
+					Return(redacted, nil).Once()
+				return mockService
+			},
+			expectedImg: redacted,
+			expectedErr: nil,
+		},
+		{
+			name:    "Original not exists. Expect ErrNoImage",
+			variant: domain.OriginalImage,
+			buildCaseRepo: func() repository.CaseRepo {
+				return mocks.NewCaseRepo(t)
+			},
+			buildCameraRepo: func() repository.CameraRepo {
+				return mocks.NewCameraRepo(t)
+			},
+			buildImgService: func() ImgService {
+				mockService := mocksservice.NewImgService(t)
+
+				mockService.On("GetImg", mock.Anything, owner, domain.OriginalImage).
+					Return(domain.Image{}, errs.ErrNoImage)
+				return mockService
+			},
+			expectedImg: domain.Image{},
+			expectedErr: errs.ErrNoImage,
+		},
+		{
+			name:    "No image of case. Expect ErrNoImage",
+			variant: domain.ThumbImage,
+			buildCaseRepo: func() repository.CaseRepo {
+				return mocks.NewCaseRepo(t)
+			},
+			buildCameraRepo: func() repository.CameraRepo {
+				return mocks.NewCameraRepo(t)
+			},
+			buildImgService: func() ImgService {
+				mockService := mocksservice.NewImgService(t)
+
+				mockService.On("GetImg", mock.Anything, owner, domain.ThumbImage).
+					Return(domain.Image{}, errs.ErrNoImage)
+				mockService.On("GetImg", mock.Anything, owner, domain.OriginalImage).
+					Return(domain.Image{}, errs.ErrNoImage)
+				return mockService
+			},
+			expectedImg: domain.Image{},
+			expectedErr: errs.ErrNoImage,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			caseService := NewCaseService(
+				tc.buildCaseRepo(), mocks.NewTransportRepo(t), tc.buildCameraRepo(), tc.buildImgService(),
+				mocks.NewUnitOfWork(t), mocksservice.NewEventPublisher(t),
+			)
+
+			actual, err := caseService.GetCaseImg(context.Background(), caseID, tc.variant)
+			assert.ErrorIs(t, err, tc.expectedErr)
+			assert.Equal(t, tc.expectedImg, actual)
+		})
+	}
+}
@@ -3,11 +3,12 @@ package service
 import (
 	"TrafficPolice/internal/domain"
 	"TrafficPolice/internal/repository"
+	"context"
 	"github.com/google/uuid"
 )
 
 type ContactInfoService interface {
-	InsertContactInfo(m map[string][]*domain.Transport) error
+	InsertContactInfo(ctx context.Context, m map[string][]*domain.Transport) error
 }
 
 type contactInfoService struct {
@@ -18,7 +19,7 @@ func NewContactInfoService(repo repository.ContactInfoRepo) ContactInfoService {
 	return &contactInfoService{repo: repo}
 }
 
-func (s *contactInfoService) InsertContactInfo(m map[string][]*domain.Transport) error {
+func (s *contactInfoService) InsertContactInfo(ctx context.Context, m map[string][]*domain.Transport) error {
 	for _, transports := range m {
 		personID := uuid.New().String()
 
@@ -27,5 +28,5 @@ func (s *contactInfoService) InsertContactInfo(m map[string][]*domain.Transport)
 			transports[i].ID = uuid.New().String()
 		}
 	}
-	return s.repo.InsertContactInfo(m)
+	return s.repo.InsertContactInfo(ctx, m)
 }
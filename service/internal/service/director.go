@@ -1,45 +1,120 @@
 package service
 
 import (
+	"TrafficPolice/internal/config"
 	"TrafficPolice/internal/domain"
 	"TrafficPolice/internal/errs"
 	"TrafficPolice/internal/repository"
+	"TrafficPolice/internal/tokens"
+	"context"
+	"errors"
+	"github.com/google/uuid"
+	"log"
 	"sort"
 	"time"
 )
 
+// notificationTokenTTL is lifetime of token, which director uses for HTTP server of fine_notification
+const notificationTokenTTL = time.Hour
+
 //go:generate go run github.com/vektra/mockery/v2@v2.42.1 --name DirectorService
 type DirectorService interface {
-	GetCase(caseID string) (domain.CaseStatus, error)
-	GetExpertAnalytics(expertID string, startTime time.Time, endTime time.Time) ([]domain.AnalyticsInterval, error)
-	UpdateExpertSkill(expertID string, skill int) error
+	GetCase(ctx context.Context, caseID string) (domain.CaseStatus, error)
+	GetExpertAnalytics(
+		ctx context.Context,
+		expertID string,
+		startTime time.Time,
+		endTime time.Time,
+	) ([]domain.AnalyticsInterval, error)
+	UpdateExpertSkill(ctx context.Context, expertID string, skill int) error
+	GetReviewCases(ctx context.Context, limit int, offset int) ([]domain.ReviewCase, error)
+	ResolveReviewCase(ctx context.Context, caseID string, fineDecision bool) error
+	OverrideCaseDecision(
+		ctx context.Context,
+		userID string,
+		caseID string,
+		fineDecision bool,
+		reason string,
+	) (domain.CaseOverride, error)
+	VoidCaseFine(ctx context.Context, userID string, caseID string, reason string) (domain.CaseOverride, error)
+	ReopenCase(ctx context.Context, userID string, caseID string, reason string) (domain.CaseOverride, error)
+	// NewNotificationToken issues token of director for HTTP server of fine_notification
+	NewNotificationToken(ctx context.Context, userID string) (string, error)
 }
 
 type directorService struct {
-	directorRepo repository.DirectorRepo
-	checkerRepo  repository.CheckerRepo
+	directorRepo       repository.DirectorRepo
+	checkerRepo        repository.CheckerRepo
+	deliveryRepo       repository.DeliveryRepo
+	notificationTokens tokens.TokenManager
+	uow                repository.UnitOfWork
+	events             EventPublisher
+	finesCfg           config.FinesConfig
 }
 
+// NewDirectorService deliveryRepo can be nil, then case status is returned without deliveries of notification.
+// notificationTokens can be nil, then tokens for fine_notification are not issued
 func NewDirectorService(
 	directorRepo repository.DirectorRepo,
 	checkerRepo repository.CheckerRepo,
+	deliveryRepo repository.DeliveryRepo,
+	notificationTokens tokens.TokenManager,
+	uow repository.UnitOfWork,
+	events EventPublisher,
+	finesCfg config.FinesConfig,
 ) DirectorService {
 	return &directorService{
-		directorRepo: directorRepo,
-		checkerRepo:  checkerRepo,
+		directorRepo:       directorRepo,
+		checkerRepo:        checkerRepo,
+		deliveryRepo:       deliveryRepo,
+		notificationTokens: notificationTokens,
+		uow:                uow,
+		events:             events,
+		finesCfg:           finesCfg,
 	}
 }
 
-func (s *directorService) GetCase(caseID string) (domain.CaseStatus, error) {
-	return s.directorRepo.GetCase(caseID)
+// GetCase returns status of case with deliveries of fine notification and of notification about cancelled
+// fine. Case status is returned, even if fine_notification is unavailable
+func (s *directorService) GetCase(ctx context.Context, caseID string) (domain.CaseStatus, error) {
+	status, err := s.directorRepo.GetCase(ctx, caseID)
+	if err != nil {
+		return domain.CaseStatus{}, err
+	}
+
+	status.Notifications = make([]domain.NotificationDelivery, 0)
+	if s.deliveryRepo == nil || (!status.FineDecision && len(status.Overrides) == 0) {
+		return status, nil
+	}
+
+	deliveries, err := s.deliveryRepo.GetCaseDeliveries(ctx, caseID)
+	if err != nil {
+		log.Printf("Error while getting deliveries of case %s: %v\n", caseID, err)
+		status.NotificationsUnavailable = true
+		return status, nil
+	}
+	status.Notifications = deliveries
+
+	return status, nil
+}
+
+func (s *directorService) NewNotificationToken(_ context.Context, userID string) (string, error) {
+	if s.notificationTokens == nil {
+		return "", errs.ErrNotificationDisabled
+	}
+
+	return s.notificationTokens.NewJWT(
+		tokens.TokenInfo{UserID: userID, UserRole: domain.DirectorRole}, notificationTokenTTL,
+	)
 }
 
 func (s *directorService) GetExpertAnalytics(
+	ctx context.Context,
 	expertID string,
 	startTime time.Time,
 	endTime time.Time,
 ) ([]domain.AnalyticsInterval, error) {
-	isExpertExists, err := s.checkerRepo.CheckExpertExists(expertID)
+	isExpertExists, err := s.checkerRepo.CheckExpertExists(ctx, expertID)
 
 	if err != nil {
 		return nil, err
@@ -48,7 +123,7 @@ func (s *directorService) GetExpertAnalytics(
 		return nil, errs.ErrExpertNotExists
 	}
 
-	intervalsCases, err := s.directorRepo.GetExpertIntervalCases(expertID, startTime, endTime)
+	intervalsCases, err := s.directorRepo.GetExpertIntervalCases(ctx, expertID, startTime, endTime)
 	if err != nil {
 		return nil, err
 	}
@@ -64,10 +139,15 @@ func (s *directorService) GetExpertAnalytics(
 		correctCnt := 0
 		incorrectCnt := 0
 		unknownCnt := 0
+		expiredCnt := 0
 
 		for i := 0; i < len(interval); i++ {
 			decision := interval[i]
 
+			if decision.IsLeaseExpired {
+				expiredCnt++
+				continue
+			}
 			if !decision.IsExpertSolve {
 				unknownCnt++
 				continue
@@ -90,6 +170,7 @@ func (s *directorService) GetExpertAnalytics(
 			CorrectCnt:           correctCnt,
 			IncorrectCnt:         incorrectCnt,
 			UnknownCnt:           unknownCnt,
+			ExpiredCnt:           expiredCnt,
 			MaxConsecutiveSolved: maxConsecutive,
 		})
 	}
@@ -101,6 +182,247 @@ func (s *directorService) GetExpertAnalytics(
 	return analyticsIntervals, nil
 }
 
-func (s *directorService) UpdateExpertSkill(expertID string, skill int) error {
-	return s.directorRepo.UpdateExpertSkill(expertID, skill)
+// UpdateExpertSkill saves skill of expert with event about changed skill in one transaction
+func (s *directorService) UpdateExpertSkill(ctx context.Context, expertID string, skill int) error {
+	return s.uow.Do(ctx, func(repos repository.TxRepos) error {
+		change, err := repos.Director.UpdateExpertSkill(ctx, expertID, skill)
+		if err != nil {
+			return err
+		}
+
+		changed := skillChangedEvents([]domain.SkillChange{change}, domain.DirectorSkillChange)
+		return s.events.Publish(ctx, repos.Outbox, changed...)
+	})
+}
+
+func (s *directorService) GetReviewCases(ctx context.Context, limit int, offset int) ([]domain.ReviewCase, error) {
+	reviewCases, err := s.directorRepo.GetReviewCases(ctx, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	if len(reviewCases) == 0 {
+		return nil, errs.ErrNoRows
+	}
+
+	return reviewCases, nil
+}
+
+// ResolveReviewCase solves case in review by decision of director the same way as consensus of experts:
+// rating of experts is updated by decision of director, and fine is issued, when fine should be sent
+func (s *directorService) ResolveReviewCase(ctx context.Context, caseID string, fineDecision bool) error {
+	return s.uow.Do(ctx, func(repos repository.TxRepos) error {
+		c, err := repos.Case.LockCase(ctx, caseID)
+		if err != nil {
+			return err
+		}
+		if c.IsSolved {
+			return errs.ErrCaseAlreadySolved
+		}
+		if !c.InReview {
+			return errs.ErrCaseNotInReview
+		}
+
+		return solveCase(ctx, repos, s.events, s.finesCfg, &domain.CaseDecisionInfo{CaseID: caseID}, fineDecision)
+	})
+}
+
+// OverrideCaseDecision changes fine decision of solved case. Rating of experts, who decided case, is
+// recalculated by new decision. Not paid fine is cancelled with notification of violator, when fine
+// decision is changed to no fine, otherwise fine is issued with fine notification
+func (s *directorService) OverrideCaseDecision(
+	ctx context.Context,
+	userID string,
+	caseID string,
+	fineDecision bool,
+	reason string,
+) (domain.CaseOverride, error) {
+	override := newCaseOverride(userID, caseID, domain.OverrideDecisionAction, reason)
+	override.NewFineDecision = &fineDecision
+
+	err := s.uow.Do(ctx, func(repos repository.TxRepos) error {
+		c, err := lockSolvedCase(ctx, repos, caseID)
+		if err != nil {
+			return err
+		}
+		if c.FineDecision == fineDecision {
+			return errs.ErrDecisionNotChanged
+		}
+		override.OldFineDecision = c.FineDecision
+
+		err = revertCaseRating(ctx, repos, caseID)
+		if err != nil {
+			return err
+		}
+		if !fineDecision {
+			override.FineID, err = cancelOverriddenFine(ctx, repos, caseID, override.CreatedAt)
+			if err != nil {
+				return err
+			}
+		}
+
+		info := domain.CaseDecisionInfo{CaseID: caseID}
+		err = solveCase(ctx, repos, s.events, s.finesCfg, &info, fineDecision)
+		if err != nil {
+			return err
+		}
+		if info.FineID != "" {
+			override.FineID = &info.FineID
+		}
+
+		return recordOverride(ctx, repos, s.events, override)
+	})
+	if err != nil {
+		return domain.CaseOverride{}, err
+	}
+
+	return override, nil
+}
+
+// VoidCaseFine cancels fine, which was issued in error, with notification of violator.
+// Fine decision of case and rating of experts are not changed
+func (s *directorService) VoidCaseFine(
+	ctx context.Context,
+	userID string,
+	caseID string,
+	reason string,
+) (domain.CaseOverride, error) {
+	override := newCaseOverride(userID, caseID, domain.VoidFineAction, reason)
+
+	err := s.uow.Do(ctx, func(repos repository.TxRepos) error {
+		c, err := repos.Case.LockCase(ctx, caseID)
+		if err != nil {
+			return err
+		}
+		override.OldFineDecision = c.FineDecision
+
+		fineID, err := cancelCaseFine(ctx, repos, caseID, override.CreatedAt)
+		if err != nil {
+			return err
+		}
+		override.FineID = &fineID
+
+		return recordOverride(ctx, repos, s.events, override)
+	})
+	if err != nil {
+		return domain.CaseOverride{}, err
+	}
+
+	return override, nil
+}
+
+// ReopenCase returns solved case to experts of its required skill. Decisions of experts are voided and
+// rating, which experts got for them, is reverted. Not paid fine is cancelled with notification of violator
+func (s *directorService) ReopenCase(
+	ctx context.Context,
+	userID string,
+	caseID string,
+	reason string,
+) (domain.CaseOverride, error) {
+	override := newCaseOverride(userID, caseID, domain.ReopenCaseAction, reason)
+
+	err := s.uow.Do(ctx, func(repos repository.TxRepos) error {
+		c, err := lockSolvedCase(ctx, repos, caseID)
+		if err != nil {
+			return err
+		}
+		override.OldFineDecision = c.FineDecision
+
+		err = revertCaseRating(ctx, repos, caseID)
+		if err != nil {
+			return err
+		}
+		override.FineID, err = cancelOverriddenFine(ctx, repos, caseID, override.CreatedAt)
+		if err != nil {
+			return err
+		}
+
+		err = repos.Expert.VoidCaseDecisions(ctx, caseID, override.CreatedAt)
+		if err != nil {
+			return err
+		}
+		err = repos.Case.ReopenCase(ctx, caseID)
+		if err != nil {
+			return err
+		}
+
+		return recordOverride(ctx, repos, s.events, override)
+	})
+	if err != nil {
+		return domain.CaseOverride{}, err
+	}
+
+	return override, nil
+}
+
+// recordOverride saves override in audit log with event about corrected case
+func recordOverride(
+	ctx context.Context,
+	repos repository.TxRepos,
+	events EventPublisher,
+	override domain.CaseOverride,
+) error {
+	err := repos.Case.InsertOverride(ctx, override)
+	if err != nil {
+		return err
+	}
+
+	return events.Publish(ctx, repos.Outbox, domain.CaseOverridden{
+		CaseID:       override.CaseID,
+		Action:       override.Action,
+		FineDecision: override.NewFineDecision,
+		Reason:       override.Reason,
+	})
+}
+
+func newCaseOverride(userID string, caseID string, action domain.OverrideAction, reason string) domain.CaseOverride {
+	return domain.CaseOverride{
+		ID:        uuid.New().String(),
+		CaseID:    caseID,
+		UserID:    userID,
+		Action:    action,
+		Reason:    reason,
+		CreatedAt: time.Now(),
+	}
+}
+
+// lockSolvedCase locks case, so decisions of experts are not evaluated during correction of case
+func lockSolvedCase(ctx context.Context, repos repository.TxRepos, caseID string) (domain.Case, error) {
+	c, err := repos.Case.LockCase(ctx, caseID)
+	if err != nil {
+		return domain.Case{}, err
+	}
+	if !c.IsSolved {
+		return domain.Case{}, errs.ErrCaseNotSolved
+	}
+
+	return c, nil
+}
+
+// revertCaseRating reverts rating, which experts got for decisions of case by its current fine decision
+func revertCaseRating(ctx context.Context, repos repository.TxRepos, caseID string) error {
+	decisions, err := repos.Rating.GetSolvedCaseDecisions(ctx, domain.CaseDecisionInfo{CaseID: caseID})
+	if err != nil {
+		return err
+	}
+
+	return repos.Rating.RevertRating(ctx, decisions)
+}
+
+// cancelOverriddenFine cancels fine of case, which is not needed after correction. It returns nil,
+// when case has no fine or fine is already cancelled
+func cancelOverriddenFine(
+	ctx context.Context,
+	repos repository.TxRepos,
+	caseID string,
+	cancelledAt time.Time,
+) (*string, error) {
+	fineID, err := cancelCaseFine(ctx, repos, caseID, cancelledAt)
+	if errors.Is(err, errs.ErrNoFine) || errors.Is(err, errs.ErrFineCancelled) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &fineID, nil
 }
@@ -1,13 +1,18 @@
 package service
 
 import (
+	"TrafficPolice/internal/config"
 	"TrafficPolice/internal/domain"
 	"TrafficPolice/internal/errs"
 	"TrafficPolice/internal/repository"
 	"TrafficPolice/internal/repository/mocks"
+	mocksservice "TrafficPolice/internal/service/mocks"
+	"TrafficPolice/internal/tokens"
+	"context"
 	"errors"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	"testing"
 	"time"
 )
@@ -16,29 +21,118 @@ func TestGetCases(t *testing.T) {
 	caseID := uuid.New().String()
 	caseStatus := domain.CaseStatus{CaseID: caseID, ViolationValue: "100km/h", RequiredSkill: 2,
 		CaseDate: time.Now(), FineDecision: true}
+	deliveries := []domain.NotificationDelivery{
+		{Channel: "email", Status: "delivered", Attempts: 1, CreatedAt: time.Now(), UpdatedAt: time.Now()},
+	}
+
+	withNotifications := func(status domain.CaseStatus, deliveries []domain.NotificationDelivery,
+		unavailable bool) domain.CaseStatus {
+		status.Notifications = deliveries
+		status.NotificationsUnavailable = unavailable
+		return status
+	}
+	noFineStatus := caseStatus
+	noFineStatus.FineDecision = false
+	overriddenStatus := noFineStatus
+	overriddenStatus.Overrides = []domain.CaseOverride{{CaseID: caseID, Action: domain.OverrideDecisionAction}}
+	cancellations := []domain.NotificationDelivery{
+		{Kind: "cancellation", Channel: "sms", Status: "delivered", Attempts: 1, CreatedAt: time.Now()},
+	}
 
 	errInternal := errors.New("internal repo error")
 
 	testCases := []struct {
 		name               string
 		buildDirectorRepo  func() repository.DirectorRepo
-		buildCheckerRepo   func() repository.CheckerRepo
+		buildDeliveryRepo  func() repository.DeliveryRepo
 		expectedCaseStatus domain.CaseStatus
 		expectedErr        error
 	}{
 		{
-			name: "Get status. Expect no error",
+			name: "Fine decision is overridden. Expect deliveries of cancellation",
 			buildDirectorRepo: func() repository.DirectorRepo {
 				mockRepo := mocks.NewDirectorRepo(t)
 
-				mockRepo.On("GetCase", caseID).
+				mockRepo.On("GetCase", mock.Anything, caseID).
+					Return(overriddenStatus, nil)
+				return mockRepo
+			},
+			buildDeliveryRepo: func() repository.DeliveryRepo {
+				mockRepo := mocks.NewDeliveryRepo(t)
+
+				mockRepo.On("GetCaseDeliveries", mock.Anything, caseID).
+					Return(cancellations, nil)
+				return mockRepo
+			},
+			expectedCaseStatus: withNotifications(overriddenStatus, cancellations, false),
+			expectedErr:        nil,
+		},
+		{
+			name: "Get status with deliveries. Expect no error",
+			buildDirectorRepo: func() repository.DirectorRepo {
+				mockRepo := mocks.NewDirectorRepo(t)
+
+				mockRepo.On("GetCase", mock.Anything, caseID).
 					Return(caseStatus, nil)
 				return mockRepo
 			},
-			buildCheckerRepo: func() repository.CheckerRepo {
-				return mocks.NewCheckerRepo(t)
+			buildDeliveryRepo: func() repository.DeliveryRepo {
+				mockRepo := mocks.NewDeliveryRepo(t)
+
+				mockRepo.On("GetCaseDeliveries", mock.Anything, caseID).
+					Return(deliveries, nil)
+				return mockRepo
+			},
+			expectedCaseStatus: withNotifications(caseStatus, deliveries, false),
+			expectedErr:        nil,
+		},
+		{
+			name: "Fine is not decided. Deliveries are not requested",
+			buildDirectorRepo: func() repository.DirectorRepo {
+				mockRepo := mocks.NewDirectorRepo(t)
+
+				mockRepo.On("GetCase", mock.Anything, caseID).
+					Return(noFineStatus, nil)
+				return mockRepo
+			},
+			buildDeliveryRepo: func() repository.DeliveryRepo {
+				return mocks.NewDeliveryRepo(t)
 			},
-			expectedCaseStatus: caseStatus,
+			expectedCaseStatus: withNotifications(noFineStatus, []domain.NotificationDelivery{}, false),
+			expectedErr:        nil,
+		},
+		{
+			name: "Delivery status is disabled. Expect status without deliveries",
+			buildDirectorRepo: func() repository.DirectorRepo {
+				mockRepo := mocks.NewDirectorRepo(t)
+
+				mockRepo.On("GetCase", mock.Anything, caseID).
+					Return(caseStatus, nil)
+				return mockRepo
+			},
+			buildDeliveryRepo: func() repository.DeliveryRepo {
+				return nil
+			},
+			expectedCaseStatus: withNotifications(caseStatus, []domain.NotificationDelivery{}, false),
+			expectedErr:        nil,
+		},
+		{
+			name: "Fine notification is unavailable. Expect status with unavailable deliveries",
+			buildDirectorRepo: func() repository.DirectorRepo {
+				mockRepo := mocks.NewDirectorRepo(t)
+
+				mockRepo.On("GetCase", mock.Anything, caseID).
+					Return(caseStatus, nil)
+				return mockRepo
+			},
+			buildDeliveryRepo: func() repository.DeliveryRepo {
+				mockRepo := mocks.NewDeliveryRepo(t)
+
+				mockRepo.On("GetCaseDeliveries", mock.Anything, caseID).
+					Return(nil, errInternal)
+				return mockRepo
+			},
+			expectedCaseStatus: withNotifications(caseStatus, []domain.NotificationDelivery{}, true),
 			expectedErr:        nil,
 		},
 		{
@@ -46,12 +140,12 @@ func TestGetCases(t *testing.T) {
 			buildDirectorRepo: func() repository.DirectorRepo {
 				mockRepo := mocks.NewDirectorRepo(t)
 
-				mockRepo.On("GetCase", caseID).
+				mockRepo.On("GetCase", mock.Anything, caseID).
 					Return(domain.CaseStatus{}, errs.ErrNoCase)
 				return mockRepo
 			},
-			buildCheckerRepo: func() repository.CheckerRepo {
-				return mocks.NewCheckerRepo(t)
+			buildDeliveryRepo: func() repository.DeliveryRepo {
+				return mocks.NewDeliveryRepo(t)
 			},
 			expectedCaseStatus: domain.CaseStatus{},
 			expectedErr:        errs.ErrNoCase,
@@ -61,12 +155,12 @@ func TestGetCases(t *testing.T) {
 			buildDirectorRepo: func() repository.DirectorRepo {
 				mockRepo := mocks.NewDirectorRepo(t)
 
-				mockRepo.On("GetCase", caseID).
+				mockRepo.On("GetCase", mock.Anything, caseID).
 					Return(domain.CaseStatus{}, errInternal)
 				return mockRepo
 			},
-			buildCheckerRepo: func() repository.CheckerRepo {
-				return mocks.NewCheckerRepo(t)
+			buildDeliveryRepo: func() repository.DeliveryRepo {
+				return mocks.NewDeliveryRepo(t)
 			},
 			expectedCaseStatus: domain.CaseStatus{},
 			expectedErr:        errInternal,
@@ -75,12 +169,10 @@ func TestGetCases(t *testing.T) {
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			directorRepo := tc.buildDirectorRepo()
-			checkerRepo := tc.buildCheckerRepo()
+			directorService := NewDirectorService(tc.buildDirectorRepo(), mocks.NewCheckerRepo(t), tc.buildDeliveryRepo(), nil,
+				mocks.NewUnitOfWork(t), mocksservice.NewEventPublisher(t), config.FinesConfig{})
 
-			directorService := NewDirectorService(directorRepo, checkerRepo)
-
-			cases, err := directorService.GetCase(caseID)
+			cases, err := directorService.GetCase(context.Background(), caseID)
 			assert.Equal(t, tc.expectedErr, err)
 			assert.Equal(t, tc.expectedCaseStatus, cases)
 		})
@@ -117,6 +209,8 @@ func TestGetExpertAnalytics(t *testing.T) {
 				IsExpertSolve: true, ExpertFineDecision: true, CaseFineDecision: false},
 			{GotAt: mustParseTime(t, time.DateTime, "2024-01-01 15:02:00"),
 				IsExpertSolve: false, ExpertFineDecision: false, CaseFineDecision: false},
+			{GotAt: mustParseTime(t, time.DateTime, "2024-01-02 16:00:00"),
+				IsExpertSolve: false, IsLeaseExpired: true},
 		},
 	}
 	expertID := "expert_id"
@@ -126,8 +220,8 @@ func TestGetExpertAnalytics(t *testing.T) {
 	intervals := []domain.AnalyticsInterval{
 		{Date: domain.Date{Year: 2024, Month: time.January, Day: 1}, AllCases: 5, CorrectCnt: 3, IncorrectCnt: 2,
 			UnknownCnt: 0, MaxConsecutiveSolved: 3},
-		{Date: domain.Date{Year: 2024, Month: time.January, Day: 2}, AllCases: 3, CorrectCnt: 1, IncorrectCnt: 1,
-			UnknownCnt: 1, MaxConsecutiveSolved: 1},
+		{Date: domain.Date{Year: 2024, Month: time.January, Day: 2}, AllCases: 4, CorrectCnt: 1, IncorrectCnt: 1,
+			UnknownCnt: 1, ExpiredCnt: 1, MaxConsecutiveSolved: 1},
 	}
 
 	testCases := []struct {
@@ -144,7 +238,7 @@ func TestGetExpertAnalytics(t *testing.T) {
 			name: "Get expert intervals",
 			buildDirectorRepo: func() repository.DirectorRepo {
 				mockRepo := mocks.NewDirectorRepo(t)
-				mockRepo.On("GetExpertIntervalCases", expertID, startTime, endTime).
+				mockRepo.On("GetExpertIntervalCases", mock.Anything, expertID, startTime, endTime).
 					Return(intervalsCases, nil)
 
 				return mockRepo
@@ -152,7 +246,7 @@ func TestGetExpertAnalytics(t *testing.T) {
 			},
 			buildCheckerRepo: func() repository.CheckerRepo {
 				mockRepo := mocks.NewCheckerRepo(t)
-				mockRepo.On("CheckExpertExists", expertID).
+				mockRepo.On("CheckExpertExists", mock.Anything, expertID).
 					Return(true, nil)
 
 				return mockRepo
@@ -172,7 +266,7 @@ func TestGetExpertAnalytics(t *testing.T) {
 			},
 			buildCheckerRepo: func() repository.CheckerRepo {
 				mockRepo := mocks.NewCheckerRepo(t)
-				mockRepo.On("CheckExpertExists", expertID).
+				mockRepo.On("CheckExpertExists", mock.Anything, expertID).
 					Return(false, nil)
 
 				return mockRepo
@@ -190,11 +284,582 @@ func TestGetExpertAnalytics(t *testing.T) {
 			directorRepo := tc.buildDirectorRepo()
 			checkerRepo := tc.buildCheckerRepo()
 
-			directorService := NewDirectorService(directorRepo, checkerRepo)
+			directorService := NewDirectorService(directorRepo, checkerRepo, nil, nil, mocks.NewUnitOfWork(t),
+				mocksservice.NewEventPublisher(t), config.FinesConfig{})
 
-			actualIntervals, err := directorService.GetExpertAnalytics(tc.expertID, tc.startTime, tc.endTime)
+			actualIntervals, err := directorService.GetExpertAnalytics(context.Background(), tc.expertID, tc.startTime, tc.endTime)
 			assert.Equal(t, tc.expectedErr, err)
 			assert.Equal(t, tc.expectedIntervals, actualIntervals)
 		})
 	}
 }
+
+func TestUpdateExpertSkill(t *testing.T) {
+	expertID := uuid.New().String()
+	errDB := errors.New("db error")
+
+	testCases := []struct {
+		name           string
+		change         domain.SkillChange
+		updateErr      error
+		expectedEvents []any
+		expectedErr    error
+	}{
+		{
+			name:   "Skill is changed. Expect event",
+			change: domain.SkillChange{ExpertID: expertID, OldSkill: 1, NewSkill: 3},
+			expectedEvents: []any{domain.ExpertSkillChanged{
+				ExpertID: expertID, OldSkill: 1, NewSkill: 3, Reason: domain.DirectorSkillChange,
+			}},
+		},
+		{
+			name:   "Skill is the same. Expect no event",
+			change: domain.SkillChange{ExpertID: expertID, OldSkill: 3, NewSkill: 3},
+		},
+		{
+			name:   "Expert not exists. Expect no event",
+			change: domain.SkillChange{},
+		},
+		{
+			name:        "Skill is not updated. Expect error",
+			updateErr:   errDB,
+			expectedErr: errDB,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			directorRepo := mocks.NewDirectorRepo(t)
+			directorRepo.On("UpdateExpertSkill", mock.Anything, expertID, 3).
+				Return(tc.change, tc.updateErr)
+			eventPublisher := mocksservice.NewEventPublisher(t)
+			if tc.expectedErr == nil {
+				eventPublisher.On("Publish", append([]any{mock.Anything, mock.Anything}, tc.expectedEvents...)...).
+					Return(nil)
+			}
+
+			directorService := NewDirectorService(mocks.NewDirectorRepo(t), mocks.NewCheckerRepo(t), nil, nil,
+				newUnitOfWork(t, repository.TxRepos{Director: directorRepo}), eventPublisher, config.FinesConfig{})
+
+			err := directorService.UpdateExpertSkill(context.Background(), expertID, 3)
+			assert.Equal(t, tc.expectedErr, err)
+		})
+	}
+}
+
+func TestGetReviewCases(t *testing.T) {
+	reviewCases := []domain.ReviewCase{{CaseID: uuid.New().String(), RequiredSkill: 5, PositiveDecisions: 1,
+		NegativeDecisions: 1, ReviewRequestedAt: time.Now()}}
+	errDB := errors.New("db error")
+
+	testCases := []struct {
+		name          string
+		repoCases     []domain.ReviewCase
+		repoErr       error
+		expectedCases []domain.ReviewCase
+		expectedErr   error
+	}{
+		{name: "Cases in review", repoCases: reviewCases, expectedCases: reviewCases},
+		{name: "No cases in review. Expect ErrNoRows", repoCases: []domain.ReviewCase{}, expectedErr: errs.ErrNoRows},
+		{name: "Repository error", repoErr: errDB, expectedErr: errDB},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			directorRepo := mocks.NewDirectorRepo(t)
+			directorRepo.On("GetReviewCases", mock.Anything, 10, 20).
+				Return(tc.repoCases, tc.repoErr)
+
+			directorService := NewDirectorService(directorRepo, mocks.NewCheckerRepo(t), nil, nil, mocks.NewUnitOfWork(t),
+				mocksservice.NewEventPublisher(t), config.FinesConfig{})
+
+			actualCases, err := directorService.GetReviewCases(context.Background(), 10, 20)
+			assert.Equal(t, tc.expectedErr, err)
+			assert.Equal(t, tc.expectedCases, actualCases)
+		})
+	}
+}
+
+func TestNewNotificationToken(t *testing.T) {
+	userID := uuid.New().String()
+	userTokens, err := tokens.NewTokenManager("user_key")
+	assert.NoError(t, err)
+	notificationTokens, err := tokens.NewTokenManager("notification_key")
+	assert.NoError(t, err)
+
+	directorService := NewDirectorService(mocks.NewDirectorRepo(t), mocks.NewCheckerRepo(t), nil, notificationTokens,
+		mocks.NewUnitOfWork(t), mocksservice.NewEventPublisher(t), config.FinesConfig{})
+
+	token, err := directorService.NewNotificationToken(context.Background(), userID)
+	assert.NoError(t, err)
+
+	tokenInfo, err := notificationTokens.Parse(token)
+	assert.NoError(t, err)
+	assert.Equal(t, tokens.TokenInfo{UserID: userID, UserRole: domain.DirectorRole}, tokenInfo)
+
+	// token for fine_notification is not accepted by service
+	_, err = userTokens.Parse(token)
+	assert.Error(t, err)
+
+	directorService = NewDirectorService(mocks.NewDirectorRepo(t), mocks.NewCheckerRepo(t), nil, nil,
+		mocks.NewUnitOfWork(t), mocksservice.NewEventPublisher(t), config.FinesConfig{})
+	_, err = directorService.NewNotificationToken(context.Background(), userID)
+	assert.ErrorIs(t, err, errs.ErrNotificationDisabled)
+}
+
+func TestResolveReviewCase(t *testing.T) {
+	caseID := uuid.New().String()
+	reviewCase := domain.Case{ID: caseID, RequiredSkill: 5, InReview: true}
+	solvedDecisions := []domain.ExpertCaseDecision{{ExpertID: uuid.New().String(), IsRight: false}}
+	finesCfg := config.FinesConfig{ReferencePrefix: "18810", DueIn: time.Hour}
+
+	testCases := []struct {
+		name           string
+		c              domain.Case
+		lockErr        error
+		fineDecision   bool
+		expectSolve    bool
+		expectFine     bool
+		expectedEvents []any
+		expectedErr    error
+	}{
+		{
+			name:         "Director decides to fine. Fine is issued",
+			c:            reviewCase,
+			fineDecision: true,
+			expectSolve:  true,
+			expectFine:   true,
+			expectedEvents: []any{mock.MatchedBy(func(e domain.CaseSolved) bool {
+				return e.CaseID == caseID && e.FineDecision && e.Fine != nil && e.Fine.Amount == 500
+			})},
+		},
+		{
+			name:           "Director decides not to fine",
+			c:              reviewCase,
+			expectSolve:    true,
+			expectedEvents: []any{domain.CaseSolved{CaseID: caseID}},
+		},
+		{
+			name:        "Case not exists",
+			lockErr:     errs.ErrNoCase,
+			expectedErr: errs.ErrNoCase,
+		},
+		{
+			name:        "Case is solved by expert in review. Expect ErrCaseAlreadySolved",
+			c:           domain.Case{ID: caseID, RequiredSkill: 5, InReview: true, IsSolved: true},
+			expectedErr: errs.ErrCaseAlreadySolved,
+		},
+		{
+			name:        "Case is not in review. Expect ErrCaseNotInReview",
+			c:           domain.Case{ID: caseID, RequiredSkill: 2},
+			expectedErr: errs.ErrCaseNotInReview,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			caseRepo := mocks.NewCaseRepo(t)
+			caseRepo.On("LockCase", mock.Anything, caseID).
+				Return(tc.c, tc.lockErr)
+			ratingRepo := mocks.NewRatingRepo(t)
+			outboxRepo := mocks.NewOutboxRepo(t)
+			fineRepo := mocks.NewFineRepo(t)
+			if tc.expectSolve {
+				caseRepo.On("SetCaseFineDecision", mock.Anything, caseID, tc.fineDecision, mock.AnythingOfType("time.Time")).
+					Return(nil)
+				info := domain.CaseDecisionInfo{CaseID: caseID, IsSolved: true, ShouldSendFine: tc.fineDecision}
+				ratingRepo.On("GetSolvedCaseDecisions", mock.Anything, info).
+					Return(solvedDecisions, nil)
+				ratingRepo.On("SetRating", mock.Anything, solvedDecisions).
+					Return(nil)
+			}
+			if tc.expectFine {
+				fineRepo.On("GetCaseFineAmount", mock.Anything, caseID).
+					Return(500, nil)
+				fineRepo.On("InsertFine", mock.Anything, mock.AnythingOfType("domain.Fine")).
+					Return(nil)
+				outboxRepo.On("InsertMessage", mock.Anything, mock.MatchedBy(func(msg domain.OutboxMessage) bool {
+					return msg.Kind == domain.FineNotificationKind
+				})).
+					Return(nil)
+			}
+			uow := newUnitOfWork(t, repository.TxRepos{Case: caseRepo, Rating: ratingRepo, Outbox: outboxRepo, Fine: fineRepo})
+
+			directorService := NewDirectorService(mocks.NewDirectorRepo(t), mocks.NewCheckerRepo(t), nil, nil, uow,
+				newEventPublisher(t, tc.expectedEvents...), finesCfg)
+
+			err := directorService.ResolveReviewCase(context.Background(), caseID, tc.fineDecision)
+			assert.Equal(t, tc.expectedErr, err)
+		})
+	}
+}
+
+// caseOverride matches record of audit log, nil fineID matches override without fine
+func caseOverride(
+	caseID string,
+	userID string,
+	action domain.OverrideAction,
+	oldDecision bool,
+	newDecision *bool,
+	fineID *string,
+) any {
+	return mock.MatchedBy(func(o domain.CaseOverride) bool {
+		sameDecision := (newDecision == nil && o.NewFineDecision == nil) ||
+			(newDecision != nil && o.NewFineDecision != nil && *newDecision == *o.NewFineDecision)
+		sameFine := (fineID == nil && o.FineID == nil) ||
+			(fineID != nil && o.FineID != nil && (*fineID == "" || *fineID == *o.FineID))
+		return o.ID != "" && o.CaseID == caseID && o.UserID == userID && o.Action == action &&
+			o.OldFineDecision == oldDecision && sameDecision && sameFine && o.Reason == "reason" &&
+			!o.CreatedAt.IsZero()
+	})
+}
+
+// isFineMessage matches outbox message of kind about fine of case
+func isFineMessage(kind domain.OutboxKind, caseID string, fineID string) any {
+	return mock.MatchedBy(func(msg domain.OutboxMessage) bool {
+		return msg.Kind == kind && string(msg.Payload) == `{"case_id":"`+caseID+`"`+fineIDField(fineID)+`}`
+	})
+}
+
+func fineIDField(fineID string) string {
+	if fineID == "" {
+		return ""
+	}
+	return `,"fine_id":"` + fineID + `"`
+}
+
+func TestOverrideCaseDecision(t *testing.T) {
+	caseID := uuid.New().String()
+	userID := uuid.New().String()
+	fineID := uuid.New().String()
+	finesCfg := config.FinesConfig{ReferencePrefix: "18810", DueIn: time.Hour}
+	fineCase := domain.Case{ID: caseID, IsSolved: true, FineDecision: true}
+	noFineCase := domain.Case{ID: caseID, IsSolved: true}
+	issuedFine := domain.Fine{ID: fineID, CaseID: caseID, Status: domain.FineIssued}
+	paidFine := domain.Fine{ID: fineID, CaseID: caseID, Status: domain.FinePaid}
+	oldDecisions := []domain.ExpertCaseDecision{{ExpertID: "expert_1", IsRight: true}, {ExpertID: "expert_2"}}
+	newDecisions := []domain.ExpertCaseDecision{{ExpertID: "expert_1"}, {ExpertID: "expert_2", IsRight: true}}
+	fine, noFine := true, false
+	anyFineID := ""
+
+	testCases := []struct {
+		name           string
+		c              domain.Case
+		fineDecision   bool
+		fine           domain.Fine
+		fineErr        error
+		expectRevert   bool
+		expectCancel   bool
+		expectSolve    bool
+		expectIssue    bool
+		expectedLog    any
+		expectedEvents []any
+		expectedErr    error
+	}{
+		{
+			name:         "Fine is not needed. Fine is cancelled with notification, rating is recalculated",
+			c:            fineCase,
+			fineDecision: false,
+			fine:         issuedFine,
+			expectRevert: true,
+			expectCancel: true,
+			expectSolve:  true,
+			expectedLog:  caseOverride(caseID, userID, domain.OverrideDecisionAction, true, &noFine, &fineID),
+			expectedEvents: []any{
+				domain.CaseSolved{CaseID: caseID},
+				domain.CaseOverridden{
+					CaseID: caseID, Action: domain.OverrideDecisionAction, FineDecision: &noFine, Reason: "reason",
+				},
+			},
+		},
+		{
+			name:         "Case was solved before fines. Fine decision is changed without cancellation",
+			c:            fineCase,
+			fineDecision: false,
+			fineErr:      errs.ErrNoFine,
+			expectRevert: true,
+			expectSolve:  true,
+			expectedLog:  caseOverride(caseID, userID, domain.OverrideDecisionAction, true, &noFine, nil),
+			expectedEvents: []any{
+				domain.CaseSolved{CaseID: caseID},
+				domain.CaseOverridden{
+					CaseID: caseID, Action: domain.OverrideDecisionAction, FineDecision: &noFine, Reason: "reason",
+				},
+			},
+		},
+		{
+			name:         "Fine is needed. Fine is issued with notification",
+			c:            noFineCase,
+			fineDecision: true,
+			expectRevert: true,
+			expectSolve:  true,
+			expectIssue:  true,
+			expectedLog:  caseOverride(caseID, userID, domain.OverrideDecisionAction, false, &fine, &anyFineID),
+			expectedEvents: []any{
+				mock.MatchedBy(func(e domain.CaseSolved) bool {
+					return e.CaseID == caseID && e.FineDecision && e.Fine != nil
+				}),
+				domain.CaseOverridden{
+					CaseID: caseID, Action: domain.OverrideDecisionAction, FineDecision: &fine, Reason: "reason",
+				},
+			},
+		},
+		{
+			name:         "Fine is paid. Expect ErrFineCannotBeCancelled",
+			c:            fineCase,
+			fineDecision: false,
+			fine:         paidFine,
+			expectRevert: true,
+			expectedErr:  errs.ErrFineCannotBeCancelled,
+		},
+		{
+			name:         "Fine decision is the same. Expect ErrDecisionNotChanged",
+			c:            fineCase,
+			fineDecision: true,
+			expectedErr:  errs.ErrDecisionNotChanged,
+		},
+		{
+			name:         "Case is not solved. Expect ErrCaseNotSolved",
+			c:            domain.Case{ID: caseID, InReview: true},
+			fineDecision: true,
+			expectedErr:  errs.ErrCaseNotSolved,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			caseRepo := mocks.NewCaseRepo(t)
+			caseRepo.On("LockCase", mock.Anything, caseID).
+				Return(tc.c, nil)
+			ratingRepo := mocks.NewRatingRepo(t)
+			outboxRepo := mocks.NewOutboxRepo(t)
+			fineRepo := mocks.NewFineRepo(t)
+			if tc.expectRevert {
+				ratingRepo.On("GetSolvedCaseDecisions", mock.Anything, domain.CaseDecisionInfo{CaseID: caseID}).
+					Return(oldDecisions, nil).
+					Once()
+				ratingRepo.On("RevertRating", mock.Anything, oldDecisions).
+					Return(nil)
+			}
+			if tc.expectRevert && !tc.fineDecision {
+				fineRepo.On("GetCaseFine", mock.Anything, caseID).
+					Return(tc.fine, tc.fineErr)
+			}
+			if tc.fineErr == nil && tc.fine.ID != "" {
+				fineRepo.On("LockFine", mock.Anything, fineID).
+					Return(tc.fine, nil)
+			}
+			if tc.expectCancel {
+				fineRepo.On("UpdateFineStatus", mock.Anything, mock.MatchedBy(func(f domain.Fine) bool {
+					return f.ID == fineID && f.Status == domain.FineCancelled && f.CancelledAt != nil
+				})).
+					Return(nil)
+				outboxRepo.On("InsertMessage", mock.Anything, isFineMessage(domain.FineCancellationKind, caseID, fineID)).
+					Return(nil)
+			}
+			if tc.expectSolve {
+				caseRepo.On("SetCaseFineDecision", mock.Anything, caseID, tc.fineDecision, mock.AnythingOfType("time.Time")).
+					Return(nil)
+				info := domain.CaseDecisionInfo{CaseID: caseID, IsSolved: true, ShouldSendFine: tc.fineDecision}
+				ratingRepo.On("GetSolvedCaseDecisions", mock.Anything, info).
+					Return(newDecisions, nil)
+				ratingRepo.On("SetRating", mock.Anything, newDecisions).
+					Return(nil)
+				caseRepo.On("InsertOverride", mock.Anything, tc.expectedLog).
+					Return(nil)
+			}
+			if tc.expectIssue {
+				fineRepo.On("GetCaseFineAmount", mock.Anything, caseID).
+					Return(500, nil)
+				fineRepo.On("InsertFine", mock.Anything, mock.AnythingOfType("domain.Fine")).
+					Return(nil)
+				outboxRepo.On("InsertMessage", mock.Anything, isFineMessage(domain.FineNotificationKind, caseID, "")).
+					Return(nil)
+			}
+			uow := newUnitOfWork(t, repository.TxRepos{Case: caseRepo, Rating: ratingRepo, Outbox: outboxRepo, Fine: fineRepo})
+
+			directorService := NewDirectorService(mocks.NewDirectorRepo(t), mocks.NewCheckerRepo(t), nil, nil, uow,
+				newEventPublisher(t, tc.expectedEvents...), finesCfg)
+
+			override, err := directorService.OverrideCaseDecision(context.Background(), userID, caseID,
+				tc.fineDecision, "reason")
+			assert.Equal(t, tc.expectedErr, err)
+			if tc.expectedErr == nil {
+				assert.Equal(t, tc.expectIssue || tc.expectCancel, override.FineID != nil)
+			}
+		})
+	}
+}
+
+func TestVoidCaseFine(t *testing.T) {
+	caseID := uuid.New().String()
+	userID := uuid.New().String()
+	fineID := uuid.New().String()
+	fineCase := domain.Case{ID: caseID, IsSolved: true, FineDecision: true}
+
+	testCases := []struct {
+		name           string
+		fine           domain.Fine
+		fineErr        error
+		expectCancel   bool
+		expectedEvents []any
+		expectedErr    error
+	}{
+		{
+			name:         "Fine is cancelled with notification. Fine decision is kept",
+			fine:         domain.Fine{ID: fineID, CaseID: caseID, Status: domain.FineOverdue},
+			expectCancel: true,
+			expectedEvents: []any{
+				domain.CaseOverridden{CaseID: caseID, Action: domain.VoidFineAction, Reason: "reason"},
+			},
+		},
+		{
+			name:        "Fine is already cancelled. Expect ErrFineCancelled",
+			fine:        domain.Fine{ID: fineID, CaseID: caseID, Status: domain.FineCancelled},
+			expectedErr: errs.ErrFineCancelled,
+		},
+		{
+			name:        "Fine is paid. Expect ErrFineCannotBeCancelled",
+			fine:        domain.Fine{ID: fineID, CaseID: caseID, Status: domain.FinePaid},
+			expectedErr: errs.ErrFineCannotBeCancelled,
+		},
+		{
+			name:        "Case has no fine. Expect ErrNoFine",
+			fineErr:     errs.ErrNoFine,
+			expectedErr: errs.ErrNoFine,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			caseRepo := mocks.NewCaseRepo(t)
+			caseRepo.On("LockCase", mock.Anything, caseID).
+				Return(fineCase, nil)
+			outboxRepo := mocks.NewOutboxRepo(t)
+			fineRepo := mocks.NewFineRepo(t)
+			fineRepo.On("GetCaseFine", mock.Anything, caseID).
+				Return(tc.fine, tc.fineErr)
+			if tc.fineErr == nil {
+				fineRepo.On("LockFine", mock.Anything, fineID).
+					Return(tc.fine, nil)
+			}
+			if tc.expectCancel {
+				fineRepo.On("UpdateFineStatus", mock.Anything, mock.MatchedBy(func(f domain.Fine) bool {
+					return f.ID == fineID && f.Status == domain.FineCancelled && f.CancelledAt != nil
+				})).
+					Return(nil)
+				outboxRepo.On("InsertMessage", mock.Anything, isFineMessage(domain.FineCancellationKind, caseID, fineID)).
+					Return(nil)
+				caseRepo.On("InsertOverride", mock.Anything,
+					caseOverride(caseID, userID, domain.VoidFineAction, true, nil, &fineID)).
+					Return(nil)
+			}
+			uow := newUnitOfWork(t, repository.TxRepos{Case: caseRepo, Outbox: outboxRepo, Fine: fineRepo})
+
+			directorService := NewDirectorService(mocks.NewDirectorRepo(t), mocks.NewCheckerRepo(t), nil, nil, uow,
+				newEventPublisher(t, tc.expectedEvents...), config.FinesConfig{})
+
+			_, err := directorService.VoidCaseFine(context.Background(), userID, caseID, "reason")
+			assert.Equal(t, tc.expectedErr, err)
+		})
+	}
+}
+
+func TestReopenCase(t *testing.T) {
+	caseID := uuid.New().String()
+	userID := uuid.New().String()
+	fineID := uuid.New().String()
+	decisions := []domain.ExpertCaseDecision{{ExpertID: "expert_1", IsRight: true}}
+
+	testCases := []struct {
+		name           string
+		c              domain.Case
+		fine           domain.Fine
+		fineErr        error
+		expectReopen   bool
+		expectCancel   bool
+		expectedFineID *string
+		expectedEvents []any
+		expectedErr    error
+	}{
+		{
+			name:           "Case with fine. Fine is cancelled, decisions are voided",
+			c:              domain.Case{ID: caseID, IsSolved: true, FineDecision: true},
+			fine:           domain.Fine{ID: fineID, CaseID: caseID, Status: domain.FineIssued},
+			expectReopen:   true,
+			expectCancel:   true,
+			expectedFineID: &fineID,
+			expectedEvents: []any{
+				domain.CaseOverridden{CaseID: caseID, Action: domain.ReopenCaseAction, Reason: "reason"},
+			},
+		},
+		{
+			name:         "Case without fine. Decisions are voided",
+			c:            domain.Case{ID: caseID, IsSolved: true},
+			fineErr:      errs.ErrNoFine,
+			expectReopen: true,
+			expectedEvents: []any{
+				domain.CaseOverridden{CaseID: caseID, Action: domain.ReopenCaseAction, Reason: "reason"},
+			},
+		},
+		{
+			name:        "Fine is paid. Expect ErrFineCannotBeCancelled",
+			c:           domain.Case{ID: caseID, IsSolved: true, FineDecision: true},
+			fine:        domain.Fine{ID: fineID, CaseID: caseID, Status: domain.FinePaid},
+			expectedErr: errs.ErrFineCannotBeCancelled,
+		},
+		{
+			name:        "Case is not solved. Expect ErrCaseNotSolved",
+			c:           domain.Case{ID: caseID},
+			expectedErr: errs.ErrCaseNotSolved,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			caseRepo := mocks.NewCaseRepo(t)
+			caseRepo.On("LockCase", mock.Anything, caseID).
+				Return(tc.c, nil)
+			expertRepo := mocks.NewExpertRepo(t)
+			ratingRepo := mocks.NewRatingRepo(t)
+			outboxRepo := mocks.NewOutboxRepo(t)
+			fineRepo := mocks.NewFineRepo(t)
+			if tc.c.IsSolved {
+				ratingRepo.On("GetSolvedCaseDecisions", mock.Anything, domain.CaseDecisionInfo{CaseID: caseID}).
+					Return(decisions, nil)
+				ratingRepo.On("RevertRating", mock.Anything, decisions).
+					Return(nil)
+				fineRepo.On("GetCaseFine", mock.Anything, caseID).
+					Return(tc.fine, tc.fineErr)
+			}
+			if tc.fine.ID != "" {
+				fineRepo.On("LockFine", mock.Anything, fineID).
+					Return(tc.fine, nil)
+			}
+			if tc.expectCancel {
+				fineRepo.On("UpdateFineStatus", mock.Anything, mock.AnythingOfType("domain.Fine")).
+					Return(nil)
+				outboxRepo.On("InsertMessage", mock.Anything, isFineMessage(domain.FineCancellationKind, caseID, fineID)).
+					Return(nil)
+			}
+			if tc.expectReopen {
+				expertRepo.On("VoidCaseDecisions", mock.Anything, caseID, mock.AnythingOfType("time.Time")).
+					Return(nil)
+				caseRepo.On("ReopenCase", mock.Anything, caseID).
+					Return(nil)
+				caseRepo.On("InsertOverride", mock.Anything,
+					caseOverride(caseID, userID, domain.ReopenCaseAction, tc.c.FineDecision, nil, tc.expectedFineID)).
+					Return(nil)
+			}
+			uow := newUnitOfWork(t, repository.TxRepos{
+				Expert: expertRepo, Case: caseRepo, Rating: ratingRepo, Outbox: outboxRepo, Fine: fineRepo,
+			})
+
+			directorService := NewDirectorService(mocks.NewDirectorRepo(t), mocks.NewCheckerRepo(t), nil, nil, uow,
+				newEventPublisher(t, tc.expectedEvents...), config.FinesConfig{})
+
+			_, err := directorService.ReopenCase(context.Background(), userID, caseID, "reason")
+			assert.Equal(t, tc.expectedErr, err)
+		})
+	}
+}
@@ -0,0 +1,49 @@
+package service
+
+import (
+	"TrafficPolice/internal/domain"
+	"TrafficPolice/internal/repository"
+	"context"
+	"encoding/json"
+	"github.com/google/uuid"
+	"time"
+)
+
+// EventPublisher emits domain events. Events are recorded in outbox of unit of work, so they are committed
+// together with state change, which they are about, and relay publishes them to topic exchange of events
+//
+//go:generate go run github.com/vektra/mockery/v2@v2.42.1 --name EventPublisher
+type EventPublisher interface {
+	Publish(ctx context.Context, outbox repository.OutboxRepo, events ...domain.Event) error
+}
+
+type outboxEventPublisher struct{}
+
+func NewEventPublisher() EventPublisher {
+	return &outboxEventPublisher{}
+}
+
+func (p *outboxEventPublisher) Publish(
+	ctx context.Context,
+	outbox repository.OutboxRepo,
+	events ...domain.Event,
+) error {
+	for _, event := range events {
+		payload, err := json.Marshal(event)
+		if err != nil {
+			return err
+		}
+
+		err = outbox.InsertMessage(ctx, domain.OutboxMessage{
+			ID:        uuid.New().String(),
+			Kind:      domain.OutboxKind(event.EventType()),
+			Payload:   payload,
+			CreatedAt: time.Now(),
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,64 @@
+package service
+
+import (
+	"TrafficPolice/internal/domain"
+	"TrafficPolice/internal/repository/mocks"
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"testing"
+)
+
+func TestEventPublisherPublish(t *testing.T) {
+	errDB := errors.New("db error")
+	escalated := domain.CaseEscalated{CaseID: "case_id", FromSkill: 1, ToSkill: 2}
+	confirmed := domain.ExpertConfirmed{ExpertID: "expert_id"}
+	isEventMessage := func(event domain.Event) any {
+		return mock.MatchedBy(func(msg domain.OutboxMessage) bool {
+			payload, err := json.Marshal(event)
+			return err == nil && msg.ID != "" && msg.Kind == domain.OutboxKind(event.EventType()) &&
+				msg.Kind.IsEvent() && string(msg.Payload) == string(payload) && !msg.CreatedAt.IsZero()
+		})
+	}
+
+	testCases := []struct {
+		name        string
+		events      []domain.Event
+		insertErr   error
+		expectedErr error
+	}{
+		{
+			name:   "Every event is recorded in outbox",
+			events: []domain.Event{escalated, confirmed},
+		},
+		{
+			name:   "No events",
+			events: nil,
+		},
+		{
+			name:        "Event is not recorded. Expect error",
+			events:      []domain.Event{escalated, confirmed},
+			insertErr:   errDB,
+			expectedErr: errDB,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			outboxRepo := mocks.NewOutboxRepo(t)
+			for _, event := range tc.events {
+				outboxRepo.On("InsertMessage", mock.Anything, isEventMessage(event)).
+					Return(tc.insertErr).
+					Once()
+				if tc.insertErr != nil {
+					break
+				}
+			}
+
+			err := NewEventPublisher().Publish(context.Background(), outboxRepo, tc.events...)
+			assert.Equal(t, tc.expectedErr, err)
+		})
+	}
+}
@@ -1,137 +1,370 @@
 package service
 
 import (
+	"TrafficPolice/internal/config"
 	"TrafficPolice/internal/domain"
 	"TrafficPolice/internal/errs"
 	"TrafficPolice/internal/repository"
+	"context"
 	"errors"
 	"github.com/google/uuid"
+	"log"
 	"time"
 )
 
 //go:generate go run github.com/vektra/mockery/v2@v2.42.1 --name ExpertService
 type ExpertService interface {
-	GetExpertByUserID(userID string) (domain.Expert, error)
-	GetCase(userID string) (domain.Case, error)
-	SetCaseDecision(decision domain.Decision) (domain.CaseDecisionInfo, error)
-	GetCaseWithPersonInfo(caseID string) (domain.Case, error)
+	GetExpertByUserID(ctx context.Context, userID string) (domain.Expert, error)
+	GetCase(ctx context.Context, userID string) (domain.AssignedCase, error)
+	SetCaseDecision(ctx context.Context, decision domain.Decision) (domain.CaseDecisionInfo, error)
+	GetCaseWithPersonInfo(ctx context.Context, caseID string) (domain.Case, error)
+	RunLeaseReaper(done <-chan struct{})
 }
 
 type expertService struct {
-	expertRepo repository.ExpertRepo
-	caseRepo   repository.CaseRepo
-	consensus  int
+	expertRepo    repository.ExpertRepo
+	caseRepo      repository.CaseRepo
+	uow           repository.UnitOfWork
+	events        EventPublisher
+	consensus     int
+	leaseCfg      config.LeaseConfig
+	escalationCfg config.EscalationConfig
+	finesCfg      config.FinesConfig
 }
 
 func NewExpertService(
 	expertRepo repository.ExpertRepo,
 	caseRepo repository.CaseRepo,
+	uow repository.UnitOfWork,
+	events EventPublisher,
 	consensus int,
+	leaseCfg config.LeaseConfig,
+	escalationCfg config.EscalationConfig,
+	finesCfg config.FinesConfig,
 ) ExpertService {
 	return &expertService{
-		expertRepo: expertRepo,
-		caseRepo:   caseRepo,
-		consensus:  consensus,
+		expertRepo:    expertRepo,
+		caseRepo:      caseRepo,
+		uow:           uow,
+		events:        events,
+		consensus:     consensus,
+		leaseCfg:      leaseCfg,
+		escalationCfg: escalationCfg,
+		finesCfg:      finesCfg,
 	}
 }
 
-func (s *expertService) GetCase(userID string) (domain.Case, error) {
-	expert, err := s.expertRepo.GetExpertByUserID(userID)
+func (s *expertService) GetCase(ctx context.Context, userID string) (domain.AssignedCase, error) {
+	expert, err := s.expertRepo.GetExpertByUserID(ctx, userID)
 	if err != nil {
-		return domain.Case{}, err
+		return domain.AssignedCase{}, err
 	}
 
-	caseID, err := s.expertRepo.GetLastNotSolvedCaseID(expert.ID)
+	gotAt := time.Now()
+	lastCase, err := s.expertRepo.GetLastNotSolvedCase(ctx, expert.ID, gotAt)
 	if err == nil {
-		return s.caseRepo.GetCaseByID(caseID)
+		c, err := s.caseRepo.GetCaseByID(ctx, lastCase.CaseID)
+		if err != nil {
+			return domain.AssignedCase{}, err
+		}
+		return domain.AssignedCase{Case: c, LeaseExpiresAt: lastCase.LeaseExpiresAt}, nil
 	}
 	if !errors.Is(err, errs.ErrNoLastNotSolvedCase) {
-		return domain.Case{}, err
+		return domain.AssignedCase{}, err
 	}
 
-	notSolvedCase, err := s.expertRepo.GetNotSolvedCase(expert)
-	if err != nil {
-		return domain.Case{}, err
-	}
+	leaseExpiresAt := gotAt.Add(s.leaseCfg.Duration)
+	var notSolvedCase domain.Case
+	err = s.uow.Do(ctx, func(repos repository.TxRepos) error {
+		notSolvedCase, err = repos.Expert.ClaimNotSolvedCase(ctx, expert, domain.ExpertCase{
+			ExpertCaseID:   uuid.New().String(),
+			ExpertID:       expert.ID,
+			GotAt:          gotAt,
+			LeaseExpiresAt: leaseExpiresAt,
+		}, s.consensus)
+		if err != nil {
+			return err
+		}
 
-	err = s.expertRepo.InsertNotSolvedCase(domain.ExpertCase{
-		ExpertCaseID:  uuid.New().String(),
-		ExpertID:      expert.ID,
-		CaseID:        notSolvedCase.ID,
-		IsExpertSolve: false,
-		FineDecision:  false,
-		GotAt:         time.Now(),
+		return s.events.Publish(ctx, repos.Outbox, domain.CaseAssigned{
+			CaseID:         notSolvedCase.ID,
+			ExpertID:       expert.ID,
+			LeaseExpiresAt: leaseExpiresAt,
+		})
 	})
 	if err != nil {
-		return domain.Case{}, err
+		return domain.AssignedCase{}, err
 	}
 
-	return notSolvedCase, err
+	return domain.AssignedCase{Case: notSolvedCase, LeaseExpiresAt: leaseExpiresAt}, nil
 }
 
-func (s *expertService) GetExpertByUserID(userID string) (domain.Expert, error) {
-	return s.expertRepo.GetExpertByUserID(userID)
+func (s *expertService) GetExpertByUserID(ctx context.Context, userID string) (domain.Expert, error) {
+	return s.expertRepo.GetExpertByUserID(ctx, userID)
 }
 
-func (s *expertService) SetCaseDecision(decision domain.Decision) (domain.CaseDecisionInfo, error) {
+// SetCaseDecision saves decision of expert and evaluates consensus in one transaction:
+// solved case, rating of experts, fine, fine notification and events are committed together
+func (s *expertService) SetCaseDecision(
+	ctx context.Context,
+	decision domain.Decision,
+) (domain.CaseDecisionInfo, error) {
 	decision.SolvedAt = time.Now()
-	err := s.expertRepo.SetCaseDecision(decision)
+
+	var info domain.CaseDecisionInfo
+	err := s.uow.Do(ctx, func(repos repository.TxRepos) error {
+		info = domain.CaseDecisionInfo{CaseID: decision.CaseID}
+
+		// lock of case serializes decisions of case, so consensus is reached only once
+		c, err := repos.Case.LockCase(ctx, decision.CaseID)
+		if errors.Is(err, errs.ErrNoCase) {
+			return errs.ErrCaseNotAssigned
+		}
+		if err != nil {
+			return err
+		}
+
+		err = repos.Expert.SetCaseDecision(ctx, decision)
+		if err != nil {
+			return err
+		}
+		err = s.events.Publish(ctx, repos.Outbox, domain.CaseDecisionRecorded{
+			CaseID:       decision.CaseID,
+			ExpertID:     decision.Expert.ID,
+			ExpertSkill:  decision.Expert.CompetenceSkill,
+			FineDecision: decision.FineDecision,
+		})
+		if err != nil {
+			return err
+		}
+		if c.IsSolved {
+			return nil
+		}
+
+		caseDecisions, err := repos.Expert.GetCaseFineDecisions(ctx, decision.CaseID, decision.Expert.CompetenceSkill)
+		if err != nil {
+			return err
+		}
+
+		if caseDecisions.PositiveDecisions >= s.consensus {
+			return solveCase(ctx, repos, s.events, s.finesCfg, &info, true)
+		}
+		if caseDecisions.NegativeDecisions >= s.consensus {
+			return solveCase(ctx, repos, s.events, s.finesCfg, &info, false)
+		}
+
+		return s.escalateIfNoConsensus(ctx, repos, c, decision.Expert.CompetenceSkill, caseDecisions,
+			domain.NoConsensusEscalation)
+	})
 	if err != nil {
 		return domain.CaseDecisionInfo{}, err
 	}
 
-	caseDecisions, err := s.expertRepo.GetCaseFineDecisions(decision.CaseID, decision.Expert.CompetenceSkill)
+	return info, nil
+}
+
+// solveCase sets fine decision of case, updates rating of experts, who decided case,
+// and issues fine with fine notification, when fine should be sent. Event of solved case contains issued fine
+func solveCase(
+	ctx context.Context,
+	repos repository.TxRepos,
+	events EventPublisher,
+	finesCfg config.FinesConfig,
+	info *domain.CaseDecisionInfo,
+	fineDecision bool,
+) error {
+	solvedAt := time.Now()
+	err := repos.Case.SetCaseFineDecision(ctx, info.CaseID, fineDecision, solvedAt)
 	if err != nil {
-		return domain.CaseDecisionInfo{}, err
+		return err
+	}
+	info.IsSolved = true
+	info.ShouldSendFine = fineDecision
+
+	solvedDecisions, err := repos.Rating.GetSolvedCaseDecisions(ctx, *info)
+	if err != nil {
+		return err
+	}
+	err = repos.Rating.SetRating(ctx, solvedDecisions)
+	if err != nil {
+		return err
+	}
+
+	if !fineDecision {
+		return events.Publish(ctx, repos.Outbox, domain.CaseSolved{CaseID: info.CaseID})
+	}
+
+	fine, err := issueFine(ctx, repos, finesCfg, info.CaseID, solvedAt)
+	if err != nil {
+		return err
+	}
+	err = events.Publish(ctx, repos.Outbox, domain.CaseSolved{
+		CaseID:       info.CaseID,
+		FineDecision: true,
+		Fine: &domain.IssuedFine{
+			FineID:    fine.ID,
+			Reference: fine.Reference,
+			Amount:    fine.Amount,
+			DueAt:     fine.DueAt,
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	info.FineID = fine.ID
+	info.NotificationID, err = insertFineMessage(ctx, repos, domain.FineNotificationKind,
+		domain.FineNotificationPayload{CaseID: info.CaseID})
+	return err
+}
+
+// escalateIfNoConsensus raises required skill of case, when experts of skill, who have not solved
+// case yet and whose lease has not expired, can not reach consensus. Case of maximum skill is sent
+// to review of director instead. Case is escalated only from its required skill, so late decisions
+// of experts of previous skill and decisions of case in review do not escalate it again
+func (s *expertService) escalateIfNoConsensus(
+	ctx context.Context,
+	repos repository.TxRepos,
+	c domain.Case,
+	skill int,
+	caseDecisions domain.FineDecisions,
+	reason domain.EscalationReason,
+) error {
+	if c.InReview || int(c.RequiredSkill) != skill {
+		return nil
 	}
 
-	if caseDecisions.PositiveDecisions >= s.consensus {
-		err = s.caseRepo.SetCaseFineDecision(decision.CaseID, true, time.Now())
+	expertsCnt, err := repos.Expert.GetExpertsCountBySkill(ctx, skill)
+	if err != nil {
+		return err
+	}
+
+	totalDecisions := caseDecisions.PositiveDecisions + caseDecisions.NegativeDecisions
+
+	leftExperts := expertsCnt - totalDecisions - caseDecisions.ExpiredLeases
+	leftDecisions := s.consensus - max(caseDecisions.PositiveDecisions, caseDecisions.NegativeDecisions)
+	if leftExperts >= leftDecisions {
+		return nil
+	}
+
+	escalation := domain.CaseEscalation{
+		ID:          uuid.New().String(),
+		CaseID:      c.ID,
+		FromSkill:   skill,
+		Reason:      reason,
+		EscalatedAt: time.Now(),
+	}
+	if skill >= s.escalationCfg.MaxSkill {
+		err = repos.Case.RequestCaseReview(ctx, c.ID, escalation.EscalatedAt)
 		if err != nil {
-			return domain.CaseDecisionInfo{}, err
+			return err
 		}
-		return domain.CaseDecisionInfo{
-			CaseID:         decision.CaseID,
-			ShouldSendFine: true,
-			IsSolved:       true,
-		}, err
-	}
-	if caseDecisions.NegativeDecisions >= s.consensus {
-		err = s.caseRepo.SetCaseFineDecision(decision.CaseID, false, time.Now())
+		err = repos.Case.InsertEscalation(ctx, escalation)
 		if err != nil {
-			return domain.CaseDecisionInfo{}, err
+			return err
 		}
-		return domain.CaseDecisionInfo{
-			CaseID:         decision.CaseID,
-			ShouldSendFine: false,
-			IsSolved:       true,
-		}, nil
+		return s.events.Publish(ctx, repos.Outbox, domain.CaseReviewRequested{
+			CaseID: c.ID,
+			Skill:  skill,
+			Reason: reason,
+		})
 	}
 
-	expertsCnt, err := s.expertRepo.GetExpertsCountBySkill(decision.Expert.CompetenceSkill)
+	toSkill := skill + 1
+	escalation.ToSkill = &toSkill
+	err = repos.Case.UpdateCaseRequiredSkill(ctx, c.ID, toSkill)
 	if err != nil {
-		return domain.CaseDecisionInfo{}, err
+		return err
 	}
+	err = repos.Case.InsertEscalation(ctx, escalation)
+	if err != nil {
+		return err
+	}
+	return s.events.Publish(ctx, repos.Outbox, domain.CaseEscalated{
+		CaseID:    c.ID,
+		FromSkill: skill,
+		ToSkill:   toSkill,
+		Reason:    reason,
+	})
+}
 
-	totalDecisions := caseDecisions.PositiveDecisions + caseDecisions.NegativeDecisions
+func (s *expertService) GetCaseWithPersonInfo(ctx context.Context, caseID string) (domain.Case, error) {
+	return s.caseRepo.GetCaseWithPersonInfo(ctx, caseID)
+}
 
-	leftExperts := expertsCnt - totalDecisions
-	leftDecisions := s.consensus - max(caseDecisions.PositiveDecisions, caseDecisions.NegativeDecisions)
-	if leftExperts < leftDecisions {
-		return domain.CaseDecisionInfo{
-			CaseID:         decision.CaseID,
-			ShouldSendFine: false,
-			IsSolved:       false,
-		}, s.caseRepo.UpdateCaseRequiredSkill(decision.CaseID, decision.Expert.CompetenceSkill+1)
-	}
-
-	return domain.CaseDecisionInfo{
-		CaseID:         decision.CaseID,
-		ShouldSendFine: false,
-		IsSolved:       false,
-	}, nil
+func (s *expertService) RunLeaseReaper(done <-chan struct{}) {
+	log.Printf("RunLeaseReaper with interval: %s\n", s.leaseCfg.ReapInterval)
+	ticker := time.NewTicker(s.leaseCfg.ReapInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			err := s.expireLeases(context.Background(), time.Now())
+			if err != nil {
+				log.Println(err)
+			}
+		case <-done:
+			log.Println("Stop lease reaper")
+			return
+		}
+	}
+}
+
+// expireLeases marks expired leases in history of experts, so cases become available for other experts.
+// Expert, whose lease is expired, can not get the case again, so consensus may become unreachable.
+// Leases of case are expired in one transaction with escalation, so case, which failed, keeps its leases
+// and is checked again on next tick. Error of one case is only logged and does not stop other cases
+func (s *expertService) expireLeases(ctx context.Context, now time.Time) error {
+	caseIDs, err := s.expertRepo.GetExpiredLeaseCases(ctx, now)
+	if err != nil {
+		return err
+	}
+
+	expiredCnt := 0
+	for _, caseID := range caseIDs {
+		expired, err := s.expireCaseLeases(ctx, caseID, now)
+		if err != nil {
+			log.Printf("Error while expiring leases of case %s: %v\n", caseID, err)
+			continue
+		}
+		expiredCnt += expired
+	}
+	if expiredCnt > 0 {
+		log.Printf("Expired %d case leases\n", expiredCnt)
+	}
+
+	return nil
 }
 
-func (s *expertService) GetCaseWithPersonInfo(caseID string) (domain.Case, error) {
-	return s.caseRepo.GetCaseWithPersonInfo(caseID)
+// expireCaseLeases expires leases of case and escalates case, when consensus becomes unreachable
+func (s *expertService) expireCaseLeases(ctx context.Context, caseID string, now time.Time) (int, error) {
+	var expired int
+	err := s.uow.Do(ctx, func(repos repository.TxRepos) error {
+		c, err := repos.Case.LockCase(ctx, caseID)
+		if err != nil {
+			return err
+		}
+
+		expired, err = repos.Expert.ExpireCaseLeases(ctx, caseID, now)
+		if err != nil {
+			return err
+		}
+		// leases may be already expired by other instance of service
+		if expired == 0 || c.IsSolved {
+			return nil
+		}
+
+		skill := int(c.RequiredSkill)
+		caseDecisions, err := repos.Expert.GetCaseFineDecisions(ctx, c.ID, skill)
+		if err != nil {
+			return err
+		}
+		return s.escalateIfNoConsensus(ctx, repos, c, skill, caseDecisions, domain.LeaseExpiredEscalation)
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return expired, nil
 }
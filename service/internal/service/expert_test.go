@@ -1,10 +1,15 @@
 package service
 
 import (
+	"TrafficPolice/internal/config"
 	"TrafficPolice/internal/domain"
 	"TrafficPolice/internal/errs"
 	"TrafficPolice/internal/repository"
 	"TrafficPolice/internal/repository/mocks"
+	mocksservice "TrafficPolice/internal/service/mocks"
+	"TrafficPolice/pkg/uin"
+	"context"
+	"encoding/json"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -19,6 +24,8 @@ func TestGetCase(t *testing.T) {
 	username := "expert"
 	password := "password"
 	caseID := "case_id"
+	leaseCfg := config.LeaseConfig{Duration: time.Hour, ReapInterval: time.Minute}
+	lastLeaseExpiresAt := time.Now().Add(30 * time.Minute)
 
 	caseToSolve := domain.Case{
 		ID: caseID, ViolationValue: "130km/h", RequiredSkill: 1, Date: time.Now(),
@@ -42,46 +49,48 @@ func TestGetCase(t *testing.T) {
 		buildCaseRepo   func() repository.CaseRepo
 		userID          uuid.UUID
 		expectedCase    domain.Case
+		expectedLease   time.Time
+		expectedEvents  []any
 		expectedErr     error
 	}{
 		{
 			name: "Get last not solved case",
 			buildExpertRepo: func() repository.ExpertRepo {
 				mockRepo := mocks.NewExpertRepo(t)
-				mockRepo.On("GetExpertByUserID", userID.String()).
+				mockRepo.On("GetExpertByUserID", mock.Anything, userID.String()).
 					Return(expert, nil)
 
-				mockRepo.On("GetLastNotSolvedCaseID", expert.ID).
-					Return(caseID, nil)
+				mockRepo.On("GetLastNotSolvedCase", mock.Anything, expert.ID, mock.Anything).
+					Return(domain.ExpertCase{CaseID: caseID, LeaseExpiresAt: lastLeaseExpiresAt}, nil)
 
 				return mockRepo
 			},
 			buildCaseRepo: func() repository.CaseRepo {
 				mockRepo := mocks.NewCaseRepo(t)
-				mockRepo.On("GetCaseByID", caseID).
+				mockRepo.On("GetCaseByID", mock.Anything, caseID).
 					Return(caseToSolve, nil)
 
 				return mockRepo
 			},
-			userID:       userID,
-			expectedCase: caseToSolve,
-			expectedErr:  nil,
+			userID:        userID,
+			expectedCase:  caseToSolve,
+			expectedLease: lastLeaseExpiresAt,
+			expectedErr:   nil,
 		},
 		{
 			name: "Get case",
 			buildExpertRepo: func() repository.ExpertRepo {
 				mockRepo := mocks.NewExpertRepo(t)
-				mockRepo.On("GetExpertByUserID", userID.String()).
+				mockRepo.On("GetExpertByUserID", mock.Anything, userID.String()).
 					Return(expert, nil)
 
-				mockRepo.On("GetLastNotSolvedCaseID", expert.ID).
-					Return("", errs.ErrNoLastNotSolvedCase)
-
-				mockRepo.On("GetNotSolvedCase", expert).
-					Return(caseToSolve, nil)
+				mockRepo.On("GetLastNotSolvedCase", mock.Anything, expert.ID, mock.Anything).
+					Return(domain.ExpertCase{}, errs.ErrNoLastNotSolvedCase)
 
-				mockRepo.On("InsertNotSolvedCase", mock.Anything).
-					Return(nil).
+				mockRepo.On("ClaimNotSolvedCase", mock.Anything, expert, mock.MatchedBy(func(c domain.ExpertCase) bool {
+					return c.ExpertID == expert.ID && c.LeaseExpiresAt.Equal(c.GotAt.Add(leaseCfg.Duration))
+				}), defaultConsensus).
+					Return(caseToSolve, nil).
 					Times(1)
 
 				return mockRepo
@@ -90,15 +99,20 @@ func TestGetCase(t *testing.T) {
 				mockRepo := mocks.NewCaseRepo(t)
 				return mockRepo
 			},
-			userID:       userID,
-			expectedCase: caseToSolve,
-			expectedErr:  nil,
+			userID:        userID,
+			expectedCase:  caseToSolve,
+			expectedLease: time.Now().Add(leaseCfg.Duration),
+			expectedEvents: []any{mock.MatchedBy(func(e domain.CaseAssigned) bool {
+				return e.CaseID == caseID && e.ExpertID == expert.ID &&
+					e.LeaseExpiresAt.After(time.Now().Add(leaseCfg.Duration-time.Minute))
+			})},
+			expectedErr: nil,
 		},
 		{
 			name: "Expert with input userID not exists",
 			buildExpertRepo: func() repository.ExpertRepo {
 				mockRepo := mocks.NewExpertRepo(t)
-				mockRepo.On("GetExpertByUserID", userID.String()).
+				mockRepo.On("GetExpertByUserID", mock.Anything, userID.String()).
 					Return(domain.Expert{}, errs.ErrUserNotExists)
 
 				return mockRepo
@@ -118,15 +132,53 @@ func TestGetCase(t *testing.T) {
 			expertRepo := tc.buildExpertRepo()
 			caseRepo := tc.buildCaseRepo()
 
-			expertService := NewExpertService(expertRepo, caseRepo, defaultConsensus)
+			uow := newUnitOfWork(t, repository.TxRepos{Expert: expertRepo})
 
-			actualCase, err := expertService.GetCase(tc.userID.String())
+			expertService := NewExpertService(expertRepo, caseRepo, uow, newEventPublisher(t, tc.expectedEvents...),
+				defaultConsensus, leaseCfg, config.EscalationConfig{}, config.FinesConfig{})
+
+			actualCase, err := expertService.GetCase(context.Background(), tc.userID.String())
 			assert.Equal(t, tc.expectedErr, err)
-			assert.Equal(t, tc.expectedCase, actualCase)
+			assert.Equal(t, tc.expectedCase, actualCase.Case)
+			assert.WithinDuration(t, tc.expectedLease, actualCase.LeaseExpiresAt, time.Second)
 		})
 	}
 }
 
+// newUnitOfWork returns unit of work, which runs every fn with repos without transaction
+func newUnitOfWork(t *testing.T, repos repository.TxRepos) repository.UnitOfWork {
+	uow := mocks.NewUnitOfWork(t)
+	uow.On("Do", mock.Anything, mock.Anything).
+		Return(func(ctx context.Context, fn func(repository.TxRepos) error) error {
+			return fn(repos)
+		}).
+		Maybe()
+
+	return uow
+}
+
+// newEventPublisher expects publish of every event once, events can be matchers of mock
+func newEventPublisher(t *testing.T, events ...any) EventPublisher {
+	mockPublisher := mocksservice.NewEventPublisher(t)
+	for _, event := range events {
+		mockPublisher.On("Publish", mock.Anything, mock.Anything, event).
+			Return(nil).
+			Once()
+	}
+
+	return mockPublisher
+}
+
+// escalationLog matches record of escalation log, zero toSkill matches escalation to review of director
+func escalationLog(caseID string, fromSkill int, toSkill int, reason domain.EscalationReason) any {
+	return mock.MatchedBy(func(e domain.CaseEscalation) bool {
+		toReview := toSkill == 0 && e.ToSkill == nil
+		raised := toSkill != 0 && e.ToSkill != nil && *e.ToSkill == toSkill
+		return e.ID != "" && e.CaseID == caseID && e.FromSkill == fromSkill && e.Reason == reason &&
+			!e.EscalatedAt.IsZero() && (toReview || raised)
+	})
+}
+
 func TestSetCaseDecision(t *testing.T) {
 	userID := uuid.New()
 	expertID := uuid.New()
@@ -157,122 +209,695 @@ func TestSetCaseDecision(t *testing.T) {
 		Expert:       expert,
 		FineDecision: false,
 	}
+	openCase := domain.Case{ID: caseID.String(), RequiredSkill: 1}
+	finesCfg := config.FinesConfig{ReferencePrefix: "18810", DueIn: time.Hour, DiscountPercent: 50,
+		DiscountPeriod: time.Minute}
+	solvedDecisions := []domain.ExpertCaseDecision{{ExpertID: expert.ID, IsRight: true}}
+	isFineNotification := func(msg domain.OutboxMessage) bool {
+		var payload domain.FineNotificationPayload
+		err := json.Unmarshal(msg.Payload, &payload)
+		return err == nil && msg.Kind == domain.FineNotificationKind && payload.CaseID == caseID.String()
+	}
+	isIssuedFine := func(fine domain.Fine) bool {
+		return fine.CaseID == caseID.String() && fine.Amount == 5000 && fine.DiscountAmount == 2500 &&
+			fine.Status == domain.FineIssued && uin.IsValid(fine.Reference)
+	}
+	positiveRecorded := domain.CaseDecisionRecorded{
+		CaseID: caseID.String(), ExpertID: expert.ID, ExpertSkill: 1, FineDecision: true,
+	}
+	negativeRecorded := domain.CaseDecisionRecorded{CaseID: caseID.String(), ExpertID: expert.ID, ExpertSkill: 1}
+	solvedWithFine := mock.MatchedBy(func(e domain.CaseSolved) bool {
+		return e.CaseID == caseID.String() && e.FineDecision && e.Fine != nil && e.Fine.Amount == 5000 &&
+			uin.IsValid(e.Fine.Reference)
+	})
+	escalated := domain.CaseEscalated{
+		CaseID: caseID.String(), FromSkill: 1, ToSkill: 2, Reason: domain.NoConsensusEscalation,
+	}
 
 	testCases := []struct {
-		name            string
-		buildExpertRepo func() repository.ExpertRepo
-		buildCaseRepo   func() repository.CaseRepo
-		consensus       int
-		decision        domain.Decision
-		expectedInfo    domain.CaseDecisionInfo
-		expectedErr     error
+		name               string
+		buildExpertRepo    func() repository.ExpertRepo
+		buildCaseRepo      func() repository.CaseRepo
+		buildRatingRepo    func() repository.RatingRepo
+		buildOutboxRepo    func() repository.OutboxRepo
+		buildFineRepo      func() repository.FineRepo // set only for cases, which issue fine
+		consensus          int
+		maxSkill           int
+		decision           domain.Decision
+		expectedInfo       domain.CaseDecisionInfo
+		expectNotification bool
+		expectedEvents     []any
+		expectedErr        error
 	}{
 		{
 			name: "Set decision. Not solved. Without upgrade required skill",
 			buildExpertRepo: func() repository.ExpertRepo {
 				mockRepo := mocks.NewExpertRepo(t)
-				mockRepo.On("SetCaseDecision", mock.Anything).
+				mockRepo.On("SetCaseDecision", mock.Anything, mock.Anything).
 					Return(nil)
-				mockRepo.On("GetCaseFineDecisions", positiveDecision.CaseID, mock.AnythingOfType("int")).
+				mockRepo.On("GetCaseFineDecisions", mock.Anything, positiveDecision.CaseID, mock.AnythingOfType("int")).
 					Return(domain.FineDecisions{PositiveDecisions: 1, NegativeDecisions: 0}, nil)
-				mockRepo.On("GetExpertsCountBySkill", positiveDecision.Expert.CompetenceSkill).
+				mockRepo.On("GetExpertsCountBySkill", mock.Anything, positiveDecision.Expert.CompetenceSkill).
 					Return(4, nil)
 
 				return mockRepo
 			},
 			buildCaseRepo: func() repository.CaseRepo {
 				mockRepo := mocks.NewCaseRepo(t)
+				mockRepo.On("LockCase", mock.Anything, caseID.String()).
+					Return(openCase, nil)
 
 				return mockRepo
 			},
-			consensus:    2,
-			decision:     positiveDecision,
-			expectedInfo: domain.CaseDecisionInfo{CaseID: caseID.String(), ShouldSendFine: false, IsSolved: false},
-			expectedErr:  nil,
+			buildRatingRepo: func() repository.RatingRepo {
+				return mocks.NewRatingRepo(t)
+			},
+			buildOutboxRepo: func() repository.OutboxRepo {
+				return mocks.NewOutboxRepo(t)
+			},
+			consensus:      2,
+			decision:       positiveDecision,
+			expectedInfo:   domain.CaseDecisionInfo{CaseID: caseID.String(), ShouldSendFine: false, IsSolved: false},
+			expectedEvents: []any{positiveRecorded},
+			expectedErr:    nil,
 		},
 		{
 			name: "Set decision. Case is solved. Fine should be sent",
 			buildExpertRepo: func() repository.ExpertRepo {
 				mockRepo := mocks.NewExpertRepo(t)
-				mockRepo.On("SetCaseDecision", mock.Anything).
+				mockRepo.On("SetCaseDecision", mock.Anything, mock.Anything).
 					Return(nil)
-				mockRepo.On("GetCaseFineDecisions", positiveDecision.CaseID, mock.AnythingOfType("int")).
+				mockRepo.On("GetCaseFineDecisions", mock.Anything, positiveDecision.CaseID, mock.AnythingOfType("int")).
 					Return(domain.FineDecisions{PositiveDecisions: 2, NegativeDecisions: 0}, nil)
 
 				return mockRepo
 			},
 			buildCaseRepo: func() repository.CaseRepo {
 				mockRepo := mocks.NewCaseRepo(t)
-				mockRepo.On("SetCaseFineDecision", positiveDecision.CaseID, true, mock.Anything).
+				mockRepo.On("LockCase", mock.Anything, caseID.String()).
+					Return(openCase, nil)
+				mockRepo.On("SetCaseFineDecision", mock.Anything, positiveDecision.CaseID, true, mock.Anything).
 					Return(nil)
 
 				return mockRepo
 			},
-			consensus:    2,
-			decision:     positiveDecision,
-			expectedInfo: domain.CaseDecisionInfo{CaseID: caseID.String(), ShouldSendFine: true, IsSolved: true},
-			expectedErr:  nil,
+			buildRatingRepo: func() repository.RatingRepo {
+				mockRepo := mocks.NewRatingRepo(t)
+				mockRepo.On("GetSolvedCaseDecisions", mock.Anything, mock.Anything).
+					Return(solvedDecisions, nil)
+				mockRepo.On("SetRating", mock.Anything, solvedDecisions).
+					Return(nil)
+
+				return mockRepo
+			},
+			buildOutboxRepo: func() repository.OutboxRepo {
+				mockRepo := mocks.NewOutboxRepo(t)
+				mockRepo.On("InsertMessage", mock.Anything, mock.MatchedBy(isFineNotification)).
+					Return(nil).
+					Times(1)
+
+				return mockRepo
+			},
+			buildFineRepo: func() repository.FineRepo {
+				mockRepo := mocks.NewFineRepo(t)
+				mockRepo.On("GetCaseFineAmount", mock.Anything, caseID.String()).
+					Return(5000, nil)
+				mockRepo.On("InsertFine", mock.Anything, mock.MatchedBy(isIssuedFine)).
+					Return(nil).
+					Times(1)
+
+				return mockRepo
+			},
+			consensus:          2,
+			decision:           positiveDecision,
+			expectedInfo:       domain.CaseDecisionInfo{CaseID: caseID.String(), ShouldSendFine: true, IsSolved: true},
+			expectNotification: true,
+			expectedEvents:     []any{positiveRecorded, solvedWithFine},
+			expectedErr:        nil,
 		},
 		{
 			name: "Set decision. Case is solved. Fine should not be sent",
 			buildExpertRepo: func() repository.ExpertRepo {
 				mockRepo := mocks.NewExpertRepo(t)
-				mockRepo.On("SetCaseDecision", mock.Anything).
+				mockRepo.On("SetCaseDecision", mock.Anything, mock.Anything).
 					Return(nil)
-				mockRepo.On("GetCaseFineDecisions", positiveDecision.CaseID, mock.AnythingOfType("int")).
+				mockRepo.On("GetCaseFineDecisions", mock.Anything, positiveDecision.CaseID, mock.AnythingOfType("int")).
 					Return(domain.FineDecisions{PositiveDecisions: 0, NegativeDecisions: 2}, nil)
 
 				return mockRepo
 			},
 			buildCaseRepo: func() repository.CaseRepo {
 				mockRepo := mocks.NewCaseRepo(t)
-				mockRepo.On("SetCaseFineDecision", negativeDecision.CaseID, false, mock.Anything).
+				mockRepo.On("LockCase", mock.Anything, caseID.String()).
+					Return(openCase, nil)
+				mockRepo.On("SetCaseFineDecision", mock.Anything, negativeDecision.CaseID, false, mock.Anything).
 					Return(nil)
 
 				return mockRepo
 			},
-			consensus:    2,
-			decision:     negativeDecision,
-			expectedInfo: domain.CaseDecisionInfo{CaseID: caseID.String(), ShouldSendFine: false, IsSolved: true},
-			expectedErr:  nil,
+			buildRatingRepo: func() repository.RatingRepo {
+				mockRepo := mocks.NewRatingRepo(t)
+				mockRepo.On("GetSolvedCaseDecisions", mock.Anything, mock.Anything).
+					Return(solvedDecisions, nil)
+				mockRepo.On("SetRating", mock.Anything, solvedDecisions).
+					Return(nil)
+
+				return mockRepo
+			},
+			buildOutboxRepo: func() repository.OutboxRepo {
+				return mocks.NewOutboxRepo(t)
+			},
+			consensus:      2,
+			decision:       negativeDecision,
+			expectedInfo:   domain.CaseDecisionInfo{CaseID: caseID.String(), ShouldSendFine: false, IsSolved: true},
+			expectedEvents: []any{negativeRecorded, domain.CaseSolved{CaseID: caseID.String()}},
+			expectedErr:    nil,
 		},
 		{
 			name: "Set decision. consensus can not be reached. Upgrade required level",
 			buildExpertRepo: func() repository.ExpertRepo {
 				mockRepo := mocks.NewExpertRepo(t)
-				mockRepo.On("SetCaseDecision", mock.Anything).
+				mockRepo.On("SetCaseDecision", mock.Anything, mock.Anything).
 					Return(nil)
-				mockRepo.On("GetCaseFineDecisions", positiveDecision.CaseID, mock.AnythingOfType("int")).
+				mockRepo.On("GetCaseFineDecisions", mock.Anything, positiveDecision.CaseID, mock.AnythingOfType("int")).
 					Return(domain.FineDecisions{PositiveDecisions: 1, NegativeDecisions: 1}, nil)
-				mockRepo.On("GetExpertsCountBySkill", positiveDecision.Expert.CompetenceSkill).
+				mockRepo.On("GetExpertsCountBySkill", mock.Anything, positiveDecision.Expert.CompetenceSkill).
 					Return(3, nil)
 
 				return mockRepo
 			},
 			buildCaseRepo: func() repository.CaseRepo {
 				mockRepo := mocks.NewCaseRepo(t)
-				mockRepo.On("UpdateCaseRequiredSkill", positiveDecision.CaseID,
+				mockRepo.On("LockCase", mock.Anything, caseID.String()).
+					Return(openCase, nil)
+				mockRepo.On("UpdateCaseRequiredSkill", mock.Anything, positiveDecision.CaseID,
 					positiveDecision.Expert.CompetenceSkill+1).
 					Return(nil)
+				mockRepo.On("InsertEscalation", mock.Anything,
+					escalationLog(caseID.String(), 1, 2, domain.NoConsensusEscalation)).
+					Return(nil)
 				return mockRepo
 			},
+			buildRatingRepo: func() repository.RatingRepo {
+				return mocks.NewRatingRepo(t)
+			},
+			buildOutboxRepo: func() repository.OutboxRepo {
+				return mocks.NewOutboxRepo(t)
+			},
+			consensus:      4,
+			maxSkill:       2,
+			decision:       positiveDecision,
+			expectedInfo:   domain.CaseDecisionInfo{CaseID: caseID.String(), ShouldSendFine: false, IsSolved: false},
+			expectedEvents: []any{positiveRecorded, escalated},
+			expectedErr:    nil,
+		},
+		{
+			name: "Set decision. Expired leases make consensus unreachable. Upgrade required level",
+			buildExpertRepo: func() repository.ExpertRepo {
+				mockRepo := mocks.NewExpertRepo(t)
+				mockRepo.On("SetCaseDecision", mock.Anything, mock.Anything).
+					Return(nil)
+				mockRepo.On("GetCaseFineDecisions", mock.Anything, positiveDecision.CaseID, mock.AnythingOfType("int")).
+					Return(domain.FineDecisions{PositiveDecisions: 1, NegativeDecisions: 0, ExpiredLeases: 2}, nil)
+				mockRepo.On("GetExpertsCountBySkill", mock.Anything, positiveDecision.Expert.CompetenceSkill).
+					Return(3, nil)
+
+				return mockRepo
+			},
+			buildCaseRepo: func() repository.CaseRepo {
+				mockRepo := mocks.NewCaseRepo(t)
+				mockRepo.On("LockCase", mock.Anything, caseID.String()).
+					Return(openCase, nil)
+				mockRepo.On("UpdateCaseRequiredSkill", mock.Anything, positiveDecision.CaseID,
+					positiveDecision.Expert.CompetenceSkill+1).
+					Return(nil)
+				mockRepo.On("InsertEscalation", mock.Anything,
+					escalationLog(caseID.String(), 1, 2, domain.NoConsensusEscalation)).
+					Return(nil)
+				return mockRepo
+			},
+			buildRatingRepo: func() repository.RatingRepo {
+				return mocks.NewRatingRepo(t)
+			},
+			buildOutboxRepo: func() repository.OutboxRepo {
+				return mocks.NewOutboxRepo(t)
+			},
+			consensus:      2,
+			maxSkill:       2,
+			decision:       positiveDecision,
+			expectedInfo:   domain.CaseDecisionInfo{CaseID: caseID.String(), ShouldSendFine: false, IsSolved: false},
+			expectedEvents: []any{positiveRecorded, escalated},
+			expectedErr:    nil,
+		},
+		{
+			name: "Set decision. Consensus can not be reached with maximum skill. Case is sent to review",
+			buildExpertRepo: func() repository.ExpertRepo {
+				mockRepo := mocks.NewExpertRepo(t)
+				mockRepo.On("SetCaseDecision", mock.Anything, mock.Anything).
+					Return(nil)
+				mockRepo.On("GetCaseFineDecisions", mock.Anything, positiveDecision.CaseID, mock.AnythingOfType("int")).
+					Return(domain.FineDecisions{PositiveDecisions: 1, NegativeDecisions: 1}, nil)
+				mockRepo.On("GetExpertsCountBySkill", mock.Anything, positiveDecision.Expert.CompetenceSkill).
+					Return(3, nil)
+
+				return mockRepo
+			},
+			buildCaseRepo: func() repository.CaseRepo {
+				mockRepo := mocks.NewCaseRepo(t)
+				mockRepo.On("LockCase", mock.Anything, caseID.String()).
+					Return(openCase, nil)
+				mockRepo.On("RequestCaseReview", mock.Anything, positiveDecision.CaseID, mock.AnythingOfType("time.Time")).
+					Return(nil)
+				mockRepo.On("InsertEscalation", mock.Anything,
+					escalationLog(caseID.String(), 1, 0, domain.NoConsensusEscalation)).
+					Return(nil)
+				return mockRepo
+			},
+			buildRatingRepo: func() repository.RatingRepo {
+				return mocks.NewRatingRepo(t)
+			},
+			buildOutboxRepo: func() repository.OutboxRepo {
+				return mocks.NewOutboxRepo(t)
+			},
 			consensus:    4,
+			maxSkill:     1,
 			decision:     positiveDecision,
 			expectedInfo: domain.CaseDecisionInfo{CaseID: caseID.String(), ShouldSendFine: false, IsSolved: false},
-			expectedErr:  nil,
+			expectedEvents: []any{positiveRecorded, domain.CaseReviewRequested{
+				CaseID: caseID.String(), Skill: 1, Reason: domain.NoConsensusEscalation,
+			}},
+			expectedErr: nil,
+		},
+		{
+			name: "Set decision. Case is in review. Case is not escalated again",
+			buildExpertRepo: func() repository.ExpertRepo {
+				mockRepo := mocks.NewExpertRepo(t)
+				mockRepo.On("SetCaseDecision", mock.Anything, mock.Anything).
+					Return(nil)
+				mockRepo.On("GetCaseFineDecisions", mock.Anything, positiveDecision.CaseID, mock.AnythingOfType("int")).
+					Return(domain.FineDecisions{PositiveDecisions: 1, NegativeDecisions: 1}, nil)
+
+				return mockRepo
+			},
+			buildCaseRepo: func() repository.CaseRepo {
+				mockRepo := mocks.NewCaseRepo(t)
+				mockRepo.On("LockCase", mock.Anything, caseID.String()).
+					Return(domain.Case{ID: caseID.String(), RequiredSkill: 1, InReview: true}, nil)
+				return mockRepo
+			},
+			buildRatingRepo: func() repository.RatingRepo {
+				return mocks.NewRatingRepo(t)
+			},
+			buildOutboxRepo: func() repository.OutboxRepo {
+				return mocks.NewOutboxRepo(t)
+			},
+			consensus:      4,
+			maxSkill:       1,
+			decision:       positiveDecision,
+			expectedInfo:   domain.CaseDecisionInfo{CaseID: caseID.String(), ShouldSendFine: false, IsSolved: false},
+			expectedEvents: []any{positiveRecorded},
+			expectedErr:    nil,
+		},
+		{
+			name: "Set decision. Case is not assigned or lease expired",
+			buildExpertRepo: func() repository.ExpertRepo {
+				mockRepo := mocks.NewExpertRepo(t)
+				mockRepo.On("SetCaseDecision", mock.Anything, mock.Anything).
+					Return(errs.ErrCaseNotAssigned)
+
+				return mockRepo
+			},
+			buildCaseRepo: func() repository.CaseRepo {
+				mockRepo := mocks.NewCaseRepo(t)
+				mockRepo.On("LockCase", mock.Anything, caseID.String()).
+					Return(openCase, nil)
+				return mockRepo
+			},
+			buildRatingRepo: func() repository.RatingRepo {
+				return mocks.NewRatingRepo(t)
+			},
+			buildOutboxRepo: func() repository.OutboxRepo {
+				return mocks.NewOutboxRepo(t)
+			},
+			consensus:    2,
+			decision:     positiveDecision,
+			expectedInfo: domain.CaseDecisionInfo{},
+			expectedErr:  errs.ErrCaseNotAssigned,
+		},
+		{
+			name: "Set decision. Case not exists",
+			buildExpertRepo: func() repository.ExpertRepo {
+				return mocks.NewExpertRepo(t)
+			},
+			buildCaseRepo: func() repository.CaseRepo {
+				mockRepo := mocks.NewCaseRepo(t)
+				mockRepo.On("LockCase", mock.Anything, caseID.String()).
+					Return(domain.Case{}, errs.ErrNoCase)
+				return mockRepo
+			},
+			buildRatingRepo: func() repository.RatingRepo {
+				return mocks.NewRatingRepo(t)
+			},
+			buildOutboxRepo: func() repository.OutboxRepo {
+				return mocks.NewOutboxRepo(t)
+			},
+			consensus:    2,
+			decision:     positiveDecision,
+			expectedInfo: domain.CaseDecisionInfo{},
+			expectedErr:  errs.ErrCaseNotAssigned,
+		},
+		{
+			name: "Set decision. Case is already solved. Decision is only saved",
+			buildExpertRepo: func() repository.ExpertRepo {
+				mockRepo := mocks.NewExpertRepo(t)
+				mockRepo.On("SetCaseDecision", mock.Anything, mock.Anything).
+					Return(nil)
+
+				return mockRepo
+			},
+			buildCaseRepo: func() repository.CaseRepo {
+				mockRepo := mocks.NewCaseRepo(t)
+				mockRepo.On("LockCase", mock.Anything, caseID.String()).
+					Return(domain.Case{ID: caseID.String(), RequiredSkill: 1, IsSolved: true, FineDecision: true}, nil)
+				return mockRepo
+			},
+			buildRatingRepo: func() repository.RatingRepo {
+				return mocks.NewRatingRepo(t)
+			},
+			buildOutboxRepo: func() repository.OutboxRepo {
+				return mocks.NewOutboxRepo(t)
+			},
+			consensus:      2,
+			decision:       positiveDecision,
+			expectedInfo:   domain.CaseDecisionInfo{CaseID: caseID.String(), ShouldSendFine: false, IsSolved: false},
+			expectedEvents: []any{positiveRecorded},
+			expectedErr:    nil,
+		},
+		{
+			name: "Set decision. Case is solved. Rating error fails decision",
+			buildExpertRepo: func() repository.ExpertRepo {
+				mockRepo := mocks.NewExpertRepo(t)
+				mockRepo.On("SetCaseDecision", mock.Anything, mock.Anything).
+					Return(nil)
+				mockRepo.On("GetCaseFineDecisions", mock.Anything, positiveDecision.CaseID, mock.AnythingOfType("int")).
+					Return(domain.FineDecisions{PositiveDecisions: 2, NegativeDecisions: 0}, nil)
+
+				return mockRepo
+			},
+			buildCaseRepo: func() repository.CaseRepo {
+				mockRepo := mocks.NewCaseRepo(t)
+				mockRepo.On("LockCase", mock.Anything, caseID.String()).
+					Return(openCase, nil)
+				mockRepo.On("SetCaseFineDecision", mock.Anything, positiveDecision.CaseID, true, mock.Anything).
+					Return(nil)
+
+				return mockRepo
+			},
+			buildRatingRepo: func() repository.RatingRepo {
+				mockRepo := mocks.NewRatingRepo(t)
+				mockRepo.On("GetSolvedCaseDecisions", mock.Anything, mock.Anything).
+					Return(solvedDecisions, nil)
+				mockRepo.On("SetRating", mock.Anything, solvedDecisions).
+					Return(errs.ErrNoRows)
+
+				return mockRepo
+			},
+			buildOutboxRepo: func() repository.OutboxRepo {
+				return mocks.NewOutboxRepo(t)
+			},
+			consensus:      2,
+			decision:       positiveDecision,
+			expectedInfo:   domain.CaseDecisionInfo{},
+			expectedEvents: []any{positiveRecorded},
+			expectedErr:    errs.ErrNoRows,
+		},
+		{
+			name: "Set decision. Case is solved. Fine error fails decision",
+			buildExpertRepo: func() repository.ExpertRepo {
+				mockRepo := mocks.NewExpertRepo(t)
+				mockRepo.On("SetCaseDecision", mock.Anything, mock.Anything).
+					Return(nil)
+				mockRepo.On("GetCaseFineDecisions", mock.Anything, positiveDecision.CaseID, mock.AnythingOfType("int")).
+					Return(domain.FineDecisions{PositiveDecisions: 2, NegativeDecisions: 0}, nil)
+
+				return mockRepo
+			},
+			buildCaseRepo: func() repository.CaseRepo {
+				mockRepo := mocks.NewCaseRepo(t)
+				mockRepo.On("LockCase", mock.Anything, caseID.String()).
+					Return(openCase, nil)
+				mockRepo.On("SetCaseFineDecision", mock.Anything, positiveDecision.CaseID, true, mock.Anything).
+					Return(nil)
+
+				return mockRepo
+			},
+			buildRatingRepo: func() repository.RatingRepo {
+				mockRepo := mocks.NewRatingRepo(t)
+				mockRepo.On("GetSolvedCaseDecisions", mock.Anything, mock.Anything).
+					Return(solvedDecisions, nil)
+				mockRepo.On("SetRating", mock.Anything, solvedDecisions).
+					Return(nil)
+
+				return mockRepo
+			},
+			buildOutboxRepo: func() repository.OutboxRepo {
+				return mocks.NewOutboxRepo(t)
+			},
+			buildFineRepo: func() repository.FineRepo {
+				mockRepo := mocks.NewFineRepo(t)
+				mockRepo.On("GetCaseFineAmount", mock.Anything, caseID.String()).
+					Return(0, errs.ErrNoCase)
+
+				return mockRepo
+			},
+			consensus:      2,
+			decision:       positiveDecision,
+			expectedInfo:   domain.CaseDecisionInfo{},
+			expectedEvents: []any{positiveRecorded},
+			expectedErr:    errs.ErrNoCase,
 		},
 	}
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			expertRepo := tc.buildExpertRepo()
-			caseRepo := tc.buildCaseRepo()
+			var fineRepo repository.FineRepo = mocks.NewFineRepo(t)
+			if tc.buildFineRepo != nil {
+				fineRepo = tc.buildFineRepo()
+			}
+			uow := newUnitOfWork(t, repository.TxRepos{
+				Expert: tc.buildExpertRepo(),
+				Case:   tc.buildCaseRepo(),
+				Rating: tc.buildRatingRepo(),
+				Outbox: tc.buildOutboxRepo(),
+				Fine:   fineRepo,
+			})
 
-			expertService := NewExpertService(expertRepo, caseRepo, tc.consensus)
+			expertService := NewExpertService(mocks.NewExpertRepo(t), mocks.NewCaseRepo(t), uow,
+				newEventPublisher(t, tc.expectedEvents...), tc.consensus, config.LeaseConfig{},
+				config.EscalationConfig{MaxSkill: tc.maxSkill}, finesCfg)
 
-			actualInfo, err := expertService.SetCaseDecision(tc.decision)
+			actualInfo, err := expertService.SetCaseDecision(context.Background(), tc.decision)
 			assert.Equal(t, tc.expectedErr, err)
+			assert.Equal(t, tc.expectNotification, actualInfo.NotificationID != "")
+			assert.Equal(t, tc.expectNotification, actualInfo.FineID != "")
+			actualInfo.NotificationID = ""
+			actualInfo.FineID = ""
 			assert.Equal(t, tc.expectedInfo, actualInfo)
 		})
 	}
 }
+
+func TestExpireLeases(t *testing.T) {
+	now := time.Now()
+	solvedCaseID := "solved_case_id"
+	openCaseID := "open_case_id"
+	escalatedCaseID := "escalated_case_id"
+	reviewCaseID := "review_case_id"
+	failedCaseID := "failed_case_id"
+
+	testCases := []struct {
+		name            string
+		buildExpertRepo func() repository.ExpertRepo
+		buildCaseRepo   func() repository.CaseRepo
+		expectedEvents  []any
+		expectedErr     error
+	}{
+		{
+			name: "No expired leases",
+			buildExpertRepo: func() repository.ExpertRepo {
+				mockRepo := mocks.NewExpertRepo(t)
+				mockRepo.On("GetExpiredLeaseCases", mock.Anything, now).
+					Return([]string{}, nil)
+
+				return mockRepo
+			},
+			buildCaseRepo: func() repository.CaseRepo {
+				return mocks.NewCaseRepo(t)
+			},
+			expectedErr: nil,
+		},
+		{
+			name: "Expired leases. Only case without reachable consensus is escalated",
+			buildExpertRepo: func() repository.ExpertRepo {
+				mockRepo := mocks.NewExpertRepo(t)
+				mockRepo.On("GetExpiredLeaseCases", mock.Anything, now).
+					Return([]string{solvedCaseID, openCaseID, escalatedCaseID}, nil)
+				mockRepo.On("ExpireCaseLeases", mock.Anything, solvedCaseID, now).
+					Return(1, nil)
+				mockRepo.On("ExpireCaseLeases", mock.Anything, openCaseID, now).
+					Return(1, nil)
+				mockRepo.On("ExpireCaseLeases", mock.Anything, escalatedCaseID, now).
+					Return(2, nil)
+				mockRepo.On("GetCaseFineDecisions", mock.Anything, openCaseID, 1).
+					Return(domain.FineDecisions{PositiveDecisions: 1, ExpiredLeases: 1}, nil)
+				mockRepo.On("GetCaseFineDecisions", mock.Anything, escalatedCaseID, 2).
+					Return(domain.FineDecisions{PositiveDecisions: 1, ExpiredLeases: 2}, nil)
+				mockRepo.On("GetExpertsCountBySkill", mock.Anything, 1).
+					Return(5, nil)
+				mockRepo.On("GetExpertsCountBySkill", mock.Anything, 2).
+					Return(3, nil)
+
+				return mockRepo
+			},
+			buildCaseRepo: func() repository.CaseRepo {
+				mockRepo := mocks.NewCaseRepo(t)
+				mockRepo.On("LockCase", mock.Anything, solvedCaseID).
+					Return(domain.Case{ID: solvedCaseID, RequiredSkill: 1, IsSolved: true}, nil)
+				mockRepo.On("LockCase", mock.Anything, openCaseID).
+					Return(domain.Case{ID: openCaseID, RequiredSkill: 1}, nil)
+				mockRepo.On("LockCase", mock.Anything, escalatedCaseID).
+					Return(domain.Case{ID: escalatedCaseID, RequiredSkill: 2}, nil)
+				mockRepo.On("UpdateCaseRequiredSkill", mock.Anything, escalatedCaseID, 3).
+					Return(nil).
+					Times(1)
+				mockRepo.On("InsertEscalation", mock.Anything,
+					escalationLog(escalatedCaseID, 2, 3, domain.LeaseExpiredEscalation)).
+					Return(nil).
+					Times(1)
+
+				return mockRepo
+			},
+			expectedEvents: []any{domain.CaseEscalated{
+				CaseID: escalatedCaseID, FromSkill: 2, ToSkill: 3, Reason: domain.LeaseExpiredEscalation,
+			}},
+			expectedErr: nil,
+		},
+		{
+			name: "Expired lease of case of maximum skill. Case is sent to review",
+			buildExpertRepo: func() repository.ExpertRepo {
+				mockRepo := mocks.NewExpertRepo(t)
+				mockRepo.On("GetExpiredLeaseCases", mock.Anything, now).
+					Return([]string{reviewCaseID}, nil)
+				mockRepo.On("ExpireCaseLeases", mock.Anything, reviewCaseID, now).
+					Return(1, nil)
+				mockRepo.On("GetCaseFineDecisions", mock.Anything, reviewCaseID, 5).
+					Return(domain.FineDecisions{NegativeDecisions: 1, ExpiredLeases: 1}, nil)
+				mockRepo.On("GetExpertsCountBySkill", mock.Anything, 5).
+					Return(2, nil)
+
+				return mockRepo
+			},
+			buildCaseRepo: func() repository.CaseRepo {
+				mockRepo := mocks.NewCaseRepo(t)
+				mockRepo.On("LockCase", mock.Anything, reviewCaseID).
+					Return(domain.Case{ID: reviewCaseID, RequiredSkill: 5}, nil)
+				mockRepo.On("RequestCaseReview", mock.Anything, reviewCaseID, mock.AnythingOfType("time.Time")).
+					Return(nil)
+				mockRepo.On("InsertEscalation", mock.Anything,
+					escalationLog(reviewCaseID, 5, 0, domain.LeaseExpiredEscalation)).
+					Return(nil)
+
+				return mockRepo
+			},
+			expectedEvents: []any{domain.CaseReviewRequested{
+				CaseID: reviewCaseID, Skill: 5, Reason: domain.LeaseExpiredEscalation,
+			}},
+			expectedErr: nil,
+		},
+		{
+			name: "Leases already expired by other instance. Case is not checked",
+			buildExpertRepo: func() repository.ExpertRepo {
+				mockRepo := mocks.NewExpertRepo(t)
+				mockRepo.On("GetExpiredLeaseCases", mock.Anything, now).
+					Return([]string{openCaseID}, nil)
+				mockRepo.On("ExpireCaseLeases", mock.Anything, openCaseID, now).
+					Return(0, nil)
+
+				return mockRepo
+			},
+			buildCaseRepo: func() repository.CaseRepo {
+				mockRepo := mocks.NewCaseRepo(t)
+				mockRepo.On("LockCase", mock.Anything, openCaseID).
+					Return(domain.Case{ID: openCaseID, RequiredSkill: 1}, nil)
+
+				return mockRepo
+			},
+			expectedErr: nil,
+		},
+		{
+			name: "Error of one case does not stop escalation of other cases",
+			buildExpertRepo: func() repository.ExpertRepo {
+				mockRepo := mocks.NewExpertRepo(t)
+				mockRepo.On("GetExpiredLeaseCases", mock.Anything, now).
+					Return([]string{failedCaseID, reviewCaseID}, nil)
+				mockRepo.On("ExpireCaseLeases", mock.Anything, reviewCaseID, now).
+					Return(1, nil)
+				mockRepo.On("GetCaseFineDecisions", mock.Anything, reviewCaseID, 5).
+					Return(domain.FineDecisions{NegativeDecisions: 1, ExpiredLeases: 1}, nil)
+				mockRepo.On("GetExpertsCountBySkill", mock.Anything, 5).
+					Return(2, nil)
+
+				return mockRepo
+			},
+			buildCaseRepo: func() repository.CaseRepo {
+				mockRepo := mocks.NewCaseRepo(t)
+				mockRepo.On("LockCase", mock.Anything, failedCaseID).
+					Return(domain.Case{}, errs.ErrNoRows)
+				mockRepo.On("LockCase", mock.Anything, reviewCaseID).
+					Return(domain.Case{ID: reviewCaseID, RequiredSkill: 5}, nil)
+				mockRepo.On("RequestCaseReview", mock.Anything, reviewCaseID, mock.AnythingOfType("time.Time")).
+					Return(nil)
+				mockRepo.On("InsertEscalation", mock.Anything,
+					escalationLog(reviewCaseID, 5, 0, domain.LeaseExpiredEscalation)).
+					Return(nil)
+
+				return mockRepo
+			},
+			expectedEvents: []any{domain.CaseReviewRequested{
+				CaseID: reviewCaseID, Skill: 5, Reason: domain.LeaseExpiredEscalation,
+			}},
+			expectedErr: nil,
+		},
+		{
+			name: "Get expired leases error",
+			buildExpertRepo: func() repository.ExpertRepo {
+				mockRepo := mocks.NewExpertRepo(t)
+				mockRepo.On("GetExpiredLeaseCases", mock.Anything, now).
+					Return(nil, errs.ErrNoRows)
+
+				return mockRepo
+			},
+			buildCaseRepo: func() repository.CaseRepo {
+				return mocks.NewCaseRepo(t)
+			},
+			expectedErr: errs.ErrNoRows,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			expertRepo := tc.buildExpertRepo()
+			s := &expertService{
+				expertRepo:    expertRepo,
+				caseRepo:      mocks.NewCaseRepo(t),
+				uow:           newUnitOfWork(t, repository.TxRepos{Expert: expertRepo, Case: tc.buildCaseRepo()}),
+				events:        newEventPublisher(t, tc.expectedEvents...),
+				consensus:     2,
+				escalationCfg: config.EscalationConfig{MaxSkill: 5},
+			}
+
+			err := s.expireLeases(context.Background(), now)
+			assert.Equal(t, tc.expectedErr, err)
+		})
+	}
+}
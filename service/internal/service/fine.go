@@ -0,0 +1,296 @@
+package service
+
+import (
+	"TrafficPolice/internal/config"
+	"TrafficPolice/internal/domain"
+	"TrafficPolice/internal/errs"
+	"TrafficPolice/internal/repository"
+	"TrafficPolice/pkg/uin"
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/google/uuid"
+	"log"
+	"time"
+)
+
+//go:generate go run github.com/vektra/mockery/v2@v2.42.1 --name FineService
+type FineService interface {
+	GetFines(ctx context.Context, statuses []domain.FineStatus, limit int, offset int) ([]domain.Fine, error)
+	GetCaseFine(ctx context.Context, caseID string) (domain.Fine, error)
+	PayFine(ctx context.Context, payment domain.FinePayment) (domain.Fine, error)
+	// CancelFine cancels not paid fine by director with required reason, which is saved in audit log of case
+	CancelFine(ctx context.Context, userID string, fineID string, reason string) (domain.Fine, error)
+	RunOverdueChecker(done <-chan struct{})
+}
+
+type fineService struct {
+	fineRepo repository.FineRepo
+	uow      repository.UnitOfWork
+	events   EventPublisher
+	finesCfg config.FinesConfig
+}
+
+func NewFineService(
+	fineRepo repository.FineRepo,
+	uow repository.UnitOfWork,
+	events EventPublisher,
+	finesCfg config.FinesConfig,
+) FineService {
+	return &fineService{
+		fineRepo: fineRepo,
+		uow:      uow,
+		events:   events,
+		finesCfg: finesCfg,
+	}
+}
+
+// newFine builds fine of case. Payment reference is UIN seeded by fine, so fine issued again after
+// cancellation has new reference and late payment of cancelled fine is not applied to it
+func newFine(finesCfg config.FinesConfig, caseID string, amount int, issuedAt time.Time) domain.Fine {
+	fineID := uuid.New().String()
+	fine := domain.Fine{
+		ID:             fineID,
+		CaseID:         caseID,
+		Reference:      uin.New(finesCfg.ReferencePrefix, fineID),
+		Amount:         amount,
+		DiscountAmount: amount,
+		IssuedAt:       issuedAt,
+		DueAt:          issuedAt.Add(finesCfg.DueIn),
+		Status:         domain.FineIssued,
+	}
+	if finesCfg.DiscountPercent > 0 {
+		discountUntil := issuedAt.Add(finesCfg.DiscountPeriod)
+		fine.DiscountAmount = amount * (100 - finesCfg.DiscountPercent) / 100
+		fine.DiscountUntil = &discountUntil
+	}
+
+	return fine
+}
+
+// issueFine inserts fine of case in transaction of case decision and returns issued fine
+func issueFine(
+	ctx context.Context,
+	repos repository.TxRepos,
+	finesCfg config.FinesConfig,
+	caseID string,
+	issuedAt time.Time,
+) (domain.Fine, error) {
+	amount, err := repos.Fine.GetCaseFineAmount(ctx, caseID)
+	if err != nil {
+		return domain.Fine{}, err
+	}
+
+	fine := newFine(finesCfg, caseID, amount, issuedAt)
+	err = repos.Fine.InsertFine(ctx, fine)
+	if err != nil {
+		return domain.Fine{}, err
+	}
+
+	return fine, nil
+}
+
+// cancelCaseFine cancels not paid fine of case in transaction of correction of case and records notification
+// about cancellation, which is sent to violator. It returns id of cancelled fine
+func cancelCaseFine(
+	ctx context.Context,
+	repos repository.TxRepos,
+	caseID string,
+	cancelledAt time.Time,
+) (string, error) {
+	caseFine, err := repos.Fine.GetCaseFine(ctx, caseID)
+	if err != nil {
+		return "", err
+	}
+	fine, err := cancelFine(ctx, repos, caseID, caseFine.ID, cancelledAt)
+	if err != nil {
+		return "", err
+	}
+
+	return fine.ID, nil
+}
+
+// cancelFine cancels not paid fine of locked case and records notification about cancellation
+func cancelFine(
+	ctx context.Context,
+	repos repository.TxRepos,
+	caseID string,
+	fineID string,
+	cancelledAt time.Time,
+) (domain.Fine, error) {
+	fine, err := repos.Fine.LockFine(ctx, fineID)
+	if err != nil {
+		return domain.Fine{}, err
+	}
+
+	switch fine.Status {
+	case domain.FineCancelled:
+		return domain.Fine{}, errs.ErrFineCancelled
+	case domain.FinePaid:
+		return domain.Fine{}, errs.ErrFineCannotBeCancelled
+	}
+
+	fine.Status = domain.FineCancelled
+	fine.CancelledAt = &cancelledAt
+	err = repos.Fine.UpdateFineStatus(ctx, fine)
+	if err != nil {
+		return domain.Fine{}, err
+	}
+
+	_, err = insertFineMessage(ctx, repos, domain.FineCancellationKind,
+		domain.FineNotificationPayload{CaseID: caseID, FineID: fine.ID})
+	if err != nil {
+		return domain.Fine{}, err
+	}
+
+	return fine, nil
+}
+
+// insertFineMessage records message to violator about fine of case in outbox and returns id of message
+func insertFineMessage(
+	ctx context.Context,
+	repos repository.TxRepos,
+	kind domain.OutboxKind,
+	fineMsg domain.FineNotificationPayload,
+) (string, error) {
+	payload, err := json.Marshal(fineMsg)
+	if err != nil {
+		return "", err
+	}
+	msg := domain.OutboxMessage{
+		ID:        uuid.New().String(),
+		Kind:      kind,
+		Payload:   payload,
+		CreatedAt: time.Now(),
+	}
+	err = repos.Outbox.InsertMessage(ctx, msg)
+	if err != nil {
+		return "", err
+	}
+
+	return msg.ID, nil
+}
+
+func (s *fineService) GetFines(
+	ctx context.Context,
+	statuses []domain.FineStatus,
+	limit int,
+	offset int,
+) ([]domain.Fine, error) {
+	fines, err := s.fineRepo.GetFines(ctx, statuses, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	if len(fines) == 0 {
+		return nil, errs.ErrNoRows
+	}
+
+	return fines, nil
+}
+
+func (s *fineService) GetCaseFine(ctx context.Context, caseID string) (domain.Fine, error) {
+	return s.fineRepo.GetCaseFine(ctx, caseID)
+}
+
+// PayFine marks fine as paid. Provider may deliver the same payment several times, so repeated payment
+// returns paid fine. Overdue fine is paid with full amount
+func (s *fineService) PayFine(ctx context.Context, payment domain.FinePayment) (domain.Fine, error) {
+	var fine domain.Fine
+	err := s.uow.Do(ctx, func(repos repository.TxRepos) error {
+		var err error
+		fine, err = repos.Fine.LockFineByReference(ctx, payment.Reference)
+		if err != nil {
+			return err
+		}
+
+		switch fine.Status {
+		case domain.FinePaid:
+			if fine.PaymentID != nil && *fine.PaymentID == payment.PaymentID {
+				return nil
+			}
+			return errs.ErrFineAlreadyPaid
+		case domain.FineCancelled:
+			return errs.ErrFineCancelled
+		}
+
+		if payment.Amount < fine.AmountDue(payment.PaidAt) {
+			return errs.ErrInsufficientPayment
+		}
+
+		fine.Status = domain.FinePaid
+		fine.PaymentID = &payment.PaymentID
+		fine.PaidAmount = &payment.Amount
+		fine.PaidAt = &payment.PaidAt
+		return repos.Fine.UpdateFineStatus(ctx, fine)
+	})
+	if err != nil {
+		return domain.Fine{}, err
+	}
+
+	return fine, nil
+}
+
+// CancelFine cancels fine in the same way as director voids fine of case: violator is notified about
+// cancellation and cancel is saved in audit log of case. Repeated cancel returns cancelled fine
+func (s *fineService) CancelFine(ctx context.Context, userID string, fineID string, reason string) (domain.Fine, error) {
+	var fine domain.Fine
+	err := s.uow.Do(ctx, func(repos repository.TxRepos) error {
+		var err error
+		fine, err = repos.Fine.GetFine(ctx, fineID)
+		if err != nil {
+			return err
+		}
+		if fine.Status == domain.FineCancelled {
+			return nil
+		}
+
+		// case is locked before fine as in corrections of case by director
+		c, err := repos.Case.LockCase(ctx, fine.CaseID)
+		if err != nil {
+			return err
+		}
+		override := newCaseOverride(userID, fine.CaseID, domain.VoidFineAction, reason)
+		override.OldFineDecision = c.FineDecision
+		override.FineID = &fine.ID
+
+		fine, err = cancelFine(ctx, repos, fine.CaseID, fineID, override.CreatedAt)
+		if errors.Is(err, errs.ErrFineCancelled) {
+			// fine is cancelled by concurrent request
+			fine, err = repos.Fine.GetFine(ctx, fineID)
+			return err
+		}
+		if err != nil {
+			return err
+		}
+
+		return recordOverride(ctx, repos, s.events, override)
+	})
+	if err != nil {
+		return domain.Fine{}, err
+	}
+
+	return fine, nil
+}
+
+func (s *fineService) RunOverdueChecker(done <-chan struct{}) {
+	log.Printf("RunOverdueChecker with interval: %s\n", s.finesCfg.OverdueInterval)
+	ticker := time.NewTicker(s.finesCfg.OverdueInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			overdue, err := s.fineRepo.MarkOverdue(context.Background(), time.Now())
+			if err != nil {
+				log.Println(err)
+				continue
+			}
+			if overdue > 0 {
+				log.Printf("Marked %d fines as overdue\n", overdue)
+			}
+		case <-done:
+			log.Println("Stop overdue checker")
+			return
+		}
+	}
+}
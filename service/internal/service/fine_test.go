@@ -0,0 +1,280 @@
+package service
+
+import (
+	"TrafficPolice/internal/config"
+	"TrafficPolice/internal/domain"
+	"TrafficPolice/internal/errs"
+	"TrafficPolice/internal/repository"
+	"TrafficPolice/internal/repository/mocks"
+	"TrafficPolice/pkg/uin"
+	"context"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"testing"
+	"time"
+)
+
+func TestNewFine(t *testing.T) {
+	issuedAt := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	discountUntil := issuedAt.Add(20 * 24 * time.Hour)
+
+	testCases := []struct {
+		name                   string
+		finesCfg               config.FinesConfig
+		expectedDiscountAmount int
+		expectedDiscountUntil  *time.Time
+	}{
+		{
+			name: "Fine with discount",
+			finesCfg: config.FinesConfig{ReferencePrefix: "18810", DueIn: 60 * 24 * time.Hour,
+				DiscountPercent: 50, DiscountPeriod: 20 * 24 * time.Hour},
+			expectedDiscountAmount: 2500,
+			expectedDiscountUntil:  &discountUntil,
+		},
+		{
+			name:                   "Fine without discount",
+			finesCfg:               config.FinesConfig{ReferencePrefix: "18810", DueIn: 60 * 24 * time.Hour},
+			expectedDiscountAmount: 5000,
+			expectedDiscountUntil:  nil,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			fine := newFine(tc.finesCfg, "case_id", 5000, issuedAt)
+
+			assert.Equal(t, "case_id", fine.CaseID)
+			assert.Equal(t, domain.FineIssued, fine.Status)
+			assert.Equal(t, 5000, fine.Amount)
+			assert.Equal(t, tc.expectedDiscountAmount, fine.DiscountAmount)
+			assert.Equal(t, tc.expectedDiscountUntil, fine.DiscountUntil)
+			assert.Equal(t, issuedAt.Add(tc.finesCfg.DueIn), fine.DueAt)
+			assert.True(t, uin.IsValid(fine.Reference))
+			assert.Equal(t, "18810", fine.Reference[:5])
+			assert.Equal(t, uin.New("18810", fine.ID), fine.Reference)
+			// fine issued again after cancellation has new reference
+			assert.NotEqual(t, fine.Reference, newFine(tc.finesCfg, "case_id", 5000, issuedAt).Reference)
+		})
+	}
+}
+
+func TestPayFine(t *testing.T) {
+	issuedAt := time.Now().Add(-30 * 24 * time.Hour)
+	discountUntil := issuedAt.Add(20 * 24 * time.Hour)
+	reference := "18810000000000000001"
+	paymentID := "payment_id"
+	otherPaymentID := "other_payment_id"
+
+	issuedFine := domain.Fine{ID: "fine_id", Reference: reference, Amount: 5000, DiscountAmount: 2500,
+		DiscountUntil: &discountUntil, IssuedAt: issuedAt, DueAt: issuedAt.Add(60 * 24 * time.Hour),
+		Status: domain.FineIssued}
+	paidFine := issuedFine
+	paidFine.Status = domain.FinePaid
+	paidFine.PaymentID = &paymentID
+	cancelledFine := issuedFine
+	cancelledFine.Status = domain.FineCancelled
+	overdueFine := issuedFine
+	overdueFine.Status = domain.FineOverdue
+
+	testCases := []struct {
+		name         string
+		fine         domain.Fine
+		lockErr      error
+		payment      domain.FinePayment
+		expectUpdate bool
+		expectedErr  error
+	}{
+		{
+			name: "Payment with discount",
+			fine: issuedFine,
+			payment: domain.FinePayment{PaymentID: paymentID, Reference: reference, Amount: 2500,
+				PaidAt: issuedAt.Add(24 * time.Hour)},
+			expectUpdate: true,
+		},
+		{
+			name: "Payment with discount after discount period",
+			fine: issuedFine,
+			payment: domain.FinePayment{PaymentID: paymentID, Reference: reference, Amount: 2500,
+				PaidAt: issuedAt.Add(25 * 24 * time.Hour)},
+			expectedErr: errs.ErrInsufficientPayment,
+		},
+		{
+			name: "Overdue fine is paid with full amount",
+			fine: overdueFine,
+			payment: domain.FinePayment{PaymentID: paymentID, Reference: reference, Amount: 5000,
+				PaidAt: issuedAt.Add(70 * 24 * time.Hour)},
+			expectUpdate: true,
+		},
+		{
+			name:    "Repeated payment",
+			fine:    paidFine,
+			payment: domain.FinePayment{PaymentID: paymentID, Reference: reference, Amount: 2500, PaidAt: issuedAt},
+		},
+		{
+			name: "Fine is paid by another payment",
+			fine: paidFine,
+			payment: domain.FinePayment{PaymentID: otherPaymentID, Reference: reference, Amount: 2500,
+				PaidAt: issuedAt},
+			expectedErr: errs.ErrFineAlreadyPaid,
+		},
+		{
+			name:        "Fine is cancelled",
+			fine:        cancelledFine,
+			payment:     domain.FinePayment{PaymentID: paymentID, Reference: reference, Amount: 5000, PaidAt: issuedAt},
+			expectedErr: errs.ErrFineCancelled,
+		},
+		{
+			name:        "Unknown reference",
+			lockErr:     errs.ErrNoFine,
+			payment:     domain.FinePayment{PaymentID: paymentID, Reference: reference, Amount: 5000, PaidAt: issuedAt},
+			expectedErr: errs.ErrNoFine,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			fineRepo := mocks.NewFineRepo(t)
+			fineRepo.On("LockFineByReference", mock.Anything, reference).
+				Return(tc.fine, tc.lockErr)
+			if tc.expectUpdate {
+				fineRepo.On("UpdateFineStatus", mock.Anything, mock.MatchedBy(func(fine domain.Fine) bool {
+					return fine.Status == domain.FinePaid && *fine.PaymentID == tc.payment.PaymentID &&
+						*fine.PaidAmount == tc.payment.Amount && fine.PaidAt.Equal(tc.payment.PaidAt)
+				})).
+					Return(nil).
+					Times(1)
+			}
+
+			s := NewFineService(mocks.NewFineRepo(t), newUnitOfWork(t, repository.TxRepos{Fine: fineRepo}), nil,
+				config.FinesConfig{})
+			fine, err := s.PayFine(context.Background(), tc.payment)
+			assert.Equal(t, tc.expectedErr, err)
+			if tc.expectedErr == nil {
+				assert.Equal(t, domain.FinePaid, fine.Status)
+			}
+		})
+	}
+}
+
+func TestCancelFine(t *testing.T) {
+	caseID := uuid.New().String()
+	userID := uuid.New().String()
+	fineID := uuid.New().String()
+	paymentID := "payment_id"
+	now := time.Now()
+	fineCase := domain.Case{ID: caseID, IsSolved: true, FineDecision: true}
+
+	testCases := []struct {
+		name           string
+		fine           domain.Fine
+		fineErr        error
+		expectLock     bool
+		expectCancel   bool
+		expectedEvents []any
+		expectedErr    error
+	}{
+		{
+			name:         "Issued fine is cancelled with notification and audit",
+			fine:         domain.Fine{ID: fineID, CaseID: caseID, Status: domain.FineIssued},
+			expectLock:   true,
+			expectCancel: true,
+			expectedEvents: []any{
+				domain.CaseOverridden{CaseID: caseID, Action: domain.VoidFineAction, Reason: "reason"},
+			},
+		},
+		{
+			name:         "Overdue fine is cancelled with notification and audit",
+			fine:         domain.Fine{ID: fineID, CaseID: caseID, Status: domain.FineOverdue},
+			expectLock:   true,
+			expectCancel: true,
+			expectedEvents: []any{
+				domain.CaseOverridden{CaseID: caseID, Action: domain.VoidFineAction, Reason: "reason"},
+			},
+		},
+		{
+			name: "Repeated cancel. Audit is not recorded again",
+			fine: domain.Fine{ID: fineID, CaseID: caseID, Status: domain.FineCancelled, CancelledAt: &now},
+		},
+		{
+			name:        "Paid fine can not be cancelled",
+			fine:        domain.Fine{ID: fineID, CaseID: caseID, Status: domain.FinePaid, PaymentID: &paymentID},
+			expectLock:  true,
+			expectedErr: errs.ErrFineCannotBeCancelled,
+		},
+		{
+			name:        "Fine not found",
+			fineErr:     errs.ErrNoFine,
+			expectedErr: errs.ErrNoFine,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			caseRepo := mocks.NewCaseRepo(t)
+			outboxRepo := mocks.NewOutboxRepo(t)
+			fineRepo := mocks.NewFineRepo(t)
+			fineRepo.On("GetFine", mock.Anything, fineID).
+				Return(tc.fine, tc.fineErr)
+			if tc.expectLock {
+				caseRepo.On("LockCase", mock.Anything, caseID).
+					Return(fineCase, nil)
+				fineRepo.On("LockFine", mock.Anything, fineID).
+					Return(tc.fine, nil)
+			}
+			if tc.expectCancel {
+				fineRepo.On("UpdateFineStatus", mock.Anything, mock.MatchedBy(func(f domain.Fine) bool {
+					return f.ID == fineID && f.Status == domain.FineCancelled && f.CancelledAt != nil
+				})).
+					Return(nil).
+					Times(1)
+				outboxRepo.On("InsertMessage", mock.Anything, isFineMessage(domain.FineCancellationKind, caseID, fineID)).
+					Return(nil)
+				caseRepo.On("InsertOverride", mock.Anything,
+					caseOverride(caseID, userID, domain.VoidFineAction, true, nil, &fineID)).
+					Return(nil)
+			}
+			uow := newUnitOfWork(t, repository.TxRepos{Case: caseRepo, Outbox: outboxRepo, Fine: fineRepo})
+
+			s := NewFineService(mocks.NewFineRepo(t), uow, newEventPublisher(t, tc.expectedEvents...),
+				config.FinesConfig{})
+			fine, err := s.CancelFine(context.Background(), userID, fineID, "reason")
+			assert.Equal(t, tc.expectedErr, err)
+			if tc.expectedErr == nil {
+				assert.Equal(t, domain.FineCancelled, fine.Status)
+			}
+		})
+	}
+}
+
+func TestGetFines(t *testing.T) {
+	statuses := []domain.FineStatus{domain.FineIssued, domain.FineOverdue}
+
+	testCases := []struct {
+		name        string
+		fines       []domain.Fine
+		expectedErr error
+	}{
+		{
+			name:  "Unpaid fines",
+			fines: []domain.Fine{{ID: "fine_id", Status: domain.FineOverdue}},
+		},
+		{
+			name:        "No unpaid fines",
+			fines:       []domain.Fine{},
+			expectedErr: errs.ErrNoRows,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			fineRepo := mocks.NewFineRepo(t)
+			fineRepo.On("GetFines", mock.Anything, statuses, 10, 0).
+				Return(tc.fines, nil)
+
+			s := NewFineService(fineRepo, mocks.NewUnitOfWork(t), nil, config.FinesConfig{})
+			_, err := s.GetFines(context.Background(), statuses, 10, 0)
+			assert.Equal(t, tc.expectedErr, err)
+		})
+	}
+}
@@ -1,51 +1,222 @@
 package service
 
 import (
+	"TrafficPolice/internal/domain"
 	"TrafficPolice/internal/errs"
-	"fmt"
+	"TrafficPolice/internal/repository"
+	"TrafficPolice/internal/storage"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
 	"log"
-	"os"
-	"path/filepath"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
 )
 
 //go:generate go run github.com/vektra/mockery/v2@v2.42.1 --name ImgService
 type ImgService interface {
-	SaveImg(img []byte, filepath string) error
-	GetImgFilePath(dir string, id string) (string, error)
+	SaveImg(ctx context.Context, owner domain.ImageOwner, variant domain.ImageVariant, img []byte) error
+	SaveImgs(
+		ctx context.Context,
+		owner domain.ImageOwner,
+		imgs map[domain.ImageVariant][]byte,
+		inTx func(repos repository.TxRepos) error,
+	) error
+	GetImg(ctx context.Context, owner domain.ImageOwner, variant domain.ImageVariant) (domain.Image, error)
+	DeleteImg(ctx context.Context, owner domain.ImageOwner) error
 }
 
-type imgServiceLocal struct {
+// imgService stores images in content-addressed storage. Blob key is SHA-256 of image,
+// so same images of different owners are stored once. Metadata links variants of owner images with blobs.
+// Blob is linked and deleted under lock of its key, so unused blob is not deleted, while it gets new ref
+type imgService struct {
+	storage   storage.BlobStorage
+	imageRepo repository.ImageRepo
+	uow       repository.UnitOfWork
 }
 
-func NewImgService() ImgService {
-	return &imgServiceLocal{}
+func NewImgService(storage storage.BlobStorage, imageRepo repository.ImageRepo, uow repository.UnitOfWork) ImgService {
+	return &imgService{
+		storage:   storage,
+		imageRepo: imageRepo,
+		uow:       uow,
+	}
+}
+
+func (s *imgService) SaveImg(
+	ctx context.Context,
+	owner domain.ImageOwner,
+	variant domain.ImageVariant,
+	img []byte,
+) error {
+	return s.SaveImgs(ctx, owner, map[domain.ImageVariant][]byte{variant: img}, nil)
+}
+
+// SaveImgs saves variants of owner image. Blobs are written to storage before transaction, so transaction
+// is not kept open during writes. inTx is called in transaction, in which metadata is saved, so caller
+// changes are committed together with metadata. If transaction fails, written blobs without refs are deleted
+func (s *imgService) SaveImgs(
+	ctx context.Context,
+	owner domain.ImageOwner,
+	imgs map[domain.ImageVariant][]byte,
+	inTx func(repos repository.TxRepos) error,
+) error {
+	metas := make([]domain.ImageMeta, 0, len(imgs))
+	blobs := make(map[string][]byte, len(imgs))
+	for variant, img := range imgs {
+		sum := sha256.Sum256(img)
+		blobKey := hex.EncodeToString(sum[:])
+		blobs[blobKey] = img
+		metas = append(metas, domain.ImageMeta{
+			Owner:       owner,
+			Variant:     variant,
+			BlobKey:     blobKey,
+			ContentType: http.DetectContentType(img),
+			Size:        int64(len(img)),
+			CreatedAt:   time.Now(),
+		})
+	}
+	// blobs are locked in the same order by concurrent transactions
+	slices.SortFunc(metas, func(a, b domain.ImageMeta) int {
+		return strings.Compare(a.BlobKey, b.BlobKey)
+	})
+
+	for blobKey, img := range blobs {
+		err := s.putBlob(ctx, blobKey, img)
+		if err != nil {
+			return err
+		}
+	}
+
+	var oldMetas []domain.ImageMeta
+	err := s.uow.Do(ctx, func(repos repository.TxRepos) error {
+		oldMetas = oldMetas[:0]
+		if inTx != nil {
+			err := inTx(repos)
+			if err != nil {
+				return err
+			}
+		}
+
+		for _, meta := range metas {
+			err := repos.Image.LockBlob(ctx, meta.BlobKey)
+			if err != nil {
+				return err
+			}
+
+			// blob could be deleted as unused after it was written, until metadata links it with owner
+			err = s.putBlob(ctx, meta.BlobKey, blobs[meta.BlobKey])
+			if err != nil {
+				return err
+			}
+
+			oldMeta, err := repos.Image.GetImageMeta(ctx, owner, meta.Variant)
+			switch {
+			case err == nil:
+				oldMetas = append(oldMetas, oldMeta)
+			case !errors.Is(err, errs.ErrNoImage):
+				return err
+			}
+
+			err = repos.Image.SetImageMeta(ctx, meta)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		// cleanup is not canceled with request, otherwise unused blobs are left
+		for blobKey := range blobs {
+			s.deleteUnusedBlob(context.WithoutCancel(ctx), blobKey)
+		}
+		return err
+	}
+
+	for _, oldMeta := range oldMetas {
+		if _, ok := blobs[oldMeta.BlobKey]; !ok {
+			s.deleteUnusedBlob(ctx, oldMeta.BlobKey)
+		}
+	}
+	return nil
+}
+
+// putBlob writes blob, if it is not in storage
+func (s *imgService) putBlob(ctx context.Context, blobKey string, img []byte) error {
+	exists, err := s.storage.Exists(ctx, blobKey)
+	if err != nil || exists {
+		return err
+	}
+
+	return s.storage.Put(ctx, blobKey, img)
 }
 
-func (s *imgServiceLocal) SaveImg(img []byte, filepath string) error {
-	imgFile, err := os.Create(filepath)
+func (s *imgService) GetImg(
+	ctx context.Context,
+	owner domain.ImageOwner,
+	variant domain.ImageVariant,
+) (domain.Image, error) {
+	meta, err := s.imageRepo.GetImageMeta(ctx, owner, variant)
+	if err != nil {
+		return domain.Image{}, err
+	}
+
+	data, err := s.storage.Get(ctx, meta.BlobKey)
+	if err != nil {
+		if errors.Is(err, storage.ErrBlobNotFound) {
+			return domain.Image{}, errs.ErrNoImage
+		}
+		return domain.Image{}, err
+	}
+
+	return domain.Image{Data: data, ContentType: meta.ContentType}, nil
+}
+
+// DeleteImg deletes all variants of owner image
+func (s *imgService) DeleteImg(ctx context.Context, owner domain.ImageOwner) error {
+	metas, err := s.imageRepo.GetImageMetas(ctx, owner)
 	if err != nil {
-		log.Printf("Error while create file to server: %v\n", err)
 		return err
 	}
-	defer imgFile.Close()
+	if len(metas) == 0 {
+		return nil
+	}
 
-	_, err = imgFile.Write(img)
+	err = s.imageRepo.DeleteImageMeta(ctx, owner)
 	if err != nil {
-		log.Printf("Error while writing tempFile: %v\n", err)
 		return err
 	}
 
+	for _, meta := range metas {
+		s.deleteUnusedBlob(ctx, meta.BlobKey)
+	}
 	return nil
 }
 
-func (s *imgServiceLocal) GetImgFilePath(dir string, id string) (string, error) {
-	pattern := fmt.Sprintf("%s/%s.*", dir, id)
-	files, err := filepath.Glob(pattern)
-	if len(files) == 0 {
-		return "", errs.ErrNoImage
-	}
+// deleteUnusedBlob deletes blob, if it is not linked with any owner.
+// Error is only logged, because unused blob does not break anything
+func (s *imgService) deleteUnusedBlob(ctx context.Context, blobKey string) {
+	err := s.uow.Do(ctx, func(repos repository.TxRepos) error {
+		err := repos.Image.LockBlob(ctx, blobKey)
+		if err != nil {
+			return err
+		}
+
+		refs, err := repos.Image.CountBlobRefs(ctx, blobKey)
+		if err != nil {
+			return err
+		}
+		if refs > 0 {
+			return nil
+		}
+
+		return s.storage.Delete(ctx, blobKey)
+	})
 	if err != nil {
-		return "", err
+		log.Printf("Error while deleting unused blob %s: %v\n", blobKey, err)
 	}
-	return files[0], nil
 }
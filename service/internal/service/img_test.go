@@ -0,0 +1,379 @@
+package service
+
+import (
+	"TrafficPolice/internal/domain"
+	"TrafficPolice/internal/errs"
+	"TrafficPolice/internal/repository"
+	"TrafficPolice/internal/repository/mocks"
+	"TrafficPolice/internal/storage"
+	mocksstorage "TrafficPolice/internal/storage/mocks"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"testing"
+)
+
+func TestSaveImg(t *testing.T) {
+	owner := domain.ImageOwner{Type: domain.CaseImageOwner, ID: uuid.New().String()}
+	img := []byte("image")
+	sum := sha256.Sum256(img)
+	blobKey := hex.EncodeToString(sum[:])
+	oldBlobKey := hex.EncodeToString(make([]byte, sha256.Size))
+
+	isNewMeta := mock.MatchedBy(func(meta domain.ImageMeta) bool {
+		return meta.Owner == owner && meta.Variant == domain.OriginalImage && meta.BlobKey == blobKey && meta.Size == int64(len(img))
+	})
+
+	testCases := []struct {
+		name           string
+		buildStorage   func() storage.BlobStorage
+		buildImageRepo func() repository.ImageRepo
+		expectedErr    error
+	}{
+		{
+			name: "New blob",
+			buildStorage: func() storage.BlobStorage {
+				mockStorage := mocksstorage.NewBlobStorage(t)
+
+				mockStorage.On("Exists", mock.Anything, blobKey).Return(false, nil).Once()
+				mockStorage.On("Put", mock.Anything, blobKey, img).Return(nil).Once()
+				mockStorage.On("Exists", mock.Anything, blobKey).Return(true, nil).Once()
+
+				return mockStorage
+			},
+			buildImageRepo: func() repository.ImageRepo {
+				mockRepo := mocks.NewImageRepo(t)
+
+				mockRepo.On("LockBlob", mock.Anything, blobKey).Return(nil)
+				mockRepo.On("GetImageMeta", mock.Anything, owner, domain.OriginalImage).Return(domain.ImageMeta{}, errs.ErrNoImage)
+				mockRepo.On("SetImageMeta", mock.Anything, isNewMeta).Return(nil)
+
+				return mockRepo
+			},
+			expectedErr: nil,
+		},
+		{
+			name: "Existing blob is not written again",
+			buildStorage: func() storage.BlobStorage {
+				mockStorage := mocksstorage.NewBlobStorage(t)
+
+				mockStorage.On("Exists", mock.Anything, blobKey).Return(true, nil)
+
+				return mockStorage
+			},
+			buildImageRepo: func() repository.ImageRepo {
+				mockRepo := mocks.NewImageRepo(t)
+
+				mockRepo.On("LockBlob", mock.Anything, blobKey).Return(nil)
+				mockRepo.On("GetImageMeta", mock.Anything, owner, domain.OriginalImage).Return(domain.ImageMeta{}, errs.ErrNoImage)
+				mockRepo.On("SetImageMeta", mock.Anything, isNewMeta).Return(nil)
+
+				return mockRepo
+			},
+			expectedErr: nil,
+		},
+		{
+			name: "Replaced blob without refs is deleted",
+			buildStorage: func() storage.BlobStorage {
+				mockStorage := mocksstorage.NewBlobStorage(t)
+
+				mockStorage.On("Exists", mock.Anything, blobKey).Return(false, nil).Once()
+				mockStorage.On("Put", mock.Anything, blobKey, img).Return(nil).Once()
+				mockStorage.On("Exists", mock.Anything, blobKey).Return(true, nil).Once()
+				mockStorage.On("Delete", mock.Anything, oldBlobKey).Return(nil)
+
+				return mockStorage
+			},
+			buildImageRepo: func() repository.ImageRepo {
+				mockRepo := mocks.NewImageRepo(t)
+
+				mockRepo.On("LockBlob", mock.Anything, blobKey).Return(nil)
+				mockRepo.On("GetImageMeta", mock.Anything, owner, domain.OriginalImage).Return(domain.ImageMeta{Owner: owner, BlobKey: oldBlobKey}, nil)
+				mockRepo.On("SetImageMeta", mock.Anything, isNewMeta).Return(nil)
+				mockRepo.On("LockBlob", mock.Anything, oldBlobKey).Return(nil)
+				mockRepo.On("CountBlobRefs", mock.Anything, oldBlobKey).Return(0, nil)
+
+				return mockRepo
+			},
+			expectedErr: nil,
+		},
+		{
+			name: "Replaced blob with refs is kept",
+			buildStorage: func() storage.BlobStorage {
+				mockStorage := mocksstorage.NewBlobStorage(t)
+
+				mockStorage.On("Exists", mock.Anything, blobKey).Return(true, nil)
+
+				return mockStorage
+			},
+			buildImageRepo: func() repository.ImageRepo {
+				mockRepo := mocks.NewImageRepo(t)
+
+				mockRepo.On("LockBlob", mock.Anything, blobKey).Return(nil)
+				mockRepo.On("GetImageMeta", mock.Anything, owner, domain.OriginalImage).Return(domain.ImageMeta{Owner: owner, BlobKey: oldBlobKey}, nil)
+				mockRepo.On("SetImageMeta", mock.Anything, isNewMeta).Return(nil)
+				mockRepo.On("LockBlob", mock.Anything, oldBlobKey).Return(nil)
+				mockRepo.On("CountBlobRefs", mock.Anything, oldBlobKey).Return(1, nil)
+
+				return mockRepo
+			},
+			expectedErr: nil,
+		},
+		{
+			name: "Storage error",
+			buildStorage: func() storage.BlobStorage {
+				mockStorage := mocksstorage.NewBlobStorage(t)
+
+				mockStorage.On("Exists", mock.Anything, blobKey).Return(false, errors.New("storage error"))
+
+				return mockStorage
+			},
+			buildImageRepo: func() repository.ImageRepo {
+				return mocks.NewImageRepo(t)
+			},
+			expectedErr: errors.New("storage error"),
+		},
+		{
+			name: "Blob deleted as unused before it is linked is written again",
+			buildStorage: func() storage.BlobStorage {
+				mockStorage := mocksstorage.NewBlobStorage(t)
+
+				mockStorage.On("Exists", mock.Anything, blobKey).Return(false, nil).Times(2)
+				mockStorage.On("Put", mock.Anything, blobKey, img).Return(nil).Times(2)
+
+				return mockStorage
+			},
+			buildImageRepo: func() repository.ImageRepo {
+				mockRepo := mocks.NewImageRepo(t)
+
+				mockRepo.On("LockBlob", mock.Anything, blobKey).Return(nil)
+				mockRepo.On("GetImageMeta", mock.Anything, owner, domain.OriginalImage).Return(domain.ImageMeta{}, errs.ErrNoImage)
+				mockRepo.On("SetImageMeta", mock.Anything, isNewMeta).Return(nil)
+
+				return mockRepo
+			},
+			expectedErr: nil,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			imageRepo := tc.buildImageRepo()
+			imgService := NewImgService(tc.buildStorage(), imageRepo, newUnitOfWork(t, repository.TxRepos{Image: imageRepo}))
+
+			err := imgService.SaveImg(context.Background(), owner, domain.OriginalImage, img)
+
+			assert.Equal(t, tc.expectedErr, err)
+		})
+	}
+}
+
+func TestSaveImgs(t *testing.T) {
+	owner := domain.ImageOwner{Type: domain.CaseImageOwner, ID: uuid.New().String()}
+	imgs := map[domain.ImageVariant][]byte{
+		domain.OriginalImage: []byte("original"),
+		domain.ThumbImage:    []byte("thumb"),
+	}
+	errCaseLocked := errors.New("case is locked")
+
+	testCases := []struct {
+		name        string
+		inTxErr     error
+		expectedErr error
+	}{
+		{
+			name:        "Metadata of variants is saved with changes of caller",
+			inTxErr:     nil,
+			expectedErr: nil,
+		},
+		{
+			name:        "Transaction of caller fails. Written blobs without refs are deleted",
+			inTxErr:     errCaseLocked,
+			expectedErr: errCaseLocked,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockStorage := mocksstorage.NewBlobStorage(t)
+			imageRepo := mocks.NewImageRepo(t)
+			for variant, img := range imgs {
+				sum := sha256.Sum256(img)
+				blobKey := hex.EncodeToString(sum[:])
+
+				mockStorage.On("Exists", mock.Anything, blobKey).Return(false, nil).Once()
+				mockStorage.On("Put", mock.Anything, blobKey, img).Return(nil).Once()
+				imageRepo.On("LockBlob", mock.Anything, blobKey).Return(nil).Once()
+				if tc.inTxErr != nil {
+					imageRepo.On("CountBlobRefs", mock.Anything, blobKey).Return(0, nil)
+					mockStorage.On("Delete", mock.Anything, blobKey).Return(nil)
+					continue
+				}
+
+				mockStorage.On("Exists", mock.Anything, blobKey).Return(true, nil).Once()
+				imageRepo.On("GetImageMeta", mock.Anything, owner, variant).Return(domain.ImageMeta{}, errs.ErrNoImage)
+				imageRepo.On("SetImageMeta", mock.Anything, mock.MatchedBy(func(meta domain.ImageMeta) bool {
+					return meta.Owner == owner && meta.Variant == variant && meta.BlobKey == blobKey
+				})).Return(nil)
+			}
+			imgService := NewImgService(mockStorage, imageRepo, newUnitOfWork(t, repository.TxRepos{Image: imageRepo}))
+
+			inTxCalled := false
+			err := imgService.SaveImgs(context.Background(), owner, imgs, func(repos repository.TxRepos) error {
+				inTxCalled = true
+				return tc.inTxErr
+			})
+
+			assert.Equal(t, tc.expectedErr, err)
+			assert.True(t, inTxCalled)
+		})
+	}
+}
+
+func TestGetImg(t *testing.T) {
+	owner := domain.ImageOwner{Type: domain.ExpertImageOwner, ID: uuid.New().String()}
+	blobKey := hex.EncodeToString(make([]byte, sha256.Size))
+	meta := domain.ImageMeta{Owner: owner, BlobKey: blobKey, ContentType: "image/png"}
+
+	testCases := []struct {
+		name           string
+		buildStorage   func() storage.BlobStorage
+		buildImageRepo func() repository.ImageRepo
+		expectedImg    domain.Image
+		expectedErr    error
+	}{
+		{
+			name: "Successful get image",
+			buildStorage: func() storage.BlobStorage {
+				mockStorage := mocksstorage.NewBlobStorage(t)
+
+				mockStorage.On("Get", mock.Anything, blobKey).Return([]byte("image"), nil)
+
+				return mockStorage
+			},
+			buildImageRepo: func() repository.ImageRepo {
+				mockRepo := mocks.NewImageRepo(t)
+
+				mockRepo.On("GetImageMeta", mock.Anything, owner, domain.OriginalImage).Return(meta, nil)
+
+				return mockRepo
+			},
+			expectedImg: domain.Image{Data: []byte("image"), ContentType: "image/png"},
+			expectedErr: nil,
+		},
+		{
+			name: "No image meta",
+			buildStorage: func() storage.BlobStorage {
+				return mocksstorage.NewBlobStorage(t)
+			},
+			buildImageRepo: func() repository.ImageRepo {
+				mockRepo := mocks.NewImageRepo(t)
+
+				mockRepo.On("GetImageMeta", mock.Anything, owner, domain.OriginalImage).Return(domain.ImageMeta{}, errs.ErrNoImage)
+
+				return mockRepo
+			},
+			expectedErr: errs.ErrNoImage,
+		},
+		{
+			name: "Blob is missing",
+			buildStorage: func() storage.BlobStorage {
+				mockStorage := mocksstorage.NewBlobStorage(t)
+
+				mockStorage.On("Get", mock.Anything, blobKey).Return(nil, storage.ErrBlobNotFound)
+
+				return mockStorage
+			},
+			buildImageRepo: func() repository.ImageRepo {
+				mockRepo := mocks.NewImageRepo(t)
+
+				mockRepo.On("GetImageMeta", mock.Anything, owner, domain.OriginalImage).Return(meta, nil)
+
+				return mockRepo
+			},
+			expectedErr: errs.ErrNoImage,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			imageRepo := tc.buildImageRepo()
+			imgService := NewImgService(tc.buildStorage(), imageRepo, newUnitOfWork(t, repository.TxRepos{Image: imageRepo}))
+
+			img, err := imgService.GetImg(context.Background(), owner, domain.OriginalImage)
+
+			assert.Equal(t, tc.expectedErr, err)
+			assert.Equal(t, tc.expectedImg, img)
+		})
+	}
+}
+
+func TestDeleteImg(t *testing.T) {
+	owner := domain.ImageOwner{Type: domain.CaseImageOwner, ID: uuid.New().String()}
+	originalKey := hex.EncodeToString(make([]byte, sha256.Size))
+	sum := sha256.Sum256([]byte("redacted"))
+	redactedKey := hex.EncodeToString(sum[:])
+
+	testCases := []struct {
+		name           string
+		buildStorage   func() storage.BlobStorage
+		buildImageRepo func() repository.ImageRepo
+		expectedErr    error
+	}{
+		{
+			name: "Unused blobs of all variants are deleted",
+			buildStorage: func() storage.BlobStorage {
+				mockStorage := mocksstorage.NewBlobStorage(t)
+
+				mockStorage.On("Delete", mock.Anything, redactedKey).Return(nil)
+
+				return mockStorage
+			},
+			buildImageRepo: func() repository.ImageRepo {
+				mockRepo := mocks.NewImageRepo(t)
+
+				mockRepo.On("GetImageMetas", mock.Anything, owner).Return([]domain.ImageMeta{
+					{Owner: owner, Variant: domain.OriginalImage, BlobKey: originalKey},
+					{Owner: owner, Variant: domain.RedactedImage, BlobKey: redactedKey},
+				}, nil)
+				mockRepo.On("DeleteImageMeta", mock.Anything, owner).Return(nil)
+				mockRepo.On("LockBlob", mock.Anything, originalKey).Return(nil)
+				mockRepo.On("LockBlob", mock.Anything, redactedKey).Return(nil)
+				mockRepo.On("CountBlobRefs", mock.Anything, originalKey).Return(1, nil)
+				mockRepo.On("CountBlobRefs", mock.Anything, redactedKey).Return(0, nil)
+
+				return mockRepo
+			},
+			expectedErr: nil,
+		},
+		{
+			name: "No image",
+			buildStorage: func() storage.BlobStorage {
+				return mocksstorage.NewBlobStorage(t)
+			},
+			buildImageRepo: func() repository.ImageRepo {
+				mockRepo := mocks.NewImageRepo(t)
+
+				mockRepo.On("GetImageMetas", mock.Anything, owner).Return([]domain.ImageMeta{}, nil)
+
+				return mockRepo
+			},
+			expectedErr: nil,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			imageRepo := tc.buildImageRepo()
+			imgService := NewImgService(tc.buildStorage(), imageRepo, newUnitOfWork(t, repository.TxRepos{Image: imageRepo}))
+
+			err := imgService.DeleteImg(context.Background(), owner)
+
+			assert.Equal(t, tc.expectedErr, err)
+		})
+	}
+}
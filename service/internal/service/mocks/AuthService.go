@@ -4,6 +4,7 @@ package mocks
 
 import (
 	domain "TrafficPolice/internal/domain"
+	context "context"
 
 	mock "github.com/stretchr/testify/mock"
 
@@ -15,17 +16,17 @@ type AuthService struct {
 	mock.Mock
 }
 
-// ConfirmExpert provides a mock function with given fields: data
-func (_m *AuthService) ConfirmExpert(data domain.ConfirmExpert) error {
-	ret := _m.Called(data)
+// ConfirmExpert provides a mock function with given fields: ctx, data
+func (_m *AuthService) ConfirmExpert(ctx context.Context, data domain.ConfirmExpert) error {
+	ret := _m.Called(ctx, data)
 
 	if len(ret) == 0 {
 		panic("no return value specified for ConfirmExpert")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(domain.ConfirmExpert) error); ok {
-		r0 = rf(data)
+	if rf, ok := ret.Get(0).(func(context.Context, domain.ConfirmExpert) error); ok {
+		r0 = rf(ctx, data)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -61,9 +62,9 @@ func (_m *AuthService) ParseAccessToken(accessToken string) (tokens.TokenInfo, e
 	return r0, r1
 }
 
-// RegisterCamera provides a mock function with given fields: info
-func (_m *AuthService) RegisterCamera(info domain.RegisterCamera) (string, error) {
-	ret := _m.Called(info)
+// RegisterCamera provides a mock function with given fields: ctx, info
+func (_m *AuthService) RegisterCamera(ctx context.Context, info domain.RegisterCamera) (string, error) {
+	ret := _m.Called(ctx, info)
 
 	if len(ret) == 0 {
 		panic("no return value specified for RegisterCamera")
@@ -71,17 +72,17 @@ func (_m *AuthService) RegisterCamera(info domain.RegisterCamera) (string, error
 
 	var r0 string
 	var r1 error
-	if rf, ok := ret.Get(0).(func(domain.RegisterCamera) (string, error)); ok {
-		return rf(info)
+	if rf, ok := ret.Get(0).(func(context.Context, domain.RegisterCamera) (string, error)); ok {
+		return rf(ctx, info)
 	}
-	if rf, ok := ret.Get(0).(func(domain.RegisterCamera) string); ok {
-		r0 = rf(info)
+	if rf, ok := ret.Get(0).(func(context.Context, domain.RegisterCamera) string); ok {
+		r0 = rf(ctx, info)
 	} else {
 		r0 = ret.Get(0).(string)
 	}
 
-	if rf, ok := ret.Get(1).(func(domain.RegisterCamera) error); ok {
-		r1 = rf(info)
+	if rf, ok := ret.Get(1).(func(context.Context, domain.RegisterCamera) error); ok {
+		r1 = rf(ctx, info)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -89,17 +90,17 @@ func (_m *AuthService) RegisterCamera(info domain.RegisterCamera) (string, error
 	return r0, r1
 }
 
-// RegisterDirectors provides a mock function with given fields: users
-func (_m *AuthService) RegisterDirectors(users []domain.UserInfo) error {
-	ret := _m.Called(users)
+// RegisterDirectors provides a mock function with given fields: ctx, users
+func (_m *AuthService) RegisterDirectors(ctx context.Context, users []domain.UserInfo) error {
+	ret := _m.Called(ctx, users)
 
 	if len(ret) == 0 {
 		panic("no return value specified for RegisterDirectors")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func([]domain.UserInfo) error); ok {
-		r0 = rf(users)
+	if rf, ok := ret.Get(0).(func(context.Context, []domain.UserInfo) error); ok {
+		r0 = rf(ctx, users)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -107,17 +108,17 @@ func (_m *AuthService) RegisterDirectors(users []domain.UserInfo) error {
 	return r0
 }
 
-// RegisterExpert provides a mock function with given fields: input
-func (_m *AuthService) RegisterExpert(input domain.UserInfo) error {
-	ret := _m.Called(input)
+// RegisterExpert provides a mock function with given fields: ctx, input
+func (_m *AuthService) RegisterExpert(ctx context.Context, input domain.UserInfo) error {
+	ret := _m.Called(ctx, input)
 
 	if len(ret) == 0 {
 		panic("no return value specified for RegisterExpert")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(domain.UserInfo) error); ok {
-		r0 = rf(input)
+	if rf, ok := ret.Get(0).(func(context.Context, domain.UserInfo) error); ok {
+		r0 = rf(ctx, input)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -125,9 +126,9 @@ func (_m *AuthService) RegisterExpert(input domain.UserInfo) error {
 	return r0
 }
 
-// SignIn provides a mock function with given fields: input
-func (_m *AuthService) SignIn(input domain.UserInfo) (domain.Tokens, error) {
-	ret := _m.Called(input)
+// SignIn provides a mock function with given fields: ctx, input
+func (_m *AuthService) SignIn(ctx context.Context, input domain.UserInfo) (domain.Tokens, error) {
+	ret := _m.Called(ctx, input)
 
 	if len(ret) == 0 {
 		panic("no return value specified for SignIn")
@@ -135,17 +136,17 @@ func (_m *AuthService) SignIn(input domain.UserInfo) (domain.Tokens, error) {
 
 	var r0 domain.Tokens
 	var r1 error
-	if rf, ok := ret.Get(0).(func(domain.UserInfo) (domain.Tokens, error)); ok {
-		return rf(input)
+	if rf, ok := ret.Get(0).(func(context.Context, domain.UserInfo) (domain.Tokens, error)); ok {
+		return rf(ctx, input)
 	}
-	if rf, ok := ret.Get(0).(func(domain.UserInfo) domain.Tokens); ok {
-		r0 = rf(input)
+	if rf, ok := ret.Get(0).(func(context.Context, domain.UserInfo) domain.Tokens); ok {
+		r0 = rf(ctx, input)
 	} else {
 		r0 = ret.Get(0).(domain.Tokens)
 	}
 
-	if rf, ok := ret.Get(1).(func(domain.UserInfo) error); ok {
-		r1 = rf(input)
+	if rf, ok := ret.Get(1).(func(context.Context, domain.UserInfo) error); ok {
+		r1 = rf(ctx, input)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -4,6 +4,7 @@ package mocks
 
 import (
 	domain "TrafficPolice/internal/domain"
+	context "context"
 
 	mock "github.com/stretchr/testify/mock"
 )
@@ -13,9 +14,9 @@ type CameraService struct {
 	mock.Mock
 }
 
-// AddCameraType provides a mock function with given fields: cameraType
-func (_m *CameraService) AddCameraType(cameraType domain.CameraType) (string, error) {
-	ret := _m.Called(cameraType)
+// AddCameraType provides a mock function with given fields: ctx, cameraType
+func (_m *CameraService) AddCameraType(ctx context.Context, cameraType domain.CameraType) (string, error) {
+	ret := _m.Called(ctx, cameraType)
 
 	if len(ret) == 0 {
 		panic("no return value specified for AddCameraType")
@@ -23,17 +24,17 @@ func (_m *CameraService) AddCameraType(cameraType domain.CameraType) (string, er
 
 	var r0 string
 	var r1 error
-	if rf, ok := ret.Get(0).(func(domain.CameraType) (string, error)); ok {
-		return rf(cameraType)
+	if rf, ok := ret.Get(0).(func(context.Context, domain.CameraType) (string, error)); ok {
+		return rf(ctx, cameraType)
 	}
-	if rf, ok := ret.Get(0).(func(domain.CameraType) string); ok {
-		r0 = rf(cameraType)
+	if rf, ok := ret.Get(0).(func(context.Context, domain.CameraType) string); ok {
+		r0 = rf(ctx, cameraType)
 	} else {
 		r0 = ret.Get(0).(string)
 	}
 
-	if rf, ok := ret.Get(1).(func(domain.CameraType) error); ok {
-		r1 = rf(cameraType)
+	if rf, ok := ret.Get(1).(func(context.Context, domain.CameraType) error); ok {
+		r1 = rf(ctx, cameraType)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -41,9 +42,9 @@ func (_m *CameraService) AddCameraType(cameraType domain.CameraType) (string, er
 	return r0, r1
 }
 
-// GetCameraTypeByCameraID provides a mock function with given fields: cameraID
-func (_m *CameraService) GetCameraTypeByCameraID(cameraID string) (string, error) {
-	ret := _m.Called(cameraID)
+// GetCameraTypeByCameraID provides a mock function with given fields: ctx, cameraID
+func (_m *CameraService) GetCameraTypeByCameraID(ctx context.Context, cameraID string) (string, error) {
+	ret := _m.Called(ctx, cameraID)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetCameraTypeByCameraID")
@@ -51,17 +52,17 @@ func (_m *CameraService) GetCameraTypeByCameraID(cameraID string) (string, error
 
 	var r0 string
 	var r1 error
-	if rf, ok := ret.Get(0).(func(string) (string, error)); ok {
-		return rf(cameraID)
+	if rf, ok := ret.Get(0).(func(context.Context, string) (string, error)); ok {
+		return rf(ctx, cameraID)
 	}
-	if rf, ok := ret.Get(0).(func(string) string); ok {
-		r0 = rf(cameraID)
+	if rf, ok := ret.Get(0).(func(context.Context, string) string); ok {
+		r0 = rf(ctx, cameraID)
 	} else {
 		r0 = ret.Get(0).(string)
 	}
 
-	if rf, ok := ret.Get(1).(func(string) error); ok {
-		r1 = rf(cameraID)
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, cameraID)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -69,6 +70,70 @@ func (_m *CameraService) GetCameraTypeByCameraID(cameraID string) (string, error
 	return r0, r1
 }
 
+// GetCameraTypeMapping provides a mock function with given fields: ctx, cameraTypeName
+func (_m *CameraService) GetCameraTypeMapping(ctx context.Context, cameraTypeName string) (domain.CameraTypeMapping, error) {
+	ret := _m.Called(ctx, cameraTypeName)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetCameraTypeMapping")
+	}
+
+	var r0 domain.CameraTypeMapping
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (domain.CameraTypeMapping, error)); ok {
+		return rf(ctx, cameraTypeName)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) domain.CameraTypeMapping); ok {
+		r0 = rf(ctx, cameraTypeName)
+	} else {
+		r0 = ret.Get(0).(domain.CameraTypeMapping)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, cameraTypeName)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SetCameraRedactRegions provides a mock function with given fields: ctx, cameraID, regions
+func (_m *CameraService) SetCameraRedactRegions(ctx context.Context, cameraID string, regions []domain.RedactRegion) error {
+	ret := _m.Called(ctx, cameraID, regions)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetCameraRedactRegions")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, []domain.RedactRegion) error); ok {
+		r0 = rf(ctx, cameraID, regions)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SetCameraTypeMapping provides a mock function with given fields: ctx, mapping
+func (_m *CameraService) SetCameraTypeMapping(ctx context.Context, mapping domain.CameraTypeMapping) error {
+	ret := _m.Called(ctx, mapping)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetCameraTypeMapping")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, domain.CameraTypeMapping) error); ok {
+		r0 = rf(ctx, mapping)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // NewCameraService creates a new instance of CameraService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewCameraService(t interface {
@@ -4,6 +4,7 @@ package mocks
 
 import (
 	domain "TrafficPolice/internal/domain"
+	context "context"
 
 	mock "github.com/stretchr/testify/mock"
 
@@ -15,9 +16,9 @@ type DirectorService struct {
 	mock.Mock
 }
 
-// GetCase provides a mock function with given fields: caseID
-func (_m *DirectorService) GetCase(caseID string) (domain.CaseStatus, error) {
-	ret := _m.Called(caseID)
+// GetCase provides a mock function with given fields: ctx, caseID
+func (_m *DirectorService) GetCase(ctx context.Context, caseID string) (domain.CaseStatus, error) {
+	ret := _m.Called(ctx, caseID)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetCase")
@@ -25,17 +26,17 @@ func (_m *DirectorService) GetCase(caseID string) (domain.CaseStatus, error) {
 
 	var r0 domain.CaseStatus
 	var r1 error
-	if rf, ok := ret.Get(0).(func(string) (domain.CaseStatus, error)); ok {
-		return rf(caseID)
+	if rf, ok := ret.Get(0).(func(context.Context, string) (domain.CaseStatus, error)); ok {
+		return rf(ctx, caseID)
 	}
-	if rf, ok := ret.Get(0).(func(string) domain.CaseStatus); ok {
-		r0 = rf(caseID)
+	if rf, ok := ret.Get(0).(func(context.Context, string) domain.CaseStatus); ok {
+		r0 = rf(ctx, caseID)
 	} else {
 		r0 = ret.Get(0).(domain.CaseStatus)
 	}
 
-	if rf, ok := ret.Get(1).(func(string) error); ok {
-		r1 = rf(caseID)
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, caseID)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -43,9 +44,9 @@ func (_m *DirectorService) GetCase(caseID string) (domain.CaseStatus, error) {
 	return r0, r1
 }
 
-// GetExpertAnalytics provides a mock function with given fields: expertID, startTime, endTime
-func (_m *DirectorService) GetExpertAnalytics(expertID string, startTime time.Time, endTime time.Time) ([]domain.AnalyticsInterval, error) {
-	ret := _m.Called(expertID, startTime, endTime)
+// GetExpertAnalytics provides a mock function with given fields: ctx, expertID, startTime, endTime
+func (_m *DirectorService) GetExpertAnalytics(ctx context.Context, expertID string, startTime time.Time, endTime time.Time) ([]domain.AnalyticsInterval, error) {
+	ret := _m.Called(ctx, expertID, startTime, endTime)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetExpertAnalytics")
@@ -53,19 +54,19 @@ func (_m *DirectorService) GetExpertAnalytics(expertID string, startTime time.Ti
 
 	var r0 []domain.AnalyticsInterval
 	var r1 error
-	if rf, ok := ret.Get(0).(func(string, time.Time, time.Time) ([]domain.AnalyticsInterval, error)); ok {
-		return rf(expertID, startTime, endTime)
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time, time.Time) ([]domain.AnalyticsInterval, error)); ok {
+		return rf(ctx, expertID, startTime, endTime)
 	}
-	if rf, ok := ret.Get(0).(func(string, time.Time, time.Time) []domain.AnalyticsInterval); ok {
-		r0 = rf(expertID, startTime, endTime)
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time, time.Time) []domain.AnalyticsInterval); ok {
+		r0 = rf(ctx, expertID, startTime, endTime)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]domain.AnalyticsInterval)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(string, time.Time, time.Time) error); ok {
-		r1 = rf(expertID, startTime, endTime)
+	if rf, ok := ret.Get(1).(func(context.Context, string, time.Time, time.Time) error); ok {
+		r1 = rf(ctx, expertID, startTime, endTime)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -73,17 +74,149 @@ func (_m *DirectorService) GetExpertAnalytics(expertID string, startTime time.Ti
 	return r0, r1
 }
 
-// UpdateExpertSkill provides a mock function with given fields: expertID, skill
-func (_m *DirectorService) UpdateExpertSkill(expertID string, skill int) error {
-	ret := _m.Called(expertID, skill)
+// GetReviewCases provides a mock function with given fields: ctx, limit, offset
+func (_m *DirectorService) GetReviewCases(ctx context.Context, limit int, offset int) ([]domain.ReviewCase, error) {
+	ret := _m.Called(ctx, limit, offset)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetReviewCases")
+	}
+
+	var r0 []domain.ReviewCase
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) ([]domain.ReviewCase, error)); ok {
+		return rf(ctx, limit, offset)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) []domain.ReviewCase); ok {
+		r0 = rf(ctx, limit, offset)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.ReviewCase)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, int) error); ok {
+		r1 = rf(ctx, limit, offset)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewNotificationToken provides a mock function with given fields: ctx, userID
+func (_m *DirectorService) NewNotificationToken(ctx context.Context, userID string) (string, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for NewNotificationToken")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (string, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) string); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// OverrideCaseDecision provides a mock function with given fields: ctx, userID, caseID, fineDecision, reason
+func (_m *DirectorService) OverrideCaseDecision(ctx context.Context, userID string, caseID string, fineDecision bool, reason string) (domain.CaseOverride, error) {
+	ret := _m.Called(ctx, userID, caseID, fineDecision, reason)
+
+	if len(ret) == 0 {
+		panic("no return value specified for OverrideCaseDecision")
+	}
+
+	var r0 domain.CaseOverride
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, bool, string) (domain.CaseOverride, error)); ok {
+		return rf(ctx, userID, caseID, fineDecision, reason)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, bool, string) domain.CaseOverride); ok {
+		r0 = rf(ctx, userID, caseID, fineDecision, reason)
+	} else {
+		r0 = ret.Get(0).(domain.CaseOverride)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, bool, string) error); ok {
+		r1 = rf(ctx, userID, caseID, fineDecision, reason)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ReopenCase provides a mock function with given fields: ctx, userID, caseID, reason
+func (_m *DirectorService) ReopenCase(ctx context.Context, userID string, caseID string, reason string) (domain.CaseOverride, error) {
+	ret := _m.Called(ctx, userID, caseID, reason)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReopenCase")
+	}
+
+	var r0 domain.CaseOverride
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) (domain.CaseOverride, error)); ok {
+		return rf(ctx, userID, caseID, reason)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) domain.CaseOverride); ok {
+		r0 = rf(ctx, userID, caseID, reason)
+	} else {
+		r0 = ret.Get(0).(domain.CaseOverride)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, string) error); ok {
+		r1 = rf(ctx, userID, caseID, reason)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ResolveReviewCase provides a mock function with given fields: ctx, caseID, fineDecision
+func (_m *DirectorService) ResolveReviewCase(ctx context.Context, caseID string, fineDecision bool) error {
+	ret := _m.Called(ctx, caseID, fineDecision)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ResolveReviewCase")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, bool) error); ok {
+		r0 = rf(ctx, caseID, fineDecision)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateExpertSkill provides a mock function with given fields: ctx, expertID, skill
+func (_m *DirectorService) UpdateExpertSkill(ctx context.Context, expertID string, skill int) error {
+	ret := _m.Called(ctx, expertID, skill)
 
 	if len(ret) == 0 {
 		panic("no return value specified for UpdateExpertSkill")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(string, int) error); ok {
-		r0 = rf(expertID, skill)
+	if rf, ok := ret.Get(0).(func(context.Context, string, int) error); ok {
+		r0 = rf(ctx, expertID, skill)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -91,6 +224,34 @@ func (_m *DirectorService) UpdateExpertSkill(expertID string, skill int) error {
 	return r0
 }
 
+// VoidCaseFine provides a mock function with given fields: ctx, userID, caseID, reason
+func (_m *DirectorService) VoidCaseFine(ctx context.Context, userID string, caseID string, reason string) (domain.CaseOverride, error) {
+	ret := _m.Called(ctx, userID, caseID, reason)
+
+	if len(ret) == 0 {
+		panic("no return value specified for VoidCaseFine")
+	}
+
+	var r0 domain.CaseOverride
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) (domain.CaseOverride, error)); ok {
+		return rf(ctx, userID, caseID, reason)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) domain.CaseOverride); ok {
+		r0 = rf(ctx, userID, caseID, reason)
+	} else {
+		r0 = ret.Get(0).(domain.CaseOverride)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, string) error); ok {
+		r1 = rf(ctx, userID, caseID, reason)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // NewDirectorService creates a new instance of DirectorService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewDirectorService(t interface {
@@ -4,6 +4,7 @@ package mocks
 
 import (
 	domain "TrafficPolice/internal/domain"
+	context "context"
 
 	mock "github.com/stretchr/testify/mock"
 )
@@ -13,27 +14,27 @@ type ExpertService struct {
 	mock.Mock
 }
 
-// GetCase provides a mock function with given fields: userID
-func (_m *ExpertService) GetCase(userID string) (domain.Case, error) {
-	ret := _m.Called(userID)
+// GetCase provides a mock function with given fields: ctx, userID
+func (_m *ExpertService) GetCase(ctx context.Context, userID string) (domain.AssignedCase, error) {
+	ret := _m.Called(ctx, userID)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetCase")
 	}
 
-	var r0 domain.Case
+	var r0 domain.AssignedCase
 	var r1 error
-	if rf, ok := ret.Get(0).(func(string) (domain.Case, error)); ok {
-		return rf(userID)
+	if rf, ok := ret.Get(0).(func(context.Context, string) (domain.AssignedCase, error)); ok {
+		return rf(ctx, userID)
 	}
-	if rf, ok := ret.Get(0).(func(string) domain.Case); ok {
-		r0 = rf(userID)
+	if rf, ok := ret.Get(0).(func(context.Context, string) domain.AssignedCase); ok {
+		r0 = rf(ctx, userID)
 	} else {
-		r0 = ret.Get(0).(domain.Case)
+		r0 = ret.Get(0).(domain.AssignedCase)
 	}
 
-	if rf, ok := ret.Get(1).(func(string) error); ok {
-		r1 = rf(userID)
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, userID)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -41,9 +42,9 @@ func (_m *ExpertService) GetCase(userID string) (domain.Case, error) {
 	return r0, r1
 }
 
-// GetCaseWithPersonInfo provides a mock function with given fields: caseID
-func (_m *ExpertService) GetCaseWithPersonInfo(caseID string) (domain.Case, error) {
-	ret := _m.Called(caseID)
+// GetCaseWithPersonInfo provides a mock function with given fields: ctx, caseID
+func (_m *ExpertService) GetCaseWithPersonInfo(ctx context.Context, caseID string) (domain.Case, error) {
+	ret := _m.Called(ctx, caseID)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetCaseWithPersonInfo")
@@ -51,17 +52,17 @@ func (_m *ExpertService) GetCaseWithPersonInfo(caseID string) (domain.Case, erro
 
 	var r0 domain.Case
 	var r1 error
-	if rf, ok := ret.Get(0).(func(string) (domain.Case, error)); ok {
-		return rf(caseID)
+	if rf, ok := ret.Get(0).(func(context.Context, string) (domain.Case, error)); ok {
+		return rf(ctx, caseID)
 	}
-	if rf, ok := ret.Get(0).(func(string) domain.Case); ok {
-		r0 = rf(caseID)
+	if rf, ok := ret.Get(0).(func(context.Context, string) domain.Case); ok {
+		r0 = rf(ctx, caseID)
 	} else {
 		r0 = ret.Get(0).(domain.Case)
 	}
 
-	if rf, ok := ret.Get(1).(func(string) error); ok {
-		r1 = rf(caseID)
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, caseID)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -69,9 +70,9 @@ func (_m *ExpertService) GetCaseWithPersonInfo(caseID string) (domain.Case, erro
 	return r0, r1
 }
 
-// GetExpertByUserID provides a mock function with given fields: userID
-func (_m *ExpertService) GetExpertByUserID(userID string) (domain.Expert, error) {
-	ret := _m.Called(userID)
+// GetExpertByUserID provides a mock function with given fields: ctx, userID
+func (_m *ExpertService) GetExpertByUserID(ctx context.Context, userID string) (domain.Expert, error) {
+	ret := _m.Called(ctx, userID)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetExpertByUserID")
@@ -79,17 +80,17 @@ func (_m *ExpertService) GetExpertByUserID(userID string) (domain.Expert, error)
 
 	var r0 domain.Expert
 	var r1 error
-	if rf, ok := ret.Get(0).(func(string) (domain.Expert, error)); ok {
-		return rf(userID)
+	if rf, ok := ret.Get(0).(func(context.Context, string) (domain.Expert, error)); ok {
+		return rf(ctx, userID)
 	}
-	if rf, ok := ret.Get(0).(func(string) domain.Expert); ok {
-		r0 = rf(userID)
+	if rf, ok := ret.Get(0).(func(context.Context, string) domain.Expert); ok {
+		r0 = rf(ctx, userID)
 	} else {
 		r0 = ret.Get(0).(domain.Expert)
 	}
 
-	if rf, ok := ret.Get(1).(func(string) error); ok {
-		r1 = rf(userID)
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, userID)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -97,9 +98,14 @@ func (_m *ExpertService) GetExpertByUserID(userID string) (domain.Expert, error)
 	return r0, r1
 }
 
-// SetCaseDecision provides a mock function with given fields: decision
-func (_m *ExpertService) SetCaseDecision(decision domain.Decision) (domain.CaseDecisionInfo, error) {
-	ret := _m.Called(decision)
+// RunLeaseReaper provides a mock function with given fields: done
+func (_m *ExpertService) RunLeaseReaper(done <-chan struct{}) {
+	_m.Called(done)
+}
+
+// SetCaseDecision provides a mock function with given fields: ctx, decision
+func (_m *ExpertService) SetCaseDecision(ctx context.Context, decision domain.Decision) (domain.CaseDecisionInfo, error) {
+	ret := _m.Called(ctx, decision)
 
 	if len(ret) == 0 {
 		panic("no return value specified for SetCaseDecision")
@@ -107,17 +113,17 @@ func (_m *ExpertService) SetCaseDecision(decision domain.Decision) (domain.CaseD
 
 	var r0 domain.CaseDecisionInfo
 	var r1 error
-	if rf, ok := ret.Get(0).(func(domain.Decision) (domain.CaseDecisionInfo, error)); ok {
-		return rf(decision)
+	if rf, ok := ret.Get(0).(func(context.Context, domain.Decision) (domain.CaseDecisionInfo, error)); ok {
+		return rf(ctx, decision)
 	}
-	if rf, ok := ret.Get(0).(func(domain.Decision) domain.CaseDecisionInfo); ok {
-		r0 = rf(decision)
+	if rf, ok := ret.Get(0).(func(context.Context, domain.Decision) domain.CaseDecisionInfo); ok {
+		r0 = rf(ctx, decision)
 	} else {
 		r0 = ret.Get(0).(domain.CaseDecisionInfo)
 	}
 
-	if rf, ok := ret.Get(1).(func(domain.Decision) error); ok {
-		r1 = rf(decision)
+	if rf, ok := ret.Get(1).(func(context.Context, domain.Decision) error); ok {
+		r1 = rf(ctx, decision)
 	} else {
 		r1 = ret.Error(1)
 	}
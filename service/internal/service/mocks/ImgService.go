@@ -2,34 +2,59 @@
 
 package mocks
 
-import mock "github.com/stretchr/testify/mock"
+import (
+	domain "TrafficPolice/internal/domain"
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+
+	repository "TrafficPolice/internal/repository"
+)
 
 // ImgService is an autogenerated mock type for the ImgService type
 type ImgService struct {
 	mock.Mock
 }
 
-// GetImgFilePath provides a mock function with given fields: dir, id
-func (_m *ImgService) GetImgFilePath(dir string, id string) (string, error) {
-	ret := _m.Called(dir, id)
+// DeleteImg provides a mock function with given fields: ctx, owner
+func (_m *ImgService) DeleteImg(ctx context.Context, owner domain.ImageOwner) error {
+	ret := _m.Called(ctx, owner)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteImg")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, domain.ImageOwner) error); ok {
+		r0 = rf(ctx, owner)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetImg provides a mock function with given fields: ctx, owner, variant
+func (_m *ImgService) GetImg(ctx context.Context, owner domain.ImageOwner, variant domain.ImageVariant) (domain.Image, error) {
+	ret := _m.Called(ctx, owner, variant)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetImgFilePath")
+		panic("no return value specified for GetImg")
 	}
 
-	var r0 string
+	var r0 domain.Image
 	var r1 error
-	if rf, ok := ret.Get(0).(func(string, string) (string, error)); ok {
-		return rf(dir, id)
+	if rf, ok := ret.Get(0).(func(context.Context, domain.ImageOwner, domain.ImageVariant) (domain.Image, error)); ok {
+		return rf(ctx, owner, variant)
 	}
-	if rf, ok := ret.Get(0).(func(string, string) string); ok {
-		r0 = rf(dir, id)
+	if rf, ok := ret.Get(0).(func(context.Context, domain.ImageOwner, domain.ImageVariant) domain.Image); ok {
+		r0 = rf(ctx, owner, variant)
 	} else {
-		r0 = ret.Get(0).(string)
+		r0 = ret.Get(0).(domain.Image)
 	}
 
-	if rf, ok := ret.Get(1).(func(string, string) error); ok {
-		r1 = rf(dir, id)
+	if rf, ok := ret.Get(1).(func(context.Context, domain.ImageOwner, domain.ImageVariant) error); ok {
+		r1 = rf(ctx, owner, variant)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -37,17 +62,35 @@ func (_m *ImgService) GetImgFilePath(dir string, id string) (string, error) {
 	return r0, r1
 }
 
-// SaveImg provides a mock function with given fields: img, filepath
-func (_m *ImgService) SaveImg(img []byte, filepath string) error {
-	ret := _m.Called(img, filepath)
+// SaveImg provides a mock function with given fields: ctx, owner, variant, img
+func (_m *ImgService) SaveImg(ctx context.Context, owner domain.ImageOwner, variant domain.ImageVariant, img []byte) error {
+	ret := _m.Called(ctx, owner, variant, img)
 
 	if len(ret) == 0 {
 		panic("no return value specified for SaveImg")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func([]byte, string) error); ok {
-		r0 = rf(img, filepath)
+	if rf, ok := ret.Get(0).(func(context.Context, domain.ImageOwner, domain.ImageVariant, []byte) error); ok {
+		r0 = rf(ctx, owner, variant, img)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SaveImgs provides a mock function with given fields: ctx, owner, imgs, inTx
+func (_m *ImgService) SaveImgs(ctx context.Context, owner domain.ImageOwner, imgs map[domain.ImageVariant][]byte, inTx func(repository.TxRepos) error) error {
+	ret := _m.Called(ctx, owner, imgs, inTx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SaveImgs")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, domain.ImageOwner, map[domain.ImageVariant][]byte, func(repository.TxRepos) error) error); ok {
+		r0 = rf(ctx, owner, imgs, inTx)
 	} else {
 		r0 = ret.Error(0)
 	}
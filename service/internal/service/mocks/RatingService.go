@@ -4,6 +4,7 @@ package mocks
 
 import (
 	domain "TrafficPolice/internal/domain"
+	context "context"
 
 	mock "github.com/stretchr/testify/mock"
 )
@@ -13,9 +14,9 @@ type RatingService struct {
 	mock.Mock
 }
 
-// GetRating provides a mock function with given fields:
-func (_m *RatingService) GetRating() ([]domain.RatingInfo, error) {
-	ret := _m.Called()
+// GetRating provides a mock function with given fields: ctx
+func (_m *RatingService) GetRating(ctx context.Context) ([]domain.RatingInfo, error) {
+	ret := _m.Called(ctx)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetRating")
@@ -23,19 +24,19 @@ func (_m *RatingService) GetRating() ([]domain.RatingInfo, error) {
 
 	var r0 []domain.RatingInfo
 	var r1 error
-	if rf, ok := ret.Get(0).(func() ([]domain.RatingInfo, error)); ok {
-		return rf()
+	if rf, ok := ret.Get(0).(func(context.Context) ([]domain.RatingInfo, error)); ok {
+		return rf(ctx)
 	}
-	if rf, ok := ret.Get(0).(func() []domain.RatingInfo); ok {
-		r0 = rf()
+	if rf, ok := ret.Get(0).(func(context.Context) []domain.RatingInfo); ok {
+		r0 = rf(ctx)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]domain.RatingInfo)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func() error); ok {
-		r1 = rf()
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -48,24 +49,6 @@ func (_m *RatingService) RunReportPeriod(done <-chan struct{}) {
 	_m.Called(done)
 }
 
-// SetRating provides a mock function with given fields: caseDecision
-func (_m *RatingService) SetRating(caseDecision domain.CaseDecisionInfo) error {
-	ret := _m.Called(caseDecision)
-
-	if len(ret) == 0 {
-		panic("no return value specified for SetRating")
-	}
-
-	var r0 error
-	if rf, ok := ret.Get(0).(func(domain.CaseDecisionInfo) error); ok {
-		r0 = rf(caseDecision)
-	} else {
-		r0 = ret.Error(0)
-	}
-
-	return r0
-}
-
 // NewRatingService creates a new instance of RatingService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewRatingService(t interface {
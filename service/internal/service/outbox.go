@@ -0,0 +1,87 @@
+package service
+
+import (
+	"TrafficPolice/internal/config"
+	"TrafficPolice/internal/domain"
+	"TrafficPolice/internal/errs"
+	"TrafficPolice/internal/repository"
+	"context"
+	"time"
+)
+
+//go:generate go run github.com/vektra/mockery/v2@v2.42.1 --name OutboxService
+type OutboxService interface {
+	ClaimPendingMessages(ctx context.Context) ([]domain.OutboxMessage, error)
+	MarkPublished(ctx context.Context, messageID string) error
+	MarkAttemptFailed(ctx context.Context, msg domain.OutboxMessage, publishErr error) error
+	GetProblemMessages(ctx context.Context, limit int) ([]domain.OutboxMessage, error)
+	ReplayMessage(ctx context.Context, messageID string) error
+}
+
+type outboxService struct {
+	outboxRepo repository.OutboxRepo
+	outboxCfg  config.OutboxConfig
+}
+
+func NewOutboxService(outboxRepo repository.OutboxRepo, outboxCfg config.OutboxConfig) OutboxService {
+	return &outboxService{
+		outboxRepo: outboxRepo,
+		outboxCfg:  outboxCfg,
+	}
+}
+
+// ClaimPendingMessages returns messages, which are ready for next attempt. Claimed messages are hidden
+// from other relays during claim timeout, so message is published again only when relay did not
+// report result of attempt
+func (s *outboxService) ClaimPendingMessages(ctx context.Context) ([]domain.OutboxMessage, error) {
+	now := time.Now()
+	return s.outboxRepo.ClaimPendingMessages(ctx, now, now.Add(s.outboxCfg.ClaimTimeout), s.outboxCfg.BatchSize)
+}
+
+func (s *outboxService) MarkPublished(ctx context.Context, messageID string) error {
+	return s.outboxRepo.MarkPublished(ctx, messageID, time.Now())
+}
+
+// MarkAttemptFailed delays next attempt of message with exponential backoff. Message becomes failed,
+// when all attempts are used
+func (s *outboxService) MarkAttemptFailed(ctx context.Context, msg domain.OutboxMessage, publishErr error) error {
+	now := time.Now()
+	lastError := publishErr.Error()
+
+	msg.Attempts++
+	msg.LastError = &lastError
+	msg.NextAttemptAt = now.Add(s.backoff(msg.Attempts))
+	if msg.Attempts >= s.outboxCfg.MaxAttempts {
+		msg.FailedAt = &now
+	}
+
+	return s.outboxRepo.MarkAttemptFailed(ctx, msg)
+}
+
+// backoff doubles delay after every failed attempt from min backoff up to max backoff
+func (s *outboxService) backoff(attempts int) time.Duration {
+	delay := s.outboxCfg.MinBackoff
+	for i := 1; i < attempts && delay < s.outboxCfg.MaxBackoff; i++ {
+		delay *= 2
+	}
+
+	return min(delay, s.outboxCfg.MaxBackoff)
+}
+
+// GetProblemMessages returns failed messages and messages, which are not published during stuck period
+func (s *outboxService) GetProblemMessages(ctx context.Context, limit int) ([]domain.OutboxMessage, error) {
+	messages, err := s.outboxRepo.GetProblemMessages(ctx, time.Now().Add(-s.outboxCfg.StuckAfter), limit)
+	if err != nil {
+		return nil, err
+	}
+	if len(messages) == 0 {
+		return nil, errs.ErrNoRows
+	}
+
+	return messages, nil
+}
+
+// ReplayMessage resets attempts of not published message, so relay publishes it on next run
+func (s *outboxService) ReplayMessage(ctx context.Context, messageID string) error {
+	return s.outboxRepo.ReplayMessage(ctx, messageID, time.Now())
+}
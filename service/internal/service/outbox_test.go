@@ -0,0 +1,106 @@
+package service
+
+import (
+	"TrafficPolice/internal/config"
+	"TrafficPolice/internal/domain"
+	"TrafficPolice/internal/errs"
+	"TrafficPolice/internal/repository/mocks"
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"testing"
+	"time"
+)
+
+func TestMarkAttemptFailed(t *testing.T) {
+	outboxCfg := config.OutboxConfig{MaxAttempts: 6, MinBackoff: time.Second, MaxBackoff: 10 * time.Second}
+	publishErr := errors.New("connection refused")
+
+	testCases := []struct {
+		name            string
+		attempts        int
+		expectedBackoff time.Duration
+		expectFailed    bool
+	}{
+		{
+			name:            "First failed attempt. Min backoff",
+			attempts:        0,
+			expectedBackoff: time.Second,
+		},
+		{
+			name:            "Third failed attempt. Backoff is doubled",
+			attempts:        2,
+			expectedBackoff: 4 * time.Second,
+		},
+		{
+			name:            "Fifth failed attempt. Backoff is limited by max backoff",
+			attempts:        4,
+			expectedBackoff: 10 * time.Second,
+		},
+		{
+			name:            "Last attempt. Message is failed",
+			attempts:        5,
+			expectedBackoff: 10 * time.Second,
+			expectFailed:    true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var actual domain.OutboxMessage
+			outboxRepo := mocks.NewOutboxRepo(t)
+			outboxRepo.On("MarkAttemptFailed", mock.Anything, mock.Anything).
+				Run(func(args mock.Arguments) {
+					actual = args.Get(1).(domain.OutboxMessage)
+				}).
+				Return(nil)
+
+			s := NewOutboxService(outboxRepo, outboxCfg)
+			before := time.Now()
+			err := s.MarkAttemptFailed(context.Background(), domain.OutboxMessage{ID: "id", Attempts: tc.attempts},
+				publishErr)
+			assert.NoError(t, err)
+
+			assert.Equal(t, tc.attempts+1, actual.Attempts)
+			assert.Equal(t, publishErr.Error(), *actual.LastError)
+			assert.WithinDuration(t, before.Add(tc.expectedBackoff), actual.NextAttemptAt, time.Second)
+			assert.Equal(t, tc.expectFailed, actual.FailedAt != nil)
+		})
+	}
+}
+
+func TestGetProblemMessages(t *testing.T) {
+	outboxCfg := config.OutboxConfig{StuckAfter: time.Hour}
+
+	testCases := []struct {
+		name        string
+		messages    []domain.OutboxMessage
+		expectedErr error
+	}{
+		{
+			name:        "Problem messages",
+			messages:    []domain.OutboxMessage{{ID: "id"}},
+			expectedErr: nil,
+		},
+		{
+			name:        "No problem messages",
+			messages:    []domain.OutboxMessage{},
+			expectedErr: errs.ErrNoRows,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			outboxRepo := mocks.NewOutboxRepo(t)
+			outboxRepo.On("GetProblemMessages", mock.Anything, mock.MatchedBy(func(stuckBefore time.Time) bool {
+				return time.Since(stuckBefore) >= outboxCfg.StuckAfter
+			}), 10).
+				Return(tc.messages, nil)
+
+			s := NewOutboxService(outboxRepo, outboxCfg)
+			_, err := s.GetProblemMessages(context.Background(), 10)
+			assert.Equal(t, tc.expectedErr, err)
+		})
+	}
+}
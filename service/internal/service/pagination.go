@@ -3,10 +3,15 @@ package service
 import (
 	"TrafficPolice/internal/domain"
 	"TrafficPolice/internal/repository"
+	"context"
 )
 
 type PaginationService interface {
-	GetPaginationInfo(table string, paginationParams domain.PaginationParams) (domain.Pagination, error)
+	GetPaginationInfo(
+		ctx context.Context,
+		table string,
+		paginationParams domain.PaginationParams,
+	) (domain.Pagination, error)
 }
 
 type paginationService struct {
@@ -20,10 +25,11 @@ func NewPaginationService(paginationRepo repository.PaginationRepo) PaginationSe
 }
 
 func (s *paginationService) GetPaginationInfo(
+	ctx context.Context,
 	table string,
 	paginationParams domain.PaginationParams,
 ) (domain.Pagination, error) {
-	recordsCount, err := s.paginationRepo.GetRecordsCount(table)
+	recordsCount, err := s.paginationRepo.GetRecordsCount(ctx, table)
 	if err != nil {
 		return domain.Pagination{}, err
 	}
@@ -4,8 +4,10 @@ import (
 	"TrafficPolice/internal/domain"
 	"TrafficPolice/internal/repository"
 	"TrafficPolice/internal/repository/mocks"
+	"context"
 	"errors"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	"testing"
 )
 
@@ -25,7 +27,7 @@ func TestGetPaginationInfo(t *testing.T) {
 			buildPaginationRepo: func() repository.PaginationRepo {
 				mockRepo := mocks.NewPaginationRepo(t)
 
-				mockRepo.On("GetRecordsCount", tableName).
+				mockRepo.On("GetRecordsCount", mock.Anything, tableName).
 					Return(100, nil)
 
 				return mockRepo
@@ -45,7 +47,7 @@ func TestGetPaginationInfo(t *testing.T) {
 			buildPaginationRepo: func() repository.PaginationRepo {
 				mockRepo := mocks.NewPaginationRepo(t)
 
-				mockRepo.On("GetRecordsCount", tableName).
+				mockRepo.On("GetRecordsCount", mock.Anything, tableName).
 					Return(100, nil)
 
 				return mockRepo
@@ -65,7 +67,7 @@ func TestGetPaginationInfo(t *testing.T) {
 			buildPaginationRepo: func() repository.PaginationRepo {
 				mockRepo := mocks.NewPaginationRepo(t)
 
-				mockRepo.On("GetRecordsCount", tableName).
+				mockRepo.On("GetRecordsCount", mock.Anything, tableName).
 					Return(100, nil)
 
 				return mockRepo
@@ -85,7 +87,7 @@ func TestGetPaginationInfo(t *testing.T) {
 			buildPaginationRepo: func() repository.PaginationRepo {
 				mockRepo := mocks.NewPaginationRepo(t)
 
-				mockRepo.On("GetRecordsCount", tableName).
+				mockRepo.On("GetRecordsCount", mock.Anything, tableName).
 					Return(100, nil)
 
 				return mockRepo
@@ -105,7 +107,7 @@ func TestGetPaginationInfo(t *testing.T) {
 			buildPaginationRepo: func() repository.PaginationRepo {
 				mockRepo := mocks.NewPaginationRepo(t)
 
-				mockRepo.On("GetRecordsCount", tableName).
+				mockRepo.On("GetRecordsCount", mock.Anything, tableName).
 					Return(101, nil)
 
 				return mockRepo
@@ -125,7 +127,7 @@ func TestGetPaginationInfo(t *testing.T) {
 			buildPaginationRepo: func() repository.PaginationRepo {
 				mockRepo := mocks.NewPaginationRepo(t)
 
-				mockRepo.On("GetRecordsCount", tableName).
+				mockRepo.On("GetRecordsCount", mock.Anything, tableName).
 					Return(100, nil)
 
 				return mockRepo
@@ -145,7 +147,7 @@ func TestGetPaginationInfo(t *testing.T) {
 			buildPaginationRepo: func() repository.PaginationRepo {
 				mockRepo := mocks.NewPaginationRepo(t)
 
-				mockRepo.On("GetRecordsCount", tableName).
+				mockRepo.On("GetRecordsCount", mock.Anything, tableName).
 					Return(0, errRowsCnt)
 
 				return mockRepo
@@ -161,7 +163,7 @@ func TestGetPaginationInfo(t *testing.T) {
 			paginationRepo := tc.buildPaginationRepo()
 			paginationService := NewPaginationService(paginationRepo)
 
-			pagination, err := paginationService.GetPaginationInfo(tableName, tc.paginationParams)
+			pagination, err := paginationService.GetPaginationInfo(context.Background(), tableName, tc.paginationParams)
 			assert.Equal(t, tc.expectedErr, err)
 			assert.Equal(t, tc.expectedPagination, pagination)
 		})
@@ -5,6 +5,7 @@ import (
 	"TrafficPolice/internal/domain"
 	"TrafficPolice/internal/errs"
 	"TrafficPolice/internal/repository"
+	"context"
 	"log"
 	"math"
 	"sort"
@@ -13,37 +14,33 @@ import (
 
 //go:generate go run github.com/vektra/mockery/v2@v2.42.1 --name RatingService
 type RatingService interface {
-	SetRating(caseDecision domain.CaseDecisionInfo) error
-	GetRating() ([]domain.RatingInfo, error)
+	GetRating(ctx context.Context) ([]domain.RatingInfo, error)
 	RunReportPeriod(done <-chan struct{})
 }
 
 type ratingService struct {
 	ratingRepo repository.RatingRepo
+	uow        repository.UnitOfWork
+	events     EventPublisher
 	ratingCfg  config.RatingConfig
 }
 
 func NewRatingService(
 	ratingRepo repository.RatingRepo,
+	uow repository.UnitOfWork,
+	events EventPublisher,
 	ratingCfg config.RatingConfig,
 ) RatingService {
 	return &ratingService{
 		ratingRepo: ratingRepo,
+		uow:        uow,
+		events:     events,
 		ratingCfg:  ratingCfg,
 	}
 }
 
-func (s *ratingService) SetRating(caseDecision domain.CaseDecisionInfo) error {
-	solvedDecisions, err := s.ratingRepo.GetSolvedCaseDecisions(caseDecision)
-	if err != nil {
-		return err
-	}
-
-	return s.ratingRepo.SetRating(solvedDecisions)
-}
-
-func (s *ratingService) GetRating() ([]domain.RatingInfo, error) {
-	rating, err := s.ratingRepo.GetRating()
+func (s *ratingService) GetRating(ctx context.Context) ([]domain.RatingInfo, error) {
+	rating, err := s.ratingRepo.GetRating(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -63,7 +60,7 @@ func (s *ratingService) RunReportPeriod(done <-chan struct{}) {
 		select {
 		case <-ticker.C:
 			log.Println("Run report period")
-			err := s.setupCompetenceSkill()
+			err := s.setupCompetenceSkill(context.Background())
 			if err != nil {
 				log.Println(err)
 			}
@@ -74,8 +71,10 @@ func (s *ratingService) RunReportPeriod(done <-chan struct{}) {
 	}
 }
 
-func (s *ratingService) setupCompetenceSkill() error {
-	ratings, err := s.ratingRepo.GetExpertsRating(s.ratingCfg.MinSolvedCases)
+// setupCompetenceSkill raises skill of top 10% of experts and lowers skill of last 10%. Skills, cleared rating
+// and events about changed skills are committed together
+func (s *ratingService) setupCompetenceSkill(ctx context.Context) error {
+	ratings, err := s.ratingRepo.GetExpertsRating(ctx, s.ratingCfg.MinSolvedCases)
 	if err != nil {
 		return err
 	}
@@ -105,9 +104,33 @@ func (s *ratingService) setupCompetenceSkill() error {
 		skills = append(skills, s)
 	}
 
-	err = s.ratingRepo.UpdateCompetenceSkills(skills)
-	if err != nil {
-		return err
+	return s.uow.Do(ctx, func(repos repository.TxRepos) error {
+		changes, err := repos.Rating.UpdateCompetenceSkills(ctx, skills)
+		if err != nil {
+			return err
+		}
+		err = repos.Rating.ClearRating(ctx)
+		if err != nil {
+			return err
+		}
+
+		return s.events.Publish(ctx, repos.Outbox, skillChangedEvents(changes, domain.ReportPeriodSkillChange)...)
+	})
+}
+
+// skillChangedEvents returns events about changes, which have changed skill of expert
+func skillChangedEvents(changes []domain.SkillChange, reason domain.SkillChangeReason) []domain.Event {
+	events := make([]domain.Event, 0, len(changes))
+	for _, change := range changes {
+		if change.OldSkill == change.NewSkill {
+			continue
+		}
+		events = append(events, domain.ExpertSkillChanged{
+			ExpertID: change.ExpertID,
+			OldSkill: change.OldSkill,
+			NewSkill: change.NewSkill,
+			Reason:   reason,
+		})
 	}
-	return s.ratingRepo.ClearRating()
+	return events
 }
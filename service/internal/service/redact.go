@@ -0,0 +1,59 @@
+package service
+
+import (
+	"TrafficPolice/internal/domain"
+	"TrafficPolice/internal/errs"
+	"TrafficPolice/pkg/imgproc"
+	"bytes"
+	"fmt"
+	"image"
+	"image/jpeg"
+)
+
+const (
+	// WebImgSide is max width and height of web variant of case image in pixels
+	WebImgSide = 1280
+	// ThumbImgSide is max width and height of thumbnail of case image in pixels
+	ThumbImgSide = 320
+
+	redactedImgQuality = 90
+	derivedImgQuality  = 80
+)
+
+// deriveCaseImgs generates derived variants of original case image. Image is decoded and encoded again
+// as jpeg, so EXIF and GPS metadata of original are not copied. Redact regions are blurred before
+// downscaling, so they are blurred in every derived variant
+func deriveCaseImgs(original []byte, regions []domain.RedactRegion) (map[domain.ImageVariant][]byte, error) {
+	decoded, _, err := image.Decode(bytes.NewReader(original))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", errs.ErrInvalidImage, err)
+	}
+
+	redacted := imgproc.ToRGBA(decoded)
+	for _, r := range regions {
+		imgproc.BlurRect(redacted, image.Rect(r.X, r.Y, r.X+r.Width, r.Y+r.Height))
+	}
+
+	variants := map[domain.ImageVariant]image.Image{
+		domain.RedactedImage: redacted,
+		domain.WebImage:      imgproc.Fit(redacted, WebImgSide),
+		domain.ThumbImage:    imgproc.Fit(redacted, ThumbImgSide),
+	}
+
+	encoded := make(map[domain.ImageVariant][]byte, len(variants))
+	for variant, img := range variants {
+		quality := derivedImgQuality
+		if variant == domain.RedactedImage {
+			quality = redactedImgQuality
+		}
+
+		var buf bytes.Buffer
+		err = jpeg.Encode(&buf, img, &jpeg.Options{Quality: quality})
+		if err != nil {
+			return nil, err
+		}
+		encoded[variant] = buf.Bytes()
+	}
+
+	return encoded, nil
+}
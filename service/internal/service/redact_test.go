@@ -0,0 +1,86 @@
+package service
+
+import (
+	"TrafficPolice/internal/domain"
+	"TrafficPolice/internal/errs"
+	"bytes"
+	"encoding/binary"
+	"github.com/stretchr/testify/assert"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"testing"
+)
+
+// jpegWithExif encodes image to jpeg and inserts APP1 segment with EXIF after SOI marker
+func jpegWithExif(t *testing.T, img image.Image, exif string) []byte {
+	var buf bytes.Buffer
+	err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: 100})
+	assert.NoError(t, err)
+	encoded := buf.Bytes()
+
+	payload := append([]byte("Exif\x00\x00"), exif...)
+	segment := []byte{0xFF, 0xE1, 0, 0}
+	binary.BigEndian.PutUint16(segment[2:], uint16(len(payload)+2))
+	segment = append(segment, payload...)
+
+	result := append([]byte{}, encoded[:2]...)
+	result = append(result, segment...)
+	return append(result, encoded[2:]...)
+}
+
+func stripes(width, height int) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			if (x/4)%2 == 0 {
+				img.Set(x, y, color.White)
+			} else {
+				img.Set(x, y, color.Black)
+			}
+		}
+	}
+	return img
+}
+
+func TestDeriveCaseImgs(t *testing.T) {
+	original := jpegWithExif(t, stripes(2000, 1000), "GPSLatitude=55.75")
+	region := domain.RedactRegion{X: 0, Y: 0, Width: 400, Height: 400}
+
+	variants, err := deriveCaseImgs(original, []domain.RedactRegion{region})
+	assert.NoError(t, err)
+
+	expectedBounds := map[domain.ImageVariant]image.Rectangle{
+		domain.RedactedImage: image.Rect(0, 0, 2000, 1000),
+		domain.WebImage:      image.Rect(0, 0, WebImgSide, 640),
+		domain.ThumbImage:    image.Rect(0, 0, ThumbImgSide, 160),
+	}
+	assert.Len(t, variants, len(expectedBounds))
+
+	for variant, bounds := range expectedBounds {
+		data := variants[variant]
+		assert.NotContains(t, string(data), "Exif", "variant %s", variant)
+		assert.NotContains(t, string(data), "GPS", "variant %s", variant)
+
+		img, err := jpeg.Decode(bytes.NewReader(data))
+		assert.NoError(t, err)
+		assert.Equal(t, bounds, img.Bounds(), "variant %s", variant)
+	}
+
+	redacted, err := jpeg.Decode(bytes.NewReader(variants[domain.RedactedImage]))
+	assert.NoError(t, err)
+	// stripes are blurred to gray inside region and stay black and white outside of it
+	for x := 100; x < 108; x++ {
+		r, _, _, _ := redacted.At(x, 200).RGBA()
+		assert.InDelta(t, 0x7fff, int(r), 0x2000, "pixel %d,200 in region", x)
+	}
+	r, _, _, _ := redacted.At(1001, 800).RGBA()
+	assert.Greater(t, int(r), 0xe000)
+	r, _, _, _ = redacted.At(1005, 800).RGBA()
+	assert.Less(t, int(r), 0x2000)
+}
+
+func TestDeriveCaseImgsInvalidImage(t *testing.T) {
+	_, err := deriveCaseImgs([]byte("not image"), nil)
+	assert.ErrorIs(t, err, errs.ErrInvalidImage)
+}
@@ -3,10 +3,15 @@ package service
 import (
 	"TrafficPolice/internal/domain"
 	"TrafficPolice/internal/repository"
+	"context"
 )
 
 type TrainingService interface {
-	GetSolvedCasesByParams(params domain.SolvedCasesParams, paginationParams domain.PaginationParams) ([]domain.Case, error)
+	GetSolvedCasesByParams(
+		ctx context.Context,
+		params domain.SolvedCasesParams,
+		paginationParams domain.PaginationParams,
+	) ([]domain.Case, error)
 }
 
 type trainingService struct {
@@ -20,8 +25,9 @@ func NewTrainingService(trainingRepo repository.TrainingRepo) TrainingService {
 }
 
 func (s *trainingService) GetSolvedCasesByParams(
+	ctx context.Context,
 	params domain.SolvedCasesParams,
 	paginationParams domain.PaginationParams,
 ) ([]domain.Case, error) {
-	return s.trainingRepo.GetSolvedCasesByParams(params, paginationParams)
+	return s.trainingRepo.GetSolvedCasesByParams(ctx, params, paginationParams)
 }
@@ -3,11 +3,12 @@ package service
 import (
 	"TrafficPolice/internal/domain"
 	"TrafficPolice/internal/repository"
+	"context"
 	"github.com/google/uuid"
 )
 
 type ViolationService interface {
-	InsertViolations(violations []*domain.Violation) error
+	InsertViolations(ctx context.Context, violations []*domain.Violation) error
 }
 
 type violationService struct {
@@ -18,10 +19,10 @@ func NewViolationService(db repository.ViolationRepo) ViolationService {
 	return &violationService{repo: db}
 }
 
-func (s *violationService) InsertViolations(violations []*domain.Violation) error {
+func (s *violationService) InsertViolations(ctx context.Context, violations []*domain.Violation) error {
 	for i := range violations {
 		violations[i].ID = uuid.New().String()
 	}
 
-	return s.repo.InsertViolations(violations)
+	return s.repo.InsertViolations(ctx, violations)
 }
@@ -0,0 +1,51 @@
+package storage
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLocalStorage(t *testing.T) {
+	dir := t.TempDir()
+	s, err := NewLocalStorage(dir)
+	assert.NoError(t, err)
+
+	data := []byte("image")
+	key := sha256Hex(data)
+
+	exists, err := s.Exists(context.Background(), key)
+	assert.NoError(t, err)
+	assert.False(t, exists)
+
+	_, err = s.Get(context.Background(), key)
+	assert.ErrorIs(t, err, ErrBlobNotFound)
+
+	assert.NoError(t, s.Put(context.Background(), key, data))
+	assert.FileExists(t, filepath.Join(dir, key[:2], key))
+
+	// second put of same content is not an error
+	assert.NoError(t, s.Put(context.Background(), key, data))
+
+	entries, err := os.ReadDir(filepath.Join(dir, key[:2]))
+	assert.NoError(t, err)
+	assert.Len(t, entries, 1)
+
+	exists, err = s.Exists(context.Background(), key)
+	assert.NoError(t, err)
+	assert.True(t, exists)
+
+	actual, err := s.Get(context.Background(), key)
+	assert.NoError(t, err)
+	assert.Equal(t, data, actual)
+
+	assert.NoError(t, s.Delete(context.Background(), key))
+	assert.NoError(t, s.Delete(context.Background(), key))
+	assert.NoFileExists(t, filepath.Join(dir, key[:2], key))
+
+	for _, invalidKey := range []string{"", "../" + key[3:], key[:63] + "G"} {
+		assert.ErrorIs(t, s.Put(context.Background(), invalidKey, data), ErrInvalidBlobKey)
+	}
+}
@@ -0,0 +1,66 @@
+package storage
+
+import (
+	"TrafficPolice/internal/config"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+const (
+	TypeLocal = "local"
+	TypeS3    = "s3"
+
+	defaultLocalDir  = "images"
+	defaultS3Timeout = 30 * time.Second
+)
+
+var (
+	ErrBlobNotFound   = errors.New("blob not found")
+	ErrInvalidBlobKey = errors.New("invalid blob key")
+)
+
+// BlobStorage stores blobs by key. Key is hex encoded SHA-256 of blob content
+//
+//go:generate go run github.com/vektra/mockery/v2@v2.42.1 --name BlobStorage
+type BlobStorage interface {
+	Put(ctx context.Context, key string, data []byte) error
+	Get(ctx context.Context, key string) ([]byte, error)
+	Exists(ctx context.Context, key string) (bool, error)
+	Delete(ctx context.Context, key string) error
+}
+
+// New creates storage by config. Local storage is used by default
+func New(cfg config.StorageConfig) (BlobStorage, error) {
+	switch cfg.Type {
+	case "", TypeLocal:
+		dir := cfg.Local.Dir
+		if dir == "" {
+			dir = defaultLocalDir
+		}
+		return NewLocalStorage(dir)
+	case TypeS3:
+		timeout := cfg.S3.Timeout
+		if timeout <= 0 {
+			timeout = defaultS3Timeout
+		}
+		return NewS3Storage(cfg.S3, &http.Client{Timeout: timeout}), nil
+	default:
+		return nil, fmt.Errorf("unknown storage type %q", cfg.Type)
+	}
+}
+
+// validateKey checks that key is hex encoded SHA-256, so it is safe to use as file name or object name
+func validateKey(key string) error {
+	if len(key) != 64 {
+		return ErrInvalidBlobKey
+	}
+	for _, c := range key {
+		if (c < '0' || c > '9') && (c < 'a' || c > 'f') {
+			return ErrInvalidBlobKey
+		}
+	}
+	return nil
+}
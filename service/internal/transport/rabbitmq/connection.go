@@ -2,17 +2,138 @@ package rabbitmq
 
 import (
 	"TrafficPolice/internal/config"
+	"context"
+	"errors"
 	"fmt"
 	amqp "github.com/rabbitmq/amqp091-go"
+	"log"
+	"sync"
+	"time"
 )
 
-func NewRabbitMQConn(cfg *config.Config) (*amqp.Connection, error) {
-	url := fmt.Sprintf(
+const (
+	defaultReconnectMinBackoff = time.Second
+	defaultReconnectMaxBackoff = 30 * time.Second
+)
+
+var ErrNotConnected = errors.New("no connection to RabbitMQ")
+
+// Connection keeps connection to RabbitMQ: when connection is closed, broker is dialed again
+// with exponential backoff. Users of connection open channels again after reconnection
+type Connection struct {
+	url        string
+	minBackoff time.Duration
+	maxBackoff time.Duration
+
+	mu   sync.Mutex
+	conn *amqp.Connection
+	// connected is closed, when conn is established, and is replaced, when conn is lost
+	connected chan struct{}
+}
+
+func URL(cfg config.RabbitMQConfig) string {
+	return fmt.Sprintf(
 		"amqp://%s:%s@%s:%d/",
-		cfg.RabbitMQ.User,
-		cfg.RabbitMQ.Password,
-		cfg.RabbitMQ.Host,
-		cfg.RabbitMQ.Port,
+		cfg.User,
+		cfg.Password,
+		cfg.Host,
+		cfg.Port,
 	)
-	return amqp.Dial(url)
+}
+
+// NewConnection creates connection, which is established by Run. Zero backoff settings mean defaults
+func NewConnection(cfg config.RabbitMQConfig) *Connection {
+	c := &Connection{
+		url:        URL(cfg),
+		minBackoff: cfg.ReconnectMinBackoff,
+		maxBackoff: cfg.ReconnectMaxBackoff,
+		connected:  make(chan struct{}),
+	}
+	if c.minBackoff <= 0 {
+		c.minBackoff = defaultReconnectMinBackoff
+	}
+	if c.maxBackoff < c.minBackoff {
+		c.maxBackoff = max(defaultReconnectMaxBackoff, c.minBackoff)
+	}
+
+	return c
+}
+
+// Run dials broker and dials it again every time connection is closed, until done is closed
+func (c *Connection) Run(done <-chan struct{}) {
+	delay := c.minBackoff
+	for {
+		conn, err := amqp.Dial(c.url)
+		if err != nil {
+			log.Printf("Dial RabbitMQ: %v. Retry in %s\n", err, delay)
+			select {
+			case <-time.After(delay):
+				delay = min(delay*2, c.maxBackoff)
+				continue
+			case <-done:
+				return
+			}
+		}
+
+		delay = c.minBackoff
+		closed := conn.NotifyClose(make(chan *amqp.Error, 1))
+		c.set(conn)
+		log.Println("Connected to RabbitMQ")
+
+		select {
+		case err := <-closed:
+			log.Printf("RabbitMQ connection is closed: %v\n", err)
+			c.lost(conn)
+		case <-done:
+			c.lost(conn)
+			if err := conn.Close(); err != nil {
+				log.Printf("Close RabbitMQ connection: %v\n", err)
+			}
+			return
+		}
+	}
+}
+
+// Channel opens channel on current connection. When there is no connection, it waits for reconnection
+// until ctx is done
+func (c *Connection) Channel(ctx context.Context) (*amqp.Channel, error) {
+	for {
+		c.mu.Lock()
+		conn, connected := c.conn, c.connected
+		c.mu.Unlock()
+
+		if conn != nil {
+			ch, err := conn.Channel()
+			if err == nil || !conn.IsClosed() {
+				return ch, err
+			}
+			c.lost(conn)
+			continue
+		}
+
+		select {
+		case <-connected:
+		case <-ctx.Done():
+			return nil, fmt.Errorf("%w: %w", ErrNotConnected, ctx.Err())
+		}
+	}
+}
+
+func (c *Connection) set(conn *amqp.Connection) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.conn = conn
+	close(c.connected)
+}
+
+// lost forgets conn, if it is still current connection
+func (c *Connection) lost(conn *amqp.Connection) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.conn == conn {
+		c.conn = nil
+		c.connected = make(chan struct{})
+	}
 }
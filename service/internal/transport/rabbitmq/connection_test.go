@@ -0,0 +1,120 @@
+package rabbitmq
+
+import (
+	"TrafficPolice/internal/config"
+	"context"
+	"github.com/google/uuid"
+	amqp "github.com/rabbitmq/amqp091-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"os"
+	"os/exec"
+	"testing"
+	"time"
+)
+
+// testRabbitMQURLEnv contains url of local broker, testRabbitMQContainerEnv contains name of its docker
+// container, which is restarted by tests. Tests are skipped without them
+const (
+	testRabbitMQURLEnv       = "TEST_RABBITMQ_URL"
+	testRabbitMQContainerEnv = "TEST_RABBITMQ_CONTAINER"
+
+	reconnectTimeout = time.Minute
+)
+
+func testBrokerConfig(t *testing.T) (config.RabbitMQConfig, string) {
+	brokerURL, container := os.Getenv(testRabbitMQURLEnv), os.Getenv(testRabbitMQContainerEnv)
+	if brokerURL == "" || container == "" {
+		t.Skipf("%s or %s is not set", testRabbitMQURLEnv, testRabbitMQContainerEnv)
+	}
+
+	uri, err := amqp.ParseURI(brokerURL)
+	require.NoError(t, err)
+
+	return config.RabbitMQConfig{
+		User:                uri.Username,
+		Password:            uri.Password,
+		Host:                uri.Host,
+		Port:                uri.Port,
+		ReconnectMinBackoff: 100 * time.Millisecond,
+		ReconnectMaxBackoff: time.Second,
+	}, container
+}
+
+func restartBroker(t *testing.T, container string) {
+	out, err := exec.Command("docker", "restart", container).CombinedOutput()
+	require.NoError(t, err, string(out))
+}
+
+// testTopology declares durable queue with unique name, so messages survive restart of broker
+func testTopology(t *testing.T, cfg config.RabbitMQConfig) Topology {
+	name := "test_" + uuid.New().String()
+	t.Cleanup(func() {
+		conn, err := amqp.Dial(URL(cfg))
+		if err != nil {
+			t.Log(err)
+			return
+		}
+		defer conn.Close()
+		ch, err := conn.Channel()
+		if err != nil {
+			t.Log(err)
+			return
+		}
+		_, _ = ch.QueueDelete(name, false, false, false)
+		_ = ch.ExchangeDelete(name, false, false)
+	})
+
+	return Topology{
+		Exchange: ExchangeParams{Name: name, Kind: Fanout, Durable: true},
+		Queue:    QueueParams{Name: name, Durable: true},
+		Binding:  BindingParams{Queue: name, Exchange: name},
+	}
+}
+
+func TestPublisherReconnect(t *testing.T) {
+	cfg, container := testBrokerConfig(t)
+	topology := testTopology(t, cfg)
+
+	done := make(chan struct{})
+	defer close(done)
+	conn := NewConnection(cfg)
+	go conn.Run(done)
+
+	publisher := NewPublisher(conn, topology)
+	defer publisher.Close()
+
+	msg := Message{Exchange: topology.Exchange.Name, Mandatory: true, ContentType: jsonContentType}
+	msg.Body = []byte(`"before"`)
+	err := publisher.Publish(context.Background(), msg)
+	require.NoError(t, err)
+
+	restartBroker(t, container)
+
+	// Publish fails until broker is started and connection is dialed again
+	msg.Body = []byte(`"after"`)
+	deadline := time.Now().Add(reconnectTimeout)
+	for {
+		err = publisher.Publish(context.Background(), msg)
+		if err == nil || time.Now().After(deadline) {
+			break
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	require.NoError(t, err)
+
+	ch, err := conn.Channel(context.Background())
+	require.NoError(t, err)
+	defer ch.Close()
+
+	var bodies []string
+	for {
+		d, ok, err := ch.Get(topology.Queue.Name, true)
+		require.NoError(t, err)
+		if !ok {
+			break
+		}
+		bodies = append(bodies, string(d.Body))
+	}
+	assert.Equal(t, []string{`"before"`, `"after"`}, bodies)
+}
@@ -0,0 +1,45 @@
+package rabbitmq
+
+import (
+	"context"
+	"encoding/json"
+	"events"
+)
+
+// EventsExchange is durable topic exchange of domain events. Service does not declare queues of events,
+// consumers bind their queues by routing keys of event types, for example case.* or expert.skill_changed
+const (
+	EventsExchange = "events"
+	Topic          = "topic"
+)
+
+//go:generate go run github.com/vektra/mockery/v2@v2.42.1 --name EventPublisher
+type EventPublisher interface {
+	PublishEvent(ctx context.Context, event events.DomainEvent) error
+}
+
+type EventPublisherRabbitMQ struct {
+	publisher *Publisher
+}
+
+func NewEventPublisher(publisher *Publisher) *EventPublisherRabbitMQ {
+	return &EventPublisherRabbitMQ{publisher: publisher}
+}
+
+// PublishEvent sends event with routing key of its type. Event is not mandatory, broker drops event,
+// which no consumer is bound to. Message id is id of event, so consumers deduplicate events published again
+func (p *EventPublisherRabbitMQ) PublishEvent(ctx context.Context, event events.DomainEvent) error {
+	eventBytes, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	return p.publisher.Publish(ctx, Message{
+		Exchange:    EventsExchange,
+		Key:         event.Type,
+		ID:          event.EventID,
+		Type:        event.Type,
+		ContentType: jsonContentType,
+		Body:        eventBytes,
+	})
+}
@@ -3,7 +3,8 @@
 package mocks
 
 import (
-	dto "TrafficPolice/internal/transport/rest/dto"
+	context "context"
+	events "events"
 
 	mock "github.com/stretchr/testify/mock"
 )
@@ -13,17 +14,17 @@ type FinePublisher struct {
 	mock.Mock
 }
 
-// PublishFineNotification provides a mock function with given fields: c
-func (_m *FinePublisher) PublishFineNotification(c dto.CaseWithImage) error {
-	ret := _m.Called(c)
+// PublishFineNotification provides a mock function with given fields: ctx, event
+func (_m *FinePublisher) PublishFineNotification(ctx context.Context, event events.FineEvent) error {
+	ret := _m.Called(ctx, event)
 
 	if len(ret) == 0 {
 		panic("no return value specified for PublishFineNotification")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(dto.CaseWithImage) error); ok {
-		r0 = rf(c)
+	if rf, ok := ret.Get(0).(func(context.Context, events.FineEvent) error); ok {
+		r0 = rf(ctx, event)
 	} else {
 		r0 = ret.Error(0)
 	}
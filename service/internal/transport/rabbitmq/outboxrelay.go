@@ -0,0 +1,215 @@
+package rabbitmq
+
+import (
+	"TrafficPolice/internal/config"
+	"TrafficPolice/internal/converter"
+	"TrafficPolice/internal/domain"
+	"TrafficPolice/internal/errs"
+	"TrafficPolice/internal/service"
+	"context"
+	"encoding/json"
+	"errors"
+	"events"
+	"fmt"
+	"log"
+	"time"
+)
+
+// ImageURLs builds signed urls of case images for fine events
+type ImageURLs interface {
+	CaseImageURL(caseID string, expiresAt time.Time) string
+}
+
+// OutboxRelay publishes messages, which are recorded in outbox with state changes, to RabbitMQ
+type OutboxRelay struct {
+	outboxService  service.OutboxService
+	expertService  service.ExpertService
+	caseService    service.CaseService
+	fineService    service.FineService
+	finePublisher  FinePublisher
+	eventPublisher EventPublisher
+	converter      *converter.EventConverter
+	imageURLs      ImageURLs
+	eventsCfg      config.EventsConfig
+	interval       time.Duration
+}
+
+func NewOutboxRelay(
+	outboxService service.OutboxService,
+	expertService service.ExpertService,
+	caseService service.CaseService,
+	fineService service.FineService,
+	finePublisher FinePublisher,
+	eventPublisher EventPublisher,
+	eventConverter *converter.EventConverter,
+	imageURLs ImageURLs,
+	eventsCfg config.EventsConfig,
+	interval time.Duration,
+) *OutboxRelay {
+	return &OutboxRelay{
+		outboxService:  outboxService,
+		expertService:  expertService,
+		caseService:    caseService,
+		fineService:    fineService,
+		finePublisher:  finePublisher,
+		eventPublisher: eventPublisher,
+		converter:      eventConverter,
+		imageURLs:      imageURLs,
+		eventsCfg:      eventsCfg,
+		interval:       interval,
+	}
+}
+
+func (r *OutboxRelay) Run(done <-chan struct{}) {
+	log.Printf("Run outbox relay with interval: %s\n", r.interval)
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			err := r.relay(context.Background())
+			if err != nil {
+				log.Println(err)
+			}
+		case <-done:
+			log.Println("Stop outbox relay")
+			return
+		}
+	}
+}
+
+// relay publishes claimed messages one by one. Failed attempt is recorded, so message is retried
+// with backoff and other messages are still published
+func (r *OutboxRelay) relay(ctx context.Context) error {
+	messages, err := r.outboxService.ClaimPendingMessages(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, msg := range messages {
+		err = r.publish(ctx, msg)
+		if err != nil {
+			log.Printf("Outbox message %s is not published: %v\n", msg.ID, err)
+			err = r.outboxService.MarkAttemptFailed(ctx, msg, err)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+
+		err = r.outboxService.MarkPublished(ctx, msg.ID)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r *OutboxRelay) publish(ctx context.Context, msg domain.OutboxMessage) error {
+	switch msg.Kind {
+	case domain.FineNotificationKind:
+		var payload domain.FineNotificationPayload
+		err := json.Unmarshal(msg.Payload, &payload)
+		if err != nil {
+			return err
+		}
+		return r.publishFineNotification(ctx, payload.CaseID)
+	case domain.FineCancellationKind:
+		var payload domain.FineNotificationPayload
+		err := json.Unmarshal(msg.Payload, &payload)
+		if err != nil {
+			return err
+		}
+		return r.publishFineCancellation(ctx, payload)
+	default:
+		if !msg.Kind.IsEvent() {
+			return fmt.Errorf("unknown outbox message kind: %s", msg.Kind)
+		}
+		return r.publishEvent(ctx, msg)
+	}
+}
+
+// publishEvent wraps payload of domain event in envelope. Id and time of event are id and creation time
+// of outbox message, so event published again is the same event
+func (r *OutboxRelay) publishEvent(ctx context.Context, msg domain.OutboxMessage) error {
+	return r.eventPublisher.PublishEvent(ctx, events.DomainEvent{
+		EventID:       msg.ID,
+		Type:          string(msg.Kind),
+		OccurredAt:    msg.CreatedAt,
+		SchemaVersion: events.DomainEventV1,
+		Data:          msg.Payload,
+	})
+}
+
+// publishFineNotification sends redacted image of web size, so notification does not contain
+// bystanders and metadata. Cases, which were solved before fines were issued, are sent without fine.
+// Event of version 2 contains signed url of image instead of image bytes
+func (r *OutboxRelay) publishFineNotification(ctx context.Context, caseID string) error {
+	caseInfo, err := r.expertService.GetCaseWithPersonInfo(ctx, caseID)
+	if err != nil {
+		return err
+	}
+
+	// image is got in both versions, so web variant is generated before fine_notification requests it
+	img, err := r.caseService.GetCaseImg(ctx, caseID, domain.WebImage)
+	if err != nil {
+		return err
+	}
+
+	var fine *events.Fine
+	caseFine, err := r.fineService.GetCaseFine(ctx, caseID)
+	if err == nil {
+		fineEvent := r.converter.MapFineToEvent(caseFine)
+		fine = &fineEvent
+	} else if !errors.Is(err, errs.ErrNoFine) {
+		return err
+	}
+
+	event := events.FineEvent{
+		SchemaVersion: r.eventsCfg.Version,
+		Case:          r.converter.MapCaseToEvent(caseInfo),
+		Fine:          fine,
+	}
+	extension := domain.ImageExtensions[img.ContentType]
+	if r.eventsCfg.Version == events.FineEventV1 {
+		event.Image = img.Data
+		event.ImageExtension = extension
+	} else {
+		expiresAt := time.Now().Add(r.eventsCfg.ImageURLTTL)
+		event.ImageRef = &events.ImageRef{
+			URL:         r.imageURLs.CaseImageURL(caseID, expiresAt),
+			ContentType: img.ContentType,
+			Extension:   extension,
+			ExpiresAt:   expiresAt,
+		}
+	}
+
+	return r.finePublisher.PublishFineNotification(ctx, event)
+}
+
+// publishFineCancellation sends fine event with cancelled fine and without image. Cancellation is not sent,
+// when fine was issued again before cancellation is published: violator gets notification about new fine
+func (r *OutboxRelay) publishFineCancellation(ctx context.Context, payload domain.FineNotificationPayload) error {
+	caseInfo, err := r.expertService.GetCaseWithPersonInfo(ctx, payload.CaseID)
+	if err != nil {
+		return err
+	}
+
+	caseFine, err := r.fineService.GetCaseFine(ctx, payload.CaseID)
+	if err != nil {
+		return err
+	}
+	if caseFine.ID != payload.FineID || caseFine.Status != domain.FineCancelled {
+		log.Printf("Fine %s of case %s is issued again, cancellation is not sent\n", payload.FineID, payload.CaseID)
+		return nil
+	}
+
+	fine := r.converter.MapFineToEvent(caseFine)
+	return r.finePublisher.PublishFineNotification(ctx, events.FineEvent{
+		SchemaVersion: r.eventsCfg.Version,
+		Case:          r.converter.MapCaseToEvent(caseInfo),
+		Fine:          &fine,
+	})
+}
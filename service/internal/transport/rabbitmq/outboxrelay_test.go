@@ -0,0 +1,567 @@
+package rabbitmq
+
+import (
+	"TrafficPolice/internal/config"
+	"TrafficPolice/internal/converter"
+	"TrafficPolice/internal/domain"
+	"TrafficPolice/internal/errs"
+	"TrafficPolice/internal/service"
+	"TrafficPolice/internal/service/mocks"
+	mocksmq "TrafficPolice/internal/transport/rabbitmq/mocks"
+	"context"
+	"encoding/json"
+	"errors"
+	"events"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"testing"
+	"time"
+)
+
+// imageURLsFunc builds image urls by function
+type imageURLsFunc func(caseID string, expiresAt time.Time) string
+
+func (f imageURLsFunc) CaseImageURL(caseID string, expiresAt time.Time) string {
+	return f(caseID, expiresAt)
+}
+
+func TestRelay(t *testing.T) {
+	imageURLs := imageURLsFunc(func(caseID string, _ time.Time) string {
+		return "http://service/img/" + caseID
+	})
+	caseID := "case_id"
+	caseInfo := domain.Case{ID: caseID, Transport: domain.Transport{Person: &domain.Person{}}}
+	img := domain.Image{Data: []byte("image"), ContentType: "image/jpeg"}
+	fine := domain.Fine{ID: "fine_id", CaseID: caseID, Reference: "18810000000000000001", Amount: 5000}
+	publishErr := errors.New("connection refused")
+	withFine := func(event events.FineEvent) bool {
+		return event.Fine != nil && event.Fine.Reference == fine.Reference
+	}
+	withImageRef := func(event events.FineEvent) bool {
+		return withFine(event) && event.SchemaVersion == events.FineEventV2 && event.Image == nil &&
+			event.ImageRef != nil && event.ImageRef.URL == "http://service/img/case_id" &&
+			event.ImageRef.Extension == "jpeg"
+	}
+	withImage := func(event events.FineEvent) bool {
+		return withFine(event) && event.SchemaVersion == events.FineEventV1 && string(event.Image) == "image" &&
+			event.ImageExtension == "jpeg" && event.ImageRef == nil
+	}
+
+	fineMsg := domain.OutboxMessage{
+		ID: "fine_id", Kind: domain.FineNotificationKind, Payload: []byte(`{"case_id":"case_id"}`),
+	}
+	unknownMsg := domain.OutboxMessage{ID: "unknown_id", Kind: "unknown", Payload: []byte(`{}`)}
+	eventMsg := domain.OutboxMessage{
+		ID: "event_id", Kind: domain.OutboxKind(domain.CaseEscalatedEvent), CreatedAt: time.Now(),
+		Payload: []byte(`{"case_id":"case_id","from_skill":1,"to_skill":2}`),
+	}
+
+	testCases := []struct {
+		name               string
+		buildOutboxService func() service.OutboxService
+		buildExpertService func() service.ExpertService
+		buildCaseService   func() service.CaseService
+		buildFineService   func() service.FineService
+		buildFinePublisher func() FinePublisher
+		// buildEventPublisher is set only for cases, which publish domain events
+		buildEventPublisher func() EventPublisher
+		version             int // version 2 is used by default
+		expectedErr         error
+	}{
+		{
+			name: "Fine notification is published",
+			buildOutboxService: func() service.OutboxService {
+				mockService := mocks.NewOutboxService(t)
+				mockService.On("ClaimPendingMessages", mock.Anything).
+					Return([]domain.OutboxMessage{fineMsg}, nil)
+				mockService.On("MarkPublished", mock.Anything, fineMsg.ID).
+					Return(nil).
+					Times(1)
+
+				return mockService
+			},
+			buildExpertService: func() service.ExpertService {
+				mockService := mocks.NewExpertService(t)
+				mockService.On("GetCaseWithPersonInfo", mock.Anything, caseID).
+					Return(caseInfo, nil)
+
+				return mockService
+			},
+			buildCaseService: func() service.CaseService {
+				mockService := mocks.NewCaseService(t)
+				mockService.On("GetCaseImg", mock.Anything, caseID, domain.WebImage).
+					Return(img, nil)
+
+				return mockService
+			},
+			buildFineService: func() service.FineService {
+				mockService := mocks.NewFineService(t)
+				mockService.On("GetCaseFine", mock.Anything, caseID).
+					Return(fine, nil)
+
+				return mockService
+			},
+			buildFinePublisher: func() FinePublisher {
+				mockPublisher := mocksmq.NewFinePublisher(t)
+				mockPublisher.On("PublishFineNotification", mock.Anything, mock.MatchedBy(withImageRef)).
+					Return(nil)
+
+				return mockPublisher
+			},
+			expectedErr: nil,
+		},
+		{
+			name: "Version 1. Fine notification is published with image",
+			buildOutboxService: func() service.OutboxService {
+				mockService := mocks.NewOutboxService(t)
+				mockService.On("ClaimPendingMessages", mock.Anything).
+					Return([]domain.OutboxMessage{fineMsg}, nil)
+				mockService.On("MarkPublished", mock.Anything, fineMsg.ID).
+					Return(nil).
+					Times(1)
+
+				return mockService
+			},
+			buildExpertService: func() service.ExpertService {
+				mockService := mocks.NewExpertService(t)
+				mockService.On("GetCaseWithPersonInfo", mock.Anything, caseID).
+					Return(caseInfo, nil)
+
+				return mockService
+			},
+			buildCaseService: func() service.CaseService {
+				mockService := mocks.NewCaseService(t)
+				mockService.On("GetCaseImg", mock.Anything, caseID, domain.WebImage).
+					Return(img, nil)
+
+				return mockService
+			},
+			buildFineService: func() service.FineService {
+				mockService := mocks.NewFineService(t)
+				mockService.On("GetCaseFine", mock.Anything, caseID).
+					Return(fine, nil)
+
+				return mockService
+			},
+			buildFinePublisher: func() FinePublisher {
+				mockPublisher := mocksmq.NewFinePublisher(t)
+				mockPublisher.On("PublishFineNotification", mock.Anything, mock.MatchedBy(withImage)).
+					Return(nil)
+
+				return mockPublisher
+			},
+			version:     events.FineEventV1,
+			expectedErr: nil,
+		},
+		{
+			name: "Broker is unavailable. Attempt is failed",
+			buildOutboxService: func() service.OutboxService {
+				mockService := mocks.NewOutboxService(t)
+				mockService.On("ClaimPendingMessages", mock.Anything).
+					Return([]domain.OutboxMessage{fineMsg}, nil)
+				mockService.On("MarkAttemptFailed", mock.Anything, fineMsg, publishErr).
+					Return(nil).
+					Times(1)
+
+				return mockService
+			},
+			buildExpertService: func() service.ExpertService {
+				mockService := mocks.NewExpertService(t)
+				mockService.On("GetCaseWithPersonInfo", mock.Anything, caseID).
+					Return(caseInfo, nil)
+
+				return mockService
+			},
+			buildCaseService: func() service.CaseService {
+				mockService := mocks.NewCaseService(t)
+				mockService.On("GetCaseImg", mock.Anything, caseID, domain.WebImage).
+					Return(img, nil)
+
+				return mockService
+			},
+			buildFineService: func() service.FineService {
+				mockService := mocks.NewFineService(t)
+				mockService.On("GetCaseFine", mock.Anything, caseID).
+					Return(domain.Fine{}, errs.ErrNoFine)
+
+				return mockService
+			},
+			buildFinePublisher: func() FinePublisher {
+				mockPublisher := mocksmq.NewFinePublisher(t)
+				mockPublisher.On("PublishFineNotification", mock.Anything, mock.Anything).
+					Return(publishErr)
+
+				return mockPublisher
+			},
+			expectedErr: nil,
+		},
+		{
+			name: "Unknown kind is failed. Other messages are published",
+			buildOutboxService: func() service.OutboxService {
+				mockService := mocks.NewOutboxService(t)
+				mockService.On("ClaimPendingMessages", mock.Anything).
+					Return([]domain.OutboxMessage{unknownMsg, fineMsg}, nil)
+				mockService.On("MarkAttemptFailed", mock.Anything, unknownMsg, mock.Anything).
+					Return(nil).
+					Times(1)
+				mockService.On("MarkPublished", mock.Anything, fineMsg.ID).
+					Return(nil).
+					Times(1)
+
+				return mockService
+			},
+			buildExpertService: func() service.ExpertService {
+				mockService := mocks.NewExpertService(t)
+				mockService.On("GetCaseWithPersonInfo", mock.Anything, caseID).
+					Return(caseInfo, nil)
+
+				return mockService
+			},
+			buildCaseService: func() service.CaseService {
+				mockService := mocks.NewCaseService(t)
+				mockService.On("GetCaseImg", mock.Anything, caseID, domain.WebImage).
+					Return(img, nil)
+
+				return mockService
+			},
+			buildFineService: func() service.FineService {
+				mockService := mocks.NewFineService(t)
+				mockService.On("GetCaseFine", mock.Anything, caseID).
+					Return(fine, nil)
+
+				return mockService
+			},
+			buildFinePublisher: func() FinePublisher {
+				mockPublisher := mocksmq.NewFinePublisher(t)
+				mockPublisher.On("PublishFineNotification", mock.Anything, mock.Anything).
+					Return(nil)
+
+				return mockPublisher
+			},
+			expectedErr: nil,
+		},
+		{
+			name: "Domain event is published in envelope",
+			buildOutboxService: func() service.OutboxService {
+				mockService := mocks.NewOutboxService(t)
+				mockService.On("ClaimPendingMessages", mock.Anything).
+					Return([]domain.OutboxMessage{eventMsg}, nil)
+				mockService.On("MarkPublished", mock.Anything, eventMsg.ID).
+					Return(nil).
+					Times(1)
+
+				return mockService
+			},
+			buildExpertService: func() service.ExpertService {
+				return mocks.NewExpertService(t)
+			},
+			buildCaseService: func() service.CaseService {
+				return mocks.NewCaseService(t)
+			},
+			buildFineService: func() service.FineService {
+				return mocks.NewFineService(t)
+			},
+			buildFinePublisher: func() FinePublisher {
+				return mocksmq.NewFinePublisher(t)
+			},
+			buildEventPublisher: func() EventPublisher {
+				mockPublisher := mocksmq.NewEventPublisher(t)
+				mockPublisher.On("PublishEvent", mock.Anything, events.DomainEvent{
+					EventID:       eventMsg.ID,
+					Type:          events.CaseEscalatedEvent,
+					OccurredAt:    eventMsg.CreatedAt,
+					SchemaVersion: events.DomainEventV1,
+					Data:          eventMsg.Payload,
+				}).
+					Return(nil)
+
+				return mockPublisher
+			},
+			expectedErr: nil,
+		},
+		{
+			name: "Claim error",
+			buildOutboxService: func() service.OutboxService {
+				mockService := mocks.NewOutboxService(t)
+				mockService.On("ClaimPendingMessages", mock.Anything).
+					Return(nil, errs.ErrNoRows)
+
+				return mockService
+			},
+			buildExpertService: func() service.ExpertService {
+				return mocks.NewExpertService(t)
+			},
+			buildCaseService: func() service.CaseService {
+				return mocks.NewCaseService(t)
+			},
+			buildFineService: func() service.FineService {
+				return mocks.NewFineService(t)
+			},
+			buildFinePublisher: func() FinePublisher {
+				return mocksmq.NewFinePublisher(t)
+			},
+			expectedErr: errs.ErrNoRows,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			eventsCfg := config.EventsConfig{Version: events.FineEventV2, ImageURLTTL: time.Hour}
+			if tc.version != 0 {
+				eventsCfg.Version = tc.version
+			}
+			var eventPublisher EventPublisher = mocksmq.NewEventPublisher(t)
+			if tc.buildEventPublisher != nil {
+				eventPublisher = tc.buildEventPublisher()
+			}
+			relay := NewOutboxRelay(tc.buildOutboxService(), tc.buildExpertService(), tc.buildCaseService(),
+				tc.buildFineService(), tc.buildFinePublisher(), eventPublisher, converter.NewEventConverter(),
+				imageURLs, eventsCfg, 0)
+
+			err := relay.relay(context.Background())
+			assert.Equal(t, tc.expectedErr, err)
+		})
+	}
+}
+
+func TestRelayFineCancellation(t *testing.T) {
+	caseID := "case_id"
+	caseInfo := domain.Case{ID: caseID, Transport: domain.Transport{Person: &domain.Person{}}}
+	cancelledAt := time.Now()
+	cancelled := domain.Fine{
+		ID: "fine_id", CaseID: caseID, Reference: "18810000000000000001", Amount: 5000,
+		Status: domain.FineCancelled, CancelledAt: &cancelledAt,
+	}
+	reissued := domain.Fine{
+		ID: "new_fine_id", CaseID: caseID, Reference: "18810000000000000001", Amount: 5000, Status: domain.FineIssued,
+	}
+	cancellationMsg := domain.OutboxMessage{
+		ID: "msg_id", Kind: domain.FineCancellationKind, Payload: []byte(`{"case_id":"case_id","fine_id":"fine_id"}`),
+	}
+	withCancelledFine := func(event events.FineEvent) bool {
+		return event.Fine != nil && event.Fine.ID == cancelled.ID && event.Fine.Status == "cancelled" &&
+			event.Image == nil && event.ImageRef == nil
+	}
+
+	testCases := []struct {
+		name               string
+		fine               domain.Fine
+		fineErr            error
+		buildFinePublisher func() FinePublisher
+		expectedErr        error
+	}{
+		{
+			name: "Cancellation is published without image",
+			fine: cancelled,
+			buildFinePublisher: func() FinePublisher {
+				mockPublisher := mocksmq.NewFinePublisher(t)
+				mockPublisher.On("PublishFineNotification", mock.Anything, mock.MatchedBy(withCancelledFine)).
+					Return(nil)
+
+				return mockPublisher
+			},
+			expectedErr: nil,
+		},
+		{
+			name: "Fine is issued again. Cancellation is not published",
+			fine: reissued,
+			buildFinePublisher: func() FinePublisher {
+				return mocksmq.NewFinePublisher(t)
+			},
+			expectedErr: nil,
+		},
+		{
+			name:    "Fine error",
+			fineErr: errs.ErrNoFine,
+			buildFinePublisher: func() FinePublisher {
+				return mocksmq.NewFinePublisher(t)
+			},
+			expectedErr: errs.ErrNoFine,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			expertService := mocks.NewExpertService(t)
+			expertService.On("GetCaseWithPersonInfo", mock.Anything, caseID).Return(caseInfo, nil)
+			fineService := mocks.NewFineService(t)
+			fineService.On("GetCaseFine", mock.Anything, caseID).Return(tc.fine, tc.fineErr)
+
+			relay := NewOutboxRelay(mocks.NewOutboxService(t), expertService, mocks.NewCaseService(t), fineService,
+				tc.buildFinePublisher(), mocksmq.NewEventPublisher(t), converter.NewEventConverter(), nil,
+				config.EventsConfig{Version: events.FineEventV2}, 0)
+
+			err := relay.publish(context.Background(), cancellationMsg)
+			assert.Equal(t, tc.expectedErr, err)
+		})
+	}
+}
+
+// TestFineEventContract fails, when published fine event does not follow schema of events, which
+// fine_notification decodes
+func TestFineEventContract(t *testing.T) {
+	imageURLs := imageURLsFunc(func(caseID string, _ time.Time) string {
+		return "http://service:8080/internal/case/" + caseID + "/img?expires=1&signature=signature"
+	})
+	date := time.Date(2024, time.March, 1, 12, 30, 0, 0, time.UTC)
+	discountUntil := date.Add(20 * 24 * time.Hour)
+	caseInfo := domain.Case{
+		ID: "case_id",
+		Transport: domain.Transport{
+			ID: "transport_id", Chars: "ABC", Num: "123", Region: "77",
+			Person: &domain.Person{
+				ID: "person_id", PhoneNum: "+79990000000", Email: "owner@example.com", VkID: "1", TgID: "2",
+				NotifyChannels: []string{"telegram", "email"},
+			},
+		},
+		Camera: domain.Camera{
+			ID: "camera_id", CameraType: domain.CameraType{ID: "camera_type_id"},
+			Latitude: 55.753722, Longitude: 37.621139, ShortDesc: "Камера на Тверской",
+		},
+		Violation:      domain.Violation{ID: "violation_id", Name: "Превышение скорости", FineAmount: 500},
+		ViolationValue: "80 км/ч",
+		RequiredSkill:  1,
+		Date:           date,
+		IsSolved:       true,
+		FineDecision:   true,
+	}
+	withoutPerson := caseInfo
+	withoutPerson.Transport.Person = nil
+	fine := domain.Fine{
+		ID: "fine_id", CaseID: "case_id", Reference: "18810000000000000001", Amount: 500, DiscountAmount: 250,
+		DiscountUntil: &discountUntil, IssuedAt: date, DueAt: date.Add(60 * 24 * time.Hour), Status: domain.FineIssued,
+	}
+
+	testCases := []struct {
+		name     string
+		caseInfo domain.Case
+		fineErr  error
+	}{
+		{name: "Case with person and fine", caseInfo: caseInfo},
+		{name: "Case without person", caseInfo: withoutPerson},
+		{name: "Case solved before fines", caseInfo: caseInfo, fineErr: errs.ErrNoFine},
+	}
+
+	for _, version := range events.FineEventVersions {
+		for _, tc := range testCases {
+			t.Run(fmt.Sprintf("Version %d. %s", version, tc.name), func(t *testing.T) {
+				expertService := mocks.NewExpertService(t)
+				expertService.On("GetCaseWithPersonInfo", mock.Anything, caseInfo.ID).Return(tc.caseInfo, nil)
+				caseService := mocks.NewCaseService(t)
+				caseService.On("GetCaseImg", mock.Anything, caseInfo.ID, domain.WebImage).
+					Return(domain.Image{Data: []byte("image"), ContentType: "image/jpeg"}, nil)
+				fineService := mocks.NewFineService(t)
+				fineService.On("GetCaseFine", mock.Anything, caseInfo.ID).Return(fine, tc.fineErr)
+
+				var published events.FineEvent
+				publisher := mocksmq.NewFinePublisher(t)
+				publisher.On("PublishFineNotification", mock.Anything, mock.Anything).
+					Run(func(args mock.Arguments) { published = args.Get(1).(events.FineEvent) }).
+					Return(nil)
+
+				relay := NewOutboxRelay(mocks.NewOutboxService(t), expertService, caseService, fineService, publisher,
+					mocksmq.NewEventPublisher(t), converter.NewEventConverter(), imageURLs,
+					config.EventsConfig{Version: version, ImageURLTTL: time.Hour}, 0)
+				err := relay.publishFineNotification(context.Background(), caseInfo.ID)
+				assert.NoError(t, err)
+
+				msg, err := json.Marshal(published)
+				assert.NoError(t, err)
+				assert.NoError(t, events.ValidateFineEvent(msg))
+			})
+		}
+
+		t.Run(fmt.Sprintf("Version %d. Cancelled fine", version), func(t *testing.T) {
+			cancelled := fine
+			cancelled.Status = domain.FineCancelled
+			cancelled.CancelledAt = &date
+			expertService := mocks.NewExpertService(t)
+			expertService.On("GetCaseWithPersonInfo", mock.Anything, caseInfo.ID).Return(caseInfo, nil)
+			fineService := mocks.NewFineService(t)
+			fineService.On("GetCaseFine", mock.Anything, caseInfo.ID).Return(cancelled, nil)
+
+			var published events.FineEvent
+			publisher := mocksmq.NewFinePublisher(t)
+			publisher.On("PublishFineNotification", mock.Anything, mock.Anything).
+				Run(func(args mock.Arguments) { published = args.Get(1).(events.FineEvent) }).
+				Return(nil)
+
+			relay := NewOutboxRelay(mocks.NewOutboxService(t), expertService, mocks.NewCaseService(t), fineService,
+				publisher, mocksmq.NewEventPublisher(t), converter.NewEventConverter(), imageURLs,
+				config.EventsConfig{Version: version, ImageURLTTL: time.Hour}, 0)
+			err := relay.publishFineCancellation(context.Background(),
+				domain.FineNotificationPayload{CaseID: caseInfo.ID, FineID: fine.ID})
+			assert.NoError(t, err)
+
+			msg, err := json.Marshal(published)
+			assert.NoError(t, err)
+			assert.NoError(t, events.ValidateFineEvent(msg))
+		})
+	}
+}
+
+// TestDomainEventContract fails, when published domain event does not follow schema of events or
+// service emits event of type, which schema does not describe
+func TestDomainEventContract(t *testing.T) {
+	dueAt := time.Date(2024, time.April, 30, 12, 30, 0, 0, time.UTC)
+	noFine := false
+	payloads := []domain.Event{
+		domain.CaseCreated{
+			CaseID: "case_id", CameraID: "camera_id", ViolationID: "violation_id", ViolationValue: "80 км/ч",
+			RequiredSkill: 1, Date: time.Now(), HasImage: true,
+		},
+		domain.CaseAssigned{CaseID: "case_id", ExpertID: "expert_id", LeaseExpiresAt: time.Now()},
+		domain.CaseDecisionRecorded{CaseID: "case_id", ExpertID: "expert_id", ExpertSkill: 1, FineDecision: true},
+		domain.CaseSolved{
+			CaseID: "case_id", FineDecision: true,
+			Fine: &domain.IssuedFine{FineID: "fine_id", Reference: "18810000000000000001", Amount: 500, DueAt: dueAt},
+		},
+		domain.CaseSolved{CaseID: "case_id"},
+		domain.CaseEscalated{CaseID: "case_id", FromSkill: 1, ToSkill: 2, Reason: domain.NoConsensusEscalation},
+		domain.CaseReviewRequested{CaseID: "case_id", Skill: 5, Reason: domain.LeaseExpiredEscalation},
+		domain.CaseOverridden{
+			CaseID: "case_id", Action: domain.OverrideDecisionAction, FineDecision: &noFine, Reason: "Неверный номер",
+		},
+		domain.CaseOverridden{CaseID: "case_id", Action: domain.ReopenCaseAction, Reason: "Нечеткое фото"},
+		domain.ExpertConfirmed{ExpertID: "expert_id"},
+		domain.ExpertSkillChanged{
+			ExpertID: "expert_id", OldSkill: 1, NewSkill: 2, Reason: domain.ReportPeriodSkillChange,
+		},
+		domain.ExpertSkillChanged{ExpertID: "expert_id", OldSkill: 3, NewSkill: 1, Reason: domain.DirectorSkillChange},
+	}
+
+	emitted := make([]string, 0, len(domain.EventTypes))
+	for _, eventType := range domain.EventTypes {
+		emitted = append(emitted, string(eventType))
+	}
+	assert.ElementsMatch(t, events.DomainEventTypes, emitted)
+
+	tested := make(map[domain.EventType]bool)
+	for _, payload := range payloads {
+		tested[payload.EventType()] = true
+		t.Run(string(payload.EventType()), func(t *testing.T) {
+			data, err := json.Marshal(payload)
+			assert.NoError(t, err)
+
+			var published events.DomainEvent
+			publisher := mocksmq.NewEventPublisher(t)
+			publisher.On("PublishEvent", mock.Anything, mock.Anything).
+				Run(func(args mock.Arguments) { published = args.Get(1).(events.DomainEvent) }).
+				Return(nil)
+
+			relay := NewOutboxRelay(mocks.NewOutboxService(t), mocks.NewExpertService(t), mocks.NewCaseService(t),
+				mocks.NewFineService(t), mocksmq.NewFinePublisher(t), publisher, converter.NewEventConverter(),
+				nil, config.EventsConfig{}, 0)
+			err = relay.publish(context.Background(), domain.OutboxMessage{
+				ID: "event_id", Kind: domain.OutboxKind(payload.EventType()), Payload: data, CreatedAt: time.Now(),
+			})
+			assert.NoError(t, err)
+
+			msg, err := json.Marshal(published)
+			assert.NoError(t, err)
+			assert.NoError(t, events.ValidateDomainEvent(msg))
+		})
+	}
+	assert.Len(t, tested, len(domain.EventTypes), "every event type should be tested")
+}
@@ -0,0 +1,162 @@
+package rabbitmq
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	amqp "github.com/rabbitmq/amqp091-go"
+	"log"
+	"sync"
+	"time"
+)
+
+const (
+	jsonContentType = "application/json"
+
+	// confirmTimeout limits waiting of broker confirmation for published message
+	confirmTimeout = 5 * time.Second
+)
+
+var (
+	ErrNotConfirmed = errors.New("message is not confirmed by broker")
+	ErrUnroutable   = errors.New("message is returned by broker, no queue is bound to exchange")
+)
+
+// Message is persistent message, which is published to exchange with routing key. Mandatory message fails
+// with ErrUnroutable, when broker could not route it to any queue. ID is generated, when it is empty
+type Message struct {
+	Exchange    string
+	Key         string
+	Mandatory   bool
+	ID          string
+	Type        string
+	ContentType string
+	Body        []byte
+}
+
+// Publisher publishes persistent messages in confirm mode: Publish returns nil only when broker has
+// acknowledged message. Closed channel is opened again on next publish
+type Publisher struct {
+	conn       *Connection
+	topologies []Topology
+
+	// mu allows one message in flight, so returned message is checked before next publish
+	mu       sync.Mutex
+	amqpChan *amqp.Channel
+	returns  chan amqp.Return
+}
+
+func NewPublisher(conn *Connection, topologies ...Topology) *Publisher {
+	return &Publisher{
+		conn:       conn,
+		topologies: topologies,
+	}
+}
+
+// channel returns open channel in confirm mode. New channel is opened, when connection or channel
+// is closed, and topologies are declared again
+func (p *Publisher) channel(ctx context.Context) (*amqp.Channel, error) {
+	if p.amqpChan != nil && !p.amqpChan.IsClosed() {
+		return p.amqpChan, nil
+	}
+
+	ch, err := p.conn.Channel(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, topology := range p.topologies {
+		err = topology.Declare(ch)
+		if err != nil {
+			break
+		}
+	}
+	if err == nil {
+		err = ch.Confirm(false)
+	}
+	if err != nil {
+		if closeErr := ch.Close(); closeErr != nil {
+			log.Printf("Publisher close channel: %v\n", closeErr)
+		}
+		return nil, err
+	}
+
+	p.amqpChan = ch
+	p.returns = ch.NotifyReturn(make(chan amqp.Return, 1))
+	return ch, nil
+}
+
+func (p *Publisher) Close() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.amqpChan == nil {
+		return
+	}
+	if err := p.amqpChan.Close(); err != nil {
+		log.Printf("Publisher Close: %v\n", err)
+	}
+}
+
+// Publish sends persistent message and waits for confirmation of broker. Mandatory message, which broker
+// could not route to any queue, is returned before confirmation and fails with ErrUnroutable
+func (p *Publisher) Publish(ctx context.Context, msg Message) error {
+	ctx, cancel := context.WithTimeout(ctx, confirmTimeout)
+	defer cancel()
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	ch, err := p.channel(ctx)
+	if err != nil {
+		return err
+	}
+
+	if msg.ID == "" {
+		msg.ID = uuid.New().String()
+	}
+	confirmation, err := ch.PublishWithDeferredConfirmWithContext(
+		ctx,
+		msg.Exchange,
+		msg.Key,
+		msg.Mandatory,
+		false,
+		amqp.Publishing{
+			ContentType:  msg.ContentType,
+			DeliveryMode: amqp.Persistent,
+			MessageId:    msg.ID,
+			Type:         msg.Type,
+			Timestamp:    time.Now(),
+			Body:         msg.Body,
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	acked, err := confirmation.WaitContext(ctx)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrNotConfirmed, err)
+	}
+
+	return checkConfirmation(acked, p.returns, msg.ID)
+}
+
+// checkConfirmation checks acknowledgement of message and returns, which broker has sent before it
+func checkConfirmation(acked bool, returns <-chan amqp.Return, messageID string) error {
+	if !acked {
+		return ErrNotConfirmed
+	}
+
+	for {
+		select {
+		case r := <-returns:
+			if r.MessageId == messageID {
+				return fmt.Errorf("%w: %d %s", ErrUnroutable, r.ReplyCode, r.ReplyText)
+			}
+		default:
+			return nil
+		}
+	}
+}
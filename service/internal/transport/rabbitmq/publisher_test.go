@@ -0,0 +1,55 @@
+package rabbitmq
+
+import (
+	amqp "github.com/rabbitmq/amqp091-go"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestCheckConfirmation(t *testing.T) {
+	messageID := "message_id"
+
+	testCases := []struct {
+		name        string
+		acked       bool
+		returns     []amqp.Return
+		expectedErr error
+	}{
+		{
+			name:        "Message is acknowledged",
+			acked:       true,
+			expectedErr: nil,
+		},
+		{
+			name:        "Message is not acknowledged",
+			acked:       false,
+			expectedErr: ErrNotConfirmed,
+		},
+		{
+			name:  "Message is acknowledged after return",
+			acked: true,
+			returns: []amqp.Return{
+				{MessageId: messageID, ReplyCode: amqp.NoRoute, ReplyText: "NO_ROUTE"},
+			},
+			expectedErr: ErrUnroutable,
+		},
+		{
+			name:        "Return of other message is skipped",
+			acked:       true,
+			returns:     []amqp.Return{{MessageId: "other_message_id", ReplyCode: amqp.NoRoute}},
+			expectedErr: nil,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			returns := make(chan amqp.Return, len(tc.returns))
+			for _, r := range tc.returns {
+				returns <- r
+			}
+
+			err := checkConfirmation(tc.acked, returns, messageID)
+			assert.ErrorIs(t, err, tc.expectedErr)
+		})
+	}
+}
@@ -0,0 +1,49 @@
+package rabbitmq
+
+import amqp "github.com/rabbitmq/amqp091-go"
+
+// Topology is exchange and queue bound to it. Topology is declared every time channel is opened,
+// so it is restored after broker restart. Topology without queue name declares only exchange
+type Topology struct {
+	Exchange ExchangeParams
+	Queue    QueueParams
+	Binding  BindingParams
+}
+
+func (t Topology) Declare(ch *amqp.Channel) error {
+	err := ch.ExchangeDeclare(
+		t.Exchange.Name,
+		t.Exchange.Kind,
+		t.Exchange.Durable,
+		t.Exchange.AutoDelete,
+		t.Exchange.Internal,
+		t.Exchange.NoWait,
+		t.Exchange.Args,
+	)
+	if err != nil {
+		return err
+	}
+	if t.Queue.Name == "" {
+		return nil
+	}
+
+	_, err = ch.QueueDeclare(
+		t.Queue.Name,
+		t.Queue.Durable,
+		t.Queue.AutoDelete,
+		t.Queue.Exclusive,
+		t.Queue.NoWait,
+		t.Queue.Args,
+	)
+	if err != nil {
+		return err
+	}
+
+	return ch.QueueBind(
+		t.Binding.Queue,
+		t.Binding.Key,
+		t.Binding.Exchange,
+		t.Binding.NoWait,
+		t.Binding.Args,
+	)
+}
@@ -61,7 +61,7 @@ func (h *AuthHandler) SignUp(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err = h.service.RegisterExpert(h.userInfoConverter.MapSignUpToUserInfo(input))
+	err = h.service.RegisterExpert(r.Context(), h.userInfoConverter.MapSignUpToUserInfo(input))
 	if err != nil {
 		if errors.Is(err, errs.ErrAlreadyExists) {
 			response.Conflict(w, "User with this username already exists")
@@ -102,7 +102,7 @@ func (h *AuthHandler) SignIn(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	tokens, err := h.service.SignIn(h.userInfoConverter.MapSignInToUserInfo(input))
+	tokens, err := h.service.SignIn(r.Context(), h.userInfoConverter.MapSignInToUserInfo(input))
 
 	if err != nil {
 		if errors.Is(err, errs.ErrNoRows) {
@@ -164,7 +164,7 @@ func (h *AuthHandler) ConfirmExpert(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err = h.service.ConfirmExpert(h.authConverter.MapConfirmExpertDtoToDomain(input))
+	err = h.service.ConfirmExpert(r.Context(), h.authConverter.MapConfirmExpertDtoToDomain(input))
 
 	if err != nil {
 		if errors.Is(err, errs.ErrNoRows) {
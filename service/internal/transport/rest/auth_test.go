@@ -38,7 +38,7 @@ func TestSignUp(t *testing.T) {
 			},
 			buildAuthService: func() service.AuthService {
 				mockService := mocks.NewAuthService(t)
-				mockService.On("RegisterExpert", mock.Anything).
+				mockService.On("RegisterExpert", mock.Anything, mock.Anything).
 					Return(nil)
 
 				return mockService
@@ -77,7 +77,7 @@ func TestSignUp(t *testing.T) {
 			},
 			buildAuthService: func() service.AuthService {
 				mockService := mocks.NewAuthService(t)
-				mockService.On("RegisterExpert", mock.Anything).
+				mockService.On("RegisterExpert", mock.Anything, mock.Anything).
 					Return(errs.ErrAlreadyExists)
 
 				return mockService
@@ -124,7 +124,7 @@ func TestSignIn(t *testing.T) {
 			},
 			buildAuthService: func() service.AuthService {
 				mockService := mocks.NewAuthService(t)
-				mockService.On("SignIn", mock.Anything).
+				mockService.On("SignIn", mock.Anything, mock.Anything).
 					Return(domain.Tokens{AccessToken: "access", RefreshToken: "refresh"}, nil)
 
 				return mockService
@@ -163,7 +163,7 @@ func TestSignIn(t *testing.T) {
 			},
 			buildAuthService: func() service.AuthService {
 				mockService := mocks.NewAuthService(t)
-				mockService.On("SignIn", mock.Anything).
+				mockService.On("SignIn", mock.Anything, mock.Anything).
 					Return(domain.Tokens{}, errs.ErrNoRows)
 
 				return mockService
@@ -178,7 +178,7 @@ func TestSignIn(t *testing.T) {
 			},
 			buildAuthService: func() service.AuthService {
 				mockService := mocks.NewAuthService(t)
-				mockService.On("SignIn", mock.Anything).
+				mockService.On("SignIn", mock.Anything, mock.Anything).
 					Return(domain.Tokens{}, errs.ErrInvalidPass)
 
 				return mockService
@@ -226,7 +226,7 @@ func TestConfirmExpert(t *testing.T) {
 			},
 			buildAuthService: func() service.AuthService {
 				mockService := mocks.NewAuthService(t)
-				mockService.On("ConfirmExpert", mock.Anything).
+				mockService.On("ConfirmExpert", mock.Anything, mock.Anything).
 					Return(nil)
 
 				return mockService
@@ -251,7 +251,7 @@ func TestConfirmExpert(t *testing.T) {
 			},
 			buildAuthService: func() service.AuthService {
 				mockService := mocks.NewAuthService(t)
-				mockService.On("ConfirmExpert", mock.Anything).
+				mockService.On("ConfirmExpert", mock.Anything, mock.Anything).
 					Return(errs.ErrNoRows)
 
 				return mockService
@@ -285,7 +285,7 @@ func FuzzSignUp(f *testing.F) {
 	authConverter := converter.NewAuthConverter()
 
 	mockService := mocks.NewAuthService(f)
-	mockService.On("RegisterExpert", mock.Anything).
+	mockService.On("RegisterExpert", mock.Anything, mock.Anything).
 		Return(nil)
 
 	handler := NewAuthHandler(mockService, validate, userInfoConverter, authConverter)
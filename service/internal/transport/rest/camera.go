@@ -10,10 +10,16 @@ import (
 	"encoding/json"
 	"errors"
 	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
 	"log"
 	"net/http"
 )
 
+const (
+	cameraTypeIDPathValue = "id"
+	cameraIDPathValue     = "id"
+)
+
 type CameraHandler struct {
 	cameraService   service.CameraService
 	authService     service.AuthService
@@ -64,7 +70,7 @@ func (h *CameraHandler) AddCameraType(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	cameraTypeID, err := h.cameraService.AddCameraType(domain.CameraType{
+	cameraTypeID, err := h.cameraService.AddCameraType(r.Context(), domain.CameraType{
 		Name: cameraType.Name,
 	})
 
@@ -109,7 +115,7 @@ func (h *CameraHandler) RegisterCamera(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	cameraID, err := h.authService.RegisterCamera(
+	cameraID, err := h.authService.RegisterCamera(r.Context(),
 		h.cameraConverter.MapRegisterCameraDtoToDomain(registerInfo.CameraIn, registerInfo.SignUp),
 	)
 	if err != nil {
@@ -124,3 +130,109 @@ func (h *CameraHandler) RegisterCamera(w http.ResponseWriter, r *http.Request) {
 
 	response.IdResponse(w, cameraID)
 }
+
+// SetCameraTypeMapping docs
+// @Summary Установка соответствия полей для вида камеры
+// @Security ApiKeyAuth
+// @Tags camera
+// @Description Задает, из каких ключей бинарной строки берутся поля случая. Вложенные ключи указываются через точку, например transport.chars. Для поля date можно указать format: rfc3339, unix, parts или layout Go. Только директор может задать соответствие
+// @ID set-camera-type-mapping
+// @Accept  json
+// @Produce  json
+// @Param id path string true "id вида камеры"
+// @Param input body dto.CameraTypeMapping true "Соответствие полей"
+// @Success 200 {object} response.Body
+// @Failure 400,401,404 {object} response.Body
+// @Failure 500 {object} response.Body
+// @Failure default {object} response.Body
+// @Router /camera/type/{id}/mapping [put]
+func (h *CameraHandler) SetCameraTypeMapping(w http.ResponseWriter, r *http.Request) {
+	cameraTypeID := r.PathValue(cameraTypeIDPathValue)
+	if _, err := uuid.Parse(cameraTypeID); err != nil {
+		response.BadRequest(w, "camera type id is not uuid")
+		return
+	}
+
+	var mapping dto.CameraTypeMapping
+	err := json.NewDecoder(r.Body).Decode(&mapping)
+	if err != nil {
+		response.BadRequest(w, err.Error())
+		return
+	}
+
+	err = h.validate.Struct(mapping)
+	if err != nil {
+		response.BadRequest(w, err.Error())
+		return
+	}
+
+	err = h.cameraService.SetCameraTypeMapping(r.Context(),
+		h.cameraConverter.MapCameraTypeMappingDtoToDomain(cameraTypeID, mapping),
+	)
+	if err != nil {
+		if errors.Is(err, errs.ErrInvalidMapping) {
+			response.BadRequest(w, err.Error())
+			return
+		}
+		if errors.Is(err, errs.ErrCameraTypeNotExists) {
+			response.NotFound(w, "Camera type with input id not found")
+			return
+		}
+		log.Println(err)
+		response.InternalServerError(w)
+		return
+	}
+
+	response.OKMessage(w, "Camera type mapping updated successfully")
+}
+
+// SetCameraRedactRegions docs
+// @Summary Установка областей размытия камеры
+// @Security ApiKeyAuth
+// @Tags camera
+// @Description Задает прямоугольники в пикселях (например, тротуар с прохожими), которые размываются на фотографиях камеры перед показом экспертам и отправкой в уведомлении. Области применяются к фотографиям, загруженным после изменения. Пустой список убирает размытие. Только директор может задать области
+// @ID set-camera-redact-regions
+// @Accept  json
+// @Produce  json
+// @Param id path string true "id камеры"
+// @Param input body dto.CameraRedactRegions true "Области размытия"
+// @Success 200 {object} response.Body
+// @Failure 400,401,404 {object} response.Body
+// @Failure 500 {object} response.Body
+// @Failure default {object} response.Body
+// @Router /camera/{id}/redact_regions [put]
+func (h *CameraHandler) SetCameraRedactRegions(w http.ResponseWriter, r *http.Request) {
+	cameraID := r.PathValue(cameraIDPathValue)
+	if err := uuid.Validate(cameraID); err != nil {
+		response.BadRequest(w, "camera id is not uuid")
+		return
+	}
+
+	var regions dto.CameraRedactRegions
+	err := json.NewDecoder(r.Body).Decode(&regions)
+	if err != nil {
+		response.BadRequest(w, err.Error())
+		return
+	}
+
+	err = h.validate.Struct(regions)
+	if err != nil {
+		response.BadRequest(w, err.Error())
+		return
+	}
+
+	err = h.cameraService.SetCameraRedactRegions(r.Context(),
+		cameraID, h.cameraConverter.MapRedactRegionsDtoToDomain(regions.Regions),
+	)
+	if err != nil {
+		if errors.Is(err, errs.ErrCameraNotExists) {
+			response.NotFound(w, "Camera with input id not found")
+			return
+		}
+		log.Println(err)
+		response.InternalServerError(w)
+		return
+	}
+
+	response.OKMessage(w, "Camera redact regions updated successfully")
+}
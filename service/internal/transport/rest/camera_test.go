@@ -2,12 +2,14 @@ package rest
 
 import (
 	"TrafficPolice/internal/converter"
+	"TrafficPolice/internal/domain"
 	"TrafficPolice/internal/errs"
 	"TrafficPolice/internal/service"
 	"TrafficPolice/internal/service/mocks"
 	"TrafficPolice/internal/transport/rest/dto"
 	"bytes"
 	"encoding/json"
+	"fmt"
 	"github.com/go-playground/validator/v10"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
@@ -37,7 +39,7 @@ func TestAddCameraType(t *testing.T) {
 			},
 			buildCameraService: func() service.CameraService {
 				mockService := mocks.NewCameraService(t)
-				mockService.On("AddCameraType", mock.Anything).
+				mockService.On("AddCameraType", mock.Anything, mock.Anything).
 					Return(cameraTypeID, nil)
 
 				return mockService
@@ -55,7 +57,7 @@ func TestAddCameraType(t *testing.T) {
 			},
 			buildCameraService: func() service.CameraService {
 				mockService := mocks.NewCameraService(t)
-				mockService.On("AddCameraType", mock.Anything).
+				mockService.On("AddCameraType", mock.Anything, mock.Anything).
 					Return("", errs.ErrAlreadyExists)
 
 				return mockService
@@ -133,7 +135,7 @@ func TestRegisterCamera(t *testing.T) {
 			},
 			buildAuthService: func() service.AuthService {
 				mockService := mocks.NewAuthService(t)
-				mockService.On("RegisterCamera", mock.Anything).
+				mockService.On("RegisterCamera", mock.Anything, mock.Anything).
 					Return(cameraID, nil)
 
 				return mockService
@@ -160,7 +162,7 @@ func TestRegisterCamera(t *testing.T) {
 			},
 			buildAuthService: func() service.AuthService {
 				mockService := mocks.NewAuthService(t)
-				mockService.On("RegisterCamera", mock.Anything).
+				mockService.On("RegisterCamera", mock.Anything, mock.Anything).
 					Return(cameraID, nil)
 
 				return mockService
@@ -185,3 +187,212 @@ func TestRegisterCamera(t *testing.T) {
 		})
 	}
 }
+
+func TestSetCameraTypeMapping(t *testing.T) {
+	validate := validator.New(validator.WithRequiredStructEnabled())
+	cameraConverter := converter.NewCameraConverter()
+	path := "/camera/type/%s/mapping"
+	cameraTypeID := uuid.New().String()
+
+	validMapping := dto.CameraTypeMapping{
+		Fields: []dto.FieldMapping{
+			{CaseField: "transport.chars", PayloadKey: "transport.chars"},
+			{CaseField: "date", PayloadKey: "datetime", Format: "unix"},
+		},
+	}
+
+	testCases := []struct {
+		name               string
+		cameraTypeID       string
+		input              dto.CameraTypeMapping
+		buildCameraService func() service.CameraService
+		expectedCode       int
+	}{
+		{
+			name:         "Set camera type mapping. 200 OK",
+			cameraTypeID: cameraTypeID,
+			input:        validMapping,
+			buildCameraService: func() service.CameraService {
+				mockService := mocks.NewCameraService(t)
+				mockService.On("SetCameraTypeMapping", mock.Anything, mock.Anything).
+					Return(nil)
+
+				return mockService
+			},
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "Camera type id is not uuid. 400 Bad request",
+			cameraTypeID: "camerus",
+			input:        validMapping,
+			buildCameraService: func() service.CameraService {
+				mockService := mocks.NewCameraService(t)
+				return mockService
+			},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "Unknown case field. 400 Bad request",
+			cameraTypeID: cameraTypeID,
+			input: dto.CameraTypeMapping{
+				Fields: []dto.FieldMapping{{CaseField: "owner", PayloadKey: "owner"}},
+			},
+			buildCameraService: func() service.CameraService {
+				mockService := mocks.NewCameraService(t)
+				return mockService
+			},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "Required field is not mapped. 400 Bad request",
+			cameraTypeID: cameraTypeID,
+			input:        validMapping,
+			buildCameraService: func() service.CameraService {
+				mockService := mocks.NewCameraService(t)
+				mockService.On("SetCameraTypeMapping", mock.Anything, mock.Anything).
+					Return(errs.ErrInvalidMapping)
+
+				return mockService
+			},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "Camera type not exists. 404 Not found",
+			cameraTypeID: cameraTypeID,
+			input:        validMapping,
+			buildCameraService: func() service.CameraService {
+				mockService := mocks.NewCameraService(t)
+				mockService.On("SetCameraTypeMapping", mock.Anything, mock.Anything).
+					Return(errs.ErrCameraTypeNotExists)
+
+				return mockService
+			},
+			expectedCode: http.StatusNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			handler := NewCameraHandler(tc.buildCameraService(), mocks.NewAuthService(t), validate, cameraConverter)
+
+			var buf bytes.Buffer
+			err := json.NewEncoder(&buf).Encode(tc.input)
+			assert.NoError(t, err)
+
+			req := httptest.NewRequest(http.MethodPut, fmt.Sprintf(path, tc.cameraTypeID), &buf)
+			req.SetPathValue(cameraTypeIDPathValue, tc.cameraTypeID)
+			rec := httptest.NewRecorder()
+
+			handler.SetCameraTypeMapping(rec, req)
+			assert.Equal(t, tc.expectedCode, rec.Code)
+		})
+	}
+}
+
+func TestSetCameraRedactRegions(t *testing.T) {
+	validate := validator.New(validator.WithRequiredStructEnabled())
+	cameraConverter := converter.NewCameraConverter()
+	path := "/camera/%s/redact_regions"
+	cameraID := uuid.New().String()
+
+	validRegions := dto.CameraRedactRegions{
+		Regions: []dto.RedactRegion{{X: 0, Y: 600, Width: 400, Height: 200}},
+	}
+
+	testCases := []struct {
+		name               string
+		cameraID           string
+		input              dto.CameraRedactRegions
+		buildCameraService func() service.CameraService
+		expectedCode       int
+	}{
+		{
+			name:     "Set camera redact regions. 200 OK",
+			cameraID: cameraID,
+			input:    validRegions,
+			buildCameraService: func() service.CameraService {
+				mockService := mocks.NewCameraService(t)
+				mockService.On("SetCameraRedactRegions", mock.Anything, cameraID,
+					[]domain.RedactRegion{{X: 0, Y: 600, Width: 400, Height: 200}}).
+					Return(nil)
+
+				return mockService
+			},
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:     "Remove camera redact regions. 200 OK",
+			cameraID: cameraID,
+			input:    dto.CameraRedactRegions{Regions: []dto.RedactRegion{}},
+			buildCameraService: func() service.CameraService {
+				mockService := mocks.NewCameraService(t)
+				mockService.On("SetCameraRedactRegions", mock.Anything, cameraID, []domain.RedactRegion{}).
+					Return(nil)
+
+				return mockService
+			},
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:     "Camera id is not uuid. 400 Bad request",
+			cameraID: "camera",
+			input:    validRegions,
+			buildCameraService: func() service.CameraService {
+				return mocks.NewCameraService(t)
+			},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:     "Region has zero width. 400 Bad request",
+			cameraID: cameraID,
+			input: dto.CameraRedactRegions{
+				Regions: []dto.RedactRegion{{X: 0, Y: 0, Width: 0, Height: 10}},
+			},
+			buildCameraService: func() service.CameraService {
+				return mocks.NewCameraService(t)
+			},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:     "Region has negative coordinate. 400 Bad request",
+			cameraID: cameraID,
+			input: dto.CameraRedactRegions{
+				Regions: []dto.RedactRegion{{X: -1, Y: 0, Width: 10, Height: 10}},
+			},
+			buildCameraService: func() service.CameraService {
+				return mocks.NewCameraService(t)
+			},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:     "Camera not exists. 404 Not found",
+			cameraID: cameraID,
+			input:    validRegions,
+			buildCameraService: func() service.CameraService {
+				mockService := mocks.NewCameraService(t)
+				mockService.On("SetCameraRedactRegions", mock.Anything, cameraID, mock.Anything).
+					Return(errs.ErrCameraNotExists)
+
+				return mockService
+			},
+			expectedCode: http.StatusNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			handler := NewCameraHandler(tc.buildCameraService(), mocks.NewAuthService(t), validate, cameraConverter)
+
+			var buf bytes.Buffer
+			err := json.NewEncoder(&buf).Encode(tc.input)
+			assert.NoError(t, err)
+
+			req := httptest.NewRequest(http.MethodPut, fmt.Sprintf(path, tc.cameraID), &buf)
+			req.SetPathValue(cameraIDPathValue, tc.cameraID)
+			rec := httptest.NewRecorder()
+
+			handler.SetCameraRedactRegions(rec, req)
+			assert.Equal(t, tc.expectedCode, rec.Code)
+		})
+	}
+}
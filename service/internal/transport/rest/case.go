@@ -3,25 +3,38 @@ package rest
 import (
 	"TrafficPolice/internal/camera"
 	"TrafficPolice/internal/converter"
+	"TrafficPolice/internal/domain"
 	"TrafficPolice/internal/errs"
 	"TrafficPolice/internal/service"
+	"TrafficPolice/internal/tokens"
+	"TrafficPolice/internal/transport/rest/middlewares"
 	"TrafficPolice/internal/transport/rest/response"
+	"context"
 	"fmt"
+	"github.com/google/uuid"
 	"github.com/pkg/errors"
 	"io"
 	"log"
+	"mime"
 	"net/http"
 )
 
 const (
-	casesDir            = "cases"
 	caseContentImageKey = "image"
+	casePayloadKey      = "payload"
 	caseIDPathValue     = "id"
+	caseImgSizeQuery    = "size"
 )
 
+// caseImgSizes maps size query param to variant of case image. Originals are available only by separate route
+var caseImgSizes = map[string]domain.ImageVariant{
+	"":      domain.RedactedImage,
+	"web":   domain.WebImage,
+	"thumb": domain.ThumbImage,
+}
+
 type CaseHandler struct {
 	caseService   service.CaseService
-	imgService    service.ImgService
 	cameraService service.CameraService
 	caseConverter *converter.CaseConverter
 	cameraParser  *camera.Parser
@@ -29,7 +42,6 @@ type CaseHandler struct {
 
 func NewCaseHandler(
 	service service.CaseService,
-	imgService service.ImgService,
 	cameraService service.CameraService,
 	caseConverter *converter.CaseConverter,
 	cameraParser *camera.Parser,
@@ -37,7 +49,6 @@ func NewCaseHandler(
 
 	return &CaseHandler{
 		caseService:   service,
-		imgService:    imgService,
 		cameraService: cameraService,
 		caseConverter: caseConverter,
 		cameraParser:  cameraParser,
@@ -48,147 +59,334 @@ func NewCaseHandler(
 // @Summary Добавление информации о проишествии
 // @Security ApiKeyAuth
 // @Tags case
-// @Description Принимает бинарную строку в описанном формате. Добавить проишествие может только камера
+// @Description Принимает бинарную строку в описанном формате. Добавить проишествие может только камера,
+// @Description которой принадлежит camera_id из бинарной строки.
+// @Description Если запрос в формате multipart/form-data, то бинарная строка передается в поле payload,
+// @Description а фотография проишествия в поле image. Тогда случай сохраняется только вместе с фотографией
 // @ID case-add
-// @Accept   application/octet-stream
+// @Accept   application/octet-stream,multipart/form-data
 // @Produce  json
+// @Param payload formData file false "Бинарная строка"
+// @Param image formData file false "Фотография проишествия"
 // @Success 200 {object} response.IDResponse
-// @Failure 400,401 {object} response.Body
+// @Failure 400,401,403 {object} response.Body
 // @Failure 500 {object} response.Body
 // @Failure default {object} response.Body
 // @Router /case [post]
 func (h *CaseHandler) AddCase(w http.ResponseWriter, r *http.Request) {
-	buf, err := io.ReadAll(r.Body)
+	tokenInfo, ok := r.Context().Value(middlewares.TokenInfoKey).(tokens.TokenInfo)
+	if !ok {
+		response.InternalServerError(w)
+		return
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get(contentTypeKey))
+	if mediaType == multipartFormData {
+		h.addCaseWithImage(w, r, tokenInfo.UserID)
+		return
+	}
+
+	buf, err := io.ReadAll(http.MaxBytesReader(w, r.Body, camera.MaxPayloadSize))
 	if err != nil {
 		response.BadRequest(w, err.Error())
 		return
 	}
 
-	inputCase, err := h.cameraParser.ParseCameraInfo(buf)
+	inputCase, err := h.cameraParser.ParseCameraInfo(r.Context(), buf)
 	if err != nil {
-		if errors.Is(err, errs.ErrEmptyPayload) {
-			response.BadRequest(w, "Binary string is empty")
-			return
-		}
-		if errors.Is(err, errs.ErrUnknownCameraID) {
-			response.BadRequest(w, "Cannot parse camera id")
-			return
-		}
-		if errors.Is(err, errs.ErrUnknownCameraType) {
-			response.BadRequest(w, "Unknown camera type")
-			return
-		}
-		if errors.Is(err, errs.ErrInvalidCameraID) {
-			response.BadRequest(w, "Invalid camera id")
-			return
-		}
-		if errors.Is(err, errs.ErrCameraNotExists) {
-			response.BadRequest(w, "Camera with passed id not exists")
-			return
-		}
-		if errors.Is(err, errs.ErrInvalidViolationID) {
-			response.BadRequest(w, "Passed violation ID is invalid")
-			return
-		}
-		log.Println(err)
+		writeParseCaseError(w, err)
+		return
+	}
+
+	caseID, err := h.caseService.AddCase(r.Context(), tokenInfo.UserID, h.caseConverter.MapDtoToDomain(inputCase))
+	if err != nil {
+		writeAddCaseError(w, err)
+		return
+	}
+
+	response.IdResponse(w, caseID)
+}
+
+func (h *CaseHandler) addCaseWithImage(w http.ResponseWriter, r *http.Request, cameraUserID string) {
+	r.Body = http.MaxBytesReader(w, r.Body, camera.MaxPayloadSize+service.MaxCaseImgSize+multipartOverhead)
+
+	file, _, err := parseMultipartForm(r, caseContentImageKey)
+	if err != nil {
+		writeParseMultipartError(w, err)
+		return
+	}
+	defer file.Close()
+
+	payloadFile, _, err := r.FormFile(casePayloadKey)
+	if err != nil {
+		response.BadRequest(w, fmt.Sprintf("Error retrieving %s from form-data: %v", casePayloadKey, err))
+		return
+	}
+	defer payloadFile.Close()
+
+	payload, err := io.ReadAll(io.LimitReader(payloadFile, camera.MaxPayloadSize+1))
+	if err != nil {
+		log.Printf("Error while reading payload: %v\n", err)
 		response.InternalServerError(w)
 		return
 	}
 
-	caseID, err := h.caseService.AddCase(h.caseConverter.MapDtoToDomain(inputCase))
+	inputCase, err := h.cameraParser.ParseCameraInfo(r.Context(), payload)
 	if err != nil {
-		if errors.Is(err, errs.ErrNoTransport) {
-			response.BadRequest(w, "No transport by input credentials")
-			return
-		}
-		if errors.Is(err, errs.ErrInvalidRelevantParams) {
-			response.BadRequest(w, "Invalid relevant params")
+		writeParseCaseError(w, err)
+		return
+	}
+
+	img, err := io.ReadAll(io.LimitReader(file, service.MaxCaseImgSize+1))
+	if err != nil {
+		log.Printf("Error while reading image: %v\n", err)
+		response.InternalServerError(w)
+		return
+	}
+
+	caseID, err := h.caseService.AddCaseWithImage(
+		r.Context(), cameraUserID, h.caseConverter.MapDtoToDomain(inputCase), img,
+	)
+	if err != nil {
+		if writeCaseImgError(w, err) {
 			return
 		}
-		log.Println(err)
-		response.InternalServerError(w)
+		writeAddCaseError(w, err)
 		return
 	}
 
 	response.IdResponse(w, caseID)
 }
 
+func writeParseCaseError(w http.ResponseWriter, err error) {
+	if errors.Is(err, errs.ErrEmptyPayload) {
+		response.BadRequest(w, "Binary string is empty")
+		return
+	}
+	if errors.Is(err, errs.ErrUnknownCameraID) {
+		response.BadRequest(w, "Cannot parse camera id")
+		return
+	}
+	if errors.Is(err, errs.ErrUnknownCameraType) {
+		response.BadRequest(w, "Unknown camera type")
+		return
+	}
+	if errors.Is(err, errs.ErrInvalidCameraID) {
+		response.BadRequest(w, "Invalid camera id")
+		return
+	}
+	if errors.Is(err, errs.ErrCameraNotExists) {
+		response.BadRequest(w, "Camera with passed id not exists")
+		return
+	}
+	if errors.Is(err, errs.ErrInvalidViolationID) {
+		response.BadRequest(w, "Passed violation ID is invalid")
+		return
+	}
+	if errors.Is(err, errs.ErrInvalidPayload) {
+		response.BadRequest(w, err.Error())
+		return
+	}
+	log.Println(err)
+	response.InternalServerError(w)
+}
+
+func writeAddCaseError(w http.ResponseWriter, err error) {
+	if errors.Is(err, errs.ErrNoTransport) {
+		response.BadRequest(w, "No transport by input credentials")
+		return
+	}
+	if errors.Is(err, errs.ErrInvalidRelevantParams) {
+		response.BadRequest(w, "Invalid relevant params")
+		return
+	}
+	if errors.Is(err, errs.ErrCameraNotExists) {
+		response.BadRequest(w, "Camera with passed id not exists")
+		return
+	}
+	if errors.Is(err, errs.ErrNotCaseOwner) {
+		response.Forbidden(w, "Camera belongs to another user")
+		return
+	}
+	log.Println(err)
+	response.InternalServerError(w)
+}
+
 // UploadCaseImg docs
 // @Summary Добавление фотографии к проишествию
 // @Security ApiKeyAuth
 // @Tags case
-// @Description Принимает фотографию и сохраняет ее по переданному id. Добавить фотографию может только камера
+// @Description Принимает фотографию и сохраняет ее по переданному id. Добавить фотографию может только камера,
+// @Description которая создала проишествие. Тип фотографии определяется по содержимому (png или jpeg).
+// @Description Повторно загрузить фотографию можно, только если это разрешил директор
 // @ID case-image-upload
 // @Accept   multipart/form-data
 // @Produce  json
 // @Param id query string true "id проишествия"
 // @Param file formData file true "Фотография проишествия"
 // @Success 200 {object} response.Body
-// @Failure 400,401 {object} response.Body
+// @Failure 400,401,403,404,409,413 {object} response.Body
 // @Failure 500 {object} response.Body
 // @Failure default {object} response.Body
 // @Router /case/{id}/img [post]
 func (h *CaseHandler) UploadCaseImg(w http.ResponseWriter, r *http.Request) {
+	tokenInfo, ok := r.Context().Value(middlewares.TokenInfoKey).(tokens.TokenInfo)
+	if !ok {
+		response.InternalServerError(w)
+		return
+	}
+
 	caseID := r.PathValue(caseIDPathValue)
-	if caseID == "" {
-		response.BadRequest(w, "id is empty")
+	if err := uuid.Validate(caseID); err != nil {
+		response.BadRequest(w, "bad case id")
 		return
 	}
 
-	file, header, err := parseMultipartForm(r, caseContentImageKey)
+	r.Body = http.MaxBytesReader(w, r.Body, service.MaxCaseImgSize+multipartOverhead)
+	file, _, err := parseMultipartForm(r, caseContentImageKey)
 	if err != nil {
-		response.BadRequest(w, err.Error())
+		writeParseMultipartError(w, err)
 		return
 	}
+	defer file.Close()
 
-	contentType := header.Header.Get(contentTypeKey)
-	extension, err := getImgExtension(contentType)
+	fileBytes, err := io.ReadAll(io.LimitReader(file, service.MaxCaseImgSize+1))
 	if err != nil {
-		response.BadRequest(w, err.Error())
+		log.Printf("Error while reading image: %v\n", err)
+		response.InternalServerError(w)
 		return
 	}
 
-	imgFilePath := fmt.Sprintf("%s/%s.%s", casesDir, caseID, extension)
-	fileBytes, err := io.ReadAll(file)
+	err = h.caseService.UploadCaseImg(r.Context(), tokenInfo.UserID, caseID, fileBytes)
 	if err != nil {
-		log.Printf("Error while reading fileBytes: %v\n", fileBytes)
+		if writeCaseImgError(w, err) {
+			return
+		}
+		if errors.Is(err, errs.ErrNoCase) {
+			response.NotFound(w, "Case with passed id not exists")
+			return
+		}
+		if errors.Is(err, errs.ErrNotCaseOwner) {
+			response.Forbidden(w, "Case is created by another camera")
+			return
+		}
+		if errors.Is(err, errs.ErrImageAlreadyUploaded) {
+			response.Conflict(w, "Image of case is already uploaded")
+			return
+		}
+		log.Println(err)
 		response.InternalServerError(w)
 		return
 	}
 
-	err = h.imgService.SaveImg(fileBytes, imgFilePath)
+	response.OKMessage(w, "Successfully uploaded image")
+}
+
+// AllowCaseImgOverwrite docs
+// @Summary Разрешение повторной загрузки фотографии проишествия
+// @Security ApiKeyAuth
+// @Tags case
+// @Description Разрешает камере один раз заново загрузить фотографию проишествия. Воспользоваться может только директор
+// @ID case-image-allow-overwrite
+// @Produce  json
+// @Param id query string true "id проишествия"
+// @Success 200 {object} response.Body
+// @Failure 400,401,404 {object} response.Body
+// @Failure 500 {object} response.Body
+// @Failure default {object} response.Body
+// @Router /case/{id}/img/allow_overwrite [post]
+func (h *CaseHandler) AllowCaseImgOverwrite(w http.ResponseWriter, r *http.Request) {
+	caseID := r.PathValue(caseIDPathValue)
+	if err := uuid.Validate(caseID); err != nil {
+		response.BadRequest(w, "bad case id")
+		return
+	}
+
+	err := h.caseService.AllowCaseImgOverwrite(r.Context(), caseID)
 	if err != nil {
+		if errors.Is(err, errs.ErrNoCase) {
+			response.NotFound(w, "Case with passed id not exists")
+			return
+		}
+		log.Println(err)
 		response.InternalServerError(w)
 		return
 	}
 
-	response.OKMessage(w, "Successfully uploaded image")
+	response.OKMessage(w, "Image overwrite is allowed")
+}
+
+// writeCaseImgError writes response, if err is caused by invalid image
+func writeCaseImgError(w http.ResponseWriter, err error) bool {
+	if errors.Is(err, errs.ErrInvalidImage) {
+		response.BadRequest(w, err.Error())
+		return true
+	}
+	if errors.Is(err, errs.ErrImageTooLarge) {
+		response.RequestEntityTooLarge(w, err.Error())
+		return true
+	}
+	return false
 }
 
 // GetCaseImg docs
 // @Summary Получение фотографии проишествия
 // @Security ApiKeyAuth
 // @Tags case
-// @Description Получение фотографии проишествия по id прошествия. Воспользоваться могут эксперт или директор
+// @Description Получение фотографии проишествия по id прошествия. Воспользоваться могут эксперт или директор.
+// @Description Возвращается обработанная фотография: без метаданных EXIF/GPS и с размытыми областями камеры.
+// @Description Параметр size: web - уменьшенная до 1280 пикселей, thumb - превью до 320 пикселей, без параметра - полный размер
 // @ID case-image-get
-// @Accept   multipart/form-data
-// @Produce  json
-// @Param id query string true "id камеры"
+// @Produce  image/jpeg
+// @Param id path string true "id проишествия"
+// @Param size query string false "Размер фотографии" Enums(web, thumb)
 // @Success 200 {file} formData
 // @Failure 400,401,404 {object} response.Body
 // @Failure 500 {object} response.Body
 // @Failure default {object} response.Body
 // @Router /case/{id}/img [get]
 func (h *CaseHandler) GetCaseImg(w http.ResponseWriter, r *http.Request) {
-	caseID := r.PathValue(caseIDPathValue)
-	if caseID == "" {
+	variant, ok := caseImgSizes[r.URL.Query().Get(caseImgSizeQuery)]
+	if !ok {
+		response.BadRequest(w, "size must be web or thumb")
+		return
+	}
+
+	writeCaseImg(r.Context(), w, h.caseService, r.PathValue(caseIDPathValue), variant)
+}
+
+// GetCaseOriginalImg docs
+// @Summary Получение оригинала фотографии проишествия
+// @Security ApiKeyAuth
+// @Tags case
+// @Description Получение загруженной камерой фотографии без обработки. Воспользоваться может только директор
+// @ID case-original-image-get
+// @Produce  image/png,image/jpeg
+// @Param id path string true "id проишествия"
+// @Success 200 {file} formData
+// @Failure 400,401,404 {object} response.Body
+// @Failure 500 {object} response.Body
+// @Failure default {object} response.Body
+// @Router /case/{id}/img/original [get]
+func (h *CaseHandler) GetCaseOriginalImg(w http.ResponseWriter, r *http.Request) {
+	writeCaseImg(r.Context(), w, h.caseService, r.PathValue(caseIDPathValue), domain.OriginalImage)
+}
+
+func writeCaseImg(
+	ctx context.Context,
+	w http.ResponseWriter,
+	caseService service.CaseService,
+	caseID string,
+	variant domain.ImageVariant,
+) {
+	if err := uuid.Validate(caseID); err != nil {
 		response.BadRequest(w, "bad case id")
 		return
 	}
 
-	file, err := h.imgService.GetImgFilePath(casesDir, caseID)
+	img, err := caseService.GetCaseImg(ctx, caseID, variant)
 	if err != nil {
-		if errors.Is(err, errs.ErrNoImage) {
+		if errors.Is(err, errs.ErrNoImage) || errors.Is(err, errs.ErrNoCase) {
 			response.NotFound(w, "Image with input case id not found")
 			return
 		}
@@ -197,5 +395,5 @@ func (h *CaseHandler) GetCaseImg(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	http.ServeFile(w, r, file)
+	response.Image(w, img.ContentType, img.Data)
 }
@@ -0,0 +1,411 @@
+package rest
+
+import (
+	"TrafficPolice/internal/camera"
+	"TrafficPolice/internal/converter"
+	"TrafficPolice/internal/domain"
+	"TrafficPolice/internal/errs"
+	"TrafficPolice/internal/service"
+	"TrafficPolice/internal/service/mocks"
+	"TrafficPolice/internal/tokens"
+	"TrafficPolice/internal/transport/rest/middlewares"
+	"TrafficPolice/pkg/camerapayload"
+	"bytes"
+	"context"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/textproto"
+	"testing"
+)
+
+func TestAddCaseWithImage(t *testing.T) {
+	caseConverter := converter.NewCaseConverter()
+	path := "/case"
+	caseID := uuid.New().String()
+	cameraID := uuid.New().String()
+	tokenInfo := tokens.TokenInfo{UserID: uuid.New().String(), UserRole: domain.CameraRole}
+
+	payload, err := camerapayload.Encode(
+		camerapayload.String("transport_chars", "abc"),
+		camerapayload.String("transport_numbers", "123"),
+		camerapayload.String("transport_region", "77"),
+		camerapayload.String("camera_id", cameraID),
+		camerapayload.String("violation_id", uuid.New().String()),
+		camerapayload.String("violation_value", "90km/h"),
+		camerapayload.Int("skill_value", 1),
+		camerapayload.String("datetime", "2024-03-10T12:30:00+03:00"),
+	)
+	assert.NoError(t, err)
+
+	type part struct {
+		key         string
+		contentType string
+		data        []byte
+	}
+	payloadPart := part{key: casePayloadKey, contentType: "application/octet-stream", data: payload}
+	imagePart := part{key: caseContentImageKey, contentType: "image/png", data: []byte("image")}
+
+	testCases := []struct {
+		name               string
+		parts              []part
+		buildCaseService   func() service.CaseService
+		buildCameraService func() service.CameraService
+		expectedCode       int
+	}{
+		{
+			name:  "Add case with image. 200 OK",
+			parts: []part{payloadPart, imagePart},
+			buildCaseService: func() service.CaseService {
+				mockService := mocks.NewCaseService(t)
+				mockService.On("AddCaseWithImage", mock.Anything, tokenInfo.UserID, mock.Anything, imagePart.data).
+					Return(caseID, nil)
+
+				return mockService
+			},
+			buildCameraService: func() service.CameraService {
+				mockService := mocks.NewCameraService(t)
+				mockService.On("GetCameraTypeByCameraID", mock.Anything, cameraID).
+					Return("camerus1", nil)
+
+				return mockService
+			},
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:  "Image is missing. 400 Bad request",
+			parts: []part{payloadPart},
+			buildCaseService: func() service.CaseService {
+				mockService := mocks.NewCaseService(t)
+				return mockService
+			},
+			buildCameraService: func() service.CameraService {
+				mockService := mocks.NewCameraService(t)
+				return mockService
+			},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:  "Payload is missing. 400 Bad request",
+			parts: []part{imagePart},
+			buildCaseService: func() service.CaseService {
+				mockService := mocks.NewCaseService(t)
+				return mockService
+			},
+			buildCameraService: func() service.CameraService {
+				mockService := mocks.NewCameraService(t)
+				return mockService
+			},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:  "Transport not exists. 400 Bad request",
+			parts: []part{payloadPart, imagePart},
+			buildCaseService: func() service.CaseService {
+				mockService := mocks.NewCaseService(t)
+				mockService.On("AddCaseWithImage", mock.Anything, tokenInfo.UserID, mock.Anything, imagePart.data).
+					Return("", errs.ErrNoTransport)
+
+				return mockService
+			},
+			buildCameraService: func() service.CameraService {
+				mockService := mocks.NewCameraService(t)
+				mockService.On("GetCameraTypeByCameraID", mock.Anything, cameraID).
+					Return("camerus1", nil)
+
+				return mockService
+			},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:  "Camera belongs to another user. 403 Forbidden",
+			parts: []part{payloadPart, imagePart},
+			buildCaseService: func() service.CaseService {
+				mockService := mocks.NewCaseService(t)
+				mockService.On("AddCaseWithImage", mock.Anything, tokenInfo.UserID, mock.Anything, imagePart.data).
+					Return("", errs.ErrNotCaseOwner)
+
+				return mockService
+			},
+			buildCameraService: func() service.CameraService {
+				mockService := mocks.NewCameraService(t)
+				mockService.On("GetCameraTypeByCameraID", mock.Anything, cameraID).
+					Return("camerus1", nil)
+
+				return mockService
+			},
+			expectedCode: http.StatusForbidden,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cameraService := tc.buildCameraService()
+			parser := camera.NewParser(cameraService, camera.NewDefaultRegistry())
+			handler := NewCaseHandler(tc.buildCaseService(), cameraService, caseConverter, parser)
+
+			var body bytes.Buffer
+			writer := multipart.NewWriter(&body)
+			for _, p := range tc.parts {
+				header := make(textproto.MIMEHeader)
+				header.Set("Content-Disposition", fmt.Sprintf(`form-data; name="%s"; filename="%s"`, p.key, p.key))
+				header.Set(contentTypeKey, p.contentType)
+
+				partWriter, err := writer.CreatePart(header)
+				assert.NoError(t, err)
+				_, err = partWriter.Write(p.data)
+				assert.NoError(t, err)
+			}
+			assert.NoError(t, writer.Close())
+
+			req := httptest.NewRequest(http.MethodPost, path, &body)
+			req.Header.Set(contentTypeKey, writer.FormDataContentType())
+			rec := httptest.NewRecorder()
+
+			ctx := context.WithValue(req.Context(), middlewares.TokenInfoKey, tokenInfo)
+			handler.AddCase(rec, req.WithContext(ctx))
+			assert.Equal(t, tc.expectedCode, rec.Code)
+		})
+	}
+}
+
+func TestUploadCaseImg(t *testing.T) {
+	path := "/case/%s/img"
+	caseID := uuid.New().String()
+	cameraUserID := uuid.New().String()
+	img := []byte("image")
+
+	testCases := []struct {
+		name             string
+		caseID           string
+		img              []byte
+		buildCaseService func() service.CaseService
+		expectedCode     int
+	}{
+		{
+			name:   "Upload image. 200 OK",
+			caseID: caseID,
+			img:    img,
+			buildCaseService: func() service.CaseService {
+				mockService := mocks.NewCaseService(t)
+				mockService.On("UploadCaseImg", mock.Anything, cameraUserID, caseID, img).
+					Return(nil)
+
+				return mockService
+			},
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:   "Case id is not uuid. 400 Bad request",
+			caseID: "case",
+			img:    img,
+			buildCaseService: func() service.CaseService {
+				return mocks.NewCaseService(t)
+			},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:   "Content is not image. 400 Bad request",
+			caseID: caseID,
+			img:    img,
+			buildCaseService: func() service.CaseService {
+				mockService := mocks.NewCaseService(t)
+				mockService.On("UploadCaseImg", mock.Anything, cameraUserID, caseID, img).
+					Return(errs.ErrInvalidImage)
+
+				return mockService
+			},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:   "Case is created by another camera. 403 Forbidden",
+			caseID: caseID,
+			img:    img,
+			buildCaseService: func() service.CaseService {
+				mockService := mocks.NewCaseService(t)
+				mockService.On("UploadCaseImg", mock.Anything, cameraUserID, caseID, img).
+					Return(errs.ErrNotCaseOwner)
+
+				return mockService
+			},
+			expectedCode: http.StatusForbidden,
+		},
+		{
+			name:   "Case not exists. 404 Not found",
+			caseID: caseID,
+			img:    img,
+			buildCaseService: func() service.CaseService {
+				mockService := mocks.NewCaseService(t)
+				mockService.On("UploadCaseImg", mock.Anything, cameraUserID, caseID, img).
+					Return(errs.ErrNoCase)
+
+				return mockService
+			},
+			expectedCode: http.StatusNotFound,
+		},
+		{
+			name:   "Image is already uploaded. 409 Conflict",
+			caseID: caseID,
+			img:    img,
+			buildCaseService: func() service.CaseService {
+				mockService := mocks.NewCaseService(t)
+				mockService.On("UploadCaseImg", mock.Anything, cameraUserID, caseID, img).
+					Return(errs.ErrImageAlreadyUploaded)
+
+				return mockService
+			},
+			expectedCode: http.StatusConflict,
+		},
+		{
+			name:   "Request body is too large. 413 Request entity too large",
+			caseID: caseID,
+			img:    make([]byte, service.MaxCaseImgSize+multipartOverhead),
+			buildCaseService: func() service.CaseService {
+				return mocks.NewCaseService(t)
+			},
+			expectedCode: http.StatusRequestEntityTooLarge,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cameraService := mocks.NewCameraService(t)
+			parser := camera.NewParser(cameraService, camera.NewDefaultRegistry())
+			handler := NewCaseHandler(tc.buildCaseService(), cameraService,
+				converter.NewCaseConverter(), parser)
+
+			var body bytes.Buffer
+			writer := multipart.NewWriter(&body)
+			partWriter, err := writer.CreateFormFile(caseContentImageKey, "img")
+			assert.NoError(t, err)
+			_, err = partWriter.Write(tc.img)
+			assert.NoError(t, err)
+			assert.NoError(t, writer.Close())
+
+			req := httptest.NewRequest(http.MethodPost, fmt.Sprintf(path, tc.caseID), &body)
+			req.Header.Set(contentTypeKey, writer.FormDataContentType())
+			req.SetPathValue(caseIDPathValue, tc.caseID)
+			ctx := context.WithValue(req.Context(), middlewares.TokenInfoKey,
+				tokens.TokenInfo{UserID: cameraUserID, UserRole: domain.CameraRole})
+			rec := httptest.NewRecorder()
+
+			handler.UploadCaseImg(rec, req.WithContext(ctx))
+			assert.Equal(t, tc.expectedCode, rec.Code)
+		})
+	}
+}
+
+func TestGetCaseImg(t *testing.T) {
+	caseID := uuid.New().String()
+	img := domain.Image{Data: []byte("image"), ContentType: "image/jpeg"}
+
+	testCases := []struct {
+		name             string
+		caseID           string
+		size             string
+		original         bool
+		buildCaseService func() service.CaseService
+		expectedCode     int
+	}{
+		{
+			name:   "Get redacted image. 200 OK",
+			caseID: caseID,
+			buildCaseService: func() service.CaseService {
+				mockService := mocks.NewCaseService(t)
+				mockService.On("GetCaseImg", mock.Anything, caseID, domain.RedactedImage).
+					Return(img, nil)
+
+				return mockService
+			},
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:   "Get thumbnail. 200 OK",
+			caseID: caseID,
+			size:   "thumb",
+			buildCaseService: func() service.CaseService {
+				mockService := mocks.NewCaseService(t)
+				mockService.On("GetCaseImg", mock.Anything, caseID, domain.ThumbImage).
+					Return(img, nil)
+
+				return mockService
+			},
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:     "Get original image. 200 OK",
+			caseID:   caseID,
+			original: true,
+			buildCaseService: func() service.CaseService {
+				mockService := mocks.NewCaseService(t)
+				mockService.On("GetCaseImg", mock.Anything, caseID, domain.OriginalImage).
+					Return(img, nil)
+
+				return mockService
+			},
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:   "Unknown size. 400 Bad request",
+			caseID: caseID,
+			size:   "original",
+			buildCaseService: func() service.CaseService {
+				return mocks.NewCaseService(t)
+			},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:   "Case id is not uuid. 400 Bad request",
+			caseID: "case",
+			buildCaseService: func() service.CaseService {
+				return mocks.NewCaseService(t)
+			},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:   "Image not exists. 404 Not found",
+			caseID: caseID,
+			size:   "web",
+			buildCaseService: func() service.CaseService {
+				mockService := mocks.NewCaseService(t)
+				mockService.On("GetCaseImg", mock.Anything, caseID, domain.WebImage).
+					Return(domain.Image{}, errs.ErrNoImage)
+
+				return mockService
+			},
+			expectedCode: http.StatusNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cameraService := mocks.NewCameraService(t)
+			parser := camera.NewParser(cameraService, camera.NewDefaultRegistry())
+			handler := NewCaseHandler(tc.buildCaseService(), cameraService,
+				converter.NewCaseConverter(), parser)
+
+			path := fmt.Sprintf("/case/%s/img?size=%s", tc.caseID, tc.size)
+			if tc.original {
+				path = fmt.Sprintf("/case/%s/img/original", tc.caseID)
+			}
+			req := httptest.NewRequest(http.MethodGet, path, nil)
+			req.SetPathValue(caseIDPathValue, tc.caseID)
+			rec := httptest.NewRecorder()
+
+			if tc.original {
+				handler.GetCaseOriginalImg(rec, req)
+			} else {
+				handler.GetCaseImg(rec, req)
+			}
+			assert.Equal(t, tc.expectedCode, rec.Code)
+			if tc.expectedCode == http.StatusOK {
+				assert.Equal(t, img.Data, rec.Body.Bytes())
+				assert.Equal(t, img.ContentType, rec.Header().Get(contentTypeKey))
+			}
+		})
+	}
+}
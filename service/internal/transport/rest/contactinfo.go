@@ -7,6 +7,7 @@ import (
 	"github.com/xuri/excelize/v2"
 	"log"
 	"net/http"
+	"strings"
 )
 
 const (
@@ -16,6 +17,8 @@ const (
 
 const (
 	contactInfoMaxMemory = int64(10 << 30)
+	// notifyChannelsSeparator separates channels in column of notification channels
+	notifyChannelsSeparator = ","
 )
 
 type ContactInfoHandler struct {
@@ -30,7 +33,7 @@ func NewContactInfoHandler(service service.ContactInfoService) *ContactInfoHandl
 // @Summary Ввод информации о транспорте и его владельце
 // @Security ApiKeyAuth
 // @Tags contact_info
-// @Description Принимает excel файл в формате по столбикам: Буквы авто:Номера авто:Регион:Номер телефона:email:VK ID: Tg ID. Только директор может загрузить файл
+// @Description Принимает excel файл в формате по столбикам: Буквы авто:Номера авто:Регион:Номер телефона:email:VK ID: Tg ID: Каналы уведомлений. Каналы уведомлений (email, telegram, vk, sms) перечисляются через запятую в порядке предпочтения, столбец необязателен. Только директор может загрузить файл
 // @ID insert-contact-info
 // @Accept  multipart/form-data
 // @Produce  json
@@ -71,7 +74,7 @@ func (h *ContactInfoHandler) InsertContactInfo(w http.ResponseWriter, r *http.Re
 
 	info := h.parseContactInfo(rows)
 
-	err = h.service.InsertContactInfo(info)
+	err = h.service.InsertContactInfo(r.Context(), info)
 	if err != nil {
 		log.Println(err)
 		response.InternalServerError(w)
@@ -86,16 +89,17 @@ func (h *ContactInfoHandler) parseContactInfo(rows [][]string) map[string][]*dom
 
 	for _, row := range rows {
 		transport := &domain.Transport{
-			Chars:  row[0],
-			Num:    row[1],
-			Region: row[2],
+			Chars:  cell(row, 0),
+			Num:    cell(row, 1),
+			Region: cell(row, 2),
 		}
 
 		person := &domain.Person{
-			PhoneNum: row[3],
-			Email:    row[4],
-			VkID:     row[5],
-			TgID:     row[6],
+			PhoneNum:       cell(row, 3),
+			Email:          cell(row, 4),
+			VkID:           cell(row, 5),
+			TgID:           cell(row, 6),
+			NotifyChannels: parseNotifyChannels(cell(row, 7)),
 		}
 		transport.Person = person
 
@@ -107,3 +111,23 @@ func (h *ContactInfoHandler) parseContactInfo(rows [][]string) map[string][]*dom
 
 	return m
 }
+
+// cell returns value of column i. Excel rows are returned without trailing empty cells
+func cell(row []string, i int) string {
+	if i < len(row) {
+		return row[i]
+	}
+	return ""
+}
+
+func parseNotifyChannels(s string) []string {
+	var channels []string
+	for _, channel := range strings.Split(s, notifyChannelsSeparator) {
+		channel = strings.ToLower(strings.TrimSpace(channel))
+		if channel != "" {
+			channels = append(channels, channel)
+		}
+	}
+
+	return channels
+}
@@ -0,0 +1,43 @@
+package rest
+
+import (
+	"TrafficPolice/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestParseContactInfo(t *testing.T) {
+	handler := NewContactInfoHandler(nil)
+
+	testCases := []struct {
+		name     string
+		row      []string
+		expected domain.Person
+	}{
+		{
+			name: "Row with notification channels",
+			row:  []string{"ab", "123", "77", "+79990000000", "a@example.com", "vk", "tg", " Telegram, email ,,"},
+			expected: domain.Person{
+				PhoneNum: "+79990000000", Email: "a@example.com", VkID: "vk", TgID: "tg",
+				NotifyChannels: []string{"telegram", "email"},
+			},
+		},
+		{
+			name:     "Row without trailing empty cells",
+			row:      []string{"ab", "123", "77", "+79990000000", "a@example.com"},
+			expected: domain.Person{PhoneNum: "+79990000000", Email: "a@example.com"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			info := handler.parseContactInfo([][]string{tc.row})
+
+			transports := info[tc.expected.PhoneNum]
+			if assert.Len(t, transports, 1) {
+				assert.Equal(t, tc.expected, *transports[0].Person)
+				assert.Equal(t, tc.row[0], transports[0].Chars)
+			}
+		})
+	}
+}
@@ -2,9 +2,12 @@ package rest
 
 import (
 	"TrafficPolice/internal/converter"
+	"TrafficPolice/internal/domain"
 	"TrafficPolice/internal/errs"
 	"TrafficPolice/internal/service"
+	"TrafficPolice/internal/tokens"
 	"TrafficPolice/internal/transport/rest/dto"
+	"TrafficPolice/internal/transport/rest/middlewares"
 	"TrafficPolice/internal/transport/rest/response"
 	"encoding/json"
 	"errors"
@@ -12,14 +15,23 @@ import (
 	"github.com/google/uuid"
 	"log"
 	"net/http"
+	"strconv"
+	"strings"
 	"time"
+	"unicode/utf8"
 )
 
 const (
-	expertIDKey  = "id"
-	startTimeKey = "start_time"
-	endTimeKey   = "end_time"
-	caseIDKey    = "id"
+	expertIDKey           = "id"
+	startTimeKey          = "start_time"
+	endTimeKey            = "end_time"
+	caseIDKey             = "id"
+	reviewCaseIDPathValue = "id"
+	reviewOffsetKey       = "offset"
+	defaultReviewLimit    = 100
+	overrideCaseIDValue   = "id"
+	// maxOverrideReasonLen limits explanation of director in characters
+	maxOverrideReasonLen = 1000
 )
 
 type DirectorHandler struct {
@@ -65,7 +77,7 @@ func (h *DirectorHandler) GetCase(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	caseStatus, err := h.directorService.GetCase(caseID)
+	caseStatus, err := h.directorService.GetCase(r.Context(), caseID)
 	if err != nil {
 		if errors.Is(err, errs.ErrNoCase) {
 			response.NotFound(w, "Case with input id not found")
@@ -119,7 +131,7 @@ func (h *DirectorHandler) ExpertAnalytics(w http.ResponseWriter, r *http.Request
 		return
 	}
 
-	analytics, err := h.directorService.GetExpertAnalytics(expertID, startTime, endTime)
+	analytics, err := h.directorService.GetExpertAnalytics(r.Context(), expertID, startTime, endTime)
 	if err != nil {
 		if errors.Is(err, errs.ErrExpertNotExists) {
 			response.NotFound(w, "Expert with input ID not found")
@@ -164,7 +176,7 @@ func (h *DirectorHandler) UpdateExpertSkill(w http.ResponseWriter, r *http.Reque
 		return
 	}
 
-	err = h.directorService.UpdateExpertSkill(input.ExpertID, input.Skill)
+	err = h.directorService.UpdateExpertSkill(r.Context(), input.ExpertID, input.Skill)
 	if err != nil {
 		response.InternalServerError(w)
 		log.Println(err)
@@ -174,6 +186,348 @@ func (h *DirectorHandler) UpdateExpertSkill(w http.ResponseWriter, r *http.Reque
 	response.OKMessage(w, "Competence skill of expert updated successfully")
 }
 
+// GetReviewCases docs
+// @Summary Получение случаев на рассмотрении директора
+// @Security ApiKeyAuth
+// @Tags director
+// @Description Получение нерешенных случаев, по которым эксперты максимального уровня компетенций не достигли консенсуса,
+// @Description с количеством оценок экспертов. Случаи отсортированы по времени передачи директору.
+// @Description Воспользоваться может только директор
+// @ID director-reviews-get
+// @Produce  json
+// @Param limit query int false "Максимальное количество случаев. По умолчанию 100"
+// @Param offset query int false "Количество пропущенных случаев. По умолчанию 0"
+// @Success 200 {object} []dto.ReviewCase
+// @Success 204 ""
+// @Failure 400,401 {object} response.Body
+// @Failure 500 {object} response.Body
+// @Failure default {object} response.Body
+// @Router /director/reviews [get]
+func (h *DirectorHandler) GetReviewCases(w http.ResponseWriter, r *http.Request) {
+	limit := defaultReviewLimit
+	if limitQuery := r.URL.Query().Get(limitKey); limitQuery != "" {
+		var err error
+		limit, err = strconv.Atoi(limitQuery)
+		if err != nil || limit <= 0 {
+			response.BadRequest(w, "limit must be positive number")
+			return
+		}
+	}
+	offset := 0
+	if offsetQuery := r.URL.Query().Get(reviewOffsetKey); offsetQuery != "" {
+		var err error
+		offset, err = strconv.Atoi(offsetQuery)
+		if err != nil || offset < 0 {
+			response.BadRequest(w, "offset must be not negative number")
+			return
+		}
+	}
+
+	reviewCases, err := h.directorService.GetReviewCases(r.Context(), limit, offset)
+	if err != nil {
+		if errors.Is(err, errs.ErrNoRows) {
+			response.NoContent(w)
+			return
+		}
+		log.Println(err)
+		response.InternalServerError(w)
+		return
+	}
+
+	casesBytes, err := json.Marshal(h.caseConverter.MapReviewCasesToDto(reviewCases))
+	if err != nil {
+		log.Println(err)
+		response.InternalServerError(w)
+		return
+	}
+
+	response.WriteResponse(w, http.StatusOK, casesBytes)
+}
+
+// ResolveReviewCase docs
+// @Summary Решение директора по случаю на рассмотрении
+// @Security ApiKeyAuth
+// @Tags director
+// @Description Решает случай на рассмотрении так же, как консенсус экспертов: обновляется рейтинг экспертов,
+// @Description а при решении о штрафе выставляется штраф и отправляется уведомление. Воспользоваться может только директор
+// @ID director-reviews-resolve
+// @Accept  json
+// @Produce  json
+// @Param id path string true "id случая"
+// @Param input body dto.ResolveReviewCase true "Решение о штрафе"
+// @Success 200 {object} response.Body
+// @Failure 400,401,404,409 {object} response.Body
+// @Failure 500 {object} response.Body
+// @Failure default {object} response.Body
+// @Router /director/reviews/{id}/resolve [post]
+func (h *DirectorHandler) ResolveReviewCase(w http.ResponseWriter, r *http.Request) {
+	caseID := r.PathValue(reviewCaseIDPathValue)
+	if err := uuid.Validate(caseID); err != nil {
+		response.BadRequest(w, "case id is not uuid")
+		return
+	}
+
+	var input dto.ResolveReviewCase
+	err := json.NewDecoder(r.Body).Decode(&input)
+	if err != nil {
+		response.BadRequest(w, err.Error())
+		return
+	}
+	if input.FineDecision == nil {
+		response.BadRequest(w, "fine_decision is required")
+		return
+	}
+
+	err = h.directorService.ResolveReviewCase(r.Context(), caseID, *input.FineDecision)
+	if err != nil {
+		switch {
+		case errors.Is(err, errs.ErrNoCase):
+			response.NotFound(w, "Case with input id not found")
+		case errors.Is(err, errs.ErrCaseAlreadySolved):
+			response.Conflict(w, "Case is already solved")
+		case errors.Is(err, errs.ErrCaseNotInReview):
+			response.Conflict(w, "Case is not in review of director")
+		default:
+			log.Println(err)
+			response.InternalServerError(w)
+		}
+		return
+	}
+
+	response.OKMessage(w, "Case is resolved by director")
+}
+
+// OverrideCaseDecision docs
+// @Summary Изменение решения о штрафе по решенному случаю
+// @Security ApiKeyAuth
+// @Tags director
+// @Description Изменяет решение о штрафе по решенному случаю с обязательным указанием причины, изменение сохраняется
+// @Description в журнале изменений. Рейтинг экспертов пересчитывается по новому решению. При отмене штрафа неоплаченный
+// @Description штраф аннулируется и владельцу транспорта отправляется уведомление об аннулировании, при решении о штрафе
+// @Description выставляется штраф и отправляется уведомление. Воспользоваться может только директор
+// @ID director-case-override
+// @Accept  json
+// @Produce  json
+// @Param id path string true "id случая"
+// @Param input body dto.OverrideCaseDecision true "Новое решение о штрафе и причина изменения"
+// @Success 200 {object} dto.CaseOverride
+// @Failure 400,401,404,409 {object} response.Body
+// @Failure 500 {object} response.Body
+// @Failure default {object} response.Body
+// @Router /director/cases/{id}/override [post]
+func (h *DirectorHandler) OverrideCaseDecision(w http.ResponseWriter, r *http.Request) {
+	caseID, userID, ok := h.parseCorrection(w, r)
+	if !ok {
+		return
+	}
+
+	var input dto.OverrideCaseDecision
+	err := json.NewDecoder(r.Body).Decode(&input)
+	if err != nil {
+		response.BadRequest(w, err.Error())
+		return
+	}
+	if input.FineDecision == nil {
+		response.BadRequest(w, "fine_decision is required")
+		return
+	}
+	reason, err := parseOverrideReason(input.Reason)
+	if err != nil {
+		response.BadRequest(w, err.Error())
+		return
+	}
+
+	override, err := h.directorService.OverrideCaseDecision(r.Context(), userID, caseID, *input.FineDecision, reason)
+	h.writeOverride(w, override, err)
+}
+
+// VoidCaseFine docs
+// @Summary Аннулирование штрафа по случаю
+// @Security ApiKeyAuth
+// @Tags director
+// @Description Аннулирует неоплаченный штраф, выставленный по ошибке, с обязательным указанием причины, изменение
+// @Description сохраняется в журнале изменений. Владельцу транспорта отправляется уведомление об аннулировании.
+// @Description Решение о штрафе и рейтинг экспертов не изменяются. Воспользоваться может только директор
+// @ID director-case-void-fine
+// @Accept  json
+// @Produce  json
+// @Param id path string true "id случая"
+// @Param input body dto.CaseCorrection true "Причина аннулирования"
+// @Success 200 {object} dto.CaseOverride
+// @Failure 400,401,404,409 {object} response.Body
+// @Failure 500 {object} response.Body
+// @Failure default {object} response.Body
+// @Router /director/cases/{id}/void_fine [post]
+func (h *DirectorHandler) VoidCaseFine(w http.ResponseWriter, r *http.Request) {
+	caseID, userID, ok := h.parseCorrection(w, r)
+	if !ok {
+		return
+	}
+
+	reason, ok := h.decodeCorrectionReason(w, r)
+	if !ok {
+		return
+	}
+
+	override, err := h.directorService.VoidCaseFine(r.Context(), userID, caseID, reason)
+	h.writeOverride(w, override, err)
+}
+
+// ReopenCase docs
+// @Summary Повторное открытие решенного случая
+// @Security ApiKeyAuth
+// @Tags director
+// @Description Возвращает решенный случай экспертам для повторной оценки с обязательным указанием причины, изменение
+// @Description сохраняется в журнале изменений. Оценки экспертов аннулируются, а рейтинг, полученный за них, отменяется.
+// @Description Неоплаченный штраф аннулируется, владельцу транспорта отправляется уведомление об аннулировании.
+// @Description Воспользоваться может только директор
+// @ID director-case-reopen
+// @Accept  json
+// @Produce  json
+// @Param id path string true "id случая"
+// @Param input body dto.CaseCorrection true "Причина повторного открытия"
+// @Success 200 {object} dto.CaseOverride
+// @Failure 400,401,404,409 {object} response.Body
+// @Failure 500 {object} response.Body
+// @Failure default {object} response.Body
+// @Router /director/cases/{id}/reopen [post]
+func (h *DirectorHandler) ReopenCase(w http.ResponseWriter, r *http.Request) {
+	caseID, userID, ok := h.parseCorrection(w, r)
+	if !ok {
+		return
+	}
+
+	reason, ok := h.decodeCorrectionReason(w, r)
+	if !ok {
+		return
+	}
+
+	override, err := h.directorService.ReopenCase(r.Context(), userID, caseID, reason)
+	h.writeOverride(w, override, err)
+}
+
+// parseCorrection returns id of corrected case and id of director. Response is written, when ok is false
+// NewNotificationToken docs
+// @Summary Получение токена для fine_notification
+// @Security ApiKeyAuth
+// @Tags director
+// @Description Выдает токен директора для HTTP сервера fine_notification (предпросмотр шаблонов, статус доставки,
+// @Description постановления). Токен подписан отдельным ключом и не принимается service.
+// @Description Воспользоваться может только директор
+// @ID director-notification-token
+// @Produce  json
+// @Success 200 {object} dto.NotificationToken
+// @Failure 401,404 {object} response.Body
+// @Failure 500 {object} response.Body
+// @Failure default {object} response.Body
+// @Router /director/notification_token [get]
+func (h *DirectorHandler) NewNotificationToken(w http.ResponseWriter, r *http.Request) {
+	tokenInfo, ok := r.Context().Value(middlewares.TokenInfoKey).(tokens.TokenInfo)
+	if !ok {
+		response.InternalServerError(w)
+		return
+	}
+
+	token, err := h.directorService.NewNotificationToken(r.Context(), tokenInfo.UserID)
+	if err != nil {
+		if errors.Is(err, errs.ErrNotificationDisabled) {
+			response.NotFound(w, "Signing key of fine_notification is not set")
+			return
+		}
+		log.Println(err)
+		response.InternalServerError(w)
+		return
+	}
+
+	tokenBytes, err := json.Marshal(dto.NotificationToken{Token: token})
+	if err != nil {
+		log.Println(err)
+		response.InternalServerError(w)
+		return
+	}
+
+	response.WriteResponse(w, http.StatusOK, tokenBytes)
+}
+
+func (h *DirectorHandler) parseCorrection(w http.ResponseWriter, r *http.Request) (string, string, bool) {
+	caseID := r.PathValue(overrideCaseIDValue)
+	if err := uuid.Validate(caseID); err != nil {
+		response.BadRequest(w, "case id is not uuid")
+		return "", "", false
+	}
+
+	tokenInfo, ok := r.Context().Value(middlewares.TokenInfoKey).(tokens.TokenInfo)
+	if !ok {
+		response.InternalServerError(w)
+		return "", "", false
+	}
+
+	return caseID, tokenInfo.UserID, true
+}
+
+// decodeCorrectionReason returns reason of request body. Response is written, when ok is false
+func (h *DirectorHandler) decodeCorrectionReason(w http.ResponseWriter, r *http.Request) (string, bool) {
+	var input dto.CaseCorrection
+	err := json.NewDecoder(r.Body).Decode(&input)
+	if err != nil {
+		response.BadRequest(w, err.Error())
+		return "", false
+	}
+
+	reason, err := parseOverrideReason(input.Reason)
+	if err != nil {
+		response.BadRequest(w, err.Error())
+		return "", false
+	}
+
+	return reason, true
+}
+
+func (h *DirectorHandler) writeOverride(w http.ResponseWriter, override domain.CaseOverride, err error) {
+	if err != nil {
+		switch {
+		case errors.Is(err, errs.ErrNoCase):
+			response.NotFound(w, "Case with input id not found")
+		case errors.Is(err, errs.ErrNoFine):
+			response.NotFound(w, "Case has no fine")
+		case errors.Is(err, errs.ErrCaseNotSolved):
+			response.Conflict(w, "Case is not solved")
+		case errors.Is(err, errs.ErrDecisionNotChanged):
+			response.Conflict(w, "Case already has this fine decision")
+		case errors.Is(err, errs.ErrFineCancelled):
+			response.Conflict(w, "Fine is already cancelled")
+		case errors.Is(err, errs.ErrFineCannotBeCancelled):
+			response.Conflict(w, "Paid fine can not be cancelled")
+		default:
+			log.Println(err)
+			response.InternalServerError(w)
+		}
+		return
+	}
+
+	overrideBytes, err := json.Marshal(h.caseConverter.MapCaseOverrideToDto(override))
+	if err != nil {
+		log.Println(err)
+		response.InternalServerError(w)
+		return
+	}
+
+	response.WriteResponse(w, http.StatusOK, overrideBytes)
+}
+
+// parseOverrideReason returns trimmed reason, reason is required for every correction of case
+func parseOverrideReason(reason string) (string, error) {
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return "", errors.New("reason is required")
+	}
+	if utf8.RuneCountInString(reason) > maxOverrideReasonLen {
+		return "", fmt.Errorf("reason is longer than %d characters", maxOverrideReasonLen)
+	}
+
+	return reason, nil
+}
+
 func (h *DirectorHandler) parseTimeQuery(r *http.Request, key string) (time.Time, error) {
 	timeQuery := r.URL.Query().Get(key)
 	if timeQuery == "" {
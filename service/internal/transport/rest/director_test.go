@@ -6,13 +6,19 @@ import (
 	"TrafficPolice/internal/errs"
 	"TrafficPolice/internal/service"
 	"TrafficPolice/internal/service/mocks"
+	"TrafficPolice/internal/tokens"
+	"TrafficPolice/internal/transport/rest/dto"
+	"TrafficPolice/internal/transport/rest/middlewares"
 	"bytes"
+	"context"
+	"encoding/json"
 	"fmt"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 )
@@ -36,7 +42,7 @@ func TestGetCases(t *testing.T) {
 			caseIDParam: caseID,
 			buildDirectorService: func() service.DirectorService {
 				mockService := mocks.NewDirectorService(t)
-				mockService.On("GetCase", caseID).
+				mockService.On("GetCase", mock.Anything, caseID).
 					Return(caseStatus, nil)
 
 				return mockService
@@ -68,7 +74,7 @@ func TestGetCases(t *testing.T) {
 			caseIDParam: caseID,
 			buildDirectorService: func() service.DirectorService {
 				mockService := mocks.NewDirectorService(t)
-				mockService.On("GetCase", caseID).
+				mockService.On("GetCase", mock.Anything, caseID).
 					Return(domain.CaseStatus{}, errs.ErrNoCase)
 
 				return mockService
@@ -117,7 +123,7 @@ func TestExpertAnalytics(t *testing.T) {
 			name: "Get analytics. Correct Params. 200 OK",
 			buildDirectorService: func() service.DirectorService {
 				mockService := mocks.NewDirectorService(t)
-				mockService.On("GetExpertAnalytics", mock.Anything, mock.Anything, mock.Anything).
+				mockService.On("GetExpertAnalytics", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
 					Return([]domain.AnalyticsInterval{}, nil)
 
 				return mockService
@@ -174,7 +180,7 @@ func TestExpertAnalytics(t *testing.T) {
 			name: "Expert with input id not found. 404 Not found",
 			buildDirectorService: func() service.DirectorService {
 				mockService := mocks.NewDirectorService(t)
-				mockService.On("GetExpertAnalytics", mock.Anything, mock.Anything, mock.Anything).
+				mockService.On("GetExpertAnalytics", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
 					Return(nil, errs.ErrExpertNotExists)
 				return mockService
 			},
@@ -189,7 +195,7 @@ func TestExpertAnalytics(t *testing.T) {
 			name: "No analytics for the expert. 204 No content",
 			buildDirectorService: func() service.DirectorService {
 				mockService := mocks.NewDirectorService(t)
-				mockService.On("GetExpertAnalytics", mock.Anything, mock.Anything, mock.Anything).
+				mockService.On("GetExpertAnalytics", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
 					Return([]domain.AnalyticsInterval{}, errs.ErrNoRows)
 				return mockService
 			},
@@ -234,3 +240,424 @@ func TestExpertAnalytics(t *testing.T) {
 		})
 	}
 }
+
+func TestGetReviewCases(t *testing.T) {
+	caseConverter := converter.NewCaseConverter()
+	analyticsConverter := converter.NewAnalyticsConverter()
+	reviewCases := []domain.ReviewCase{{CaseID: uuid.New().String(), RequiredSkill: 5, ReviewRequestedAt: time.Now()}}
+
+	testCases := []struct {
+		name                 string
+		query                string
+		buildDirectorService func() service.DirectorService
+		expectedCode         int
+	}{
+		{
+			name: "Default limit and offset. 200 OK",
+			buildDirectorService: func() service.DirectorService {
+				mockService := mocks.NewDirectorService(t)
+				mockService.On("GetReviewCases", mock.Anything, defaultReviewLimit, 0).
+					Return(reviewCases, nil)
+
+				return mockService
+			},
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:  "No cases in review. 204 No content",
+			query: "?limit=10&offset=20",
+			buildDirectorService: func() service.DirectorService {
+				mockService := mocks.NewDirectorService(t)
+				mockService.On("GetReviewCases", mock.Anything, 10, 20).
+					Return(nil, errs.ErrNoRows)
+
+				return mockService
+			},
+			expectedCode: http.StatusNoContent,
+		},
+		{
+			name:  "Negative offset. 400 Bad request",
+			query: "?offset=-1",
+			buildDirectorService: func() service.DirectorService {
+				return mocks.NewDirectorService(t)
+			},
+			expectedCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			handler := NewDirectorHandler(tc.buildDirectorService(), caseConverter, analyticsConverter)
+
+			req := httptest.NewRequest(http.MethodGet, "/director/reviews"+tc.query, nil)
+			rec := httptest.NewRecorder()
+
+			handler.GetReviewCases(rec, req)
+			assert.Equal(t, tc.expectedCode, rec.Code)
+		})
+	}
+}
+
+func TestResolveReviewCase(t *testing.T) {
+	caseConverter := converter.NewCaseConverter()
+	analyticsConverter := converter.NewAnalyticsConverter()
+	caseID := uuid.New().String()
+
+	testCases := []struct {
+		name                 string
+		caseID               string
+		body                 string
+		buildDirectorService func() service.DirectorService
+		expectedCode         int
+	}{
+		{
+			name:   "Resolve case. 200 OK",
+			caseID: caseID,
+			body:   `{"fine_decision": false}`,
+			buildDirectorService: func() service.DirectorService {
+				mockService := mocks.NewDirectorService(t)
+				mockService.On("ResolveReviewCase", mock.Anything, caseID, false).
+					Return(nil)
+
+				return mockService
+			},
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:   "Fine decision is missing. 400 Bad request",
+			caseID: caseID,
+			body:   `{}`,
+			buildDirectorService: func() service.DirectorService {
+				return mocks.NewDirectorService(t)
+			},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:   "Case id is not uuid. 400 Bad request",
+			caseID: "case_id",
+			body:   `{"fine_decision": true}`,
+			buildDirectorService: func() service.DirectorService {
+				return mocks.NewDirectorService(t)
+			},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:   "Case not exists. 404 Not found",
+			caseID: caseID,
+			body:   `{"fine_decision": true}`,
+			buildDirectorService: func() service.DirectorService {
+				mockService := mocks.NewDirectorService(t)
+				mockService.On("ResolveReviewCase", mock.Anything, caseID, true).
+					Return(errs.ErrNoCase)
+
+				return mockService
+			},
+			expectedCode: http.StatusNotFound,
+		},
+		{
+			name:   "Case is not in review. 409 Conflict",
+			caseID: caseID,
+			body:   `{"fine_decision": true}`,
+			buildDirectorService: func() service.DirectorService {
+				mockService := mocks.NewDirectorService(t)
+				mockService.On("ResolveReviewCase", mock.Anything, caseID, true).
+					Return(errs.ErrCaseNotInReview)
+
+				return mockService
+			},
+			expectedCode: http.StatusConflict,
+		},
+		{
+			name:   "Case is already solved. 409 Conflict",
+			caseID: caseID,
+			body:   `{"fine_decision": true}`,
+			buildDirectorService: func() service.DirectorService {
+				mockService := mocks.NewDirectorService(t)
+				mockService.On("ResolveReviewCase", mock.Anything, caseID, true).
+					Return(errs.ErrCaseAlreadySolved)
+
+				return mockService
+			},
+			expectedCode: http.StatusConflict,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			handler := NewDirectorHandler(tc.buildDirectorService(), caseConverter, analyticsConverter)
+
+			req := httptest.NewRequest(http.MethodPost, "/director/reviews/"+tc.caseID+"/resolve",
+				bytes.NewBufferString(tc.body))
+			req.SetPathValue(reviewCaseIDPathValue, tc.caseID)
+			rec := httptest.NewRecorder()
+
+			handler.ResolveReviewCase(rec, req)
+			assert.Equal(t, tc.expectedCode, rec.Code)
+		})
+	}
+}
+
+func TestOverrideCaseDecision(t *testing.T) {
+	caseConverter := converter.NewCaseConverter()
+	analyticsConverter := converter.NewAnalyticsConverter()
+	caseID := uuid.New().String()
+	userID := uuid.New().String()
+	fineID := uuid.New().String()
+	tokenInfo := tokens.TokenInfo{UserID: userID, UserRole: domain.DirectorRole}
+	noFine := false
+	override := domain.CaseOverride{
+		ID: uuid.New().String(), CaseID: caseID, UserID: userID, Action: domain.OverrideDecisionAction,
+		OldFineDecision: true, NewFineDecision: &noFine, FineID: &fineID, Reason: "Неверный номер",
+		CreatedAt: time.Now(),
+	}
+
+	testCases := []struct {
+		name                 string
+		caseID               string
+		body                 string
+		buildDirectorService func() service.DirectorService
+		expectedCode         int
+	}{
+		{
+			name:   "Override decision. 200 OK",
+			caseID: caseID,
+			body:   `{"fine_decision": false, "reason": "  Неверный номер  "}`,
+			buildDirectorService: func() service.DirectorService {
+				mockService := mocks.NewDirectorService(t)
+				mockService.On("OverrideCaseDecision", mock.Anything, userID, caseID, false, "Неверный номер").
+					Return(override, nil)
+
+				return mockService
+			},
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:   "Reason is empty. 400 Bad request",
+			caseID: caseID,
+			body:   `{"fine_decision": false, "reason": " "}`,
+			buildDirectorService: func() service.DirectorService {
+				return mocks.NewDirectorService(t)
+			},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:   "Reason is too long. 400 Bad request",
+			caseID: caseID,
+			body:   `{"fine_decision": false, "reason": "` + strings.Repeat("я", maxOverrideReasonLen+1) + `"}`,
+			buildDirectorService: func() service.DirectorService {
+				return mocks.NewDirectorService(t)
+			},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:   "Fine decision is missing. 400 Bad request",
+			caseID: caseID,
+			body:   `{"reason": "Неверный номер"}`,
+			buildDirectorService: func() service.DirectorService {
+				return mocks.NewDirectorService(t)
+			},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:   "Case id is not uuid. 400 Bad request",
+			caseID: "case_id",
+			body:   `{"fine_decision": false, "reason": "Неверный номер"}`,
+			buildDirectorService: func() service.DirectorService {
+				return mocks.NewDirectorService(t)
+			},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:   "Case is not solved. 409 Conflict",
+			caseID: caseID,
+			body:   `{"fine_decision": true, "reason": "Неверный номер"}`,
+			buildDirectorService: func() service.DirectorService {
+				mockService := mocks.NewDirectorService(t)
+				mockService.On("OverrideCaseDecision", mock.Anything, userID, caseID, true, "Неверный номер").
+					Return(domain.CaseOverride{}, errs.ErrCaseNotSolved)
+
+				return mockService
+			},
+			expectedCode: http.StatusConflict,
+		},
+		{
+			name:   "Fine decision is the same. 409 Conflict",
+			caseID: caseID,
+			body:   `{"fine_decision": true, "reason": "Неверный номер"}`,
+			buildDirectorService: func() service.DirectorService {
+				mockService := mocks.NewDirectorService(t)
+				mockService.On("OverrideCaseDecision", mock.Anything, userID, caseID, true, "Неверный номер").
+					Return(domain.CaseOverride{}, errs.ErrDecisionNotChanged)
+
+				return mockService
+			},
+			expectedCode: http.StatusConflict,
+		},
+		{
+			name:   "Case not exists. 404 Not found",
+			caseID: caseID,
+			body:   `{"fine_decision": true, "reason": "Неверный номер"}`,
+			buildDirectorService: func() service.DirectorService {
+				mockService := mocks.NewDirectorService(t)
+				mockService.On("OverrideCaseDecision", mock.Anything, userID, caseID, true, "Неверный номер").
+					Return(domain.CaseOverride{}, errs.ErrNoCase)
+
+				return mockService
+			},
+			expectedCode: http.StatusNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			handler := NewDirectorHandler(tc.buildDirectorService(), caseConverter, analyticsConverter)
+
+			req := httptest.NewRequest(http.MethodPost, "/director/cases/"+tc.caseID+"/override",
+				bytes.NewBufferString(tc.body))
+			req.SetPathValue(overrideCaseIDValue, tc.caseID)
+			rec := httptest.NewRecorder()
+
+			ctx := context.WithValue(req.Context(), middlewares.TokenInfoKey, tokenInfo)
+			handler.OverrideCaseDecision(rec, req.WithContext(ctx))
+			assert.Equal(t, tc.expectedCode, rec.Code)
+			if tc.expectedCode == http.StatusOK {
+				var actual dto.CaseOverride
+				assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &actual))
+				assert.Equal(t, string(domain.OverrideDecisionAction), actual.Action)
+				assert.Equal(t, &fineID, actual.FineID)
+				assert.Equal(t, &noFine, actual.NewFineDecision)
+			}
+		})
+	}
+}
+
+func TestCaseCorrections(t *testing.T) {
+	caseConverter := converter.NewCaseConverter()
+	analyticsConverter := converter.NewAnalyticsConverter()
+	caseID := uuid.New().String()
+	userID := uuid.New().String()
+	tokenInfo := tokens.TokenInfo{UserID: userID, UserRole: domain.DirectorRole}
+	voidFine := func(h *DirectorHandler) http.HandlerFunc { return h.VoidCaseFine }
+	reopen := func(h *DirectorHandler) http.HandlerFunc { return h.ReopenCase }
+
+	testCases := []struct {
+		name         string
+		method       string
+		handler      func(h *DirectorHandler) http.HandlerFunc
+		body         string
+		serviceErr   error
+		expectCall   bool
+		expectedCode int
+	}{
+		{
+			name: "Void fine. 200 OK", method: "VoidCaseFine", handler: voidFine,
+			body: `{"reason": "Штраф выставлен по ошибке"}`, expectCall: true, expectedCode: http.StatusOK,
+		},
+		{
+			name: "Void fine without reason. 400 Bad request", method: "VoidCaseFine", handler: voidFine,
+			body: `{}`, expectedCode: http.StatusBadRequest,
+		},
+		{
+			name: "Void fine of case without fine. 404 Not found", method: "VoidCaseFine", handler: voidFine,
+			body: `{"reason": "Штраф выставлен по ошибке"}`, serviceErr: errs.ErrNoFine, expectCall: true,
+			expectedCode: http.StatusNotFound,
+		},
+		{
+			name: "Void cancelled fine. 409 Conflict", method: "VoidCaseFine", handler: voidFine,
+			body: `{"reason": "Штраф выставлен по ошибке"}`, serviceErr: errs.ErrFineCancelled, expectCall: true,
+			expectedCode: http.StatusConflict,
+		},
+		{
+			name: "Void paid fine. 409 Conflict", method: "VoidCaseFine", handler: voidFine,
+			body: `{"reason": "Штраф выставлен по ошибке"}`, serviceErr: errs.ErrFineCannotBeCancelled,
+			expectCall: true, expectedCode: http.StatusConflict,
+		},
+		{
+			name: "Reopen case. 200 OK", method: "ReopenCase", handler: reopen,
+			body: `{"reason": "Нечеткое фото"}`, expectCall: true, expectedCode: http.StatusOK,
+		},
+		{
+			name: "Reopen case without reason. 400 Bad request", method: "ReopenCase", handler: reopen,
+			body: `{"reason": ""}`, expectedCode: http.StatusBadRequest,
+		},
+		{
+			name: "Reopen not solved case. 409 Conflict", method: "ReopenCase", handler: reopen,
+			body: `{"reason": "Нечеткое фото"}`, serviceErr: errs.ErrCaseNotSolved, expectCall: true,
+			expectedCode: http.StatusConflict,
+		},
+		{
+			name: "Reopen case with error. 500 Internal server error", method: "ReopenCase", handler: reopen,
+			body: `{"reason": "Нечеткое фото"}`, serviceErr: fmt.Errorf("internal error"), expectCall: true,
+			expectedCode: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			directorService := mocks.NewDirectorService(t)
+			if tc.expectCall {
+				directorService.On(tc.method, mock.Anything, userID, caseID, mock.AnythingOfType("string")).
+					Return(domain.CaseOverride{CaseID: caseID, UserID: userID}, tc.serviceErr)
+			}
+			handler := NewDirectorHandler(directorService, caseConverter, analyticsConverter)
+
+			req := httptest.NewRequest(http.MethodPost, "/director/cases/"+caseID, bytes.NewBufferString(tc.body))
+			req.SetPathValue(overrideCaseIDValue, caseID)
+			rec := httptest.NewRecorder()
+
+			ctx := context.WithValue(req.Context(), middlewares.TokenInfoKey, tokenInfo)
+			tc.handler(handler)(rec, req.WithContext(ctx))
+			assert.Equal(t, tc.expectedCode, rec.Code)
+		})
+	}
+}
+
+func TestNewNotificationToken(t *testing.T) {
+	userID := uuid.New().String()
+	tokenInfo := tokens.TokenInfo{UserID: userID, UserRole: domain.DirectorRole}
+
+	testCases := []struct {
+		name          string
+		serviceErr    error
+		expectedCode  int
+		expectedToken string
+	}{
+		{
+			name:          "200 OK",
+			expectedCode:  http.StatusOK,
+			expectedToken: "notification_token",
+		},
+		{
+			name:         "Signing key is not set. 404 Not found",
+			serviceErr:   errs.ErrNotificationDisabled,
+			expectedCode: http.StatusNotFound,
+		},
+		{
+			name:         "Service error. 500 Internal server error",
+			serviceErr:   fmt.Errorf("internal error"),
+			expectedCode: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			directorService := mocks.NewDirectorService(t)
+			directorService.On("NewNotificationToken", mock.Anything, userID).
+				Return(tc.expectedToken, tc.serviceErr)
+			handler := NewDirectorHandler(directorService, converter.NewCaseConverter(), converter.NewAnalyticsConverter())
+
+			req := httptest.NewRequest(http.MethodGet, "/director/notification_token", nil)
+			rec := httptest.NewRecorder()
+
+			ctx := context.WithValue(req.Context(), middlewares.TokenInfoKey, tokenInfo)
+			handler.NewNotificationToken(rec, req.WithContext(ctx))
+			assert.Equal(t, tc.expectedCode, rec.Code)
+
+			if tc.expectedCode == http.StatusOK {
+				var actual dto.NotificationToken
+				assert.NoError(t, json.NewDecoder(rec.Body).Decode(&actual))
+				assert.Equal(t, tc.expectedToken, actual.Token)
+			}
+		})
+	}
+}
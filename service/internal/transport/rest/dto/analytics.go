@@ -6,5 +6,6 @@ type AnalyticsInterval struct {
 	CorrectCnt           int    `json:"correct_cnt"`
 	IncorrectCnt         int    `json:"incorrect_cnt"`
 	UnknownCnt           int    `json:"unknown_cnt"`
+	ExpiredCnt           int    `json:"expired_cnt"`
 	MaxConsecutiveSolved int    `json:"max_consecutive_solved"`
 }
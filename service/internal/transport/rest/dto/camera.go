@@ -17,9 +17,33 @@ type CameraIn struct {
 	Latitude     float64 `json:"latitude,omitempty" validate:"required"`
 	Longitude    float64 `json:"longitude,omitempty" validate:"required"`
 	ShortDesc    string  `json:"short_desc,omitempty" validate:"required"`
+	// RedactRegions are blurred on images of camera before they are shown to experts
+	RedactRegions []RedactRegion `json:"redact_regions,omitempty" validate:"omitempty,dive"`
 }
 
 type RegisterCamera struct {
 	CameraIn CameraIn `json:"camera" validate:"required"`
 	SignUp   SignUp   `json:"sign_up" validate:"required"`
 }
+
+// RedactRegion is rectangle of image in pixels, x and y are coordinates of top left corner
+type RedactRegion struct {
+	X      int `json:"x" validate:"min=0"`
+	Y      int `json:"y" validate:"min=0"`
+	Width  int `json:"width" validate:"required,min=1"`
+	Height int `json:"height" validate:"required,min=1"`
+}
+
+type CameraRedactRegions struct {
+	Regions []RedactRegion `json:"regions" validate:"dive"`
+}
+
+type FieldMapping struct {
+	CaseField  string `json:"case_field" validate:"required,oneof=transport.chars transport.num transport.region camera.id violation.id violation_value required_skill date"`
+	PayloadKey string `json:"payload_key" validate:"required"`
+	Format     string `json:"format,omitempty"`
+}
+
+type CameraTypeMapping struct {
+	Fields []FieldMapping `json:"fields" validate:"required,min=1,dive"`
+}
@@ -12,18 +12,17 @@ type Case struct {
 	Date           time.Time `json:"date,omitempty"`
 	IsSolved       bool      `json:"is_solved,omitempty"`
 	FineDecision   bool      `json:"fine_decision,omitempty"`
-}
-
-type CaseWithImage struct {
-	Case           Case   `json:"case"`
-	Image          []byte `json:"image"`
-	ImageExtension string `json:"image_extension"`
+	// LeaseExpiresAt is time, until which expert must solve assigned case
+	LeaseExpiresAt *time.Time `json:"lease_expires_at,omitempty"`
 }
 
 type CaseAssessment struct {
-	ExpertID      string `json:"expert_id"`
-	IsExpertSolve bool   `json:"is_expert_solve"`
-	FineDecision  bool   `json:"fine_decision"`
+	ExpertID       string `json:"expert_id"`
+	IsExpertSolve  bool   `json:"is_expert_solve"`
+	FineDecision   bool   `json:"fine_decision"`
+	IsLeaseExpired bool   `json:"is_lease_expired"`
+	// IsVoided is true for decision, which was made before case was reopened by director
+	IsVoided bool `json:"is_voided"`
 }
 
 type CaseStatus struct {
@@ -34,4 +33,47 @@ type CaseStatus struct {
 	IsSolved        bool             `json:"is_solved"`
 	FineDecision    bool             `json:"fine_decision"`
 	CaseAssessments []CaseAssessment `json:"case_assessments"`
+	// ReviewRequestedAt is set, when case waits for decision of director
+	ReviewRequestedAt *time.Time       `json:"review_requested_at,omitempty"`
+	Escalations       []CaseEscalation `json:"escalations"`
+	// Overrides are corrections of case by director
+	Overrides []CaseOverride `json:"overrides"`
+	// Notifications are deliveries of fine notification, notifications_unavailable is true,
+	// when fine_notification did not return them
+	Notifications            []NotificationDelivery `json:"notifications"`
+	NotificationsUnavailable bool                   `json:"notifications_unavailable"`
+}
+
+// CaseEscalation to_skill is empty, when case is sent to review of director
+type CaseEscalation struct {
+	FromSkill   int       `json:"from_skill"`
+	ToSkill     *int      `json:"to_skill,omitempty"`
+	Reason      string    `json:"reason"`
+	EscalatedAt time.Time `json:"escalated_at"`
+}
+
+// CaseOverride new_fine_decision is empty, when fine decision is not changed or case is reopened,
+// fine_id is fine, which was cancelled or issued
+type CaseOverride struct {
+	ID              string    `json:"id"`
+	UserID          string    `json:"user_id"`
+	Action          string    `json:"action"`
+	OldFineDecision bool      `json:"old_fine_decision"`
+	NewFineDecision *bool     `json:"new_fine_decision,omitempty"`
+	FineID          *string   `json:"fine_id,omitempty"`
+	Reason          string    `json:"reason"`
+	CreatedAt       time.Time `json:"created_at"`
+}
+
+// NotificationDelivery kind is fine for notification about fine and cancellation for notification
+// about cancelled fine
+type NotificationDelivery struct {
+	Kind        string     `json:"kind"`
+	Channel     string     `json:"channel"`
+	Status      string     `json:"status"`
+	Attempts    int        `json:"attempts"`
+	LastError   *string    `json:"last_error,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+	DeliveredAt *time.Time `json:"delivered_at,omitempty"`
 }
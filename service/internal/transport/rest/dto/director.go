@@ -1,6 +1,39 @@
 package dto
 
+import "time"
+
 type UpdateExpertSkill struct {
 	ExpertID string `json:"expert_id"`
 	Skill    int    `json:"skill"`
 }
+
+// ReviewCase is case, which experts of maximum skill could not solve, with decisions of experts
+type ReviewCase struct {
+	CaseID            string    `json:"case_id"`
+	ViolationValue    string    `json:"violation_value"`
+	RequiredSkill     int       `json:"required_skill"`
+	CaseDate          time.Time `json:"case_date"`
+	ReviewRequestedAt time.Time `json:"review_requested_at"`
+	PositiveDecisions int       `json:"positive_decisions"`
+	NegativeDecisions int       `json:"negative_decisions"`
+}
+
+type ResolveReviewCase struct {
+	FineDecision *bool `json:"fine_decision"`
+}
+
+// OverrideCaseDecision reason is required explanation of director, which is saved in audit log
+type OverrideCaseDecision struct {
+	FineDecision *bool  `json:"fine_decision"`
+	Reason       string `json:"reason"`
+}
+
+// CaseCorrection is request to void fine or to reopen case with required explanation of director
+type CaseCorrection struct {
+	Reason string `json:"reason"`
+}
+
+// NotificationToken is token of director for HTTP server of fine_notification
+type NotificationToken struct {
+	Token string `json:"token"`
+}
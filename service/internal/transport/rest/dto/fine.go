@@ -0,0 +1,27 @@
+package dto
+
+import "time"
+
+type Fine struct {
+	ID             string     `json:"id"`
+	CaseID         string     `json:"case_id"`
+	Reference      string     `json:"reference"`
+	Amount         int        `json:"amount"`
+	DiscountAmount int        `json:"discount_amount"`
+	DiscountUntil  *time.Time `json:"discount_until"`
+	IssuedAt       time.Time  `json:"issued_at"`
+	DueAt          time.Time  `json:"due_at"`
+	Status         string     `json:"status"`
+	PaymentID      *string    `json:"payment_id"`
+	PaidAmount     *int       `json:"paid_amount"`
+	PaidAt         *time.Time `json:"paid_at"`
+	CancelledAt    *time.Time `json:"cancelled_at"`
+}
+
+// FinePayment is body of payment provider webhook
+type FinePayment struct {
+	PaymentID string    `json:"payment_id" validate:"required"`
+	Reference string    `json:"reference" validate:"required"`
+	Amount    int       `json:"amount" validate:"gt=0"`
+	PaidAt    time.Time `json:"paid_at" validate:"required"`
+}
@@ -0,0 +1,18 @@
+package dto
+
+import (
+	"encoding/json"
+	"time"
+)
+
+type OutboxMessage struct {
+	ID            string          `json:"id"`
+	Kind          string          `json:"kind"`
+	Payload       json.RawMessage `json:"payload" swaggertype:"object"`
+	Status        string          `json:"status"`
+	CreatedAt     time.Time       `json:"created_at"`
+	Attempts      int             `json:"attempts"`
+	NextAttemptAt time.Time       `json:"next_attempt_at"`
+	LastError     *string         `json:"last_error"`
+	FailedAt      *time.Time      `json:"failed_at"`
+}
@@ -6,4 +6,6 @@ type Person struct {
 	Email    string `json:"email,omitempty"`
 	VkID     string `json:"vk_id,omitempty"`
 	TgID     string `json:"tg_id,omitempty"`
+	// NotifyChannels are channels of fine notification in order of preference: email, telegram, vk, sms
+	NotifyChannels []string `json:"notify_channels,omitempty"`
 }
@@ -2,24 +2,22 @@ package rest
 
 import (
 	"TrafficPolice/internal/converter"
+	"TrafficPolice/internal/domain"
 	"TrafficPolice/internal/errs"
 	"TrafficPolice/internal/service"
 	"TrafficPolice/internal/tokens"
-	"TrafficPolice/internal/transport/rabbitmq"
 	"TrafficPolice/internal/transport/rest/dto"
 	"TrafficPolice/internal/transport/rest/middlewares"
 	"TrafficPolice/internal/transport/rest/response"
-	"TrafficPolice/pkg/imagereader"
 	"encoding/json"
 	"errors"
-	"fmt"
+	"github.com/google/uuid"
 	"io"
 	"log"
 	"net/http"
 )
 
 const (
-	expertsDir            = "experts"
 	expertContentImageKey = "image"
 	expertIDPathValue     = "id"
 )
@@ -27,9 +25,6 @@ const (
 type ExpertHandler struct {
 	imgService            service.ImgService
 	expertService         service.ExpertService
-	ratingService         service.RatingService
-	finePublisher         rabbitmq.FinePublisher
-	imageReader           imagereader.ImageReader
 	caseConverter         *converter.CaseConverter
 	caseDecisionConverter *converter.CaseDecisionConverter
 }
@@ -37,18 +32,12 @@ type ExpertHandler struct {
 func NewExpertHandler(
 	imgService service.ImgService,
 	expertService service.ExpertService,
-	ratingService service.RatingService,
-	finePublisher rabbitmq.FinePublisher,
-	imageReader imagereader.ImageReader,
 	caseConverter *converter.CaseConverter,
 	caseDecisionConverter *converter.CaseDecisionConverter,
 ) *ExpertHandler {
 	return &ExpertHandler{
 		imgService:            imgService,
 		expertService:         expertService,
-		ratingService:         ratingService,
-		finePublisher:         finePublisher,
-		imageReader:           imageReader,
 		caseConverter:         caseConverter,
 		caseDecisionConverter: caseDecisionConverter,
 	}
@@ -71,7 +60,7 @@ func NewExpertHandler(
 // @Router /expert/{id}/img [post]
 func (h *ExpertHandler) UploadExpertImg(w http.ResponseWriter, r *http.Request) {
 	expertID := r.PathValue(expertIDPathValue)
-	if expertID == "" {
+	if err := uuid.Validate(expertID); err != nil {
 		response.BadRequest(w, "Bad expert id")
 		return
 	}
@@ -83,13 +72,12 @@ func (h *ExpertHandler) UploadExpertImg(w http.ResponseWriter, r *http.Request)
 	}
 
 	contentType := header.Header.Get(contentTypeKey)
-	extension, err := getImgExtension(contentType)
+	_, err = getImgExtension(contentType)
 	if err != nil {
 		response.BadRequest(w, err.Error())
 		return
 	}
 
-	imgFilePath := fmt.Sprintf("%s/%s.%s", expertsDir, expertID, extension)
 	fileBytes, err := io.ReadAll(file)
 	if err != nil {
 		log.Printf("Error while reading fileBytes: %v\n", fileBytes)
@@ -97,7 +85,7 @@ func (h *ExpertHandler) UploadExpertImg(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	err = h.imgService.SaveImg(fileBytes, imgFilePath)
+	err = h.imgService.SaveImg(r.Context(), domain.ImageOwner{Type: domain.ExpertImageOwner, ID: expertID}, domain.OriginalImage, fileBytes)
 	if err != nil {
 		log.Println(err)
 		response.InternalServerError(w)
@@ -123,12 +111,12 @@ func (h *ExpertHandler) UploadExpertImg(w http.ResponseWriter, r *http.Request)
 // @Router /expert/{id}/img [get]
 func (h *ExpertHandler) GetExpertImg(w http.ResponseWriter, r *http.Request) {
 	expertID := r.PathValue(expertIDPathValue)
-	if expertID == "" {
+	if err := uuid.Validate(expertID); err != nil {
 		response.BadRequest(w, "Bad expert id")
 		return
 	}
 
-	file, err := h.imgService.GetImgFilePath(expertsDir, expertID)
+	img, err := h.imgService.GetImg(r.Context(), domain.ImageOwner{Type: domain.ExpertImageOwner, ID: expertID}, domain.OriginalImage)
 	if err != nil {
 		if errors.Is(err, errs.ErrNoImage) {
 			response.NotFound(w, "Image with input expert id not found")
@@ -139,14 +127,15 @@ func (h *ExpertHandler) GetExpertImg(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	http.ServeFile(w, r, file)
+	response.Image(w, img.ContentType, img.Data)
 }
 
 // GetCaseForExpert docs
 // @Summary Получение случая для оценки экспертом
 // @Security ApiKeyAuth
 // @Tags expert
-// @Description Получение случая для оценки экспертом. Воспользоваться могут эксперт или директор
+// @Description Получение случая для оценки экспертом. Воспользоваться могут эксперт или директор.
+// @Description Случай закрепляется за экспертом до lease_expires_at, после этого передается другим экспертам
 // @ID expert-get-case
 // @Produce  json
 // @Success 200 {object} dto.Case
@@ -162,10 +151,11 @@ func (h *ExpertHandler) GetCaseForExpert(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	c, err := h.expertService.GetCase(tokenInfo.UserID)
+	c, err := h.expertService.GetCase(r.Context(), tokenInfo.UserID)
 	if err != nil {
 		if errors.Is(err, errs.ErrUserNotExists) {
 			response.NotFound(w, "Expert not found")
+			return
 		}
 		if errors.Is(err, errs.ErrNoNotSolvedCase) || errors.Is(err, errs.ErrNoCase) {
 			response.NoContent(w)
@@ -176,7 +166,7 @@ func (h *ExpertHandler) GetCaseForExpert(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	cBytes, err := json.Marshal(h.caseConverter.MapDomainToDto(c))
+	cBytes, err := json.Marshal(h.caseConverter.MapAssignedCaseToDto(c))
 	if err != nil {
 		log.Println(err)
 		response.InternalServerError(w)
@@ -197,6 +187,7 @@ func (h *ExpertHandler) GetCaseForExpert(w http.ResponseWriter, r *http.Request)
 // @Param input body dto.Decision true "id случая и решение эксперта"
 // @Success 200 {object} response.Body
 // @Failure 400,401,404 {object} response.Body
+// @Failure 409 {object} response.Body "Случай не закреплен за экспертом или время на оценку истекло"
 // @Failure 500 {object} response.Body
 // @Failure default {object} response.Body
 // @Router /expert/decision [post]
@@ -210,7 +201,7 @@ func (h *ExpertHandler) SetCaseDecision(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	expert, err := h.expertService.GetExpertByUserID(tokenInfo.UserID)
+	expert, err := h.expertService.GetExpertByUserID(r.Context(), tokenInfo.UserID)
 	if err != nil {
 		if errors.Is(err, errs.ErrUserNotExists) {
 			response.NotFound(w, "Input expert not found")
@@ -221,66 +212,19 @@ func (h *ExpertHandler) SetCaseDecision(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	caseDecision, err := h.expertService.SetCaseDecision(
+	_, err = h.expertService.SetCaseDecision(r.Context(),
 		h.caseDecisionConverter.MapDtoToDomain(decision, expert),
 	)
 
 	if err != nil {
+		if errors.Is(err, errs.ErrCaseNotAssigned) {
+			response.Conflict(w, "Case is not assigned to expert or lease expired")
+			return
+		}
 		log.Println(err)
 		response.InternalServerError(w)
 		return
 	}
 
-	if caseDecision.IsSolved {
-		err = h.ratingService.SetRating(caseDecision)
-		if err != nil {
-			log.Println(err)
-			response.InternalServerError(w)
-		}
-	}
-
-	if caseDecision.ShouldSendFine {
-		h.sendNotification(decision.CaseID)
-	}
-
 	response.OKMessage(w, "Decision accepted")
 }
-
-func (h *ExpertHandler) getCaseImage(caseID string) ([]byte, string, error) {
-	filePath, err := h.imgService.GetImgFilePath(casesDir, caseID)
-	if err != nil {
-		return nil, "", err
-	}
-
-	file, err := h.imageReader.Read(filePath)
-	if err != nil {
-		return nil, "", err
-	}
-
-	extension := h.imageReader.GetExtension(filePath)
-
-	return file, extension, nil
-}
-
-func (h *ExpertHandler) sendNotification(caseID string) {
-	caseInfo, err := h.expertService.GetCaseWithPersonInfo(caseID)
-	if err != nil {
-		log.Println(err)
-		return
-	}
-	c := h.caseConverter.MapCaseWithPersonToDTO(caseInfo)
-	image, extension, err := h.getCaseImage(caseInfo.ID)
-	if err != nil {
-		log.Println(err)
-		return
-	}
-	err = h.finePublisher.PublishFineNotification(dto.CaseWithImage{
-		Case:           c,
-		Image:          image,
-		ImageExtension: extension,
-	})
-	if err != nil {
-		log.Println(err)
-		return
-	}
-}
@@ -7,11 +7,8 @@ import (
 	"TrafficPolice/internal/service"
 	"TrafficPolice/internal/service/mocks"
 	"TrafficPolice/internal/tokens"
-	"TrafficPolice/internal/transport/rabbitmq"
-	mocksmq "TrafficPolice/internal/transport/rabbitmq/mocks"
+	"TrafficPolice/internal/transport/rest/dto"
 	"TrafficPolice/internal/transport/rest/middlewares"
-	"TrafficPolice/pkg/imagereader"
-	mocksreader "TrafficPolice/pkg/imagereader/mocks"
 	"bytes"
 	"context"
 	"encoding/json"
@@ -21,6 +18,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 )
 
 func TestGetCaseForExpert(t *testing.T) {
@@ -34,19 +32,19 @@ func TestGetCaseForExpert(t *testing.T) {
 		UserRole: domain.ExpertRole,
 	}
 
-	caseForExpert := domain.Case{
-		Transport: domain.Transport{Person: &domain.Person{}},
-		Camera:    domain.Camera{CameraType: domain.CameraType{}},
-		Violation: domain.Violation{},
+	caseForExpert := domain.AssignedCase{
+		Case: domain.Case{
+			Transport: domain.Transport{Person: &domain.Person{}},
+			Camera:    domain.Camera{CameraType: domain.CameraType{}},
+			Violation: domain.Violation{},
+		},
+		LeaseExpiresAt: time.Now().Add(time.Hour),
 	}
 
 	testCases := []struct {
 		name               string
 		buildImgService    func() service.ImgService
 		buildExpertService func() service.ExpertService
-		buildRatingService func() service.RatingService
-		buildFinePublisher func() rabbitmq.FinePublisher
-		buildImageReader   func() imagereader.ImageReader
 		expectedCode       int
 	}{
 		{
@@ -57,23 +55,11 @@ func TestGetCaseForExpert(t *testing.T) {
 			},
 			buildExpertService: func() service.ExpertService {
 				mockService := mocks.NewExpertService(t)
-				mockService.On("GetCase", tokenInfo.UserID).
+				mockService.On("GetCase", mock.Anything, tokenInfo.UserID).
 					Return(caseForExpert, nil)
 
 				return mockService
 			},
-			buildRatingService: func() service.RatingService {
-				mockService := mocks.NewRatingService(t)
-				return mockService
-			},
-			buildFinePublisher: func() rabbitmq.FinePublisher {
-				mockPublisher := mocksmq.NewFinePublisher(t)
-				return mockPublisher
-			},
-			buildImageReader: func() imagereader.ImageReader {
-				mockReader := mocksreader.NewImageReader(t)
-				return mockReader
-			},
 			expectedCode: http.StatusOK,
 		},
 		{
@@ -84,23 +70,11 @@ func TestGetCaseForExpert(t *testing.T) {
 			},
 			buildExpertService: func() service.ExpertService {
 				mockService := mocks.NewExpertService(t)
-				mockService.On("GetCase", tokenInfo.UserID).
+				mockService.On("GetCase", mock.Anything, tokenInfo.UserID).
 					Return(caseForExpert, errs.ErrUserNotExists)
 
 				return mockService
 			},
-			buildRatingService: func() service.RatingService {
-				mockService := mocks.NewRatingService(t)
-				return mockService
-			},
-			buildFinePublisher: func() rabbitmq.FinePublisher {
-				mockPublisher := mocksmq.NewFinePublisher(t)
-				return mockPublisher
-			},
-			buildImageReader: func() imagereader.ImageReader {
-				mockReader := mocksreader.NewImageReader(t)
-				return mockReader
-			},
 			expectedCode: http.StatusNotFound,
 		},
 		{
@@ -111,23 +85,11 @@ func TestGetCaseForExpert(t *testing.T) {
 			},
 			buildExpertService: func() service.ExpertService {
 				mockService := mocks.NewExpertService(t)
-				mockService.On("GetCase", tokenInfo.UserID).
+				mockService.On("GetCase", mock.Anything, tokenInfo.UserID).
 					Return(caseForExpert, errs.ErrNoCase)
 
 				return mockService
 			},
-			buildRatingService: func() service.RatingService {
-				mockService := mocks.NewRatingService(t)
-				return mockService
-			},
-			buildFinePublisher: func() rabbitmq.FinePublisher {
-				mockPublisher := mocksmq.NewFinePublisher(t)
-				return mockPublisher
-			},
-			buildImageReader: func() imagereader.ImageReader {
-				mockReader := mocksreader.NewImageReader(t)
-				return mockReader
-			},
 			expectedCode: http.StatusNoContent,
 		},
 		{
@@ -138,23 +100,11 @@ func TestGetCaseForExpert(t *testing.T) {
 			},
 			buildExpertService: func() service.ExpertService {
 				mockService := mocks.NewExpertService(t)
-				mockService.On("GetCase", tokenInfo.UserID).
+				mockService.On("GetCase", mock.Anything, tokenInfo.UserID).
 					Return(caseForExpert, errs.ErrNoCase)
 
 				return mockService
 			},
-			buildRatingService: func() service.RatingService {
-				mockService := mocks.NewRatingService(t)
-				return mockService
-			},
-			buildFinePublisher: func() rabbitmq.FinePublisher {
-				mockPublisher := mocksmq.NewFinePublisher(t)
-				return mockPublisher
-			},
-			buildImageReader: func() imagereader.ImageReader {
-				mockReader := mocksreader.NewImageReader(t)
-				return mockReader
-			},
 			expectedCode: http.StatusNoContent,
 		},
 	}
@@ -162,8 +112,7 @@ func TestGetCaseForExpert(t *testing.T) {
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
 			handler := NewExpertHandler(
-				tc.buildImgService(), tc.buildExpertService(), tc.buildRatingService(),
-				tc.buildFinePublisher(), tc.buildImageReader(), caseConverter, caseDecisionConverter,
+				tc.buildImgService(), tc.buildExpertService(), caseConverter, caseDecisionConverter,
 			)
 
 			var buf bytes.Buffer
@@ -174,6 +123,11 @@ func TestGetCaseForExpert(t *testing.T) {
 			ctx := context.WithValue(req.Context(), middlewares.TokenInfoKey, tokenInfo)
 			handler.GetCaseForExpert(rec, req.WithContext(ctx))
 			assert.Equal(t, tc.expectedCode, rec.Code)
+			if tc.expectedCode == http.StatusOK {
+				var actual dto.Case
+				assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &actual))
+				assert.WithinDuration(t, caseForExpert.LeaseExpiresAt, *actual.LeaseExpiresAt, time.Second)
+			}
 		})
 	}
 }
@@ -205,164 +159,83 @@ func TestSetCaseDecision(t *testing.T) {
 		},
 	}
 
-	caseInfo := domain.Case{
-		ID:        caseID,
-		Transport: domain.Transport{Person: &domain.Person{}},
-		Camera:    domain.Camera{CameraType: domain.CameraType{}},
-		Violation: domain.Violation{},
-	}
-
-	filePath := "filepath"
-
+	notificationID := uuid.New().String()
 	testCases := []struct {
 		name               string
-		buildImgService    func() service.ImgService
 		buildExpertService func() service.ExpertService
-		buildRatingService func() service.RatingService
-		buildFinePublisher func() rabbitmq.FinePublisher
-		buildImageReader   func() imagereader.ImageReader
 		decision           domain.Decision
 		expectedCode       int
 	}{
 		{
-			name: "Set decision. Case is solved. Should send fine. 200 OK",
-			buildImgService: func() service.ImgService {
-				mockService := mocks.NewImgService(t)
-				mockService.On("GetImgFilePath", mock.Anything, mock.Anything).
-					Return(filePath, nil)
-
-				return mockService
-			},
+			name: "Set decision. Case is solved. Fine notification is recorded. 200 OK",
 			buildExpertService: func() service.ExpertService {
 				mockService := mocks.NewExpertService(t)
-				mockService.On("GetExpertByUserID", tokenInfo.UserID).
+				mockService.On("GetExpertByUserID", mock.Anything, tokenInfo.UserID).
 					Return(expert, nil)
-				mockService.On("SetCaseDecision", mock.Anything).
-					Return(domain.CaseDecisionInfo{CaseID: caseID, ShouldSendFine: true, IsSolved: true}, nil)
-				mockService.On("GetCaseWithPersonInfo", mock.Anything).
-					Return(caseInfo, nil)
-
-				return mockService
-			},
-			buildRatingService: func() service.RatingService {
-				mockService := mocks.NewRatingService(t)
-				mockService.On("SetRating", mock.Anything).
-					Return(nil)
+				mockService.On("SetCaseDecision", mock.Anything, mock.Anything).
+					Return(domain.CaseDecisionInfo{
+						CaseID: caseID, ShouldSendFine: true, IsSolved: true, NotificationID: notificationID,
+					}, nil)
 
 				return mockService
 			},
-			buildFinePublisher: func() rabbitmq.FinePublisher {
-				mockPublisher := mocksmq.NewFinePublisher(t)
-				mockPublisher.On("PublishFineNotification", mock.Anything).
-					Return(nil)
-
-				return mockPublisher
-			},
-			buildImageReader: func() imagereader.ImageReader {
-				mockReader := mocksreader.NewImageReader(t)
-				mockReader.On("Read", filePath).
-					Return([]byte{}, nil)
-				mockReader.On("GetExtension", filePath).
-					Return("jpg")
-
-				return mockReader
-			},
 			expectedCode: http.StatusOK,
 		},
 		{
 			name: "Set case decision. Case is solved. Should not send fine. 200 OK",
-			buildImgService: func() service.ImgService {
-				mockService := mocks.NewImgService(t)
-				return mockService
-			},
 			buildExpertService: func() service.ExpertService {
 				mockService := mocks.NewExpertService(t)
-				mockService.On("GetExpertByUserID", tokenInfo.UserID).
+				mockService.On("GetExpertByUserID", mock.Anything, tokenInfo.UserID).
 					Return(expert, nil)
-				mockService.On("SetCaseDecision", mock.Anything).
+				mockService.On("SetCaseDecision", mock.Anything, mock.Anything).
 					Return(domain.CaseDecisionInfo{CaseID: caseID, ShouldSendFine: false, IsSolved: true}, nil)
 
 				return mockService
 			},
-			buildRatingService: func() service.RatingService {
-				mockService := mocks.NewRatingService(t)
-				mockService.On("SetRating", mock.Anything).
-					Return(nil)
-
-				return mockService
-			},
-			buildFinePublisher: func() rabbitmq.FinePublisher {
-				mockPublisher := mocksmq.NewFinePublisher(t)
-				return mockPublisher
-			},
-			buildImageReader: func() imagereader.ImageReader {
-				mockReader := mocksreader.NewImageReader(t)
-				return mockReader
-			},
 			expectedCode: http.StatusOK,
 		},
 		{
 			name: "Set case decision. Case is not solved. Should not send fine. 200 OK",
-			buildImgService: func() service.ImgService {
-				mockService := mocks.NewImgService(t)
-				return mockService
-			},
 			buildExpertService: func() service.ExpertService {
 				mockService := mocks.NewExpertService(t)
-				mockService.On("GetExpertByUserID", tokenInfo.UserID).
+				mockService.On("GetExpertByUserID", mock.Anything, tokenInfo.UserID).
 					Return(expert, nil)
-				mockService.On("SetCaseDecision", mock.Anything).
+				mockService.On("SetCaseDecision", mock.Anything, mock.Anything).
 					Return(domain.CaseDecisionInfo{CaseID: caseID, ShouldSendFine: false, IsSolved: false}, nil)
 
 				return mockService
 			},
-			buildRatingService: func() service.RatingService {
-				mockService := mocks.NewRatingService(t)
-				return mockService
-			},
-			buildFinePublisher: func() rabbitmq.FinePublisher {
-				mockPublisher := mocksmq.NewFinePublisher(t)
-				return mockPublisher
-			},
-			buildImageReader: func() imagereader.ImageReader {
-				mockReader := mocksreader.NewImageReader(t)
-				return mockReader
-			},
 			expectedCode: http.StatusOK,
 		},
 		{
 			name: "Set case decision. Expert by input user id not found. 404 Not found",
-			buildImgService: func() service.ImgService {
-				mockService := mocks.NewImgService(t)
-				return mockService
-			},
 			buildExpertService: func() service.ExpertService {
 				mockService := mocks.NewExpertService(t)
-				mockService.On("GetExpertByUserID", tokenInfo.UserID).
+				mockService.On("GetExpertByUserID", mock.Anything, tokenInfo.UserID).
 					Return(domain.Expert{}, errs.ErrUserNotExists)
 				return mockService
 			},
-			buildRatingService: func() service.RatingService {
-				mockService := mocks.NewRatingService(t)
+			expectedCode: http.StatusNotFound,
+		},
+		{
+			name: "Set case decision. Lease of expert expired. 409 Conflict",
+			buildExpertService: func() service.ExpertService {
+				mockService := mocks.NewExpertService(t)
+				mockService.On("GetExpertByUserID", mock.Anything, tokenInfo.UserID).
+					Return(expert, nil)
+				mockService.On("SetCaseDecision", mock.Anything, mock.Anything).
+					Return(domain.CaseDecisionInfo{}, errs.ErrCaseNotAssigned)
+
 				return mockService
 			},
-			buildFinePublisher: func() rabbitmq.FinePublisher {
-				mockPublisher := mocksmq.NewFinePublisher(t)
-				return mockPublisher
-			},
-			buildImageReader: func() imagereader.ImageReader {
-				mockReader := mocksreader.NewImageReader(t)
-				return mockReader
-			},
-			expectedCode: http.StatusNotFound,
+			expectedCode: http.StatusConflict,
 		},
 	}
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
 			handler := NewExpertHandler(
-				tc.buildImgService(), tc.buildExpertService(), tc.buildRatingService(),
-				tc.buildFinePublisher(), tc.buildImageReader(), caseConverter, caseDecisionConverter,
+				mocks.NewImgService(t), tc.buildExpertService(), caseConverter, caseDecisionConverter,
 			)
 
 			var buf bytes.Buffer
@@ -0,0 +1,286 @@
+package rest
+
+import (
+	"TrafficPolice/internal/converter"
+	"TrafficPolice/internal/domain"
+	"TrafficPolice/internal/errs"
+	"TrafficPolice/internal/service"
+	"TrafficPolice/internal/tokens"
+	"TrafficPolice/internal/transport/rest/dto"
+	"TrafficPolice/internal/transport/rest/middlewares"
+	"TrafficPolice/internal/transport/rest/response"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+const (
+	fineIDPathValue      = "id"
+	fineStatusKey        = "status"
+	fineOffsetKey        = "offset"
+	fineUnpaidStatus     = "unpaid"
+	defaultFinesLimit    = 100
+	signatureHeader      = "X-Signature"
+	maxPaymentBodyLength = 1 << 16
+)
+
+type FineHandler struct {
+	fineService   service.FineService
+	validate      *validator.Validate
+	fineConverter *converter.FineConverter
+	webhookSecret string
+}
+
+// NewFineHandler creates handler, which accepts payments signed by webhookSecret.
+// Payments are rejected, when webhookSecret is empty
+func NewFineHandler(
+	fineService service.FineService,
+	validate *validator.Validate,
+	fineConverter *converter.FineConverter,
+	webhookSecret string,
+) *FineHandler {
+	return &FineHandler{
+		fineService:   fineService,
+		validate:      validate,
+		fineConverter: fineConverter,
+		webhookSecret: webhookSecret,
+	}
+}
+
+// PaymentWebhook docs
+// @Summary Уведомление об оплате штрафа
+// @Tags fines
+// @Description Вызывается платежным провайдером после оплаты штрафа. Тело запроса подписывается HMAC-SHA256
+// @Description с секретом fines.webhookSecret, подпись передается в заголовке X-Signature в hex. Повторное
+// @Description уведомление о том же платеже возвращает оплаченный штраф. До окончания скидки штраф оплачивается
+// @Description суммой со скидкой, затем полной суммой
+// @ID fines-payment
+// @Accept  json
+// @Produce  json
+// @Param X-Signature header string true "HMAC-SHA256 тела запроса в hex"
+// @Param input body dto.FinePayment true "Платеж"
+// @Success 200 {object} dto.Fine
+// @Failure 400,401,404,409,422 {object} response.Body
+// @Failure 500 {object} response.Body
+// @Failure default {object} response.Body
+// @Router /fines/payments [post]
+func (h *FineHandler) PaymentWebhook(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxPaymentBodyLength))
+	if err != nil {
+		response.BadRequest(w, err.Error())
+		return
+	}
+	if !h.validSignature(body, r.Header.Get(signatureHeader)) {
+		response.WriteMessage(w, http.StatusUnauthorized, "Invalid signature")
+		return
+	}
+
+	var input dto.FinePayment
+	err = json.Unmarshal(body, &input)
+	if err != nil {
+		response.BadRequest(w, err.Error())
+		return
+	}
+	err = h.validate.Struct(input)
+	if err != nil {
+		response.BadRequest(w, err.Error())
+		return
+	}
+
+	fine, err := h.fineService.PayFine(r.Context(), h.fineConverter.MapPaymentDtoToDomain(input))
+	if err != nil {
+		switch {
+		case errors.Is(err, errs.ErrNoFine):
+			response.NotFound(w, "Fine with input reference not found")
+		case errors.Is(err, errs.ErrFineCancelled):
+			response.Conflict(w, "Fine is cancelled")
+		case errors.Is(err, errs.ErrFineAlreadyPaid):
+			response.Conflict(w, "Fine is already paid by another payment")
+		case errors.Is(err, errs.ErrInsufficientPayment):
+			response.UnprocessableEntity(w, "Payment amount is less than amount due")
+		default:
+			log.Println(err)
+			response.InternalServerError(w)
+		}
+		return
+	}
+
+	h.writeFine(w, fine)
+}
+
+// validSignature compares signature with HMAC-SHA256 of body in constant time
+func (h *FineHandler) validSignature(body []byte, signature string) bool {
+	if h.webhookSecret == "" {
+		return false
+	}
+	actual, err := hex.DecodeString(signature)
+	if err != nil {
+		return false
+	}
+
+	mac := hmac.New(sha256.New, []byte(h.webhookSecret))
+	mac.Write(body)
+	return hmac.Equal(actual, mac.Sum(nil))
+}
+
+// GetFines docs
+// @Summary Получение штрафов
+// @Security ApiKeyAuth
+// @Tags fines
+// @Description Получение штрафов по статусам, отсортированных по сроку оплаты. По умолчанию возвращаются
+// @Description неоплаченные штрафы (issued и overdue). Воспользоваться может только директор
+// @ID fines-get
+// @Produce  json
+// @Param status query string false "Статусы через запятую: issued, paid, overdue, cancelled или unpaid (issued и overdue)"
+// @Param limit query int false "Максимальное количество штрафов. По умолчанию 100"
+// @Param offset query int false "Количество пропущенных штрафов. По умолчанию 0"
+// @Success 200 {object} []dto.Fine
+// @Success 204 ""
+// @Failure 400,401 {object} response.Body
+// @Failure 500 {object} response.Body
+// @Failure default {object} response.Body
+// @Router /fines [get]
+func (h *FineHandler) GetFines(w http.ResponseWriter, r *http.Request) {
+	statuses, err := parseFineStatuses(r.URL.Query().Get(fineStatusKey))
+	if err != nil {
+		response.BadRequest(w, err.Error())
+		return
+	}
+
+	limit := defaultFinesLimit
+	if limitQuery := r.URL.Query().Get(limitKey); limitQuery != "" {
+		limit, err = strconv.Atoi(limitQuery)
+		if err != nil || limit <= 0 {
+			response.BadRequest(w, "limit must be positive number")
+			return
+		}
+	}
+	offset := 0
+	if offsetQuery := r.URL.Query().Get(fineOffsetKey); offsetQuery != "" {
+		offset, err = strconv.Atoi(offsetQuery)
+		if err != nil || offset < 0 {
+			response.BadRequest(w, "offset must be not negative number")
+			return
+		}
+	}
+
+	fines, err := h.fineService.GetFines(r.Context(), statuses, limit, offset)
+	if err != nil {
+		if errors.Is(err, errs.ErrNoRows) {
+			response.NoContent(w)
+			return
+		}
+		log.Println(err)
+		response.InternalServerError(w)
+		return
+	}
+
+	finesBytes, err := json.Marshal(h.fineConverter.MapSliceDomainToDto(fines))
+	if err != nil {
+		log.Println(err)
+		response.InternalServerError(w)
+		return
+	}
+
+	response.WriteResponse(w, http.StatusOK, finesBytes)
+}
+
+// parseFineStatuses parses comma separated statuses, empty query and unpaid mean issued and overdue fines
+func parseFineStatuses(query string) ([]domain.FineStatus, error) {
+	if query == "" {
+		query = fineUnpaidStatus
+	}
+
+	var statuses []domain.FineStatus
+	for _, status := range strings.Split(query, ",") {
+		switch domain.FineStatus(status) {
+		case domain.FineIssued, domain.FinePaid, domain.FineOverdue, domain.FineCancelled:
+			statuses = append(statuses, domain.FineStatus(status))
+		case fineUnpaidStatus:
+			statuses = append(statuses, domain.FineIssued, domain.FineOverdue)
+		default:
+			return nil, errors.New("unknown fine status: " + status)
+		}
+	}
+
+	return statuses, nil
+}
+
+// CancelFine docs
+// @Summary Отмена штрафа
+// @Security ApiKeyAuth
+// @Tags fines
+// @Description Отменяет неоплаченный штраф, оплата отмененного штрафа отклоняется. Оплаченный штраф отменить нельзя.
+// @Description Причина обязательна, отмена записывается в журнал корректировок дела, нарушитель получает уведомление.
+// @Description Воспользоваться может только директор
+// @ID fines-cancel
+// @Accept  json
+// @Produce  json
+// @Param id path string true "id штрафа"
+// @Param input body dto.CaseCorrection true "причина отмены"
+// @Success 200 {object} dto.Fine
+// @Failure 400,401,404,409 {object} response.Body
+// @Failure 500 {object} response.Body
+// @Failure default {object} response.Body
+// @Router /fines/{id}/cancel [post]
+func (h *FineHandler) CancelFine(w http.ResponseWriter, r *http.Request) {
+	fineID := r.PathValue(fineIDPathValue)
+	if err := uuid.Validate(fineID); err != nil {
+		response.BadRequest(w, "Bad fine id")
+		return
+	}
+
+	tokenInfo, ok := r.Context().Value(middlewares.TokenInfoKey).(tokens.TokenInfo)
+	if !ok {
+		response.InternalServerError(w)
+		return
+	}
+
+	var input dto.CaseCorrection
+	err := json.NewDecoder(r.Body).Decode(&input)
+	if err != nil {
+		response.BadRequest(w, err.Error())
+		return
+	}
+	reason, err := parseOverrideReason(input.Reason)
+	if err != nil {
+		response.BadRequest(w, err.Error())
+		return
+	}
+
+	fine, err := h.fineService.CancelFine(r.Context(), tokenInfo.UserID, fineID, reason)
+	if err != nil {
+		switch {
+		case errors.Is(err, errs.ErrNoFine):
+			response.NotFound(w, "Fine with input id not found")
+		case errors.Is(err, errs.ErrFineCannotBeCancelled):
+			response.Conflict(w, "Paid fine can not be cancelled")
+		default:
+			log.Println(err)
+			response.InternalServerError(w)
+		}
+		return
+	}
+
+	h.writeFine(w, fine)
+}
+
+func (h *FineHandler) writeFine(w http.ResponseWriter, fine domain.Fine) {
+	fineBytes, err := json.Marshal(h.fineConverter.MapDomainToDto(fine))
+	if err != nil {
+		log.Println(err)
+		response.InternalServerError(w)
+		return
+	}
+
+	response.WriteResponse(w, http.StatusOK, fineBytes)
+}
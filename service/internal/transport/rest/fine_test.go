@@ -0,0 +1,336 @@
+package rest
+
+import (
+	"TrafficPolice/internal/converter"
+	"TrafficPolice/internal/domain"
+	"TrafficPolice/internal/errs"
+	"TrafficPolice/internal/service"
+	"TrafficPolice/internal/service/mocks"
+	"TrafficPolice/internal/tokens"
+	"TrafficPolice/internal/transport/rest/dto"
+	"TrafficPolice/internal/transport/rest/middlewares"
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func TestPaymentWebhook(t *testing.T) {
+	fineConverter := converter.NewFineConverter()
+	validate := validator.New(validator.WithRequiredStructEnabled())
+	secret := "secret"
+	paidAt := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	body, err := json.Marshal(dto.FinePayment{PaymentID: "payment_id", Reference: "18810000000000000001",
+		Amount: 2500, PaidAt: paidAt})
+	assert.NoError(t, err)
+	noReferenceBody := []byte(`{"payment_id":"payment_id","amount":2500,"paid_at":"2024-03-01T12:00:00Z"}`)
+	payment := domain.FinePayment{PaymentID: "payment_id", Reference: "18810000000000000001", Amount: 2500,
+		PaidAt: paidAt}
+
+	testCases := []struct {
+		name             string
+		webhookSecret    string
+		body             []byte
+		signature        string
+		buildFineService func() service.FineService
+		expectedCode     int
+	}{
+		{
+			name:          "Fine is paid. 200 OK",
+			webhookSecret: secret,
+			body:          body,
+			signature:     sign(secret, body),
+			buildFineService: func() service.FineService {
+				mockService := mocks.NewFineService(t)
+				mockService.On("PayFine", mock.Anything, payment).
+					Return(domain.Fine{ID: "fine_id", Status: domain.FinePaid}, nil)
+
+				return mockService
+			},
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:          "Wrong signature. 401 Unauthorized",
+			webhookSecret: secret,
+			body:          body,
+			signature:     sign("other_secret", body),
+			buildFineService: func() service.FineService {
+				return mocks.NewFineService(t)
+			},
+			expectedCode: http.StatusUnauthorized,
+		},
+		{
+			name:          "Secret is not set. 401 Unauthorized",
+			webhookSecret: "",
+			body:          body,
+			signature:     sign("", body),
+			buildFineService: func() service.FineService {
+				return mocks.NewFineService(t)
+			},
+			expectedCode: http.StatusUnauthorized,
+		},
+		{
+			name:          "Payment without reference. 400 Bad request",
+			webhookSecret: secret,
+			body:          noReferenceBody,
+			signature:     sign(secret, noReferenceBody),
+			buildFineService: func() service.FineService {
+				return mocks.NewFineService(t)
+			},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:          "Unknown reference. 404 Not found",
+			webhookSecret: secret,
+			body:          body,
+			signature:     sign(secret, body),
+			buildFineService: func() service.FineService {
+				mockService := mocks.NewFineService(t)
+				mockService.On("PayFine", mock.Anything, payment).
+					Return(domain.Fine{}, errs.ErrNoFine)
+
+				return mockService
+			},
+			expectedCode: http.StatusNotFound,
+		},
+		{
+			name:          "Fine is paid by another payment. 409 Conflict",
+			webhookSecret: secret,
+			body:          body,
+			signature:     sign(secret, body),
+			buildFineService: func() service.FineService {
+				mockService := mocks.NewFineService(t)
+				mockService.On("PayFine", mock.Anything, payment).
+					Return(domain.Fine{}, errs.ErrFineAlreadyPaid)
+
+				return mockService
+			},
+			expectedCode: http.StatusConflict,
+		},
+		{
+			name:          "Insufficient payment. 422 Unprocessable entity",
+			webhookSecret: secret,
+			body:          body,
+			signature:     sign(secret, body),
+			buildFineService: func() service.FineService {
+				mockService := mocks.NewFineService(t)
+				mockService.On("PayFine", mock.Anything, payment).
+					Return(domain.Fine{}, errs.ErrInsufficientPayment)
+
+				return mockService
+			},
+			expectedCode: http.StatusUnprocessableEntity,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			handler := NewFineHandler(tc.buildFineService(), validate, fineConverter, tc.webhookSecret)
+
+			req := httptest.NewRequest(http.MethodPost, "/fines/payments", bytes.NewReader(tc.body))
+			req.Header.Set(signatureHeader, tc.signature)
+			rec := httptest.NewRecorder()
+
+			handler.PaymentWebhook(rec, req)
+			assert.Equal(t, tc.expectedCode, rec.Code)
+		})
+	}
+}
+
+func TestGetFines(t *testing.T) {
+	fineConverter := converter.NewFineConverter()
+	unpaid := []domain.FineStatus{domain.FineIssued, domain.FineOverdue}
+	fines := []domain.Fine{{ID: uuid.New().String(), Status: domain.FineOverdue}}
+
+	testCases := []struct {
+		name             string
+		query            string
+		buildFineService func() service.FineService
+		expectedCode     int
+	}{
+		{
+			name:  "Unpaid fines by default. 200 OK",
+			query: "",
+			buildFineService: func() service.FineService {
+				mockService := mocks.NewFineService(t)
+				mockService.On("GetFines", mock.Anything, unpaid, defaultFinesLimit, 0).
+					Return(fines, nil)
+
+				return mockService
+			},
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:  "Overdue fines with limit and offset. 200 OK",
+			query: "?status=overdue&limit=10&offset=20",
+			buildFineService: func() service.FineService {
+				mockService := mocks.NewFineService(t)
+				mockService.On("GetFines", mock.Anything, []domain.FineStatus{domain.FineOverdue}, 10, 20).
+					Return(fines, nil)
+
+				return mockService
+			},
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:  "No fines. 204 No content",
+			query: "?status=paid,cancelled",
+			buildFineService: func() service.FineService {
+				mockService := mocks.NewFineService(t)
+				mockService.On("GetFines", mock.Anything,
+					[]domain.FineStatus{domain.FinePaid, domain.FineCancelled}, defaultFinesLimit, 0).
+					Return(nil, errs.ErrNoRows)
+
+				return mockService
+			},
+			expectedCode: http.StatusNoContent,
+		},
+		{
+			name:  "Unknown status. 400 Bad request",
+			query: "?status=refunded",
+			buildFineService: func() service.FineService {
+				return mocks.NewFineService(t)
+			},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:  "Invalid offset. 400 Bad request",
+			query: "?offset=-1",
+			buildFineService: func() service.FineService {
+				return mocks.NewFineService(t)
+			},
+			expectedCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			handler := NewFineHandler(tc.buildFineService(), nil, fineConverter, "")
+
+			req := httptest.NewRequest(http.MethodGet, "/fines"+tc.query, nil)
+			rec := httptest.NewRecorder()
+
+			handler.GetFines(rec, req)
+			assert.Equal(t, tc.expectedCode, rec.Code)
+			if tc.expectedCode == http.StatusOK {
+				var actual []dto.Fine
+				assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &actual))
+				assert.Equal(t, fineConverter.MapSliceDomainToDto(fines), actual)
+			}
+		})
+	}
+}
+
+func TestCancelFine(t *testing.T) {
+	fineConverter := converter.NewFineConverter()
+	fineID := uuid.New().String()
+	tokenInfo := tokens.TokenInfo{UserID: uuid.New().String(), UserRole: domain.DirectorRole}
+	body := `{"reason": " wrong violator "}`
+
+	testCases := []struct {
+		name             string
+		fineID           string
+		body             string
+		buildFineService func() service.FineService
+		expectedCode     int
+	}{
+		{
+			name:   "Cancel fine. 200 OK",
+			fineID: fineID,
+			body:   body,
+			buildFineService: func() service.FineService {
+				mockService := mocks.NewFineService(t)
+				mockService.On("CancelFine", mock.Anything, tokenInfo.UserID, fineID, "wrong violator").
+					Return(domain.Fine{ID: fineID, Status: domain.FineCancelled}, nil)
+
+				return mockService
+			},
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:   "Fine is paid. 409 Conflict",
+			fineID: fineID,
+			body:   body,
+			buildFineService: func() service.FineService {
+				mockService := mocks.NewFineService(t)
+				mockService.On("CancelFine", mock.Anything, tokenInfo.UserID, fineID, "wrong violator").
+					Return(domain.Fine{}, errs.ErrFineCannotBeCancelled)
+
+				return mockService
+			},
+			expectedCode: http.StatusConflict,
+		},
+		{
+			name:   "Fine not exists. 404 Not found",
+			fineID: fineID,
+			body:   body,
+			buildFineService: func() service.FineService {
+				mockService := mocks.NewFineService(t)
+				mockService.On("CancelFine", mock.Anything, tokenInfo.UserID, fineID, "wrong violator").
+					Return(domain.Fine{}, errs.ErrNoFine)
+
+				return mockService
+			},
+			expectedCode: http.StatusNotFound,
+		},
+		{
+			name:   "Fine id is not uuid. 400 Bad request",
+			fineID: "fine_id",
+			body:   body,
+			buildFineService: func() service.FineService {
+				return mocks.NewFineService(t)
+			},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:   "Reason is empty. 400 Bad request",
+			fineID: fineID,
+			body:   `{"reason": "  "}`,
+			buildFineService: func() service.FineService {
+				return mocks.NewFineService(t)
+			},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:   "Body is not json. 400 Bad request",
+			fineID: fineID,
+			body:   "reason",
+			buildFineService: func() service.FineService {
+				return mocks.NewFineService(t)
+			},
+			expectedCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			handler := NewFineHandler(tc.buildFineService(), nil, fineConverter, "")
+
+			req := httptest.NewRequest(http.MethodPost, "/fines/"+tc.fineID+"/cancel",
+				bytes.NewBufferString(tc.body))
+			req.SetPathValue(fineIDPathValue, tc.fineID)
+			rec := httptest.NewRecorder()
+
+			ctx := context.WithValue(req.Context(), middlewares.TokenInfoKey, tokenInfo)
+			handler.CancelFine(rec, req.WithContext(ctx))
+			assert.Equal(t, tc.expectedCode, rec.Code)
+		})
+	}
+}
@@ -74,7 +74,7 @@ func (h *AuthMiddleware) IsExpertConfirmed(next http.Handler) http.Handler {
 			return
 		}
 
-		expert, err := h.expertService.GetExpertByUserID(tokenInfo.UserID)
+		expert, err := h.expertService.GetExpertByUserID(r.Context(), tokenInfo.UserID)
 		if err != nil {
 			log.Println(err)
 			response.Unauthorized(w)
@@ -144,7 +144,7 @@ func TestIsExpertConfirmed(t *testing.T) {
 			headerKey: authorizationHeader,
 			buildExpertService: func() service.ExpertService {
 				expertService := mocks.NewExpertService(t)
-				expertService.On("GetExpertByUserID", mock.Anything).
+				expertService.On("GetExpertByUserID", mock.Anything, mock.Anything).
 					Return(domain.Expert{IsConfirmed: true}, nil)
 
 				return expertService
@@ -211,7 +211,7 @@ func TestIsExpertConfirmed(t *testing.T) {
 			headerKey: authorizationHeader,
 			buildExpertService: func() service.ExpertService {
 				expertService := mocks.NewExpertService(t)
-				expertService.On("GetExpertByUserID", mock.Anything).
+				expertService.On("GetExpertByUserID", mock.Anything, mock.Anything).
 					Return(domain.Expert{IsConfirmed: false}, nil)
 
 				return expertService
@@ -231,7 +231,7 @@ func TestIsExpertConfirmed(t *testing.T) {
 			headerKey: authorizationHeader,
 			buildExpertService: func() service.ExpertService {
 				expertService := mocks.NewExpertService(t)
-				expertService.On("GetExpertByUserID", mock.Anything).
+				expertService.On("GetExpertByUserID", mock.Anything, mock.Anything).
 					Return(domain.Expert{}, errs.ErrUserNotExists)
 
 				return expertService
@@ -0,0 +1,116 @@
+package rest
+
+import (
+	"TrafficPolice/internal/converter"
+	"TrafficPolice/internal/errs"
+	"TrafficPolice/internal/service"
+	"TrafficPolice/internal/transport/rest/response"
+	"encoding/json"
+	"errors"
+	"github.com/google/uuid"
+	"log"
+	"net/http"
+	"strconv"
+)
+
+const (
+	outboxIDPathValue  = "id"
+	defaultOutboxLimit = 100
+)
+
+type OutboxHandler struct {
+	outboxService   service.OutboxService
+	outboxConverter *converter.OutboxConverter
+}
+
+func NewOutboxHandler(
+	outboxService service.OutboxService,
+	outboxConverter *converter.OutboxConverter,
+) *OutboxHandler {
+	return &OutboxHandler{
+		outboxService:   outboxService,
+		outboxConverter: outboxConverter,
+	}
+}
+
+// GetProblemMessages docs
+// @Summary Получение неотправленных сообщений outbox
+// @Security ApiKeyAuth
+// @Tags outbox
+// @Description Получение сообщений outbox (уведомлений о штрафах), которые не удалось отправить за все попытки (failed),
+// @Description или которые не отправлены дольше заданного в конфиге времени (stuck). Воспользоваться может только директор
+// @ID outbox-get
+// @Produce  json
+// @Param limit query int false "Максимальное количество сообщений. По умолчанию 100"
+// @Success 200 {object} []dto.OutboxMessage
+// @Success 204 ""
+// @Failure 400,401 {object} response.Body
+// @Failure 500 {object} response.Body
+// @Failure default {object} response.Body
+// @Router /outbox [get]
+func (h *OutboxHandler) GetProblemMessages(w http.ResponseWriter, r *http.Request) {
+	limit := defaultOutboxLimit
+	if limitQuery := r.URL.Query().Get(limitKey); limitQuery != "" {
+		var err error
+		limit, err = strconv.Atoi(limitQuery)
+		if err != nil || limit <= 0 {
+			response.BadRequest(w, "limit must be positive number")
+			return
+		}
+	}
+
+	messages, err := h.outboxService.GetProblemMessages(r.Context(), limit)
+	if err != nil {
+		if errors.Is(err, errs.ErrNoRows) {
+			response.NoContent(w)
+			return
+		}
+		log.Println(err)
+		response.InternalServerError(w)
+		return
+	}
+
+	messagesBytes, err := json.Marshal(h.outboxConverter.MapSliceDomainToDto(messages))
+	if err != nil {
+		log.Println(err)
+		response.InternalServerError(w)
+		return
+	}
+
+	response.WriteResponse(w, http.StatusOK, messagesBytes)
+}
+
+// ReplayMessage docs
+// @Summary Повторная отправка сообщения outbox
+// @Security ApiKeyAuth
+// @Tags outbox
+// @Description Сбрасывает попытки неотправленного сообщения outbox, и оно отправляется при следующем запуске relay.
+// @Description Воспользоваться может только директор
+// @ID outbox-replay
+// @Produce  json
+// @Param id path string true "id сообщения"
+// @Success 200 {object} response.Body
+// @Failure 400,401,404 {object} response.Body
+// @Failure 500 {object} response.Body
+// @Failure default {object} response.Body
+// @Router /outbox/{id}/replay [post]
+func (h *OutboxHandler) ReplayMessage(w http.ResponseWriter, r *http.Request) {
+	messageID := r.PathValue(outboxIDPathValue)
+	if err := uuid.Validate(messageID); err != nil {
+		response.BadRequest(w, "Bad outbox message id")
+		return
+	}
+
+	err := h.outboxService.ReplayMessage(r.Context(), messageID)
+	if err != nil {
+		if errors.Is(err, errs.ErrNoOutboxMessage) {
+			response.NotFound(w, "Not published outbox message with input id not found")
+			return
+		}
+		log.Println(err)
+		response.InternalServerError(w)
+		return
+	}
+
+	response.OKMessage(w, "Outbox message is scheduled for replay")
+}
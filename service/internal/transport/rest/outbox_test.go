@@ -0,0 +1,164 @@
+package rest
+
+import (
+	"TrafficPolice/internal/converter"
+	"TrafficPolice/internal/domain"
+	"TrafficPolice/internal/errs"
+	"TrafficPolice/internal/service"
+	"TrafficPolice/internal/service/mocks"
+	"TrafficPolice/internal/transport/rest/dto"
+	"encoding/json"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestGetProblemMessages(t *testing.T) {
+	outboxConverter := converter.NewOutboxConverter()
+
+	failedAt := time.Now()
+	lastError := "connection refused"
+	messages := []domain.OutboxMessage{
+		{ID: uuid.New().String(), Kind: domain.FineNotificationKind, Payload: []byte(`{"case_id":"id"}`),
+			Attempts: 10, LastError: &lastError, FailedAt: &failedAt},
+		{ID: uuid.New().String(), Kind: domain.FineNotificationKind, Payload: []byte(`{"case_id":"id"}`)},
+	}
+
+	testCases := []struct {
+		name               string
+		query              string
+		buildOutboxService func() service.OutboxService
+		expectedCode       int
+		expectedStatuses   []string
+	}{
+		{
+			name:  "Get problem messages with default limit. 200 OK",
+			query: "",
+			buildOutboxService: func() service.OutboxService {
+				mockService := mocks.NewOutboxService(t)
+				mockService.On("GetProblemMessages", mock.Anything, defaultOutboxLimit).
+					Return(messages, nil)
+
+				return mockService
+			},
+			expectedCode:     http.StatusOK,
+			expectedStatuses: []string{"failed", "stuck"},
+		},
+		{
+			name:  "Get problem messages with limit. 200 OK",
+			query: "?limit=1",
+			buildOutboxService: func() service.OutboxService {
+				mockService := mocks.NewOutboxService(t)
+				mockService.On("GetProblemMessages", mock.Anything, 1).
+					Return(messages[:1], nil)
+
+				return mockService
+			},
+			expectedCode:     http.StatusOK,
+			expectedStatuses: []string{"failed"},
+		},
+		{
+			name:  "No problem messages. 204 No content",
+			query: "",
+			buildOutboxService: func() service.OutboxService {
+				mockService := mocks.NewOutboxService(t)
+				mockService.On("GetProblemMessages", mock.Anything, defaultOutboxLimit).
+					Return(nil, errs.ErrNoRows)
+
+				return mockService
+			},
+			expectedCode: http.StatusNoContent,
+		},
+		{
+			name:  "Invalid limit. 400 Bad request",
+			query: "?limit=-1",
+			buildOutboxService: func() service.OutboxService {
+				return mocks.NewOutboxService(t)
+			},
+			expectedCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			handler := NewOutboxHandler(tc.buildOutboxService(), outboxConverter)
+
+			req := httptest.NewRequest(http.MethodGet, "/outbox"+tc.query, nil)
+			rec := httptest.NewRecorder()
+
+			handler.GetProblemMessages(rec, req)
+			assert.Equal(t, tc.expectedCode, rec.Code)
+			if tc.expectedCode == http.StatusOK {
+				var actual []dto.OutboxMessage
+				assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &actual))
+				statuses := make([]string, len(actual))
+				for i := range actual {
+					statuses[i] = actual[i].Status
+				}
+				assert.Equal(t, tc.expectedStatuses, statuses)
+			}
+		})
+	}
+}
+
+func TestReplayMessage(t *testing.T) {
+	outboxConverter := converter.NewOutboxConverter()
+	messageID := uuid.New().String()
+
+	testCases := []struct {
+		name               string
+		messageID          string
+		buildOutboxService func() service.OutboxService
+		expectedCode       int
+	}{
+		{
+			name:      "Replay message. 200 OK",
+			messageID: messageID,
+			buildOutboxService: func() service.OutboxService {
+				mockService := mocks.NewOutboxService(t)
+				mockService.On("ReplayMessage", mock.Anything, messageID).
+					Return(nil)
+
+				return mockService
+			},
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:      "Message is published or not exists. 404 Not found",
+			messageID: messageID,
+			buildOutboxService: func() service.OutboxService {
+				mockService := mocks.NewOutboxService(t)
+				mockService.On("ReplayMessage", mock.Anything, messageID).
+					Return(errs.ErrNoOutboxMessage)
+
+				return mockService
+			},
+			expectedCode: http.StatusNotFound,
+		},
+		{
+			name:      "Message id is not uuid. 400 Bad request",
+			messageID: "message_id",
+			buildOutboxService: func() service.OutboxService {
+				return mocks.NewOutboxService(t)
+			},
+			expectedCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			handler := NewOutboxHandler(tc.buildOutboxService(), outboxConverter)
+
+			req := httptest.NewRequest(http.MethodPost, "/outbox/"+tc.messageID+"/replay", nil)
+			req.SetPathValue(outboxIDPathValue, tc.messageID)
+			rec := httptest.NewRecorder()
+
+			handler.ReplayMessage(rec, req)
+			assert.Equal(t, tc.expectedCode, rec.Code)
+		})
+	}
+}
@@ -1,6 +1,8 @@
 package rest
 
 import (
+	"TrafficPolice/internal/transport/rest/response"
+	"errors"
 	"fmt"
 	"log"
 	"mime/multipart"
@@ -15,16 +17,20 @@ var allowedExtensions = map[string]struct{}{
 }
 
 const (
-	contentTypeKey = "Content-Type"
-	contentImage   = "image"
+	contentTypeKey    = "Content-Type"
+	contentImage      = "image"
+	multipartFormData = "multipart/form-data"
+
+	// multipartOverhead is max size of multipart form without file content
+	multipartOverhead = 1 << 20
+
+	// maxImgSize is max size of image in bytes, which is read into memory
+	maxImgSize = 10 << 20
 )
 
 func parseMultipartForm(r *http.Request, key string) (multipart.File, *multipart.FileHeader, error) {
 	// parse input, type multipart/form-data
-	// 10 MB
-	maxMemory := int64(10 << 20)
-
-	err := r.ParseMultipartForm(maxMemory)
+	err := r.ParseMultipartForm(maxImgSize)
 	if err != nil {
 		log.Printf("Error while ParseMultipartForm: %v", err)
 		return nil, nil, err
@@ -59,3 +65,13 @@ func getImgExtension(contentType string) (string, error) {
 		return "", fmt.Errorf("extension %s is not allowed", extension)
 	}
 }
+
+// writeParseMultipartError writes 413 if request body is larger than limit, otherwise 400
+func writeParseMultipartError(w http.ResponseWriter, err error) {
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		response.RequestEntityTooLarge(w, err.Error())
+		return
+	}
+	response.BadRequest(w, err.Error())
+}
@@ -39,7 +39,7 @@ func NewRatingHandler(
 // @Failure default {object} response.Body
 // @Router /rating [get]
 func (h *RatingHandler) GetRating(w http.ResponseWriter, r *http.Request) {
-	rating, err := h.ratingService.GetRating()
+	rating, err := h.ratingService.GetRating(r.Context())
 	if err != nil {
 		if errors.Is(err, errs.ErrNoRows) {
 			response.NoContent(w)
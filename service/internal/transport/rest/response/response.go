@@ -45,6 +45,14 @@ func NotFound(w http.ResponseWriter, text string) {
 	WriteMessage(w, http.StatusNotFound, text)
 }
 
+func Forbidden(w http.ResponseWriter, text string) {
+	WriteMessage(w, http.StatusForbidden, text)
+}
+
+func RequestEntityTooLarge(w http.ResponseWriter, text string) {
+	WriteMessage(w, http.StatusRequestEntityTooLarge, text)
+}
+
 func OKMessage(w http.ResponseWriter, text string) {
 	WriteMessage(w, http.StatusOK, text)
 }
@@ -57,6 +65,10 @@ func Conflict(w http.ResponseWriter, text string) {
 	WriteMessage(w, http.StatusConflict, text)
 }
 
+func UnprocessableEntity(w http.ResponseWriter, text string) {
+	WriteMessage(w, http.StatusUnprocessableEntity, text)
+}
+
 func InternalServerError(w http.ResponseWriter) {
 	WriteMessage(w, http.StatusInternalServerError, "Internal server error")
 }
@@ -66,6 +78,16 @@ func Unauthorized(w http.ResponseWriter) {
 	WriteMessage(w, http.StatusUnauthorized, "Unauthorized")
 }
 
+func Image(w http.ResponseWriter, contentType string, data []byte) {
+	w.Header().Set("Content-Type", contentType)
+	w.WriteHeader(http.StatusOK)
+
+	_, err := w.Write(data)
+	if err != nil {
+		log.Printf("error occurred when write image: %v", err)
+	}
+}
+
 func IdResponse(w http.ResponseWriter, id string) {
 	respBody := IDResponse{ID: id}
 	body, err := json.Marshal(respBody)
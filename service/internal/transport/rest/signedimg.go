@@ -0,0 +1,70 @@
+package rest
+
+import (
+	"TrafficPolice/internal/domain"
+	"TrafficPolice/internal/service"
+	"TrafficPolice/internal/transport/rest/response"
+	"TrafficPolice/pkg/signedurl"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// SignedCaseImgPath is path of case image, which is authenticated by signature of url
+const SignedCaseImgPath = "/internal/case/{id}/img"
+
+// SignedImageHandler gives case images by signed expiring urls, so fine_notification gets image
+// without image bytes in fine event
+type SignedImageHandler struct {
+	caseService service.CaseService
+	signer      *signedurl.Signer
+	baseURL     string
+}
+
+func NewSignedImageHandler(
+	caseService service.CaseService,
+	signer *signedurl.Signer,
+	baseURL string,
+) *SignedImageHandler {
+	return &SignedImageHandler{
+		caseService: caseService,
+		signer:      signer,
+		baseURL:     strings.TrimSuffix(baseURL, "/"),
+	}
+}
+
+// CaseImageURL returns signed url of web image of case, which is valid until expiresAt
+func (h *SignedImageHandler) CaseImageURL(caseID string, expiresAt time.Time) string {
+	path := strings.Replace(SignedCaseImgPath, "{"+caseIDPathValue+"}", caseID, 1)
+	return h.baseURL + path + "?" + h.signer.Sign(path, expiresAt).Encode()
+}
+
+// GetCaseImg docs
+// @Summary Получение фотографии проишествия по подписанной ссылке
+// @Tags case
+// @Description Получение фотографии проишествия размера web по ссылке из события о штрафе. Ссылка подписывается
+// @Description service и действует до времени expires, авторизация не требуется. Используется fine_notification
+// @ID case-image-signed-get
+// @Produce  image/jpeg
+// @Param id path string true "id проишествия"
+// @Param expires query int true "Время окончания действия ссылки (unix)"
+// @Param signature query string true "Подпись ссылки"
+// @Success 200 {file} formData
+// @Failure 400,403,404,410 {object} response.Body
+// @Failure 500 {object} response.Body
+// @Failure default {object} response.Body
+// @Router /internal/case/{id}/img [get]
+func (h *SignedImageHandler) GetCaseImg(w http.ResponseWriter, r *http.Request) {
+	err := h.signer.Verify(r.URL.Path, r.URL.Query(), time.Now())
+	if errors.Is(err, signedurl.ErrExpired) {
+		response.WriteMessage(w, http.StatusGone, "Image url is expired")
+		return
+	}
+	if err != nil {
+		response.Forbidden(w, "Invalid image url signature")
+		return
+	}
+
+	writeCaseImg(r.Context(), w, h.caseService, r.PathValue(caseIDPathValue), domain.WebImage)
+}
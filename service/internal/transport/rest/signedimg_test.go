@@ -0,0 +1,127 @@
+package rest
+
+import (
+	"TrafficPolice/internal/domain"
+	"TrafficPolice/internal/errs"
+	"TrafficPolice/internal/service"
+	"TrafficPolice/internal/service/mocks"
+	"TrafficPolice/pkg/signedurl"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSignedImageHandlerGetCaseImg(t *testing.T) {
+	caseID := uuid.New().String()
+	otherCaseID := uuid.New().String()
+	img := domain.Image{Data: []byte("image"), ContentType: "image/jpeg"}
+	signer := signedurl.New("secret")
+
+	testCases := []struct {
+		name             string
+		caseID           string
+		urlCaseID        string
+		expiresAt        time.Time
+		signer           *signedurl.Signer
+		buildCaseService func() service.CaseService
+		expectedCode     int
+	}{
+		{
+			name:      "Signed url. 200 OK",
+			caseID:    caseID,
+			urlCaseID: caseID,
+			expiresAt: time.Now().Add(time.Hour),
+			signer:    signer,
+			buildCaseService: func() service.CaseService {
+				mockService := mocks.NewCaseService(t)
+				mockService.On("GetCaseImg", mock.Anything, caseID, domain.WebImage).
+					Return(img, nil)
+
+				return mockService
+			},
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:      "Url is expired. 410 Gone",
+			caseID:    caseID,
+			urlCaseID: caseID,
+			expiresAt: time.Now().Add(-time.Minute),
+			signer:    signer,
+			buildCaseService: func() service.CaseService {
+				return mocks.NewCaseService(t)
+			},
+			expectedCode: http.StatusGone,
+		},
+		{
+			name:      "Url is signed by other key. 403 Forbidden",
+			caseID:    caseID,
+			urlCaseID: caseID,
+			expiresAt: time.Now().Add(time.Hour),
+			signer:    signedurl.New("other secret"),
+			buildCaseService: func() service.CaseService {
+				return mocks.NewCaseService(t)
+			},
+			expectedCode: http.StatusForbidden,
+		},
+		{
+			name:      "Signature of other case. 403 Forbidden",
+			caseID:    caseID,
+			urlCaseID: otherCaseID,
+			expiresAt: time.Now().Add(time.Hour),
+			signer:    signer,
+			buildCaseService: func() service.CaseService {
+				return mocks.NewCaseService(t)
+			},
+			expectedCode: http.StatusForbidden,
+		},
+		{
+			name:      "Image not exists. 404 Not found",
+			caseID:    caseID,
+			urlCaseID: caseID,
+			expiresAt: time.Now().Add(time.Hour),
+			signer:    signer,
+			buildCaseService: func() service.CaseService {
+				mockService := mocks.NewCaseService(t)
+				mockService.On("GetCaseImg", mock.Anything, caseID, domain.WebImage).
+					Return(domain.Image{}, errs.ErrNoImage)
+
+				return mockService
+			},
+			expectedCode: http.StatusNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			handler := NewSignedImageHandler(tc.buildCaseService(), signer, "http://service:8080/")
+
+			signedURL := NewSignedImageHandler(nil, tc.signer, "").CaseImageURL(tc.urlCaseID, tc.expiresAt)
+			signedURL = strings.Replace(signedURL, tc.urlCaseID, tc.caseID, 1)
+			req := httptest.NewRequest(http.MethodGet, signedURL, nil)
+			req.SetPathValue(caseIDPathValue, tc.caseID)
+			rec := httptest.NewRecorder()
+
+			handler.GetCaseImg(rec, req)
+
+			assert.Equal(t, tc.expectedCode, rec.Code)
+		})
+	}
+}
+
+func TestSignedImageHandlerCaseImageURL(t *testing.T) {
+	handler := NewSignedImageHandler(nil, signedurl.New("secret"), "http://service:8080/")
+	expiresAt := time.Unix(1700000000, 0)
+
+	actual, err := url.Parse(handler.CaseImageURL("case", expiresAt))
+
+	assert.NoError(t, err)
+	assert.Equal(t, "http://service:8080/internal/case/case/img", actual.Scheme+"://"+actual.Host+actual.Path)
+	assert.Equal(t, "1700000000", actual.Query().Get(signedurl.ExpiresKey))
+	assert.NotEmpty(t, actual.Query().Get(signedurl.SignatureKey))
+}
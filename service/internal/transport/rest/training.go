@@ -98,7 +98,7 @@ func (h *TrainingHandler) GetSolvedCasesByParams(w http.ResponseWriter, r *http.
 		Limit: limit,
 	}
 
-	cases, err := h.trainingService.GetSolvedCasesByParams(
+	cases, err := h.trainingService.GetSolvedCasesByParams(r.Context(),
 		h.solvedCasesConverter.MapParamsDtoToDomain(params),
 		paginationParams,
 	)
@@ -112,7 +112,7 @@ func (h *TrainingHandler) GetSolvedCasesByParams(w http.ResponseWriter, r *http.
 		return
 	}
 
-	pagination, err := h.paginationService.GetPaginationInfo(casesTableName, paginationParams)
+	pagination, err := h.paginationService.GetPaginationInfo(r.Context(), casesTableName, paginationParams)
 	if err != nil {
 		response.InternalServerError(w)
 		return
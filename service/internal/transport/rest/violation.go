@@ -71,7 +71,7 @@ func (h *ViolationHandler) InsertViolations(w http.ResponseWriter, r *http.Reque
 
 	violations := h.parseViolations(rows)
 
-	err = h.service.InsertViolations(violations)
+	err = h.service.InsertViolations(r.Context(), violations)
 	if err != nil {
 		response.InternalServerError(w)
 		return
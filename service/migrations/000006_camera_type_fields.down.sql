@@ -0,0 +1,5 @@
+ALTER TABLE
+    "camera_type_fields"
+    DROP CONSTRAINT "camera_type_fields_camera_type_id_foreign";
+
+DROP TABLE camera_type_fields CASCADE;
\ No newline at end of file
@@ -0,0 +1,14 @@
+CREATE TABLE "camera_type_fields"
+(
+    "camera_type_id" UUID         NOT NULL,
+    "case_field"     VARCHAR(255) NOT NULL,
+    "payload_key"    VARCHAR(255) NOT NULL,
+    "value_format"   VARCHAR(255) NOT NULL DEFAULT ''
+);
+ALTER TABLE
+    "camera_type_fields"
+    ADD PRIMARY KEY ("camera_type_id", "case_field");
+
+ALTER TABLE
+    "camera_type_fields"
+    ADD CONSTRAINT "camera_type_fields_camera_type_id_foreign" FOREIGN KEY ("camera_type_id") REFERENCES "camera_types" ("camera_type_id");
\ No newline at end of file
@@ -0,0 +1,3 @@
+ALTER TABLE
+    "cases"
+    DROP COLUMN "has_image";
\ No newline at end of file
@@ -0,0 +1,3 @@
+ALTER TABLE
+    "cases"
+    ADD COLUMN "has_image" BOOLEAN NOT NULL DEFAULT false;
\ No newline at end of file
@@ -0,0 +1,3 @@
+ALTER TABLE
+    "cases"
+    DROP COLUMN "image_overwrite_allowed";
\ No newline at end of file
@@ -0,0 +1,3 @@
+ALTER TABLE
+    "cases"
+    ADD COLUMN "image_overwrite_allowed" BOOLEAN NOT NULL DEFAULT false;
\ No newline at end of file
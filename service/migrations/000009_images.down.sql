@@ -0,0 +1 @@
+DROP TABLE images CASCADE;
\ No newline at end of file
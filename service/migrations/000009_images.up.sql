@@ -0,0 +1,14 @@
+CREATE TABLE "images"
+(
+    "owner_type"   VARCHAR(255) NOT NULL,
+    "owner_id"     UUID         NOT NULL,
+    "blob_key"     CHAR(64)     NOT NULL,
+    "content_type" VARCHAR(255) NOT NULL,
+    "size"         BIGINT       NOT NULL,
+    "created_at"   TIMESTAMP    NOT NULL
+);
+ALTER TABLE
+    "images"
+    ADD PRIMARY KEY ("owner_type", "owner_id");
+
+CREATE INDEX "images_blob_key_index" ON "images" ("blob_key");
\ No newline at end of file
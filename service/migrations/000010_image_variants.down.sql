@@ -0,0 +1,8 @@
+DROP TABLE camera_redact_regions CASCADE;
+
+DELETE FROM images WHERE variant <> 'original';
+ALTER TABLE "images" DROP CONSTRAINT "images_pkey";
+ALTER TABLE
+    "images"
+    ADD PRIMARY KEY ("owner_type", "owner_id");
+ALTER TABLE "images" DROP COLUMN "variant";
\ No newline at end of file
@@ -0,0 +1,18 @@
+ALTER TABLE "images" ADD COLUMN "variant" VARCHAR(255) NOT NULL DEFAULT 'original';
+ALTER TABLE "images" DROP CONSTRAINT "images_pkey";
+ALTER TABLE
+    "images"
+    ADD PRIMARY KEY ("owner_type", "owner_id", "variant");
+
+CREATE TABLE "camera_redact_regions"
+(
+    "camera_id" UUID    NOT NULL,
+    "x"         INTEGER NOT NULL,
+    "y"         INTEGER NOT NULL,
+    "width"     INTEGER NOT NULL,
+    "height"    INTEGER NOT NULL
+);
+CREATE INDEX "camera_redact_regions_camera_id_index" ON "camera_redact_regions" ("camera_id");
+ALTER TABLE
+    "camera_redact_regions"
+    ADD CONSTRAINT "camera_redact_regions_camera_id_foreign" FOREIGN KEY ("camera_id") REFERENCES "cameras" ("camera_id");
\ No newline at end of file
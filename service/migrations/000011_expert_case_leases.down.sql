@@ -0,0 +1,8 @@
+DROP INDEX "expert_cases_active_lease_index";
+
+ALTER TABLE
+    "expert_cases"
+    DROP COLUMN "expired_at";
+ALTER TABLE
+    "expert_cases"
+    DROP COLUMN "lease_expires_at";
\ No newline at end of file
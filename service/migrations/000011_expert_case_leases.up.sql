@@ -0,0 +1,8 @@
+ALTER TABLE "expert_cases" ADD COLUMN "lease_expires_at" TIMESTAMP NULL;
+ALTER TABLE "expert_cases" ADD COLUMN "expired_at" TIMESTAMP NULL;
+
+-- Cases, which are assigned before leases, get default lease from the moment of migration
+UPDATE "expert_cases" SET "lease_expires_at" = NOW() + INTERVAL '24 hours' WHERE "is_expert_solve" = false;
+
+CREATE INDEX "expert_cases_active_lease_index" ON "expert_cases" ("lease_expires_at")
+    WHERE "is_expert_solve" = false AND "expired_at" IS NULL;
\ No newline at end of file
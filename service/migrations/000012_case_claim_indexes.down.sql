@@ -0,0 +1,2 @@
+DROP INDEX "expert_cases_case_id_index";
+DROP INDEX "cases_not_solved_index";
\ No newline at end of file
@@ -0,0 +1,4 @@
+CREATE INDEX "cases_not_solved_index" ON "cases" ("required_skill", "case_date", "case_id")
+    WHERE "is_solved" = false AND "has_image" = true;
+
+CREATE INDEX "expert_cases_case_id_index" ON "expert_cases" ("case_id");
\ No newline at end of file
@@ -0,0 +1,3 @@
+DROP INDEX "outbox_not_published_index";
+
+DROP TABLE "outbox";
\ No newline at end of file
@@ -0,0 +1,14 @@
+CREATE TABLE "outbox"
+(
+    "outbox_id"    UUID         NOT NULL,
+    "kind"         VARCHAR(255) NOT NULL,
+    "payload"      JSONB        NOT NULL,
+    "created_at"   TIMESTAMP    NOT NULL,
+    "published_at" TIMESTAMP    NULL
+);
+ALTER TABLE
+    "outbox"
+    ADD PRIMARY KEY ("outbox_id");
+
+CREATE INDEX "outbox_not_published_index" ON "outbox" ("created_at")
+    WHERE "published_at" IS NULL;
\ No newline at end of file
@@ -0,0 +1,16 @@
+DROP INDEX "outbox_pending_index";
+CREATE INDEX "outbox_not_published_index" ON "outbox" ("created_at")
+    WHERE "published_at" IS NULL;
+
+ALTER TABLE
+    "outbox"
+    DROP COLUMN "failed_at";
+ALTER TABLE
+    "outbox"
+    DROP COLUMN "last_error";
+ALTER TABLE
+    "outbox"
+    DROP COLUMN "next_attempt_at";
+ALTER TABLE
+    "outbox"
+    DROP COLUMN "attempts";
\ No newline at end of file
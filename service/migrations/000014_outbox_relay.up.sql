@@ -0,0 +1,8 @@
+ALTER TABLE "outbox" ADD COLUMN "attempts" INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE "outbox" ADD COLUMN "next_attempt_at" TIMESTAMP NOT NULL DEFAULT NOW();
+ALTER TABLE "outbox" ADD COLUMN "last_error" TEXT NULL;
+ALTER TABLE "outbox" ADD COLUMN "failed_at" TIMESTAMP NULL;
+
+DROP INDEX "outbox_not_published_index";
+CREATE INDEX "outbox_pending_index" ON "outbox" ("next_attempt_at")
+    WHERE "published_at" IS NULL AND "failed_at" IS NULL;
\ No newline at end of file
@@ -0,0 +1,3 @@
+ALTER TABLE
+    "persons"
+    DROP COLUMN "notify_channels";
\ No newline at end of file
@@ -0,0 +1,2 @@
+-- notify_channels keeps channels of fine notification in order of preference, empty means default order
+ALTER TABLE "persons" ADD COLUMN "notify_channels" TEXT[] NOT NULL DEFAULT '{}';
\ No newline at end of file
@@ -0,0 +1 @@
+DROP TABLE "fines";
\ No newline at end of file
@@ -0,0 +1,31 @@
+CREATE TABLE "fines"
+(
+    "fine_id"         UUID         NOT NULL,
+    "case_id"         UUID         NOT NULL,
+    "reference"       VARCHAR(20)  NOT NULL,
+    "amount"          BIGINT       NOT NULL,
+    "discount_amount" BIGINT       NOT NULL,
+    "discount_until"  TIMESTAMP    NULL,
+    "issued_at"       TIMESTAMP    NOT NULL,
+    "due_at"          TIMESTAMP    NOT NULL,
+    "status"          VARCHAR(16)  NOT NULL,
+    "payment_id"      VARCHAR(255) NULL,
+    "paid_amount"     BIGINT       NULL,
+    "paid_at"         TIMESTAMP    NULL,
+    "cancelled_at"    TIMESTAMP    NULL
+);
+ALTER TABLE
+    "fines"
+    ADD PRIMARY KEY ("fine_id");
+ALTER TABLE
+    "fines"
+    ADD CONSTRAINT "fines_case_id_unique" UNIQUE ("case_id");
+ALTER TABLE
+    "fines"
+    ADD CONSTRAINT "fines_reference_unique" UNIQUE ("reference");
+ALTER TABLE
+    "fines"
+    ADD CONSTRAINT "fines_case_id_foreign" FOREIGN KEY ("case_id") REFERENCES "cases" ("case_id");
+
+CREATE INDEX "fines_unpaid_index" ON "fines" ("due_at")
+    WHERE "status" IN ('issued', 'overdue');
\ No newline at end of file
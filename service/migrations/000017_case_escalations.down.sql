@@ -0,0 +1,7 @@
+DROP TABLE "case_escalations";
+
+DROP INDEX "cases_review_index";
+
+ALTER TABLE
+    "cases"
+    DROP COLUMN "review_requested_at";
\ No newline at end of file
@@ -0,0 +1,25 @@
+-- review_requested_at is set, when required skill of case can not be raised above maximum skill,
+-- then case is not given to experts and waits for decision of director
+ALTER TABLE "cases" ADD COLUMN "review_requested_at" TIMESTAMP NULL;
+
+CREATE INDEX "cases_review_index" ON "cases" ("review_requested_at")
+    WHERE "is_solved" = false AND "review_requested_at" IS NOT NULL;
+
+-- to_skill is NULL, when case is sent to review of director instead of raising required skill
+CREATE TABLE "case_escalations"
+(
+    "escalation_id" UUID        NOT NULL,
+    "case_id"       UUID        NOT NULL,
+    "from_skill"    INTEGER     NOT NULL,
+    "to_skill"      INTEGER     NULL,
+    "reason"        VARCHAR(32) NOT NULL,
+    "escalated_at"  TIMESTAMP   NOT NULL
+);
+ALTER TABLE
+    "case_escalations"
+    ADD PRIMARY KEY ("escalation_id");
+ALTER TABLE
+    "case_escalations"
+    ADD CONSTRAINT "case_escalations_case_id_foreign" FOREIGN KEY ("case_id") REFERENCES "cases" ("case_id");
+
+CREATE INDEX "case_escalations_case_id_index" ON "case_escalations" ("case_id", "escalated_at");
\ No newline at end of file
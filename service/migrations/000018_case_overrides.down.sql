@@ -0,0 +1,5 @@
+ALTER TABLE
+    "expert_cases"
+    DROP COLUMN "voided_at";
+
+DROP TABLE "case_overrides";
\ No newline at end of file
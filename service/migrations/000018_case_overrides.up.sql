@@ -0,0 +1,29 @@
+-- case_overrides is audit log of corrections of solved cases by director. new_fine_decision is NULL,
+-- when fine decision is not changed or case is reopened, fine_id is fine, which was cancelled or issued
+CREATE TABLE "case_overrides"
+(
+    "override_id"       UUID        NOT NULL,
+    "case_id"           UUID        NOT NULL,
+    "user_id"           UUID        NOT NULL,
+    "action"            VARCHAR(32) NOT NULL,
+    "old_fine_decision" BOOLEAN     NOT NULL,
+    "new_fine_decision" BOOLEAN     NULL,
+    "fine_id"           UUID        NULL,
+    "reason"            TEXT        NOT NULL,
+    "created_at"        TIMESTAMP   NOT NULL
+);
+ALTER TABLE
+    "case_overrides"
+    ADD PRIMARY KEY ("override_id");
+ALTER TABLE
+    "case_overrides"
+    ADD CONSTRAINT "case_overrides_case_id_foreign" FOREIGN KEY ("case_id") REFERENCES "cases" ("case_id");
+ALTER TABLE
+    "case_overrides"
+    ADD CONSTRAINT "case_overrides_user_id_foreign" FOREIGN KEY ("user_id") REFERENCES "users" ("user_id");
+
+CREATE INDEX "case_overrides_case_id_index" ON "case_overrides" ("case_id", "created_at");
+
+-- voided_at is set, when case is reopened by director: voided decisions and leases are kept in history,
+-- but are not counted in consensus, rating and analytics, and expert may get the case again
+ALTER TABLE "expert_cases" ADD COLUMN "voided_at" TIMESTAMP NULL;
\ No newline at end of file
@@ -0,0 +1,13 @@
+-- only the last fine of case is kept
+DELETE
+FROM "fines" AS f
+    USING "fines" AS newer
+WHERE newer."case_id" = f."case_id"
+  AND newer."issued_at" > f."issued_at";
+
+DROP INDEX "fines_case_id_index";
+DROP INDEX "fines_case_id_active_unique";
+
+ALTER TABLE
+    "fines"
+    ADD CONSTRAINT "fines_case_id_unique" UNIQUE ("case_id");
\ No newline at end of file
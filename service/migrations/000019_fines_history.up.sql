@@ -0,0 +1,8 @@
+-- cancelled fines are kept as history of case, so case has only one not cancelled fine
+ALTER TABLE
+    "fines"
+    DROP CONSTRAINT "fines_case_id_unique";
+
+CREATE UNIQUE INDEX "fines_case_id_active_unique" ON "fines" ("case_id")
+    WHERE "status" <> 'cancelled';
+CREATE INDEX "fines_case_id_index" ON "fines" ("case_id", "issued_at");
\ No newline at end of file
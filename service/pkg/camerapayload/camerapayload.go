@@ -0,0 +1,93 @@
+// Package camerapayload encodes case information into the binary layout sent by cameras.
+//
+// Payload consists of 2 bytes of header and sequence of entries.
+// Entry consists of key size (2 bytes), value size (2 bytes), value type (1 byte), key and value.
+// Sizes are big endian. Value is string, signed leb128 int or nested sequence of entries.
+package camerapayload
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"github.com/jcalabro/leb128"
+	"math"
+)
+
+const (
+	TypeString byte = 0
+	TypeInt    byte = 1
+	TypeDict   byte = 2
+
+	HeaderSize      = 2
+	EntryHeaderSize = 5
+)
+
+var (
+	ErrKeyTooLong       = errors.New("key is too long")
+	ErrValueTooLong     = errors.New("value is too long")
+	ErrUnsupportedValue = errors.New("unsupported value")
+)
+
+// Entry is one key-value pair of payload. Value is string, int64 or []Entry
+type Entry struct {
+	Key   string
+	Value any
+}
+
+func String(key, value string) Entry {
+	return Entry{Key: key, Value: value}
+}
+
+func Int(key string, value int64) Entry {
+	return Entry{Key: key, Value: value}
+}
+
+func Dict(key string, entries ...Entry) Entry {
+	return Entry{Key: key, Value: entries}
+}
+
+// Encode writes header and entries in wire layout
+func Encode(entries ...Entry) ([]byte, error) {
+	return AppendEntries(make([]byte, HeaderSize), entries...)
+}
+
+// AppendEntries appends entries without header to buf
+func AppendEntries(buf []byte, entries ...Entry) ([]byte, error) {
+	for _, e := range entries {
+		valueType, value, err := encodeValue(e.Value)
+		if err != nil {
+			return nil, fmt.Errorf("key %q: %w", e.Key, err)
+		}
+		if len(e.Key) > math.MaxUint16 {
+			return nil, fmt.Errorf("key %q: %w", e.Key, ErrKeyTooLong)
+		}
+		if len(value) > math.MaxUint16 {
+			return nil, fmt.Errorf("key %q: %w", e.Key, ErrValueTooLong)
+		}
+
+		buf = binary.BigEndian.AppendUint16(buf, uint16(len(e.Key)))
+		buf = binary.BigEndian.AppendUint16(buf, uint16(len(value)))
+		buf = append(buf, valueType)
+		buf = append(buf, e.Key...)
+		buf = append(buf, value...)
+	}
+
+	return buf, nil
+}
+
+func encodeValue(value any) (byte, []byte, error) {
+	switch v := value.(type) {
+	case string:
+		return TypeString, []byte(v), nil
+	case int64:
+		return TypeInt, leb128.EncodeS64(v), nil
+	case []Entry:
+		dict, err := AppendEntries(nil, v...)
+		if err != nil {
+			return 0, nil, err
+		}
+		return TypeDict, dict, nil
+	default:
+		return 0, nil, fmt.Errorf("%w: %T", ErrUnsupportedValue, value)
+	}
+}
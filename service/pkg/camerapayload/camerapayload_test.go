@@ -0,0 +1,72 @@
+package camerapayload
+
+import (
+	"github.com/stretchr/testify/assert"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestEncode(t *testing.T) {
+	testCases := []struct {
+		name            string
+		entries         []Entry
+		expectedPayload []byte
+		expectedErr     error
+	}{
+		{
+			name:            "Empty payload",
+			entries:         nil,
+			expectedPayload: []byte{0, 0},
+		},
+		{
+			name:            "String value",
+			entries:         []Entry{String("k", "abc")},
+			expectedPayload: []byte{0, 0, 0, 1, 0, 3, TypeString, 'k', 'a', 'b', 'c'},
+		},
+		{
+			name:            "Negative int value",
+			entries:         []Entry{Int("k", -2)},
+			expectedPayload: []byte{0, 0, 0, 1, 0, 1, TypeInt, 'k', 0x7e},
+		},
+		{
+			name:            "Multi-byte int value",
+			entries:         []Entry{Int("k", 300)},
+			expectedPayload: []byte{0, 0, 0, 1, 0, 2, TypeInt, 'k', 0xac, 0x02},
+		},
+		{
+			name:    "Nested dict",
+			entries: []Entry{Dict("d", String("k", "v")), Int("n", 1)},
+			expectedPayload: []byte{
+				0, 0,
+				0, 1, 0, 7, TypeDict, 'd',
+				0, 1, 0, 1, TypeString, 'k', 'v',
+				0, 1, 0, 1, TypeInt, 'n', 1,
+			},
+		},
+		{
+			name:        "Too long value",
+			entries:     []Entry{String("k", strings.Repeat("a", math.MaxUint16+1))},
+			expectedErr: ErrValueTooLong,
+		},
+		{
+			name:        "Too long key in nested dict",
+			entries:     []Entry{Dict("d", String(strings.Repeat("k", math.MaxUint16+1), "v"))},
+			expectedErr: ErrKeyTooLong,
+		},
+		{
+			name:        "Unsupported value",
+			entries:     []Entry{{Key: "k", Value: 1.5}},
+			expectedErr: ErrUnsupportedValue,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			payload, err := Encode(tc.entries...)
+
+			assert.ErrorIs(t, err, tc.expectedErr)
+			assert.Equal(t, tc.expectedPayload, payload)
+		})
+	}
+}
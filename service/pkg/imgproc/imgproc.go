@@ -0,0 +1,114 @@
+// Package imgproc implements image operations used for derived images:
+// downscaling by area averaging and blurring of rectangles.
+package imgproc
+
+import (
+	"image"
+	"image/draw"
+)
+
+// blurPasses of box blur approximate gaussian blur
+const blurPasses = 3
+
+// ToRGBA copies image to RGBA image with bounds starting at zero point.
+// Only pixels are copied, so metadata of source file is lost
+func ToRGBA(img image.Image) *image.RGBA {
+	bounds := img.Bounds()
+	dst := image.NewRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	draw.Draw(dst, dst.Bounds(), img, bounds.Min, draw.Src)
+	return dst
+}
+
+// Fit downscales image keeping aspect ratio, so that width and height are not more than maxSide.
+// Every pixel of result is average of source pixels it covers. Image, which already fits, is returned as is
+func Fit(img *image.RGBA, maxSide int) *image.RGBA {
+	srcW, srcH := img.Bounds().Dx(), img.Bounds().Dy()
+	if srcW <= maxSide && srcH <= maxSide {
+		return img
+	}
+
+	dstW, dstH := maxSide, maxSide
+	if srcW > srcH {
+		dstH = max(1, srcH*maxSide/srcW)
+	} else {
+		dstW = max(1, srcW*maxSide/srcH)
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, dstW, dstH))
+	for dy := 0; dy < dstH; dy++ {
+		y0, y1 := dy*srcH/dstH, (dy+1)*srcH/dstH
+		for dx := 0; dx < dstW; dx++ {
+			x0, x1 := dx*srcW/dstW, (dx+1)*srcW/dstW
+
+			var sum [4]int
+			for y := y0; y < y1; y++ {
+				row := img.Pix[img.PixOffset(img.Rect.Min.X+x0, img.Rect.Min.Y+y):]
+				for x := 0; x < x1-x0; x++ {
+					for c := 0; c < 4; c++ {
+						sum[c] += int(row[x*4+c])
+					}
+				}
+			}
+
+			n := (x1 - x0) * (y1 - y0)
+			off := dst.PixOffset(dx, dy)
+			for c := 0; c < 4; c++ {
+				dst.Pix[off+c] = uint8(sum[c] / n)
+			}
+		}
+	}
+
+	return dst
+}
+
+// BlurRect blurs rectangle of image in place. Rectangle is clipped by image bounds.
+// Radius depends on size of rectangle, so details inside it can not be recognized
+func BlurRect(img *image.RGBA, rect image.Rectangle) {
+	rect = rect.Intersect(img.Bounds())
+	if rect.Empty() {
+		return
+	}
+
+	radius := max(4, max(rect.Dx(), rect.Dy())/8)
+	for i := 0; i < blurPasses; i++ {
+		for y := rect.Min.Y; y < rect.Max.Y; y++ {
+			boxBlur(img.Pix[img.PixOffset(rect.Min.X, y):], rect.Dx(), 4, radius)
+		}
+		for x := rect.Min.X; x < rect.Max.X; x++ {
+			boxBlur(img.Pix[img.PixOffset(x, rect.Min.Y):], rect.Dy(), img.Stride, radius)
+		}
+	}
+}
+
+// boxBlur replaces n pixels located with step in pix by average of neighbours in radius.
+// Neighbours outside of line are replaced by the nearest pixel of line
+func boxBlur(pix []uint8, n int, step int, radius int) {
+	line := make([]uint8, n*4)
+	for i := 0; i < n; i++ {
+		copy(line[i*4:i*4+4], pix[i*step:i*step+4])
+	}
+
+	at := func(i int) []uint8 {
+		i = min(max(i, 0), n-1)
+		return line[i*4 : i*4+4]
+	}
+
+	var sum [4]int
+	for i := -radius; i <= radius; i++ {
+		for c, v := range at(i) {
+			sum[c] += int(v)
+		}
+	}
+
+	size := 2*radius + 1
+	for i := 0; i < n; i++ {
+		for c := 0; c < 4; c++ {
+			pix[i*step+c] = uint8(sum[c] / size)
+		}
+
+		out, in := at(i-radius), at(i+radius+1)
+		for c := 0; c < 4; c++ {
+			sum[c] += int(in[c]) - int(out[c])
+		}
+	}
+}
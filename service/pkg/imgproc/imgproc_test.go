@@ -0,0 +1,117 @@
+package imgproc
+
+import (
+	"github.com/stretchr/testify/assert"
+	"image"
+	"image/color"
+	"testing"
+)
+
+// checkerboard returns image with black and white pixels in turn
+func checkerboard(w, h int) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			if (x+y)%2 == 0 {
+				img.Set(x, y, color.White)
+			} else {
+				img.Set(x, y, color.Black)
+			}
+		}
+	}
+	return img
+}
+
+func TestToRGBA(t *testing.T) {
+	src := checkerboard(4, 4).SubImage(image.Rect(1, 1, 3, 4))
+
+	dst := ToRGBA(src)
+
+	assert.Equal(t, image.Rect(0, 0, 2, 3), dst.Bounds())
+	assert.Equal(t, src.At(1, 1), dst.At(0, 0))
+	assert.Equal(t, src.At(2, 3), dst.At(1, 2))
+}
+
+func TestFit(t *testing.T) {
+	testCases := []struct {
+		name           string
+		width, height  int
+		maxSide        int
+		expectedBounds image.Rectangle
+	}{
+		{
+			name:           "Image fits",
+			width:          10,
+			height:         5,
+			maxSide:        10,
+			expectedBounds: image.Rect(0, 0, 10, 5),
+		},
+		{
+			name:           "Landscape image",
+			width:          40,
+			height:         20,
+			maxSide:        10,
+			expectedBounds: image.Rect(0, 0, 10, 5),
+		},
+		{
+			name:           "Portrait image",
+			width:          20,
+			height:         40,
+			maxSide:        10,
+			expectedBounds: image.Rect(0, 0, 5, 10),
+		},
+		{
+			name:           "Thin image",
+			width:          100,
+			height:         1,
+			maxSide:        10,
+			expectedBounds: image.Rect(0, 0, 10, 1),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			dst := Fit(checkerboard(tc.width, tc.height), tc.maxSide)
+
+			assert.Equal(t, tc.expectedBounds, dst.Bounds())
+		})
+	}
+}
+
+func TestFitAveragesPixels(t *testing.T) {
+	dst := Fit(checkerboard(8, 8), 4)
+
+	for y := 0; y < 4; y++ {
+		for x := 0; x < 4; x++ {
+			assert.Equal(t, color.RGBA{R: 127, G: 127, B: 127, A: 255}, dst.RGBAAt(x, y))
+		}
+	}
+}
+
+func TestBlurRect(t *testing.T) {
+	img := checkerboard(32, 32)
+	rect := image.Rect(8, 8, 24, 24)
+
+	BlurRect(img, rect)
+
+	for y := 0; y < 32; y++ {
+		for x := 0; x < 32; x++ {
+			c := img.RGBAAt(x, y)
+			if image.Pt(x, y).In(rect) {
+				// black and white pixels are mixed, so no pixel keeps its color
+				assert.InDelta(t, 127, int(c.R), 20, "pixel %d,%d", x, y)
+			} else {
+				assert.True(t, c.R == 0 || c.R == 255, "pixel %d,%d is changed", x, y)
+			}
+		}
+	}
+}
+
+func TestBlurRectOutsideImage(t *testing.T) {
+	img := checkerboard(8, 8)
+	expected := checkerboard(8, 8)
+
+	BlurRect(img, image.Rect(10, 10, 20, 20))
+
+	assert.Equal(t, expected, img)
+}
@@ -0,0 +1,66 @@
+// Package signedurl signs paths of urls with expiry time, so url can be used without other
+// authentication until it expires
+package signedurl
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+const (
+	ExpiresKey   = "expires"
+	SignatureKey = "signature"
+)
+
+var (
+	ErrInvalidSignature = errors.New("invalid url signature")
+	ErrExpired          = errors.New("url is expired")
+)
+
+// Signer signs path and expiry time by HMAC-SHA256 with key
+type Signer struct {
+	key []byte
+}
+
+func New(key string) *Signer {
+	return &Signer{key: []byte(key)}
+}
+
+// Sign returns query with expiry time and signature of path
+func (s *Signer) Sign(path string, expiresAt time.Time) url.Values {
+	expires := strconv.FormatInt(expiresAt.Unix(), 10)
+	return url.Values{
+		ExpiresKey:   {expires},
+		SignatureKey: {hex.EncodeToString(s.mac(path, expires))},
+	}
+}
+
+// Verify checks signature of path from query, then checks, that url is not expired at now
+func (s *Signer) Verify(path string, query url.Values, now time.Time) error {
+	expires := query.Get(ExpiresKey)
+	signature, err := hex.DecodeString(query.Get(SignatureKey))
+	if err != nil || expires == "" || !hmac.Equal(signature, s.mac(path, expires)) {
+		return ErrInvalidSignature
+	}
+
+	expiresAt, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil {
+		return ErrInvalidSignature
+	}
+	if now.Unix() > expiresAt {
+		return ErrExpired
+	}
+
+	return nil
+}
+
+func (s *Signer) mac(path string, expires string) []byte {
+	mac := hmac.New(sha256.New, s.key)
+	mac.Write([]byte(path + "\n" + expires))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,42 @@
+package signedurl
+
+import (
+	"github.com/stretchr/testify/assert"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestVerify(t *testing.T) {
+	signer := New("key")
+	path := "/internal/case/id/img"
+	now := time.Now()
+	query := signer.Sign(path, now.Add(time.Minute))
+
+	testCases := []struct {
+		name        string
+		signer      *Signer
+		path        string
+		query       url.Values
+		now         time.Time
+		expectedErr error
+	}{
+		{name: "Valid url", signer: signer, path: path, query: query, now: now},
+		{name: "Expired url", signer: signer, path: path, query: query, now: now.Add(2 * time.Minute),
+			expectedErr: ErrExpired},
+		{name: "Other path", signer: signer, path: "/internal/case/other/img", query: query, now: now,
+			expectedErr: ErrInvalidSignature},
+		{name: "Other key", signer: New("other"), path: path, query: query, now: now,
+			expectedErr: ErrInvalidSignature},
+		{name: "Changed expiry", signer: signer, path: path, now: now, expectedErr: ErrInvalidSignature,
+			query: url.Values{ExpiresKey: {"9999999999"}, SignatureKey: query[SignatureKey]}},
+		{name: "No signature", signer: signer, path: path, query: url.Values{}, now: now,
+			expectedErr: ErrInvalidSignature},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expectedErr, tc.signer.Verify(tc.path, tc.query, tc.now))
+		})
+	}
+}
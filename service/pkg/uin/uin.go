@@ -0,0 +1,59 @@
+// Package uin builds unique identifiers of accrual (UIN), which are payment references of fines
+package uin
+
+import (
+	"fmt"
+	"hash/fnv"
+	"strings"
+)
+
+const (
+	// Len is number of digits in UIN
+	Len = 20
+	// MaxPrefixLen leaves at least 9 digits of seed hash in UIN
+	MaxPrefixLen = 10
+)
+
+// ValidatePrefix checks, that prefix contains only digits and leaves enough digits for seed
+func ValidatePrefix(prefix string) error {
+	if len(prefix) > MaxPrefixLen {
+		return fmt.Errorf("uin prefix is longer than %d digits: %s", MaxPrefixLen, prefix)
+	}
+	if strings.Trim(prefix, "0123456789") != "" {
+		return fmt.Errorf("uin prefix must contain only digits: %s", prefix)
+	}
+	return nil
+}
+
+// New returns UIN of prefix, digits of seed hash and check digit. UIN of the same seed is the same
+func New(prefix string, seed string) string {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(seed))
+	digits := fmt.Sprintf("%020d", h.Sum64())
+
+	body := prefix + digits[len(digits)-(Len-1-len(prefix)):]
+	return body + string(rune('0'+CheckDigit(body)))
+}
+
+// IsValid checks length, digits and check digit of UIN
+func IsValid(uin string) bool {
+	if len(uin) != Len || strings.Trim(uin, "0123456789") != "" {
+		return false
+	}
+	return int(uin[Len-1]-'0') == CheckDigit(uin[:Len-1])
+}
+
+// CheckDigit of UIN: digits are weighted by 1..10 in cycle, remainder of division by 11 is check digit.
+// When remainder is 10, weights are shifted by 2, and remainder 10 gives check digit 0
+func CheckDigit(digits string) int {
+	for _, shift := range []int{0, 2} {
+		sum := 0
+		for i, d := range digits {
+			sum += int(d-'0') * ((i+shift)%10 + 1)
+		}
+		if sum%11 < 10 {
+			return sum % 11
+		}
+	}
+	return 0
+}
@@ -0,0 +1,63 @@
+package uin
+
+import (
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+func TestCheckDigit(t *testing.T) {
+	testCases := []struct {
+		name     string
+		digits   string
+		expected int
+	}{
+		{name: "Remainder with base weights", digits: "1881017719031234567", expected: 6},
+		{name: "Remainder 10, weights are shifted", digits: "1881010000000000004", expected: 5},
+		{name: "Remainder 10 with shifted weights", digits: "1881010000000000097", expected: 0},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, CheckDigit(tc.digits))
+		})
+	}
+}
+
+func TestNew(t *testing.T) {
+	for _, prefix := range []string{"", "18810", "1881017719"} {
+		actual := New(prefix, "case 1")
+		assert.Len(t, actual, Len)
+		assert.True(t, strings.HasPrefix(actual, prefix))
+		assert.True(t, IsValid(actual))
+		assert.Equal(t, actual, New(prefix, "case 1"))
+	}
+
+	assert.NotEqual(t, New("18810", "case 1"), New("18810", "case 2"))
+}
+
+func TestIsValid(t *testing.T) {
+	testCases := []struct {
+		name     string
+		uin      string
+		expected bool
+	}{
+		{name: "Valid", uin: "18810177190312345676", expected: true},
+		{name: "Invalid check digit", uin: "18810177190312345670", expected: false},
+		{name: "Short", uin: "1881017719031234567", expected: false},
+		{name: "Not digits", uin: "1881017719031234567A", expected: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, IsValid(tc.uin))
+		})
+	}
+}
+
+func TestValidatePrefix(t *testing.T) {
+	assert.NoError(t, ValidatePrefix(""))
+	assert.NoError(t, ValidatePrefix("18810"))
+	assert.Error(t, ValidatePrefix("188A0"))
+	assert.Error(t, ValidatePrefix("18810177190"))
+}